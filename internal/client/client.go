@@ -16,8 +16,9 @@ var (
 	version     = "dev"
 )
 
-// CreateNRClient initializes the New Relic client.
-func CreateNRClient(cfg *config.Config, creds *credentials.Credentials) (*newrelic.NewRelic, *credentials.Profile, error) {
+// CreateNRClient initializes the New Relic client.  Any options given are
+// applied after those derived from the configuration and credentials.
+func CreateNRClient(cfg *config.Config, creds *credentials.Credentials, opts ...newrelic.ConfigOption) (*newrelic.NewRelic, *credentials.Profile, error) {
 	var (
 		err               error
 		apiKey            string
@@ -54,6 +55,8 @@ func CreateNRClient(cfg *config.Config, creds *credentials.Credentials) (*newrel
 		cfgOpts = append(cfgOpts, newrelic.ConfigNerdGraphBaseURL(nerdGraphURLOverride))
 	}
 
+	cfgOpts = append(cfgOpts, opts...)
+
 	nrClient, err := newrelic.New(cfgOpts...)
 
 	if err != nil {
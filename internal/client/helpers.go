@@ -6,6 +6,7 @@ import (
 	"github.com/newrelic/newrelic-cli/internal/config"
 	"github.com/newrelic/newrelic-cli/internal/credentials"
 	"github.com/newrelic/newrelic-client-go/newrelic"
+	"github.com/newrelic/newrelic-client-go/pkg/nerdgraph"
 )
 
 // WithClient returns a New Relic client.
@@ -28,17 +29,17 @@ func WithClientFrom(configDir string, f func(c *newrelic.NewRelic)) {
 }
 
 // WithClientAndProfile returns a New Relic client and the profile used to initialize it,
-// after environment oveerrides have been applied.
-func WithClientAndProfile(f func(c *newrelic.NewRelic, p *credentials.Profile)) {
-	WithClientAndProfileFrom(config.DefaultConfigDirectory, f)
+// after environment oveerrides have been applied.  Any options given are applied to the client.
+func WithClientAndProfile(f func(c *newrelic.NewRelic, p *credentials.Profile), opts ...newrelic.ConfigOption) {
+	WithClientAndProfileFrom(config.DefaultConfigDirectory, f, opts...)
 }
 
 // WithClientAndProfileFrom returns a New Relic client and default profile used to initialize it,
-// after environment oveerrides have been applied.
-func WithClientAndProfileFrom(configDir string, f func(c *newrelic.NewRelic, p *credentials.Profile)) {
+// after environment oveerrides have been applied.  Any options given are applied to the client.
+func WithClientAndProfileFrom(configDir string, f func(c *newrelic.NewRelic, p *credentials.Profile), opts ...newrelic.ConfigOption) {
 	config.WithConfigFrom(configDir, func(cfg *config.Config) {
 		credentials.WithCredentialsFrom(configDir, func(creds *credentials.Credentials) {
-			nrClient, defaultProfile, err := CreateNRClient(cfg, creds)
+			nrClient, defaultProfile, err := CreateNRClient(cfg, creds, opts...)
 			if err != nil {
 				log.Fatal(err)
 			}
@@ -47,3 +48,25 @@ func WithClientAndProfileFrom(configDir string, f func(c *newrelic.NewRelic, p *
 		})
 	})
 }
+
+// NewNerdGraphClient returns a NerdGraph client, authenticated with the
+// default profile, that sends its requests to the given base URL.  Any options
+// given are applied to the client.
+func NewNerdGraphClient(baseURL string, opts ...newrelic.ConfigOption) (*nerdgraph.NerdGraph, error) {
+	var (
+		nrClient *newrelic.NewRelic
+		err      error
+	)
+
+	config.WithConfigFrom(config.DefaultConfigDirectory, func(cfg *config.Config) {
+		credentials.WithCredentialsFrom(config.DefaultConfigDirectory, func(creds *credentials.Credentials) {
+			nrClient, _, err = CreateNRClient(cfg, creds, append(opts, newrelic.ConfigNerdGraphBaseURL(baseURL))...)
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &nrClient.NerdGraph, nil
+}
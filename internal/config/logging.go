@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -18,8 +19,21 @@ const (
 
 var (
 	fileHookConfigured = false
+
+	// fileLogFields are added to every entry written to the log file.
+	fileLogFields      = log.Fields{}
+	fileLogFieldsMutex sync.RWMutex
 )
 
+// SetFileLogField adds a field to every entry written to the log file, leaving
+// the terminal output unchanged.
+func SetFileLogField(key string, value interface{}) {
+	fileLogFieldsMutex.Lock()
+	defer fileLogFieldsMutex.Unlock()
+
+	fileLogFields[key] = value
+}
+
 func initLogger(logLevel string) {
 	l := log.StandardLogger()
 
@@ -85,7 +99,7 @@ func NewLogrusFileHook(file string, flag int, chmod os.FileMode) (*LogrusFileHoo
 }
 
 func (hook *LogrusFileHook) Fire(entry *log.Entry) error {
-	plainformat, err := hook.formatter.Format(entry)
+	plainformat, err := hook.formatter.Format(withFileLogFields(entry))
 	if err != nil {
 		return err
 	}
@@ -100,6 +114,29 @@ func (hook *LogrusFileHook) Fire(entry *log.Entry) error {
 	return nil
 }
 
+func withFileLogFields(entry *log.Entry) *log.Entry {
+	fileLogFieldsMutex.RLock()
+	defer fileLogFieldsMutex.RUnlock()
+
+	if len(fileLogFields) == 0 {
+		return entry
+	}
+
+	data := make(log.Fields, len(entry.Data)+len(fileLogFields))
+	for k, v := range fileLogFields {
+		data[k] = v
+	}
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+
+	e := *entry
+	e.Data = data
+	e.Buffer = nil
+
+	return &e
+}
+
 func (hook *LogrusFileHook) Levels() []log.Level {
 	return []log.Level{
 		log.PanicLevel,
@@ -0,0 +1,42 @@
+//go:build unit
+// +build unit
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogrusFileHook_AddsFileLogFields(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "newrelic")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	file := filepath.Join(tmp, DefaultLogFile)
+	hook, err := NewLogrusFileHook(file, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0640)
+	require.NoError(t, err)
+
+	SetFileLogField("correlation_id", "abc-123")
+	defer func() {
+		fileLogFieldsMutex.Lock()
+		delete(fileLogFields, "correlation_id")
+		fileLogFieldsMutex.Unlock()
+	}()
+
+	entry := log.NewEntry(log.New()).WithField("recipe_name", "test")
+	entry.Message = "recipe event"
+	entry.Level = log.DebugLevel
+	require.NoError(t, hook.Fire(entry))
+
+	data, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "correlation_id=abc-123")
+	require.Contains(t, string(data), "recipe_name=test")
+	require.NotContains(t, entry.Data, "correlation_id")
+}
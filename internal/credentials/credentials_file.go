@@ -0,0 +1,129 @@
+package credentials
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+
+	"github.com/newrelic/newrelic-client-go/pkg/region"
+)
+
+// credentialsFileProfile holds the values of the credentials file in use, if
+// any, which take precedence over the default profile and the environment.
+var credentialsFileProfile *Profile
+
+// credentialsFileContents is the format of a credentials file, in JSON or YAML.
+type credentialsFileContents struct {
+	APIKey    string `json:"apiKey" yaml:"apiKey"`
+	AccountID int    `json:"accountID" yaml:"accountID"`
+	Region    string `json:"region" yaml:"region"`
+}
+
+// LoadCredentialsFile reads the API key, account ID and region from the
+// credentials file at the given path, for automation that keeps secrets in
+// files rather than in the environment.  A warning is logged when the file is
+// readable by any user.
+func LoadCredentialsFile(path string) (*Profile, error) {
+	path = os.ExpandEnv(path)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read credentials file: %s", err)
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("credentials file %s is not a regular file", path)
+	}
+
+	if worldReadable(info.Mode()) {
+		log.Warnf("Credentials file %s is readable by any user, restrict its permissions with: chmod 600 %s", path, path)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read credentials file: %s", err)
+	}
+
+	return parseCredentialsFile(data)
+}
+
+// parseCredentialsFile parses the contents of a credentials file.  JSON is
+// parsed as YAML, of which it is a subset.
+func parseCredentialsFile(data []byte) (*Profile, error) {
+	var c credentialsFileContents
+	if err := yaml.UnmarshalStrict(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid credentials file: %s", err)
+	}
+
+	if c.APIKey == "" && c.AccountID == 0 && c.Region == "" {
+		return nil, fmt.Errorf("invalid credentials file: none of apiKey, accountID or region is set")
+	}
+
+	if c.Region != "" {
+		if _, err := region.Parse(c.Region); err != nil {
+			return nil, fmt.Errorf("invalid credentials file: %s", err)
+		}
+	}
+
+	return &Profile{
+		APIKey:    c.APIKey,
+		AccountID: c.AccountID,
+		Region:    strings.ToUpper(c.Region),
+	}, nil
+}
+
+// worldReadable returns whether a file with the given mode is readable by any
+// user.  File modes do not reflect access control on Windows.
+func worldReadable(mode os.FileMode) bool {
+	return runtime.GOOS != "windows" && mode.Perm()&0004 != 0
+}
+
+// SetCredentialsFile loads the credentials file at the given path, whose values
+// then take precedence over those of the default profile and the environment.
+func SetCredentialsFile(path string) error {
+	p, err := LoadCredentialsFile(path)
+	if err != nil {
+		return err
+	}
+
+	credentialsFileProfile = p
+	defaultProfile = nil
+
+	return nil
+}
+
+// applyCredentialsFile overrides the profile with the values set by the
+// credentials file in use, if any.
+func applyCredentialsFile(p *Profile) *Profile {
+	return mergeCredentialsFile(p, credentialsFileProfile)
+}
+
+func mergeCredentialsFile(p *Profile, file *Profile) *Profile {
+	if file == nil {
+		return p
+	}
+
+	out := Profile{}
+	if p != nil {
+		out = *p
+	}
+
+	if file.APIKey != "" {
+		out.APIKey = file.APIKey
+	}
+
+	if file.AccountID != 0 {
+		out.AccountID = file.AccountID
+	}
+
+	if file.Region != "" {
+		out.Region = file.Region
+	}
+
+	return &out
+}
@@ -0,0 +1,141 @@
+// +build unit
+
+package credentials
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCredentialsFile_JSON(t *testing.T) {
+	p, err := parseCredentialsFile([]byte(`{"apiKey": "NRAK-FILE", "accountID": 12345, "region": "eu"}`))
+
+	require.NoError(t, err)
+	require.Equal(t, "NRAK-FILE", p.APIKey)
+	require.Equal(t, 12345, p.AccountID)
+	require.Equal(t, "EU", p.Region)
+}
+
+func TestParseCredentialsFile_YAML(t *testing.T) {
+	p, err := parseCredentialsFile([]byte("apiKey: NRAK-FILE\naccountID: 12345\n"))
+
+	require.NoError(t, err)
+	require.Equal(t, "NRAK-FILE", p.APIKey)
+	require.Equal(t, 12345, p.AccountID)
+	require.Equal(t, "", p.Region)
+}
+
+func TestParseCredentialsFile_Invalid(t *testing.T) {
+	for _, data := range []string{
+		`{"apiKey": "NRAK-FILE", "apikey": "typo"}`,
+		`{"apiKey": "NRAK-FILE", "region": "moon"}`,
+		`{"accountID": "not a number"}`,
+		`{}`,
+		`not: [valid`,
+	} {
+		_, err := parseCredentialsFile([]byte(data))
+		require.Error(t, err, data)
+	}
+}
+
+func TestLoadCredentialsFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "credentials")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "nr.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"apiKey": "NRAK-FILE"}`), 0600))
+
+	p, err := LoadCredentialsFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "NRAK-FILE", p.APIKey)
+
+	// World-readable files are loaded, with a warning.
+	require.NoError(t, os.Chmod(path, 0644))
+	_, err = LoadCredentialsFile(path)
+	require.NoError(t, err)
+
+	_, err = LoadCredentialsFile(filepath.Join(dir, "missing.json"))
+	require.Error(t, err)
+
+	_, err = LoadCredentialsFile(dir)
+	require.Error(t, err)
+}
+
+func TestWorldReadable(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes do not reflect access control on Windows")
+	}
+
+	require.False(t, worldReadable(0600))
+	require.False(t, worldReadable(0640))
+	require.True(t, worldReadable(0644))
+	require.True(t, worldReadable(0604))
+}
+
+func TestCredentialsDefault_CredentialsFilePrecedence(t *testing.T) {
+	// Not parallel, the environment and credentials file are global.
+	defer func(p *Profile) { credentialsFileProfile = p }(credentialsFileProfile)
+	for _, v := range []string{"NEW_RELIC_API_KEY", "NEW_RELIC_REGION", "NEW_RELIC_ACCOUNT_ID", "NEW_RELIC_INSIGHTS_INSERT_KEY", "NEW_RELIC_LICENSE_KEY"} {
+		if val, ok := os.LookupEnv(v); ok {
+			defer os.Setenv(v, val)
+		}
+		os.Unsetenv(v)
+	}
+
+	c := &Credentials{
+		DefaultProfile: "default",
+		Profiles: map[string]Profile{
+			"default": {APIKey: "NRAK-PROFILE", AccountID: 1, Region: "US", LicenseKey: "license"},
+		},
+	}
+
+	os.Setenv("NEW_RELIC_API_KEY", "NRAK-ENV")
+	os.Setenv("NEW_RELIC_ACCOUNT_ID", "2")
+
+	// The environment overrides the profile.
+	credentialsFileProfile = nil
+	p := c.Default()
+	require.Equal(t, "NRAK-ENV", p.APIKey)
+	require.Equal(t, 2, p.AccountID)
+
+	// The credentials file overrides both, for the values it sets.
+	credentialsFileProfile = &Profile{APIKey: "NRAK-FILE", Region: "EU"}
+	p = c.Default()
+	require.Equal(t, "NRAK-FILE", p.APIKey)
+	require.Equal(t, 2, p.AccountID)
+	require.Equal(t, "EU", p.Region)
+	require.Equal(t, "license", p.LicenseKey)
+
+	// Without a default profile, the credentials file is used alone.
+	os.Unsetenv("NEW_RELIC_API_KEY")
+	os.Unsetenv("NEW_RELIC_ACCOUNT_ID")
+	p = (&Credentials{}).Default()
+	require.NotNil(t, p)
+	require.Equal(t, "NRAK-FILE", p.APIKey)
+}
+
+func TestSetCredentialsFile(t *testing.T) {
+	defer func(p *Profile) { credentialsFileProfile = p }(credentialsFileProfile)
+	defer func(p *Profile) { defaultProfile = p }(defaultProfile)
+
+	dir, err := ioutil.TempDir("", "credentials")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "nr.yml")
+	require.NoError(t, ioutil.WriteFile(path, []byte("apiKey: NRAK-FILE\nregion: eu\n"), 0600))
+
+	SetDefaultProfile(Profile{APIKey: "NRAK-CACHED"})
+	require.NoError(t, SetCredentialsFile(path))
+	require.Nil(t, defaultProfile)
+	require.Equal(t, "NRAK-FILE", credentialsFileProfile.APIKey)
+
+	require.Error(t, SetCredentialsFile(filepath.Join(dir, "missing.yml")))
+	require.Equal(t, "NRAK-FILE", credentialsFileProfile.APIKey)
+}
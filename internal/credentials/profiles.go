@@ -56,7 +56,8 @@ func LoadDefaultProfile(configDir string) (string, error) {
 	return defProfile, nil
 }
 
-// Default returns the default profile
+// Default returns the default profile, overridden by the environment and then
+// by the credentials file in use, if any.
 func (c *Credentials) Default() *Profile {
 	var p *Profile
 	if c.DefaultProfile != "" {
@@ -66,6 +67,7 @@ func (c *Credentials) Default() *Profile {
 	}
 
 	p = applyOverrides(p)
+	p = applyCredentialsFile(p)
 	return p
 }
 
@@ -0,0 +1,136 @@
+package install
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-client-go/newrelic"
+)
+
+// normalizeCABundle returns the absolute path of the CA bundle, when given,
+// so that recipes running in other directories find it, ensuring it holds
+// at least one PEM certificate.
+func normalizeCABundle(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid --ca-bundle %s: %s", path, err)
+	}
+
+	if _, err := loadCABundle(abs); err != nil {
+		return "", err
+	}
+
+	return abs, nil
+}
+
+// loadCABundle returns the system's trusted certificates with those of the
+// PEM bundle at the given path added.
+func loadCABundle(path string) (*x509.CertPool, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read CA bundle %s: %s", path, err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("invalid CA bundle %s: no PEM certificates found", path)
+	}
+
+	return pool, nil
+}
+
+// newHTTPTransport returns a transport like the default one, through the
+// proxy configured in the environment, that also trusts the certificates of
+// the CA bundle, when given.
+func newHTTPTransport(caBundle string) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	if caBundle == "" {
+		return t, nil
+	}
+
+	pool, err := loadCABundle(caBundle)
+	if err != nil {
+		return nil, err
+	}
+
+	t.TLSClientConfig = &tls.Config{
+		RootCAs:    pool,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	return t, nil
+}
+
+// newHTTPClient returns an HTTP client using the transport of newHTTPTransport.
+func newHTTPClient(caBundle string) (*http.Client, error) {
+	t, err := newHTTPTransport(caBundle)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{Transport: t}, nil
+}
+
+// newRecipeFileFetcher returns a recipe file fetcher that trusts the
+// certificates of the CA bundle, when given.
+func newRecipeFileFetcher(caBundle string) recipes.RecipeFileFetcher {
+	if caBundle == "" {
+		return recipes.NewRecipeFileFetcher()
+	}
+
+	c, err := newHTTPClient(caBundle)
+	if err != nil {
+		log.Warnf("could not use CA bundle for recipe downloads: %s", err)
+		return recipes.NewRecipeFileFetcher()
+	}
+
+	return recipes.NewRecipeFileFetcherWithClient(c)
+}
+
+// newVerifyingRecipeFileFetcher returns a fetcher verifying the recipe files
+// of the given fetcher, which downloads their signatures trusting the
+// certificates of the CA bundle, when given.
+func newVerifyingRecipeFileFetcher(ff recipes.RecipeFileFetcher, v *recipes.RecipeVerifier, caBundle string) recipes.RecipeFileFetcher {
+	if caBundle == "" {
+		return recipes.NewVerifyingRecipeFileFetcher(ff, v)
+	}
+
+	c, err := newHTTPClient(caBundle)
+	if err != nil {
+		log.Warnf("could not use CA bundle for recipe signature downloads: %s", err)
+		return recipes.NewVerifyingRecipeFileFetcher(ff, v)
+	}
+
+	return recipes.NewVerifyingRecipeFileFetcherWithClient(ff, v, c)
+}
+
+// caBundleClientOptions returns the options of the New Relic clients that
+// make them trust the certificates of the CA bundle, when given.
+func caBundleClientOptions(caBundle string) ([]newrelic.ConfigOption, error) {
+	if caBundle == "" {
+		return nil, nil
+	}
+
+	t, err := newHTTPTransport(caBundle)
+	if err != nil {
+		return nil, err
+	}
+
+	return []newrelic.ConfigOption{newrelic.ConfigHTTPTransport(t)}, nil
+}
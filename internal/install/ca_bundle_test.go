@@ -0,0 +1,127 @@
+// +build unit
+
+package install
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+)
+
+// writeCABundle writes the certificate of the TLS server as a PEM bundle in
+// the directory, returning its path.
+func writeCABundle(t *testing.T, dir string, server *httptest.Server) string {
+	path := filepath.Join(dir, "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, ioutil.WriteFile(path, data, 0600))
+
+	return path
+}
+
+func TestNewHTTPClient_CABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "name: test-recipe\n")
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "ca-bundle")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	bundle := writeCABundle(t, dir, server)
+
+	c, err := newHTTPClient(bundle)
+	require.NoError(t, err)
+	resp, err := c.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	c, err = newHTTPClient("")
+	require.NoError(t, err)
+	_, err = c.Get(server.URL)
+	require.Error(t, err)
+
+	opts, err := caBundleClientOptions(bundle)
+	require.NoError(t, err)
+	require.Len(t, opts, 1)
+
+	opts, err = caBundleClientOptions("")
+	require.NoError(t, err)
+	require.Empty(t, opts)
+}
+
+func TestNewRecipeFileFetcher_CABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "name: test-recipe\n")
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "ca-bundle")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	u, err := url.Parse(server.URL + "/test-recipe.yml")
+	require.NoError(t, err)
+
+	r, err := newRecipeFileFetcher(writeCABundle(t, dir, server)).FetchRecipeFile(u)
+	require.NoError(t, err)
+	require.Equal(t, "test-recipe", r.Name)
+
+	_, err = recipes.NewRecipeFileFetcher().FetchRecipeFile(u)
+	require.Error(t, err)
+}
+
+func TestCheckEndpoints_CABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "ca-bundle")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	transport, err := newHTTPTransport(writeCABundle(t, dir, server))
+	require.NoError(t, err)
+
+	client := newPreflightClient()
+	client.Transport = transport
+	err = checkEndpoints(context.Background(), client, []preflightEndpoint{{Name: "test", URL: server.URL}})
+	require.NoError(t, err)
+}
+
+func TestNormalizeCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "ca-bundle")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path, err := normalizeCABundle("")
+	require.NoError(t, err)
+	require.Empty(t, path)
+
+	bundle := writeCABundle(t, dir, server)
+	path, err = normalizeCABundle(bundle)
+	require.NoError(t, err)
+	require.True(t, filepath.IsAbs(path))
+	require.Equal(t, bundle, path)
+
+	notPEM := filepath.Join(dir, "not-pem.txt")
+	require.NoError(t, ioutil.WriteFile(notPEM, []byte("not a certificate"), 0600))
+	_, err = normalizeCABundle(notPEM)
+	require.Error(t, err)
+
+	_, err = normalizeCABundle(filepath.Join(dir, "missing.pem"))
+	require.Error(t, err)
+}
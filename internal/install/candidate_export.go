@@ -0,0 +1,206 @@
+package install
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+// exportCandidatesStdout is the path that exports the candidates to standard
+// output rather than to a file.
+const exportCandidatesStdout = "-"
+
+// candidateExport is the plan of a guided install: the recipes it would
+// install on the discovered host, with why each is included, and those it
+// would not.
+type candidateExport struct {
+	Hostname        string              `json:"hostname"`
+	OS              string              `json:"os"`
+	Platform        string              `json:"platform"`
+	PlatformVersion string              `json:"platformVersion"`
+	Candidates      []exportedCandidate `json:"candidates"`
+	Excluded        []exportedExclusion `json:"excluded"`
+}
+
+// exportedCandidate is a recipe the guided install would install, in the
+// order it would be installed.
+type exportedCandidate struct {
+	Name        string   `json:"name"`
+	DisplayName string   `json:"displayName,omitempty"`
+	Required    bool     `json:"required,omitempty"`
+	Relevance   float64  `json:"relevance,omitempty"`
+	Reasons     []string `json:"reasons"`
+}
+
+// exportedExclusion is a recipe the guided install would not install.
+type exportedExclusion struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName,omitempty"`
+	Reason      string `json:"reason"`
+}
+
+// exportCandidates runs the discovery and recommendation phases of a guided
+// install, then exports the recipes it would install as JSON, without
+// prompting or installing anything.  Every recommendation is accepted, as with
+// --assumeYes, and the flags filtering recommendations apply.
+func (i *RecipeInstaller) exportCandidates(ctx context.Context, m *types.DiscoveryManifest) error {
+	i.AssumeYes = true
+
+	infraAgentRecipe, err := i.fetchInfraAgentRecipe(ctx, m)
+	if err != nil {
+		return err
+	}
+
+	loggingRecipe, err := i.fetchRecipeAndReportAvailable(ctx, m, i.loggingRecipeName())
+	if err != nil {
+		return err
+	}
+
+	recommended := []types.OpenInstallationRecipe{}
+	if i.SkipLoggingInstall {
+		i.status.RecipeSkipped(execution.RecipeStatusEvent{
+			Recipe: *loggingRecipe,
+			Msg:    "--skipLoggingInstall is set",
+		})
+	} else {
+		recommended = append(recommended, *loggingRecipe)
+	}
+
+	if !i.SkipDiscovery {
+		r, err := i.fetchRecommendations(m)
+		if err != nil {
+			return err
+		}
+		recommended = append(recommended, r...)
+	}
+
+	selected, err := i.filterIntegrations(m, recommended)
+	if err != nil {
+		return err
+	}
+
+	provided := []string{infraAgentRecipe.Name}
+	unavailable := []string{}
+	if i.ShouldInstallLogging() {
+		provided = append(provided, loggingRecipe.Name)
+	} else {
+		unavailable = append(unavailable, loggingRecipe.Name)
+	}
+
+	selected, err = i.withPrerequisites(ctx, m, selected, provided, unavailable)
+	if err != nil {
+		return err
+	}
+
+	selected, err = i.resolveConflicts(selected)
+	if err != nil {
+		return err
+	}
+
+	selected = i.removeRecipes(selected, *loggingRecipe)
+	order := i.guidedInstallOrder(infraAgentRecipe, loggingRecipe, selected)
+
+	return i.writeCandidateExport(newCandidateExport(m, order, i.status, infraAgentRecipe.Name, loggingRecipe.Name))
+}
+
+func newCandidateExport(m *types.DiscoveryManifest, recipes []types.OpenInstallationRecipe, status *execution.InstallStatus, infraAgentRecipeName string, loggingRecipeName string) candidateExport {
+	e := candidateExport{
+		Hostname:        m.Hostname,
+		OS:              m.OS,
+		Platform:        m.Platform,
+		PlatformVersion: m.PlatformVersion,
+		Candidates:      []exportedCandidate{},
+		Excluded:        []exportedExclusion{},
+	}
+
+	included := map[string]bool{}
+	for _, r := range recipes {
+		included[r.Name] = true
+
+		e.Candidates = append(e.Candidates, exportedCandidate{
+			Name:        r.Name,
+			DisplayName: r.DisplayName,
+			Required:    r.IsRequired(),
+			Relevance:   recipeRelevance(m, r),
+			Reasons:     candidateReasons(m, r, status, infraAgentRecipeName, loggingRecipeName),
+		})
+	}
+
+	for _, s := range status.Statuses {
+		if s.Status != execution.RecipeStatusTypes.SKIPPED || included[s.Name] {
+			continue
+		}
+
+		e.Excluded = append(e.Excluded, exportedExclusion{
+			Name:        s.Name,
+			DisplayName: s.DisplayName,
+			Reason:      s.SkipReason,
+		})
+	}
+
+	return e
+}
+
+// candidateReasons returns why the recipe is included in the install.
+func candidateReasons(m *types.DiscoveryManifest, r types.OpenInstallationRecipe, status *execution.InstallStatus, infraAgentRecipeName string, loggingRecipeName string) []string {
+	reasons := []string{}
+
+	switch r.Name {
+	case infraAgentRecipeName:
+		reasons = append(reasons, "the infrastructure agent is installed by every guided installation")
+	case loggingRecipeName:
+		reasons = append(reasons, "forwards the logs discovered on the host")
+	}
+
+	for _, s := range status.Statuses {
+		if s.Name == r.Name {
+			for _, p := range s.PrerequisiteOf {
+				reasons = append(reasons, fmt.Sprintf("prerequisite of %s", p))
+			}
+		}
+	}
+
+	if len(reasons) == 0 {
+		if processMatchRelevance(m, r) > 0 {
+			reasons = append(reasons, "matches processes running on the host")
+		} else {
+			reasons = append(reasons, "recommended for the host by the recipe source")
+		}
+	}
+
+	if r.IsRequired() {
+		reasons = append(reasons, "required when recommended")
+	}
+
+	return reasons
+}
+
+// writeCandidateExport writes the export to the file given by
+// --export-candidates, or to standard output.
+func (i *RecipeInstaller) writeCandidateExport(e candidateExport) error {
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if i.ExportCandidates == exportCandidatesStdout {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	if err := utils.WriteFileAtomic(i.ExportCandidates, data, 0644); err != nil {
+		return fmt.Errorf("could not export the install candidates: %s", err)
+	}
+
+	log.Infof("Exported %d install candidates to %s", len(e.Candidates), i.ExportCandidates)
+
+	return nil
+}
@@ -0,0 +1,84 @@
+// +build unit
+
+package install
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/discovery"
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/ux"
+)
+
+func TestInstall_ExportCandidates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "candidates")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "candidates.json")
+	ic := InstallerContext{
+		ExportCandidates: path,
+		SkipRecipes:      []string{"nginx"},
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{
+		{
+			Name:         "mysql",
+			DisplayName:  "MySQL",
+			ProcessMatch: []string{"mysqld"},
+		},
+		{
+			Name:        "nginx",
+			DisplayName: "NGINX",
+		},
+		{
+			Name:        "redis",
+			Requirement: types.OpenInstallationRequirementTypes.REQUIRED,
+		},
+	}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{Name: types.InfraAgentRecipeName},
+		{Name: types.LoggingRecipeName},
+	}
+
+	d := discovery.NewMockDiscoverer()
+	d.DiscoveryManifest.Hostname = "db-1"
+	d.DiscoveryManifest.Processes = []types.MatchedProcess{{Command: "/usr/sbin/mysqld"}}
+
+	e := execution.NewMockRecipeExecutor()
+	p := ux.NewMockPrompter()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	require.NoError(t, i.Install())
+	require.Equal(t, 0, e.ExecuteCallCount)
+	require.Equal(t, 0, p.PromptMultiSelectCallCount)
+	require.Equal(t, 0, p.PromptYesNoCallCount)
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	var export candidateExport
+	require.NoError(t, json.Unmarshal(data, &export))
+	require.Equal(t, "db-1", export.Hostname)
+
+	names := []string{}
+	for _, c := range export.Candidates {
+		names = append(names, c.Name)
+	}
+	require.Equal(t, []string{types.InfraAgentRecipeName, types.LoggingRecipeName, "redis", "mysql"}, names)
+	require.Equal(t, []string{"matches processes running on the host"}, export.Candidates[3].Reasons)
+	require.Equal(t, []string{"recommended for the host by the recipe source", "required when recommended"}, export.Candidates[2].Reasons)
+	require.True(t, export.Candidates[2].Required)
+
+	require.Equal(t, []exportedExclusion{{Name: "nginx", DisplayName: "NGINX", Reason: "--skip is set for nginx"}}, export.Excluded)
+}
@@ -2,6 +2,12 @@ package install
 
 import (
 	"errors"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -9,23 +15,89 @@ import (
 	"github.com/newrelic/newrelic-cli/internal/client"
 	"github.com/newrelic/newrelic-cli/internal/config"
 	"github.com/newrelic/newrelic-cli/internal/credentials"
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
 	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/ux"
+	"github.com/newrelic/newrelic-cli/internal/utils"
 	"github.com/newrelic/newrelic-client-go/newrelic"
 )
 
 var (
-	assumeYes          bool
-	localRecipes       string
-	recipeNames        []string
-	recipePaths        []string
-	skipDiscovery      bool
-	skipIntegrations   bool
-	skipLoggingInstall bool
-	skipApm            bool
-	skipInfra          bool
-	testMode           bool
-	debug              bool
-	trace              bool
+	assumeYes           bool
+	localRecipes        string
+	recipeNames         []string
+	recipePaths         []string
+	skipDiscovery       bool
+	skipIntegrations    bool
+	skipLoggingInstall  bool
+	skipApm             bool
+	skipInfra           bool
+	testMode            bool
+	verboseRecipeSteps  bool
+	resetSelections     bool
+	reinstall           bool
+	force               bool
+	recipeVarsFiles     map[string]string
+	plan                bool
+	sendUsageData       bool
+	infraRecipeName     string
+	loggingRecipeName   string
+	logMaxMatches       int
+	logMaxAgeDays       int
+	logExclude          []string
+	maxRecipeFailures   int
+	outputDir           string
+	includeTags         []string
+	excludeTags         []string
+	promptTimeout       time.Duration
+	promptTimeoutFails  bool
+	sshHosts            []string
+	sshKeyFile          string
+	recipeSources       []string
+	quiet               bool
+	audit               bool
+	ignorePortConflicts bool
+	junitOutput         string
+	installTimeout      time.Duration
+	shell               string
+	iterativeRecs       bool
+	continueOnInfraFail bool
+	recipePublicKeys    []string
+	requireSigned       bool
+	answersFile         string
+	isolatedEnv         bool
+	recipeSourceURL     string
+	retryExitCodes      []int
+	recipeRetries       int
+	manifestFile        string
+	saveManifestFile    string
+	heartbeatFile       string
+	heartbeatInterval   time.Duration
+	logAllow            []string
+	logDeny             []string
+	skipPreflight       bool
+	dryRun              bool
+	campaignID          string
+	assumeContainer     bool
+	assumeHost          bool
+	skipRecipes         []string
+	logGlobConcurrency  int
+	credentialsFile     string
+	successLinksOutput  string
+	exportCandidates    string
+	progress            string
+	recipeMemoryLimitMB int
+	recipeCPULimit      float64
+	jsonOutput          string
+	messagesFile        string
+	lang                string
+	recipePolicyFile    string
+	diagnosticsOnFail   bool
+	caBundle            string
+	observeValidation   time.Duration
+	metricsAddr         string
+	debug               bool
+	trace               bool
 )
 
 // Command represents the install command.
@@ -34,19 +106,98 @@ var Command = &cobra.Command{
 	Short: "Install New Relic.",
 	Run: func(cmd *cobra.Command, args []string) {
 		ic := InstallerContext{
-			AssumeYes:          assumeYes,
-			LocalRecipes:       localRecipes,
-			RecipeNames:        recipeNames,
-			RecipePaths:        recipePaths,
-			SkipDiscovery:      skipDiscovery,
-			SkipIntegrations:   skipIntegrations,
-			SkipLoggingInstall: skipLoggingInstall,
-			SkipApm:            skipApm,
-			SkipInfra:          skipInfra,
+			AssumeYes:                assumeYes,
+			LocalRecipes:             localRecipes,
+			RecipeNames:              recipeNames,
+			RecipePaths:              recipePaths,
+			SkipDiscovery:            skipDiscovery,
+			SkipIntegrations:         skipIntegrations,
+			SkipLoggingInstall:       skipLoggingInstall,
+			SkipApm:                  skipApm,
+			SkipInfra:                skipInfra,
+			VerboseRecipeSteps:       verboseRecipeSteps,
+			ResetSelections:          resetSelections,
+			Reinstall:                reinstall || force,
+			RecipeVarsFiles:          recipeVarsFiles,
+			Plan:                     plan,
+			SendUsageData:            sendUsageData,
+			InfraAgentRecipeName:     infraRecipeName,
+			LoggingRecipeName:        loggingRecipeName,
+			LogMaxMatches:            logMaxMatches,
+			LogMaxAgeDays:            logMaxAgeDays,
+			LogExclude:               logExclude,
+			MaxRecipeFailures:        maxRecipeFailures,
+			OutputDir:                outputDir,
+			IncludeTags:              includeTags,
+			ExcludeTags:              excludeTags,
+			PromptTimeout:            promptTimeout,
+			PromptTimeoutFails:       promptTimeoutFails,
+			SSHHosts:                 sshHosts,
+			SSHKeyFile:               sshKeyFile,
+			RecipeSources:            recipeSources,
+			Quiet:                    quiet,
+			Audit:                    audit,
+			IgnorePortConflicts:      ignorePortConflicts,
+			JUnitOutput:              junitOutput,
+			InstallTimeout:           installTimeout,
+			Shell:                    shell,
+			IterativeRecommendations: iterativeRecs,
+			ContinueOnInfraFailure:   continueOnInfraFail,
+			RecipePublicKeys:         recipePublicKeys,
+			RequireSignedRecipes:     requireSigned,
+			AnswersFile:              answersFile,
+			IsolatedEnv:              isolatedEnv,
+			RecipeSourceURL:          recipeSourceURL,
+			RetryExitCodes:           retryExitCodes,
+			RecipeRetries:            recipeRetries,
+			ManifestFile:             manifestFile,
+			SaveManifestFile:         saveManifestFile,
+			HeartbeatFile:            heartbeatFile,
+			HeartbeatInterval:        heartbeatInterval,
+			LogAllow:                 logAllow,
+			LogDeny:                  logDeny,
+			SkipPreflight:            skipPreflight,
+			DryRun:                   dryRun,
+			CampaignID:               campaignID,
+			AssumeContainer:          assumeContainer,
+			AssumeHost:               assumeHost,
+			SkipRecipes:              skipRecipes,
+			LogGlobConcurrency:       logGlobConcurrency,
+			CredentialsFile:          credentialsFile,
+			SuccessLinksOutput:       successLinksOutput,
+			ExportCandidates:         exportCandidates,
+			Progress:                 progress,
+			RecipeMemoryLimitMB:      recipeMemoryLimitMB,
+			RecipeCPULimit:           recipeCPULimit,
+			JSONOutput:               jsonOutput,
+			MessagesFile:             messagesFile,
+			Lang:                     lang,
+			RecipePolicyFile:         recipePolicyFile,
+			DiagnosticsOnFailure:     diagnosticsOnFail,
+			CABundle:                 caBundle,
+			ObserveValidation:        observeValidation,
+			MetricsAddr:              metricsAddr,
 		}
 
 		config.InitFileLogger()
 
+		if ic.CredentialsFile != "" {
+			if err := credentials.SetCredentialsFile(ic.CredentialsFile); err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		var err error
+		ic.CABundle, err = normalizeCABundle(ic.CABundle)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		clientOpts, err := caBundleClientOptions(ic.CABundle)
+		if err != nil {
+			log.Fatal(err)
+		}
+
 		client.WithClientAndProfile(func(nrClient *newrelic.NewRelic, profile *credentials.Profile) {
 			if trace {
 				log.SetLevel(log.TraceLevel)
@@ -54,6 +205,8 @@ var Command = &cobra.Command{
 			} else if debug {
 				log.SetLevel(log.DebugLevel)
 				nrClient.SetLogLevel("debug")
+			} else if quiet {
+				log.SetLevel(log.WarnLevel)
 			}
 
 			err := assertProfileIsValid(profile)
@@ -61,17 +214,146 @@ var Command = &cobra.Command{
 				log.Fatal(err)
 			}
 
-			i := NewRecipeInstaller(ic, nrClient)
+			err = assertQuietIsValid(ic)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			err = execution.ValidateShellName(ic.Shell)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			err = ux.ValidateProgressStyle(ic.Progress)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			err = assertResourceLimitsAreValid(ic)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			ic.CampaignID, err = normalizeCampaignID(ic.CampaignID)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			ic.Lang, err = resolveLang(ic.Lang)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			err = assertSignatureConfigIsValid(ic)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			err = assertAnswersFileIsValid(ic)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			err = assertMessagesFileIsValid(ic)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			if ic.RecipePolicyFile == "" {
+				ic.RecipePolicyFile = defaultRecipePolicyPath()
+			}
+
+			err = assertRecipePolicyIsValid(ic)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			err = assertRecipeSourceURLIsValid(ic)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			err = assertRetryConfigIsValid(ic)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			err = assertManifestFileIsValid(ic)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			err = assertLogApprovalIsValid(ic)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			err = assertDryRunIsValid(ic)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			err = assertMetricsAddrIsValid(ic)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			err = assertContainerAssumptionIsValid(ic)
+			if err != nil {
+				log.Fatal(err)
+			}
 
-			// Run the install.
-			if err := i.Install(); err != nil {
-				if err == types.ErrInterrupt {
+			err = assertExportCandidatesIsValid(ic)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			err = checkRecipeSource(utils.SignalCtx, ic, nrClient)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			err = checkNetworkConnectivity(utils.SignalCtx, ic, profile)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			// Run the install, remotely when hosts are given.
+			if ic.RemoteInstall() {
+				err = InstallOnRemoteHosts(ic, nrClient)
+			} else {
+				err = NewRecipeInstaller(ic, nrClient).Install()
+			}
+
+			if err != nil {
+				if isCanceled(err) {
 					return
 				}
 
+				if err == types.ErrPromptTimeout {
+					log.Fatal("The installation was canceled because a prompt received no response within the prompt timeout.")
+				}
+
+				var herr ErrRemoteHostsFailed
+				if errors.As(err, &herr) {
+					log.Fatal(herr)
+				}
+
+				var perr ErrUnsupportedPlatform
+				if errors.As(err, &perr) {
+					log.Error(perr)
+					os.Exit(unsupportedPlatformExitCode)
+				}
+
+				var aerr ErrAuthentication
+				if errors.As(err, &aerr) {
+					log.Debug(err)
+					log.Fatal(aerr.Message())
+				}
+
 				log.Fatalf("We encountered an error during the installation: %s. If this problem persists please visit the documentation and support page for additional help here: https://one.nr/06vjAeZLKjP", err)
 			}
-		})
+		}, clientOpts...)
 	},
 }
 
@@ -82,6 +364,125 @@ func assertProfileIsValid(profile *credentials.Profile) error {
 	return nil
 }
 
+// assertManifestFileIsValid ensures a saved manifest is only given for local
+// installs, and can be read.
+func assertManifestFileIsValid(ic InstallerContext) error {
+	if ic.ManifestFile == "" {
+		return nil
+	}
+
+	if ic.RemoteInstall() {
+		return errors.New("--manifest cannot be used with --ssh, as remote hosts are always discovered")
+	}
+
+	if _, err := os.Stat(ic.ManifestFile); err != nil {
+		return fmt.Errorf("invalid --manifest: %s", err)
+	}
+
+	return nil
+}
+
+// assertQuietIsValid ensures a quiet install will not need to prompt, since
+// prompts cannot be shown in quiet mode.
+func assertQuietIsValid(ic InstallerContext) error {
+	if ic.Quiet && !ic.AssumeYes && ic.AnswersFile == "" {
+		return errors.New("--quiet requires --assumeYes or --answers-file, as prompts are not shown in quiet mode")
+	}
+	return nil
+}
+
+// assertMetricsAddrIsValid ensures the metrics address, when given, is a
+// host and port to listen on.
+func assertMetricsAddrIsValid(ic InstallerContext) error {
+	if ic.MetricsAddr == "" {
+		return nil
+	}
+
+	if _, _, err := net.SplitHostPort(ic.MetricsAddr); err != nil {
+		return fmt.Errorf("invalid --metrics-addr %s: %s", ic.MetricsAddr, err)
+	}
+	return nil
+}
+
+// assertDryRunIsValid ensures a dry run is only requested for local installs,
+// as remote hosts run recipes with their own executor.
+func assertDryRunIsValid(ic InstallerContext) error {
+	if ic.DryRun && ic.RemoteInstall() {
+		return errors.New("--dry-run cannot be used with --ssh")
+	}
+	return nil
+}
+
+// assertResourceLimitsAreValid ensures the recipe resource limits are not
+// negative, and are only given for local installs, as remote hosts run
+// recipes with their own executor.
+func assertResourceLimitsAreValid(ic InstallerContext) error {
+	limits := ic.recipeResourceLimits()
+	if err := limits.Validate(); err != nil {
+		return err
+	}
+
+	if limits.IsSet() && ic.RemoteInstall() {
+		return errors.New("--recipe-memory-limit-mb and --recipe-cpu-limit cannot be used with --ssh")
+	}
+
+	return nil
+}
+
+// maxCampaignIDLength is the longest campaign label accepted.
+const maxCampaignIDLength = 64
+
+var campaignIDPattern = regexp.MustCompile(`^[a-z0-9._-]+$`)
+
+// normalizeCampaignID returns the campaign label trimmed and lowercased, with
+// whitespace replaced by dashes, so that the installs of a campaign are
+// labeled alike however it was typed.  An error is returned when the label
+// has other characters than letters, digits, dots, dashes and underscores, or
+// is too long to name the campaign's NerdStorage collection.
+func normalizeCampaignID(id string) (string, error) {
+	id = strings.ToLower(strings.Join(strings.Fields(id), "-"))
+	if id == "" {
+		return "", nil
+	}
+
+	if len(id) > maxCampaignIDLength {
+		return "", fmt.Errorf("invalid --campaign %s: must be at most %d characters", id, maxCampaignIDLength)
+	}
+
+	if !campaignIDPattern.MatchString(id) {
+		return "", fmt.Errorf("invalid --campaign %s: must only contain letters, digits, dots, dashes and underscores", id)
+	}
+
+	return id, nil
+}
+
+// assertExportCandidatesIsValid ensures candidates are only exported for local
+// guided installs, since targeted installs have no recommendations.
+func assertExportCandidatesIsValid(ic InstallerContext) error {
+	if ic.ExportCandidates == "" {
+		return nil
+	}
+
+	if ic.RemoteInstall() {
+		return errors.New("--export-candidates cannot be used with --ssh")
+	}
+
+	if ic.RecipesProvided() {
+		return errors.New("--export-candidates cannot be used with --recipe or --recipePath, since only guided installs recommend recipes")
+	}
+
+	return nil
+}
+
+// assertContainerAssumptionIsValid ensures the install is not assumed to run
+// both inside a container and on a bare host.
+func assertContainerAssumptionIsValid(ic InstallerContext) error {
+	if ic.AssumeContainer && ic.AssumeHost {
+		return errors.New("--assume-container and --assume-host cannot be used together")
+	}
+	return nil
+}
+
 func init() {
 	Command.Flags().StringSliceVarP(&recipePaths, "recipePath", "c", []string{}, "the path to a recipe file to install")
 	Command.Flags().StringSliceVarP(&recipeNames, "recipe", "n", []string{}, "the name of a recipe to install")
@@ -94,5 +495,68 @@ func init() {
 	Command.Flags().BoolVar(&debug, "debug", false, "debug level logging")
 	Command.Flags().BoolVar(&trace, "trace", false, "trace level logging")
 	Command.Flags().BoolVarP(&assumeYes, "assumeYes", "y", false, "use \"yes\" for all questions during install")
+	Command.Flags().BoolVar(&verboseRecipeSteps, "verbose-recipe-steps", false, "stream each recipe step and its output as it runs")
+	Command.Flags().BoolVar(&resetSelections, "reset-selections", false, "clear the recipe selections remembered from previous installs")
+	Command.Flags().BoolVar(&reinstall, "reinstall", false, "execute recipes again even when they are already installed and reporting data, such as to apply configuration changes")
+	Command.Flags().BoolVar(&force, "force", false, "same as --reinstall")
+	Command.Flags().StringToStringVar(&recipeVarsFiles, "recipe-vars-file", map[string]string{}, "a YAML file of variables for a recipe, given as name=path.yml")
+	Command.Flags().BoolVar(&plan, "plan", false, "show the installation plan and confirm it before installing")
+	Command.Flags().BoolVar(&sendUsageData, "send-usage-data", false, "opt in to sending install duration, outcome and platform details to your New Relic account (disable with NEW_RELIC_CLI_DISABLE_USAGE_DATA=true)")
+	Command.Flags().StringVar(&infraRecipeName, "infra-recipe-name", types.InfraAgentRecipeName, "the name of the recipe used to install the infrastructure agent")
+	Command.Flags().StringVar(&loggingRecipeName, "logging-recipe-name", types.LoggingRecipeName, "the name of the recipe used to install logging")
+	Command.Flags().IntVar(&logMaxMatches, "log-max-matches", 0, "the maximum number of log files matched per pattern, overriding the recipe")
+	Command.Flags().IntVar(&logMaxAgeDays, "log-max-age-days", 0, "skip log files last modified more than this many days ago, overriding the recipe")
+	Command.Flags().StringSliceVar(&logExclude, "log-exclude", []string{}, "glob patterns of log files to exclude, e.g. *.gz")
+	Command.Flags().IntVar(&maxRecipeFailures, "max-recipe-failures", 0, "abort the install when more than this many recipes fail (0 for unlimited)")
+	Command.Flags().StringVar(&outputDir, "output-dir", "", fmt.Sprintf("directory under which the artifacts of each install run are written, keeping the %d most recent (defaults to the config directory)", execution.MaxRunDirectories))
+	Command.Flags().StringSliceVar(&includeTags, "include-tag", []string{}, "only install recommended integrations with any of these keywords")
+	Command.Flags().StringSliceVar(&excludeTags, "exclude-tag", []string{}, "skip recommended integrations with any of these keywords")
+	Command.Flags().DurationVar(&promptTimeout, "prompt-timeout", 0, "answer interactive prompts with their default after this long without a response, e.g. 5m (0 to wait indefinitely)")
+	Command.Flags().BoolVar(&promptTimeoutFails, "prompt-timeout-fails", false, "cancel the install when a prompt times out instead of using its default")
+	Command.Flags().StringSliceVar(&sshHosts, "ssh", []string{}, "install on these remote hosts, as [user@]host[:port], over SSH; go-task must be installed on each host")
+	Command.Flags().StringVar(&sshKeyFile, "ssh-key", "", "private key file used to authenticate to --ssh hosts (defaults to the SSH agent and ~/.ssh keys)")
+	Command.Flags().StringSliceVar(&recipeSources, "recipe-sources", []string{}, "ordered recipe sources to fall back through when one is unavailable: service, cache, or a local recipe directory")
+	Command.Flags().BoolVarP(&quiet, "quiet", "q", false, "suppress all output except warnings, errors and the final summary (requires --assumeYes or --answers-file)")
+	Command.Flags().BoolVar(&audit, "audit", false, "record every command run by recipes, with secrets masked, to an audit log in the output directory")
+	Command.Flags().BoolVar(&ignorePortConflicts, "ignore-port-conflicts", false, "install recipes even when ports they require are already in use")
+	Command.Flags().StringVar(&junitOutput, "junit-output", "", "write the outcome of each recipe to this file as a JUnit XML report")
+	Command.Flags().DurationVar(&installTimeout, "install-timeout", 0, "stop installing after this long, e.g. 30m, skipping the recipes not yet installed (0 for unlimited)")
+	Command.Flags().StringVar(&shell, "shell", "", "the shell to run the steps of every recipe with, overriding the recipes' own: "+strings.Join(execution.ShellNames(), ", "))
+	Command.Flags().BoolVar(&iterativeRecs, "iterative-recommendations", false, "after installing integrations, refresh the recommendations and offer those newly surfaced, a bounded number of times")
+	Command.Flags().BoolVar(&continueOnInfraFail, "continue-on-infra-failure", false, "continue installing logging and integrations when the infrastructure agent fails to install, for hosts whose agent is managed separately")
+	Command.Flags().StringSliceVar(&recipePublicKeys, "recipe-public-key", []string{}, "the path to a public key, PEM or base64 encoded ed25519, to verify the signatures of recipes against")
+	Command.Flags().BoolVar(&requireSigned, "require-signed", false, "reject recipes that are not signed by one of the --recipe-public-key keys")
+	Command.Flags().StringVar(&answersFile, "answers-file", "", "a YAML file of answers to the install's prompts, given in order, to run a guided install non-interactively")
+	Command.Flags().BoolVar(&isolatedEnv, "isolated-env", false, "run recipes with a temporary home directory and an environment cleared of all but proxy, locale, path and recipe variables")
+	Command.Flags().StringVar(&recipeSourceURL, "recipe-source-url", "", "the URL of the GraphQL endpoint of a recipe service to fetch recipes from, such as an internal mirror; defaults to New Relic's")
+	Command.Flags().IntSliceVar(&retryExitCodes, "retry-exit-codes", []int{}, "exit codes of a recipe's install on which it is retried, unless the recipe sets its own")
+	Command.Flags().IntVar(&recipeRetries, "recipe-retries", 0, "the number of times a recipe is retried on a retryable exit code, unless the recipe sets its own")
+	Command.Flags().StringVar(&manifestFile, "manifest", "", "a discovery manifest saved with --save-manifest to install against instead of discovering the host")
+	Command.Flags().StringVar(&saveManifestFile, "save-manifest", "", "the path to save the discovery manifest of the host to")
+	Command.Flags().StringVar(&heartbeatFile, "heartbeat-file", "", "the path of a file to keep updated with the progress of the install, for external monitoring")
+	Command.Flags().DurationVar(&heartbeatInterval, "heartbeat-interval", execution.DefaultHeartbeatInterval, "how often to update the --heartbeat-file while installing")
+	Command.Flags().StringSliceVar(&logAllow, "log-allow", []string{}, "glob patterns of log file patterns or names to watch without prompting; with --assumeYes, only these are watched")
+	Command.Flags().StringSliceVar(&logDeny, "log-deny", []string{}, "glob patterns of log file patterns or names never to watch, taking precedence over --log-allow")
+	Command.Flags().BoolVar(&skipPreflight, "skip-preflight", false, "skip checking that the New Relic endpoints can be reached before installing")
+	Command.Flags().BoolVar(&dryRun, "dry-run", false, "show the commands each recipe would run, without running them or validating the recipes")
+	Command.Flags().StringVar(&campaignID, "campaign", "", "label the install with this campaign, such as a fleet rollout, and report its outcome to the campaign's status document")
+	Command.Flags().BoolVar(&assumeContainer, "assume-container", false, "treat the host as a container, overriding the detection of discovery")
+	Command.Flags().BoolVar(&assumeHost, "assume-host", false, "treat the host as a bare host rather than a container, overriding the detection of discovery")
+	Command.Flags().StringSliceVar(&skipRecipes, "skip", []string{}, "the name of a recipe not to install, marking it as skipped; may be repeated")
+	Command.Flags().IntVar(&logGlobConcurrency, "log-glob-concurrency", 0, "the number of log file patterns searched for at once while discovering logs (0 for the default)")
+	Command.Flags().StringVar(&credentialsFile, "credentials-file", "", "a JSON or YAML file with the apiKey, accountID and region to install with, which take precedence over the default profile and environment variables")
+	Command.Flags().StringVar(&successLinksOutput, "success-links-output", "", "write the links to the installed data of each recipe to this file as JSON, or to stdout when -")
+	Command.Flags().StringVar(&exportCandidates, "export-candidates", "", "discover the host and export the recipes a guided install would install, with why each is included, to this file as JSON, or to stdout when -, without installing anything")
+	Command.Flags().StringVar(&progress, "progress", ux.ProgressPlain, "the style the progress of each recipe is reported in: "+strings.Join(ux.ProgressStyles(), ", ")+"; lines reports each recipe on a line of its own with its state")
+	Command.Flags().IntVar(&recipeMemoryLimitMB, "recipe-memory-limit-mb", 0, "on Linux, the memory in megabytes the processes of each recipe may use, beyond which they are terminated (0 for no limit)")
+	Command.Flags().Float64Var(&recipeCPULimit, "recipe-cpu-limit", 0, "on Linux, the number of CPUs, which may be fractional, the processes of each recipe may use (0 for no limit)")
+	Command.Flags().StringVar(&jsonOutput, "output-json", "", "write each status event of the install to this file as a line of JSON, or to stdout when -, which newrelic install render replays")
+	Command.Flags().StringVar(&messagesFile, "messages-file", "", "a YAML or JSON file overriding the text of the guided install's introductions and prompts, such as selectIntegrations")
+	Command.Flags().StringVar(&lang, "lang", "", "the language of the install's messages: "+strings.Join(Langs(), ", ")+"; defaults to that of "+LangEnvVar+" or of the locale, or else en")
+	Command.Flags().StringVar(&recipePolicyFile, "recipe-policy", "", "a YAML or JSON file of the allow and deny patterns of the recipes that may run; defaults to "+DefaultRecipePolicyFile+" in the config directory, when present")
+	Command.Flags().BoolVar(&diagnosticsOnFail, "diagnostics-on-failure", false, "when the install fails, bundle its manifest, recipe output, events and a summary of the environment, with secrets masked, into a zip under the output directory to attach to support tickets")
+	Command.Flags().StringVar(&caBundle, "ca-bundle", "", "a PEM bundle of CA certificates to trust, in addition to the system's, for the installer's requests, recipe downloads and recipe steps, such as that of a TLS-inspecting proxy")
+	Command.Flags().DurationVar(&observeValidation, "observe-validation", 0, "after each recipe installs, watch its validation query for this long and report when its data was first seen and became stable, to tell delayed ingest from a failed install")
+	Command.Flags().StringVar(&metricsAddr, "metrics-addr", "", "an address, such as localhost:9464, on which to serve the progress of the install as Prometheus metrics until it ends")
 	Command.Flags().StringVarP(&localRecipes, "localRecipes", "", "", "a path to local recipes to load instead of service other fetching")
 }
@@ -0,0 +1,80 @@
+package install
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/client"
+	"github.com/newrelic/newrelic-cli/internal/config"
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/output"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+	"github.com/newrelic/newrelic-client-go/newrelic"
+)
+
+var (
+	diffOutput       string
+	diffOutputDir    string
+	diffLocalRecipes string
+)
+
+var cmdDiff = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare the recipes installed on this host with those recommended for it",
+	Long: `Compare the recipes installed on this host with those recommended for it
+
+The recipes recorded as installed by earlier installs are compared with the
+recipes currently offered for the host, to detect drift between runs.  Recipes
+recommended but not installed are listed as added, recipes installed but no
+longer recommended as removed, and installed recipes that have since been
+updated as changed.  Nothing is installed.
+`,
+	Example: "newrelic install diff --output json",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if diffOutput != "table" && diffOutput != "json" {
+			log.Fatalf("Invalid output %s.  Valid values are table, json", diffOutput)
+		}
+
+		outputDir := diffOutputDir
+		if outputDir == "" {
+			outputDir = config.DefaultConfigDirectory
+		}
+
+		installed, err := execution.LoadInstalledRecipes(outputDir)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		client.WithClient(func(nrClient *newrelic.NewRelic) {
+			i := newRecipeLister(InstallerContext{LocalRecipes: diffLocalRecipes}, nrClient)
+			i.recipeDetector = nil
+
+			diffed, err := i.diffRecipes(utils.SignalCtx, installed)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			if diffOutput == "json" {
+				output.JSON(diffed)
+				return
+			}
+
+			if len(diffed) == 0 {
+				fmt.Println("The installed recipes match those recommended for this host.")
+				return
+			}
+
+			output.Text(diffed)
+		})
+	},
+}
+
+func init() {
+	Command.AddCommand(cmdDiff)
+	cmdDiff.Flags().StringVar(&diffOutput, "output", "table", "output format [table, json]")
+	cmdDiff.Flags().StringVar(&diffOutputDir, "output-dir", "", "directory under which the artifacts of earlier installs were written (defaults to the config directory)")
+	cmdDiff.Flags().StringVar(&diffLocalRecipes, "localRecipes", "", "a path to local recipes to load instead of service other fetching")
+}
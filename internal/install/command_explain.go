@@ -0,0 +1,114 @@
+package install
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/client"
+	"github.com/newrelic/newrelic-cli/internal/install/discovery"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/output"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+	"github.com/newrelic/newrelic-client-go/newrelic"
+)
+
+var (
+	explainOutput       string
+	explainLocalRecipes string
+)
+
+var cmdExplain = &cobra.Command{
+	Use:   "explain <recipe>",
+	Short: "Describe what a recipe will do without installing it",
+	Long: `Describe what a recipe will do without installing it
+
+The recipe is fetched as it would be for installing on this host, and its
+pre- and post-install information, variables, install steps, validation and
+targets are printed.  A recipe file path or URL may be given in place of a name.
+`,
+	Example: "newrelic install explain mysql-open-source-integration",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if explainOutput != "text" && explainOutput != "json" {
+			log.Fatalf("Invalid output %s.  Valid values are text, json", explainOutput)
+		}
+
+		var r *types.OpenInstallationRecipe
+		var err error
+
+		if isRecipeFileOrURL(args[0]) {
+			r, err = recipeFromFileOrURL(recipes.NewRecipeFileFetcher(), args[0])
+		} else if explainLocalRecipes != "" {
+			r, err = fetchRecipeForHost(&recipes.LocalRecipeFetcher{Path: explainLocalRecipes}, args[0])
+		} else {
+			client.WithClient(func(nrClient *newrelic.NewRelic) {
+				r, err = fetchRecipeForHost(recipes.NewServiceRecipeFetcher(&nrClient.NerdGraph), args[0])
+			})
+		}
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		e, err := explainRecipe(*r)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if explainOutput == "json" {
+			output.JSON(e)
+			return
+		}
+
+		fmt.Print(e.String())
+	},
+}
+
+func isRecipeFileOrURL(s string) bool {
+	if u, err := url.Parse(s); err == nil && u.Scheme != "" {
+		return true
+	}
+
+	_, err := os.Stat(s)
+	return err == nil && (strings.HasSuffix(s, ".yml") || strings.HasSuffix(s, ".yaml"))
+}
+
+func recipeFromFileOrURL(ff recipes.RecipeFileFetcher, s string) (*types.OpenInstallationRecipe, error) {
+	if u, err := url.Parse(s); err == nil && u.Scheme != "" {
+		return ff.FetchRecipeFile(u)
+	}
+
+	return ff.LoadRecipeFile(s)
+}
+
+// fetchRecipeForHost fetches the named recipe as it would be fetched for
+// installing on this host.
+func fetchRecipeForHost(f recipes.RecipeFetcher, name string) (*types.OpenInstallationRecipe, error) {
+	m, err := discovery.NewPSUtilDiscoverer(discovery.NewNoOpProcessFilterer()).Discover(utils.SignalCtx)
+	if err != nil {
+		return nil, fmt.Errorf("there was an error discovering system info: %s", err)
+	}
+
+	r, err := f.FetchRecipe(utils.SignalCtx, m, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if r == nil {
+		return nil, fmt.Errorf("recipe %s not found", name)
+	}
+
+	return r, nil
+}
+
+func init() {
+	Command.AddCommand(cmdExplain)
+	cmdExplain.Flags().StringVar(&explainOutput, "output", "text", "output format [text, json]")
+	cmdExplain.Flags().StringVar(&explainLocalRecipes, "localRecipes", "", "a path to local recipes to load instead of service other fetching")
+}
@@ -0,0 +1,95 @@
+package install
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/output"
+)
+
+var lintOutput string
+
+var cmdLint = &cobra.Command{
+	Use:   "lint <file>",
+	Short: "Check a recipe file for problems before publishing it",
+	Long: `Check a recipe file for problems before publishing it
+
+The recipe file is parsed as it is when installed, and checked for missing
+required fields, a missing or malformed validation query, an install without a
+default task, install steps referencing undeclared variables, misspelled fields
+and other common mistakes.  Errors prevent the recipe from installing, while
+warnings flag likely mistakes.  The command exits with a non-zero status when
+errors are found.
+`,
+	Example: "newrelic install lint recipes/mysql.yml",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if lintOutput != "text" && lintOutput != "json" {
+			log.Fatalf("Invalid output %s.  Valid values are text, json", lintOutput)
+		}
+
+		data, err := ioutil.ReadFile(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		issues := lintRecipe(string(data))
+
+		if lintOutput == "json" {
+			output.JSON(issues)
+		} else {
+			fmt.Print(formatLintIssues(args[0], string(data), issues))
+		}
+
+		if errs := countLintIssues(issues, lintSeverityError); errs > 0 {
+			log.Fatalf("%s has %d error(s)", args[0], errs)
+		}
+	},
+}
+
+// formatLintIssues describes the issues found in the recipe file, each with
+// the line it was found on, followed by a count of errors and warnings.
+func formatLintIssues(path string, content string, issues []RecipeLintIssue) string {
+	var b strings.Builder
+	lines := strings.Split(content, "\n")
+
+	for _, issue := range issues {
+		if issue.Line > 0 {
+			fmt.Fprintf(&b, "%s:%d: %s: %s\n", path, issue.Line, issue.Severity, issue.Message)
+		} else {
+			fmt.Fprintf(&b, "%s: %s: %s\n", path, issue.Severity, issue.Message)
+		}
+
+		if issue.Line > 0 && issue.Line <= len(lines) {
+			fmt.Fprintf(&b, "  %4d | %s\n", issue.Line, lines[issue.Line-1])
+		}
+	}
+
+	if len(issues) == 0 {
+		fmt.Fprintf(&b, "%s: no problems found\n", path)
+	} else {
+		fmt.Fprintf(&b, "%d error(s), %d warning(s)\n", countLintIssues(issues, lintSeverityError), countLintIssues(issues, lintSeverityWarning))
+	}
+
+	return b.String()
+}
+
+func countLintIssues(issues []RecipeLintIssue, severity string) int {
+	count := 0
+	for _, issue := range issues {
+		if issue.Severity == severity {
+			count++
+		}
+	}
+
+	return count
+}
+
+func init() {
+	Command.AddCommand(cmdLint)
+	cmdLint.Flags().StringVar(&lintOutput, "output", "text", "output format [text, json]")
+}
@@ -0,0 +1,74 @@
+package install
+
+import (
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/client"
+	"github.com/newrelic/newrelic-cli/internal/install/discovery"
+	"github.com/newrelic/newrelic-cli/internal/install/validation"
+	"github.com/newrelic/newrelic-cli/internal/output"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+	"github.com/newrelic/newrelic-client-go/newrelic"
+)
+
+var (
+	listOutput       string
+	listLocalRecipes string
+)
+
+var cmdList = &cobra.Command{
+	Use:   "list",
+	Short: "List the recipes available for this host",
+	Long: `List the recipes available for this host
+
+The host is discovered as it would be for an install, and every recipe offered
+for it is listed as installed, available, or unsupported along with the reason.
+Nothing is installed.
+`,
+	Example: "newrelic install list --output json",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if listOutput != "table" && listOutput != "json" {
+			log.Fatalf("Invalid output %s.  Valid values are table, json", listOutput)
+		}
+
+		client.WithClient(func(nrClient *newrelic.NewRelic) {
+			i := newRecipeLister(InstallerContext{LocalRecipes: listLocalRecipes}, nrClient)
+
+			listed, err := i.listRecipes(utils.SignalCtx)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			if listOutput == "json" {
+				output.JSON(listed)
+				return
+			}
+
+			output.Text(listed)
+		})
+	},
+}
+
+// newRecipeLister returns a RecipeInstaller with only what is needed to list
+// the recipes for the host.
+func newRecipeLister(ic InstallerContext, nrClient *newrelic.NewRelic) *RecipeInstaller {
+	recipeFetcher := newRecipeFetcher(ic, nrClient)
+
+	i := RecipeInstaller{
+		InstallerContext:  ic,
+		discoverer:        discovery.NewPSUtilDiscoverer(discovery.NewRegexProcessFilterer(recipeFetcher)),
+		manifestValidator: discovery.NewManifestValidator(),
+		recipeFetcher:     recipeFetcher,
+		recipeDetector:    validation.NewNRQLRecipeDetector(&nrClient.Nrdb),
+	}
+
+	return &i
+}
+
+func init() {
+	Command.AddCommand(cmdList)
+	cmdList.Flags().StringVar(&listOutput, "output", "table", "output format [table, json]")
+	cmdList.Flags().StringVar(&listLocalRecipes, "localRecipes", "", "a path to local recipes to load instead of service other fetching")
+}
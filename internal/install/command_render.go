@@ -0,0 +1,102 @@
+package install
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+)
+
+const (
+	renderReporterTerminal = "terminal"
+	renderReporterJUnit    = "junit"
+	renderReporterJSON     = "json"
+)
+
+var (
+	renderReporter string
+	renderOutput   string
+	renderQuiet    bool
+)
+
+var cmdRender = &cobra.Command{
+	Use:   "render <events.json>",
+	Short: "Render a saved install event stream through a reporter",
+	Long: `Render a saved install event stream through a reporter
+
+The JSON event stream written by an install run with --output-json is replayed
+through the chosen reporter, as it would have reported the install while it
+ran, so that the summary of a failed install can be reconstructed from the
+stream without the host it ran on.  The stream is read from standard input
+when the path is -.
+`,
+	Example: "newrelic install render install-events.json --reporter junit --output install.xml",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		reporter, err := newRenderReporter(renderReporter, renderOutput, renderQuiet)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if err := renderEvents(args[0], reporter); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// newRenderReporter returns the status subscriber of the given name, writing
+// to the given output where the reporter writes to a file.
+func newRenderReporter(name string, output string, quiet bool) (execution.StatusSubscriber, error) {
+	switch name {
+	case renderReporterTerminal:
+		return newTerminalStatusReporter(quiet), nil
+
+	case renderReporterJUnit:
+		if output == "" {
+			return nil, fmt.Errorf("the %s reporter requires --output", name)
+		}
+		return execution.NewJUnitStatusReporter(output), nil
+
+	case renderReporterJSON:
+		if output == "" {
+			output = execution.JSONOutputStdout
+		}
+		return execution.NewJSONStatusReporter(output), nil
+	}
+
+	return nil, fmt.Errorf("invalid reporter %s.  Valid values are %s", name, strings.Join(renderReporters(), ", "))
+}
+
+func renderReporters() []string {
+	return []string{renderReporterTerminal, renderReporterJUnit, renderReporterJSON}
+}
+
+// renderEvents replays the event stream at the given path, or standard input
+// when it is -, through the reporter.
+func renderEvents(path string, reporter execution.StatusSubscriber) error {
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("could not open event stream: %s", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	status := execution.NewInstallStatus([]execution.StatusSubscriber{reporter}, execution.NewConcreteSuccessLinkGenerator())
+
+	return execution.ReplayStatusEvents(in, status)
+}
+
+func init() {
+	Command.AddCommand(cmdRender)
+	cmdRender.Flags().StringVar(&renderReporter, "reporter", renderReporterTerminal, "the reporter to render the events through: "+strings.Join(renderReporters(), ", "))
+	cmdRender.Flags().StringVar(&renderOutput, "output", "", "the file the junit and json reporters write to, the json reporter writing to stdout by default")
+	cmdRender.Flags().BoolVar(&renderQuiet, "quiet", false, "limit the terminal reporter to failures and the final summary line")
+}
@@ -3,6 +3,7 @@
 package install
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -16,3 +17,110 @@ func TestInstallCommand(t *testing.T) {
 	testcobra.CheckCobraMetadata(t, Command)
 	testcobra.CheckCobraRequiredFlags(t, Command, []string{})
 }
+
+func TestAssertQuietIsValid(t *testing.T) {
+	assert.NoError(t, assertQuietIsValid(InstallerContext{}))
+	assert.NoError(t, assertQuietIsValid(InstallerContext{Quiet: true, AssumeYes: true}))
+	assert.Error(t, assertQuietIsValid(InstallerContext{Quiet: true}))
+	assert.NoError(t, assertQuietIsValid(InstallerContext{Quiet: true, AnswersFile: "answers.yml"}))
+}
+
+func TestAssertDryRunIsValid(t *testing.T) {
+	assert.NoError(t, assertDryRunIsValid(InstallerContext{DryRun: true}))
+	assert.NoError(t, assertDryRunIsValid(InstallerContext{SSHHosts: []string{"host"}}))
+	assert.Error(t, assertDryRunIsValid(InstallerContext{DryRun: true, SSHHosts: []string{"host"}}))
+}
+
+func TestAssertMetricsAddrIsValid(t *testing.T) {
+	assert.NoError(t, assertMetricsAddrIsValid(InstallerContext{}))
+	assert.NoError(t, assertMetricsAddrIsValid(InstallerContext{MetricsAddr: "localhost:9464"}))
+	assert.NoError(t, assertMetricsAddrIsValid(InstallerContext{MetricsAddr: ":9464"}))
+	assert.Error(t, assertMetricsAddrIsValid(InstallerContext{MetricsAddr: "9464"}))
+}
+
+func TestAssertContainerAssumptionIsValid(t *testing.T) {
+	assert.NoError(t, assertContainerAssumptionIsValid(InstallerContext{AssumeContainer: true}))
+	assert.NoError(t, assertContainerAssumptionIsValid(InstallerContext{AssumeHost: true}))
+	assert.Error(t, assertContainerAssumptionIsValid(InstallerContext{AssumeContainer: true, AssumeHost: true}))
+}
+
+func TestAssertExportCandidatesIsValid(t *testing.T) {
+	assert.NoError(t, assertExportCandidatesIsValid(InstallerContext{}))
+	assert.NoError(t, assertExportCandidatesIsValid(InstallerContext{ExportCandidates: "-"}))
+	assert.Error(t, assertExportCandidatesIsValid(InstallerContext{ExportCandidates: "-", SSHHosts: []string{"host"}}))
+	assert.Error(t, assertExportCandidatesIsValid(InstallerContext{ExportCandidates: "-", RecipeNames: []string{"mysql"}}))
+}
+
+func TestAssertResourceLimitsAreValid(t *testing.T) {
+	assert.NoError(t, assertResourceLimitsAreValid(InstallerContext{}))
+	assert.NoError(t, assertResourceLimitsAreValid(InstallerContext{RecipeMemoryLimitMB: 512, RecipeCPULimit: 0.5}))
+	assert.NoError(t, assertResourceLimitsAreValid(InstallerContext{SSHHosts: []string{"host"}}))
+	assert.Error(t, assertResourceLimitsAreValid(InstallerContext{RecipeMemoryLimitMB: -1}))
+	assert.Error(t, assertResourceLimitsAreValid(InstallerContext{RecipeCPULimit: -0.5}))
+	assert.Error(t, assertResourceLimitsAreValid(InstallerContext{RecipeMemoryLimitMB: 512, SSHHosts: []string{"host"}}))
+}
+
+func TestNormalizeCampaignID(t *testing.T) {
+	id, err := normalizeCampaignID("")
+	assert.NoError(t, err)
+	assert.Equal(t, "", id)
+
+	id, err = normalizeCampaignID("  Q3 Rollout_web.1 ")
+	assert.NoError(t, err)
+	assert.Equal(t, "q3-rollout_web.1", id)
+
+	_, err = normalizeCampaignID("rollout/1")
+	assert.Error(t, err)
+
+	_, err = normalizeCampaignID(strings.Repeat("a", maxCampaignIDLength+1))
+	assert.Error(t, err)
+}
+
+func TestAssertSignatureConfigIsValid(t *testing.T) {
+	assert.NoError(t, assertSignatureConfigIsValid(InstallerContext{}))
+	assert.Error(t, assertSignatureConfigIsValid(InstallerContext{RequireSignedRecipes: true}))
+	assert.Error(t, assertSignatureConfigIsValid(InstallerContext{RecipePublicKeys: []string{"/nonexistent/key.pem"}}))
+}
+
+func TestAssertRecipeSourceURLIsValid(t *testing.T) {
+	assert.NoError(t, assertRecipeSourceURLIsValid(InstallerContext{}))
+	assert.NoError(t, assertRecipeSourceURLIsValid(InstallerContext{RecipeSourceURL: "https://recipes.example.com/graphql"}))
+	assert.Error(t, assertRecipeSourceURLIsValid(InstallerContext{RecipeSourceURL: "recipes.example.com"}))
+	assert.Error(t, assertRecipeSourceURLIsValid(InstallerContext{RecipeSourceURL: "ftp://recipes.example.com"}))
+	assert.Error(t, assertRecipeSourceURLIsValid(InstallerContext{RecipeSourceURL: "https://"}))
+}
+
+func TestAssertRetryConfigIsValid(t *testing.T) {
+	assert.NoError(t, assertRetryConfigIsValid(InstallerContext{}))
+	assert.NoError(t, assertRetryConfigIsValid(InstallerContext{RetryExitCodes: []int{2, 100}, RecipeRetries: 3}))
+	assert.Error(t, assertRetryConfigIsValid(InstallerContext{RecipeRetries: -1}))
+	assert.Error(t, assertRetryConfigIsValid(InstallerContext{RetryExitCodes: []int{0}}))
+	assert.Error(t, assertRetryConfigIsValid(InstallerContext{RetryExitCodes: []int{256}}))
+}
+
+func TestAssertManifestFileIsValid(t *testing.T) {
+	assert.NoError(t, assertManifestFileIsValid(InstallerContext{}))
+	assert.Error(t, assertManifestFileIsValid(InstallerContext{ManifestFile: "/nonexistent/manifest.json"}))
+	assert.Error(t, assertManifestFileIsValid(InstallerContext{ManifestFile: "command_test.go", SSHHosts: []string{"host1"}}))
+	assert.NoError(t, assertManifestFileIsValid(InstallerContext{ManifestFile: "command_test.go"}))
+}
+
+func TestNewRenderReporter(t *testing.T) {
+	r, err := newRenderReporter("terminal", "", false)
+	assert.NoError(t, err)
+	assert.NotNil(t, r)
+
+	r, err = newRenderReporter("json", "", false)
+	assert.NoError(t, err)
+	assert.NotNil(t, r)
+
+	_, err = newRenderReporter("junit", "", false)
+	assert.Error(t, err)
+
+	r, err = newRenderReporter("junit", "install.xml", false)
+	assert.NoError(t, err)
+	assert.NotNil(t, r)
+
+	_, err = newRenderReporter("html", "", false)
+	assert.Error(t, err)
+}
@@ -0,0 +1,118 @@
+package install
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/newrelic/newrelic-cli/internal/client"
+	"github.com/newrelic/newrelic-cli/internal/config"
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/validation"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+	"github.com/newrelic/newrelic-client-go/newrelic"
+)
+
+var cmdValidate = &cobra.Command{
+	Use:   "validate <recipe>",
+	Short: "Validate a recipe whose validation failed during an earlier install",
+	Long: `Validate a recipe whose validation failed during an earlier install
+
+When a recipe installs but its data cannot be validated, for instance because
+ingest is delayed, the context of the install is recorded.  This command polls
+for the recipe's data again using that context, without running its steps
+again, and reports and updates the recipe's install status.
+`,
+	Example: "newrelic install validate mysql-open-source-integration",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client.WithClient(func(nrClient *newrelic.NewRelic) {
+			store := newValidationCheckpointStore()
+
+			cp, err := loadValidationCheckpoint(store, args[0])
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			pv := validation.NewPollingRecipeValidator(&nrClient.Nrdb)
+			pv.AccountID = cp.AccountID
+			v := validation.NewAccountRecipeValidator(pv)
+			v.AccountID = cp.AccountID
+
+			status := execution.NewInstallStatus([]execution.StatusSubscriber{
+				execution.NewNerdStorageStatusReporter(&nrClient.NerdStorage),
+				execution.NewValidationCheckpointReporter(store),
+			}, execution.NewConcreteSuccessLinkGenerator())
+
+			if _, err := validateCheckpoint(utils.SignalCtx, cp, v, status); err != nil {
+				log.Fatal(err)
+			}
+		})
+	},
+}
+
+func newValidationCheckpointStore() *execution.ValidationCheckpointStore {
+	return execution.NewValidationCheckpointStore(filepath.Join(config.DefaultConfigDirectory, validationCheckpointDirName))
+}
+
+// loadValidationCheckpoint returns the checkpoint recorded for the named
+// recipe, failing when there is none.
+func loadValidationCheckpoint(store *execution.ValidationCheckpointStore, recipeName string) (*execution.ValidationCheckpoint, error) {
+	cp, err := store.Load(recipeName)
+	if err != nil {
+		return nil, err
+	}
+
+	if cp == nil {
+		return nil, fmt.Errorf("no failed validation is recorded for recipe %s", recipeName)
+	}
+
+	return cp, nil
+}
+
+// validateCheckpoint validates the recipe of the checkpoint with the recorded
+// context, reporting the outcome to the status.  The checkpoint is kept when
+// validation fails again, and removed once it passes.
+func validateCheckpoint(ctx context.Context, cp *execution.ValidationCheckpoint, v validation.RecipeValidator, status *execution.InstallStatus) (string, error) {
+	log.WithFields(log.Fields{
+		"name":           cp.Recipe.Name,
+		"account_id":     cp.AccountID,
+		"correlation_id": cp.CorrelationID,
+	}).Debug("resuming validation")
+
+	status.DiscoveryComplete(cp.Manifest)
+
+	fmt.Printf("Validating %s, last attempted %s...\n", cp.Recipe.Name, cp.FailedAt.Format("2006-01-02 15:04:05"))
+
+	entityGUID, err := v.ValidateRecipe(ctx, cp.Manifest, cp.Recipe)
+	if err != nil {
+		msg := fmt.Sprintf("encountered an error while validating receipt of data for %s: %s", cp.Recipe.Name, err)
+		status.RecipeFailed(execution.RecipeStatusEvent{
+			Recipe:           cp.Recipe,
+			Msg:              msg,
+			ValidationFailed: err != types.ErrInterrupt,
+		})
+		status.InstallComplete(err)
+
+		return "", errors.New(msg)
+	}
+
+	status.RecipeInstalled(execution.RecipeStatusEvent{
+		Recipe:     cp.Recipe,
+		EntityGUID: entityGUID,
+	})
+	status.InstallComplete(nil)
+
+	fmt.Printf("%s is reporting data.\n", cp.Recipe.Name)
+
+	return entityGUID, nil
+}
+
+func init() {
+	Command.AddCommand(cmdValidate)
+}
@@ -0,0 +1,94 @@
+// +build unit
+
+package install
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/validation"
+)
+
+func TestValidateCheckpoint(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "newrelic")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	store := execution.NewValidationCheckpointStore(tmp)
+	require.NoError(t, store.Save(execution.ValidationCheckpoint{
+		Recipe:    types.OpenInstallationRecipe{Name: testRecipeName, ValidationNRQL: "testNrql"},
+		Manifest:  types.DiscoveryManifest{Hostname: "db-1"},
+		AccountID: 12345,
+	}))
+
+	_, err = loadValidationCheckpoint(store, "missing-recipe")
+	require.Error(t, err)
+
+	cp, err := loadValidationCheckpoint(store, testRecipeName)
+	require.NoError(t, err)
+
+	// Validation times out again: the checkpoint is kept.
+	sr := execution.NewMockStatusReporter()
+	status := execution.NewInstallStatus([]execution.StatusSubscriber{sr, execution.NewValidationCheckpointReporter(store)}, execution.NewMockSuccessLinkGenerator())
+	v := validation.NewMockRecipeValidator()
+	v.ValidateErr = errors.New("no data reported")
+
+	_, err = validateCheckpoint(context.Background(), cp, v, status)
+	require.Error(t, err)
+	require.Equal(t, 1, sr.RecipeFailedCallCount)
+
+	cp, err = loadValidationCheckpoint(store, testRecipeName)
+	require.NoError(t, err)
+
+	// Data arrives: the recipe is installed and the checkpoint removed.
+	sr = execution.NewMockStatusReporter()
+	status = execution.NewInstallStatus([]execution.StatusSubscriber{sr, execution.NewValidationCheckpointReporter(store)}, execution.NewMockSuccessLinkGenerator())
+	v = validation.NewMockRecipeValidator()
+	v.ValidateVal = "entity-guid"
+
+	entityGUID, err := validateCheckpoint(context.Background(), cp, v, status)
+	require.NoError(t, err)
+	require.Equal(t, "entity-guid", entityGUID)
+	require.Equal(t, 1, sr.RecipeInstalledCallCount)
+	require.Equal(t, 1, sr.InstallCompleteCallCount)
+	require.Equal(t, "db-1", status.DiscoveryManifest.Hostname)
+
+	_, err = loadValidationCheckpoint(store, testRecipeName)
+	require.Error(t, err)
+}
+
+func TestInstall_ValidationFailureSavesCheckpoint(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "newrelic")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	store := execution.NewValidationCheckpointStore(tmp)
+
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+		SkipIntegrations:   true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter(), execution.NewValidationCheckpointReporter(store)}
+	status = execution.NewInstallStatus(statusReporters, execution.NewMockSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{Name: types.InfraAgentRecipeName, ValidationNRQL: "testNrql"},
+	}
+	v = validation.NewMockRecipeValidator()
+	v.ValidateErr = errors.New("no data reported")
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	require.Error(t, i.Install())
+
+	cp, err := loadValidationCheckpoint(store, types.InfraAgentRecipeName)
+	require.NoError(t, err)
+	require.Equal(t, types.NRQL("testNrql"), cp.Recipe.ValidationNRQL)
+}
@@ -0,0 +1,61 @@
+package install
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// applyContainerAssumption overrides whether discovery detected the installer
+// running inside a container with --assume-container or --assume-host.  A
+// container assumed but not detected is of unknown kind.
+func (i *RecipeInstaller) applyContainerAssumption(m *types.DiscoveryManifest) {
+	switch {
+	case i.AssumeHost:
+		m.Container = ""
+	case i.AssumeContainer && m.Container == "":
+		m.Container = types.ContainerTypes.GENERIC
+	}
+
+	if m.InContainer() {
+		log.Debugf("installing inside a %s container", m.Container)
+	}
+}
+
+// confirmContainerInstall guards against installing the infrastructure agent
+// inside a container, where it monitors the container rather than its host.
+// The user is asked whether to install anyway, and the install is refused
+// without asking when --assumeYes is set, unless --assume-host is.
+func (i *RecipeInstaller) confirmContainerInstall(m *types.DiscoveryManifest) error {
+	if !m.InContainer() {
+		return nil
+	}
+
+	log.Warnf("The installer is running inside a %s container, where the infrastructure agent would monitor the container rather than its host.", m.Container)
+	log.Warnf("To monitor the host, %s.", containerizedAgentAdvice(m.Container))
+
+	if i.AssumeYes {
+		return NewErrInContainer(m.Container)
+	}
+
+	ok, err := i.prompter.PromptYesNo(showMessage(i.messages().ConfirmContainerInstall, messageData{Container: m.Container}))
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return NewErrUserCanceled("installing the infrastructure agent inside a container was declined")
+	}
+
+	return nil
+}
+
+// containerizedAgentAdvice recommends how to monitor the host of the given
+// kind of container.
+func containerizedAgentAdvice(container string) string {
+	if container == types.ContainerTypes.KUBERNETES {
+		return "install the Kubernetes integration in the cluster instead"
+	}
+
+	return "run the containerized infrastructure agent, the newrelic/infrastructure image, on the host instead"
+}
@@ -0,0 +1,93 @@
+// +build unit
+
+package install
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/discovery"
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/ux"
+	"github.com/newrelic/newrelic-cli/internal/install/validation"
+)
+
+func TestApplyContainerAssumption(t *testing.T) {
+	i := RecipeInstaller{InstallerContext: InstallerContext{}}
+
+	m := &types.DiscoveryManifest{Container: "docker"}
+	i.applyContainerAssumption(m)
+	require.Equal(t, "docker", m.Container)
+
+	i.AssumeHost = true
+	i.applyContainerAssumption(m)
+	require.False(t, m.InContainer())
+
+	i.AssumeHost = false
+	i.AssumeContainer = true
+	i.applyContainerAssumption(m)
+	require.Equal(t, types.ContainerTypes.GENERIC, m.Container)
+
+	m = &types.DiscoveryManifest{Container: "podman"}
+	i.applyContainerAssumption(m)
+	require.Equal(t, "podman", m.Container)
+}
+
+func TestConfirmContainerInstall(t *testing.T) {
+	mp := ux.NewMockPrompter()
+	i := RecipeInstaller{InstallerContext: InstallerContext{}, prompter: mp}
+
+	require.NoError(t, i.confirmContainerInstall(&types.DiscoveryManifest{}))
+	require.Equal(t, 0, mp.PromptYesNoCallCount)
+
+	m := &types.DiscoveryManifest{Container: "docker"}
+
+	mp.PromptYesNoVal = true
+	require.NoError(t, i.confirmContainerInstall(m))
+	require.Equal(t, 1, mp.PromptYesNoCallCount)
+
+	mp.PromptYesNoVal = false
+	require.ErrorIs(t, i.confirmContainerInstall(m), types.ErrInterrupt)
+
+	i.AssumeYes = true
+	var cerr ErrInContainer
+	require.True(t, errors.As(i.confirmContainerInstall(m), &cerr))
+	require.Equal(t, "docker", cerr.Container)
+	require.Contains(t, cerr.Error(), "--assume-host")
+	require.Equal(t, 2, mp.PromptYesNoCallCount)
+}
+
+func TestInstall_InfraAgentNotInstalledInContainer(t *testing.T) {
+	ic := InstallerContext{
+		RecipeNames: []string{types.InfraAgentRecipeName},
+		AssumeYes:   true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+	e := execution.NewMockRecipeExecutor()
+	v = validation.NewMockRecipeValidator()
+	d := discovery.NewMockDiscovererWithManifest(types.DiscoveryManifest{OS: "linux", Container: "docker"})
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.Error(t, err)
+	require.True(t, errors.As(err, &ErrInContainer{}))
+	require.Equal(t, 0, e.ExecuteCallCount)
+
+	// The detection is overridden with --assume-host.
+	i.AssumeHost = true
+	d.DiscoveryManifest.Container = "docker"
+	require.NoError(t, i.Install())
+	require.Equal(t, 1, e.ExecuteCallCount)
+}
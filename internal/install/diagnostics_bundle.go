@@ -0,0 +1,33 @@
+package install
+
+import (
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// writeDiagnosticsBundle bundles the artifacts of the failed install for
+// support when --diagnostics-on-failure is set, printing and returning the
+// path of the bundle.  Diagnostics are best-effort, so a failure is only
+// warned about.
+func (i *RecipeInstaller) writeDiagnosticsBundle() string {
+	if !i.DiagnosticsOnFailure {
+		return ""
+	}
+
+	if i.runDir == "" {
+		log.Warnf("No diagnostics bundle was written: the install artifacts were not recorded.")
+		return ""
+	}
+
+	path, err := i.status.WriteDiagnosticsBundle(i.runDir)
+	if err != nil {
+		log.Warnf("No diagnostics bundle was written: %s", err)
+		return ""
+	}
+
+	fmt.Fprintf(os.Stderr, "Diagnostics written to %s. Attach them to your support ticket.\n", path)
+
+	return path
+}
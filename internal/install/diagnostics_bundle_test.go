@@ -0,0 +1,45 @@
+// +build unit
+
+package install
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+)
+
+func TestInstall_DiagnosticsOnFailure(t *testing.T) {
+	baseDir, err := ioutil.TempDir("", "diagnostics")
+	require.NoError(t, err)
+	defer os.RemoveAll(baseDir)
+
+	for _, enabled := range []bool{true, false} {
+		runDir, err := execution.CreateRunDirectory(baseDir, time.Now())
+		require.NoError(t, err)
+
+		ic := InstallerContext{DiagnosticsOnFailure: enabled, runDir: runDir}
+		statusReporters = []execution.StatusSubscriber{execution.NewArtifactStatusReporter(runDir)}
+		status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+		f = recipes.NewMockRecipeFetcher()
+		f.FetchRecipeErr = recipes.NewErrRecipeNotFoundMessage("infrastructure agent was unable to be installed for your operating system")
+
+		i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+		result, err := i.InstallWithResult()
+		require.Error(t, err)
+
+		if !enabled {
+			require.Empty(t, result.DiagnosticsBundle)
+			continue
+		}
+
+		require.Equal(t, execution.DiagnosticsBundlePath(runDir), result.DiagnosticsBundle)
+		_, err = os.Stat(result.DiagnosticsBundle)
+		require.NoError(t, err)
+	}
+}
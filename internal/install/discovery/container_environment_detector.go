@@ -0,0 +1,76 @@
+package discovery
+
+import (
+	"context"
+	"strings"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// ContainerEnvironmentDetector detects whether the installer runs inside a
+// container, returning the kind of container or an empty string on a bare
+// host.
+type ContainerEnvironmentDetector interface {
+	ContainerEnvironment(context.Context) (string, error)
+}
+
+// initCgroupPath lists the control groups of the first process, which are
+// those of the container when running in one.
+const initCgroupPath = "/proc/1/cgroup"
+
+// containerMarker is a file a container runtime creates inside the containers
+// it runs.
+type containerMarker struct {
+	name string
+	path string
+}
+
+// containerMarkers are the files recognizing a container, in order of
+// precedence.  Pods are recognized first since they also run in docker or
+// containerd containers.
+var containerMarkers = []containerMarker{
+	{name: types.ContainerTypes.KUBERNETES, path: "/var/run/secrets/kubernetes.io/serviceaccount"},
+	{name: types.ContainerTypes.DOCKER, path: "/.dockerenv"},
+	{name: types.ContainerTypes.PODMAN, path: "/run/.containerenv"},
+}
+
+// containerCgroupPatterns recognize a container by the control groups of the
+// first process, in order of precedence.
+var containerCgroupPatterns = []containerMarker{
+	{name: types.ContainerTypes.KUBERNETES, path: "kubepods"},
+	{name: types.ContainerTypes.PODMAN, path: "libpod"},
+	{name: types.ContainerTypes.DOCKER, path: "docker"},
+	{name: types.ContainerTypes.CONTAINERD, path: "containerd"},
+	{name: types.ContainerTypes.LXC, path: "lxc"},
+}
+
+// containerMarkerPaths returns every path inspected to detect a container.
+func containerMarkerPaths() []string {
+	paths := []string{}
+	for _, m := range containerMarkers {
+		paths = append(paths, m.path)
+	}
+
+	return paths
+}
+
+// detectContainer returns the kind of container recognized by its marker files
+// or by the control groups of the first process, or an empty string when none
+// is recognized.
+func detectContainer(exists func(string) bool, cgroup string) string {
+	for _, m := range containerMarkers {
+		if exists(m.path) {
+			return m.name
+		}
+	}
+
+	for _, line := range strings.Split(cgroup, "\n") {
+		for _, p := range containerCgroupPatterns {
+			if strings.Contains(line, p.path) {
+				return p.name
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,102 @@
+// +build unit
+
+package discovery
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/remote"
+)
+
+func TestDetectContainer(t *testing.T) {
+	tests := map[string]struct {
+		paths    []string
+		cgroup   string
+		expected string
+	}{
+		"bare host": {
+			cgroup:   "12:pids:/init.scope\n1:name=systemd:/init.scope\n",
+			expected: "",
+		},
+		"docker": {
+			paths:    []string{"/.dockerenv"},
+			cgroup:   "0::/\n",
+			expected: "docker",
+		},
+		"podman": {
+			paths:    []string{"/run/.containerenv"},
+			expected: "podman",
+		},
+		"kubernetes pod in docker": {
+			paths:    []string{"/.dockerenv", "/var/run/secrets/kubernetes.io/serviceaccount"},
+			expected: "kubernetes",
+		},
+		"docker by cgroup": {
+			cgroup:   "12:pids:/docker/3f6a1c\n",
+			expected: "docker",
+		},
+		"kubernetes by cgroup": {
+			cgroup:   "1:name=systemd:/kubepods/besteffort/pod1/cri-containerd-3f6a1c\n",
+			expected: "kubernetes",
+		},
+		"lxc by cgroup": {
+			cgroup:   "1:name=systemd:/lxc/web\n",
+			expected: "lxc",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			existing := map[string]bool{}
+			for _, p := range tc.paths {
+				existing[p] = true
+			}
+
+			require.Equal(t, tc.expected, detectContainer(func(path string) bool { return existing[path] }, tc.cgroup))
+		})
+	}
+}
+
+func TestLocalContainerEnvironmentDetector(t *testing.T) {
+	root, err := ioutil.TempDir("", "container-environment")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	d := &LocalContainerEnvironmentDetector{root: root}
+	container, err := d.ContainerEnvironment(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "", container)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, ".dockerenv"), nil, 0644))
+
+	container, err = d.ContainerEnvironment(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "docker", container)
+}
+
+func TestSSHContainerEnvironmentDetector(t *testing.T) {
+	r := remote.NewMockRunner("host")
+	r.Responses[remoteCgroupCmd] = remote.MockResponse{
+		Output: "0::/system.slice/containerd.service/kubepods-burstable.slice\n",
+	}
+
+	container, err := NewSSHContainerEnvironmentDetector(r).ContainerEnvironment(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "kubernetes", container)
+}
+
+func TestContainerEnvironment_IgnoresErrors(t *testing.T) {
+	d := NewMockContainerEnvironmentDetector()
+	d.ContainerEnvironmentVal = "docker"
+	d.ContainerEnvironmentErr = errors.New("permission denied")
+
+	require.Equal(t, "", containerEnvironment(context.Background(), d))
+	require.Equal(t, 1, d.ContainerEnvironmentCallCount)
+}
@@ -0,0 +1,83 @@
+package discovery
+
+import (
+	"context"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// ContainerRuntimeDetector detects the container runtimes running on the host.
+type ContainerRuntimeDetector interface {
+	ContainerRuntimes(context.Context) ([]types.ContainerRuntime, error)
+}
+
+// containerRuntimeProbe describes how a container runtime is recognized: by the
+// presence of any of its sockets.
+type containerRuntimeProbe struct {
+	name    string
+	sockets []string
+	logDirs []string
+}
+
+// containerRuntimeProbes are the container runtimes detected, along with the
+// directories they are known to write container logs to.
+var containerRuntimeProbes = []containerRuntimeProbe{
+	{
+		name:    types.ContainerRuntimeTypes.DOCKER,
+		sockets: []string{"/var/run/docker.sock", "/run/docker.sock"},
+		logDirs: []string{"/var/lib/docker/containers"},
+	},
+	{
+		name:    types.ContainerRuntimeTypes.CONTAINERD,
+		sockets: []string{"/run/containerd/containerd.sock", "/var/run/containerd/containerd.sock"},
+		logDirs: []string{"/var/log/pods", "/var/log/containers"},
+	},
+	{
+		name:    types.ContainerRuntimeTypes.CRIO,
+		sockets: []string{"/var/run/crio/crio.sock", "/run/crio/crio.sock"},
+		logDirs: []string{"/var/log/pods", "/var/log/containers"},
+	},
+}
+
+// containerRuntimePaths returns every path inspected to detect container
+// runtimes.
+func containerRuntimePaths() []string {
+	paths := []string{}
+	for _, p := range containerRuntimeProbes {
+		paths = append(paths, p.sockets...)
+		paths = append(paths, p.logDirs...)
+	}
+
+	return paths
+}
+
+// detectContainerRuntimes returns the runtimes with an existing socket, each
+// with the first of its log directories that exists.
+func detectContainerRuntimes(exists func(string) bool) []types.ContainerRuntime {
+	runtimes := []types.ContainerRuntime{}
+
+	for _, p := range containerRuntimeProbes {
+		socket := firstExisting(p.sockets, exists)
+		if socket == "" {
+			continue
+		}
+
+		runtimes = append(runtimes, types.ContainerRuntime{
+			Name:   p.name,
+			Socket: socket,
+			LogDir: firstExisting(p.logDirs, exists),
+		})
+	}
+
+	return runtimes
+}
+
+func firstExisting(paths []string, exists func(string) bool) string {
+	for _, path := range paths {
+		if exists(path) {
+			return path
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,66 @@
+// +build unit
+
+package discovery
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/remote"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestLocalContainerRuntimeDetector(t *testing.T) {
+	root, err := ioutil.TempDir("", "container-runtimes")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	for _, dir := range []string{"var/run", "var/lib/docker/containers", "run/containerd"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0755))
+	}
+	for _, socket := range []string{"var/run/docker.sock", "run/containerd/containerd.sock"} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(root, socket), nil, 0600))
+	}
+
+	d := &LocalContainerRuntimeDetector{root: root}
+	runtimes, err := d.ContainerRuntimes(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []types.ContainerRuntime{
+		{Name: "docker", Socket: "/var/run/docker.sock", LogDir: "/var/lib/docker/containers"},
+		{Name: "containerd", Socket: "/run/containerd/containerd.sock"},
+	}, runtimes)
+}
+
+func TestSSHContainerRuntimeDetector(t *testing.T) {
+	r := remote.NewMockRunner("host")
+	r.Responses[remoteExistingPathsCmd(containerRuntimePaths())] = remote.MockResponse{
+		Output: "/run/containerd/containerd.sock\n/var/log/pods\n/var/log/containers\n",
+	}
+
+	runtimes, err := NewSSHContainerRuntimeDetector(r).ContainerRuntimes(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []types.ContainerRuntime{
+		{Name: "containerd", Socket: "/run/containerd/containerd.sock", LogDir: "/var/log/pods"},
+	}, runtimes)
+}
+
+func TestContainerRuntimes(t *testing.T) {
+	d := NewMockContainerRuntimeDetector()
+	d.ContainerRuntimesVal = []types.ContainerRuntime{{Name: "docker", Socket: "/var/run/docker.sock"}}
+
+	require.Equal(t, d.ContainerRuntimesVal, containerRuntimes(context.Background(), d))
+	require.Equal(t, 1, d.ContainerRuntimesCallCount)
+}
+
+func TestContainerRuntimes_IgnoresErrors(t *testing.T) {
+	d := NewMockContainerRuntimeDetector()
+	d.ContainerRuntimesErr = errors.New("permission denied")
+
+	require.Nil(t, containerRuntimes(context.Background(), d))
+}
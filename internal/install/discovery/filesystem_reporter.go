@@ -0,0 +1,31 @@
+package discovery
+
+import (
+	"context"
+	"os"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+const bytesPerMB = 1024 * 1024
+
+// FilesystemReporter reports the space available on the filesystems holding
+// the directories recipes install to.
+type FilesystemReporter interface {
+	Filesystems(context.Context) ([]types.Filesystem, error)
+}
+
+// filesystemPaths returns the directories recipes install to on the given
+// operating system.
+func filesystemPaths(goos string) []string {
+	if goos == "windows" {
+		drive := os.Getenv("SystemDrive")
+		if drive == "" {
+			drive = "C:"
+		}
+
+		return []string{drive + `\`}
+	}
+
+	return []string{"/", "/usr", "/var", "/opt", "/etc"}
+}
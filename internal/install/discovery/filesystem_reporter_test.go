@@ -0,0 +1,63 @@
+// +build unit
+
+package discovery
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/remote"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestPSUtilFilesystemReporter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filesystems")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	r := &PSUtilFilesystemReporter{paths: []string{dir, dir + "/missing"}}
+	filesystems, err := r.Filesystems(context.Background())
+	require.NoError(t, err)
+	require.Len(t, filesystems, 1)
+	require.Equal(t, dir, filesystems[0].Path)
+}
+
+func TestSSHFilesystemReporter(t *testing.T) {
+	r := remote.NewMockRunner("host")
+	r.Responses[remoteFreeSpaceCmd(filesystemPaths("linux"))] = remote.MockResponse{
+		Output: "/ 2097152\n/usr 2097152\n/opt \n/var 51200\n",
+	}
+
+	filesystems, err := NewSSHFilesystemReporter(r).Filesystems(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []types.Filesystem{
+		{Path: "/", FreeMB: 2048},
+		{Path: "/usr", FreeMB: 2048},
+		{Path: "/var", FreeMB: 50},
+	}, filesystems)
+}
+
+func TestFilesystemPaths(t *testing.T) {
+	require.Contains(t, filesystemPaths("linux"), "/")
+	require.Len(t, filesystemPaths("windows"), 1)
+}
+
+func TestFilesystems(t *testing.T) {
+	r := NewMockFilesystemReporter()
+	r.FilesystemsVal = []types.Filesystem{{Path: "/", FreeMB: 1024}}
+
+	require.Equal(t, r.FilesystemsVal, filesystems(context.Background(), r))
+	require.Equal(t, 1, r.FilesystemsCallCount)
+}
+
+func TestFilesystems_IgnoresErrors(t *testing.T) {
+	r := NewMockFilesystemReporter()
+	r.FilesystemsErr = errors.New("permission denied")
+
+	require.Nil(t, filesystems(context.Background(), r))
+}
@@ -2,16 +2,38 @@ package discovery
 
 import (
 	"context"
+	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/newrelic/newrelic-cli/internal/install/types"
 )
 
+// defaultGlobConcurrency is the number of log match patterns evaluated at once
+// when the concurrency is not set.
+const defaultGlobConcurrency = 4
+
 // GlobFileFilterer is an implementation of the FileFilterer interface that uses
 // glob-based filesystem searches to locate the existence of files.
-type GlobFileFilterer struct{}
+type GlobFileFilterer struct {
+	// MaxMatches, when set, overrides the maximum number of files matched per
+	// pattern declared by the recipe.
+	MaxMatches int
+	// MaxAgeDays, when set, overrides the age in days beyond which files are
+	// not matched declared by the recipe.
+	MaxAgeDays int
+	// Exclude holds glob patterns of files to exclude in addition to those
+	// declared by the recipe.
+	Exclude []string
+	// Concurrency is the number of log match patterns evaluated at once,
+	// defaulting to defaultGlobConcurrency.
+	Concurrency int
+}
 
 // NewGlobFileFilterer returns a new instance of GlobFileFilterer.
 func NewGlobFileFilterer() *GlobFileFilterer {
@@ -21,22 +43,153 @@ func NewGlobFileFilterer() *GlobFileFilterer {
 }
 
 // Filter uses the patterns provided in the passed recipe to return matches based
-// on which files exist in the underlying file system.
+// on which files exist in the underlying file system.  When matched files are
+// excluded, too old or truncated, the returned match lists the remaining files
+// explicitly in place of its pattern.  Patterns are evaluated concurrently, and
+// the matches are returned in the order of the recipes and their patterns.  The
+// context's error is returned when it ends before every pattern is evaluated.
 func (f *GlobFileFilterer) Filter(ctx context.Context, recipes []types.OpenInstallationRecipe) ([]types.OpenInstallationLogMatch, error) {
-	fileMatches := []types.OpenInstallationLogMatch{}
-
+	matchers := []types.OpenInstallationLogMatch{}
 	for _, r := range recipes {
-		for _, l := range r.LogMatch {
-			match, _ := matchLogFilesFromRecipe(l)
-			if match {
-				fileMatches = append(fileMatches, l)
+		matchers = append(matchers, r.LogMatch...)
+	}
+
+	concurrency := f.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultGlobConcurrency
+	}
+
+	results := make([]*types.OpenInstallationLogMatch, len(matchers))
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for n, l := range matchers {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(n int, l types.OpenInstallationLogMatch) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if ctx.Err() != nil {
+				return
 			}
+
+			results[n] = f.filterMatch(l)
+		}(n, l)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	fileMatches := []types.OpenInstallationLogMatch{}
+	for _, m := range results {
+		if m != nil {
+			fileMatches = append(fileMatches, *m)
 		}
 	}
 
 	return fileMatches, nil
 }
 
+// filterMatch returns the log match with the files its pattern matches, or nil
+// when it matches none that are kept.
+func (f *GlobFileFilterer) filterMatch(l types.OpenInstallationLogMatch) *types.OpenInstallationLogMatch {
+	match, files := matchLogFilesFromRecipe(l)
+	if !match {
+		return nil
+	}
+
+	limited := f.limitFiles(l, files)
+	if len(limited) == 0 {
+		return nil
+	}
+
+	if len(limited) < len(files) {
+		l.File = strings.Join(limited, ",")
+	}
+
+	return &l
+}
+
+func (f *GlobFileFilterer) limitFiles(matcher types.OpenInstallationLogMatch, files []string) []string {
+	maxMatches := matcher.MaxMatches
+	if f.MaxMatches > 0 {
+		maxMatches = f.MaxMatches
+	}
+
+	maxAgeDays := matcher.MaxAgeDays
+	if f.MaxAgeDays > 0 {
+		maxAgeDays = f.MaxAgeDays
+	}
+
+	exclude := append(append([]string{}, matcher.Exclude...), f.Exclude...)
+
+	type fileInfo struct {
+		path    string
+		modTime time.Time
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -maxAgeDays)
+	candidates := []fileInfo{}
+	for _, file := range files {
+		if isExcluded(file, exclude) {
+			continue
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			log.Debugf("could not stat log file %s: %s", file, err)
+			continue
+		}
+
+		if maxAgeDays > 0 && info.ModTime().Before(cutoff) {
+			continue
+		}
+
+		candidates = append(candidates, fileInfo{path: file, modTime: info.ModTime()})
+	}
+
+	if maxMatches > 0 && len(candidates) > maxMatches {
+		log.Warnf("Log file pattern %s matched %d files, only the %d most recently modified will be used.", matcher.File, len(candidates), maxMatches)
+
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].modTime.After(candidates[j].modTime)
+		})
+
+		candidates = candidates[:maxMatches]
+	}
+
+	limited := make([]string, len(candidates))
+	for i, c := range candidates {
+		limited[i] = c.path
+	}
+
+	return limited
+}
+
+func isExcluded(file string, exclude []string) bool {
+	for _, pattern := range exclude {
+		if matched, _ := filepath.Match(pattern, file); matched {
+			return true
+		}
+
+		if matched, _ := filepath.Match(pattern, filepath.Base(file)); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
 func matchLogFilesFromRecipe(matcher types.OpenInstallationLogMatch) (bool, []string) {
 	matches, err := filepath.Glob(matcher.File)
 	if err != nil {
@@ -1,13 +1,17 @@
+//go:build integration
 // +build integration
 
 package discovery
 
 import (
 	"context"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -96,3 +100,201 @@ func TestMatchLogFilesFromRecipe(t *testing.T) {
 	require.True(t, matched)
 	require.Equal(t, 2, len(files))
 }
+
+func createLogFile(t *testing.T, dir string, name string, age time.Duration) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, []byte("log"), 0600))
+
+	modTime := time.Now().Add(-age)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+
+	return path
+}
+
+func TestGlobFileFilter_Exclude(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("/tmp", "logfiles")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	current := createLogFile(t, tmpDir, "app.log", 0)
+	createLogFile(t, tmpDir, "app.log.1.gz", time.Hour)
+
+	recipes := []types.OpenInstallationRecipe{{
+		LogMatch: []types.OpenInstallationLogMatch{{
+			File:    filepath.Join(tmpDir, "app.log*"),
+			Exclude: []string{"*.gz"},
+		}},
+	}}
+
+	f := NewGlobFileFilterer()
+	filtered, err := f.Filter(context.Background(), recipes)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(filtered))
+	require.Equal(t, current, filtered[0].File)
+}
+
+func TestGlobFileFilter_MaxAgeDays(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("/tmp", "logfiles")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	createLogFile(t, tmpDir, "old.log", 10*24*time.Hour)
+
+	recipes := []types.OpenInstallationRecipe{{
+		LogMatch: []types.OpenInstallationLogMatch{{
+			File:       filepath.Join(tmpDir, "*.log"),
+			MaxAgeDays: 7,
+		}},
+	}}
+
+	f := NewGlobFileFilterer()
+	filtered, err := f.Filter(context.Background(), recipes)
+	require.NoError(t, err)
+	require.Empty(t, filtered)
+
+	recipes[0].LogMatch[0].MaxAgeDays = 30
+	filtered, err = f.Filter(context.Background(), recipes)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(filtered))
+	require.Equal(t, filepath.Join(tmpDir, "*.log"), filtered[0].File)
+}
+
+func TestGlobFileFilter_MaxMatches(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("/tmp", "logfiles")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	newest := createLogFile(t, tmpDir, "a.log", 0)
+	middle := createLogFile(t, tmpDir, "b.log", time.Hour)
+	createLogFile(t, tmpDir, "c.log", 2*time.Hour)
+
+	recipes := []types.OpenInstallationRecipe{{
+		LogMatch: []types.OpenInstallationLogMatch{{
+			File:       filepath.Join(tmpDir, "*.log"),
+			MaxMatches: 2,
+		}},
+	}}
+
+	f := NewGlobFileFilterer()
+	filtered, err := f.Filter(context.Background(), recipes)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(filtered))
+	require.Equal(t, strings.Join([]string{newest, middle}, ","), filtered[0].File)
+}
+
+func TestGlobFileFilter_Overrides(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("/tmp", "logfiles")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	newest := createLogFile(t, tmpDir, "a.log", 0)
+	createLogFile(t, tmpDir, "b.log", time.Hour)
+	createLogFile(t, tmpDir, "c.log", 2*time.Hour)
+
+	recipes := []types.OpenInstallationRecipe{{
+		LogMatch: []types.OpenInstallationLogMatch{{
+			File:       filepath.Join(tmpDir, "*.log"),
+			MaxMatches: 2,
+		}},
+	}}
+
+	f := NewGlobFileFilterer()
+	f.MaxMatches = 1
+	filtered, err := f.Filter(context.Background(), recipes)
+	require.NoError(t, err)
+	require.Equal(t, newest, filtered[0].File)
+
+	f.MaxMatches = 0
+	f.Exclude = []string{"a.log", "b.log", "c.log"}
+	filtered, err = f.Filter(context.Background(), recipes)
+	require.NoError(t, err)
+	require.Empty(t, filtered)
+}
+
+// createLogTree creates a directory per pattern under dir, each holding a few
+// log files, and returns a recipe with a log match per directory.
+func createLogTree(t testing.TB, dir string, patterns int) []types.OpenInstallationRecipe {
+	recipes := []types.OpenInstallationRecipe{}
+	for n := 0; n < patterns; n++ {
+		sub := filepath.Join(dir, fmt.Sprintf("app-%03d", n))
+		require.NoError(t, os.MkdirAll(sub, 0755))
+
+		for _, name := range []string{"access.log", "error.log", "debug.txt"} {
+			require.NoError(t, ioutil.WriteFile(filepath.Join(sub, name), []byte("log"), 0644))
+		}
+
+		recipes = append(recipes, types.OpenInstallationRecipe{
+			Name: fmt.Sprintf("app-%03d", n),
+			LogMatch: []types.OpenInstallationLogMatch{
+				{Name: "access", File: filepath.Join(sub, "access.log")},
+				{Name: "missing", File: filepath.Join(sub, "*.gz")},
+				{Name: "all", File: filepath.Join(sub, "*.log")},
+			},
+		})
+	}
+
+	return recipes
+}
+
+func TestGlobFileFilter_ManyPatternsOrdered(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("/tmp", "logfiles")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	recipes := createLogTree(t, tmpDir, 50)
+
+	sequential := NewGlobFileFilterer()
+	sequential.Concurrency = 1
+	expected, err := sequential.Filter(context.Background(), recipes)
+	require.NoError(t, err)
+	require.Len(t, expected, 100)
+
+	for n, r := range recipes {
+		require.Equal(t, r.LogMatch[0].File, expected[2*n].File)
+		require.Equal(t, r.LogMatch[2].File, expected[2*n+1].File)
+	}
+
+	f := NewGlobFileFilterer()
+	f.Concurrency = 16
+	for i := 0; i < 5; i++ {
+		filtered, err := f.Filter(context.Background(), recipes)
+		require.NoError(t, err)
+		require.Equal(t, expected, filtered)
+	}
+}
+
+func TestGlobFileFilter_Canceled(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("/tmp", "logfiles")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	recipes := createLogTree(t, tmpDir, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	filtered, err := NewGlobFileFilterer().Filter(ctx, recipes)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Nil(t, filtered)
+}
+
+func BenchmarkGlobFileFilter(b *testing.B) {
+	tmpDir, err := ioutil.TempDir("/tmp", "logfiles")
+	require.NoError(b, err)
+	defer os.RemoveAll(tmpDir)
+
+	recipes := createLogTree(b, tmpDir, 200)
+
+	for _, concurrency := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("concurrency-%d", concurrency), func(b *testing.B) {
+			f := NewGlobFileFilterer()
+			f.Concurrency = concurrency
+
+			for i := 0; i < b.N; i++ {
+				if _, err := f.Filter(context.Background(), recipes); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package discovery
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// LocalContainerEnvironmentDetector is an implementation of the
+// ContainerEnvironmentDetector interface that looks for the signs of a
+// container on the local filesystem.
+type LocalContainerEnvironmentDetector struct {
+	root string
+}
+
+// NewLocalContainerEnvironmentDetector returns a new instance of
+// LocalContainerEnvironmentDetector.
+func NewLocalContainerEnvironmentDetector() *LocalContainerEnvironmentDetector {
+	return &LocalContainerEnvironmentDetector{}
+}
+
+func (d *LocalContainerEnvironmentDetector) ContainerEnvironment(ctx context.Context) (string, error) {
+	// The control groups are only listed on Linux.
+	cgroup, _ := ioutil.ReadFile(filepath.Join(d.root, initCgroupPath))
+
+	return detectContainer(func(path string) bool {
+		_, err := os.Stat(filepath.Join(d.root, path))
+		return err == nil
+	}, string(cgroup)), nil
+}
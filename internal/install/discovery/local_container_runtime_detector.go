@@ -0,0 +1,29 @@
+package discovery
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// LocalContainerRuntimeDetector is an implementation of the
+// ContainerRuntimeDetector interface that looks for the sockets and log
+// directories of known container runtimes on the local filesystem.
+type LocalContainerRuntimeDetector struct {
+	root string
+}
+
+// NewLocalContainerRuntimeDetector returns a new instance of
+// LocalContainerRuntimeDetector.
+func NewLocalContainerRuntimeDetector() *LocalContainerRuntimeDetector {
+	return &LocalContainerRuntimeDetector{}
+}
+
+func (d *LocalContainerRuntimeDetector) ContainerRuntimes(ctx context.Context) ([]types.ContainerRuntime, error) {
+	return detectContainerRuntimes(func(path string) bool {
+		_, err := os.Stat(filepath.Join(d.root, path))
+		return err == nil
+	}), nil
+}
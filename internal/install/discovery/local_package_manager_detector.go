@@ -0,0 +1,27 @@
+package discovery
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+)
+
+// LocalPackageManagerDetector is an implementation of the
+// PackageManagerDetector interface that looks for the executables of known
+// package managers on the local filesystem.
+type LocalPackageManagerDetector struct {
+	root string
+}
+
+// NewLocalPackageManagerDetector returns a new instance of
+// LocalPackageManagerDetector.
+func NewLocalPackageManagerDetector() *LocalPackageManagerDetector {
+	return &LocalPackageManagerDetector{}
+}
+
+func (d *LocalPackageManagerDetector) PackageManagers(ctx context.Context) ([]string, error) {
+	return detectPackageManagers(func(path string) bool {
+		_, err := os.Stat(filepath.Join(d.root, path))
+		return err == nil
+	}), nil
+}
@@ -0,0 +1,113 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+const manifestFilePerm = 0600
+
+// SaveManifest writes the manifest to the named file as JSON.
+func SaveManifest(path string, m types.DiscoveryManifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := utils.WriteFileAtomic(path, data, manifestFilePerm); err != nil {
+		return fmt.Errorf("could not save discovery manifest to %s: %s", path, err)
+	}
+
+	return nil
+}
+
+// LoadManifest reads a manifest written by SaveManifest.
+func LoadManifest(path string) (*types.DiscoveryManifest, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read discovery manifest: %s", err)
+	}
+
+	var m types.DiscoveryManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("could not read discovery manifest %s: %s", path, err)
+	}
+
+	return &m, nil
+}
+
+// FileDiscoverer is an implementation of the Discoverer interface that loads a
+// previously saved manifest instead of discovering the host.  A warning is
+// logged when the saved host differs from the local one.
+type FileDiscoverer struct {
+	path      string
+	localHost func(context.Context) (types.DiscoveryManifest, error)
+}
+
+// NewFileDiscoverer returns a new instance of FileDiscoverer loading the
+// manifest saved at the given path.
+func NewFileDiscoverer(path string) *FileDiscoverer {
+	d := FileDiscoverer{
+		path:      path,
+		localHost: localHostManifest,
+	}
+
+	return &d
+}
+
+func (d *FileDiscoverer) Discover(ctx context.Context) (*types.DiscoveryManifest, error) {
+	m, err := LoadManifest(d.path)
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"path": d.path,
+	}).Debug("loaded discovery manifest")
+
+	current, err := d.localHost(ctx)
+	if err != nil {
+		log.Debugf("cannot compare the loaded discovery manifest to this host: %s", err)
+		return m, nil
+	}
+
+	if changes := manifestHostChanges(*m, current); len(changes) > 0 {
+		log.Warnf("The discovery manifest %s appears to describe a different or changed host: %v", d.path, changes)
+	}
+
+	return m, nil
+}
+
+// manifestHostChanges describes how the host information of the saved
+// manifest differs from the current one.
+func manifestHostChanges(saved types.DiscoveryManifest, current types.DiscoveryManifest) []string {
+	changes := []string{}
+
+	fields := []struct {
+		name    string
+		saved   string
+		current string
+	}{
+		{"hostname", saved.Hostname, current.Hostname},
+		{"os", saved.OS, current.OS},
+		{"platform", saved.Platform, current.Platform},
+		{"platform version", saved.PlatformVersion, current.PlatformVersion},
+		{"kernel version", saved.KernelVersion, current.KernelVersion},
+		{"kernel arch", saved.KernelArch, current.KernelArch},
+	}
+
+	for _, f := range fields {
+		if f.saved != f.current {
+			changes = append(changes, fmt.Sprintf("%s %q is now %q", f.name, f.saved, f.current))
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,90 @@
+// +build unit
+
+package discovery
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestSaveManifest_LoadManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "newrelic-cli-manifest")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "manifest.json")
+
+	m := types.DiscoveryManifest{
+		Hostname: "web-1",
+		OS:       "linux",
+		Platform: "ubuntu",
+	}
+	m.AddMatchedProcess(types.MatchedProcess{
+		Command:         "nginx",
+		MatchingPattern: "nginx",
+		Process:         mockProcess{pid: 7, name: "nginx", cmdline: "nginx: master process"},
+	})
+
+	require.NoError(t, SaveManifest(path, m))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(manifestFilePerm), info.Mode().Perm())
+
+	loaded, err := LoadManifest(path)
+	require.NoError(t, err)
+	require.Equal(t, "web-1", loaded.Hostname)
+	require.Len(t, loaded.Processes, 1)
+	require.Equal(t, int32(7), loaded.Processes[0].Process.PID())
+}
+
+func TestLoadManifest_ShouldFailWhenMissing(t *testing.T) {
+	_, err := LoadManifest("/nonexistent/manifest.json")
+	require.Error(t, err)
+}
+
+func TestFileDiscoverer_Discover(t *testing.T) {
+	dir, err := ioutil.TempDir("", "newrelic-cli-manifest")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "manifest.json")
+	require.NoError(t, SaveManifest(path, types.DiscoveryManifest{Hostname: "web-1", OS: "linux"}))
+
+	d := NewFileDiscoverer(path)
+	d.localHost = func(context.Context) (types.DiscoveryManifest, error) {
+		return types.DiscoveryManifest{Hostname: "web-2", OS: "linux"}, nil
+	}
+
+	m, err := d.Discover(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "web-1", m.Hostname)
+
+	d.localHost = func(context.Context) (types.DiscoveryManifest, error) {
+		return types.DiscoveryManifest{}, errors.New("unavailable")
+	}
+
+	m, err = d.Discover(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "web-1", m.Hostname)
+}
+
+func TestManifestHostChanges(t *testing.T) {
+	saved := types.DiscoveryManifest{Hostname: "web-1", OS: "linux", Platform: "ubuntu", PlatformVersion: "18.04"}
+
+	require.Empty(t, manifestHostChanges(saved, saved))
+
+	current := saved
+	current.PlatformVersion = "20.04"
+	changes := manifestHostChanges(saved, current)
+	require.Len(t, changes, 1)
+	require.Contains(t, changes[0], "platform version")
+}
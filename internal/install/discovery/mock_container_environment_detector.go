@@ -0,0 +1,20 @@
+package discovery
+
+import (
+	"context"
+)
+
+type MockContainerEnvironmentDetector struct {
+	ContainerEnvironmentCallCount int
+	ContainerEnvironmentErr       error
+	ContainerEnvironmentVal       string
+}
+
+func NewMockContainerEnvironmentDetector() *MockContainerEnvironmentDetector {
+	return &MockContainerEnvironmentDetector{}
+}
+
+func (d *MockContainerEnvironmentDetector) ContainerEnvironment(context.Context) (string, error) {
+	d.ContainerEnvironmentCallCount++
+	return d.ContainerEnvironmentVal, d.ContainerEnvironmentErr
+}
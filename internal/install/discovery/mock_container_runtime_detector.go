@@ -0,0 +1,22 @@
+package discovery
+
+import (
+	"context"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+type MockContainerRuntimeDetector struct {
+	ContainerRuntimesCallCount int
+	ContainerRuntimesErr       error
+	ContainerRuntimesVal       []types.ContainerRuntime
+}
+
+func NewMockContainerRuntimeDetector() *MockContainerRuntimeDetector {
+	return &MockContainerRuntimeDetector{}
+}
+
+func (d *MockContainerRuntimeDetector) ContainerRuntimes(context.Context) ([]types.ContainerRuntime, error) {
+	d.ContainerRuntimesCallCount++
+	return d.ContainerRuntimesVal, d.ContainerRuntimesErr
+}
@@ -22,6 +22,14 @@ func NewMockDiscoverer() *MockDiscoverer {
 	}
 }
 
+// NewMockDiscovererWithManifest returns a MockDiscoverer that always discovers
+// the given manifest.
+func NewMockDiscovererWithManifest(m types.DiscoveryManifest) *MockDiscoverer {
+	return &MockDiscoverer{
+		DiscoveryManifest: &m,
+	}
+}
+
 func (d *MockDiscoverer) SetOs(os string) {
 	d.DiscoveryManifest.OS = os
 }
@@ -0,0 +1,22 @@
+package discovery
+
+import (
+	"context"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+type MockFilesystemReporter struct {
+	FilesystemsCallCount int
+	FilesystemsErr       error
+	FilesystemsVal       []types.Filesystem
+}
+
+func NewMockFilesystemReporter() *MockFilesystemReporter {
+	return &MockFilesystemReporter{}
+}
+
+func (r *MockFilesystemReporter) Filesystems(context.Context) ([]types.Filesystem, error) {
+	r.FilesystemsCallCount++
+	return r.FilesystemsVal, r.FilesystemsErr
+}
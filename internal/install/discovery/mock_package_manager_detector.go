@@ -0,0 +1,20 @@
+package discovery
+
+import (
+	"context"
+)
+
+type MockPackageManagerDetector struct {
+	PackageManagersCallCount int
+	PackageManagersErr       error
+	PackageManagersVal       []string
+}
+
+func NewMockPackageManagerDetector() *MockPackageManagerDetector {
+	return &MockPackageManagerDetector{}
+}
+
+func (d *MockPackageManagerDetector) PackageManagers(context.Context) ([]string, error) {
+	d.PackageManagersCallCount++
+	return d.PackageManagersVal, d.PackageManagersErr
+}
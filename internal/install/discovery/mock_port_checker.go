@@ -0,0 +1,20 @@
+package discovery
+
+import (
+	"context"
+)
+
+type MockPortChecker struct {
+	ListeningPortsCallCount int
+	ListeningPortsErr       error
+	ListeningPortsVal       []int
+}
+
+func NewMockPortChecker() *MockPortChecker {
+	return &MockPortChecker{}
+}
+
+func (c *MockPortChecker) ListeningPorts(context.Context) ([]int, error) {
+	c.ListeningPortsCallCount++
+	return c.ListeningPortsVal, c.ListeningPortsErr
+}
@@ -0,0 +1,74 @@
+package discovery
+
+import (
+	"regexp"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// knownMonitoringAgent describes the processes of a monitoring agent whose
+// data may overlap with that of the infrastructure agent.
+type knownMonitoringAgent struct {
+	name   string
+	vendor string
+	// Matched against the process name
+	namePattern *regexp.Regexp
+	// Matched against the command line, for agents run by an interpreter
+	cmdlinePattern *regexp.Regexp
+}
+
+var knownMonitoringAgents = []knownMonitoringAgent{
+	{name: "Datadog Agent", vendor: "Datadog", namePattern: regexp.MustCompile(`^(datadog-agent|trace-agent|process-agent)(\.exe)?$`), cmdlinePattern: regexp.MustCompile(`datadog-agent[/\\]bin[/\\]`)},
+	{name: "Dynatrace OneAgent", vendor: "Dynatrace", namePattern: regexp.MustCompile(`^oneagent(watchdog|helper|os|network)?(\.exe)?$`)},
+	{name: "AppDynamics Machine Agent", vendor: "AppDynamics", cmdlinePattern: regexp.MustCompile(`machineagent\.jar`)},
+	{name: "Elastic Agent", vendor: "Elastic", namePattern: regexp.MustCompile(`^(elastic-agent|metricbeat|apm-server)(\.exe)?$`)},
+	{name: "Splunk OpenTelemetry Collector", vendor: "Splunk", namePattern: regexp.MustCompile(`^(otelcol|signalfx-agent)(\.exe)?$`)},
+	{name: "Instana Agent", vendor: "Instana", cmdlinePattern: regexp.MustCompile(`com\.instana\.agent`)},
+	{name: "Sysdig Agent", vendor: "Sysdig", namePattern: regexp.MustCompile(`^(dragent|sysdig-agent)$`)},
+	{name: "Zabbix Agent", vendor: "Zabbix", namePattern: regexp.MustCompile(`^zabbix_agent(d|2)?(\.exe)?$`)},
+	{name: "Telegraf", vendor: "InfluxData", namePattern: regexp.MustCompile(`^telegraf(\.exe)?$`)},
+	{name: "collectd", vendor: "collectd", namePattern: regexp.MustCompile(`^collectd$`)},
+}
+
+// monitoringAgents returns the known monitoring agents of other vendors among
+// the given processes, each listed once.
+func monitoringAgents(processes []types.GenericProcess) []types.MonitoringAgent {
+	found := []types.MonitoringAgent{}
+	seen := map[string]bool{}
+
+	for _, p := range processes {
+		name, err := p.Name()
+		if err != nil {
+			continue
+		}
+
+		cmdline, err := p.Cmdline()
+		if err != nil {
+			cmdline = ""
+		}
+
+		for _, a := range knownMonitoringAgents {
+			if seen[a.name] || !a.matches(name, cmdline) {
+				continue
+			}
+
+			seen[a.name] = true
+			found = append(found, types.MonitoringAgent{
+				Name:    a.name,
+				Vendor:  a.vendor,
+				Command: name,
+				PID:     p.PID(),
+			})
+		}
+	}
+
+	return found
+}
+
+func (a knownMonitoringAgent) matches(name string, cmdline string) bool {
+	if a.namePattern != nil && a.namePattern.MatchString(name) {
+		return true
+	}
+
+	return a.cmdlinePattern != nil && a.cmdlinePattern.MatchString(cmdline)
+}
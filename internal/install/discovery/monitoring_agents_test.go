@@ -0,0 +1,53 @@
+// +build unit
+
+package discovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/remote"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestMonitoringAgents(t *testing.T) {
+	processes := []types.GenericProcess{
+		mockProcess{pid: 1, name: "systemd", cmdline: "/sbin/init"},
+		mockProcess{pid: 10, name: "agent", cmdline: "/opt/datadog-agent/bin/agent/agent run"},
+		mockProcess{pid: 11, name: "trace-agent", cmdline: "/opt/datadog-agent/embedded/bin/trace-agent"},
+		mockProcess{pid: 20, name: "java", cmdline: "java -jar /opt/appdynamics/machineagent.jar"},
+		mockProcess{pid: 30, name: "zabbix_agentd", cmdline: "/usr/sbin/zabbix_agentd"},
+		mockProcess{pid: 40, name: "newrelic-infra", cmdline: "/usr/bin/newrelic-infra"},
+	}
+
+	agents := monitoringAgents(processes)
+
+	require.Equal(t, []types.MonitoringAgent{
+		{Name: "Datadog Agent", Vendor: "Datadog", Command: "agent", PID: 10},
+		{Name: "AppDynamics Machine Agent", Vendor: "AppDynamics", Command: "java", PID: 20},
+		{Name: "Zabbix Agent", Vendor: "Zabbix", Command: "zabbix_agentd", PID: 30},
+	}, agents)
+}
+
+func TestMonitoringAgents_None(t *testing.T) {
+	processes := []types.GenericProcess{
+		mockProcess{pid: 1, name: "agent", cmdline: "/usr/local/bin/agent"},
+		mockProcess{pid: 2, name: "mysqld", cmdline: "/usr/sbin/mysqld"},
+	}
+
+	require.Empty(t, monitoringAgents(processes))
+}
+
+func TestSSHDiscoverer_MonitoringAgents(t *testing.T) {
+	r := newTestRunner()
+	r.Responses[remoteProcessesCmd] = remote.MockResponse{Output: "    1 systemd /sbin/init\n  900 telegraf /usr/bin/telegraf --config /etc/telegraf/telegraf.conf\n"}
+	d := NewSSHDiscoverer(r, NewNoOpProcessFilterer())
+
+	m, err := d.Discover(context.Background())
+	require.NoError(t, err)
+	require.Len(t, m.MonitoringAgents, 1)
+	require.Equal(t, "Telegraf", m.MonitoringAgents[0].Name)
+	require.Equal(t, int32(900), m.MonitoringAgents[0].PID)
+}
@@ -0,0 +1,93 @@
+package discovery
+
+import (
+	"context"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+// PackageManagerDetector detects the package managers available on the host.
+type PackageManagerDetector interface {
+	PackageManagers(context.Context) ([]string, error)
+}
+
+// packageManagerProbe describes how a package manager is recognized: by the
+// presence of any of its executables.
+type packageManagerProbe struct {
+	name     string
+	binaries []string
+	families []string
+}
+
+// packageManagerProbes are the package managers detected, in order of
+// preference, along with the platform families they are native to.
+var packageManagerProbes = []packageManagerProbe{
+	{
+		name:     types.PackageManagerTypes.APT,
+		binaries: []string{"/usr/bin/apt-get", "/bin/apt-get"},
+		families: []string{"debian"},
+	},
+	{
+		name:     types.PackageManagerTypes.DNF,
+		binaries: []string{"/usr/bin/dnf", "/bin/dnf"},
+		families: []string{"rhel", "fedora"},
+	},
+	{
+		name:     types.PackageManagerTypes.YUM,
+		binaries: []string{"/usr/bin/yum", "/bin/yum"},
+		families: []string{"rhel", "fedora"},
+	},
+	{
+		name:     types.PackageManagerTypes.ZYPPER,
+		binaries: []string{"/usr/bin/zypper", "/bin/zypper"},
+		families: []string{"suse"},
+	},
+	{
+		name:     types.PackageManagerTypes.APK,
+		binaries: []string{"/sbin/apk", "/usr/bin/apk"},
+		families: []string{"alpine"},
+	},
+}
+
+// packageManagerPaths returns every path inspected to detect package
+// managers.
+func packageManagerPaths() []string {
+	paths := []string{}
+	for _, p := range packageManagerProbes {
+		paths = append(paths, p.binaries...)
+	}
+
+	return paths
+}
+
+// detectPackageManagers returns the package managers with an existing
+// executable, in order of preference.
+func detectPackageManagers(exists func(string) bool) []string {
+	managers := []string{}
+
+	for _, p := range packageManagerProbes {
+		if firstExisting(p.binaries, exists) != "" {
+			managers = append(managers, p.name)
+		}
+	}
+
+	return managers
+}
+
+// primaryPackageManager returns the package manager recipes should use among
+// those available: the preferred one native to the platform family, or
+// otherwise the first available.
+func primaryPackageManager(available []string, platformFamily string) string {
+	for _, p := range packageManagerProbes {
+		if utils.StringInSlice(p.name, available) && utils.StringInSlice(platformFamily, p.families) {
+			return p.name
+		}
+	}
+
+	if len(available) > 0 {
+		return available[0]
+	}
+
+	return ""
+}
@@ -0,0 +1,113 @@
+// +build unit
+
+package discovery
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/remote"
+)
+
+func TestDetectPackageManagers(t *testing.T) {
+	tests := map[string]struct {
+		paths          []string
+		platformFamily string
+		expected       []string
+		primary        string
+	}{
+		"ubuntu": {
+			paths:          []string{"/usr/bin/apt-get"},
+			platformFamily: "debian",
+			expected:       []string{"apt"},
+			primary:        "apt",
+		},
+		"rhel 8 with both dnf and yum": {
+			paths:          []string{"/usr/bin/dnf", "/usr/bin/yum"},
+			platformFamily: "rhel",
+			expected:       []string{"dnf", "yum"},
+			primary:        "dnf",
+		},
+		"centos 7": {
+			paths:          []string{"/usr/bin/yum"},
+			platformFamily: "rhel",
+			expected:       []string{"yum"},
+			primary:        "yum",
+		},
+		"suse": {
+			paths:          []string{"/usr/bin/zypper"},
+			platformFamily: "suse",
+			expected:       []string{"zypper"},
+			primary:        "zypper",
+		},
+		"alpine": {
+			paths:          []string{"/sbin/apk"},
+			platformFamily: "",
+			expected:       []string{"apk"},
+			primary:        "apk",
+		},
+		"suse with apt also installed": {
+			paths:          []string{"/usr/bin/apt-get", "/usr/bin/zypper"},
+			platformFamily: "suse",
+			expected:       []string{"apt", "zypper"},
+			primary:        "zypper",
+		},
+		"none": {
+			platformFamily: "debian",
+			expected:       []string{},
+			primary:        "",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			existing := map[string]bool{}
+			for _, p := range tc.paths {
+				existing[p] = true
+			}
+
+			managers := detectPackageManagers(func(path string) bool { return existing[path] })
+			require.Equal(t, tc.expected, managers)
+			require.Equal(t, tc.primary, primaryPackageManager(managers, tc.platformFamily))
+		})
+	}
+}
+
+func TestLocalPackageManagerDetector(t *testing.T) {
+	root, err := ioutil.TempDir("", "package-managers")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "usr/bin"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "usr/bin/yum"), nil, 0755))
+
+	d := &LocalPackageManagerDetector{root: root}
+	managers, err := d.PackageManagers(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"yum"}, managers)
+}
+
+func TestSSHPackageManagerDetector(t *testing.T) {
+	r := remote.NewMockRunner("host")
+	r.Responses[remoteExistingPathsCmd(packageManagerPaths())] = remote.MockResponse{
+		Output: "/usr/bin/dnf\n/usr/bin/yum\n",
+	}
+
+	managers, err := NewSSHPackageManagerDetector(r).PackageManagers(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"dnf", "yum"}, managers)
+}
+
+func TestPackageManagers_IgnoresErrors(t *testing.T) {
+	d := NewMockPackageManagerDetector()
+	d.PackageManagersErr = errors.New("permission denied")
+
+	require.Nil(t, packageManagers(context.Background(), d))
+	require.Equal(t, 1, d.PackageManagersCallCount)
+}
@@ -0,0 +1,28 @@
+package discovery
+
+import (
+	"context"
+	"sort"
+)
+
+// PortChecker enumerates the ports on which the host is listening.
+type PortChecker interface {
+	ListeningPorts(context.Context) ([]int, error)
+}
+
+// uniquePorts returns the given ports sorted and without duplicates.
+func uniquePorts(ports []int) []int {
+	seen := map[int]bool{}
+	unique := []int{}
+
+	for _, p := range ports {
+		if p > 0 && !seen[p] {
+			seen[p] = true
+			unique = append(unique, p)
+		}
+	}
+
+	sort.Ints(unique)
+
+	return unique
+}
@@ -0,0 +1,55 @@
+//go:build unit
+// +build unit
+
+package discovery
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/remote"
+)
+
+func TestParseListeningPorts(t *testing.T) {
+	out := `LISTEN 0      4096   127.0.0.53%lo:53        0.0.0.0:*
+LISTEN 0      128          0.0.0.0:22        0.0.0.0:*
+LISTEN 0      511                *:80              *:*
+LISTEN 0      128             [::]:22           [::]:*
+LISTEN 0      128        [::1]:8080          [::]:*
+`
+
+	require.Equal(t, []int{22, 53, 80, 8080}, parseListeningPorts(out))
+}
+
+func TestSSHPortChecker_ListeningPorts(t *testing.T) {
+	r := remote.NewMockRunner("host")
+	r.Responses[remoteListeningPortsCmd] = remote.MockResponse{
+		Output: "LISTEN 0 128 0.0.0.0:3306 0.0.0.0:*\n",
+	}
+
+	ports, err := NewSSHPortChecker(r).ListeningPorts(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []int{3306}, ports)
+}
+
+func TestListeningPorts_IgnoresErrors(t *testing.T) {
+	c := NewMockPortChecker()
+	c.ListeningPortsErr = errors.New("permission denied")
+
+	require.Nil(t, listeningPorts(context.Background(), c))
+	require.Equal(t, 1, c.ListeningPortsCallCount)
+}
+
+func TestListeningPorts(t *testing.T) {
+	c := NewMockPortChecker()
+	c.ListeningPortsVal = []int{22, 443}
+
+	require.Equal(t, []int{22, 443}, listeningPorts(context.Background(), c))
+}
+
+func TestUniquePorts(t *testing.T) {
+	require.Equal(t, []int{22, 80}, uniquePorts([]int{80, 22, 80, 0}))
+}
@@ -14,35 +14,33 @@ import (
 )
 
 type PSUtilDiscoverer struct {
-	processFilterer ProcessFilterer
+	processFilterer    ProcessFilterer
+	portChecker        PortChecker
+	containerDetector  ContainerRuntimeDetector
+	filesystemReporter FilesystemReporter
+	packageDetector    PackageManagerDetector
+	containerEnv       ContainerEnvironmentDetector
 }
 
 func NewPSUtilDiscoverer(f ProcessFilterer) *PSUtilDiscoverer {
 	d := PSUtilDiscoverer{
-		processFilterer: f,
+		processFilterer:    f,
+		portChecker:        NewPSUtilPortChecker(),
+		containerDetector:  NewLocalContainerRuntimeDetector(),
+		filesystemReporter: NewPSUtilFilesystemReporter(),
+		packageDetector:    NewLocalPackageManagerDetector(),
+		containerEnv:       NewLocalContainerEnvironmentDetector(),
 	}
 
 	return &d
 }
 
 func (p *PSUtilDiscoverer) Discover(ctx context.Context) (*types.DiscoveryManifest, error) {
-	i, err := host.InfoWithContext(ctx)
+	m, err := localHostManifest(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	m := types.DiscoveryManifest{
-		Hostname:        i.Hostname,
-		KernelArch:      i.KernelArch,
-		KernelVersion:   i.KernelVersion,
-		OS:              i.OS,
-		Platform:        i.Platform,
-		PlatformFamily:  i.PlatformFamily,
-		PlatformVersion: i.PlatformVersion,
-	}
-
-	m = filterValues(m)
-
 	pids, err := process.PidsWithContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("cannot retrieve processes: %s", err)
@@ -71,9 +69,118 @@ func (p *PSUtilDiscoverer) Discover(ctx context.Context) (*types.DiscoveryManife
 		m.AddMatchedProcess(p)
 	}
 
+	m.ListeningPorts = listeningPorts(ctx, p.portChecker)
+	m.ContainerRuntimes = containerRuntimes(ctx, p.containerDetector)
+	m.Filesystems = filesystems(ctx, p.filesystemReporter)
+	m.PackageManagers = packageManagers(ctx, p.packageDetector)
+	m.PackageManager = primaryPackageManager(m.PackageManagers, m.PlatformFamily)
+	m.Container = containerEnvironment(ctx, p.containerEnv)
+	m.MonitoringAgents = monitoringAgents(processes)
+
 	return &m, nil
 }
 
+// localHostManifest returns a manifest holding only the host information of
+// the local host.
+func localHostManifest(ctx context.Context) (types.DiscoveryManifest, error) {
+	i, err := host.InfoWithContext(ctx)
+	if err != nil {
+		return types.DiscoveryManifest{}, err
+	}
+
+	m := types.DiscoveryManifest{
+		Hostname:        i.Hostname,
+		KernelArch:      i.KernelArch,
+		KernelVersion:   i.KernelVersion,
+		OS:              i.OS,
+		Platform:        i.Platform,
+		PlatformFamily:  i.PlatformFamily,
+		PlatformVersion: i.PlatformVersion,
+	}
+
+	return filterValues(m), nil
+}
+
+// listeningPorts returns the ports the host is listening on.  Port conflicts
+// are only checked when the ports can be listed, so a failure is not fatal.
+func listeningPorts(ctx context.Context, c PortChecker) []int {
+	if c == nil {
+		return nil
+	}
+
+	ports, err := c.ListeningPorts(ctx)
+	if err != nil {
+		log.Debugf("cannot retrieve listening ports: %s", err)
+		return nil
+	}
+
+	return ports
+}
+
+// containerRuntimes returns the container runtimes running on the host.  A
+// failure to detect them is not fatal.
+func containerRuntimes(ctx context.Context, d ContainerRuntimeDetector) []types.ContainerRuntime {
+	if d == nil {
+		return nil
+	}
+
+	runtimes, err := d.ContainerRuntimes(ctx)
+	if err != nil {
+		log.Debugf("cannot detect container runtimes: %s", err)
+		return nil
+	}
+
+	return runtimes
+}
+
+// packageManagers returns the package managers available on the host.  A
+// failure to detect them is not fatal.
+func packageManagers(ctx context.Context, d PackageManagerDetector) []string {
+	if d == nil {
+		return nil
+	}
+
+	managers, err := d.PackageManagers(ctx)
+	if err != nil {
+		log.Debugf("cannot detect package managers: %s", err)
+		return nil
+	}
+
+	return managers
+}
+
+// containerEnvironment returns the kind of container the installer runs in,
+// if any.  A failure to detect it is not fatal, and the host is assumed bare.
+func containerEnvironment(ctx context.Context, d ContainerEnvironmentDetector) string {
+	if d == nil {
+		return ""
+	}
+
+	container, err := d.ContainerEnvironment(ctx)
+	if err != nil {
+		log.Debugf("cannot detect a container environment: %s", err)
+		return ""
+	}
+
+	return container
+}
+
+// filesystems returns the space available on the host's filesystems.  Disk
+// space is only checked when it can be reported, so a failure is not fatal.
+func filesystems(ctx context.Context, r FilesystemReporter) []types.Filesystem {
+	if r == nil {
+		return nil
+	}
+
+	fs, err := r.Filesystems(ctx)
+	if err != nil {
+		log.Debugf("cannot retrieve filesystem space: %s", err)
+		return nil
+	}
+
+	return fs
+}
+
 func filterValues(m types.DiscoveryManifest) types.DiscoveryManifest {
 	if !isValidOpenInstallationPlatform(m.Platform) {
 		m.Platform = ""
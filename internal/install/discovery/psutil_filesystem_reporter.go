@@ -0,0 +1,44 @@
+package discovery
+
+import (
+	"context"
+	"runtime"
+
+	"github.com/shirou/gopsutil/disk"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// PSUtilFilesystemReporter is an implementation of the FilesystemReporter
+// interface that reports the space available on the local host.
+type PSUtilFilesystemReporter struct {
+	paths []string
+}
+
+// NewPSUtilFilesystemReporter returns a new instance of
+// PSUtilFilesystemReporter.
+func NewPSUtilFilesystemReporter() *PSUtilFilesystemReporter {
+	return &PSUtilFilesystemReporter{
+		paths: filesystemPaths(runtime.GOOS),
+	}
+}
+
+func (r *PSUtilFilesystemReporter) Filesystems(ctx context.Context) ([]types.Filesystem, error) {
+	filesystems := []types.Filesystem{}
+
+	for _, path := range r.paths {
+		usage, err := disk.UsageWithContext(ctx, path)
+		if err != nil {
+			log.Debugf("cannot retrieve disk usage of %s: %s", path, err)
+			continue
+		}
+
+		filesystems = append(filesystems, types.Filesystem{
+			Path:   path,
+			FreeMB: usage.Free / bytesPerMB,
+		})
+	}
+
+	return filesystems, nil
+}
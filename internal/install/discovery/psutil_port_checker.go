@@ -0,0 +1,34 @@
+package discovery
+
+import (
+	"context"
+
+	"github.com/shirou/gopsutil/net"
+)
+
+const listenStatus = "LISTEN"
+
+// PSUtilPortChecker is an implementation of the PortChecker interface that
+// lists the listening sockets of the local host.
+type PSUtilPortChecker struct{}
+
+// NewPSUtilPortChecker returns a new instance of PSUtilPortChecker.
+func NewPSUtilPortChecker() *PSUtilPortChecker {
+	return &PSUtilPortChecker{}
+}
+
+func (c *PSUtilPortChecker) ListeningPorts(ctx context.Context) ([]int, error) {
+	conns, err := net.ConnectionsWithContext(ctx, "inet")
+	if err != nil {
+		return nil, err
+	}
+
+	ports := []int{}
+	for _, conn := range conns {
+		if conn.Status == listenStatus {
+			ports = append(ports, int(conn.Laddr.Port))
+		}
+	}
+
+	return uniquePorts(ports), nil
+}
@@ -0,0 +1,48 @@
+package discovery
+
+import (
+	"context"
+	"strings"
+
+	"github.com/newrelic/newrelic-cli/internal/install/remote"
+)
+
+// SSHContainerEnvironmentDetector is an implementation of the
+// ContainerEnvironmentDetector interface that looks for the signs of a
+// container on a remote host over SSH.
+type SSHContainerEnvironmentDetector struct {
+	runner remote.Runner
+}
+
+// NewSSHContainerEnvironmentDetector returns a new instance of
+// SSHContainerEnvironmentDetector.
+func NewSSHContainerEnvironmentDetector(r remote.Runner) *SSHContainerEnvironmentDetector {
+	return &SSHContainerEnvironmentDetector{
+		runner: r,
+	}
+}
+
+func (d *SSHContainerEnvironmentDetector) ContainerEnvironment(ctx context.Context) (string, error) {
+	out, err := d.runner.Output(ctx, remoteExistingPathsCmd(containerMarkerPaths()))
+	if err != nil {
+		return "", err
+	}
+
+	existing := map[string]bool{}
+	for _, line := range strings.Split(out, "\n") {
+		existing[strings.TrimSpace(line)] = true
+	}
+
+	cgroup, err := d.runner.Output(ctx, remoteCgroupCmd)
+	if err != nil {
+		return "", err
+	}
+
+	return detectContainer(func(path string) bool {
+		return existing[path]
+	}, cgroup), nil
+}
+
+// remoteCgroupCmd prints the control groups of the first process.  The
+// command succeeds on hosts that do not list them.
+const remoteCgroupCmd = "cat " + initCgroupPath + " 2>/dev/null; true"
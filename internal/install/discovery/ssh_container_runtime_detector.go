@@ -0,0 +1,47 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/newrelic/newrelic-cli/internal/install/remote"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// SSHContainerRuntimeDetector is an implementation of the
+// ContainerRuntimeDetector interface that looks for the sockets and log
+// directories of known container runtimes on a remote host over SSH.
+type SSHContainerRuntimeDetector struct {
+	runner remote.Runner
+}
+
+// NewSSHContainerRuntimeDetector returns a new instance of
+// SSHContainerRuntimeDetector.
+func NewSSHContainerRuntimeDetector(r remote.Runner) *SSHContainerRuntimeDetector {
+	return &SSHContainerRuntimeDetector{
+		runner: r,
+	}
+}
+
+func (d *SSHContainerRuntimeDetector) ContainerRuntimes(ctx context.Context) ([]types.ContainerRuntime, error) {
+	out, err := d.runner.Output(ctx, remoteExistingPathsCmd(containerRuntimePaths()))
+	if err != nil {
+		return nil, err
+	}
+
+	existing := map[string]bool{}
+	for _, line := range strings.Split(out, "\n") {
+		existing[strings.TrimSpace(line)] = true
+	}
+
+	return detectContainerRuntimes(func(path string) bool {
+		return existing[path]
+	}), nil
+}
+
+// remoteExistingPathsCmd returns a command printing those of the given paths
+// that exist.  The command succeeds even when none do.
+func remoteExistingPathsCmd(paths []string) string {
+	return fmt.Sprintf(`for p in %s; do [ -e "$p" ] && echo "$p"; done; true`, strings.Join(paths, " "))
+}
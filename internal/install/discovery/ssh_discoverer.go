@@ -0,0 +1,187 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/newrelic/newrelic-cli/internal/install/remote"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+const (
+	remoteHostInfoCmd  = "hostname; uname -s; uname -m; uname -r"
+	remoteOSReleaseCmd = "cat /etc/os-release"
+	remoteProcessesCmd = "ps -eo pid=,comm=,args="
+)
+
+// platformFamilies maps os-release IDs to the platform family reported for
+// the local host.
+var platformFamilies = map[string]string{
+	"ubuntu":        "debian",
+	"debian":        "debian",
+	"centos":        "rhel",
+	"rhel":          "rhel",
+	"amzn":          "rhel",
+	"sles":          "suse",
+	"opensuse-leap": "suse",
+}
+
+// platformNames maps os-release IDs whose platform is named differently.
+var platformNames = map[string]string{
+	"rhel": "redhat",
+	"amzn": "amazon",
+	"sles": "suse",
+}
+
+// SSHDiscoverer is an implementation of the Discoverer interface that
+// discovers information about a remote host by running commands over SSH.
+type SSHDiscoverer struct {
+	runner             remote.Runner
+	processFilterer    ProcessFilterer
+	portChecker        PortChecker
+	containerDetector  ContainerRuntimeDetector
+	filesystemReporter FilesystemReporter
+	packageDetector    PackageManagerDetector
+	containerEnv       ContainerEnvironmentDetector
+}
+
+// NewSSHDiscoverer returns a new instance of SSHDiscoverer.
+func NewSSHDiscoverer(r remote.Runner, f ProcessFilterer) *SSHDiscoverer {
+	d := SSHDiscoverer{
+		runner:             r,
+		processFilterer:    f,
+		portChecker:        NewSSHPortChecker(r),
+		containerDetector:  NewSSHContainerRuntimeDetector(r),
+		filesystemReporter: NewSSHFilesystemReporter(r),
+		packageDetector:    NewSSHPackageManagerDetector(r),
+		containerEnv:       NewSSHContainerEnvironmentDetector(r),
+	}
+
+	return &d
+}
+
+func (d *SSHDiscoverer) Discover(ctx context.Context) (*types.DiscoveryManifest, error) {
+	out, err := d.runner.Output(ctx, remoteHostInfoCmd)
+	if err != nil {
+		return nil, fmt.Errorf("cannot retrieve host info from %s: %s", d.runner.Host(), err)
+	}
+
+	info := strings.Split(strings.TrimSpace(out), "\n")
+	if len(info) < 4 {
+		return nil, fmt.Errorf("unexpected host info from %s: %q", d.runner.Host(), out)
+	}
+
+	m := types.DiscoveryManifest{
+		Hostname:      strings.TrimSpace(info[0]),
+		OS:            strings.ToLower(strings.TrimSpace(info[1])),
+		KernelArch:    strings.TrimSpace(info[2]),
+		KernelVersion: strings.TrimSpace(info[3]),
+	}
+
+	// Not all systems provide os-release, in which case the platform is left
+	// for the manifest validator to reject.
+	if osRelease, err := d.runner.Output(ctx, remoteOSReleaseCmd); err == nil {
+		release := parseOSRelease(osRelease)
+		id := release["ID"]
+
+		m.Platform = id
+		if name, ok := platformNames[id]; ok {
+			m.Platform = name
+		}
+		m.PlatformFamily = platformFamilies[id]
+		m.PlatformVersion = release["VERSION_ID"]
+	}
+
+	m = filterValues(m)
+
+	out, err = d.runner.Output(ctx, remoteProcessesCmd)
+	if err != nil {
+		return nil, fmt.Errorf("cannot retrieve processes from %s: %s", d.runner.Host(), err)
+	}
+
+	processes := parseRemoteProcesses(out)
+
+	matchedProcesses, err := d.processFilterer.filter(ctx, processes, m)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range matchedProcesses {
+		m.AddMatchedProcess(p)
+	}
+
+	m.ListeningPorts = listeningPorts(ctx, d.portChecker)
+	m.ContainerRuntimes = containerRuntimes(ctx, d.containerDetector)
+	m.Filesystems = filesystems(ctx, d.filesystemReporter)
+	m.PackageManagers = packageManagers(ctx, d.packageDetector)
+	m.PackageManager = primaryPackageManager(m.PackageManagers, m.PlatformFamily)
+	m.Container = containerEnvironment(ctx, d.containerEnv)
+	m.MonitoringAgents = monitoringAgents(processes)
+
+	return &m, nil
+}
+
+func parseOSRelease(s string) map[string]string {
+	release := map[string]string{}
+
+	for _, line := range strings.Split(s, "\n") {
+		parts := strings.SplitN(strings.TrimSpace(line), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		release[parts[0]] = strings.Trim(parts[1], `"'`)
+	}
+
+	return release
+}
+
+func parseRemoteProcesses(s string) []types.GenericProcess {
+	processes := []types.GenericProcess{}
+
+	for _, line := range strings.Split(s, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		pid, err := strconv.ParseInt(fields[0], 10, 32)
+		if err != nil {
+			continue
+		}
+
+		cmdline := strings.Join(fields[2:], " ")
+		if cmdline == "" {
+			cmdline = fields[1]
+		}
+
+		processes = append(processes, remoteProcess{
+			pid:     int32(pid),
+			name:    fields[1],
+			cmdline: cmdline,
+		})
+	}
+
+	return processes
+}
+
+// remoteProcess is a process listed on a remote host.
+type remoteProcess struct {
+	cmdline string
+	name    string
+	pid     int32
+}
+
+func (p remoteProcess) Name() (string, error) {
+	return p.name, nil
+}
+
+func (p remoteProcess) Cmdline() (string, error) {
+	return p.cmdline, nil
+}
+
+func (p remoteProcess) PID() int32 {
+	return p.pid
+}
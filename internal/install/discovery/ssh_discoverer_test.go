@@ -0,0 +1,114 @@
+//go:build unit
+// +build unit
+
+package discovery
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/remote"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+const testOSRelease = `NAME="Ubuntu"
+VERSION="20.04.2 LTS (Focal Fossa)"
+ID=ubuntu
+ID_LIKE=debian
+VERSION_ID="20.04"
+`
+
+func newTestRunner() *remote.MockRunner {
+	r := remote.NewMockRunner("db-1")
+	r.Responses[remoteHostInfoCmd] = remote.MockResponse{Output: "db-1\nLinux\nx86_64\n5.4.0-1045-aws\n"}
+	r.Responses[remoteOSReleaseCmd] = remote.MockResponse{Output: testOSRelease}
+	r.Responses[remoteProcessesCmd] = remote.MockResponse{Output: "    1 systemd /sbin/init\n  812 mysqld /usr/sbin/mysqld --daemonize\n"}
+
+	return r
+}
+
+func TestSSHDiscoverer_Discover(t *testing.T) {
+	d := NewSSHDiscoverer(newTestRunner(), NewNoOpProcessFilterer())
+
+	m, err := d.Discover(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "db-1", m.Hostname)
+	require.Equal(t, "linux", m.OS)
+	require.Equal(t, "x86_64", m.KernelArch)
+	require.Equal(t, "5.4.0-1045-aws", m.KernelVersion)
+	require.Equal(t, "ubuntu", m.Platform)
+	require.Equal(t, "debian", m.PlatformFamily)
+	require.Equal(t, "20.04", m.PlatformVersion)
+}
+
+func TestSSHDiscoverer_PlatformNames(t *testing.T) {
+	r := newTestRunner()
+	r.Responses[remoteOSReleaseCmd] = remote.MockResponse{Output: "ID=\"amzn\"\nVERSION_ID=\"2\"\n"}
+	d := NewSSHDiscoverer(r, NewNoOpProcessFilterer())
+
+	m, err := d.Discover(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "amazon", m.Platform)
+	require.Equal(t, "rhel", m.PlatformFamily)
+	require.Equal(t, "2", m.PlatformVersion)
+}
+
+func TestSSHDiscoverer_NoOSRelease(t *testing.T) {
+	r := newTestRunner()
+	r.Responses[remoteOSReleaseCmd] = remote.MockResponse{Err: errors.New("no such file")}
+	d := NewSSHDiscoverer(r, NewNoOpProcessFilterer())
+
+	m, err := d.Discover(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "", m.Platform)
+}
+
+func TestSSHDiscoverer_HostInfoError(t *testing.T) {
+	r := newTestRunner()
+	r.Responses[remoteHostInfoCmd] = remote.MockResponse{Err: errors.New("connection lost")}
+	d := NewSSHDiscoverer(r, NewNoOpProcessFilterer())
+
+	_, err := d.Discover(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "db-1")
+}
+
+func TestParseRemoteProcesses(t *testing.T) {
+	processes := parseRemoteProcesses("    1 systemd /sbin/init\n  812 mysqld /usr/sbin/mysqld --daemonize\n  900 kworker\nbogus line\n")
+	require.Equal(t, 3, len(processes))
+
+	name, _ := processes[1].Name()
+	cmdline, _ := processes[1].Cmdline()
+	require.Equal(t, int32(812), processes[1].PID())
+	require.Equal(t, "mysqld", name)
+	require.Equal(t, "/usr/sbin/mysqld --daemonize", cmdline)
+
+	cmdline, _ = processes[2].Cmdline()
+	require.Equal(t, "kworker", cmdline)
+}
+
+func TestSSHFileFilterer_Filter(t *testing.T) {
+	r := remote.NewMockRunner("db-1")
+	r.Responses["ls -1d -- /var/log/mysql/*.log"] = remote.MockResponse{Output: "/var/log/mysql/error.log\n"}
+	f := NewSSHFileFilterer(r)
+
+	recipes := []types.OpenInstallationRecipe{
+		{
+			Name: "mysql",
+			LogMatch: []types.OpenInstallationLogMatch{
+				{Name: "mysql", File: "/var/log/mysql/*.log"},
+				{Name: "missing", File: "/var/log/missing/*.log"},
+				{Name: "unsafe", File: "/var/log/$(reboot)"},
+			},
+		},
+	}
+
+	matches, err := f.Filter(context.Background(), recipes)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(matches))
+	require.Equal(t, "mysql", matches[0].Name)
+	require.Equal(t, 2, len(r.Commands))
+}
@@ -0,0 +1,57 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/remote"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// unsafePatternChars are shell metacharacters not allowed in log file patterns
+// expanded by the remote shell.
+const unsafePatternChars = ";&|`$()<>\\\"'\n"
+
+// SSHFileFilterer is an implementation of the FileFilterer interface that
+// checks for the existence of files on a remote host over SSH.
+type SSHFileFilterer struct {
+	runner remote.Runner
+}
+
+// NewSSHFileFilterer returns a new instance of SSHFileFilterer.
+func NewSSHFileFilterer(r remote.Runner) *SSHFileFilterer {
+	f := SSHFileFilterer{
+		runner: r,
+	}
+
+	return &f
+}
+
+// Filter returns the log matches of the given recipes whose patterns match
+// files on the remote host.
+func (f *SSHFileFilterer) Filter(ctx context.Context, recipes []types.OpenInstallationRecipe) ([]types.OpenInstallationLogMatch, error) {
+	fileMatches := []types.OpenInstallationLogMatch{}
+
+	for _, r := range recipes {
+		for _, l := range r.LogMatch {
+			if strings.ContainsAny(l.File, unsafePatternChars) {
+				log.Warnf("Skipping log file pattern %s which cannot be matched remotely.", l.File)
+				continue
+			}
+
+			out, err := f.runner.Output(ctx, fmt.Sprintf("ls -1d -- %s 2>/dev/null; true", l.File))
+			if err != nil {
+				return nil, fmt.Errorf("cannot match log files on %s: %s", f.runner.Host(), err)
+			}
+
+			if strings.TrimSpace(out) != "" {
+				fileMatches = append(fileMatches, l)
+			}
+		}
+	}
+
+	return fileMatches, nil
+}
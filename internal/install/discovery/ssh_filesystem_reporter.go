@@ -0,0 +1,63 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/newrelic/newrelic-cli/internal/install/remote"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// SSHFilesystemReporter is an implementation of the FilesystemReporter
+// interface that reports the space available on a remote host over SSH.
+type SSHFilesystemReporter struct {
+	runner remote.Runner
+}
+
+// NewSSHFilesystemReporter returns a new instance of SSHFilesystemReporter.
+func NewSSHFilesystemReporter(r remote.Runner) *SSHFilesystemReporter {
+	return &SSHFilesystemReporter{
+		runner: r,
+	}
+}
+
+func (r *SSHFilesystemReporter) Filesystems(ctx context.Context) ([]types.Filesystem, error) {
+	out, err := r.runner.Output(ctx, remoteFreeSpaceCmd(filesystemPaths("linux")))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFreeSpace(out), nil
+}
+
+// remoteFreeSpaceCmd returns a command printing each of the given paths that
+// exist along with the kilobytes available on its filesystem.  The command
+// succeeds even when none do.
+func remoteFreeSpaceCmd(paths []string) string {
+	return fmt.Sprintf(`for p in %s; do [ -e "$p" ] && echo "$p $(df -Pk "$p" | awk 'NR==2 {print $4}')"; done; true`, strings.Join(paths, " "))
+}
+
+func parseFreeSpace(out string) []types.Filesystem {
+	filesystems := []types.Filesystem{}
+
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		filesystems = append(filesystems, types.Filesystem{
+			Path:   fields[0],
+			FreeMB: kb / 1024,
+		})
+	}
+
+	return filesystems
+}
@@ -0,0 +1,39 @@
+package discovery
+
+import (
+	"context"
+	"strings"
+
+	"github.com/newrelic/newrelic-cli/internal/install/remote"
+)
+
+// SSHPackageManagerDetector is an implementation of the PackageManagerDetector
+// interface that looks for the executables of known package managers on a
+// remote host over SSH.
+type SSHPackageManagerDetector struct {
+	runner remote.Runner
+}
+
+// NewSSHPackageManagerDetector returns a new instance of
+// SSHPackageManagerDetector.
+func NewSSHPackageManagerDetector(r remote.Runner) *SSHPackageManagerDetector {
+	return &SSHPackageManagerDetector{
+		runner: r,
+	}
+}
+
+func (d *SSHPackageManagerDetector) PackageManagers(ctx context.Context) ([]string, error) {
+	out, err := d.runner.Output(ctx, remoteExistingPathsCmd(packageManagerPaths()))
+	if err != nil {
+		return nil, err
+	}
+
+	existing := map[string]bool{}
+	for _, line := range strings.Split(out, "\n") {
+		existing[strings.TrimSpace(line)] = true
+	}
+
+	return detectPackageManagers(func(path string) bool {
+		return existing[path]
+	}), nil
+}
@@ -0,0 +1,61 @@
+package discovery
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/newrelic/newrelic-cli/internal/install/remote"
+)
+
+const remoteListeningPortsCmd = "ss -Hltn"
+
+// SSHPortChecker is an implementation of the PortChecker interface that lists
+// the listening TCP sockets of a remote host over SSH.
+type SSHPortChecker struct {
+	runner remote.Runner
+}
+
+// NewSSHPortChecker returns a new instance of SSHPortChecker.
+func NewSSHPortChecker(r remote.Runner) *SSHPortChecker {
+	return &SSHPortChecker{
+		runner: r,
+	}
+}
+
+func (c *SSHPortChecker) ListeningPorts(ctx context.Context) ([]int, error) {
+	out, err := c.runner.Output(ctx, remoteListeningPortsCmd)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseListeningPorts(out), nil
+}
+
+// parseListeningPorts reads the ports from the local addresses listed by ss,
+// such as 0.0.0.0:22, [::]:443 or *:80.
+func parseListeningPorts(s string) []int {
+	ports := []int{}
+
+	for _, line := range strings.Split(s, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+
+		addr := fields[3]
+		n := strings.LastIndex(addr, ":")
+		if n < 0 {
+			continue
+		}
+
+		port, err := strconv.Atoi(addr[n+1:])
+		if err != nil {
+			continue
+		}
+
+		ports = append(ports, port)
+	}
+
+	return uniquePorts(ports)
+}
@@ -0,0 +1,144 @@
+package install
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// EntitlementChecker checks the entitlements of the account installed to.
+type EntitlementChecker interface {
+	HasEntitlement(ctx context.Context, name string) (bool, error)
+}
+
+// ServiceEntitlementChecker is an implementation of the EntitlementChecker
+// interface that looks the entitlements of the account up in NerdGraph.  The
+// entitlements are looked up once per run, on the first check.
+type ServiceEntitlementChecker struct {
+	client    recipes.NerdGraphClient
+	accountID int
+
+	mu           sync.Mutex
+	fetched      bool
+	entitlements map[string]bool
+	err          error
+}
+
+// NewServiceEntitlementChecker returns a new instance of
+// ServiceEntitlementChecker for the given account.
+func NewServiceEntitlementChecker(client recipes.NerdGraphClient, accountID int) *ServiceEntitlementChecker {
+	c := ServiceEntitlementChecker{
+		client:    client,
+		accountID: accountID,
+	}
+
+	return &c
+}
+
+func (c *ServiceEntitlementChecker) HasEntitlement(ctx context.Context, name string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.fetched {
+		c.entitlements, c.err = c.fetch(ctx)
+		c.fetched = true
+	}
+
+	if c.err != nil {
+		return false, c.err
+	}
+
+	return c.entitlements[name], nil
+}
+
+func (c *ServiceEntitlementChecker) fetch(ctx context.Context) (map[string]bool, error) {
+	var resp entitlementsQueryResult
+
+	query := `
+	query($accountId: Int!) {
+		actor {
+			account(id: $accountId) {
+				entitlements {
+					name
+				}
+			}
+		}
+	}`
+
+	vars := map[string]interface{}{
+		"accountId": c.accountID,
+	}
+
+	if err := c.client.QueryWithResponseAndContext(ctx, query, vars, &resp); err != nil {
+		return nil, err
+	}
+
+	entitlements := map[string]bool{}
+	for _, e := range resp.Actor.Account.Entitlements {
+		entitlements[e.Name] = true
+	}
+
+	log.WithFields(log.Fields{
+		"account_id": c.accountID,
+		"count":      len(entitlements),
+	}).Debug("fetched account entitlements")
+
+	return entitlements, nil
+}
+
+type entitlementsQueryResult struct {
+	Actor struct {
+		Account struct {
+			Entitlements []struct {
+				Name string `json:"name"`
+			} `json:"entitlements"`
+		} `json:"account"`
+	} `json:"actor"`
+}
+
+// unentitledReason returns why the recipe is unsupported for the account,
+// or an empty string when the account has the entitlement the recipe requires,
+// or the recipe requires none.  Recipes are offered when the entitlements
+// cannot be looked up, so that a failed lookup does not hide them.
+func (i *RecipeInstaller) unentitledReason(ctx context.Context, r types.OpenInstallationRecipe) string {
+	if r.RequiresEntitlement == "" || i.EntitlementChecker == nil {
+		return ""
+	}
+
+	ok, err := i.EntitlementChecker.HasEntitlement(ctx, r.RequiresEntitlement)
+	if err != nil {
+		log.Debugf("could not check the %s entitlement required by %s, offering it: %s", r.RequiresEntitlement, r.Name, err)
+		return ""
+	}
+
+	if !ok {
+		return fmt.Sprintf("the account lacks the %s entitlement", r.RequiresEntitlement)
+	}
+
+	return ""
+}
+
+// skipUnentitledRecipes reports the recipes requiring an entitlement the
+// account lacks as skipped with the reason, returning the others.
+func (i *RecipeInstaller) skipUnentitledRecipes(ctx context.Context, recipes []types.OpenInstallationRecipe) []types.OpenInstallationRecipe {
+	entitled := []types.OpenInstallationRecipe{}
+	for _, r := range recipes {
+		if reason := i.unentitledReason(ctx, r); reason != "" {
+			i.status.RecipeSkipped(execution.RecipeStatusEvent{
+				Recipe: r,
+				Msg:    "unsupported: " + reason,
+			})
+			continue
+		}
+
+		entitled = append(entitled, r)
+	}
+
+	return entitled
+}
@@ -0,0 +1,191 @@
+// +build unit
+
+package install
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/discovery"
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/validation"
+)
+
+type entitlementsNerdGraphClient struct {
+	resp       string
+	err        error
+	queryCount int
+}
+
+func (c *entitlementsNerdGraphClient) QueryWithResponseAndContext(ctx context.Context, query string, variables map[string]interface{}, respBody interface{}) error {
+	c.queryCount++
+
+	if c.err != nil {
+		return c.err
+	}
+
+	return json.Unmarshal([]byte(c.resp), respBody)
+}
+
+func TestServiceEntitlementChecker_CachesLookup(t *testing.T) {
+	client := &entitlementsNerdGraphClient{
+		resp: `{"actor": {"account": {"entitlements": [{"name": "hipaa"}, {"name": "fedramp"}]}}}`,
+	}
+	c := NewServiceEntitlementChecker(client, 12345)
+
+	ok, err := c.HasEntitlement(context.Background(), "hipaa")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = c.HasEntitlement(context.Background(), "synthetics")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.Equal(t, 1, client.queryCount)
+}
+
+func TestServiceEntitlementChecker_CachesError(t *testing.T) {
+	client := &entitlementsNerdGraphClient{err: errors.New("boom")}
+	c := NewServiceEntitlementChecker(client, 12345)
+
+	_, err := c.HasEntitlement(context.Background(), "hipaa")
+	require.Error(t, err)
+
+	_, err = c.HasEntitlement(context.Background(), "fedramp")
+	require.Error(t, err)
+
+	require.Equal(t, 1, client.queryCount)
+}
+
+func TestInstall_RecipeSkipped_Unentitled(t *testing.T) {
+	checker := NewMockEntitlementChecker()
+	checker.Entitlements = []string{"hipaa"}
+	ic := InstallerContext{
+		AssumeYes:          true,
+		EntitlementChecker: checker,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{
+		{
+			Name:                "mysql",
+			ValidationNRQL:      "testNrql",
+			RequiresEntitlement: "hipaa",
+		},
+		{
+			Name:                "nginx",
+			ValidationNRQL:      "testNrql",
+			RequiresEntitlement: "fedramp",
+		},
+	}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+		{
+			Name:           types.LoggingRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+	v = validation.NewMockRecipeValidator()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+
+	reporter := statusReporters[0].(*execution.MockStatusReporter)
+	require.Equal(t, 1, reporter.ReportSkipped["nginx"])
+	require.Equal(t, 0, reporter.ReportInstalled["nginx"])
+	require.Equal(t, 1, reporter.ReportInstalled["mysql"])
+
+	for _, s := range status.Statuses {
+		if s.Name == "nginx" {
+			require.Equal(t, "unsupported: the account lacks the fedramp entitlement", s.SkipReason)
+		}
+	}
+}
+
+func TestInstall_TargetedRecipeSkipped_Unentitled(t *testing.T) {
+	ic := InstallerContext{
+		RecipeNames:        []string{testRecipeName},
+		SkipInfra:          true,
+		EntitlementChecker: NewMockEntitlementChecker(),
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:                testRecipeName,
+			ValidationNRQL:      "testNrql",
+			RequiresEntitlement: "hipaa",
+		},
+	}
+	e := execution.NewMockRecipeExecutor()
+	v = validation.NewMockRecipeValidator()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+
+	reporter := statusReporters[0].(*execution.MockStatusReporter)
+	require.Equal(t, 1, reporter.ReportSkipped[testRecipeName])
+	require.Equal(t, 0, reporter.ReportInstalled[testRecipeName])
+	require.Equal(t, 0, e.ExecuteCallCount)
+}
+
+func TestUnentitledReason_LookupErrorOffersRecipe(t *testing.T) {
+	checker := NewMockEntitlementChecker()
+	checker.Err = errors.New("boom")
+	i := RecipeInstaller{}
+	i.EntitlementChecker = checker
+
+	reason := i.unentitledReason(context.Background(), types.OpenInstallationRecipe{Name: "mysql", RequiresEntitlement: "hipaa"})
+	require.Empty(t, reason)
+}
+
+func TestUnentitledReason_NoRequirement(t *testing.T) {
+	checker := NewMockEntitlementChecker()
+	i := RecipeInstaller{}
+	i.EntitlementChecker = checker
+
+	reason := i.unentitledReason(context.Background(), types.OpenInstallationRecipe{Name: "mysql"})
+	require.Empty(t, reason)
+	require.Equal(t, 0, checker.CheckCallCount)
+}
+
+func TestListRecipes_Unentitled(t *testing.T) {
+	md := discovery.NewMockDiscovererWithManifest(types.DiscoveryManifest{OS: "linux"})
+	rf := recipes.NewMockRecipeFetcher()
+	rf.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{Name: types.InfraAgentRecipeName},
+		{Name: types.LoggingRecipeName},
+	}
+	rf.FetchRecommendationsVal = []types.OpenInstallationRecipe{
+		{Name: "mysql", RequiresEntitlement: "hipaa"},
+	}
+
+	i := RecipeInstaller{
+		discoverer:        md,
+		manifestValidator: discovery.NewEmptyManifestValidator(),
+		recipeFetcher:     rf,
+	}
+	i.EntitlementChecker = NewMockEntitlementChecker()
+
+	listed, err := i.listRecipes(context.Background())
+	require.NoError(t, err)
+	require.Contains(t, listed, ListedRecipe{
+		Name:        "mysql",
+		DisplayName: "mysql",
+		Status:      listedRecipeUnsupported,
+		Reason:      "the account lacks the hipaa entitlement",
+	})
+}
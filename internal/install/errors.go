@@ -0,0 +1,404 @@
+package install
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+const (
+	apiKeyDocsURL             = "https://docs.newrelic.com/docs/apis/intro-apis/new-relic-api-keys/#user-api-key"
+	supportedPlatformsDocsURL = "https://docs.newrelic.com/docs/infrastructure/install-infrastructure-agent/get-started/requirements-infrastructure-agent/"
+)
+
+// ErrAuthentication represents a failure caused by an invalid API key or by an
+// API key that lacks the permissions required to install.
+type ErrAuthentication struct {
+	innerErr  error
+	forbidden bool
+}
+
+func NewErrAuthentication(err error, forbidden bool) ErrAuthentication {
+	return ErrAuthentication{
+		innerErr:  err,
+		forbidden: forbidden,
+	}
+}
+
+func (e ErrAuthentication) Error() string {
+	return e.innerErr.Error()
+}
+
+func (e ErrAuthentication) Unwrap() error {
+	return e.innerErr
+}
+
+// Forbidden indicates the API key is valid but lacks the required permissions.
+func (e ErrAuthentication) Forbidden() bool {
+	return e.forbidden
+}
+
+// Message returns an actionable description of the failure for the user.
+func (e ErrAuthentication) Message() string {
+	if e.forbidden {
+		return "Your API key lacks the permissions required to install New Relic. Please use a User API key belonging to a user with access to the configured account, and update it with the `newrelic profile` command. For more details visit " + apiKeyDocsURL
+	}
+
+	return "Your API key is invalid. Please set a valid User API key with the `newrelic profile` command. For more details visit " + apiKeyDocsURL
+}
+
+// ErrMaxRecipeFailures represents an install aborted because more recipes
+// failed than allowed by --max-recipe-failures.
+type ErrMaxRecipeFailures struct {
+	Failed int
+	Max    int
+}
+
+func NewErrMaxRecipeFailures(failed int, max int) ErrMaxRecipeFailures {
+	return ErrMaxRecipeFailures{
+		Failed: failed,
+		Max:    max,
+	}
+}
+
+func (e ErrMaxRecipeFailures) Error() string {
+	return fmt.Sprintf("%d recipes failed, exceeding the maximum of %d allowed by --max-recipe-failures", e.Failed, e.Max)
+}
+
+// ErrInstallTimeout represents an install stopped because it exceeded the time
+// allowed by --install-timeout.
+type ErrInstallTimeout struct {
+	Timeout time.Duration
+}
+
+func NewErrInstallTimeout(timeout time.Duration) ErrInstallTimeout {
+	return ErrInstallTimeout{
+		Timeout: timeout,
+	}
+}
+
+func (e ErrInstallTimeout) Error() string {
+	return fmt.Sprintf("the install exceeded the --install-timeout of %s, the remaining recipes were skipped", e.Timeout)
+}
+
+// ErrInstallHook represents an install stopped because one of its hooks
+// failed while HookErrorsFatal is set.
+type ErrInstallHook struct {
+	Hook string
+	Err  error
+}
+
+func NewErrInstallHook(hook string, err error) ErrInstallHook {
+	return ErrInstallHook{
+		Hook: hook,
+		Err:  err,
+	}
+}
+
+func (e ErrInstallHook) Error() string {
+	return fmt.Sprintf("the %s hook failed: %s", e.Hook, e.Err)
+}
+
+func (e ErrInstallHook) Unwrap() error {
+	return e.Err
+}
+
+// ErrPortsInUse represents a recipe that was not installed because ports it
+// requires are already in use on the host.
+type ErrPortsInUse struct {
+	RecipeName string
+	Ports      []int
+}
+
+func NewErrPortsInUse(recipeName string, ports []int) ErrPortsInUse {
+	return ErrPortsInUse{
+		RecipeName: recipeName,
+		Ports:      ports,
+	}
+}
+
+func (e ErrPortsInUse) Error() string {
+	ports := make([]string, len(e.Ports))
+	for n, p := range e.Ports {
+		ports[n] = strconv.Itoa(p)
+	}
+
+	return fmt.Sprintf("%s requires ports already in use on this host: %s. Stop the services using them, or rerun with --ignore-port-conflicts to install anyway", e.RecipeName, strings.Join(ports, ", "))
+}
+
+// ErrInsufficientDiskSpace represents a recipe that was not installed because
+// a filesystem it installs to has less free space than it requires.
+type ErrInsufficientDiskSpace struct {
+	RecipeName  string
+	Path        string
+	RequiredMB  int
+	AvailableMB uint64
+}
+
+func NewErrInsufficientDiskSpace(recipeName string, path string, requiredMB int, availableMB uint64) ErrInsufficientDiskSpace {
+	return ErrInsufficientDiskSpace{
+		RecipeName:  recipeName,
+		Path:        path,
+		RequiredMB:  requiredMB,
+		AvailableMB: availableMB,
+	}
+}
+
+func (e ErrInsufficientDiskSpace) Error() string {
+	return fmt.Sprintf("%s requires %d MB of free disk space, but only %d MB is available on the filesystem holding %s. Free up disk space and try again", e.RecipeName, e.RequiredMB, e.AvailableMB, e.Path)
+}
+
+// ErrRemoteHostsFailed represents a multi-host install in which the install
+// failed on some of the hosts.  Errors are keyed by host.
+type ErrRemoteHostsFailed struct {
+	Errors map[string]error
+	Total  int
+}
+
+func NewErrRemoteHostsFailed(errs map[string]error, total int) ErrRemoteHostsFailed {
+	return ErrRemoteHostsFailed{
+		Errors: errs,
+		Total:  total,
+	}
+}
+
+func (e ErrRemoteHostsFailed) Error() string {
+	return fmt.Sprintf("installation failed on %d of %d hosts", len(e.Errors), e.Total)
+}
+
+// ErrEndpointUnreachable represents a New Relic endpoint the host could not
+// reach.  Kind classifies the failure, such as DNS or TLS.
+type ErrEndpointUnreachable struct {
+	Name string
+	URL  string
+	Kind string
+	Err  error
+}
+
+func (e ErrEndpointUnreachable) Error() string {
+	return fmt.Sprintf("%s (%s): %s failure: %s", e.Name, e.URL, e.Kind, e.Err)
+}
+
+func (e ErrEndpointUnreachable) Unwrap() error {
+	return e.Err
+}
+
+// ErrEndpointsUnreachable represents a failed network preflight, listing the
+// endpoints that could not be reached.
+type ErrEndpointsUnreachable struct {
+	Endpoints []ErrEndpointUnreachable
+}
+
+func NewErrEndpointsUnreachable(endpoints []ErrEndpointUnreachable) ErrEndpointsUnreachable {
+	return ErrEndpointsUnreachable{
+		Endpoints: endpoints,
+	}
+}
+
+func (e ErrEndpointsUnreachable) Error() string {
+	lines := []string{fmt.Sprintf("this host could not reach %d of the New Relic endpoints needed to install:", len(e.Endpoints))}
+	for _, u := range e.Endpoints {
+		lines = append(lines, "  "+u.Error())
+	}
+	lines = append(lines, "Check the network, DNS, firewall and proxy (HTTPS_PROXY) configuration of the host, or rerun with --skip-preflight to install anyway")
+
+	return strings.Join(lines, "\n")
+}
+
+// ErrInContainer represents a refusal to install the infrastructure agent
+// inside a container, where it would monitor the container rather than its
+// host.
+type ErrInContainer struct {
+	Container string
+}
+
+func NewErrInContainer(container string) ErrInContainer {
+	return ErrInContainer{
+		Container: container,
+	}
+}
+
+func (e ErrInContainer) Error() string {
+	return fmt.Sprintf("the installer is running inside a %s container, where the infrastructure agent would monitor the container rather than its host; %s, or rerun with --assume-host to install here anyway", e.Container, containerizedAgentAdvice(e.Container))
+}
+
+// ErrUnsupportedPlatform represents a host whose platform the infrastructure
+// agent is not available for, so that New Relic cannot be installed on it.
+type ErrUnsupportedPlatform struct {
+	OS              string
+	Platform        string
+	PlatformVersion string
+	KernelArch      string
+	Err             error
+}
+
+func NewErrUnsupportedPlatform(m types.DiscoveryManifest, err error) ErrUnsupportedPlatform {
+	return ErrUnsupportedPlatform{
+		OS:              m.OS,
+		Platform:        m.Platform,
+		PlatformVersion: m.PlatformVersion,
+		KernelArch:      m.KernelArch,
+		Err:             err,
+	}
+}
+
+func (e ErrUnsupportedPlatform) Error() string {
+	return fmt.Sprintf("this platform is not supported: no infrastructure agent is available for %s. For the supported platforms, see %s", e.platform(), supportedPlatformsDocsURL)
+}
+
+func (e ErrUnsupportedPlatform) Unwrap() error {
+	return e.Err
+}
+
+// platform describes the platform as os/arch, followed by the distribution
+// and its version when they were discovered.
+func (e ErrUnsupportedPlatform) platform() string {
+	p := fmt.Sprintf("%s/%s", valueOrUnknown(e.OS), valueOrUnknown(e.KernelArch))
+
+	if d := strings.TrimSpace(e.Platform + " " + e.PlatformVersion); d != "" {
+		p += fmt.Sprintf(" (%s)", d)
+	}
+
+	return p
+}
+
+func valueOrUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+
+	return s
+}
+
+// ErrRecipeFetch represents a failure to retrieve a recipe, or the recipe
+// recommendations when RecipeName is empty, from the recipe source.
+type ErrRecipeFetch struct {
+	RecipeName string
+	Err        error
+}
+
+func NewErrRecipeFetch(recipeName string, err error) ErrRecipeFetch {
+	return ErrRecipeFetch{
+		RecipeName: recipeName,
+		Err:        err,
+	}
+}
+
+func (e ErrRecipeFetch) Error() string {
+	if e.RecipeName == "" {
+		return fmt.Sprintf("error retrieving recipe recommendations: %s", e.Err)
+	}
+
+	return fmt.Sprintf("error retrieving recipe %s: %s", e.RecipeName, e.Err)
+}
+
+func (e ErrRecipeFetch) Unwrap() error {
+	return e.Err
+}
+
+// ErrExecution represents a recipe whose steps failed to execute.  Underlying
+// is the error of the recipe executor, such as a non-zero exit status.
+type ErrExecution struct {
+	Recipe     string
+	Underlying error
+}
+
+func NewErrExecution(recipe string, err error) ErrExecution {
+	return ErrExecution{
+		Recipe:     recipe,
+		Underlying: err,
+	}
+}
+
+func (e ErrExecution) Error() string {
+	return fmt.Sprintf("encountered an error while executing %s: %s", e.Recipe, e.Underlying)
+}
+
+func (e ErrExecution) Unwrap() error {
+	return e.Underlying
+}
+
+// ErrValidationTimeout represents a recipe that executed, but whose data was
+// not found in New Relic before validation gave up.
+type ErrValidationTimeout struct {
+	Recipe string
+	Err    error
+}
+
+func NewErrValidationTimeout(recipe string, err error) ErrValidationTimeout {
+	return ErrValidationTimeout{
+		Recipe: recipe,
+		Err:    err,
+	}
+}
+
+func (e ErrValidationTimeout) Error() string {
+	return fmt.Sprintf("encountered an error while validating receipt of data for %s: %s", e.Recipe, e.Err)
+}
+
+func (e ErrValidationTimeout) Unwrap() error {
+	return e.Err
+}
+
+// ErrUserCanceled represents an install the user chose to stop, such as by
+// declining the install plan or aborting after a recipe failed.  It matches
+// types.ErrInterrupt with errors.Is, as any other cancellation does.
+type ErrUserCanceled struct {
+	Reason string
+}
+
+func NewErrUserCanceled(reason string) ErrUserCanceled {
+	return ErrUserCanceled{
+		Reason: reason,
+	}
+}
+
+func (e ErrUserCanceled) Error() string {
+	return fmt.Sprintf("%s: %s", types.ErrInterrupt, e.Reason)
+}
+
+func (e ErrUserCanceled) Is(target error) bool {
+	return target == types.ErrInterrupt
+}
+
+// isCanceled reports whether err is a cancellation of the install, by a signal,
+// a prompt or the user.
+func isCanceled(err error) bool {
+	return errors.Is(err, types.ErrInterrupt)
+}
+
+// classifyAuthError wraps err in an ErrAuthentication when it was caused by a
+// New Relic API rejecting an invalid or under-privileged API key, and returns
+// it unchanged otherwise.  Only the typed 401 and 403 responses of the New
+// Relic client are classified, so that a recipe failing on a permission of the
+// host, or on a package repository, is not mistaken for one.
+func classifyAuthError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var aerr ErrAuthentication
+	if errors.As(err, &aerr) {
+		return err
+	}
+
+	var eerr ErrExecution
+	if errors.As(err, &eerr) {
+		return err
+	}
+
+	switch recipes.HTTPStatusCode(err) {
+	case http.StatusUnauthorized:
+		return NewErrAuthentication(err, false)
+	case http.StatusForbidden:
+		return NewErrAuthentication(err, true)
+	}
+
+	return err
+}
@@ -0,0 +1,82 @@
+// +build unit
+
+package install
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
+)
+
+func TestClassifyAuthError_Nil(t *testing.T) {
+	require.NoError(t, classifyAuthError(nil))
+}
+
+func TestClassifyAuthError_Unauthorized(t *testing.T) {
+	errs := []error{
+		nrErrors.NewUnauthorizedError(),
+		fmt.Errorf("error retrieving recipe recommendations: %w", nrErrors.NewUnauthorizedError()),
+		NewErrRecipeFetch("", nrErrors.NewUnexpectedStatusCode(401, "")),
+	}
+
+	for _, err := range errs {
+		var aerr ErrAuthentication
+		require.True(t, errors.As(classifyAuthError(err), &aerr), err.Error())
+		require.False(t, aerr.Forbidden())
+		require.Contains(t, aerr.Message(), "is invalid")
+	}
+}
+
+func TestClassifyAuthError_Forbidden(t *testing.T) {
+	errs := []error{
+		nrErrors.NewUnexpectedStatusCode(403, ""),
+		NewErrRecipeFetch("mysql", nrErrors.NewUnexpectedStatusCode(403, "Access denied.")),
+	}
+
+	for _, err := range errs {
+		var aerr ErrAuthentication
+		require.True(t, errors.As(classifyAuthError(err), &aerr), err.Error())
+		require.True(t, aerr.Forbidden())
+		require.Contains(t, aerr.Message(), "lacks the permissions")
+	}
+}
+
+func TestClassifyAuthError_Other(t *testing.T) {
+	errs := []error{
+		errors.New("recipe not found"),
+		errors.New("Invalid API key"),
+		errors.New("E: Could not open lock file /var/lib/dpkg/lock-frontend - open (13: Permission denied)"),
+		NewErrExecution("mysql", errors.New("403 Forbidden from https://download.example.com/repo")),
+		NewErrExecution("mysql", nrErrors.NewUnexpectedStatusCode(403, "")),
+		nrErrors.NewUnexpectedStatusCode(500, ""),
+	}
+
+	for _, err := range errs {
+		require.Equal(t, err, classifyAuthError(err), err.Error())
+	}
+}
+
+func TestClassifyAuthError_PreservesInnerError(t *testing.T) {
+	inner := nrErrors.NewUnauthorizedError()
+	err := classifyAuthError(inner)
+
+	var uerr *nrErrors.UnauthorizedError
+	require.True(t, errors.As(err, &uerr))
+	require.Equal(t, inner.Error(), err.Error())
+}
+
+func TestErrUnsupportedPlatform(t *testing.T) {
+	err := NewErrUnsupportedPlatform(types.DiscoveryManifest{OS: "linux", KernelArch: "ppc64le", Platform: "rhel", PlatformVersion: "8"}, recipes.ErrRecipeNotFound)
+
+	require.Contains(t, err.Error(), "linux/ppc64le (rhel 8)")
+	require.True(t, errors.Is(err, recipes.ErrRecipeNotFound))
+
+	err = NewErrUnsupportedPlatform(types.DiscoveryManifest{OS: "plan9"}, recipes.ErrRecipeNotFound)
+	require.Contains(t, err.Error(), "plan9/unknown.")
+}
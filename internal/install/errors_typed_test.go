@@ -0,0 +1,118 @@
+// +build unit
+
+package install
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/validation"
+	utilsValidation "github.com/newrelic/newrelic-cli/internal/utils/validation"
+)
+
+func TestErrRecipeFetch(t *testing.T) {
+	inner := errors.New("service unavailable")
+	err := classifyAuthError(NewErrRecipeFetch("mysql", inner))
+
+	var ferr ErrRecipeFetch
+	require.True(t, errors.As(err, &ferr))
+	require.Equal(t, "mysql", ferr.RecipeName)
+	require.ErrorIs(t, err, inner)
+	require.Equal(t, "error retrieving recipe mysql: service unavailable", err.Error())
+
+	require.Equal(t, "error retrieving recipe recommendations: service unavailable", NewErrRecipeFetch("", inner).Error())
+}
+
+func TestErrUserCanceled(t *testing.T) {
+	err := NewErrUserCanceled("the installation plan was declined")
+
+	var cerr ErrUserCanceled
+	require.True(t, errors.As(err, &cerr))
+	require.ErrorIs(t, err, types.ErrInterrupt)
+	require.True(t, isCanceled(err))
+	require.False(t, errors.Is(types.ErrInterrupt, err))
+}
+
+func TestInstall_TypedErrors_RecipeFetch(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+	}
+	status = execution.NewInstallStatus([]execution.StatusSubscriber{}, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeErr = errors.New("service unavailable")
+	v = validation.NewMockRecipeValidator()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+
+	var ferr ErrRecipeFetch
+	require.True(t, errors.As(err, &ferr))
+	require.Equal(t, types.InfraAgentRecipeName, ferr.RecipeName)
+}
+
+func TestInstall_TypedErrors_RecipeNotFound(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+	}
+	status = execution.NewInstallStatus([]execution.StatusSubscriber{}, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	v = validation.NewMockRecipeValidator()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+
+	var ferr ErrRecipeFetch
+	require.True(t, errors.As(err, &ferr))
+	require.ErrorIs(t, err, recipes.ErrRecipeNotFound)
+}
+
+func TestInstall_TypedErrors_Execution(t *testing.T) {
+	ic := InstallerContext{
+		AssumeYes:   true,
+		RecipeNames: []string{testRecipeName},
+		SkipInfra:   true,
+	}
+	status = execution.NewInstallStatus([]execution.StatusSubscriber{}, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{{Name: testRecipeName, ValidationNRQL: "testNrql"}}
+	v = validation.NewMockRecipeValidator()
+
+	exitErr := errors.New("exit status 1")
+	ex := execution.NewMockRecipeExecutor()
+	ex.ExecuteErrs = []error{exitErr}
+
+	i := RecipeInstaller{ic, d, l, mv, f, ex, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+
+	var xerr ErrExecution
+	require.True(t, errors.As(err, &xerr))
+	require.Equal(t, testRecipeName, xerr.Recipe)
+	require.Equal(t, exitErr, xerr.Underlying)
+	require.ErrorIs(t, err, exitErr)
+}
+
+func TestInstall_TypedErrors_ValidationTimeout(t *testing.T) {
+	ic := InstallerContext{
+		AssumeYes:   true,
+		RecipeNames: []string{testRecipeName},
+		SkipInfra:   true,
+	}
+	status = execution.NewInstallStatus([]execution.StatusSubscriber{}, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{{Name: testRecipeName, ValidationNRQL: "testNrql"}}
+	v = validation.NewMockRecipeValidator()
+	v.ValidateErrs = []error{utilsValidation.ErrMaxAttemptsReached}
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+
+	var verr ErrValidationTimeout
+	require.True(t, errors.As(err, &verr))
+	require.Equal(t, testRecipeName, verr.Recipe)
+	require.ErrorIs(t, err, utilsValidation.ErrMaxAttemptsReached)
+}
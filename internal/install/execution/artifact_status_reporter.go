@@ -0,0 +1,265 @@
+package execution
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+const (
+	artifactDirPerm  = 0700
+	artifactFilePerm = 0600
+
+	manifestArtifactFile = "manifest.json"
+	summaryArtifactFile  = "summary.json"
+	eventsArtifactFile   = "events.jsonl"
+	recipeLogsDir        = "recipes"
+
+	runDirPrefix = "install-"
+
+	// MaxRunDirectories is how many run directories are kept in an output
+	// directory, the oldest being removed as new runs are created.
+	MaxRunDirectories = 10
+)
+
+// CreateRunDirectory creates a timestamped directory under baseDir to hold the
+// artifacts of a single install run, and returns its path.  Directories are
+// created readable by the current user only.
+func CreateRunDirectory(baseDir string, now time.Time) (string, error) {
+	dir := filepath.Join(baseDir, runDirPrefix+now.Format("20060102-150405"))
+
+	if err := os.MkdirAll(filepath.Join(dir, recipeLogsDir), artifactDirPerm); err != nil {
+		return "", fmt.Errorf("could not create output directory %s: %s", dir, err)
+	}
+
+	return dir, nil
+}
+
+// PruneRunDirectories removes all but the newest keep run directories under
+// baseDir, along with their diagnostics bundles, returning the paths removed.
+func PruneRunDirectories(baseDir string, keep int) ([]string, error) {
+	entries, err := ioutil.ReadDir(baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Run directories are named by when they were created, so sort oldest
+	// first.
+	runDirs := []string{}
+	for _, e := range entries {
+		if e.IsDir() && strings.HasPrefix(e.Name(), runDirPrefix) {
+			runDirs = append(runDirs, e.Name())
+		}
+	}
+	sort.Strings(runDirs)
+
+	removed := []string{}
+	for len(runDirs) > keep {
+		dir := filepath.Join(baseDir, runDirs[0])
+		runDirs = runDirs[1:]
+
+		if err := os.RemoveAll(dir); err != nil {
+			return removed, fmt.Errorf("could not remove run directory %s: %s", dir, err)
+		}
+		removed = append(removed, dir)
+
+		if err := os.Remove(DiagnosticsBundlePath(dir)); err != nil && !os.IsNotExist(err) {
+			return removed, fmt.Errorf("could not remove diagnostics bundle of %s: %s", dir, err)
+		}
+	}
+
+	return removed, nil
+}
+
+// RecipeLogPath returns the path of the captured output of the given recipe
+// within a run directory.
+func RecipeLogPath(runDir string, recipeName string) string {
+	return filepath.Join(runDir, recipeLogsDir, recipeName+".log")
+}
+
+// RecipeAuditLogPath returns the path of the audit log of the commands run by
+// the given recipe within a run directory.
+func RecipeAuditLogPath(runDir string, recipeName string) string {
+	return filepath.Join(runDir, recipeLogsDir, recipeName+".audit.jsonl")
+}
+
+// ArtifactStatusReporter is an implementation of the StatusSubscriber interface
+// that records the install to a run directory: the discovery manifest, a JSON
+// event stream and a final summary report.
+type ArtifactStatusReporter struct {
+	dir string
+	mu  sync.Mutex
+}
+
+type artifactEvent struct {
+	Timestamp     int64  `json:"timestamp"`
+	Event         string `json:"event"`
+	Recipe        string `json:"recipe,omitempty"`
+	Step          string `json:"step,omitempty"`
+	Msg           string `json:"msg,omitempty"`
+	EntityGUID    string `json:"entityGuid,omitempty"`
+	Required      bool   `json:"required,omitempty"`
+	CorrelationID string `json:"correlationId,omitempty"`
+	CampaignID    string `json:"campaignId,omitempty"`
+}
+
+// NewArtifactStatusReporter returns a new instance of ArtifactStatusReporter
+// writing to the given run directory.
+func NewArtifactStatusReporter(dir string) *ArtifactStatusReporter {
+	r := ArtifactStatusReporter{
+		dir: dir,
+	}
+
+	return &r
+}
+
+func (r *ArtifactStatusReporter) DiscoveryComplete(status *InstallStatus, dm types.DiscoveryManifest) error {
+	if err := r.writeJSON(manifestArtifactFile, dm); err != nil {
+		return err
+	}
+
+	return r.appendEvent(status, artifactEvent{Event: "DISCOVERY_COMPLETE"})
+}
+
+func (r *ArtifactStatusReporter) RecipeAvailable(status *InstallStatus, recipe types.OpenInstallationRecipe) error {
+	return r.appendEvent(status, artifactEvent{Event: string(RecipeStatusTypes.AVAILABLE), Recipe: recipe.Name})
+}
+
+func (r *ArtifactStatusReporter) RecipesAvailable(status *InstallStatus, recipes []types.OpenInstallationRecipe) error {
+	for _, recipe := range recipes {
+		if err := r.RecipeAvailable(status, recipe); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *ArtifactStatusReporter) RecipesSelected(status *InstallStatus, recipes []types.OpenInstallationRecipe) error {
+	for _, recipe := range recipes {
+		if err := r.appendEvent(status, artifactEvent{Event: "SELECTED", Recipe: recipe.Name}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *ArtifactStatusReporter) RecipeFailed(status *InstallStatus, event RecipeStatusEvent) error {
+	return r.appendRecipeEvent(status, RecipeStatusTypes.FAILED, event)
+}
+
+func (r *ArtifactStatusReporter) RecipeInstalled(status *InstallStatus, event RecipeStatusEvent) error {
+	return r.appendRecipeEvent(status, RecipeStatusTypes.INSTALLED, event)
+}
+
+func (r *ArtifactStatusReporter) RecipeInstalling(status *InstallStatus, event RecipeStatusEvent) error {
+	return r.appendRecipeEvent(status, RecipeStatusTypes.INSTALLING, event)
+}
+
+func (r *ArtifactStatusReporter) RecipeRecommended(status *InstallStatus, event RecipeStatusEvent) error {
+	return r.appendRecipeEvent(status, RecipeStatusTypes.RECOMMENDED, event)
+}
+
+func (r *ArtifactStatusReporter) RecipeSkipped(status *InstallStatus, event RecipeStatusEvent) error {
+	return r.appendRecipeEvent(status, RecipeStatusTypes.SKIPPED, event)
+}
+
+func (r *ArtifactStatusReporter) RecipeStepSkipped(status *InstallStatus, event RecipeStepEvent) error {
+	return r.appendEvent(status, artifactEvent{
+		Event:  "STEP_SKIPPED",
+		Recipe: event.Recipe.Name,
+		Step:   event.Step,
+		Msg:    event.Msg,
+	})
+}
+
+func (r *ArtifactStatusReporter) RecipeRetrying(status *InstallStatus, event RecipeRetryEvent) error {
+	return r.appendEvent(status, artifactEvent{
+		Event:  "RETRYING",
+		Recipe: event.Recipe.Name,
+		Msg:    fmt.Sprintf("attempt %d of %d after exit code %d: %s", event.Attempt, event.MaxAttempts, event.ExitCode, event.Msg),
+	})
+}
+
+func (r *ArtifactStatusReporter) InstallComplete(status *InstallStatus) error {
+	if err := r.appendEvent(status, artifactEvent{Event: "INSTALL_COMPLETE", Msg: status.Error.Message}); err != nil {
+		return err
+	}
+
+	return r.writeSummary(status)
+}
+
+func (r *ArtifactStatusReporter) InstallCanceled(status *InstallStatus) error {
+	if err := r.appendEvent(status, artifactEvent{Event: "INSTALL_CANCELED"}); err != nil {
+		return err
+	}
+
+	return r.writeSummary(status)
+}
+
+func (r *ArtifactStatusReporter) writeSummary(status *InstallStatus) error {
+	if err := r.writeJSON(summaryArtifactFile, status); err != nil {
+		return err
+	}
+
+	log.Debugf("install artifacts written to %s", r.dir)
+
+	return nil
+}
+
+func (r *ArtifactStatusReporter) appendRecipeEvent(status *InstallStatus, rs RecipeStatusType, event RecipeStatusEvent) error {
+	return r.appendEvent(status, artifactEvent{
+		Event:      string(rs),
+		Recipe:     event.Recipe.Name,
+		Msg:        event.Msg,
+		EntityGUID: event.EntityGUID,
+		Required:   event.Recipe.IsRequired(),
+	})
+}
+
+func (r *ArtifactStatusReporter) appendEvent(status *InstallStatus, e artifactEvent) error {
+	e.Timestamp = time.Now().UnixNano() / int64(time.Millisecond)
+	e.CorrelationID = status.CorrelationID
+	e.CampaignID = status.CampaignID
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f, err := os.OpenFile(filepath.Join(r.dir, eventsArtifactFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, artifactFilePerm)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+func (r *ArtifactStatusReporter) writeJSON(name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return utils.WriteFileAtomic(filepath.Join(r.dir, name), data, artifactFilePerm)
+}
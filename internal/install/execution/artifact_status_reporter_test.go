@@ -0,0 +1,136 @@
+//go:build unit
+// +build unit
+
+package execution
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestArtifactStatusReporter_interface(t *testing.T) {
+	var r StatusSubscriber = NewArtifactStatusReporter("")
+	require.NotNil(t, r)
+}
+
+func TestCreateRunDirectory(t *testing.T) {
+	base, err := ioutil.TempDir("", "artifacts")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+
+	dir, err := CreateRunDirectory(base, time.Date(2021, 6, 1, 13, 4, 5, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(base, "install-20210601-130405"), dir)
+
+	info, err := os.Stat(dir)
+	require.NoError(t, err)
+	require.True(t, info.IsDir())
+	require.Equal(t, os.FileMode(0700), info.Mode().Perm())
+
+	_, err = os.Stat(filepath.Join(dir, "recipes"))
+	require.NoError(t, err)
+}
+
+func TestPruneRunDirectories(t *testing.T) {
+	base, err := ioutil.TempDir("", "artifacts")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+
+	start := time.Date(2021, 6, 1, 13, 4, 5, 0, time.UTC)
+	dirs := []string{}
+	for n := 0; n < 4; n++ {
+		dir, err := CreateRunDirectory(base, start.Add(time.Duration(n)*time.Minute))
+		require.NoError(t, err)
+		dirs = append(dirs, dir)
+	}
+	require.NoError(t, ioutil.WriteFile(DiagnosticsBundlePath(dirs[0]), []byte("zip"), 0600))
+	require.NoError(t, os.Mkdir(filepath.Join(base, "other"), 0700))
+
+	removed, err := PruneRunDirectories(base, 2)
+	require.NoError(t, err)
+	require.Equal(t, dirs[:2], removed)
+
+	for _, dir := range dirs[:2] {
+		_, err = os.Stat(dir)
+		require.True(t, os.IsNotExist(err))
+	}
+	for _, dir := range dirs[2:] {
+		_, err = os.Stat(dir)
+		require.NoError(t, err)
+	}
+
+	_, err = os.Stat(DiagnosticsBundlePath(dirs[0]))
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(base, "other"))
+	require.NoError(t, err)
+}
+
+func TestArtifactStatusReporter_WritesArtifacts(t *testing.T) {
+	base, err := ioutil.TempDir("", "artifacts")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+
+	dir, err := CreateRunDirectory(base, time.Now())
+	require.NoError(t, err)
+
+	r := NewArtifactStatusReporter(dir)
+	status := NewInstallStatus([]StatusSubscriber{r}, NewMockSuccessLinkGenerator())
+	status.CampaignID = "rollout-1"
+
+	status.DiscoveryComplete(types.DiscoveryManifest{Hostname: "testHost"})
+	status.RecipeInstalled(RecipeStatusEvent{Recipe: types.OpenInstallationRecipe{Name: "infra"}, EntityGUID: "abc"})
+	status.RecipeFailed(RecipeStatusEvent{Recipe: types.OpenInstallationRecipe{Name: "logs"}, Msg: "boom"})
+	status.InstallComplete(nil)
+
+	var m types.DiscoveryManifest
+	data, err := ioutil.ReadFile(filepath.Join(dir, "manifest.json"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &m))
+	require.Equal(t, "testHost", m.Hostname)
+
+	var summary InstallStatus
+	data, err = ioutil.ReadFile(filepath.Join(dir, "summary.json"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &summary))
+	require.True(t, summary.Complete)
+	require.Equal(t, 2, len(summary.Statuses))
+	require.Equal(t, status.CorrelationID, summary.CorrelationID)
+	require.Equal(t, "rollout-1", summary.CampaignID)
+
+	info, err := os.Stat(filepath.Join(dir, "summary.json"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	f, err := os.Open(filepath.Join(dir, "events.jsonl"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	events := []artifactEvent{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e artifactEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		events = append(events, e)
+	}
+
+	require.Equal(t, 4, len(events))
+	require.Equal(t, "DISCOVERY_COMPLETE", events[0].Event)
+	require.Equal(t, "infra", events[1].Recipe)
+	require.Equal(t, "abc", events[1].EntityGUID)
+	require.Equal(t, string(RecipeStatusTypes.FAILED), events[2].Event)
+	require.Equal(t, "boom", events[2].Msg)
+	require.Equal(t, "INSTALL_COMPLETE", events[3].Event)
+	for _, e := range events {
+		require.Equal(t, status.CorrelationID, e.CorrelationID)
+		require.Equal(t, "rollout-1", e.CampaignID)
+	}
+}
@@ -0,0 +1,84 @@
+package execution
+
+import (
+	"os"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// CleanupRegistry tracks the temporary files created during an install, so
+// that those left behind when the install is canceled or fails part way can
+// be removed.  A nil registry removes files on release but tracks nothing.
+type CleanupRegistry struct {
+	mu    sync.Mutex
+	paths map[string]bool
+}
+
+// NewCleanupRegistry returns a new instance of CleanupRegistry.
+func NewCleanupRegistry() *CleanupRegistry {
+	return &CleanupRegistry{
+		paths: map[string]bool{},
+	}
+}
+
+// Register tracks the given path for removal by Cleanup.
+func (c *CleanupRegistry) Register(path string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.paths[path] = true
+}
+
+// Release removes the given path once it is no longer needed and stops
+// tracking it.
+func (c *CleanupRegistry) Release(path string) {
+	removeTemp(path)
+
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.paths, path)
+}
+
+// Cleanup removes all tracked paths.
+func (c *CleanupRegistry) Cleanup() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for path := range c.paths {
+		log.Debugf("cleaning up %s", path)
+		removeTemp(path)
+		delete(c.paths, path)
+	}
+}
+
+// Pending returns the number of tracked paths.
+func (c *CleanupRegistry) Pending() int {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.paths)
+}
+
+func removeTemp(path string) {
+	if err := os.RemoveAll(path); err != nil {
+		log.Debugf("could not remove %s: %s", path, err)
+	}
+}
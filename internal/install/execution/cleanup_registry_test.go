@@ -0,0 +1,66 @@
+//go:build unit
+// +build unit
+
+package execution
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func tempFile(t *testing.T) string {
+	f, err := ioutil.TempFile("", "cleanup")
+	require.NoError(t, err)
+	f.Close()
+	return f.Name()
+}
+
+func requireRemoved(t *testing.T, path string) {
+	_, err := os.Stat(path)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestCleanupRegistry_Cleanup(t *testing.T) {
+	a := tempFile(t)
+	b := tempFile(t)
+
+	c := NewCleanupRegistry()
+	c.Register(a)
+	c.Register(b)
+	require.Equal(t, 2, c.Pending())
+
+	c.Cleanup()
+
+	require.Equal(t, 0, c.Pending())
+	requireRemoved(t, a)
+	requireRemoved(t, b)
+}
+
+func TestCleanupRegistry_Release(t *testing.T) {
+	a := tempFile(t)
+
+	c := NewCleanupRegistry()
+	c.Register(a)
+	c.Release(a)
+
+	require.Equal(t, 0, c.Pending())
+	requireRemoved(t, a)
+}
+
+func TestCleanupRegistry_Nil(t *testing.T) {
+	a := tempFile(t)
+
+	var c *CleanupRegistry
+	c.Register(a)
+	c.Cleanup()
+	require.Equal(t, 0, c.Pending())
+
+	_, err := os.Stat(a)
+	require.NoError(t, err)
+
+	c.Release(a)
+	requireRemoved(t, a)
+}
@@ -0,0 +1,310 @@
+package execution
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-task/task/v3"
+	"github.com/go-task/task/v3/taskfile"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+const (
+	maskedValue = "********"
+
+	// taskCommandPrefix starts every message go-task logs, including the echo
+	// of each command it runs.
+	taskCommandPrefix = "task: "
+)
+
+var (
+	// sensitiveVarPatterns match the names of variables whose values are
+	// masked in audit logs.
+	sensitiveVarPatterns = []string{
+		"LICENSE_KEY",
+		"API_KEY",
+		"INSERT_KEY",
+		"PRIVATE_KEY",
+		"PASSWORD",
+		"PASSWD",
+		"SECRET",
+		"TOKEN",
+		"CREDENTIAL",
+	}
+
+	ansiEscapes = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+)
+
+// AuditedCommand is an entry in a recipe's audit log: a single shell command
+// run by the recipe, with its working directory and the environment set by the
+// recipe.  Sensitive values are masked.
+type AuditedCommand struct {
+	Time    time.Time         `json:"time"`
+	Recipe  string            `json:"recipe"`
+	Task    string            `json:"task"`
+	Dir     string            `json:"dir"`
+	Command string            `json:"command"`
+	Env     map[string]string `json:"env,omitempty"`
+}
+
+// taskOutput mirrors go-task's output interface, which is internal to it.
+type taskOutput interface {
+	WrapWriter(w io.Writer, prefix string) io.Writer
+}
+
+// commandAuditor records every command a go-task executor runs.  go-task has
+// no hook for this, so the executor is made to echo every command, and the
+// echo is paired with the wrapping of the command's output, which go-task does
+// immediately before running it.  Echoes are still only shown for commands the
+// recipe does not mark as silent, unless verbose.
+type commandAuditor struct {
+	executor *task.Executor
+	recipe   string
+	verbose  bool
+	masker   *secretMasker
+	stderr   io.Writer
+	output   taskOutput
+	log      io.Writer
+
+	mu      sync.Mutex
+	pending []byte
+}
+
+// attachCommandAuditor starts auditing the commands run by the given executor,
+// which must already be set up, writing them to w as JSON lines.
+func attachCommandAuditor(e *task.Executor, recipe string, vars types.RecipeVars, verbose bool, w io.Writer) *commandAuditor {
+	a := &commandAuditor{
+		executor: e,
+		recipe:   recipe,
+		verbose:  verbose,
+		masker:   newSecretMasker(vars),
+		stderr:   e.Logger.Stderr,
+		output:   e.Output,
+		log:      w,
+	}
+
+	e.Verbose = true
+	e.Logger.Stderr = a
+	e.Output = a
+
+	return a
+}
+
+// Write receives each message logged by go-task.  A command echo is held until
+// its command runs, and any other message is passed through.
+func (a *commandAuditor) Write(p []byte) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.flushPending()
+
+	msg := ansiEscapes.ReplaceAllString(string(p), "")
+	if strings.HasPrefix(msg, taskCommandPrefix) {
+		a.pending = append([]byte{}, p...)
+		return len(p), nil
+	}
+
+	return a.stderr.Write(p)
+}
+
+// WrapWriter is called by go-task as each command is about to run, first for
+// its standard output.
+func (a *commandAuditor) WrapWriter(w io.Writer, prefix string) io.Writer {
+	if w == a.executor.Stdout {
+		a.mu.Lock()
+		echo := a.pending
+		a.pending = nil
+		a.mu.Unlock()
+
+		if echo != nil {
+			a.record(prefix, echo)
+		}
+	}
+
+	return a.output.WrapWriter(w, prefix)
+}
+
+// Flush passes through a message still held once the recipe has finished.
+func (a *commandAuditor) Flush() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.flushPending()
+}
+
+func (a *commandAuditor) flushPending() {
+	if a.pending == nil {
+		return
+	}
+
+	a.writeStderr(a.pending)
+	a.pending = nil
+}
+
+func (a *commandAuditor) writeStderr(p []byte) {
+	if _, err := a.stderr.Write(p); err != nil {
+		log.Debugf("could not write task output: %s", err)
+	}
+}
+
+// record audits the command echoed by go-task, showing the echo unless the
+// command is silent.
+func (a *commandAuditor) record(prefix string, echo []byte) {
+	msg := ansiEscapes.ReplaceAllString(string(echo), "")
+	command := strings.TrimSuffix(strings.TrimPrefix(msg, taskCommandPrefix), "\n")
+
+	entry := AuditedCommand{
+		Time:    time.Now(),
+		Recipe:  a.recipe,
+		Task:    prefix,
+		Command: a.masker.Mask(command),
+	}
+
+	silent := a.executor.Taskfile.Silent
+	if t := a.compiledTask(prefix); t != nil {
+		entry.Task = t.Task
+		entry.Dir = t.Dir
+		entry.Env = a.masker.MaskVars(t.Env)
+		silent = silent || t.Silent || isSilentCommand(t, command)
+	}
+
+	if entry.Dir == "" {
+		entry.Dir, _ = os.Getwd()
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Debugf("could not audit command of recipe %s: %s", a.recipe, err)
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.verbose || !silent {
+		a.writeStderr(echo)
+	}
+
+	if _, err := a.log.Write(append(data, '\n')); err != nil {
+		log.Debugf("could not audit command of recipe %s: %s", a.recipe, err)
+	}
+}
+
+// compiledTask finds the task with the given output prefix, which is the task
+// name unless the task sets its own.  Dynamic variables are not evaluated, as
+// that would run their commands again.
+func (a *commandAuditor) compiledTask(prefix string) *taskfile.Task {
+	name := prefix
+	if _, ok := a.executor.Taskfile.Tasks[name]; !ok {
+		for n, t := range a.executor.Taskfile.Tasks {
+			if t.Prefix == prefix {
+				name = n
+				break
+			}
+		}
+	}
+
+	t, err := a.executor.FastCompiledTask(taskfile.Call{Task: name})
+	if err != nil {
+		return nil
+	}
+
+	return t
+}
+
+func isSilentCommand(t *taskfile.Task, command string) bool {
+	for _, c := range t.Cmds {
+		if c.Cmd == command {
+			return c.Silent
+		}
+	}
+
+	return false
+}
+
+// secretMasker replaces the values of sensitive variables with a mask.
+type secretMasker struct {
+	secrets []string
+}
+
+func newSecretMasker(vars types.RecipeVars) *secretMasker {
+	m := &secretMasker{}
+	for k, v := range vars {
+		if isSensitiveVar(k) {
+			m.add(v)
+		}
+	}
+
+	return m
+}
+
+func (m *secretMasker) add(secret string) {
+	if strings.TrimSpace(secret) == "" {
+		return
+	}
+
+	m.secrets = append(m.secrets, secret)
+
+	// Replace longer secrets first, in case one contains another.
+	sort.Slice(m.secrets, func(i, j int) bool {
+		return len(m.secrets[i]) > len(m.secrets[j])
+	})
+}
+
+// Mask replaces every known secret in s.
+func (m *secretMasker) Mask(s string) string {
+	for _, secret := range m.secrets {
+		s = strings.ReplaceAll(s, secret, maskedValue)
+	}
+
+	return s
+}
+
+// MaskVars returns the values of vars, masking sensitive variables
+// entirely and known secrets within the others.
+func (m *secretMasker) MaskVars(vars *taskfile.Vars) map[string]string {
+	if vars.Len() == 0 {
+		return nil
+	}
+
+	masked := map[string]string{}
+	_ = vars.Range(func(k string, v taskfile.Var) error {
+		if isSensitiveVar(k) {
+			m.add(v.Static)
+		}
+		return nil
+	})
+	_ = vars.Range(func(k string, v taskfile.Var) error {
+		switch {
+		case isSensitiveVar(k):
+			masked[k] = maskedValue
+		case v.Sh != "":
+			// Dynamic variables are shown in their taskfile form.
+			masked[k] = "$" + m.Mask(v.Sh)
+		default:
+			masked[k] = m.Mask(v.Static)
+		}
+		return nil
+	})
+
+	return masked
+}
+
+func isSensitiveVar(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, p := range sensitiveVarPatterns {
+		if strings.Contains(upper, p) {
+			return true
+		}
+	}
+
+	return false
+}
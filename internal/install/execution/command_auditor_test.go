@@ -0,0 +1,49 @@
+//go:build unit
+// +build unit
+
+package execution
+
+import (
+	"testing"
+
+	"github.com/go-task/task/v3/taskfile"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestSecretMasker_MasksSensitiveVarValues(t *testing.T) {
+	m := newSecretMasker(types.RecipeVars{
+		"NEW_RELIC_LICENSE_KEY": "licensekey123",
+		"NEW_RELIC_API_KEY":     "NRAK-apikey",
+		"HOSTNAME":              "myhost",
+	})
+
+	masked := m.Mask("install --license licensekey123 --api-key NRAK-apikey --host myhost")
+	require.Equal(t, "install --license ******** --api-key ******** --host myhost", masked)
+}
+
+func TestSecretMasker_MaskVars(t *testing.T) {
+	m := newSecretMasker(types.RecipeVars{})
+
+	vars := &taskfile.Vars{}
+	vars.Set("MYSQL_PASSWORD", taskfile.Var{Static: "hunter2"})
+	vars.Set("DSN", taskfile.Var{Static: "root:hunter2@localhost"})
+	vars.Set("VERSION", taskfile.Var{Sh: "cat VERSION"})
+
+	masked := m.MaskVars(vars)
+	require.Equal(t, maskedValue, masked["MYSQL_PASSWORD"])
+	require.Equal(t, "root:********@localhost", masked["DSN"])
+	require.Equal(t, "$cat VERSION", masked["VERSION"])
+}
+
+func TestSecretMasker_MaskVarsEmpty(t *testing.T) {
+	require.Nil(t, newSecretMasker(types.RecipeVars{}).MaskVars(nil))
+}
+
+func TestIsSensitiveVar(t *testing.T) {
+	require.True(t, isSensitiveVar("NEW_RELIC_LICENSE_KEY"))
+	require.True(t, isSensitiveVar("db_password"))
+	require.True(t, isSensitiveVar("GITHUB_TOKEN"))
+	require.False(t, isSensitiveVar("HOSTNAME"))
+}
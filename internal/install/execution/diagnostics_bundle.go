@@ -0,0 +1,156 @@
+package execution
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	diagnosticsBundleSuffix = "-diagnostics.zip"
+	environmentArtifactFile = "environment.json"
+)
+
+// diagnosticsEnvPrefixes and diagnosticsEnvVars select the environment
+// variables recorded in the environment summary of a diagnostics bundle.
+var (
+	diagnosticsEnvPrefixes = []string{"NEW_RELIC_", "NR_"}
+	diagnosticsEnvVars     = []string{"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "LANG", "SHELL", "PATH"}
+)
+
+// EnvironmentSummary describes the environment an install ran in, with its
+// sensitive values masked.
+type EnvironmentSummary struct {
+	OS            string            `json:"os"`
+	Arch          string            `json:"arch"`
+	GoVersion     string            `json:"goVersion"`
+	CLIVersion    string            `json:"cliVersion,omitempty"`
+	CorrelationID string            `json:"correlationId,omitempty"`
+	Args          []string          `json:"args"`
+	Env           map[string]string `json:"env"`
+}
+
+// DiagnosticsBundlePath returns the path of the diagnostics bundle of the
+// given run directory, beside it in the output directory.
+func DiagnosticsBundlePath(runDir string) string {
+	return filepath.Clean(runDir) + diagnosticsBundleSuffix
+}
+
+// WriteDiagnosticsBundle zips the artifacts of the run directory, which are
+// the discovery manifest, the event stream, the summary and the captured
+// output of each recipe, with a summary of the environment, into a bundle to
+// attach to support tickets, returning its path.  Known secrets are masked in
+// every file of the bundle.
+func (s *InstallStatus) WriteDiagnosticsBundle(runDir string) (string, error) {
+	path := DiagnosticsBundlePath(runDir)
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, artifactFilePerm)
+	if err != nil {
+		return "", fmt.Errorf("could not create diagnostics bundle %s: %s", path, err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+
+	err = filepath.Walk(runDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(runDir, p)
+		if err != nil {
+			return err
+		}
+
+		data, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		return writeZipFile(zw, filepath.ToSlash(rel), []byte(s.redactor().Mask(string(data))))
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not bundle the artifacts of %s: %s", runDir, err)
+	}
+
+	env, err := json.MarshalIndent(s.environmentSummary(), "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	if err := writeZipFile(zw, environmentArtifactFile, env); err != nil {
+		return "", err
+	}
+
+	if err := zw.Close(); err != nil {
+		return "", fmt.Errorf("could not write diagnostics bundle %s: %s", path, err)
+	}
+
+	return path, nil
+}
+
+func writeZipFile(zw *zip.Writer, name string, data []byte) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+// environmentSummary summarizes the environment of the install, masking the
+// values of sensitive variables and arguments, and the credentials of URLs.
+func (s *InstallStatus) environmentSummary() EnvironmentSummary {
+	env := map[string]string{}
+	for _, kv := range os.Environ() {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || !isDiagnosticsEnvVar(parts[0]) {
+			continue
+		}
+
+		if isSensitiveVar(parts[0]) {
+			env[parts[0]] = maskedValue
+		} else {
+			env[parts[0]] = s.redactor().Mask(urlCredentials.ReplaceAllString(parts[1], "$1:"+maskedValue+"@"))
+		}
+	}
+
+	args := []string{}
+	if len(os.Args) > 1 {
+		args = strings.Fields(s.redactor().Mask(redactCmdline(strings.Join(os.Args[1:], " "))))
+	}
+
+	return EnvironmentSummary{
+		OS:            runtime.GOOS,
+		Arch:          runtime.GOARCH,
+		GoVersion:     runtime.Version(),
+		CLIVersion:    s.CLIVersion,
+		CorrelationID: s.CorrelationID,
+		Args:          args,
+		Env:           env,
+	}
+}
+
+func isDiagnosticsEnvVar(name string) bool {
+	upper := strings.ToUpper(name)
+
+	for _, p := range diagnosticsEnvPrefixes {
+		if strings.HasPrefix(upper, p) {
+			return true
+		}
+	}
+
+	for _, v := range diagnosticsEnvVars {
+		if upper == v {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,86 @@
+package execution
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// dryRunOutput collects the messages go-task logs while running a recipe in
+// dry-run mode, which are the commands each step would run, echoed in the
+// order they would run, along with the steps it finds up to date.  Secrets are
+// masked.
+type dryRunOutput struct {
+	masker *secretMasker
+
+	mu      sync.Mutex
+	pending string
+	lines   []string
+}
+
+func newDryRunOutput(vars types.RecipeVars) *dryRunOutput {
+	return &dryRunOutput{
+		masker: newSecretMasker(vars),
+	}
+}
+
+func (o *dryRunOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.pending += ansiEscapes.ReplaceAllString(string(p), "")
+	for {
+		n := strings.Index(o.pending, "\n")
+		if n < 0 {
+			break
+		}
+
+		o.add(o.pending[:n])
+		o.pending = o.pending[n+1:]
+	}
+
+	return len(p), nil
+}
+
+func (o *dryRunOutput) add(line string) {
+	line = strings.TrimPrefix(strings.TrimRight(line, "\r"), taskCommandPrefix)
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+
+	o.lines = append(o.lines, o.masker.Mask(line))
+}
+
+// Lines returns the messages logged, without go-task's prefix.
+func (o *dryRunOutput) Lines() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.pending != "" {
+		o.add(o.pending)
+		o.pending = ""
+	}
+
+	return append([]string{}, o.lines...)
+}
+
+// writeDryRun shows the commands the recipe would run.
+func writeDryRun(w io.Writer, r types.OpenInstallationRecipe, lines []string) {
+	name := r.DisplayName
+	if name == "" {
+		name = r.Name
+	}
+
+	if len(lines) == 0 {
+		fmt.Fprintf(w, "Dry run of %s: no commands would be run.\n", name)
+		return
+	}
+
+	fmt.Fprintf(w, "Dry run of %s, commands that would be run:\n", name)
+	for _, l := range lines {
+		fmt.Fprintf(w, "  %s\n", l)
+	}
+}
@@ -0,0 +1,40 @@
+//go:build unit
+// +build unit
+
+package execution
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestDryRunOutput_CollectsCommands(t *testing.T) {
+	o := newDryRunOutput(types.RecipeVars{"NEW_RELIC_API_KEY": "NRAK-secret"})
+
+	_, err := o.Write([]byte("task: curl -H 'Api-Key: NRAK-secret' https://example.com\n\x1b[32mtask: apt-get "))
+	require.NoError(t, err)
+	_, err = o.Write([]byte("install newrelic-infra\x1b[0m\n\ntask: Task \"configure\" is up to date"))
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"curl -H 'Api-Key: " + maskedValue + "' https://example.com",
+		"apt-get install newrelic-infra",
+		`Task "configure" is up to date`,
+	}, o.Lines())
+}
+
+func TestWriteDryRun(t *testing.T) {
+	r := types.OpenInstallationRecipe{Name: "redis", DisplayName: "Redis"}
+
+	var buf bytes.Buffer
+	writeDryRun(&buf, r, []string{"apt-get install redis-integration"})
+	require.Equal(t, "Dry run of Redis, commands that would be run:\n  apt-get install redis-integration\n", buf.String())
+
+	buf.Reset()
+	writeDryRun(&buf, r, nil)
+	require.Equal(t, "Dry run of Redis: no commands would be run.\n", buf.String())
+}
@@ -0,0 +1,6 @@
+package execution
+
+// EventsClient sends custom events to New Relic.
+type EventsClient interface {
+	CreateEvent(accountID int, event interface{}) error
+}
@@ -4,13 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
-	survey "github.com/AlecAivazis/survey/v2"
-	"github.com/AlecAivazis/survey/v2/terminal"
 	"github.com/go-task/task/v3"
 	taskargs "github.com/go-task/task/v3/args"
 	"github.com/go-task/task/v3/taskfile"
@@ -19,11 +19,76 @@ import (
 
 	"github.com/newrelic/newrelic-cli/internal/credentials"
 	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/ux"
 )
 
 // GoTaskRecipeExecutor is an implementation of the recipeExecutor interface that
 // uses the go-task module to execute the steps defined in each recipe.
-type GoTaskRecipeExecutor struct{}
+type GoTaskRecipeExecutor struct {
+	// VerboseSteps streams every task step and its command to the terminal as
+	// it runs, including steps the recipe marks as silent.
+	VerboseSteps bool
+
+	// VarsFiles maps a recipe name to a YAML file of variables that are
+	// merged into the recipe's variables during Prepare.
+	VarsFiles map[string]string
+
+	// Cleanup, when set, tracks the temporary task file of a running recipe so
+	// it is removed if the install is canceled mid-recipe.
+	Cleanup *CleanupRegistry
+
+	// OutputDir, when set, is the run directory to which each recipe's output
+	// is also captured, as recipes/<name>.log.
+	OutputDir string
+
+	// Audit records every shell command a recipe runs, with its working
+	// directory and environment, to recipes/<name>.audit.jsonl in OutputDir.
+	// Sensitive values are masked.
+	Audit bool
+
+	// Quiet discards the standard output of recipes instead of streaming it to
+	// the terminal.  Standard error is still shown.
+	Quiet bool
+
+	// StepMarkerDir, when set, is the directory in which the idempotency keys
+	// of completed recipe steps are recorded, so later installs skip them.
+	StepMarkerDir string
+
+	// Reinstall runs every step again, ignoring and clearing the idempotency
+	// keys recorded by previous installs.
+	Reinstall bool
+
+	// StepSkipped, when set, is called for each recipe step skipped because
+	// its step check reports it already complete.
+	StepSkipped func(RecipeStepEvent)
+
+	// Shell, when set, overrides the shell named by each recipe for running
+	// its steps.
+	Shell string
+
+	// IsolatedEnv runs each recipe with a temporary home directory and an
+	// environment cleared of all but allowlisted variables, restoring the
+	// original environment afterwards.
+	IsolatedEnv bool
+
+	// DryRun runs each recipe in go-task's dry-run mode, showing the commands
+	// its steps would run, as authored by the recipe, without running them.
+	// Status checks, preconditions and dynamic variables are still evaluated.
+	DryRun bool
+
+	// ResourceLimits, when set, limits the CPU and memory the processes run by
+	// each recipe's steps may use, on hosts supporting it.
+	ResourceLimits ResourceLimits
+
+	// CABundle, when set, is the path of a PEM bundle of CA certificates that
+	// recipe steps trust along with the system's, through SSL_CERT_FILE and
+	// the like, so that their downloads verify through a TLS-inspecting proxy.
+	CABundle string
+
+	// Prompter, when set, prompts for the values of the recipe's input
+	// variables.  The user is otherwise asked at the terminal.
+	Prompter ux.Prompter
+}
 
 // NewGoTaskRecipeExecutor returns a new instance of GoTaskRecipeExecutor.
 func NewGoTaskRecipeExecutor() *GoTaskRecipeExecutor {
@@ -40,20 +105,35 @@ func (re *GoTaskRecipeExecutor) Prepare(ctx context.Context, m types.DiscoveryMa
 	results := []types.RecipeVars{}
 
 	systemInfoResult := varsFromSystemInfo(m)
+	platformDefaultsResult := varsFromPlatformDefaults(m, r)
+	inputVars := inputVarsWithDefaults(r.InputVars, platformDefaultsResult)
 
 	profileResult, err := varsFromProfile(licenseKey)
 	if err != nil {
 		return types.RecipeVars{}, err
 	}
 
-	inputVarsResult, err := varsFromInput(r.InputVars, assumeYes)
+	fileVarsResult, err := re.varsFromFile(r)
+	if err != nil {
+		return types.RecipeVars{}, err
+	}
+
+	if assumeYes {
+		if missing := missingInputVars(inputVars, fileVarsResult); len(missing) > 0 {
+			return types.RecipeVars{}, fmt.Errorf("missing required variables for recipe %s: %s; set them in the environment or with --recipe-vars-file", r.Name, strings.Join(missing, ", "))
+		}
+	}
+
+	inputVarsResult, err := varsFromInput(re.prompter(), inputVars, assumeYes, fileVarsResult)
 	if err != nil {
 		return types.RecipeVars{}, err
 	}
 
+	results = append(results, platformDefaultsResult)
 	results = append(results, systemInfoResult)
 	results = append(results, profileResult)
 	results = append(results, types.RecipeVariables)
+	results = append(results, fileVarsResult)
 	results = append(results, inputVarsResult)
 
 	for _, result := range results {
@@ -62,6 +142,10 @@ func (re *GoTaskRecipeExecutor) Prepare(ctx context.Context, m types.DiscoveryMa
 		}
 	}
 
+	if err := checkRequiredEnvVars(r, vars, os.LookupEnv); err != nil {
+		return types.RecipeVars{}, err
+	}
+
 	return vars, nil
 }
 
@@ -72,27 +156,94 @@ func (re *GoTaskRecipeExecutor) Execute(ctx context.Context, m types.DiscoveryMa
 
 	// Create a temporary task file.
 	file, err := ioutil.TempFile("", r.Name)
-	defer os.Remove(file.Name())
 	if err != nil {
 		return err
 	}
+	re.Cleanup.Register(file.Name())
+	defer re.Cleanup.Release(file.Name())
 
 	_, err = file.Write(out)
+	file.Close()
 	if err != nil {
 		return err
 	}
 
+	if re.IsolatedEnv {
+		restoreEnv, err := re.isolateEnv(r.Name, recipeVars)
+		if err != nil {
+			return fmt.Errorf("could not isolate the environment of recipe %s: %s", r.Name, err)
+		}
+		defer restoreEnv()
+	}
+
+	restoreCABundleEnv := re.applyCABundle(r.Name)
+	defer restoreCABundleEnv()
+
+	tail := newOutputTail(defaultOutputTailLines)
+	var stdout io.Writer = io.MultiWriter(os.Stdout, tail)
+	if re.Quiet {
+		stdout = tail
+	}
+	stderr := io.MultiWriter(os.Stderr, tail)
+	var dryRunOut io.Writer = os.Stdout
+
+	if re.OutputDir != "" {
+		logFile, err := os.OpenFile(RecipeLogPath(re.OutputDir, r.Name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, artifactFilePerm)
+		if err != nil {
+			log.Warnf("Could not capture output of recipe %s: %s", r.Name, err)
+		} else {
+			defer logFile.Close()
+			stdout = io.MultiWriter(stdout, logFile)
+			stderr = io.MultiWriter(stderr, logFile)
+			dryRunOut = io.MultiWriter(dryRunOut, logFile)
+		}
+	}
+
+	// The working directory is not created in a dry run, in which the steps
+	// are shown in the current directory instead.
+	dir, err := resolveWorkingDir(r, recipeVars, !re.DryRun)
+	if err != nil {
+		return err
+	}
+	if re.DryRun && dir != "" {
+		if _, err := os.Stat(dir); err != nil {
+			log.Debugf("working directory %s of recipe %s would be created", dir, r.Name)
+			dir = ""
+		}
+	}
+	logWorkingDir(r.Name, dir)
+
+	// go-task reads the entrypoint relative to the executor's directory.
+	entrypoint := file.Name()
+	if dir != "" {
+		if entrypoint, err = filepath.Rel(dir, file.Name()); err != nil {
+			return fmt.Errorf("could not run recipe %s in %s: %s", r.Name, dir, err)
+		}
+	}
+
 	e := task.Executor{
-		Entrypoint: file.Name(),
-		Stderr:     os.Stderr,
-		Stdout:     os.Stdout,
+		Dir:        dir,
+		Entrypoint: entrypoint,
+		Stderr:     stderr,
+		Stdout:     stdout,
 		Stdin:      os.Stdin,
+		Verbose:    re.VerboseSteps,
 	}
 
 	if err = e.Setup(); err != nil {
 		return fmt.Errorf("could not set up task executor: %s", err)
 	}
 
+	var dryRun *dryRunOutput
+	if re.DryRun {
+		// Every command is echoed, including those of silent steps.
+		dryRun = newDryRunOutput(recipeVars)
+		e.Dry = true
+		e.Verbose = true
+		e.Logger.Verbose = true
+		e.Logger.Stderr = dryRun
+	}
+
 	var tf taskfile.Taskfile
 	err = yaml.Unmarshal(out, &tf)
 	if err != nil {
@@ -106,9 +257,36 @@ func (re *GoTaskRecipeExecutor) Execute(ctx context.Context, m types.DiscoveryMa
 		e.Taskfile.Vars.Set(k, taskfile.Var{Static: val})
 	}
 
+	if err := re.applyShell(&e, r.Name, r.Shell); err != nil {
+		return err
+	}
+
+	var limiter resourceLimiter
+	if re.ResourceLimits.IsSet() && !re.DryRun {
+		if limiter = re.applyResourceLimits(&e, r.Name); limiter != nil {
+			defer limiter.release()
+		}
+	}
+
+	skippedSteps, err := re.applyStepConditions(&e, m, r, recipeVars)
+	if err != nil {
+		return err
+	}
+
+	re.applyStepChecks(ctx, &e, r, skippedSteps)
+
+	if re.Audit && !re.DryRun {
+		auditor, closeAuditLog := re.attachAuditor(&e, r.Name, recipeVars)
+		if auditor != nil {
+			defer closeAuditLog()
+			defer auditor.Flush()
+		}
+	}
+
 	if err := e.Run(ctx, calls...); err != nil {
 		log.WithFields(log.Fields{
-			"err": err,
+			"err":         err,
+			"output_tail": strings.Join(tail.Lines(), "\n"),
 		}).Debug("Task execution returned error")
 
 		// go-task does not provide an error type to denote context cancelation
@@ -122,12 +300,81 @@ func (re *GoTaskRecipeExecutor) Execute(ctx context.Context, m types.DiscoveryMa
 			return types.ErrInterrupt
 		}
 
+		if limiter != nil && limiter.memoryExceeded() {
+			return NewErrMemoryLimitExceeded(r.Name, re.ResourceLimits.MemoryMB)
+		}
+
 		return err
 	}
 
+	if dryRun != nil {
+		writeDryRun(dryRunOut, r, dryRun.Lines())
+	}
+
 	return nil
 }
 
+// attachAuditor starts auditing the commands run by e to the recipe's audit
+// log.  Auditing is skipped, with a warning, when the log cannot be opened.
+func (re *GoTaskRecipeExecutor) attachAuditor(e *task.Executor, recipeName string, recipeVars types.RecipeVars) (*commandAuditor, func()) {
+	if re.OutputDir == "" {
+		log.Warnf("Commands of recipe %s will not be audited: no output directory", recipeName)
+		return nil, nil
+	}
+
+	auditLog, err := os.OpenFile(RecipeAuditLogPath(re.OutputDir, recipeName), os.O_APPEND|os.O_CREATE|os.O_WRONLY, artifactFilePerm)
+	if err != nil {
+		log.Warnf("Commands of recipe %s will not be audited: %s", recipeName, err)
+		return nil, nil
+	}
+
+	return attachCommandAuditor(e, recipeName, recipeVars, re.VerboseSteps, auditLog), func() { auditLog.Close() }
+}
+
+func (re *GoTaskRecipeExecutor) varsFromFile(r types.OpenInstallationRecipe) (types.RecipeVars, error) {
+	path, ok := re.VarsFiles[r.Name]
+	if !ok {
+		return types.RecipeVars{}, nil
+	}
+
+	log.WithFields(log.Fields{
+		"name": r.Name,
+		"path": path,
+	}).Debug("loading recipe vars file")
+
+	vars, err := loadRecipeVarsFile(path)
+	if err != nil {
+		return types.RecipeVars{}, err
+	}
+
+	if err = validateRecipeVars(r, vars); err != nil {
+		return types.RecipeVars{}, err
+	}
+
+	return vars, nil
+}
+
+// BuiltinRecipeVarNames returns the names of the variables set for every
+// recipe in addition to its input variables and platform defaults.
+func BuiltinRecipeVarNames() []string {
+	return []string{
+		"NEW_RELIC_LICENSE_KEY",
+		"NEW_RELIC_ACCOUNT_ID",
+		"NEW_RELIC_API_KEY",
+		"NEW_RELIC_REGION",
+		"NEW_RELIC_ASSUME_YES",
+		"HOSTNAME",
+		"OS",
+		"PLATFORM",
+		"PLATFORM_FAMILY",
+		"PLATFORM_VERSION",
+		"KERNEL_ARCH",
+		"KERNEL_VERSION",
+		"PACKAGE_MANAGER",
+		"NR_DISCOVERED_LOG_FILES",
+	}
+}
+
 func varsFromProfile(licenseKey string) (types.RecipeVars, error) {
 	defaultProfile := credentials.DefaultProfile()
 	if licenseKey == "" {
@@ -154,11 +401,85 @@ func varsFromSystemInfo(m types.DiscoveryManifest) types.RecipeVars {
 	vars["PLATFORM_VERSION"] = m.PlatformVersion
 	vars["KERNEL_ARCH"] = m.KernelArch
 	vars["KERNEL_VERSION"] = m.KernelVersion
+	vars["PACKAGE_MANAGER"] = m.PackageManager
 
 	return vars
 }
 
-func varsFromInput(inputVars []types.OpenInstallationRecipeInputVariable, assumeYes bool) (types.RecipeVars, error) {
+// varsFromPlatformDefaults returns the recipe's default variables for the
+// discovered platform.  An environment variable of the same name overrides its
+// default.
+func varsFromPlatformDefaults(m types.DiscoveryManifest, r types.OpenInstallationRecipe) types.RecipeVars {
+	vars, applied := r.PlatformDefaultVars(m)
+	if len(applied) == 0 {
+		return vars
+	}
+
+	for k := range vars {
+		if v := os.Getenv(k); v != "" {
+			vars[k] = v
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"name":      r.Name,
+		"platforms": applied,
+	}).Debug("applied platform default variables")
+
+	return vars
+}
+
+// inputVarsWithDefaults returns the input variables with their defaults
+// replaced by any platform defaults, so prompts and --assumeYes use them.
+func inputVarsWithDefaults(inputVars []types.OpenInstallationRecipeInputVariable, defaults types.RecipeVars) []types.OpenInstallationRecipeInputVariable {
+	if len(defaults) == 0 {
+		return inputVars
+	}
+
+	withDefaults := make([]types.OpenInstallationRecipeInputVariable, len(inputVars))
+	for n, v := range inputVars {
+		if d, ok := defaults[v.Name]; ok {
+			v.Default = d
+		}
+		withDefaults[n] = v
+	}
+
+	return withDefaults
+}
+
+// missingInputVars returns the names of the input variables that have no value
+// from the environment or the given vars and no default.
+func missingInputVars(inputVars []types.OpenInstallationRecipeInputVariable, provided types.RecipeVars) []string {
+	missing := []string{}
+
+	for _, envConfig := range inputVars {
+		if os.Getenv(envConfig.Name) != "" || envConfig.Default != "" {
+			continue
+		}
+
+		if _, ok := provided[envConfig.Name]; ok {
+			continue
+		}
+
+		missing = append(missing, envConfig.Name)
+	}
+
+	return missing
+}
+
+// prompter returns the prompter for input variables.
+func (re *GoTaskRecipeExecutor) prompter() ux.Prompter {
+	if re.Prompter != nil {
+		return re.Prompter
+	}
+
+	return ux.NewPromptUIPrompter()
+}
+
+// varsFromInput returns the values of the input variables, from the
+// environment, the provided vars or, failing those, the prompter.  With
+// assumeYes, defaults are used instead of prompting.
+func varsFromInput(p ux.Prompter, inputVars []types.OpenInstallationRecipeInputVariable, assumeYes bool, provided types.RecipeVars) (types.RecipeVars, error) {
 	vars := make(types.RecipeVars)
 
 	vars["NEW_RELIC_ASSUME_YES"] = fmt.Sprintf("%t", assumeYes)
@@ -172,6 +493,11 @@ func varsFromInput(inputVars []types.OpenInstallationRecipeInputVariable, assume
 			continue
 		}
 
+		if v, ok := provided[envConfig.Name]; ok {
+			vars[envConfig.Name] = v
+			continue
+		}
+
 		if assumeYes {
 			if envConfig.Default == "" {
 				return types.RecipeVars{}, fmt.Errorf("no default value for environment variable %s and none provided", envConfig.Name)
@@ -188,10 +514,10 @@ func varsFromInput(inputVars []types.OpenInstallationRecipeInputVariable, assume
 				"name": envConfig.Name,
 			}).Debug("required environment variable not found")
 
-			envValue, err = varFromPrompt(envConfig)
+			envValue, err = varFromPrompt(p, envConfig)
 			if err != nil {
-				if err == terminal.InterruptErr {
-					return types.RecipeVars{}, types.ErrInterrupt
+				if err == types.ErrInterrupt || err == types.ErrPromptTimeout {
+					return types.RecipeVars{}, err
 				}
 
 				return types.RecipeVars{}, fmt.Errorf("prompt failed: %s", err)
@@ -204,38 +530,12 @@ func varsFromInput(inputVars []types.OpenInstallationRecipeInputVariable, assume
 	return vars, nil
 }
 
-func varFromPrompt(envConfig types.OpenInstallationRecipeInputVariable) (string, error) {
+func varFromPrompt(p ux.Prompter, envConfig types.OpenInstallationRecipeInputVariable) (string, error) {
 	msg := fmt.Sprintf("value for %s required", envConfig.Name)
 
 	if envConfig.Prompt != "" {
 		msg = envConfig.Prompt
 	}
 
-	value := ""
-	var prompt survey.Prompt
-
-	if envConfig.Secret {
-		prompt = &survey.Password{
-			Message: msg,
-		}
-	} else {
-		defaultValue := ""
-
-		if envConfig.Default != "" {
-			defaultValue = envConfig.Default
-		}
-
-		prompt = &survey.Input{
-			Message: msg,
-			Default: defaultValue,
-		}
-	}
-
-	err := survey.AskOne(prompt, &value)
-	if err != nil {
-		return "", err
-	}
-
-	return value, nil
-
+	return p.Input(msg, envConfig.Default, envConfig.Secret)
 }
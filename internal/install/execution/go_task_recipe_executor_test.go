@@ -1,3 +1,4 @@
+//go:build integration
 // +build integration
 
 package execution
@@ -8,8 +9,12 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"gopkg.in/yaml.v2"
 
@@ -106,3 +111,427 @@ func TestExecute_SystemVariableInterpolation(t *testing.T) {
 	require.Equal(t, m.KernelArch, actual.KernelArch)
 	require.Equal(t, m.KernelVersion, actual.KernelVersion)
 }
+
+func TestExecute_AuditsCommands(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "newrelic")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	runDir, err := CreateRunDirectory(tmp, time.Now())
+	require.NoError(t, err)
+
+	e := NewGoTaskRecipeExecutor()
+	e.Audit = true
+	e.OutputDir = runDir
+
+	r := types.OpenInstallationRecipe{
+		Name: "audited",
+		Install: `
+version: "3"
+tasks:
+  default:
+    cmds:
+      - task: setup
+      - echo "key is {{.NEW_RELIC_LICENSE_KEY}}"
+  setup:
+    silent: true
+    env:
+      MODE: quiet
+      DB_PASSWORD: hunter2
+    cmds:
+      - echo setting up
+`,
+	}
+
+	err = e.Execute(context.Background(), types.DiscoveryManifest{}, r, types.RecipeVars{"NEW_RELIC_LICENSE_KEY": "abc123secret"})
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadFile(RecipeAuditLogPath(runDir, "audited"))
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "abc123secret")
+	require.NotContains(t, string(data), "hunter2")
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var setup, key AuditedCommand
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &setup))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &key))
+
+	require.Equal(t, "setup", setup.Task)
+	require.Equal(t, "echo setting up", setup.Command)
+	require.Equal(t, "quiet", setup.Env["MODE"])
+	require.Equal(t, maskedValue, setup.Env["DB_PASSWORD"])
+	require.NotEmpty(t, setup.Dir)
+
+	require.Equal(t, "default", key.Task)
+	require.Equal(t, `echo "key is `+maskedValue+`"`, key.Command)
+}
+
+func TestExecute_SkipsCompletedSteps(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "newrelic")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	skipped := []RecipeStepEvent{}
+	e := NewGoTaskRecipeExecutor()
+	e.StepMarkerDir = filepath.Join(tmp, "markers")
+	e.StepSkipped = func(event RecipeStepEvent) {
+		skipped = append(skipped, event)
+	}
+
+	r := types.OpenInstallationRecipe{
+		Name: "idempotent",
+		Install: `
+version: "3"
+tasks:
+  default:
+    cmds:
+      - task: configure
+      - task: install
+  configure:
+    cmds:
+      - echo configured > {{.OUT_DIR}}/configured
+  install:
+    cmds:
+      - echo installed >> {{.OUT_DIR}}/installed
+`,
+		StepChecks: []types.OpenInstallationStepCheck{
+			{Step: "configure", Check: "test -f {{.OUT_DIR}}/configured"},
+			{Step: "install", IdempotencyKey: "install-v1"},
+		},
+	}
+	vars := types.RecipeVars{"OUT_DIR": filepath.ToSlash(tmp)}
+
+	err = e.Execute(context.Background(), types.DiscoveryManifest{}, r, vars)
+	require.NoError(t, err)
+	require.Empty(t, skipped)
+	require.FileExists(t, StepMarkerPath(e.StepMarkerDir, r.Name, "install-v1"))
+
+	err = e.Execute(context.Background(), types.DiscoveryManifest{}, r, vars)
+	require.NoError(t, err)
+	require.Len(t, skipped, 2)
+	require.Equal(t, "configure", skipped[0].Step)
+	require.Equal(t, stepCheckPassedMsg, skipped[0].Msg)
+	require.Equal(t, "install", skipped[1].Step)
+	require.Equal(t, stepMarkerFoundMsg, skipped[1].Msg)
+
+	data, err := ioutil.ReadFile(filepath.Join(tmp, "installed"))
+	require.NoError(t, err)
+	require.Equal(t, "installed\n", string(data))
+}
+
+func TestExecute_ReinstallIgnoresStepMarkers(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "newrelic")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	e := NewGoTaskRecipeExecutor()
+	e.StepMarkerDir = filepath.Join(tmp, "markers")
+
+	r := types.OpenInstallationRecipe{
+		Name: "idempotent",
+		Install: `
+version: "3"
+tasks:
+  default:
+    cmds:
+      - echo installed >> {{.OUT_DIR}}/installed
+`,
+		StepChecks: []types.OpenInstallationStepCheck{
+			{Step: "default", IdempotencyKey: "install-v1"},
+		},
+	}
+	vars := types.RecipeVars{"OUT_DIR": filepath.ToSlash(tmp)}
+
+	stale := StepMarkerPath(e.StepMarkerDir, r.Name, "install-v0")
+	require.NoError(t, os.MkdirAll(filepath.Dir(stale), 0700))
+	require.NoError(t, ioutil.WriteFile(stale, []byte("install-v0"), 0600))
+	require.NoError(t, ioutil.WriteFile(StepMarkerPath(e.StepMarkerDir, r.Name, "install-v1"), []byte("install-v1"), 0600))
+
+	e.Reinstall = true
+	err = e.Execute(context.Background(), types.DiscoveryManifest{}, r, vars)
+	require.NoError(t, err)
+	require.NoFileExists(t, stale)
+	require.FileExists(t, StepMarkerPath(e.StepMarkerDir, r.Name, "install-v1"))
+
+	data, err := ioutil.ReadFile(filepath.Join(tmp, "installed"))
+	require.NoError(t, err)
+	require.Equal(t, "installed\n", string(data))
+}
+
+func TestExecute_SkipsStepsWhoseConditionIsFalse(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "newrelic")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	skipped := []RecipeStepEvent{}
+	e := NewGoTaskRecipeExecutor()
+	e.StepSkipped = func(event RecipeStepEvent) {
+		skipped = append(skipped, event)
+	}
+
+	r := types.OpenInstallationRecipe{
+		Name: "conditional",
+		Install: `
+version: "3"
+tasks:
+  default:
+    cmds:
+      - task: install_apt
+      - task: install_yum
+  install_apt:
+    cmds:
+      - echo apt > {{.OUT_DIR}}/apt
+  install_yum:
+    cmds:
+      - echo yum > {{.OUT_DIR}}/yum
+`,
+		StepConditions: []types.OpenInstallationStepCondition{
+			{Step: "install_apt", When: `packageManager == "apt"`},
+			{Step: "install_yum", When: `packageManager == "yum"`},
+		},
+	}
+	vars := types.RecipeVars{"OUT_DIR": filepath.ToSlash(tmp)}
+
+	err = e.Execute(context.Background(), types.DiscoveryManifest{PackageManager: "apt"}, r, vars)
+	require.NoError(t, err)
+	require.FileExists(t, filepath.Join(tmp, "apt"))
+	require.NoFileExists(t, filepath.Join(tmp, "yum"))
+	require.Len(t, skipped, 1)
+	require.Equal(t, "install_yum", skipped[0].Step)
+	require.Equal(t, `condition not met: packageManager == "yum"`, skipped[0].Msg)
+}
+
+func TestExecute_FailsWhenConditionInvalid(t *testing.T) {
+	e := NewGoTaskRecipeExecutor()
+	r := types.OpenInstallationRecipe{
+		Name: "conditional",
+		Install: `
+version: "3"
+tasks:
+  default:
+    cmds:
+      - echo installed
+`,
+		StepConditions: []types.OpenInstallationStepCondition{
+			{Step: "default", When: `unknownFact == "apt"`},
+		},
+	}
+
+	err := e.Execute(context.Background(), types.DiscoveryManifest{}, r, types.RecipeVars{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown name unknownFact")
+}
+
+func TestExecute_RunsStepsWithRecipeShell(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not installed")
+	}
+
+	tmp, err := ioutil.TempDir("", "newrelic")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	out := filepath.Join(tmp, "shell")
+	r := types.OpenInstallationRecipe{
+		Name:  "bash-only",
+		Shell: "bash",
+		Install: `
+version: "3"
+tasks:
+  default:
+    cmds:
+      - echo "$BASH_VERSION" > {{.OUT}}
+`,
+	}
+
+	err = NewGoTaskRecipeExecutor().Execute(context.Background(), types.DiscoveryManifest{}, r, types.RecipeVars{"OUT": filepath.ToSlash(out)})
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadFile(out)
+	require.NoError(t, err)
+	require.NotEmpty(t, strings.TrimSpace(string(data)))
+}
+
+func TestExecute_FailsWhenShellMissing(t *testing.T) {
+	e := NewGoTaskRecipeExecutor()
+	e.Shell = "cmd"
+
+	r := types.OpenInstallationRecipe{
+		Name: "needs-cmd",
+		Install: `
+version: "3"
+tasks:
+  default:
+    cmds:
+      - echo hi
+`,
+	}
+
+	err := e.Execute(context.Background(), types.DiscoveryManifest{}, r, types.RecipeVars{})
+	if runtime.GOOS == "windows" {
+		require.NoError(t, err)
+		return
+	}
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "could not run recipe needs-cmd")
+}
+
+func TestExecute_IsolatedEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the recipe uses POSIX variable expansion")
+	}
+
+	tmp, err := ioutil.TempDir("", "newrelic")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	defer os.Unsetenv("NR_TEST_SECRET")
+	defer os.Unsetenv("NR_TEST_RECIPE_VAR")
+	defer os.Unsetenv("HTTPS_PROXY")
+	os.Setenv("NR_TEST_SECRET", "leaked")
+	os.Setenv("NR_TEST_RECIPE_VAR", "kept")
+	os.Setenv("HTTPS_PROXY", "http://proxy:3128")
+	realHome := os.Getenv("HOME")
+
+	out := filepath.Join(tmp, "env")
+	r := types.OpenInstallationRecipe{
+		Name: "isolated",
+		Install: `
+version: "3"
+tasks:
+  default:
+    cmds:
+      - echo "home=$HOME secret=$NR_TEST_SECRET var=$NR_TEST_RECIPE_VAR proxy=$HTTPS_PROXY" > {{.OUT}}
+      - touch "$HOME/.recipe-dotfile"
+`,
+	}
+
+	e := NewGoTaskRecipeExecutor()
+	e.IsolatedEnv = true
+
+	err = e.Execute(context.Background(), types.DiscoveryManifest{}, r, types.RecipeVars{"OUT": out, "NR_TEST_RECIPE_VAR": "kept"})
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadFile(out)
+	require.NoError(t, err)
+	line := strings.TrimSpace(string(data))
+
+	require.NotContains(t, line, "home="+realHome+" ")
+	require.Contains(t, line, "secret= ")
+	require.Contains(t, line, "var=kept")
+	require.Contains(t, line, "proxy=http://proxy:3128")
+
+	// The temporary home is removed and the environment restored.
+	home := strings.TrimPrefix(strings.Fields(line)[0], "home=")
+	_, err = os.Stat(home)
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(realHome, ".recipe-dotfile"))
+	require.True(t, os.IsNotExist(err))
+	require.Equal(t, "leaked", os.Getenv("NR_TEST_SECRET"))
+	require.Equal(t, realHome, os.Getenv("HOME"))
+}
+
+func TestExecute_DryRun(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "newrelic")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	runDir, err := CreateRunDirectory(tmp, time.Now())
+	require.NoError(t, err)
+
+	e := NewGoTaskRecipeExecutor()
+	e.DryRun = true
+	e.OutputDir = runDir
+	e.StepMarkerDir = filepath.Join(tmp, "markers")
+
+	r := types.OpenInstallationRecipe{
+		Name: "dry",
+		Install: `
+version: "3"
+tasks:
+  default:
+    cmds:
+      - task: install
+      - echo "key is {{.NEW_RELIC_LICENSE_KEY}}"
+  install:
+    silent: true
+    cmds:
+      - echo installed > {{.OUT_DIR}}/installed
+`,
+		StepChecks: []types.OpenInstallationStepCheck{
+			{Step: "install", IdempotencyKey: "install-v1"},
+		},
+	}
+	vars := types.RecipeVars{"OUT_DIR": filepath.ToSlash(tmp), "NEW_RELIC_LICENSE_KEY": "abc123secret"}
+
+	err = e.Execute(context.Background(), types.DiscoveryManifest{}, r, vars)
+	require.NoError(t, err)
+	require.NoFileExists(t, filepath.Join(tmp, "installed"))
+	require.NoFileExists(t, StepMarkerPath(e.StepMarkerDir, r.Name, "install-v1"))
+
+	data, err := ioutil.ReadFile(RecipeLogPath(runDir, "dry"))
+	require.NoError(t, err)
+	require.Contains(t, string(data), "Dry run of dry, commands that would be run:")
+	require.Contains(t, string(data), "  echo installed > "+filepath.ToSlash(tmp)+"/installed\n")
+	require.Contains(t, string(data), `  echo "key is `+maskedValue+`"`)
+	require.NotContains(t, string(data), "abc123secret")
+}
+
+func TestExecute_RunsStepsInWorkingDir(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "newrelic")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	e := NewGoTaskRecipeExecutor()
+
+	r := types.OpenInstallationRecipe{
+		Name:       "cwd",
+		WorkingDir: "${INSTALL_ROOT}/work",
+		Install: `
+version: "3"
+tasks:
+  default:
+    cmds:
+      - task: relative
+      - echo installed > installed
+  relative:
+    dir: sub
+    cmds:
+      - echo nested > nested
+`,
+	}
+	vars := types.RecipeVars{"INSTALL_ROOT": tmp}
+
+	err = e.Execute(context.Background(), types.DiscoveryManifest{}, r, vars)
+	require.NoError(t, err)
+	require.FileExists(t, filepath.Join(tmp, "work", "installed"))
+	require.FileExists(t, filepath.Join(tmp, "work", "sub", "nested"))
+}
+
+func TestExecute_DryRunDoesNotCreateWorkingDir(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "newrelic")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	e := NewGoTaskRecipeExecutor()
+	e.DryRun = true
+
+	r := types.OpenInstallationRecipe{
+		Name:       "cwd",
+		WorkingDir: filepath.Join(tmp, "work"),
+		Install: `
+version: "3"
+tasks:
+  default:
+    cmds:
+      - echo installed > installed
+`,
+	}
+
+	err = e.Execute(context.Background(), types.DiscoveryManifest{}, r, types.RecipeVars{})
+	require.NoError(t, err)
+	require.NoDirExists(t, filepath.Join(tmp, "work"))
+}
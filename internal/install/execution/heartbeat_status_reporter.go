@@ -0,0 +1,247 @@
+package execution
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+const (
+	heartbeatFilePerm = 0600
+
+	// DefaultHeartbeatInterval is how often the heartbeat file is rewritten
+	// when no interval is given.
+	DefaultHeartbeatInterval = 10 * time.Second
+)
+
+// The phases of an install recorded in the heartbeat file.
+const (
+	HeartbeatPhaseDiscovery = "discovery"
+	HeartbeatPhaseSelection = "selection"
+	HeartbeatPhaseInstall   = "install"
+	HeartbeatPhaseComplete  = "complete"
+	HeartbeatPhaseFailed    = "failed"
+	HeartbeatPhaseCanceled  = "canceled"
+)
+
+// Heartbeat is the content of the heartbeat file, describing the progress of
+// an install in flight.  LastUpdate is refreshed periodically while the
+// installer runs, and RecipeStartedAt allows a watcher to tell a stalled recipe
+// from a stalled installer.
+type Heartbeat struct {
+	Phase           string     `json:"phase"`
+	Recipe          string     `json:"recipe,omitempty"`
+	RecipeStartedAt *time.Time `json:"recipeStartedAt,omitempty"`
+	RecipesDone     int        `json:"recipesDone"`
+	RecipesTotal    int        `json:"recipesTotal"`
+	PercentComplete int        `json:"percentComplete"`
+	Error           string     `json:"error,omitempty"`
+	PID             int        `json:"pid"`
+	CorrelationID   string     `json:"correlationId,omitempty"`
+	CampaignID      string     `json:"campaignId,omitempty"`
+	LastUpdate      time.Time  `json:"lastUpdate"`
+}
+
+// HeartbeatStatusReporter is an implementation of the StatusSubscriber
+// interface that keeps a heartbeat file up to date with the progress of the
+// install, so an external watcher can detect stalled installs.  The file is
+// rewritten on every event and at each interval in between, until the install
+// completes or is canceled.
+type HeartbeatStatusReporter struct {
+	path     string
+	interval time.Duration
+	mu       sync.Mutex
+	hb       Heartbeat
+	selected map[string]bool
+	done     map[string]bool
+	stop     chan struct{}
+}
+
+// NewHeartbeatStatusReporter returns a new instance of HeartbeatStatusReporter
+// writing to the given path at the given interval.
+func NewHeartbeatStatusReporter(path string, interval time.Duration) *HeartbeatStatusReporter {
+	if interval <= 0 {
+		interval = DefaultHeartbeatInterval
+	}
+
+	r := HeartbeatStatusReporter{
+		path:     path,
+		interval: interval,
+		hb: Heartbeat{
+			Phase: HeartbeatPhaseDiscovery,
+			PID:   os.Getpid(),
+		},
+		selected: map[string]bool{},
+		done:     map[string]bool{},
+	}
+
+	return &r
+}
+
+// Start writes the initial heartbeat and begins rewriting it periodically.
+func (r *HeartbeatStatusReporter) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stop != nil {
+		return
+	}
+
+	r.stop = make(chan struct{})
+	r.writeLocked()
+
+	go r.beat(r.stop)
+}
+
+func (r *HeartbeatStatusReporter) beat(stop chan struct{}) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			r.update(nil)
+		}
+	}
+}
+
+func (r *HeartbeatStatusReporter) DiscoveryComplete(status *InstallStatus, dm types.DiscoveryManifest) error {
+	return r.update(func(hb *Heartbeat) {
+		hb.CorrelationID = status.CorrelationID
+		hb.CampaignID = status.CampaignID
+		hb.Phase = HeartbeatPhaseSelection
+	})
+}
+
+func (r *HeartbeatStatusReporter) RecipeAvailable(status *InstallStatus, recipe types.OpenInstallationRecipe) error {
+	return nil
+}
+
+func (r *HeartbeatStatusReporter) RecipesAvailable(status *InstallStatus, recipes []types.OpenInstallationRecipe) error {
+	return nil
+}
+
+func (r *HeartbeatStatusReporter) RecipesSelected(status *InstallStatus, recipes []types.OpenInstallationRecipe) error {
+	return r.update(func(hb *Heartbeat) {
+		for _, recipe := range recipes {
+			r.selected[recipe.Name] = true
+		}
+	})
+}
+
+func (r *HeartbeatStatusReporter) RecipeInstalling(status *InstallStatus, event RecipeStatusEvent) error {
+	return r.update(func(hb *Heartbeat) {
+		now := time.Now()
+		hb.Phase = HeartbeatPhaseInstall
+		hb.Recipe = event.Recipe.Name
+		hb.RecipeStartedAt = &now
+	})
+}
+
+func (r *HeartbeatStatusReporter) RecipeInstalled(status *InstallStatus, event RecipeStatusEvent) error {
+	return r.recipeDone(event)
+}
+
+func (r *HeartbeatStatusReporter) RecipeFailed(status *InstallStatus, event RecipeStatusEvent) error {
+	return r.recipeDone(event)
+}
+
+func (r *HeartbeatStatusReporter) RecipeSkipped(status *InstallStatus, event RecipeStatusEvent) error {
+	return r.recipeDone(event)
+}
+
+func (r *HeartbeatStatusReporter) RecipeRecommended(status *InstallStatus, event RecipeStatusEvent) error {
+	return nil
+}
+
+func (r *HeartbeatStatusReporter) InstallComplete(status *InstallStatus) error {
+	return r.finish(func(hb *Heartbeat) {
+		hb.Phase = HeartbeatPhaseComplete
+		if status.Error.Message != "" {
+			hb.Phase = HeartbeatPhaseFailed
+			hb.Error = status.Error.Message
+		}
+		hb.PercentComplete = 100
+	})
+}
+
+func (r *HeartbeatStatusReporter) InstallCanceled(status *InstallStatus) error {
+	return r.finish(func(hb *Heartbeat) {
+		hb.Phase = HeartbeatPhaseCanceled
+	})
+}
+
+func (r *HeartbeatStatusReporter) recipeDone(event RecipeStatusEvent) error {
+	return r.update(func(hb *Heartbeat) {
+		if r.selected[event.Recipe.Name] {
+			r.done[event.Recipe.Name] = true
+		}
+
+		if hb.Recipe == event.Recipe.Name {
+			hb.Recipe = ""
+			hb.RecipeStartedAt = nil
+		}
+	})
+}
+
+// finish records the final state of the install and stops the periodic
+// rewrites.
+func (r *HeartbeatStatusReporter) finish(f func(*Heartbeat)) error {
+	err := r.update(func(hb *Heartbeat) {
+		hb.Recipe = ""
+		hb.RecipeStartedAt = nil
+		f(hb)
+	})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stop != nil {
+		close(r.stop)
+		r.stop = nil
+	}
+
+	return err
+}
+
+// update applies the change, when given, and rewrites the heartbeat file.
+func (r *HeartbeatStatusReporter) update(f func(*Heartbeat)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if f != nil {
+		f(&r.hb)
+
+		r.hb.RecipesTotal = len(r.selected)
+		r.hb.RecipesDone = len(r.done)
+		if r.hb.RecipesTotal > 0 && r.hb.PercentComplete < 100 {
+			r.hb.PercentComplete = r.hb.RecipesDone * 100 / r.hb.RecipesTotal
+		}
+	}
+
+	return r.writeLocked()
+}
+
+func (r *HeartbeatStatusReporter) writeLocked() error {
+	r.hb.LastUpdate = time.Now().UTC()
+
+	data, err := json.MarshalIndent(r.hb, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := utils.WriteFileAtomic(r.path, data, heartbeatFilePerm); err != nil {
+		log.Debugf("could not write heartbeat file %s: %s", r.path, err)
+		return err
+	}
+
+	return nil
+}
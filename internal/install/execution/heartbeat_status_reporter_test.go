@@ -0,0 +1,119 @@
+//go:build unit
+// +build unit
+
+package execution
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func readHeartbeat(t *testing.T, path string) Heartbeat {
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	var hb Heartbeat
+	require.NoError(t, json.Unmarshal(data, &hb))
+
+	return hb
+}
+
+func TestHeartbeatStatusReporter_interface(t *testing.T) {
+	var r StatusSubscriber = NewHeartbeatStatusReporter("", 0)
+	require.NotNil(t, r)
+}
+
+func TestHeartbeatStatusReporter_TracksProgress(t *testing.T) {
+	dir, err := ioutil.TempDir("", "heartbeat")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "heartbeat.json")
+	r := NewHeartbeatStatusReporter(path, time.Hour)
+	status := NewInstallStatus([]StatusSubscriber{r}, NewMockSuccessLinkGenerator())
+
+	r.Start()
+	hb := readHeartbeat(t, path)
+	require.Equal(t, HeartbeatPhaseDiscovery, hb.Phase)
+	require.Equal(t, os.Getpid(), hb.PID)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	infra := types.OpenInstallationRecipe{Name: "infra"}
+	logs := types.OpenInstallationRecipe{Name: "logs"}
+
+	status.DiscoveryComplete(types.DiscoveryManifest{})
+	require.Equal(t, HeartbeatPhaseSelection, readHeartbeat(t, path).Phase)
+
+	status.RecipesSelected([]types.OpenInstallationRecipe{infra, logs})
+	status.RecipeInstalling(RecipeStatusEvent{Recipe: infra})
+
+	hb = readHeartbeat(t, path)
+	require.Equal(t, HeartbeatPhaseInstall, hb.Phase)
+	require.Equal(t, "infra", hb.Recipe)
+	require.NotNil(t, hb.RecipeStartedAt)
+	require.Equal(t, 2, hb.RecipesTotal)
+	require.Equal(t, 0, hb.PercentComplete)
+	require.Equal(t, status.CorrelationID, hb.CorrelationID)
+
+	status.RecipeInstalled(RecipeStatusEvent{Recipe: infra})
+
+	hb = readHeartbeat(t, path)
+	require.Equal(t, "", hb.Recipe)
+	require.Nil(t, hb.RecipeStartedAt)
+	require.Equal(t, 1, hb.RecipesDone)
+	require.Equal(t, 50, hb.PercentComplete)
+
+	status.InstallComplete(nil)
+
+	hb = readHeartbeat(t, path)
+	require.Equal(t, HeartbeatPhaseComplete, hb.Phase)
+	require.Equal(t, 100, hb.PercentComplete)
+}
+
+func TestHeartbeatStatusReporter_RecordsFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "heartbeat")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "heartbeat.json")
+	r := NewHeartbeatStatusReporter(path, time.Hour)
+	status := NewInstallStatus([]StatusSubscriber{r}, NewMockSuccessLinkGenerator())
+
+	status.InstallComplete(errors.New("boom"))
+
+	hb := readHeartbeat(t, path)
+	require.Equal(t, HeartbeatPhaseFailed, hb.Phase)
+	require.Equal(t, "boom", hb.Error)
+}
+
+func TestHeartbeatStatusReporter_RewritesPeriodically(t *testing.T) {
+	dir, err := ioutil.TempDir("", "heartbeat")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "heartbeat.json")
+	r := NewHeartbeatStatusReporter(path, 10*time.Millisecond)
+	status := NewInstallStatus([]StatusSubscriber{r}, NewMockSuccessLinkGenerator())
+
+	r.Start()
+	first := readHeartbeat(t, path).LastUpdate
+
+	require.Eventually(t, func() bool {
+		return readHeartbeat(t, path).LastUpdate.After(first)
+	}, time.Second, 10*time.Millisecond)
+
+	status.InstallCanceled()
+	require.Equal(t, HeartbeatPhaseCanceled, readHeartbeat(t, path).Phase)
+}
@@ -14,28 +14,34 @@ import (
 
 // nolint: maligned
 type InstallStatus struct {
-	Complete             bool                    `json:"complete"`
-	DiscoveryManifest    types.DiscoveryManifest `json:"discoveryManifest"`
-	EntityGUIDs          []string                `json:"entityGuids"`
-	Error                StatusError             `json:"error"`
-	LogFilePath          string                  `json:"logFilePath"`
-	Statuses             []*RecipeStatus         `json:"recipes"`
-	Timestamp            int64                   `json:"timestamp"`
-	CLIVersion           string                  `json:"cliVersion"`
-	HasInstalledRecipes  bool                    `json:"hasInstalledRecipes"`
-	HasCanceledRecipes   bool                    `json:"hasCanceledRecipes"`
-	HasSkippedRecipes    bool                    `json:"hasSkippedRecipes"`
-	HasFailedRecipes     bool                    `json:"hasFailedRecipes"`
-	RecipesSkipped       []*RecipeStatus         `json:"recipesSkipped"`
-	RecipesCanceled      []*RecipeStatus         `json:"recipesCanceled"`
-	RecipesFailed        []*RecipeStatus         `json:"recipesFailed"`
-	RecipesInstalled     []*RecipeStatus         `json:"recipesInstalled"`
-	RedirectURL          string                  `json:"redirectUrl"`
+	Complete            bool                    `json:"complete"`
+	DiscoveryManifest   types.DiscoveryManifest `json:"discoveryManifest"`
+	EntityGUIDs         []string                `json:"entityGuids"`
+	Error               StatusError             `json:"error"`
+	LogFilePath         string                  `json:"logFilePath"`
+	Statuses            []*RecipeStatus         `json:"recipes"`
+	Timestamp           int64                   `json:"timestamp"`
+	CLIVersion          string                  `json:"cliVersion"`
+	HasInstalledRecipes bool                    `json:"hasInstalledRecipes"`
+	HasCanceledRecipes  bool                    `json:"hasCanceledRecipes"`
+	HasSkippedRecipes   bool                    `json:"hasSkippedRecipes"`
+	HasFailedRecipes    bool                    `json:"hasFailedRecipes"`
+	RecipesSkipped      []*RecipeStatus         `json:"recipesSkipped"`
+	RecipesCanceled     []*RecipeStatus         `json:"recipesCanceled"`
+	RecipesFailed       []*RecipeStatus         `json:"recipesFailed"`
+	RecipesInstalled    []*RecipeStatus         `json:"recipesInstalled"`
+	RedirectURL         string                  `json:"redirectUrl"`
+	// CampaignID labels the rollout campaign the install is part of, so the
+	// results of its installs can be sliced by campaign.
+	CampaignID string `json:"campaignId,omitempty"`
+	// CorrelationID links the events, log lines and documents of a single install run.
+	CorrelationID        string `json:"correlationId"`
 	DocumentID           string
 	targetedInstall      bool
 	statusSubscriber     []StatusSubscriber
 	successLinkConfig    types.OpenInstallationSuccessLinkConfig
 	successLinkGenerator SuccessLinkGenerator
+	secrets              *statusRedactor
 }
 
 type RecipeStatus struct {
@@ -46,6 +52,24 @@ type RecipeStatus struct {
 	EntityGUID  string           `json:"entityGuid,omitempty"`
 	// ValidationDurationMilliseconds is duration in Milliseconds that a recipe took to validate data was flowing.
 	ValidationDurationMilliseconds int64 `json:"validationDurationMilliseconds,omitempty"`
+	// AlreadyInstalled indicates the recipe was already present and reporting data.
+	AlreadyInstalled bool `json:"alreadyInstalled,omitempty"`
+	// Reinstalled indicates the recipe was already present and reporting data,
+	// and was installed again.
+	Reinstalled bool `json:"reinstalled,omitempty"`
+	// SkippedSteps are the steps of the recipe skipped because they were already complete.
+	SkippedSteps []string `json:"skippedSteps,omitempty"`
+	// Required indicates the recipe is essential rather than optional when recommended.
+	Required bool `json:"required,omitempty"`
+	// Attempts is the number of times the recipe was attempted, when retried.
+	Attempts int `json:"attempts,omitempty"`
+	// SkipReason is why the recipe was skipped, when it was.
+	SkipReason string `json:"skipReason,omitempty"`
+	// PrerequisiteOf names the recipes the recipe was added for, when it was
+	// not selected but is depended on.
+	PrerequisiteOf []string `json:"prerequisiteOf,omitempty"`
+	// Checksum is the checksum of the recipe definition the status is for.
+	Checksum string `json:"checksum,omitempty"`
 }
 
 type RecipeStatusType string
@@ -76,16 +100,35 @@ type StatusError struct {
 func NewInstallStatus(reporters []StatusSubscriber, successLinkGenerator SuccessLinkGenerator) *InstallStatus {
 	s := InstallStatus{
 		DocumentID:           uuid.New().String(),
+		CorrelationID:        uuid.New().String(),
 		Timestamp:            utils.GetTimestamp(),
 		LogFilePath:          config.DefaultConfigDirectory + "/" + config.DefaultLogFile,
 		statusSubscriber:     reporters,
 		successLinkGenerator: successLinkGenerator,
+		secrets:              newStatusRedactor(),
 	}
 
 	return &s
 }
 
+// AddSecret masks the given value in every event and error recorded from now
+// on, in addition to the sensitive variables of each recipe.
+func (s *InstallStatus) AddSecret(secret string) {
+	s.redactor().AddSecret(secret)
+}
+
+func (s *InstallStatus) redactor() *statusRedactor {
+	if s.secrets == nil {
+		s.secrets = newStatusRedactor()
+	}
+
+	return s.secrets
+}
+
+// DiscoveryComplete records the discovery manifest, with the sensitive
+// arguments of its processes masked, and passes it to the status subscribers.
 func (s *InstallStatus) DiscoveryComplete(dm types.DiscoveryManifest) {
+	dm = redactManifest(dm)
 	s.withDiscoveryInfo(dm)
 
 	for _, r := range s.statusSubscriber {
@@ -123,7 +166,16 @@ func (s *InstallStatus) RecipesSelected(recipes []types.OpenInstallationRecipe)
 	}
 }
 
+// prepareEvent redacts the event and labels it with the install run and
+// campaign it belongs to.
+func (s *InstallStatus) prepareEvent(event *RecipeStatusEvent) {
+	*event = s.redactor().RedactEvent(*event)
+	event.CorrelationID = s.CorrelationID
+	event.CampaignID = s.CampaignID
+}
+
 func (s *InstallStatus) RecipeInstalled(event RecipeStatusEvent) {
+	s.prepareEvent(&event)
 	s.withRecipeEvent(event, RecipeStatusTypes.INSTALLED)
 
 	for _, r := range s.statusSubscriber {
@@ -139,6 +191,7 @@ func (s *InstallStatus) RecipeInstalled(event RecipeStatusEvent) {
 // should consider integrating, but not something that the recipe framework
 // will currently assist with.
 func (s *InstallStatus) RecipeRecommended(event RecipeStatusEvent) {
+	s.prepareEvent(&event)
 	s.withRecipeEvent(event, RecipeStatusTypes.RECOMMENDED)
 
 	for _, r := range s.statusSubscriber {
@@ -149,6 +202,7 @@ func (s *InstallStatus) RecipeRecommended(event RecipeStatusEvent) {
 }
 
 func (s *InstallStatus) RecipeInstalling(event RecipeStatusEvent) {
+	s.prepareEvent(&event)
 	s.withRecipeEvent(event, RecipeStatusTypes.INSTALLING)
 
 	for _, r := range s.statusSubscriber {
@@ -159,6 +213,7 @@ func (s *InstallStatus) RecipeInstalling(event RecipeStatusEvent) {
 }
 
 func (s *InstallStatus) RecipeFailed(event RecipeStatusEvent) {
+	s.prepareEvent(&event)
 	s.withRecipeEvent(event, RecipeStatusTypes.FAILED)
 
 	for _, r := range s.statusSubscriber {
@@ -169,6 +224,7 @@ func (s *InstallStatus) RecipeFailed(event RecipeStatusEvent) {
 }
 
 func (s *InstallStatus) RecipeSkipped(event RecipeStatusEvent) {
+	s.prepareEvent(&event)
 	s.withRecipeEvent(event, RecipeStatusTypes.SKIPPED)
 
 	for _, r := range s.statusSubscriber {
@@ -178,6 +234,86 @@ func (s *InstallStatus) RecipeSkipped(event RecipeStatusEvent) {
 	}
 }
 
+// RecipeStepSkipped records a step of a recipe that was skipped because it was
+// already complete, notifying the subscribers that follow individual steps.
+func (s *InstallStatus) RecipeStepSkipped(event RecipeStepEvent) {
+	event.Msg = s.redactor().Mask(event.Msg)
+	event.CorrelationID = s.CorrelationID
+	event.CampaignID = s.CampaignID
+
+	log.WithFields(log.Fields{
+		"recipe_name":    event.Recipe.Name,
+		"step":           event.Step,
+		"correlation_id": s.CorrelationID,
+	}).Debug("recipe step skipped")
+
+	if found := s.getStatus(event.Recipe); found != nil {
+		found.SkippedSteps = append(found.SkippedSteps, event.Step)
+	}
+
+	for _, r := range s.statusSubscriber {
+		sr, ok := r.(StepStatusSubscriber)
+		if !ok {
+			continue
+		}
+
+		if err := sr.RecipeStepSkipped(s, event); err != nil {
+			log.Errorf("Error writing step status for recipe %s: %s", event.Recipe.Name, err)
+		}
+	}
+}
+
+// RecipePrerequisiteAdded records a recipe added to the install because the
+// dependent recipe depends on it.
+func (s *InstallStatus) RecipePrerequisiteAdded(recipe types.OpenInstallationRecipe, dependent types.OpenInstallationRecipe) {
+	log.WithFields(log.Fields{
+		"recipe_name":    recipe.Name,
+		"dependent":      dependent.Name,
+		"correlation_id": s.CorrelationID,
+	}).Debug("recipe prerequisite added")
+
+	found := s.getStatus(recipe)
+	if found == nil {
+		s.withAvailableRecipe(recipe)
+		found = s.getStatus(recipe)
+	}
+
+	if !utils.StringInSlice(dependent.Name, found.PrerequisiteOf) {
+		found.PrerequisiteOf = append(found.PrerequisiteOf, dependent.Name)
+	}
+}
+
+// RecipeRetrying records a recipe about to be retried, notifying the
+// subscribers that follow retries.
+func (s *InstallStatus) RecipeRetrying(event RecipeRetryEvent) {
+	event.Msg = s.redactor().Mask(event.Msg)
+	event.CorrelationID = s.CorrelationID
+	event.CampaignID = s.CampaignID
+
+	log.WithFields(log.Fields{
+		"recipe_name":    event.Recipe.Name,
+		"attempt":        event.Attempt,
+		"max_attempts":   event.MaxAttempts,
+		"exit_code":      event.ExitCode,
+		"correlation_id": s.CorrelationID,
+	}).Debug("recipe retrying")
+
+	if found := s.getStatus(event.Recipe); found != nil {
+		found.Attempts = event.Attempt
+	}
+
+	for _, r := range s.statusSubscriber {
+		rr, ok := r.(RetryStatusSubscriber)
+		if !ok {
+			continue
+		}
+
+		if err := rr.RecipeRetrying(s, event); err != nil {
+			log.Errorf("Error writing retry status for recipe %s: %s", event.Recipe.Name, err)
+		}
+	}
+}
+
 func (s *InstallStatus) InstallComplete(err error) {
 	s.completed(err)
 
@@ -299,18 +435,26 @@ func (s *InstallStatus) withRecipeEvent(e RecipeStatusEvent, rs RecipeStatusType
 
 	s.Error = statusError
 
+	skipReason := ""
+	if rs == RecipeStatusTypes.SKIPPED {
+		skipReason = e.Msg
+	}
+
 	log.WithFields(log.Fields{
 		"recipe_name":                    e.Recipe.Name,
 		"status":                         rs,
 		"error":                          statusError.Message,
 		"guid":                           e.EntityGUID,
 		"validationDurationMilliseconds": e.ValidationDurationMilliseconds,
+		"required":                       e.Recipe.IsRequired(),
+		"correlation_id":                 s.CorrelationID,
 	}).Debug("recipe event")
 
 	found := s.getStatus(e.Recipe)
 
 	if found != nil {
 		found.Status = rs
+		found.Error = statusError
 
 		if e.EntityGUID != "" {
 			found.EntityGUID = e.EntityGUID
@@ -319,12 +463,22 @@ func (s *InstallStatus) withRecipeEvent(e RecipeStatusEvent, rs RecipeStatusType
 		if e.ValidationDurationMilliseconds > 0 {
 			found.ValidationDurationMilliseconds = e.ValidationDurationMilliseconds
 		}
+
+		found.AlreadyInstalled = e.AlreadyInstalled
+		found.Reinstalled = found.Reinstalled || e.Reinstalled
+		found.SkipReason = skipReason
+		found.Checksum = e.Recipe.Checksum()
 	} else {
 		recipeStatus := &RecipeStatus{
-			Name:        e.Recipe.Name,
-			DisplayName: e.Recipe.DisplayName,
-			Status:      rs,
-			Error:       statusError,
+			Name:             e.Recipe.Name,
+			DisplayName:      e.Recipe.DisplayName,
+			Status:           rs,
+			Error:            statusError,
+			AlreadyInstalled: e.AlreadyInstalled,
+			Reinstalled:      e.Reinstalled,
+			Required:         e.Recipe.IsRequired(),
+			SkipReason:       skipReason,
+			Checksum:         e.Recipe.Checksum(),
 		}
 
 		if e.EntityGUID != "" {
@@ -347,7 +501,7 @@ func (s *InstallStatus) completed(err error) {
 
 	if err != nil {
 		statusError := StatusError{
-			Message: err.Error(),
+			Message: s.redactor().Mask(err.Error()),
 		}
 		s.Error = statusError
 	}
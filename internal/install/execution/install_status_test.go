@@ -1,8 +1,11 @@
+//go:build unit
 // +build unit
 
 package execution
 
 import (
+	"encoding/json"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -15,6 +18,72 @@ func TestNewInstallStatus(t *testing.T) {
 	s := NewInstallStatus([]StatusSubscriber{}, slg)
 	require.NotEmpty(t, s.Timestamp)
 	require.NotEmpty(t, s.DocumentID)
+	require.NotEmpty(t, s.CorrelationID)
+	require.NotEqual(t, s.DocumentID, s.CorrelationID)
+}
+
+func TestInstallStatus_CorrelationIDPropagatesToReporters(t *testing.T) {
+	reporter := NewMockStatusReporter()
+	s := NewInstallStatus([]StatusSubscriber{reporter}, NewConcreteSuccessLinkGenerator())
+	r := types.OpenInstallationRecipe{Name: "test"}
+
+	s.RecipeInstalling(RecipeStatusEvent{Recipe: r})
+	s.RecipeStepSkipped(RecipeStepEvent{Recipe: r, Step: "configure"})
+	s.RecipeInstalled(RecipeStatusEvent{Recipe: r})
+	s.RecipeFailed(RecipeStatusEvent{Recipe: r, CorrelationID: "stale"})
+	s.RecipeSkipped(RecipeStatusEvent{Recipe: r})
+	s.RecipeRecommended(RecipeStatusEvent{Recipe: r})
+
+	require.Len(t, reporter.CorrelationIDs, 6)
+	for _, id := range reporter.CorrelationIDs {
+		require.Equal(t, s.CorrelationID, id)
+	}
+}
+
+func TestInstallStatus_CampaignIDPropagatesToReporters(t *testing.T) {
+	reporter := NewMockStatusReporter()
+	s := NewInstallStatus([]StatusSubscriber{reporter}, NewConcreteSuccessLinkGenerator())
+	s.CampaignID = "rollout-1"
+	r := types.OpenInstallationRecipe{Name: "test"}
+
+	s.RecipeInstalling(RecipeStatusEvent{Recipe: r})
+	s.RecipeStepSkipped(RecipeStepEvent{Recipe: r, Step: "configure"})
+	s.RecipeInstalled(RecipeStatusEvent{Recipe: r})
+	s.RecipeFailed(RecipeStatusEvent{Recipe: r, CampaignID: "stale"})
+	s.RecipeSkipped(RecipeStatusEvent{Recipe: r})
+	s.RecipeRecommended(RecipeStatusEvent{Recipe: r})
+
+	require.Len(t, reporter.CampaignIDs, 6)
+	for _, id := range reporter.CampaignIDs {
+		require.Equal(t, "rollout-1", id)
+	}
+
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"campaignId":"rollout-1"`)
+}
+
+func TestStatusWithRecipeEvent_Required(t *testing.T) {
+	s := NewInstallStatus([]StatusSubscriber{}, NewConcreteSuccessLinkGenerator())
+	s.withAvailableRecipes([]types.OpenInstallationRecipe{
+		{Name: "required", Requirement: types.OpenInstallationRequirementTypes.REQUIRED},
+		{Name: "optional"},
+	})
+
+	require.True(t, s.Statuses[0].Required)
+	require.False(t, s.Statuses[1].Required)
+}
+
+func TestStatusWithRecipeEvent_Reinstalled(t *testing.T) {
+	s := NewInstallStatus([]StatusSubscriber{}, NewConcreteSuccessLinkGenerator())
+	r := types.OpenInstallationRecipe{Name: "testRecipe"}
+
+	s.withRecipeEvent(RecipeStatusEvent{Recipe: r, Reinstalled: true}, RecipeStatusTypes.INSTALLING)
+	s.withRecipeEvent(RecipeStatusEvent{Recipe: r, Msg: "validation failed"}, RecipeStatusTypes.FAILED)
+
+	require.Len(t, s.Statuses, 1)
+	require.True(t, s.Statuses[0].Reinstalled)
+	require.False(t, s.Statuses[0].AlreadyInstalled)
 }
 
 func TestStatusWithAvailableRecipes_Basic(t *testing.T) {
@@ -213,3 +282,38 @@ func TestInstallStatus_multipleRecipeStatuses(t *testing.T) {
 	require.True(t, s.HasCanceledRecipes)
 	require.True(t, s.HasFailedRecipes)
 }
+
+func TestInstallStatus_RecipeStepSkipped(t *testing.T) {
+	reporter := NewMockStatusReporter()
+	s := NewInstallStatus([]StatusSubscriber{reporter, NewTerminalStatusReporter()}, NewConcreteSuccessLinkGenerator())
+	r := types.OpenInstallationRecipe{Name: "test"}
+
+	s.RecipeInstalling(RecipeStatusEvent{Recipe: r})
+	s.RecipeStepSkipped(RecipeStepEvent{Recipe: r, Step: "configure", Msg: stepCheckPassedMsg})
+
+	require.Equal(t, []string{"configure"}, s.Statuses[0].SkippedSteps)
+	require.Equal(t, 1, reporter.RecipeStepSkippedCallCount)
+	require.Equal(t, []string{"configure"}, reporter.SkippedSteps)
+}
+
+func TestStepMarkerPath(t *testing.T) {
+	require.Equal(t, filepath.Join("markers", "my_recipe", "install_v1.0"), StepMarkerPath("markers", "my/recipe", "install v1.0"))
+}
+
+func TestStatusWithRecipeEvent_SkipReason(t *testing.T) {
+	s := NewInstallStatus([]StatusSubscriber{}, NewConcreteSuccessLinkGenerator())
+	r := types.OpenInstallationRecipe{Name: "testRecipe"}
+
+	s.RecipeSkipped(RecipeStatusEvent{Recipe: r, Msg: "not selected"})
+	require.Equal(t, "not selected", s.Statuses[0].SkipReason)
+
+	s.InstallComplete(nil)
+	require.Len(t, s.RecipesSkipped, 1)
+
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"skipReason":"not selected"`)
+
+	s.withRecipeEvent(RecipeStatusEvent{Recipe: r}, RecipeStatusTypes.INSTALLED)
+	require.Empty(t, s.Statuses[0].SkipReason)
+}
@@ -0,0 +1,47 @@
+package execution
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// LoadInstalledRecipes returns the recipes recorded as installed by the
+// summaries of the install runs under baseDir, by name.  Runs are read in the
+// order they were made, so that the most recent install of a recipe is kept.
+// A recipe that failed in a later run is still considered installed.
+func LoadInstalledRecipes(baseDir string) (map[string]*RecipeStatus, error) {
+	paths, err := filepath.Glob(filepath.Join(baseDir, "install-*", summaryArtifactFile))
+	if err != nil {
+		return nil, err
+	}
+
+	// Run directories are named after the time of the run.
+	sort.Strings(paths)
+
+	installed := map[string]*RecipeStatus{}
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read install summary: %s", err)
+		}
+
+		var status InstallStatus
+		if err := json.Unmarshal(data, &status); err != nil {
+			log.Debugf("ignoring unreadable install summary %s: %s", path, err)
+			continue
+		}
+
+		for _, rs := range status.Statuses {
+			if rs.Status == RecipeStatusTypes.INSTALLED {
+				installed[rs.Name] = rs
+			}
+		}
+	}
+
+	return installed, nil
+}
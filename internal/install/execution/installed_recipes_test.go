@@ -0,0 +1,56 @@
+//go:build unit
+// +build unit
+
+package execution
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestSummary(t *testing.T, dir string, run string, statuses ...*RecipeStatus) {
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, run), 0700))
+
+	data, err := json.Marshal(InstallStatus{Statuses: statuses})
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, run, "summary.json"), data, 0600))
+}
+
+func TestLoadInstalledRecipes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "installed")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeTestSummary(t, dir, "install-20210601-130405",
+		&RecipeStatus{Name: "infra", Status: RecipeStatusTypes.INSTALLED, Checksum: "old"},
+		&RecipeStatus{Name: "mysql", Status: RecipeStatusTypes.INSTALLED, Checksum: "a"},
+		&RecipeStatus{Name: "nginx", Status: RecipeStatusTypes.FAILED},
+	)
+	writeTestSummary(t, dir, "install-20210602-090000",
+		&RecipeStatus{Name: "infra", Status: RecipeStatusTypes.INSTALLED, Checksum: "new"},
+		&RecipeStatus{Name: "mysql", Status: RecipeStatusTypes.FAILED, Checksum: "b"},
+	)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "install-20210603-090000"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "install-20210603-090000", "summary.json"), []byte("{"), 0600))
+
+	installed, err := LoadInstalledRecipes(dir)
+	require.NoError(t, err)
+	require.Len(t, installed, 2)
+	require.Equal(t, "new", installed["infra"].Checksum)
+	require.Equal(t, "a", installed["mysql"].Checksum)
+}
+
+func TestLoadInstalledRecipes_NoRuns(t *testing.T) {
+	dir, err := ioutil.TempDir("", "installed")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	installed, err := LoadInstalledRecipes(dir)
+	require.NoError(t, err)
+	require.Empty(t, installed)
+}
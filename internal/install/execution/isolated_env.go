@@ -0,0 +1,116 @@
+package execution
+
+import (
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// isolatedEnvAllowlist are the variables of the invoking environment kept
+// when a recipe runs in an isolated environment, besides those named after
+// recipe variables.  Names are matched without regard to case.
+var isolatedEnvAllowlist = []string{
+	"PATH",
+	"HTTP_PROXY",
+	"HTTPS_PROXY",
+	"NO_PROXY",
+	"ALL_PROXY",
+	"LANG",
+	"LC_ALL",
+	"TERM",
+	"TZ",
+	"USER",
+	"LOGNAME",
+	// Needed by Windows to run programs at all.
+	"COMSPEC",
+	"PATHEXT",
+	"SYSTEMDRIVE",
+	"SYSTEMROOT",
+	"WINDIR",
+}
+
+// isolatedHomeVars returns the variables pointed at the temporary home
+// directory on the given operating system.
+func isolatedHomeVars(goos string) []string {
+	if goos == "windows" {
+		return []string{"HOME", "USERPROFILE", "APPDATA", "LOCALAPPDATA", "TEMP", "TMP"}
+	}
+
+	return []string{"HOME", "TMPDIR"}
+}
+
+// isolateEnv replaces the environment of the process, which recipe steps
+// inherit, with a clean one whose home is a new temporary directory.  Only
+// allowlisted variables and those named after recipe variables are kept.  The
+// returned function restores the original environment and removes the
+// temporary home.
+func (re *GoTaskRecipeExecutor) isolateEnv(recipeName string, recipeVars types.RecipeVars) (func(), error) {
+	home, err := ioutil.TempDir("", "newrelic-home-")
+	if err != nil {
+		return nil, err
+	}
+	re.Cleanup.Register(home)
+
+	original := os.Environ()
+	kept := isolatedEnviron(original, recipeVars)
+
+	log.WithFields(log.Fields{
+		"name": recipeName,
+		"home": home,
+	}).Debug("running recipe in an isolated environment")
+
+	os.Clearenv()
+	setEnviron(kept)
+	for _, k := range isolatedHomeVars(runtime.GOOS) {
+		os.Setenv(k, home)
+	}
+
+	return func() {
+		os.Clearenv()
+		setEnviron(original)
+		re.Cleanup.Release(home)
+	}, nil
+}
+
+// isolatedEnviron returns the entries of environ an isolated recipe keeps.
+func isolatedEnviron(environ []string, recipeVars types.RecipeVars) []string {
+	kept := []string{}
+	for _, kv := range environ {
+		name := strings.SplitN(kv, "=", 2)[0]
+		if isAllowedEnvVar(name, recipeVars) {
+			kept = append(kept, kv)
+		}
+	}
+
+	return kept
+}
+
+func isAllowedEnvVar(name string, recipeVars types.RecipeVars) bool {
+	if _, ok := recipeVars[name]; ok {
+		return true
+	}
+
+	for _, a := range isolatedEnvAllowlist {
+		if strings.EqualFold(name, a) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func setEnviron(environ []string) {
+	for _, kv := range environ {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			continue
+		}
+
+		os.Setenv(parts[0], parts[1])
+	}
+}
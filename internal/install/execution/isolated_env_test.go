@@ -0,0 +1,34 @@
+// +build unit
+
+package execution
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestIsolatedEnviron(t *testing.T) {
+	environ := []string{
+		"PATH=/usr/bin:/bin",
+		"HOME=/home/admin",
+		"AWS_SECRET_ACCESS_KEY=secret",
+		"https_proxy=http://proxy:3128",
+		"NEW_RELIC_LOG_LEVEL=debug",
+		"=C:=C:\\",
+	}
+
+	kept := isolatedEnviron(environ, types.RecipeVars{"NEW_RELIC_LOG_LEVEL": "debug"})
+	require.Equal(t, []string{
+		"PATH=/usr/bin:/bin",
+		"https_proxy=http://proxy:3128",
+		"NEW_RELIC_LOG_LEVEL=debug",
+	}, kept)
+}
+
+func TestIsolatedHomeVars(t *testing.T) {
+	require.Equal(t, []string{"HOME", "TMPDIR"}, isolatedHomeVars("linux"))
+	require.Contains(t, isolatedHomeVars("windows"), "USERPROFILE")
+}
@@ -0,0 +1,249 @@
+package execution
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+const (
+	// JSONOutputStdout is the path that writes the event stream to standard
+	// output rather than to a file.
+	JSONOutputStdout = "-"
+
+	jsonOutputFilePerm = 0644
+)
+
+// Events of the JSON event stream, named after the StatusSubscriber methods
+// they are written from.
+const (
+	jsonEventDiscoveryComplete = "DISCOVERY_COMPLETE"
+	jsonEventRecipeAvailable   = "RECIPE_AVAILABLE"
+	jsonEventRecipesAvailable  = "RECIPES_AVAILABLE"
+	jsonEventRecipesSelected   = "RECIPES_SELECTED"
+	jsonEventStepSkipped       = "STEP_SKIPPED"
+	jsonEventRetrying          = "RETRYING"
+	jsonEventInstallComplete   = "INSTALL_COMPLETE"
+	jsonEventInstallCanceled   = "INSTALL_CANCELED"
+)
+
+// JSONStatusReporter is an implementation of the StatusSubscriber interface
+// that writes each status event of the install as a line of JSON, so that the
+// install can later be replayed with ReplayStatusEvents.  The events carry
+// what the other status subscribers report from, with sensitive values
+// already masked.
+type JSONStatusReporter struct {
+	path   string
+	stdout io.Writer
+
+	mu      sync.Mutex
+	started bool
+}
+
+// jsonStatusEvent is a line of the JSON event stream.
+type jsonStatusEvent struct {
+	Timestamp        int64                    `json:"timestamp"`
+	Event            string                   `json:"event"`
+	Recipe           *jsonEventRecipe         `json:"recipe,omitempty"`
+	Recipes          []jsonEventRecipe        `json:"recipes,omitempty"`
+	Manifest         *types.DiscoveryManifest `json:"manifest,omitempty"`
+	Step             string                   `json:"step,omitempty"`
+	Msg              string                   `json:"msg,omitempty"`
+	EntityGUID       string                   `json:"entityGuid,omitempty"`
+	ValidationMillis int64                    `json:"validationDurationMilliseconds,omitempty"`
+	AlreadyInstalled bool                     `json:"alreadyInstalled,omitempty"`
+	Reinstalled      bool                     `json:"reinstalled,omitempty"`
+	ValidationFailed bool                     `json:"validationFailed,omitempty"`
+	RecipeVars       types.RecipeVars         `json:"recipeVars,omitempty"`
+	Attempt          int                      `json:"attempt,omitempty"`
+	MaxAttempts      int                      `json:"maxAttempts,omitempty"`
+	ExitCode         int                      `json:"exitCode,omitempty"`
+	Error            string                   `json:"error,omitempty"`
+	LogFilePath      string                   `json:"logFilePath,omitempty"`
+	CorrelationID    string                   `json:"correlationId,omitempty"`
+	CampaignID       string                   `json:"campaignId,omitempty"`
+}
+
+// jsonEventRecipe holds the parts of a recipe the status subscribers report
+// from, leaving out its install steps.
+type jsonEventRecipe struct {
+	Name              string                                         `json:"name"`
+	DisplayName       string                                         `json:"displayName,omitempty"`
+	Requirement       types.OpenInstallationRequirement              `json:"requirement,omitempty"`
+	PreInstall        types.OpenInstallationPreInstallConfiguration  `json:"preInstall,omitempty"`
+	PostInstall       types.OpenInstallationPostInstallConfiguration `json:"postInstall,omitempty"`
+	SuccessLinkConfig types.OpenInstallationSuccessLinkConfig        `json:"successLinkConfig,omitempty"`
+}
+
+func newJSONEventRecipe(r types.OpenInstallationRecipe) jsonEventRecipe {
+	return jsonEventRecipe{
+		Name:              r.Name,
+		DisplayName:       r.DisplayName,
+		Requirement:       r.Requirement,
+		PreInstall:        r.PreInstall,
+		PostInstall:       r.PostInstall,
+		SuccessLinkConfig: r.SuccessLinkConfig,
+	}
+}
+
+func (r jsonEventRecipe) recipe() types.OpenInstallationRecipe {
+	return types.OpenInstallationRecipe{
+		Name:              r.Name,
+		DisplayName:       r.DisplayName,
+		Requirement:       r.Requirement,
+		PreInstall:        r.PreInstall,
+		PostInstall:       r.PostInstall,
+		SuccessLinkConfig: r.SuccessLinkConfig,
+	}
+}
+
+func newJSONEventRecipes(recipes []types.OpenInstallationRecipe) []jsonEventRecipe {
+	rs := make([]jsonEventRecipe, 0, len(recipes))
+	for _, r := range recipes {
+		rs = append(rs, newJSONEventRecipe(r))
+	}
+
+	return rs
+}
+
+// NewJSONStatusReporter returns a new instance of JSONStatusReporter writing to
+// the given file, which is replaced, or to standard output when the path is
+// JSONOutputStdout.
+func NewJSONStatusReporter(path string) *JSONStatusReporter {
+	r := JSONStatusReporter{
+		path:   path,
+		stdout: os.Stdout,
+	}
+
+	return &r
+}
+
+func (r *JSONStatusReporter) DiscoveryComplete(status *InstallStatus, dm types.DiscoveryManifest) error {
+	return r.write(status, jsonStatusEvent{Event: jsonEventDiscoveryComplete, Manifest: &dm})
+}
+
+func (r *JSONStatusReporter) RecipeAvailable(status *InstallStatus, recipe types.OpenInstallationRecipe) error {
+	er := newJSONEventRecipe(recipe)
+	return r.write(status, jsonStatusEvent{Event: jsonEventRecipeAvailable, Recipe: &er})
+}
+
+func (r *JSONStatusReporter) RecipesAvailable(status *InstallStatus, recipes []types.OpenInstallationRecipe) error {
+	return r.write(status, jsonStatusEvent{Event: jsonEventRecipesAvailable, Recipes: newJSONEventRecipes(recipes)})
+}
+
+func (r *JSONStatusReporter) RecipesSelected(status *InstallStatus, recipes []types.OpenInstallationRecipe) error {
+	return r.write(status, jsonStatusEvent{Event: jsonEventRecipesSelected, Recipes: newJSONEventRecipes(recipes)})
+}
+
+func (r *JSONStatusReporter) RecipeFailed(status *InstallStatus, event RecipeStatusEvent) error {
+	return r.writeRecipeEvent(status, RecipeStatusTypes.FAILED, event)
+}
+
+func (r *JSONStatusReporter) RecipeInstalled(status *InstallStatus, event RecipeStatusEvent) error {
+	return r.writeRecipeEvent(status, RecipeStatusTypes.INSTALLED, event)
+}
+
+func (r *JSONStatusReporter) RecipeInstalling(status *InstallStatus, event RecipeStatusEvent) error {
+	return r.writeRecipeEvent(status, RecipeStatusTypes.INSTALLING, event)
+}
+
+func (r *JSONStatusReporter) RecipeRecommended(status *InstallStatus, event RecipeStatusEvent) error {
+	return r.writeRecipeEvent(status, RecipeStatusTypes.RECOMMENDED, event)
+}
+
+func (r *JSONStatusReporter) RecipeSkipped(status *InstallStatus, event RecipeStatusEvent) error {
+	return r.writeRecipeEvent(status, RecipeStatusTypes.SKIPPED, event)
+}
+
+func (r *JSONStatusReporter) RecipeStepSkipped(status *InstallStatus, event RecipeStepEvent) error {
+	er := newJSONEventRecipe(event.Recipe)
+	return r.write(status, jsonStatusEvent{
+		Event:  jsonEventStepSkipped,
+		Recipe: &er,
+		Step:   event.Step,
+		Msg:    event.Msg,
+	})
+}
+
+func (r *JSONStatusReporter) RecipeRetrying(status *InstallStatus, event RecipeRetryEvent) error {
+	er := newJSONEventRecipe(event.Recipe)
+	return r.write(status, jsonStatusEvent{
+		Event:       jsonEventRetrying,
+		Recipe:      &er,
+		Msg:         event.Msg,
+		Attempt:     event.Attempt,
+		MaxAttempts: event.MaxAttempts,
+		ExitCode:    event.ExitCode,
+	})
+}
+
+func (r *JSONStatusReporter) InstallComplete(status *InstallStatus) error {
+	return r.write(status, jsonStatusEvent{
+		Event:       jsonEventInstallComplete,
+		Error:       status.Error.Message,
+		LogFilePath: status.LogFilePath,
+	})
+}
+
+func (r *JSONStatusReporter) InstallCanceled(status *InstallStatus) error {
+	return r.write(status, jsonStatusEvent{
+		Event:       jsonEventInstallCanceled,
+		LogFilePath: status.LogFilePath,
+	})
+}
+
+func (r *JSONStatusReporter) writeRecipeEvent(status *InstallStatus, rs RecipeStatusType, event RecipeStatusEvent) error {
+	er := newJSONEventRecipe(event.Recipe)
+	return r.write(status, jsonStatusEvent{
+		Event:            string(rs),
+		Recipe:           &er,
+		Msg:              event.Msg,
+		EntityGUID:       event.EntityGUID,
+		ValidationMillis: event.ValidationDurationMilliseconds,
+		AlreadyInstalled: event.AlreadyInstalled,
+		Reinstalled:      event.Reinstalled,
+		ValidationFailed: event.ValidationFailed,
+		RecipeVars:       event.RecipeVars,
+	})
+}
+
+// write appends the event to the stream.  The file is replaced by the first
+// event, so that it only holds the events of this install.
+func (r *JSONStatusReporter) write(status *InstallStatus, e jsonStatusEvent) error {
+	e.Timestamp = time.Now().UnixNano() / int64(time.Millisecond)
+	e.CorrelationID = status.CorrelationID
+	e.CampaignID = status.CampaignID
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.path == JSONOutputStdout {
+		_, err = r.stdout.Write(line)
+		return err
+	}
+
+	flags := os.O_APPEND | os.O_CREATE | os.O_WRONLY
+	if !r.started {
+		flags |= os.O_TRUNC
+	}
+
+	f, err := os.OpenFile(r.path, flags, jsonOutputFilePerm)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r.started = true
+
+	_, err = f.Write(line)
+	return err
+}
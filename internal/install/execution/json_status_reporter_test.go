@@ -0,0 +1,169 @@
+//go:build unit
+// +build unit
+
+package execution
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestJSONStatusReporter_interface(t *testing.T) {
+	var r StatusSubscriber = NewJSONStatusReporter("")
+	require.NotNil(t, r)
+}
+
+// recordInstall runs a small install through the given status.
+func recordInstall(s *InstallStatus) {
+	installed := types.OpenInstallationRecipe{
+		Name:        "installed",
+		DisplayName: "Installed Recipe",
+		PostInstall: types.OpenInstallationPostInstallConfiguration{Info: "Installed on {{.Manifest.Hostname}}"},
+	}
+	failed := types.OpenInstallationRecipe{Name: "failed", Requirement: types.OpenInstallationRequirementTypes.REQUIRED}
+	skipped := types.OpenInstallationRecipe{Name: "skipped"}
+
+	s.DiscoveryComplete(types.DiscoveryManifest{Hostname: "web-1", OS: "linux"})
+	s.RecipesAvailable([]types.OpenInstallationRecipe{installed, failed, skipped})
+	s.RecipesSelected([]types.OpenInstallationRecipe{installed, failed})
+	s.RecipeInstalling(RecipeStatusEvent{Recipe: installed})
+	s.RecipeStepSkipped(RecipeStepEvent{Recipe: installed, Step: "configure", Msg: "already complete"})
+	s.RecipeInstalled(RecipeStatusEvent{Recipe: installed, EntityGUID: "GUID-1", ValidationDurationMilliseconds: 1500})
+	s.RecipeInstalling(RecipeStatusEvent{Recipe: failed})
+	s.RecipeRetrying(RecipeRetryEvent{Recipe: failed, Attempt: 2, MaxAttempts: 3, ExitCode: 75, Msg: "apt lock held"})
+	s.RecipeFailed(RecipeStatusEvent{Recipe: failed, Msg: "exit status 1", RecipeVars: types.RecipeVars{"NEW_RELIC_API_KEY": "NRAK-SECRET"}})
+	s.RecipeSkipped(RecipeStatusEvent{Recipe: skipped, Msg: "conflict"})
+	s.InstallComplete(errors.New("one or more recipes failed"))
+}
+
+func TestJSONStatusReporter_WritesEventPerLine(t *testing.T) {
+	dir, err := ioutil.TempDir("", "json-output")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "events.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte("stale\n"), 0644))
+
+	s := NewInstallStatus([]StatusSubscriber{NewJSONStatusReporter(path)}, NewMockSuccessLinkGenerator())
+	s.CampaignID = "fleet-rollout"
+	recordInstall(s)
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 11)
+
+	var events []jsonStatusEvent
+	for _, l := range lines {
+		var e jsonStatusEvent
+		require.NoError(t, json.Unmarshal([]byte(l), &e))
+		require.Equal(t, s.CorrelationID, e.CorrelationID)
+		require.Equal(t, "fleet-rollout", e.CampaignID)
+		events = append(events, e)
+	}
+
+	require.Equal(t, jsonEventDiscoveryComplete, events[0].Event)
+	require.Equal(t, "web-1", events[0].Manifest.Hostname)
+	require.Equal(t, "FAILED", events[8].Event)
+	require.NotContains(t, string(data), "NRAK-SECRET")
+	require.Equal(t, jsonEventInstallComplete, events[10].Event)
+	require.Equal(t, "one or more recipes failed", events[10].Error)
+}
+
+func TestReplayStatusEvents_RoundTrip(t *testing.T) {
+	original := &bytes.Buffer{}
+	recorder := NewJSONStatusReporter(JSONOutputStdout)
+	recorder.stdout = original
+
+	s := NewInstallStatus([]StatusSubscriber{recorder}, NewMockSuccessLinkGenerator())
+	s.CampaignID = "fleet-rollout"
+	recordInstall(s)
+
+	replayed := &bytes.Buffer{}
+	rerecorder := NewJSONStatusReporter(JSONOutputStdout)
+	rerecorder.stdout = replayed
+	mock := NewMockStatusReporter()
+
+	r := NewInstallStatus([]StatusSubscriber{rerecorder, mock}, NewMockSuccessLinkGenerator())
+	require.NoError(t, ReplayStatusEvents(bytes.NewReader(original.Bytes()), r))
+
+	require.Equal(t, s.CorrelationID, r.CorrelationID)
+	require.Equal(t, "fleet-rollout", r.CampaignID)
+	require.Equal(t, "web-1", r.DiscoveryManifest.Hostname)
+	require.Equal(t, "one or more recipes failed", r.Error.Message)
+	require.True(t, r.HasInstalledRecipes)
+	require.True(t, r.HasFailedRecipes)
+	require.True(t, r.HasSkippedRecipes)
+	require.Equal(t, []string{"GUID-1"}, r.EntityGUIDs)
+
+	require.Equal(t, 1, mock.DiscoveryCompleteCallCount)
+	require.Equal(t, 2, mock.RecipeInstallingCallCount)
+	require.Equal(t, 1, mock.RecipeInstalledCallCount)
+	require.Equal(t, 1, mock.RecipeFailedCallCount)
+	require.Equal(t, 1, mock.RecipeSkippedCallCount)
+	require.Equal(t, 1, mock.RecipeStepSkippedCallCount)
+	require.Equal(t, 1, mock.RecipeRetryingCallCount)
+	require.Equal(t, 1, mock.InstallCompleteCallCount)
+
+	require.Equal(t, withoutTimestamps(t, original.String()), withoutTimestamps(t, replayed.String()))
+}
+
+func TestReplayStatusEvents_RendersThroughJUnit(t *testing.T) {
+	events := &bytes.Buffer{}
+	recorder := NewJSONStatusReporter(JSONOutputStdout)
+	recorder.stdout = events
+	recordInstall(NewInstallStatus([]StatusSubscriber{recorder}, NewMockSuccessLinkGenerator()))
+
+	dir, err := ioutil.TempDir("", "render")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "install.xml")
+	r := NewInstallStatus([]StatusSubscriber{NewJUnitStatusReporter(path)}, NewMockSuccessLinkGenerator())
+	require.NoError(t, ReplayStatusEvents(events, r))
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `name="Installed Recipe"`)
+	require.Contains(t, string(data), "exit status 1")
+}
+
+func TestReplayStatusEvents_Errors(t *testing.T) {
+	s := NewInstallStatus([]StatusSubscriber{}, NewMockSuccessLinkGenerator())
+
+	err := ReplayStatusEvents(strings.NewReader("{\"event\":\"DISCOVERY_COMPLETE\"}\n"), s)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "line 1")
+
+	err = ReplayStatusEvents(strings.NewReader("\n{\"event\":\"INSTALLED\"}\n"), s)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "line 2")
+
+	err = ReplayStatusEvents(strings.NewReader("not json\n"), s)
+	require.Error(t, err)
+
+	require.NoError(t, ReplayStatusEvents(strings.NewReader("{\"event\":\"FUTURE_EVENT\"}\n"), s))
+}
+
+func withoutTimestamps(t *testing.T, stream string) []jsonStatusEvent {
+	var events []jsonStatusEvent
+	for _, l := range strings.Split(strings.TrimSpace(stream), "\n") {
+		var e jsonStatusEvent
+		require.NoError(t, json.Unmarshal([]byte(l), &e))
+		e.Timestamp = 0
+		events = append(events, e)
+	}
+
+	return events
+}
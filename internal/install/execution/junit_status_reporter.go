@@ -0,0 +1,244 @@
+package execution
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+const (
+	junitSuiteName = "newrelic-install"
+	junitFilePerm  = 0644
+)
+
+// JUnitStatusReporter is an implementation of the StatusSubscriber interface
+// that writes the outcome of the install as a JUnit XML report, in which each
+// recipe is a test case.  The report is written once the install completes or
+// is canceled.
+type JUnitStatusReporter struct {
+	path    string
+	start   time.Time
+	mu      sync.Mutex
+	started map[string]time.Time
+	elapsed map[string]time.Duration
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Hostname  string          `xml:"hostname,attr,omitempty"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Details string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// NewJUnitStatusReporter returns a new instance of JUnitStatusReporter writing
+// to the given file.
+func NewJUnitStatusReporter(path string) *JUnitStatusReporter {
+	r := JUnitStatusReporter{
+		path:    path,
+		start:   time.Now(),
+		started: map[string]time.Time{},
+		elapsed: map[string]time.Duration{},
+	}
+
+	return &r
+}
+
+func (r *JUnitStatusReporter) RecipeInstalling(status *InstallStatus, event RecipeStatusEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.started[event.Recipe.Name] = time.Now()
+
+	return nil
+}
+
+func (r *JUnitStatusReporter) RecipeInstalled(status *InstallStatus, event RecipeStatusEvent) error {
+	r.recipeFinished(event.Recipe.Name)
+	return nil
+}
+
+func (r *JUnitStatusReporter) RecipeFailed(status *InstallStatus, event RecipeStatusEvent) error {
+	r.recipeFinished(event.Recipe.Name)
+	return nil
+}
+
+func (r *JUnitStatusReporter) RecipeSkipped(status *InstallStatus, event RecipeStatusEvent) error {
+	return nil
+}
+
+func (r *JUnitStatusReporter) RecipeRecommended(status *InstallStatus, event RecipeStatusEvent) error {
+	return nil
+}
+
+func (r *JUnitStatusReporter) RecipeAvailable(status *InstallStatus, recipe types.OpenInstallationRecipe) error {
+	return nil
+}
+
+func (r *JUnitStatusReporter) RecipesAvailable(status *InstallStatus, recipes []types.OpenInstallationRecipe) error {
+	return nil
+}
+
+func (r *JUnitStatusReporter) RecipesSelected(status *InstallStatus, recipes []types.OpenInstallationRecipe) error {
+	return nil
+}
+
+func (r *JUnitStatusReporter) DiscoveryComplete(status *InstallStatus, dm types.DiscoveryManifest) error {
+	return nil
+}
+
+func (r *JUnitStatusReporter) InstallComplete(status *InstallStatus) error {
+	return r.writeReport(status)
+}
+
+func (r *JUnitStatusReporter) InstallCanceled(status *InstallStatus) error {
+	return r.writeReport(status)
+}
+
+// recipeFinished records how long the recipe took since it started installing.
+// Retries accumulate into the same duration.
+func (r *JUnitStatusReporter) recipeFinished(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if start, ok := r.started[name]; ok {
+		r.elapsed[name] += time.Since(start)
+		delete(r.started, name)
+	}
+}
+
+func (r *JUnitStatusReporter) writeReport(status *InstallStatus) error {
+	data, err := xml.MarshalIndent(r.report(status), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+
+	if err := utils.WriteFileAtomic(r.path, append([]byte(xml.Header), append(data, '\n')...), junitFilePerm); err != nil {
+		return err
+	}
+
+	log.Debugf("JUnit report written to %s", r.path)
+
+	return nil
+}
+
+func (r *JUnitStatusReporter) report(status *InstallStatus) junitTestSuites {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	suite := junitTestSuite{
+		Name:      junitSuiteName,
+		Hostname:  status.DiscoveryManifest.Hostname,
+		Timestamp: r.start.UTC().Format(time.RFC3339),
+		Time:      junitSeconds(time.Since(r.start)),
+	}
+
+	for _, s := range status.Statuses {
+		c, ok := r.testCase(s)
+		if !ok {
+			continue
+		}
+
+		suite.Tests++
+		if c.Failure != nil {
+			suite.Failures++
+		}
+		if c.Skipped != nil {
+			suite.Skipped++
+		}
+
+		suite.Cases = append(suite.Cases, c)
+	}
+
+	return junitTestSuites{
+		Name:     junitSuiteName,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+}
+
+// testCase returns the test case for a recipe, or false for recipes that were
+// only available or recommended and never attempted.
+func (r *JUnitStatusReporter) testCase(s *RecipeStatus) (junitTestCase, bool) {
+	name := s.DisplayName
+	if name == "" {
+		name = s.Name
+	}
+
+	duration := r.elapsed[s.Name]
+	if duration == 0 {
+		duration = time.Duration(s.ValidationDurationMilliseconds) * time.Millisecond
+	}
+
+	c := junitTestCase{
+		Name:      name,
+		ClassName: fmt.Sprintf("%s.%s", junitSuiteName, s.Name),
+		Time:      junitSeconds(duration),
+	}
+
+	switch s.Status {
+	case RecipeStatusTypes.INSTALLED:
+	case RecipeStatusTypes.FAILED:
+		c.Failure = &junitFailure{
+			Message: s.Error.Message,
+			Details: s.Error.Details,
+		}
+	case RecipeStatusTypes.SKIPPED:
+		c.Skipped = &junitSkipped{Message: s.Error.Message}
+	case RecipeStatusTypes.CANCELED, RecipeStatusTypes.INSTALLING:
+		c.Skipped = &junitSkipped{Message: "installation canceled"}
+	default:
+		return junitTestCase{}, false
+	}
+
+	return c, true
+}
+
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
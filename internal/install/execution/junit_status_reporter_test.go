@@ -0,0 +1,92 @@
+//go:build unit
+// +build unit
+
+package execution
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestJUnitStatusReporter_interface(t *testing.T) {
+	var r StatusSubscriber = NewJUnitStatusReporter("")
+	require.NotNil(t, r)
+}
+
+func TestJUnitStatusReporter_InstallComplete(t *testing.T) {
+	dir, err := ioutil.TempDir("", "junit")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "reports", "install.xml")
+	r := NewJUnitStatusReporter(path)
+	s := NewInstallStatus([]StatusSubscriber{r}, NewMockSuccessLinkGenerator())
+
+	installed := types.OpenInstallationRecipe{Name: "installed", DisplayName: "Installed Recipe"}
+	failed := types.OpenInstallationRecipe{Name: "failed"}
+	skipped := types.OpenInstallationRecipe{Name: "skipped"}
+	recommended := types.OpenInstallationRecipe{Name: "recommended"}
+
+	s.RecipeInstalling(RecipeStatusEvent{Recipe: installed})
+	s.RecipeInstalled(RecipeStatusEvent{Recipe: installed})
+	s.RecipeInstalling(RecipeStatusEvent{Recipe: failed})
+	s.RecipeFailed(RecipeStatusEvent{Recipe: failed, Msg: "exit status 1"})
+	s.RecipeSkipped(RecipeStatusEvent{Recipe: skipped, Msg: "conflict"})
+	s.RecipeRecommended(RecipeStatusEvent{Recipe: recommended})
+	s.InstallComplete(nil)
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	var report junitTestSuites
+	require.NoError(t, xml.Unmarshal(data, &report))
+
+	require.Equal(t, 3, report.Tests)
+	require.Equal(t, 1, report.Failures)
+	require.Equal(t, 1, report.Skipped)
+	require.Len(t, report.Suites, 1)
+
+	cases := report.Suites[0].Cases
+	require.Len(t, cases, 3)
+
+	require.Equal(t, "Installed Recipe", cases[0].Name)
+	require.Equal(t, "newrelic-install.installed", cases[0].ClassName)
+	require.Nil(t, cases[0].Failure)
+	require.Nil(t, cases[0].Skipped)
+
+	require.Equal(t, "failed", cases[1].Name)
+	require.NotNil(t, cases[1].Failure)
+	require.Equal(t, "exit status 1", cases[1].Failure.Message)
+
+	require.Equal(t, "skipped", cases[2].Name)
+	require.NotNil(t, cases[2].Skipped)
+	require.Equal(t, "conflict", cases[2].Skipped.Message)
+}
+
+func TestJUnitStatusReporter_InstallCanceled(t *testing.T) {
+	dir, err := ioutil.TempDir("", "junit")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "install.xml")
+	r := NewJUnitStatusReporter(path)
+	s := NewInstallStatus([]StatusSubscriber{r}, NewMockSuccessLinkGenerator())
+
+	s.RecipeInstalling(RecipeStatusEvent{Recipe: types.OpenInstallationRecipe{Name: "interrupted"}})
+	s.InstallCanceled()
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	var report junitTestSuites
+	require.NoError(t, xml.Unmarshal(data, &report))
+	require.Equal(t, 1, report.Tests)
+	require.Equal(t, 1, report.Skipped)
+}
@@ -0,0 +1,131 @@
+//go:build unit
+// +build unit
+
+package execution
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+type testProcess struct {
+	name    string
+	cmdline string
+	pid     int32
+}
+
+func (p testProcess) Name() (string, error) {
+	return p.name, nil
+}
+
+func (p testProcess) Cmdline() (string, error) {
+	return p.cmdline, nil
+}
+
+func (p testProcess) PID() int32 {
+	return p.pid
+}
+
+func testManifest() types.DiscoveryManifest {
+	cmdline := "/usr/bin/redis-server --port 6379 --requirepass hunter2 --masterauth=hunter3"
+	return types.DiscoveryManifest{
+		Hostname: "web-01",
+		OS:       "linux",
+		Platform: "ubuntu",
+		Processes: []types.MatchedProcess{
+			{
+				Command:         cmdline,
+				MatchingPattern: "redis-server",
+				Process:         testProcess{name: "redis-server", cmdline: cmdline, pid: 42},
+			},
+		},
+	}
+}
+
+func TestRedactCmdline(t *testing.T) {
+	tests := map[string]string{
+		"/usr/sbin/mysqld --user=mysql":                          "/usr/sbin/mysqld --user=mysql",
+		"/usr/sbin/mysqld --password=hunter2":                    "/usr/sbin/mysqld --password=********",
+		"agent -api-key hunter2 -v":                              "agent -api-key ******** -v",
+		"agent --token --verbose":                                "agent --token --verbose",
+		"app DB_PASSWORD=hunter2 start":                          "app DB_PASSWORD=******** start",
+		"worker --url postgres://app:hunter2@db:5432/app":        "worker --url postgres://app:********@db:5432/app",
+		"worker --url=postgres://app@db:5432/app":                "worker --url=postgres://app@db:5432/app",
+		"java -Dnewrelic.config.license_key=abc123 -jar app.jar": "java -Dnewrelic.config.license_key=******** -jar app.jar",
+	}
+
+	for cmdline, expected := range tests {
+		require.Equal(t, expected, redactCmdline(cmdline), cmdline)
+	}
+}
+
+func TestRedactManifest(t *testing.T) {
+	dm := testManifest()
+
+	redacted := redactManifest(dm)
+	require.Equal(t, "web-01", redacted.Hostname)
+	require.Len(t, redacted.Processes, 1)
+	require.NotContains(t, redacted.Processes[0].Command, "hunter")
+	require.Equal(t, "redis-server", redacted.Processes[0].MatchingPattern)
+
+	cmdline, err := redacted.Processes[0].Process.Cmdline()
+	require.NoError(t, err)
+	require.Equal(t, "/usr/bin/redis-server --port 6379 --requirepass ******** --masterauth=********", cmdline)
+	require.Equal(t, int32(42), redacted.Processes[0].Process.PID())
+
+	// The manifest discovered is left as is.
+	require.Contains(t, dm.Processes[0].Command, "hunter2")
+}
+
+func TestNerdStorageStatusReporter_PersistsManifest(t *testing.T) {
+	c := NewMockNerdStorageClient()
+	r := NewNerdStorageStatusReporter(c)
+	status := NewInstallStatus([]StatusSubscriber{r}, NewConcreteSuccessLinkGenerator())
+
+	status.DiscoveryComplete(testManifest())
+	require.Len(t, c.WrittenUserScopeDocuments, 1)
+
+	data, err := json.Marshal(c.WrittenUserScopeDocuments[0])
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "hunter")
+
+	var written InstallStatus
+	require.NoError(t, json.Unmarshal(data, &written))
+	require.Equal(t, "web-01", written.DiscoveryManifest.Hostname)
+	require.Equal(t, "ubuntu", written.DiscoveryManifest.Platform)
+	require.Len(t, written.DiscoveryManifest.Processes, 1)
+	require.Equal(t, "redis-server", written.DiscoveryManifest.Processes[0].MatchingPattern)
+	require.Equal(t, int32(42), written.DiscoveryManifest.Processes[0].Process.PID())
+}
+
+func TestArtifactStatusReporter_PersistsRedactedManifest(t *testing.T) {
+	base, err := ioutil.TempDir("", "artifacts")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+
+	dir, err := CreateRunDirectory(base, time.Now())
+	require.NoError(t, err)
+
+	status := NewInstallStatus([]StatusSubscriber{NewArtifactStatusReporter(dir)}, NewMockSuccessLinkGenerator())
+	status.DiscoveryComplete(testManifest())
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "manifest.json"))
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "hunter")
+
+	var m types.DiscoveryManifest
+	require.NoError(t, json.Unmarshal(data, &m))
+	require.Len(t, m.Processes, 1)
+
+	name, err := m.Processes[0].Process.Name()
+	require.NoError(t, err)
+	require.Equal(t, "redis-server", name)
+}
@@ -0,0 +1,270 @@
+package execution
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+const (
+	// MetricsPath is the path the install metrics are served on.
+	MetricsPath = "/metrics"
+
+	metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+	// metricsServerTimeout bounds reading a scrape's headers and waiting for
+	// scrapes in flight when the server stops.
+	metricsServerTimeout = 5 * time.Second
+)
+
+// metricsPhases are the phases of an install exposed by the phase metric.
+var metricsPhases = []string{
+	HeartbeatPhaseDiscovery,
+	HeartbeatPhaseSelection,
+	HeartbeatPhaseInstall,
+	HeartbeatPhaseComplete,
+	HeartbeatPhaseFailed,
+	HeartbeatPhaseCanceled,
+}
+
+// MetricsStatusReporter is an implementation of the StatusSubscriber interface
+// that serves the progress of the install as Prometheus metrics, so that
+// existing monitoring can watch installs across a fleet.  The metrics are
+// served from Start until the install completes or is canceled.
+type MetricsStatusReporter struct {
+	addr       string
+	mu         sync.Mutex
+	phase      string
+	startedAt  time.Time
+	finishedAt time.Time
+	selected   map[string]bool
+	installed  map[string]bool
+	failed     map[string]bool
+	skipped    map[string]bool
+	listener   net.Listener
+	server     *http.Server
+}
+
+// NewMetricsStatusReporter returns a new instance of MetricsStatusReporter
+// serving on the given address, such as localhost:9464.
+func NewMetricsStatusReporter(addr string) *MetricsStatusReporter {
+	r := MetricsStatusReporter{
+		addr:      addr,
+		phase:     HeartbeatPhaseDiscovery,
+		startedAt: time.Now(),
+		selected:  map[string]bool{},
+		installed: map[string]bool{},
+		failed:    map[string]bool{},
+		skipped:   map[string]bool{},
+	}
+
+	return &r
+}
+
+// Start begins serving the metrics, returning an error if the address cannot
+// be listened on.
+func (r *MetricsStatusReporter) Start() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.server != nil {
+		return nil
+	}
+
+	l, err := net.Listen("tcp", r.addr)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(MetricsPath, r.serveMetrics)
+
+	r.listener = l
+	r.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: metricsServerTimeout,
+	}
+
+	go func(s *http.Server) {
+		if err := s.Serve(l); err != nil && err != http.ErrServerClosed {
+			log.Debugf("could not serve install metrics: %s", err)
+		}
+	}(r.server)
+
+	log.Debugf("serving install metrics on http://%s%s", l.Addr(), MetricsPath)
+
+	return nil
+}
+
+// Addr returns the address the metrics are served on, once started.
+func (r *MetricsStatusReporter) Addr() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.listener == nil {
+		return ""
+	}
+
+	return r.listener.Addr().String()
+}
+
+// Stop stops serving the metrics.
+func (r *MetricsStatusReporter) Stop() error {
+	r.mu.Lock()
+	s := r.server
+	r.server = nil
+	r.listener = nil
+	r.mu.Unlock()
+
+	if s == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), metricsServerTimeout)
+	defer cancel()
+
+	return s.Shutdown(ctx)
+}
+
+func (r *MetricsStatusReporter) DiscoveryComplete(status *InstallStatus, dm types.DiscoveryManifest) error {
+	r.update(func() {
+		r.phase = HeartbeatPhaseSelection
+	})
+
+	return nil
+}
+
+func (r *MetricsStatusReporter) RecipeAvailable(status *InstallStatus, recipe types.OpenInstallationRecipe) error {
+	return nil
+}
+
+func (r *MetricsStatusReporter) RecipesAvailable(status *InstallStatus, recipes []types.OpenInstallationRecipe) error {
+	return nil
+}
+
+func (r *MetricsStatusReporter) RecipesSelected(status *InstallStatus, recipes []types.OpenInstallationRecipe) error {
+	r.update(func() {
+		for _, recipe := range recipes {
+			r.selected[recipe.Name] = true
+		}
+	})
+
+	return nil
+}
+
+func (r *MetricsStatusReporter) RecipeInstalling(status *InstallStatus, event RecipeStatusEvent) error {
+	r.update(func() {
+		r.phase = HeartbeatPhaseInstall
+	})
+
+	return nil
+}
+
+func (r *MetricsStatusReporter) RecipeInstalled(status *InstallStatus, event RecipeStatusEvent) error {
+	r.update(func() {
+		r.installed[event.Recipe.Name] = true
+		delete(r.failed, event.Recipe.Name)
+	})
+
+	return nil
+}
+
+func (r *MetricsStatusReporter) RecipeFailed(status *InstallStatus, event RecipeStatusEvent) error {
+	r.update(func() {
+		r.failed[event.Recipe.Name] = true
+	})
+
+	return nil
+}
+
+func (r *MetricsStatusReporter) RecipeSkipped(status *InstallStatus, event RecipeStatusEvent) error {
+	r.update(func() {
+		r.skipped[event.Recipe.Name] = true
+	})
+
+	return nil
+}
+
+func (r *MetricsStatusReporter) RecipeRecommended(status *InstallStatus, event RecipeStatusEvent) error {
+	return nil
+}
+
+func (r *MetricsStatusReporter) InstallComplete(status *InstallStatus) error {
+	r.update(func() {
+		r.phase = HeartbeatPhaseComplete
+		if status.Error.Message != "" {
+			r.phase = HeartbeatPhaseFailed
+		}
+		r.finishedAt = time.Now()
+	})
+
+	return r.Stop()
+}
+
+func (r *MetricsStatusReporter) InstallCanceled(status *InstallStatus) error {
+	r.update(func() {
+		r.phase = HeartbeatPhaseCanceled
+		r.finishedAt = time.Now()
+	})
+
+	return r.Stop()
+}
+
+func (r *MetricsStatusReporter) update(f func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f()
+}
+
+func (r *MetricsStatusReporter) serveMetrics(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", metricsContentType)
+	if _, err := w.Write(r.metrics()); err != nil {
+		log.Debugf("could not write install metrics: %s", err)
+	}
+}
+
+// metrics returns the current metrics in the Prometheus text format.
+func (r *MetricsStatusReporter) metrics() []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var b bytes.Buffer
+
+	writeGauge(&b, "newrelic_install_recipes_total", "The number of recipes selected for installation.", float64(len(r.selected)))
+	writeGauge(&b, "newrelic_install_recipes_completed", "The number of recipes installed.", float64(len(r.installed)))
+	writeGauge(&b, "newrelic_install_recipes_failed", "The number of recipes that failed to install.", float64(len(r.failed)))
+	writeGauge(&b, "newrelic_install_recipes_skipped", "The number of recipes skipped.", float64(len(r.skipped)))
+
+	fmt.Fprintln(&b, "# HELP newrelic_install_phase The current phase of the install, which has the value 1.")
+	fmt.Fprintln(&b, "# TYPE newrelic_install_phase gauge")
+	for _, p := range metricsPhases {
+		v := 0
+		if p == r.phase {
+			v = 1
+		}
+		fmt.Fprintf(&b, "newrelic_install_phase{phase=%q} %d\n", p, v)
+	}
+
+	end := r.finishedAt
+	if end.IsZero() {
+		end = time.Now()
+	}
+	writeGauge(&b, "newrelic_install_duration_seconds", "The time the install has been running.", end.Sub(r.startedAt).Seconds())
+
+	return b.Bytes()
+}
+
+func writeGauge(b *bytes.Buffer, name string, help string, value float64) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s gauge\n", name)
+	fmt.Fprintf(b, "%s %g\n", name, value)
+}
@@ -0,0 +1,90 @@
+//go:build unit
+// +build unit
+
+package execution
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func scrapeMetrics(t *testing.T, addr string) string {
+	resp, err := http.Get("http://" + addr + MetricsPath)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Contains(t, resp.Header.Get("Content-Type"), "text/plain")
+
+	data, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+func TestMetricsStatusReporter_interface(t *testing.T) {
+	var r StatusSubscriber = NewMetricsStatusReporter("")
+	require.NotNil(t, r)
+}
+
+func TestMetricsStatusReporter_ServesProgress(t *testing.T) {
+	r := NewMetricsStatusReporter("127.0.0.1:0")
+	require.NoError(t, r.Start())
+	defer r.Stop()
+
+	status := NewInstallStatus([]StatusSubscriber{r}, NewMockSuccessLinkGenerator())
+
+	metrics := scrapeMetrics(t, r.Addr())
+	require.Contains(t, metrics, "# TYPE newrelic_install_recipes_total gauge\n")
+	require.Contains(t, metrics, "newrelic_install_recipes_total 0\n")
+	require.Contains(t, metrics, `newrelic_install_phase{phase="discovery"} 1`)
+
+	infra := types.OpenInstallationRecipe{Name: "infra"}
+	logs := types.OpenInstallationRecipe{Name: "logs"}
+	mysql := types.OpenInstallationRecipe{Name: "mysql"}
+
+	status.DiscoveryComplete(types.DiscoveryManifest{})
+	status.RecipesSelected([]types.OpenInstallationRecipe{infra, logs, mysql})
+	status.RecipeInstalling(RecipeStatusEvent{Recipe: infra})
+	status.RecipeInstalled(RecipeStatusEvent{Recipe: infra})
+	status.RecipeFailed(RecipeStatusEvent{Recipe: logs})
+	status.RecipeSkipped(RecipeStatusEvent{Recipe: mysql})
+
+	metrics = scrapeMetrics(t, r.Addr())
+	require.Contains(t, metrics, "newrelic_install_recipes_total 3\n")
+	require.Contains(t, metrics, "newrelic_install_recipes_completed 1\n")
+	require.Contains(t, metrics, "newrelic_install_recipes_failed 1\n")
+	require.Contains(t, metrics, "newrelic_install_recipes_skipped 1\n")
+	require.Contains(t, metrics, `newrelic_install_phase{phase="install"} 1`)
+	require.Contains(t, metrics, `newrelic_install_phase{phase="discovery"} 0`)
+	require.Contains(t, metrics, "newrelic_install_duration_seconds ")
+}
+
+func TestMetricsStatusReporter_StopsWhenComplete(t *testing.T) {
+	r := NewMetricsStatusReporter("127.0.0.1:0")
+	require.NoError(t, r.Start())
+	addr := r.Addr()
+
+	status := NewInstallStatus([]StatusSubscriber{r}, NewMockSuccessLinkGenerator())
+	status.InstallComplete(errors.New("boom"))
+
+	require.Contains(t, string(r.metrics()), `newrelic_install_phase{phase="failed"} 1`)
+	require.Empty(t, r.Addr())
+
+	_, err := http.Get("http://" + addr + MetricsPath)
+	require.Error(t, err)
+}
+
+func TestMetricsStatusReporter_AddressInUse(t *testing.T) {
+	r := NewMetricsStatusReporter("127.0.0.1:0")
+	require.NoError(t, r.Start())
+	defer r.Stop()
+
+	require.Error(t, NewMetricsStatusReporter(r.Addr()).Start())
+}
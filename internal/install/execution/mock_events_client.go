@@ -0,0 +1,32 @@
+package execution
+
+import (
+	"sync"
+	"time"
+)
+
+type MockEventsClient struct {
+	CreateEventErr       error
+	CreateEventDelay     time.Duration
+	CreateEventCallCount int
+	AccountIDs           []int
+	Events               []interface{}
+	mu                   sync.Mutex
+}
+
+func NewMockEventsClient() *MockEventsClient {
+	return &MockEventsClient{}
+}
+
+func (c *MockEventsClient) CreateEvent(accountID int, event interface{}) error {
+	time.Sleep(c.CreateEventDelay)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.CreateEventCallCount++
+	c.AccountIDs = append(c.AccountIDs, accountID)
+	c.Events = append(c.Events, event)
+
+	return c.CreateEventErr
+}
@@ -1,14 +1,26 @@
 package execution
 
-import "github.com/newrelic/newrelic-client-go/pkg/nerdstorage"
+import (
+	"sync"
+
+	"github.com/newrelic/newrelic-cli/internal/utils"
+	"github.com/newrelic/newrelic-client-go/pkg/nerdstorage"
+)
 
 type MockNerdStorageClient struct {
-	WriteDocumentWithUserScopeVal         interface{}
-	WriteDocumentWithEntityScopeVal       interface{}
-	WriteDocumentWithUserScopeErr         error
-	WriteDocumentWithEntityScopeErr       error
+	WriteDocumentWithUserScopeVal    interface{}
+	WriteDocumentWithEntityScopeVal  interface{}
+	WriteDocumentWithUserScopeErr    error
+	WriteDocumentWithEntityScopeErr  error
+	WriteDocumentWithEntityScopeErrs []error
+	WriteDocumentWithAccountScopeErr error
+	// WrittenAccountScopeDocuments are the documents written with account scope.
+	WrittenAccountScopeDocuments []nerdstorage.WriteDocumentInput
+	// WrittenUserScopeDocuments are the documents written with user scope.
+	WrittenUserScopeDocuments             []interface{}
 	writeDocumentWithUserScopeCallCount   int
 	writeDocumentWithEntityScopeCallCount int
+	mu                                    sync.Mutex
 }
 
 func NewMockNerdStorageClient() *MockNerdStorageClient {
@@ -20,12 +32,33 @@ func NewMockNerdStorageClient() *MockNerdStorageClient {
 	}
 }
 
-func (c *MockNerdStorageClient) WriteDocumentWithUserScope(nerdstorage.WriteDocumentInput) (interface{}, error) {
+func (c *MockNerdStorageClient) WriteDocumentWithUserScope(input nerdstorage.WriteDocumentInput) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.writeDocumentWithUserScopeCallCount++
+	c.WrittenUserScopeDocuments = append(c.WrittenUserScopeDocuments, input.Document)
 	return c.WriteDocumentWithUserScopeVal, c.WriteDocumentWithUserScopeErr
 }
 
+func (c *MockNerdStorageClient) WriteDocumentWithAccountScope(accountID int, input nerdstorage.WriteDocumentInput) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.WrittenAccountScopeDocuments = append(c.WrittenAccountScopeDocuments, input)
+	return struct{}{}, c.WriteDocumentWithAccountScopeErr
+}
+
 func (c *MockNerdStorageClient) WriteDocumentWithEntityScope(string, nerdstorage.WriteDocumentInput) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.writeDocumentWithEntityScopeCallCount++
+
+	if len(c.WriteDocumentWithEntityScopeErrs) > 0 {
+		i := utils.MinOf(c.writeDocumentWithEntityScopeCallCount, len(c.WriteDocumentWithEntityScopeErrs)) - 1
+		return c.WriteDocumentWithEntityScopeVal, c.WriteDocumentWithEntityScopeErrs[i]
+	}
+
 	return c.WriteDocumentWithEntityScopeVal, c.WriteDocumentWithEntityScopeErr
 }
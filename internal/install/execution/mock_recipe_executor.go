@@ -8,6 +8,12 @@ import (
 
 type MockRecipeExecutor struct {
 	result bool
+	// PrepareErr, when set, is returned by Prepare.
+	PrepareErr error
+	// ExecuteErrs are returned by successive calls to Execute, which succeeds
+	// once they run out.
+	ExecuteErrs      []error
+	ExecuteCallCount int
 }
 
 func NewMockRecipeExecutor() *MockRecipeExecutor {
@@ -17,9 +23,15 @@ func NewMockRecipeExecutor() *MockRecipeExecutor {
 }
 
 func (m *MockRecipeExecutor) Prepare(ctx context.Context, dm types.DiscoveryManifest, r types.OpenInstallationRecipe, y bool, z string) (types.RecipeVars, error) {
-	return types.RecipeVars{}, nil
+	return types.RecipeVars{}, m.PrepareErr
 }
 
 func (m *MockRecipeExecutor) Execute(ctx context.Context, dm types.DiscoveryManifest, r types.OpenInstallationRecipe, v types.RecipeVars) error {
+	m.ExecuteCallCount++
+
+	if m.ExecuteCallCount <= len(m.ExecuteErrs) {
+		return m.ExecuteErrs[m.ExecuteCallCount-1]
+	}
+
 	return nil
 }
@@ -15,6 +15,8 @@ type MockStatusReporter struct {
 	RecipeInstallingErr        error
 	RecipeRecommendedErr       error
 	RecipeSkippedErr           error
+	RecipeStepSkippedErr       error
+	RecipeRetryingErr          error
 	InstallCompleteErr         error
 	InstallCanceledErr         error
 	DiscoveryCompleteErr       error
@@ -26,6 +28,8 @@ type MockStatusReporter struct {
 	RecipeInstallingCallCount  int
 	RecipeRecommendedCallCount int
 	RecipeSkippedCallCount     int
+	RecipeStepSkippedCallCount int
+	RecipeRetryingCallCount    int
 	InstallCompleteCallCount   int
 	InstallCanceledCallCount   int
 	DiscoveryCompleteCallCount int
@@ -36,6 +40,9 @@ type MockStatusReporter struct {
 	ReportRecommended map[string]int
 	ReportFailed      map[string]int
 	ReportAvailable   map[string]int
+	SkippedSteps      []string
+	CorrelationIDs    []string
+	CampaignIDs       []string
 
 	GUIDs      []string
 	Durations  []int64
@@ -49,6 +56,8 @@ func NewMockStatusReporter() *MockStatusReporter {
 
 func (r *MockStatusReporter) RecipeFailed(status *InstallStatus, event RecipeStatusEvent) error {
 	r.RecipeFailedCallCount++
+	r.CorrelationIDs = append(r.CorrelationIDs, event.CorrelationID)
+	r.CampaignIDs = append(r.CampaignIDs, event.CampaignID)
 	if len(r.ReportFailed) == 0 {
 		r.ReportFailed = make(map[string]int)
 	}
@@ -58,6 +67,8 @@ func (r *MockStatusReporter) RecipeFailed(status *InstallStatus, event RecipeSta
 
 func (r *MockStatusReporter) RecipeInstalled(status *InstallStatus, event RecipeStatusEvent) error {
 	r.RecipeInstalledCallCount++
+	r.CorrelationIDs = append(r.CorrelationIDs, event.CorrelationID)
+	r.CampaignIDs = append(r.CampaignIDs, event.CampaignID)
 	if len(r.ReportInstalled) == 0 {
 		r.ReportInstalled = make(map[string]int)
 	}
@@ -82,6 +93,8 @@ func (r *MockStatusReporter) RecipeInstalled(status *InstallStatus, event Recipe
 
 func (r *MockStatusReporter) RecipeInstalling(status *InstallStatus, event RecipeStatusEvent) error {
 	r.RecipeInstallingCallCount++
+	r.CorrelationIDs = append(r.CorrelationIDs, event.CorrelationID)
+	r.CampaignIDs = append(r.CampaignIDs, event.CampaignID)
 	if len(r.ReportInstalling) == 0 {
 		r.ReportInstalling = make(map[string]int)
 	}
@@ -91,6 +104,8 @@ func (r *MockStatusReporter) RecipeInstalling(status *InstallStatus, event Recip
 
 func (r *MockStatusReporter) RecipeRecommended(status *InstallStatus, event RecipeStatusEvent) error {
 	r.RecipeRecommendedCallCount++
+	r.CorrelationIDs = append(r.CorrelationIDs, event.CorrelationID)
+	r.CampaignIDs = append(r.CampaignIDs, event.CampaignID)
 	if len(r.ReportRecommended) == 0 {
 		r.ReportRecommended = make(map[string]int)
 	}
@@ -100,6 +115,8 @@ func (r *MockStatusReporter) RecipeRecommended(status *InstallStatus, event Reci
 
 func (r *MockStatusReporter) RecipeSkipped(status *InstallStatus, event RecipeStatusEvent) error {
 	r.RecipeSkippedCallCount++
+	r.CorrelationIDs = append(r.CorrelationIDs, event.CorrelationID)
+	r.CampaignIDs = append(r.CampaignIDs, event.CampaignID)
 	if len(r.ReportSkipped) == 0 {
 		r.ReportSkipped = make(map[string]int)
 	}
@@ -107,6 +124,21 @@ func (r *MockStatusReporter) RecipeSkipped(status *InstallStatus, event RecipeSt
 	return r.RecipeSkippedErr
 }
 
+func (r *MockStatusReporter) RecipeStepSkipped(status *InstallStatus, event RecipeStepEvent) error {
+	r.RecipeStepSkippedCallCount++
+	r.CorrelationIDs = append(r.CorrelationIDs, event.CorrelationID)
+	r.CampaignIDs = append(r.CampaignIDs, event.CampaignID)
+	r.SkippedSteps = append(r.SkippedSteps, event.Step)
+	return r.RecipeStepSkippedErr
+}
+
+func (r *MockStatusReporter) RecipeRetrying(status *InstallStatus, event RecipeRetryEvent) error {
+	r.RecipeRetryingCallCount++
+	r.CorrelationIDs = append(r.CorrelationIDs, event.CorrelationID)
+	r.CampaignIDs = append(r.CampaignIDs, event.CampaignID)
+	return r.RecipeRetryingErr
+}
+
 func (r *MockStatusReporter) RecipeAvailable(status *InstallStatus, recipe types.OpenInstallationRecipe) error {
 	r.RecipeAvailableCallCount++
 	if len(r.ReportAvailable) == 0 {
@@ -3,10 +3,14 @@ package execution
 import "strings"
 
 type MockSuccessLinkGenerator struct {
-	GenerateExplorerLinkCallCount int
-	GenerateEntityLinkCallCount   int
-	GenerateExplorerLinkVal       string
-	GenerateEntityLinkVal         string
+	GenerateExplorerLinkCallCount  int
+	GenerateEntityLinkCallCount    int
+	GenerateDashboardLinkCallCount int
+	GenerateNRQLLinkCallCount      int
+	GenerateExplorerLinkVal        string
+	GenerateEntityLinkVal          string
+	GenerateDashboardLinkVal       string
+	GenerateNRQLLinkVal            string
 }
 
 func NewMockSuccessLinkGenerator() *MockSuccessLinkGenerator {
@@ -23,11 +27,25 @@ func (g *MockSuccessLinkGenerator) GenerateEntityLink(entityGUID string) string
 	return g.GenerateEntityLinkVal
 }
 
+func (g *MockSuccessLinkGenerator) GenerateDashboardLink(dashboardName string) string {
+	g.GenerateDashboardLinkCallCount++
+	return g.GenerateDashboardLinkVal
+}
+
+func (g *MockSuccessLinkGenerator) GenerateNRQLLink(query string) string {
+	g.GenerateNRQLLinkCallCount++
+	return g.GenerateNRQLLinkVal
+}
+
 func (g *MockSuccessLinkGenerator) GenerateRedirectURL(status InstallStatus) string {
 	if status.hasAnyRecipeStatus(RecipeStatusTypes.INSTALLED) {
 		switch t := status.successLinkConfig.Type; {
 		case strings.EqualFold(string(t), "explorer"):
 			return g.GenerateExplorerLink(status.successLinkConfig.Filter)
+		case strings.EqualFold(string(t), "dashboard"):
+			return g.GenerateDashboardLink(status.successLinkConfig.Filter)
+		case strings.EqualFold(string(t), "nrql"):
+			return g.GenerateNRQLLink(status.successLinkConfig.Filter)
 		default:
 			return g.GenerateEntityLink(status.HostEntityGUID())
 		}
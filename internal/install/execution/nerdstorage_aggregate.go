@@ -0,0 +1,154 @@
+package execution
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-client-go/pkg/nerdstorage"
+)
+
+const (
+	// aggregateCollectionPrefix starts the name of the account-scoped
+	// collection holding the summaries of the installs of a campaign.
+	aggregateCollectionPrefix = "openInstallCampaign-"
+
+	// DefaultAggregateWriteInterval is the minimum interval between two writes
+	// of a host's summary to its campaign's collection while installing.
+	DefaultAggregateWriteInterval = 30 * time.Second
+)
+
+// The outcomes of an install recorded in its host summary.
+const (
+	HostInstallInProgress = "in progress"
+	HostInstallComplete   = "complete"
+	HostInstallFailed     = "failed"
+	HostInstallCanceled   = "canceled"
+)
+
+// HostInstallSummary is the outcome of an install on one host, written to the
+// collection of the campaign the install is part of, so the outcomes of the
+// installs across a fleet can be read in one place.
+type HostInstallSummary struct {
+	Hostname      string                      `json:"hostname"`
+	CorrelationID string                      `json:"correlationId"`
+	CampaignID    string                      `json:"campaignId"`
+	Outcome       string                      `json:"outcome"`
+	Container     string                      `json:"container,omitempty"`
+	Error         string                      `json:"error,omitempty"`
+	Recipes       map[string]RecipeStatusType `json:"recipes"`
+	Installed     int                         `json:"installed"`
+	Failed        int                         `json:"failed"`
+	Skipped       int                         `json:"skipped"`
+	Timestamp     int64                       `json:"timestamp"`
+}
+
+// aggregateWriter writes the summary of the install to the collection of its
+// campaign, keyed by host.  Writes while installing are rate limited to one
+// per interval, since every host of the campaign writes to the same account,
+// and the final outcome is always written.
+type aggregateWriter struct {
+	campaignID string
+	accountID  int
+	interval   time.Duration
+
+	mu        sync.Mutex
+	lastWrite time.Time
+}
+
+// WithAggregateStatus also writes a summary of the install to an account-scoped
+// collection shared by the installs of the given campaign, such as a fleet
+// rollout, at most once per interval until the install ends.
+func WithAggregateStatus(campaignID string, accountID int, interval time.Duration) NerdStorageStatusReporterOption {
+	return func(r *NerdstorageStatusReporter) {
+		if campaignID == "" {
+			return
+		}
+
+		r.aggregate = &aggregateWriter{
+			campaignID: campaignID,
+			accountID:  accountID,
+			interval:   interval,
+		}
+	}
+}
+
+// due reports whether a write of the given outcome is due, recording it as
+// written when it is.
+func (a *aggregateWriter) due(outcome string, now time.Time) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if outcome == HostInstallInProgress && !a.lastWrite.IsZero() && now.Sub(a.lastWrite) < a.interval {
+		return false
+	}
+
+	a.lastWrite = now
+	return true
+}
+
+// writeAggregateStatus writes the host's summary to its campaign's collection,
+// when the reporter has one and a write is due.
+func (r NerdstorageStatusReporter) writeAggregateStatus(status *InstallStatus, outcome string) error {
+	if r.aggregate == nil || !r.aggregate.due(outcome, time.Now()) {
+		return nil
+	}
+
+	summary := newHostInstallSummary(status, outcome)
+	i := nerdstorage.WriteDocumentInput{
+		PackageID:  packageID,
+		Collection: aggregateCollectionPrefix + r.aggregate.campaignID,
+		DocumentID: aggregateDocumentID(summary),
+		Document:   summary,
+	}
+
+	log.WithFields(log.Fields{
+		"campaign": r.aggregate.campaignID,
+		"outcome":  outcome,
+	}).Debug("writing aggregate install status")
+
+	return r.withRateLimitRetry(func() error {
+		_, err := r.client.WriteDocumentWithAccountScope(r.aggregate.accountID, i)
+		return err
+	})
+}
+
+func newHostInstallSummary(status *InstallStatus, outcome string) HostInstallSummary {
+	s := HostInstallSummary{
+		Hostname:      status.DiscoveryManifest.Hostname,
+		CorrelationID: status.CorrelationID,
+		CampaignID:    status.CampaignID,
+		Outcome:       outcome,
+		Container:     status.DiscoveryManifest.Container,
+		Error:         status.Error.Message,
+		Recipes:       map[string]RecipeStatusType{},
+		Timestamp:     time.Now().Unix(),
+	}
+
+	for _, rs := range status.Statuses {
+		s.Recipes[rs.Name] = rs.Status
+
+		switch rs.Status {
+		case RecipeStatusTypes.INSTALLED:
+			s.Installed++
+		case RecipeStatusTypes.FAILED:
+			s.Failed++
+		case RecipeStatusTypes.SKIPPED:
+			s.Skipped++
+		}
+	}
+
+	return s
+}
+
+// aggregateDocumentID identifies the host's document in its campaign's
+// collection.  Installs whose host is not yet discovered are identified by
+// their correlation ID.
+func aggregateDocumentID(s HostInstallSummary) string {
+	if s.Hostname != "" {
+		return s.Hostname
+	}
+
+	return s.CorrelationID
+}
@@ -0,0 +1,95 @@
+package execution
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxWriteFailures is the number of consecutive failed writes of
+	// the install status after which NerdStorage is considered unavailable.
+	DefaultMaxWriteFailures = 3
+
+	// DefaultDegradedRetryInterval is the minimum interval between two writes
+	// of the install status while NerdStorage is considered unavailable.
+	DefaultDegradedRetryInterval = 1 * time.Minute
+)
+
+// writeHealth tracks whether the status documents can be written.  Every
+// document holds the whole status of the install, so a write that fails is
+// made up for by the next one.  Once too many writes in a row have failed,
+// the status is only written again once per retry interval, and when the
+// install ends, until a write succeeds.
+type writeHealth struct {
+	maxFailures   int
+	retryInterval time.Duration
+
+	mu       sync.Mutex
+	failures int
+	degraded bool
+	lastTry  time.Time
+}
+
+// WithDegradation sets the number of consecutive failed writes after which
+// NerdStorage is considered unavailable, and the minimum interval between the
+// writes attempted until it is available again.  Failures are then reported
+// once, with a warning, rather than for every write.
+func WithDegradation(maxFailures int, retryInterval time.Duration) NerdStorageStatusReporterOption {
+	return func(r *NerdstorageStatusReporter) {
+		if maxFailures > 0 {
+			r.health.maxFailures = maxFailures
+		}
+		r.health.retryInterval = retryInterval
+	}
+}
+
+func newWriteHealth() *writeHealth {
+	return &writeHealth{
+		maxFailures:   DefaultMaxWriteFailures,
+		retryInterval: DefaultDegradedRetryInterval,
+	}
+}
+
+// due reports whether a write is due.  While degraded, writes that are not
+// final are deferred until the retry interval has passed.
+func (h *writeHealth) due(final bool, now time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.degraded && !final && now.Sub(h.lastTry) < h.retryInterval {
+		return false
+	}
+
+	h.lastTry = now
+	return true
+}
+
+// record records the outcome of a write, returning whether it made NerdStorage
+// considered unavailable.
+func (h *writeHealth) record(err error) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err == nil {
+		h.failures = 0
+		h.degraded = false
+		return false
+	}
+
+	h.failures++
+
+	if !h.degraded && h.failures >= h.maxFailures {
+		h.degraded = true
+		return true
+	}
+
+	return false
+}
+
+// Degraded reports whether NerdStorage is considered unavailable.
+func (h *writeHealth) Degraded() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.degraded
+}
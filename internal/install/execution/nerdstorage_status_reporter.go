@@ -1,6 +1,10 @@
 package execution
 
 import (
+	"strings"
+	"sync"
+	"time"
+
 	log "github.com/sirupsen/logrus"
 
 	"github.com/newrelic/newrelic-cli/internal/install/types"
@@ -10,18 +14,67 @@ import (
 const (
 	packageID    = "00000000-0000-0000-0000-000000000000"
 	collectionID = "openInstallLibrary"
+
+	defaultEntityWriteConcurrency = 1
+	defaultRateLimitMaxRetries    = 3
+	defaultRateLimitBackoff       = 1 * time.Second
 )
 
 // NerdstorageStatusReporter is an implementation of the ExecutionStatusReporter
 // interface that reports esecution status into NerdStorage.
 type NerdstorageStatusReporter struct {
-	client NerdStorageClient
+	client                 NerdStorageClient
+	entityWriteConcurrency int
+	entityWriteInterval    time.Duration
+	rateLimitMaxRetries    int
+	rateLimitBackoff       time.Duration
+	aggregate              *aggregateWriter
+	health                 *writeHealth
+}
+
+// NerdStorageStatusReporterOption configures optional behavior of a
+// NerdstorageStatusReporter.
+type NerdStorageStatusReporterOption func(*NerdstorageStatusReporter)
+
+// WithEntityWriteConcurrency sets the maximum number of entity-scoped documents
+// that are written concurrently.
+func WithEntityWriteConcurrency(concurrency int) NerdStorageStatusReporterOption {
+	return func(r *NerdstorageStatusReporter) {
+		if concurrency > 0 {
+			r.entityWriteConcurrency = concurrency
+		}
+	}
+}
+
+// WithEntityWriteInterval sets the minimum interval between the start of two
+// entity-scoped document writes.
+func WithEntityWriteInterval(interval time.Duration) NerdStorageStatusReporterOption {
+	return func(r *NerdstorageStatusReporter) {
+		r.entityWriteInterval = interval
+	}
+}
+
+// WithRateLimitRetries sets how many times a rate limited write is retried, and
+// the initial backoff between attempts.  The backoff doubles after each attempt.
+func WithRateLimitRetries(maxRetries int, backoff time.Duration) NerdStorageStatusReporterOption {
+	return func(r *NerdstorageStatusReporter) {
+		r.rateLimitMaxRetries = maxRetries
+		r.rateLimitBackoff = backoff
+	}
 }
 
 // NewNerdStorageStatusReporter returns a new instance of NerdStorageExecutionStatusReporter.
-func NewNerdStorageStatusReporter(client NerdStorageClient) *NerdstorageStatusReporter {
+func NewNerdStorageStatusReporter(client NerdStorageClient, opts ...NerdStorageStatusReporterOption) *NerdstorageStatusReporter {
 	r := NerdstorageStatusReporter{
-		client: client,
+		client:                 client,
+		entityWriteConcurrency: defaultEntityWriteConcurrency,
+		rateLimitMaxRetries:    defaultRateLimitMaxRetries,
+		rateLimitBackoff:       defaultRateLimitBackoff,
+		health:                 newWriteHealth(),
+	}
+
+	for _, opt := range opts {
+		opt(&r)
 	}
 
 	return &r
@@ -64,11 +117,16 @@ func (r NerdstorageStatusReporter) RecipeSkipped(status *InstallStatus, event Re
 }
 
 func (r NerdstorageStatusReporter) InstallComplete(status *InstallStatus) error {
-	return r.writeStatus(status)
+	outcome := HostInstallComplete
+	if status.Error.Message != "" {
+		outcome = HostInstallFailed
+	}
+
+	return r.writeStatusWithOutcome(status, outcome)
 }
 
 func (r NerdstorageStatusReporter) InstallCanceled(status *InstallStatus) error {
-	return r.writeStatus(status)
+	return r.writeStatusWithOutcome(status, HostInstallCanceled)
 }
 
 func (r NerdstorageStatusReporter) DiscoveryComplete(status *InstallStatus, dm types.DiscoveryManifest) error {
@@ -80,24 +138,137 @@ func (r NerdstorageStatusReporter) DiscoveryComplete(status *InstallStatus, dm t
 }
 
 func (r NerdstorageStatusReporter) writeStatus(status *InstallStatus) error {
+	return r.writeStatusWithOutcome(status, HostInstallInProgress)
+}
+
+// writeStatusWithOutcome writes the status document with user scope and to
+// each entity, and the summary of the install with the given outcome to its
+// campaign, when there is one.  The first error encountered is returned, until
+// too many writes in a row have failed: NerdStorage is then considered
+// unavailable, which is warned of once, and failures are no longer returned so
+// the install carries on with its status reported locally.
+func (r NerdstorageStatusReporter) writeStatusWithOutcome(status *InstallStatus, outcome string) error {
+	final := outcome != HostInstallInProgress
+	if !r.health.due(final, time.Now()) {
+		log.Debug("nerdstorage unavailable, deferring install status write")
+		return nil
+	}
+
+	err := r.writeStatusDocuments(status, outcome)
+	if r.health.record(err) {
+		log.Warnf("The status of this install could not be saved to New Relic: %s. The install will continue, and its progress is still shown here.", err)
+	}
+
+	if err != nil && r.health.Degraded() {
+		log.Debugf("could not write install status: %s", err)
+		return nil
+	}
+
+	return err
+}
+
+func (r NerdstorageStatusReporter) writeStatusDocuments(status *InstallStatus, outcome string) error {
+	aggregateErr := r.writeAggregateStatus(status, outcome)
+	if aggregateErr != nil {
+		log.Debugf("could not write aggregate install status: %s", aggregateErr)
+	}
+
+	if err := r.writeScopedStatus(status); err != nil {
+		return err
+	}
+
+	return aggregateErr
+}
+
+func (r NerdstorageStatusReporter) writeScopedStatus(status *InstallStatus) error {
 	i := r.buildExecutionStatusDocument(status)
-	_, err := r.client.WriteDocumentWithUserScope(i)
+	err := r.withRateLimitRetry(func() error {
+		_, err := r.client.WriteDocumentWithUserScope(i)
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
-	for _, g := range status.EntityGUIDs {
-		_, err := r.client.WriteDocumentWithEntityScope(g, i)
-		if err != nil {
-			return err
+	if len(status.EntityGUIDs) == 0 {
+		log.Debug("no entity GUIDs available, skipping entity-scoped status updates")
+		return nil
+	}
+
+	return r.writeEntityStatuses(status.EntityGUIDs, i)
+}
+
+// writeEntityStatuses writes the status document to each entity scope, bounded
+// by the configured concurrency and throttled by the configured interval.  The
+// first error encountered is returned once all writes have finished.
+func (r NerdstorageStatusReporter) writeEntityStatuses(guids []string, i nerdstorage.WriteDocumentInput) error {
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+
+	sem := make(chan struct{}, r.entityWriteConcurrency)
+
+	var throttle <-chan time.Time
+	if r.entityWriteInterval > 0 {
+		ticker := time.NewTicker(r.entityWriteInterval)
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	for n, g := range guids {
+		if throttle != nil && n > 0 {
+			<-throttle
 		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+
+		go func(guid string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			err := r.withRateLimitRetry(func() error {
+				_, err := r.client.WriteDocumentWithEntityScope(guid, i)
+				return err
+			})
+			if err != nil {
+				once.Do(func() { firstErr = err })
+			}
+		}(g)
 	}
 
-	if len(status.EntityGUIDs) == 0 {
-		log.Debug("no entity GUIDs available, skipping entity-scoped status updates")
+	wg.Wait()
+
+	return firstErr
+}
+
+func (r NerdstorageStatusReporter) withRateLimitRetry(write func() error) error {
+	backoff := r.rateLimitBackoff
+
+	for attempt := 0; ; attempt++ {
+		err := write()
+		if err == nil || !isRateLimitError(err) || attempt >= r.rateLimitMaxRetries {
+			return err
+		}
+
+		log.WithFields(log.Fields{
+			"attempt": attempt + 1,
+			"backoff": backoff,
+		}).Debug("nerdstorage write rate limited, retrying")
+
+		time.Sleep(backoff)
+		backoff *= 2
 	}
+}
 
-	return nil
+func isRateLimitError(err error) bool {
+	msg := strings.ToLower(err.Error())
+
+	return strings.Contains(msg, "429 response returned") ||
+		strings.Contains(msg, "rate limit") ||
+		strings.Contains(msg, "too many requests")
 }
 
 func (r NerdstorageStatusReporter) buildExecutionStatusDocument(status *InstallStatus) nerdstorage.WriteDocumentInput {
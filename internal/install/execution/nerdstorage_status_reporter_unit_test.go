@@ -1,3 +1,4 @@
+//go:build unit
 // +build unit
 
 package execution
@@ -5,6 +6,7 @@ package execution
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -234,3 +236,202 @@ func TestDiscoveryComplete_UserScopeError(t *testing.T) {
 	err := r.DiscoveryComplete(status, types.DiscoveryManifest{})
 	require.Error(t, err)
 }
+
+func TestRecipeInstalled_EntityScopeConcurrency(t *testing.T) {
+	c := NewMockNerdStorageClient()
+	r := NewNerdStorageStatusReporter(c, WithEntityWriteConcurrency(3))
+	slg := NewConcreteSuccessLinkGenerator()
+	status := NewInstallStatus([]StatusSubscriber{}, slg)
+	for _, g := range []string{"testGuid", "testGuid2", "testGuid3", "testGuid4", "testGuid5"} {
+		status.withEntityGUID(g)
+	}
+	e := RecipeStatusEvent{}
+
+	err := r.RecipeInstalled(status, e)
+	require.NoError(t, err)
+	require.Equal(t, 1, c.writeDocumentWithUserScopeCallCount)
+	require.Equal(t, 5, c.writeDocumentWithEntityScopeCallCount)
+}
+
+func TestRecipeInstalled_EntityScopeRateLimitRetry(t *testing.T) {
+	c := NewMockNerdStorageClient()
+	r := NewNerdStorageStatusReporter(c, WithRateLimitRetries(3, time.Millisecond))
+	slg := NewConcreteSuccessLinkGenerator()
+	status := NewInstallStatus([]StatusSubscriber{}, slg)
+	status.withEntityGUID("testGuid")
+	e := RecipeStatusEvent{}
+
+	c.WriteDocumentWithEntityScopeErrs = []error{
+		errors.New("429 response returned: rate limit exceeded"),
+		nil,
+	}
+
+	err := r.RecipeInstalled(status, e)
+	require.NoError(t, err)
+	require.Equal(t, 2, c.writeDocumentWithEntityScopeCallCount)
+}
+
+func TestRecipeInstalled_EntityScopeRateLimitRetriesExhausted(t *testing.T) {
+	c := NewMockNerdStorageClient()
+	r := NewNerdStorageStatusReporter(c, WithRateLimitRetries(2, time.Millisecond))
+	slg := NewConcreteSuccessLinkGenerator()
+	status := NewInstallStatus([]StatusSubscriber{}, slg)
+	status.withEntityGUID("testGuid")
+	e := RecipeStatusEvent{}
+
+	c.WriteDocumentWithEntityScopeErr = errors.New("429 response returned")
+
+	err := r.RecipeInstalled(status, e)
+	require.Error(t, err)
+	require.Equal(t, 3, c.writeDocumentWithEntityScopeCallCount)
+}
+
+func TestRecipeInstalled_EntityScopeErrorNotRetried(t *testing.T) {
+	c := NewMockNerdStorageClient()
+	r := NewNerdStorageStatusReporter(c, WithRateLimitRetries(3, time.Millisecond))
+	slg := NewConcreteSuccessLinkGenerator()
+	status := NewInstallStatus([]StatusSubscriber{}, slg)
+	status.withEntityGUID("testGuid")
+	e := RecipeStatusEvent{}
+
+	c.WriteDocumentWithEntityScopeErr = errors.New("error")
+
+	err := r.RecipeInstalled(status, e)
+	require.Error(t, err)
+	require.Equal(t, 1, c.writeDocumentWithEntityScopeCallCount)
+}
+
+func TestAggregateStatus_NotWrittenByDefault(t *testing.T) {
+	c := NewMockNerdStorageClient()
+	r := NewNerdStorageStatusReporter(c)
+	status := NewInstallStatus([]StatusSubscriber{}, NewConcreteSuccessLinkGenerator())
+
+	require.NoError(t, r.InstallComplete(status))
+	require.Empty(t, c.WrittenAccountScopeDocuments)
+}
+
+func TestAggregateStatus_RateLimitedUntilComplete(t *testing.T) {
+	c := NewMockNerdStorageClient()
+	r := NewNerdStorageStatusReporter(c, WithAggregateStatus("rollout-1", 12345, time.Hour))
+	status := NewInstallStatus([]StatusSubscriber{}, NewConcreteSuccessLinkGenerator())
+	status.CampaignID = "rollout-1"
+	status.withDiscoveryInfo(types.DiscoveryManifest{Hostname: "web-01", Container: "docker"})
+	status.Statuses = []*RecipeStatus{
+		{Name: "infrastructure-agent-installer", Status: RecipeStatusTypes.INSTALLED},
+		{Name: "mysql", Status: RecipeStatusTypes.FAILED},
+	}
+
+	require.NoError(t, r.DiscoveryComplete(status, status.DiscoveryManifest))
+	require.NoError(t, r.RecipeInstalled(status, RecipeStatusEvent{}))
+	require.Len(t, c.WrittenAccountScopeDocuments, 1)
+
+	require.NoError(t, r.InstallComplete(status))
+	require.Len(t, c.WrittenAccountScopeDocuments, 2)
+	require.Equal(t, 3, c.writeDocumentWithUserScopeCallCount)
+
+	written := c.WrittenAccountScopeDocuments[1]
+	require.Equal(t, "openInstallCampaign-rollout-1", written.Collection)
+	require.Equal(t, "web-01", written.DocumentID)
+
+	summary := written.Document.(HostInstallSummary)
+	require.Equal(t, HostInstallComplete, summary.Outcome)
+	require.Equal(t, status.CorrelationID, summary.CorrelationID)
+	require.Equal(t, "rollout-1", summary.CampaignID)
+	require.Equal(t, "rollout-1", c.WrittenUserScopeDocuments[0].(*InstallStatus).CampaignID)
+	require.Equal(t, "docker", summary.Container)
+	require.Equal(t, 1, summary.Installed)
+	require.Equal(t, 1, summary.Failed)
+	require.Equal(t, RecipeStatusTypes.FAILED, summary.Recipes["mysql"])
+}
+
+func TestAggregateStatus_Outcomes(t *testing.T) {
+	c := NewMockNerdStorageClient()
+	r := NewNerdStorageStatusReporter(c, WithAggregateStatus("rollout-1", 12345, time.Hour))
+	status := NewInstallStatus([]StatusSubscriber{}, NewConcreteSuccessLinkGenerator())
+
+	require.NoError(t, r.InstallCanceled(status))
+	status.Error = StatusError{Message: "something went wrong"}
+	require.NoError(t, r.InstallComplete(status))
+
+	require.Len(t, c.WrittenAccountScopeDocuments, 2)
+	require.Equal(t, HostInstallCanceled, c.WrittenAccountScopeDocuments[0].Document.(HostInstallSummary).Outcome)
+	require.Equal(t, status.CorrelationID, c.WrittenAccountScopeDocuments[0].DocumentID)
+	require.Equal(t, HostInstallFailed, c.WrittenAccountScopeDocuments[1].Document.(HostInstallSummary).Outcome)
+	require.Equal(t, "something went wrong", c.WrittenAccountScopeDocuments[1].Document.(HostInstallSummary).Error)
+}
+
+func TestAggregateStatus_ErrorStillWritesUserScope(t *testing.T) {
+	c := NewMockNerdStorageClient()
+	c.WriteDocumentWithAccountScopeErr = errors.New("error")
+	r := NewNerdStorageStatusReporter(c, WithAggregateStatus("rollout-1", 12345, time.Hour), WithRateLimitRetries(0, 0))
+	status := NewInstallStatus([]StatusSubscriber{}, NewConcreteSuccessLinkGenerator())
+
+	err := r.InstallComplete(status)
+	require.Error(t, err)
+	require.Equal(t, 1, c.writeDocumentWithUserScopeCallCount)
+}
+
+func TestNerdStorageStatusReporter_DegradesWhenUnavailable(t *testing.T) {
+	c := NewMockNerdStorageClient()
+	c.WriteDocumentWithUserScopeErr = errors.New("connection refused")
+	r := NewNerdStorageStatusReporter(c, WithDegradation(2, time.Hour))
+	status := NewInstallStatus([]StatusSubscriber{}, NewConcreteSuccessLinkGenerator())
+
+	require.Error(t, r.RecipeInstalling(status, RecipeStatusEvent{}))
+	require.False(t, r.health.Degraded())
+
+	// Once degraded, failures are no longer returned.
+	require.NoError(t, r.RecipeInstalled(status, RecipeStatusEvent{}))
+	require.True(t, r.health.Degraded())
+	require.Equal(t, 2, c.writeDocumentWithUserScopeCallCount)
+
+	// Writes while installing are deferred until the retry interval passes.
+	require.NoError(t, r.RecipeInstalling(status, RecipeStatusEvent{}))
+	require.NoError(t, r.RecipeFailed(status, RecipeStatusEvent{}))
+	require.Equal(t, 2, c.writeDocumentWithUserScopeCallCount)
+
+	// The final status is always written.
+	require.NoError(t, r.InstallComplete(status))
+	require.Equal(t, 3, c.writeDocumentWithUserScopeCallCount)
+}
+
+func TestNerdStorageStatusReporter_RecoversWhenAvailable(t *testing.T) {
+	c := NewMockNerdStorageClient()
+	c.WriteDocumentWithUserScopeErr = errors.New("connection refused")
+	r := NewNerdStorageStatusReporter(c, WithDegradation(1, 0))
+	status := NewInstallStatus([]StatusSubscriber{}, NewConcreteSuccessLinkGenerator())
+
+	require.NoError(t, r.RecipeInstalling(status, RecipeStatusEvent{}))
+	require.True(t, r.health.Degraded())
+
+	// With no retry interval, the next write is attempted and, succeeding,
+	// holds the status deferred so far.
+	c.WriteDocumentWithUserScopeErr = nil
+	require.NoError(t, r.RecipeInstalled(status, RecipeStatusEvent{}))
+	require.False(t, r.health.Degraded())
+	require.Equal(t, 2, c.writeDocumentWithUserScopeCallCount)
+
+	c.WriteDocumentWithUserScopeErr = errors.New("connection refused")
+	require.NoError(t, r.RecipeInstalled(status, RecipeStatusEvent{}))
+	require.True(t, r.health.Degraded())
+}
+
+func TestInstallStatus_ContinuesWhenNerdStorageUnavailable(t *testing.T) {
+	c := NewMockNerdStorageClient()
+	c.WriteDocumentWithUserScopeErr = errors.New("403 forbidden")
+	r := NewNerdStorageStatusReporter(c, WithDegradation(2, time.Hour))
+	m := NewMockStatusReporter()
+	status := NewInstallStatus([]StatusSubscriber{r, m}, NewConcreteSuccessLinkGenerator())
+
+	recipe := types.OpenInstallationRecipe{Name: "mysql"}
+	status.DiscoveryComplete(types.DiscoveryManifest{Hostname: "web-01"})
+	status.RecipeInstalling(RecipeStatusEvent{Recipe: recipe})
+	status.RecipeInstalled(RecipeStatusEvent{Recipe: recipe})
+	status.InstallComplete(nil)
+
+	require.True(t, r.health.Degraded())
+	require.Equal(t, 3, c.writeDocumentWithUserScopeCallCount)
+	require.Equal(t, 1, m.RecipeInstalledCallCount)
+	require.Equal(t, 1, m.InstallCompleteCallCount)
+	require.Equal(t, RecipeStatusTypes.INSTALLED, status.Statuses[0].Status)
+}
@@ -0,0 +1,255 @@
+package execution
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// filterClause is a single attribute comparison of a success link filter,
+// such as `tags.language` = 'java'.
+type filterClause struct {
+	Attribute string
+	Operator  string
+	Value     string
+	// Literal marks numeric and boolean values, which are not quoted.
+	Literal bool
+}
+
+// filterOperators are the comparisons a success link filter clause may use,
+// longest first so that they match greedily.
+var filterOperators = []string{"!=", "="}
+
+var nrqlLiteralRegex = regexp.MustCompile(`^(-?[0-9]+(\.[0-9]+)?|true|false)$`)
+
+// parseSuccessLinkFilter parses a success link filter made of attribute
+// comparisons joined with AND.  Attributes may be quoted with backticks and
+// values with single or double quotes, escaping embedded quotes with a
+// backslash or by doubling them.  A filter wrapped in double quotes, as recipes historically wrote
+// it, is unwrapped first.
+func parseSuccessLinkFilter(filter string) ([]filterClause, error) {
+	p := &filterParser{input: []rune(unwrapFilter(filter))}
+
+	clauses := []filterClause{}
+	for {
+		p.skipSpace()
+		c, err := p.clause()
+		if err != nil {
+			return nil, fmt.Errorf("could not parse filter %s: %s", filter, err)
+		}
+		clauses = append(clauses, c)
+
+		p.skipSpace()
+		if p.done() {
+			return clauses, nil
+		}
+
+		if !p.keyword("AND") {
+			return nil, fmt.Errorf("could not parse filter %s: expected AND at position %d", filter, p.pos)
+		}
+	}
+}
+
+// escapeNRQLString returns s as a single quoted NRQL string.
+func escapeNRQLString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+
+	return "'" + s + "'"
+}
+
+// quoteNRQLIdentifier returns s as a backtick quoted NRQL identifier.
+func quoteNRQLIdentifier(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+}
+
+// formatSuccessLinkFilter renders parsed clauses as a NRQL condition.
+func formatSuccessLinkFilter(clauses []filterClause) string {
+	parts := []string{}
+	for _, c := range clauses {
+		value := escapeNRQLString(c.Value)
+		if c.Literal {
+			value = c.Value
+		}
+
+		parts = append(parts, fmt.Sprintf("%s %s %s", quoteNRQLIdentifier(c.Attribute), c.Operator, value))
+	}
+
+	return strings.Join(parts, " AND ")
+}
+
+// encodeExplorerFilter returns the filter as the quoted string the explorer
+// expects.  Filters that cannot be parsed are passed on as written.
+func encodeExplorerFilter(filter string) string {
+	condition := unwrapFilter(filter)
+
+	if clauses, err := parseSuccessLinkFilter(filter); err == nil {
+		condition = formatSuccessLinkFilter(clauses)
+	}
+
+	data, err := json.Marshal(condition)
+	if err != nil {
+		return filter
+	}
+
+	return string(data)
+}
+
+// unwrapFilter removes the double quotes a filter may be wrapped in.
+func unwrapFilter(filter string) string {
+	filter = strings.TrimSpace(filter)
+
+	var unquoted string
+	if strings.HasPrefix(filter, `"`) && json.Unmarshal([]byte(filter), &unquoted) == nil {
+		return strings.TrimSpace(unquoted)
+	}
+
+	return filter
+}
+
+type filterParser struct {
+	input []rune
+	pos   int
+}
+
+func (p *filterParser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *filterParser) peek() rune {
+	return p.input[p.pos]
+}
+
+func (p *filterParser) skipSpace() {
+	for !p.done() && unicode.IsSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+// keyword consumes the given case-insensitive keyword when it is followed by
+// a space.
+func (p *filterParser) keyword(k string) bool {
+	end := p.pos + len(k)
+	if end >= len(p.input) || !strings.EqualFold(string(p.input[p.pos:end]), k) || !unicode.IsSpace(p.input[end]) {
+		return false
+	}
+
+	p.pos = end
+	return true
+}
+
+func (p *filterParser) clause() (filterClause, error) {
+	attribute, err := p.attribute()
+	if err != nil {
+		return filterClause{}, err
+	}
+
+	p.skipSpace()
+	operator, err := p.operator()
+	if err != nil {
+		return filterClause{}, err
+	}
+
+	p.skipSpace()
+	value, literal, err := p.value()
+	if err != nil {
+		return filterClause{}, err
+	}
+
+	return filterClause{
+		Attribute: attribute,
+		Operator:  operator,
+		Value:     value,
+		Literal:   literal,
+	}, nil
+}
+
+func (p *filterParser) attribute() (string, error) {
+	if p.done() {
+		return "", fmt.Errorf("expected an attribute at position %d", p.pos)
+	}
+
+	if p.peek() == '`' {
+		return p.quoted('`')
+	}
+
+	start := p.pos
+	for !p.done() && isAttributeRune(p.peek()) {
+		p.pos++
+	}
+
+	if start == p.pos {
+		return "", fmt.Errorf("expected an attribute at position %d", p.pos)
+	}
+
+	return string(p.input[start:p.pos]), nil
+}
+
+func (p *filterParser) operator() (string, error) {
+	for _, o := range filterOperators {
+		end := p.pos + len(o)
+		if end <= len(p.input) && string(p.input[p.pos:end]) == o {
+			p.pos = end
+			return o, nil
+		}
+	}
+
+	return "", fmt.Errorf("expected one of %s at position %d", strings.Join(filterOperators, ", "), p.pos)
+}
+
+func (p *filterParser) value() (string, bool, error) {
+	if p.done() {
+		return "", false, fmt.Errorf("expected a value at position %d", p.pos)
+	}
+
+	if r := p.peek(); r == '\'' || r == '"' {
+		v, err := p.quoted(r)
+		return v, false, err
+	}
+
+	start := p.pos
+	for !p.done() && !unicode.IsSpace(p.peek()) {
+		p.pos++
+	}
+
+	v := string(p.input[start:p.pos])
+	if !nrqlLiteralRegex.MatchString(v) {
+		return "", false, fmt.Errorf("unquoted value %s at position %d", v, start)
+	}
+
+	return v, true, nil
+}
+
+// quoted consumes a string quoted with q.  An embedded quote is escaped with a
+// backslash or, as NRQL identifiers do, by doubling it.
+func (p *filterParser) quoted(q rune) (string, error) {
+	start := p.pos
+	p.pos++
+
+	var b strings.Builder
+	for !p.done() {
+		r := p.peek()
+		p.pos++
+
+		switch {
+		case r == '\\' && !p.done():
+			b.WriteRune(p.peek())
+			p.pos++
+		case r == q && !p.done() && p.peek() == q:
+			b.WriteRune(q)
+			p.pos++
+		case r == q:
+			return b.String(), nil
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return "", fmt.Errorf("unterminated quote at position %d", start)
+}
+
+func isAttributeRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.'
+}
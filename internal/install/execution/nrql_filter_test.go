@@ -0,0 +1,59 @@
+//go:build unit
+// +build unit
+
+package execution
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSuccessLinkFilter(t *testing.T) {
+	clauses, err := parseSuccessLinkFilter("\"`tags.language` = 'java'\"")
+	require.NoError(t, err)
+	require.Equal(t, []filterClause{{Attribute: "tags.language", Operator: "=", Value: "java"}}, clauses)
+}
+
+func TestParseSuccessLinkFilter_MultipleClauses(t *testing.T) {
+	clauses, err := parseSuccessLinkFilter(`domain = 'INFRA' and  reporting != false AND name = "my host"`)
+	require.NoError(t, err)
+	require.Equal(t, []filterClause{
+		{Attribute: "domain", Operator: "=", Value: "INFRA"},
+		{Attribute: "reporting", Operator: "!=", Value: "false", Literal: true},
+		{Attribute: "name", Operator: "=", Value: "my host"},
+	}, clauses)
+}
+
+func TestParseSuccessLinkFilter_EscapedQuotes(t *testing.T) {
+	clauses, err := parseSuccessLinkFilter("`odd``name` = 'it\\'s' AND `tags.team name` = 'o''brien'")
+	require.NoError(t, err)
+	require.Equal(t, "odd`name", clauses[0].Attribute)
+	require.Equal(t, "it's", clauses[0].Value)
+	require.Equal(t, "tags.team name", clauses[1].Attribute)
+	require.Equal(t, "o'brien", clauses[1].Value)
+}
+
+func TestParseSuccessLinkFilter_Invalid(t *testing.T) {
+	for _, filter := range []string{"", "testFilter", "name = 'unterminated", "name = java", "a = 'b' OR c = 'd'"} {
+		_, err := parseSuccessLinkFilter(filter)
+		require.Error(t, err, filter)
+	}
+}
+
+func TestEscapeNRQLString(t *testing.T) {
+	require.Equal(t, `'java'`, escapeNRQLString("java"))
+	require.Equal(t, `'it\'s a \\path'`, escapeNRQLString(`it's a \path`))
+}
+
+func TestQuoteNRQLIdentifier(t *testing.T) {
+	require.Equal(t, "`tags.language`", quoteNRQLIdentifier("tags.language"))
+	require.Equal(t, "`odd``name`", quoteNRQLIdentifier("odd`name"))
+}
+
+func TestEncodeExplorerFilter(t *testing.T) {
+	require.Equal(t, "\"`tags.language` = 'java'\"", encodeExplorerFilter("\"`tags.language` = 'java'\""))
+	require.Equal(t, "\"`tags.language` = 'java'\"", encodeExplorerFilter("tags.language = \"java\""))
+	require.Equal(t, "\"`name` = 'my \\\"quoted\\\" host\\\\'s'\"", encodeExplorerFilter(`name = 'my "quoted" host''s'`))
+	require.Equal(t, `"testFilter"`, encodeExplorerFilter("testFilter"))
+}
@@ -0,0 +1,68 @@
+package execution
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+)
+
+const defaultOutputTailLines = 20
+
+// outputTail is an io.Writer that retains the last n lines written to it.  It
+// is used to capture recipe output so it can be surfaced when a recipe fails.
+type outputTail struct {
+	mu      sync.Mutex
+	max     int
+	lines   []string
+	partial bytes.Buffer
+}
+
+func newOutputTail(max int) *outputTail {
+	return &outputTail{
+		max: max,
+	}
+}
+
+func (t *outputTail) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.partial.Write(p)
+
+	for {
+		line, err := t.partial.ReadString('\n')
+		if err != nil {
+			// No newline found; keep the remainder for the next write.
+			t.partial.Reset()
+			t.partial.WriteString(line)
+			break
+		}
+
+		t.append(strings.TrimRight(line, "\r\n"))
+	}
+
+	return len(p), nil
+}
+
+// Lines returns the retained lines, including any trailing partial line.
+func (t *outputTail) Lines() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	lines := make([]string, len(t.lines))
+	copy(lines, t.lines)
+
+	if t.partial.Len() > 0 {
+		lines = append(lines, t.partial.String())
+	}
+
+	return lines
+}
+
+func (t *outputTail) append(line string) {
+	t.lines = append(t.lines, line)
+
+	if len(t.lines) > t.max {
+		t.lines = t.lines[len(t.lines)-t.max:]
+	}
+}
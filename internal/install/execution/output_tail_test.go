@@ -0,0 +1,33 @@
+//go:build unit
+// +build unit
+
+package execution
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputTail_RetainsLastLines(t *testing.T) {
+	tail := newOutputTail(3)
+
+	for i := 1; i <= 5; i++ {
+		_, err := fmt.Fprintf(tail, "line %d\n", i)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, []string{"line 3", "line 4", "line 5"}, tail.Lines())
+}
+
+func TestOutputTail_PartialLines(t *testing.T) {
+	tail := newOutputTail(3)
+
+	_, err := tail.Write([]byte("first "))
+	require.NoError(t, err)
+	_, err = tail.Write([]byte("line\nsecond"))
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"first line", "second"}, tail.Lines())
+}
@@ -0,0 +1,145 @@
+package execution
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// caBundleEnvVars are the variables that point the tools recipe steps commonly
+// run, such as OpenSSL, curl and Python's requests, at the CA bundle they
+// trust in place of the system's.
+var caBundleEnvVars = []string{
+	"SSL_CERT_FILE",
+	"CURL_CA_BUNDLE",
+	"REQUESTS_CA_BUNDLE",
+}
+
+// caBundleExtraEnvVars are the variables that point tools at certificates they
+// trust in addition to the system's, such as Node.js.
+var caBundleExtraEnvVars = []string{
+	"NODE_EXTRA_CA_CERTS",
+}
+
+// systemCABundleFiles are the locations of the system's bundle of trusted
+// certificates on the platforms recipes run on, in the order they are looked
+// for.
+var systemCABundleFiles = []string{
+	"/etc/ssl/certs/ca-certificates.crt",
+	"/etc/pki/tls/certs/ca-bundle.crt",
+	"/etc/ssl/ca-bundle.pem",
+	"/etc/pki/tls/cacert.pem",
+	"/etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem",
+	"/etc/ssl/cert.pem",
+}
+
+// applyCABundle points the environment recipe steps run in at the CA bundle,
+// when one is given, returning a func restoring the environment.  Tools that
+// replace the system's trusted certificates with the bundle they are pointed
+// at are given the system's certificates along with those of the CA bundle,
+// as the installer's own clients trust, so that downloads not going through a
+// TLS-inspecting proxy still verify.
+func (re *GoTaskRecipeExecutor) applyCABundle(recipeName string) func() {
+	if re.CABundle == "" {
+		return func() {}
+	}
+
+	log.Debugf("running recipe %s with the CA bundle %s", recipeName, re.CABundle)
+
+	bundle, err := withSystemCABundle(re.CABundle)
+	if err != nil {
+		log.Debugf("could not add the system's certificates to the CA bundle: %s", err)
+		bundle = re.CABundle
+	}
+
+	if bundle != re.CABundle {
+		re.Cleanup.Register(bundle)
+	}
+
+	original := map[string]*string{}
+	setEnv := func(k string, v string) {
+		if o, ok := os.LookupEnv(k); ok {
+			original[k] = &o
+		} else {
+			original[k] = nil
+		}
+
+		os.Setenv(k, v)
+	}
+
+	for _, k := range caBundleEnvVars {
+		setEnv(k, bundle)
+	}
+
+	for _, k := range caBundleExtraEnvVars {
+		setEnv(k, re.CABundle)
+	}
+
+	return func() {
+		for k, v := range original {
+			if v == nil {
+				os.Unsetenv(k)
+			} else {
+				os.Setenv(k, *v)
+			}
+		}
+
+		if bundle != re.CABundle {
+			re.Cleanup.Release(bundle)
+		}
+	}
+}
+
+// withSystemCABundle writes the system's bundle of trusted certificates
+// followed by the given CA bundle to a private temporary file, returning its
+// path.  The bundle SSL_CERT_FILE points at, when set, is taken as the
+// system's.
+func withSystemCABundle(caBundle string) (string, error) {
+	custom, err := ioutil.ReadFile(caBundle)
+	if err != nil {
+		return "", err
+	}
+
+	system, err := readSystemCABundle()
+	if err != nil {
+		return "", err
+	}
+
+	file, err := ioutil.TempFile("", "newrelic-ca-bundle-*.pem")
+	if err != nil {
+		return "", err
+	}
+
+	var combined bytes.Buffer
+	combined.Write(bytes.TrimSpace(system))
+	combined.WriteString("\n")
+	combined.Write(custom)
+
+	_, err = file.Write(combined.Bytes())
+	file.Close()
+	if err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+
+	return file.Name(), nil
+}
+
+func readSystemCABundle() ([]byte, error) {
+	files := systemCABundleFiles
+	if f := os.Getenv("SSL_CERT_FILE"); f != "" {
+		files = append([]string{f}, files...)
+	}
+
+	var err error
+	for _, f := range files {
+		var data []byte
+		if data, err = ioutil.ReadFile(f); err == nil {
+			return data, nil
+		}
+	}
+
+	return nil, err
+}
@@ -0,0 +1,70 @@
+//go:build unit
+// +build unit
+
+package execution
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSystemCert = "-----BEGIN CERTIFICATE-----\nc3lzdGVt\n-----END CERTIFICATE-----\n"
+	testProxyCert  = "-----BEGIN CERTIFICATE-----\ncHJveHk=\n-----END CERTIFICATE-----\n"
+)
+
+func TestApplyCABundle(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "newrelic")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	systemBundle := filepath.Join(tmp, "system.pem")
+	require.NoError(t, ioutil.WriteFile(systemBundle, []byte(testSystemCert), 0600))
+	caBundle := filepath.Join(tmp, "ca.pem")
+	require.NoError(t, ioutil.WriteFile(caBundle, []byte(testProxyCert), 0600))
+
+	value, ok := os.LookupEnv("SSL_CERT_FILE")
+	os.Setenv("SSL_CERT_FILE", systemBundle)
+	if ok {
+		defer os.Setenv("SSL_CERT_FILE", value)
+	} else {
+		defer os.Unsetenv("SSL_CERT_FILE")
+	}
+	curl, curlOk := os.LookupEnv("CURL_CA_BUNDLE")
+	os.Unsetenv("CURL_CA_BUNDLE")
+	if curlOk {
+		defer os.Setenv("CURL_CA_BUNDLE", curl)
+	}
+
+	re := NewGoTaskRecipeExecutor()
+	re.CABundle = caBundle
+
+	restore := re.applyCABundle("test-recipe")
+
+	combined := os.Getenv("SSL_CERT_FILE")
+	require.NotEqual(t, systemBundle, combined)
+	for _, k := range caBundleEnvVars {
+		require.Equal(t, combined, os.Getenv(k))
+	}
+	require.Equal(t, caBundle, os.Getenv("NODE_EXTRA_CA_CERTS"))
+
+	data, err := ioutil.ReadFile(combined)
+	require.NoError(t, err)
+	require.Contains(t, string(data), testSystemCert)
+	require.Contains(t, string(data), testProxyCert)
+
+	restore()
+	require.Equal(t, systemBundle, os.Getenv("SSL_CERT_FILE"))
+	_, ok = os.LookupEnv("CURL_CA_BUNDLE")
+	require.False(t, ok)
+	require.NoFileExists(t, combined)
+}
+
+func TestApplyCABundle_None(t *testing.T) {
+	re := NewGoTaskRecipeExecutor()
+	re.applyCABundle("test-recipe")()
+}
@@ -0,0 +1,68 @@
+//go:build unit
+// +build unit
+
+package execution
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/ux"
+)
+
+var testInputVars = []types.OpenInstallationRecipeInputVariable{
+	{Name: "TEST_INPUT_PORT", Prompt: "MySQL port", Default: "3306"},
+	{Name: "TEST_INPUT_PASSWORD", Prompt: "MySQL password", Secret: true},
+}
+
+func TestVarsFromInput_Prompts(t *testing.T) {
+	p := ux.NewMockPrompter()
+	p.PromptInputVals = []string{"3307", "hunter2"}
+
+	vars, err := varsFromInput(p, testInputVars, false, types.RecipeVars{})
+	require.NoError(t, err)
+	require.Equal(t, "3307", vars["TEST_INPUT_PORT"])
+	require.Equal(t, "hunter2", vars["TEST_INPUT_PASSWORD"])
+	require.Equal(t, 2, p.PromptInputCallCount)
+	require.Equal(t, "MySQL password", p.PromptInputMsg)
+	require.True(t, p.PromptInputSecret)
+}
+
+func TestVarsFromInput_ProvidedNotPrompted(t *testing.T) {
+	os.Setenv("TEST_INPUT_PORT", "3308")
+	defer os.Unsetenv("TEST_INPUT_PORT")
+
+	p := ux.NewMockPrompter()
+
+	vars, err := varsFromInput(p, testInputVars, false, types.RecipeVars{"TEST_INPUT_PASSWORD": "from-file"})
+	require.NoError(t, err)
+	require.Equal(t, "3308", vars["TEST_INPUT_PORT"])
+	require.Equal(t, "from-file", vars["TEST_INPUT_PASSWORD"])
+	require.Equal(t, 0, p.PromptInputCallCount)
+}
+
+func TestVarsFromInput_PromptInterrupted(t *testing.T) {
+	p := ux.NewMockPrompter()
+	p.PromptInputErr = types.ErrInterrupt
+
+	_, err := varsFromInput(p, testInputVars, false, types.RecipeVars{})
+	require.Equal(t, types.ErrInterrupt, err)
+}
+
+func TestVarsFromInput_AssumeYesRequiresValues(t *testing.T) {
+	p := ux.NewMockPrompter()
+
+	require.Equal(t, []string{"TEST_INPUT_PASSWORD"}, missingInputVars(testInputVars, types.RecipeVars{}))
+
+	_, err := varsFromInput(p, testInputVars, true, types.RecipeVars{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "TEST_INPUT_PASSWORD")
+
+	vars, err := varsFromInput(p, testInputVars, true, types.RecipeVars{"TEST_INPUT_PASSWORD": "from-file"})
+	require.NoError(t, err)
+	require.Equal(t, "3306", vars["TEST_INPUT_PORT"])
+	require.Equal(t, 0, p.PromptInputCallCount)
+}
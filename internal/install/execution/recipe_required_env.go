@@ -0,0 +1,100 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/remote"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// envVarName matches the names of environment variables a shell can read.
+var envVarName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ErrMissingEnvVars is returned when environment variables a recipe requires
+// are neither set nor provided as recipe variables.
+type ErrMissingEnvVars struct {
+	RecipeName string
+	Names      []string
+}
+
+func NewErrMissingEnvVars(recipeName string, names []string) ErrMissingEnvVars {
+	return ErrMissingEnvVars{
+		RecipeName: recipeName,
+		Names:      names,
+	}
+}
+
+func (e ErrMissingEnvVars) Error() string {
+	return fmt.Sprintf("%s requires environment variables that are not set: %s. Set them, or provide them with --recipe-vars-file, and try again", e.RecipeName, strings.Join(e.Names, ", "))
+}
+
+// checkRequiredEnvVars ensures each environment variable the recipe requires
+// is either provided as a recipe variable or set, as reported by lookup.
+func checkRequiredEnvVars(r types.OpenInstallationRecipe, vars types.RecipeVars, lookup func(string) (string, bool)) error {
+	missing := []string{}
+	for _, name := range r.RequiredEnvVars {
+		if vars[name] != "" {
+			continue
+		}
+
+		if v, ok := lookup(name); ok && v != "" {
+			continue
+		}
+
+		missing = append(missing, name)
+	}
+
+	if len(missing) > 0 {
+		return NewErrMissingEnvVars(r.Name, missing)
+	}
+
+	return nil
+}
+
+// remoteEnvLookup returns a lookup of the environment variables set on the
+// remote host for the given names, read with a single command.  Names that are
+// not valid variable names are reported unset.
+func remoteEnvLookup(ctx context.Context, runner remote.Runner, names []string) (func(string) (string, bool), error) {
+	valid := []string{}
+	for _, name := range names {
+		if envVarName.MatchString(name) {
+			valid = append(valid, name)
+		}
+	}
+
+	set := map[string]bool{}
+	if len(valid) > 0 {
+		out, err := runner.Output(ctx, remoteSetEnvVarsCmd(valid))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range strings.Fields(out) {
+			set[name] = true
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"host": runner.Host(),
+		"set":  set,
+	}).Debug("checked required environment variables")
+
+	return func(name string) (string, bool) {
+		if set[name] {
+			// Only whether the variable is set is known.
+			return name, true
+		}
+		return "", false
+	}, nil
+}
+
+// remoteSetEnvVarsCmd returns a command echoing which of the given variables
+// are set to a non-empty value.
+func remoteSetEnvVarsCmd(names []string) string {
+	return fmt.Sprintf(`for v in %s; do [ -n "$(printenv "$v")" ] && echo "$v"; done; true`, strings.Join(names, " "))
+}
@@ -0,0 +1,92 @@
+//go:build unit
+// +build unit
+
+package execution
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/remote"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func testEnv(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+}
+
+func TestCheckRequiredEnvVars_Present(t *testing.T) {
+	r := types.OpenInstallationRecipe{
+		Name:            "mysql",
+		RequiredEnvVars: []string{"MYSQL_USER", "MYSQL_PASSWORD"},
+	}
+
+	err := checkRequiredEnvVars(r, types.RecipeVars{}, testEnv(map[string]string{
+		"MYSQL_USER":     "newrelic",
+		"MYSQL_PASSWORD": "secret",
+	}))
+	require.NoError(t, err)
+}
+
+func TestCheckRequiredEnvVars_Missing(t *testing.T) {
+	r := types.OpenInstallationRecipe{
+		Name:            "mysql",
+		RequiredEnvVars: []string{"MYSQL_USER", "MYSQL_PASSWORD", "MYSQL_PORT"},
+	}
+
+	err := checkRequiredEnvVars(r, types.RecipeVars{}, testEnv(map[string]string{
+		"MYSQL_USER": "newrelic",
+		"MYSQL_PORT": "",
+	}))
+
+	var merr ErrMissingEnvVars
+	require.True(t, errors.As(err, &merr))
+	require.Equal(t, "mysql", merr.RecipeName)
+	require.Equal(t, []string{"MYSQL_PASSWORD", "MYSQL_PORT"}, merr.Names)
+	require.Contains(t, err.Error(), "MYSQL_PASSWORD, MYSQL_PORT")
+}
+
+func TestCheckRequiredEnvVars_ProvidedAsRecipeVars(t *testing.T) {
+	r := types.OpenInstallationRecipe{
+		Name:            "mysql",
+		RequiredEnvVars: []string{"MYSQL_USER", "MYSQL_PASSWORD"},
+	}
+
+	err := checkRequiredEnvVars(r, types.RecipeVars{"MYSQL_PASSWORD": "secret"}, testEnv(map[string]string{
+		"MYSQL_USER": "newrelic",
+	}))
+	require.NoError(t, err)
+}
+
+func TestSSHRecipeExecutor_PrepareChecksRemoteEnv(t *testing.T) {
+	r := types.OpenInstallationRecipe{
+		Name:            "mysql",
+		RequiredEnvVars: []string{"MYSQL_USER", "MYSQL_PASSWORD", "NOT A NAME"},
+	}
+
+	runner := remote.NewMockRunner("db-1")
+	runner.Responses["for v in"] = remote.MockResponse{Output: "MYSQL_USER\n"}
+	re := NewSSHRecipeExecutor(runner, NewMockRecipeExecutor())
+
+	_, err := re.Prepare(context.Background(), types.DiscoveryManifest{}, r, true, "")
+
+	var merr ErrMissingEnvVars
+	require.True(t, errors.As(err, &merr))
+	require.Equal(t, []string{"MYSQL_PASSWORD", "NOT A NAME"}, merr.Names)
+	require.Equal(t, []string{remoteSetEnvVarsCmd([]string{"MYSQL_USER", "MYSQL_PASSWORD"})}, runner.Commands)
+}
+
+func TestSSHRecipeExecutor_PrepareWithoutRequiredEnv(t *testing.T) {
+	runner := remote.NewMockRunner("db-1")
+	re := NewSSHRecipeExecutor(runner, NewMockRecipeExecutor())
+
+	_, err := re.Prepare(context.Background(), types.DiscoveryManifest{}, types.OpenInstallationRecipe{Name: "mysql"}, true, "")
+	require.NoError(t, err)
+	require.Empty(t, runner.Commands)
+}
@@ -0,0 +1,140 @@
+package execution
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/go-task/task/v3"
+	log "github.com/sirupsen/logrus"
+)
+
+// shellInterpreter describes how to run a single command with a shell.
+type shellInterpreter struct {
+	// Executable is the program looked up in the PATH.
+	Executable string
+	// Args precede the command passed to the executable.
+	Args []string
+	// WindowsOnly marks interpreters only available on Windows.
+	WindowsOnly bool
+}
+
+// shellInterpreters are the shells recipe steps can be run with.  Steps of
+// recipes that name no shell are run by go-task's built-in POSIX interpreter.
+var shellInterpreters = map[string]shellInterpreter{
+	"sh":         {Executable: "sh", Args: []string{"-c"}},
+	"bash":       {Executable: "bash", Args: []string{"-c"}},
+	"pwsh":       {Executable: "pwsh", Args: []string{"-NoProfile", "-NonInteractive", "-Command"}},
+	"powershell": {Executable: "powershell", Args: []string{"-NoProfile", "-NonInteractive", "-Command"}, WindowsOnly: true},
+	"cmd":        {Executable: "cmd", Args: []string{"/C"}, WindowsOnly: true},
+}
+
+// lookPath finds the executable of a shell, and is replaced in tests.
+var lookPath = exec.LookPath
+
+// ShellNames returns the names of the supported shells.
+func ShellNames() []string {
+	names := []string{}
+	for name := range shellInterpreters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ValidateShellName returns an error when the named shell is not supported.
+// An empty name selects the default interpreter.
+func ValidateShellName(name string) error {
+	if name == "" {
+		return nil
+	}
+
+	if _, ok := shellInterpreters[strings.ToLower(name)]; !ok {
+		return fmt.Errorf("unsupported shell %s, must be one of: %s", name, strings.Join(ShellNames(), ", "))
+	}
+
+	return nil
+}
+
+// selectShell returns the interpreter the steps of a recipe are run with on
+// the given operating system: the override if set, otherwise the recipe's own
+// shell.  No interpreter is returned when neither is set.  An error is
+// returned when the shell is not supported on the operating system or is not
+// installed.
+func selectShell(override string, recipeShell string, goos string) (*shellInterpreter, error) {
+	name := strings.ToLower(override)
+	if name == "" {
+		name = strings.ToLower(recipeShell)
+	}
+
+	if name == "" {
+		return nil, nil
+	}
+
+	if err := ValidateShellName(name); err != nil {
+		return nil, err
+	}
+
+	sh := shellInterpreters[name]
+	if sh.WindowsOnly && goos != "windows" {
+		return nil, fmt.Errorf("shell %s is only available on windows", name)
+	}
+
+	path, err := lookPath(sh.Executable)
+	if err != nil {
+		return nil, fmt.Errorf("shell %s is required but was not found in the PATH", name)
+	}
+
+	sh.Executable = path
+
+	return &sh, nil
+}
+
+// applyShell arranges for the steps of the recipe to be run with its selected
+// shell instead of go-task's built-in interpreter.
+func (re *GoTaskRecipeExecutor) applyShell(e *task.Executor, recipeName string, recipeShell string) error {
+	sh, err := selectShell(re.Shell, recipeShell, runtime.GOOS)
+	if err != nil {
+		return fmt.Errorf("could not run recipe %s: %s", recipeName, err)
+	}
+
+	if sh == nil {
+		return nil
+	}
+
+	log.WithFields(log.Fields{
+		"name":  recipeName,
+		"shell": sh.Executable,
+	}).Debug("running recipe steps with shell")
+
+	for _, t := range e.Taskfile.Tasks {
+		for _, c := range t.Cmds {
+			if c.Cmd == "" {
+				continue
+			}
+
+			c.Cmd = sh.command(c.Cmd)
+		}
+	}
+
+	return nil
+}
+
+// command returns a command that runs cmd with the shell.
+func (sh *shellInterpreter) command(cmd string) string {
+	parts := []string{shellQuote(sh.Executable)}
+	for _, a := range sh.Args {
+		parts = append(parts, shellQuote(a))
+	}
+	parts = append(parts, shellQuote(cmd))
+
+	return strings.Join(parts, " ")
+}
+
+// shellQuote quotes s as a single word for go-task's POSIX interpreter.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
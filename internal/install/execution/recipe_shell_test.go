@@ -0,0 +1,87 @@
+// +build unit
+
+package execution
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func withLookPath(found map[string]bool) func() {
+	lookPath = func(file string) (string, error) {
+		if found[file] {
+			return "/usr/bin/" + file, nil
+		}
+		return "", errors.New("not found")
+	}
+
+	return func() { lookPath = exec.LookPath }
+}
+
+func TestSelectShell_NoneSelected(t *testing.T) {
+	sh, err := selectShell("", "", "linux")
+	require.NoError(t, err)
+	require.Nil(t, sh)
+}
+
+func TestSelectShell_Recipe(t *testing.T) {
+	defer withLookPath(map[string]bool{"bash": true})()
+
+	sh, err := selectShell("", "Bash", "linux")
+	require.NoError(t, err)
+	require.Equal(t, "/usr/bin/bash", sh.Executable)
+	require.Equal(t, []string{"-c"}, sh.Args)
+}
+
+func TestSelectShell_OverrideWins(t *testing.T) {
+	defer withLookPath(map[string]bool{"bash": true, "sh": true})()
+
+	sh, err := selectShell("sh", "bash", "linux")
+	require.NoError(t, err)
+	require.Equal(t, "/usr/bin/sh", sh.Executable)
+}
+
+func TestSelectShell_Windows(t *testing.T) {
+	defer withLookPath(map[string]bool{"cmd": true, "powershell": true})()
+
+	sh, err := selectShell("", "cmd", "windows")
+	require.NoError(t, err)
+	require.Equal(t, []string{"/C"}, sh.Args)
+
+	sh, err = selectShell("", "powershell", "windows")
+	require.NoError(t, err)
+	require.Equal(t, "/usr/bin/powershell", sh.Executable)
+
+	_, err = selectShell("", "powershell", "linux")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "only available on windows")
+}
+
+func TestSelectShell_Missing(t *testing.T) {
+	defer withLookPath(map[string]bool{})()
+
+	_, err := selectShell("", "pwsh", "linux")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "shell pwsh is required but was not found")
+}
+
+func TestSelectShell_Unsupported(t *testing.T) {
+	_, err := selectShell("fish", "", "linux")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unsupported shell fish")
+}
+
+func TestValidateShellName(t *testing.T) {
+	require.NoError(t, ValidateShellName(""))
+	require.NoError(t, ValidateShellName("PowerShell"))
+	require.Error(t, ValidateShellName("zsh"))
+}
+
+func TestShellInterpreterCommand(t *testing.T) {
+	sh := shellInterpreter{Executable: "/bin/bash", Args: []string{"-c"}}
+
+	require.Equal(t, `'/bin/bash' '-c' 'echo '"'"'hi'"'"' && [[ -n "$X" ]]'`, sh.command(`echo 'hi' && [[ -n "$X" ]]`))
+}
@@ -0,0 +1,88 @@
+package execution
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// loadRecipeVarsFile reads recipe variables from the YAML file at the given
+// path.  Values may be strings, numbers or booleans and are converted to their
+// string form for use by go-task.
+func loadRecipeVarsFile(path string) (types.RecipeVars, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return types.RecipeVars{}, fmt.Errorf("could not read recipe vars file %s: %s", path, err)
+	}
+
+	var raw map[string]interface{}
+	if err = yaml.Unmarshal(data, &raw); err != nil {
+		return types.RecipeVars{}, fmt.Errorf("could not parse recipe vars file %s: %s", path, err)
+	}
+
+	vars := make(types.RecipeVars)
+	for k, v := range raw {
+		s, err := recipeVarToString(v)
+		if err != nil {
+			return types.RecipeVars{}, fmt.Errorf("invalid value for %s in %s: %s", k, path, err)
+		}
+
+		vars[k] = s
+	}
+
+	return vars, nil
+}
+
+func recipeVarToString(v interface{}) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return val, nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	case int:
+		return strconv.Itoa(val), nil
+	case int64:
+		return strconv.FormatInt(val, 10), nil
+	case uint64:
+		return strconv.FormatUint(val, 10), nil
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("expected a string, number or boolean but got %T", v)
+	}
+}
+
+// validateRecipeVars checks the given variables against the input variables
+// declared by the recipe.  Recipes that declare no input variables accept any
+// variable.
+func validateRecipeVars(r types.OpenInstallationRecipe, vars types.RecipeVars) error {
+	if len(r.InputVars) == 0 {
+		return nil
+	}
+
+	declared := map[string]bool{}
+	for _, v := range r.InputVars {
+		declared[v.Name] = true
+	}
+
+	unknown := []string{}
+	for k := range vars {
+		if !declared[k] {
+			unknown = append(unknown, k)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("recipe %s does not declare variables %v", r.Name, unknown)
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+//go:build unit
+// +build unit
+
+package execution
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/credentials"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func writeVarsFile(t *testing.T, content string) string {
+	f, err := ioutil.TempFile("", "recipe-vars-*.yml")
+	require.NoError(t, err)
+
+	_, err = f.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	return f.Name()
+}
+
+func TestLoadRecipeVarsFile_TypedValues(t *testing.T) {
+	path := writeVarsFile(t, "HOST: db.local\nPORT: 5432\nRATIO: 0.5\nENABLED: true\n")
+	defer os.Remove(path)
+
+	vars, err := loadRecipeVarsFile(path)
+	require.NoError(t, err)
+	require.Equal(t, types.RecipeVars{
+		"HOST":    "db.local",
+		"PORT":    "5432",
+		"RATIO":   "0.5",
+		"ENABLED": "true",
+	}, vars)
+}
+
+func TestLoadRecipeVarsFile_RejectsNestedValues(t *testing.T) {
+	path := writeVarsFile(t, "HOSTS:\n  - a\n  - b\n")
+	defer os.Remove(path)
+
+	_, err := loadRecipeVarsFile(path)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "HOSTS")
+}
+
+func TestLoadRecipeVarsFile_MissingFile(t *testing.T) {
+	_, err := loadRecipeVarsFile("does-not-exist.yml")
+	require.Error(t, err)
+}
+
+func TestValidateRecipeVars(t *testing.T) {
+	r := types.OpenInstallationRecipe{
+		Name:      "test",
+		InputVars: []types.OpenInstallationRecipeInputVariable{{Name: "HOST"}},
+	}
+
+	require.NoError(t, validateRecipeVars(r, types.RecipeVars{"HOST": "a"}))
+	require.Error(t, validateRecipeVars(r, types.RecipeVars{"HOTS": "a"}))
+	require.NoError(t, validateRecipeVars(types.OpenInstallationRecipe{}, types.RecipeVars{"ANY": "a"}))
+}
+
+func TestPrepare_MergesVarsFile(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+	path := writeVarsFile(t, "TEST_VARS_FILE_HOST: db.local\nTEST_VARS_FILE_PORT: 5432\n")
+	defer os.Remove(path)
+
+	e := NewGoTaskRecipeExecutor()
+	e.VarsFiles = map[string]string{"test": path}
+	r := types.OpenInstallationRecipe{
+		Name: "test",
+		InputVars: []types.OpenInstallationRecipeInputVariable{
+			{Name: "TEST_VARS_FILE_HOST"},
+			{Name: "TEST_VARS_FILE_PORT"},
+		},
+	}
+
+	vars, err := e.Prepare(context.Background(), types.DiscoveryManifest{}, r, true, "testLicenseKey")
+	require.NoError(t, err)
+	require.Equal(t, "db.local", vars["TEST_VARS_FILE_HOST"])
+	require.Equal(t, "5432", vars["TEST_VARS_FILE_PORT"])
+}
+
+func TestPrepare_ReportsAllMissingVars(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+
+	e := NewGoTaskRecipeExecutor()
+	r := types.OpenInstallationRecipe{
+		Name: "test",
+		InputVars: []types.OpenInstallationRecipeInputVariable{
+			{Name: "TEST_VARS_FILE_USER"},
+			{Name: "TEST_VARS_FILE_LEVEL", Default: "info"},
+			{Name: "TEST_VARS_FILE_PASSWORD"},
+		},
+	}
+
+	_, err := e.Prepare(context.Background(), types.DiscoveryManifest{}, r, true, "testLicenseKey")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "TEST_VARS_FILE_USER, TEST_VARS_FILE_PASSWORD")
+}
+
+func TestPrepare_AppliesPlatformDefaults(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+
+	e := NewGoTaskRecipeExecutor()
+	r := types.OpenInstallationRecipe{
+		Name: "test",
+		InputVars: []types.OpenInstallationRecipeInputVariable{
+			{Name: "TEST_PLATFORM_CONFIG_DIR", Default: "/opt/newrelic"},
+		},
+		PlatformDefaults: map[string]map[string]string{
+			"linux":   {"TEST_PLATFORM_CONFIG_DIR": "/etc/newrelic", "TEST_PLATFORM_SERVICE": "systemd"},
+			"windows": {"TEST_PLATFORM_CONFIG_DIR": `C:\newrelic`},
+		},
+	}
+
+	vars, err := e.Prepare(context.Background(), types.DiscoveryManifest{OS: "linux"}, r, true, "testLicenseKey")
+	require.NoError(t, err)
+	require.Equal(t, "/etc/newrelic", vars["TEST_PLATFORM_CONFIG_DIR"])
+	require.Equal(t, "systemd", vars["TEST_PLATFORM_SERVICE"])
+
+	vars, err = e.Prepare(context.Background(), types.DiscoveryManifest{OS: "windows"}, r, true, "testLicenseKey")
+	require.NoError(t, err)
+	require.Equal(t, `C:\newrelic`, vars["TEST_PLATFORM_CONFIG_DIR"])
+
+	vars, err = e.Prepare(context.Background(), types.DiscoveryManifest{OS: "darwin"}, r, true, "testLicenseKey")
+	require.NoError(t, err)
+	require.Equal(t, "/opt/newrelic", vars["TEST_PLATFORM_CONFIG_DIR"])
+}
+
+func TestPrepare_EnvOverridesPlatformDefaults(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+	os.Setenv("TEST_PLATFORM_SERVICE", "sysv")
+	defer os.Unsetenv("TEST_PLATFORM_SERVICE")
+
+	e := NewGoTaskRecipeExecutor()
+	r := types.OpenInstallationRecipe{
+		Name: "test",
+		PlatformDefaults: map[string]map[string]string{
+			"linux": {"TEST_PLATFORM_SERVICE": "systemd"},
+		},
+	}
+
+	vars, err := e.Prepare(context.Background(), types.DiscoveryManifest{OS: "linux"}, r, true, "testLicenseKey")
+	require.NoError(t, err)
+	require.Equal(t, "sysv", vars["TEST_PLATFORM_SERVICE"])
+}
@@ -0,0 +1,70 @@
+package execution
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// workingDirPerm is the mode of the working directories created for recipes.
+const workingDirPerm = 0755
+
+// resolveWorkingDir returns the absolute directory the steps of the recipe run
+// in, with the recipe's variables, then those of the environment, expanded in
+// its working directory.  The directory is created when missing, unless create
+// is false.  An empty string is returned when the recipe sets none, and the
+// steps run in the current directory.
+func resolveWorkingDir(r types.OpenInstallationRecipe, vars types.RecipeVars, create bool) (string, error) {
+	if r.WorkingDir == "" {
+		return "", nil
+	}
+
+	dir := os.Expand(r.WorkingDir, func(name string) string {
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		return os.Getenv(name)
+	})
+	if dir == "" {
+		return "", fmt.Errorf("the working directory %s of recipe %s expands to nothing", r.WorkingDir, r.Name)
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("invalid working directory %s for recipe %s: %s", dir, r.Name, err)
+	}
+
+	info, err := os.Stat(dir)
+	switch {
+	case err == nil && !info.IsDir():
+		return "", fmt.Errorf("the working directory %s of recipe %s is not a directory", dir, r.Name)
+	case err == nil:
+		return dir, nil
+	case !os.IsNotExist(err):
+		return "", fmt.Errorf("could not access the working directory %s of recipe %s: %s", dir, r.Name, err)
+	case !create:
+		return dir, nil
+	}
+
+	if err := os.MkdirAll(dir, workingDirPerm); err != nil {
+		return "", fmt.Errorf("could not create the working directory %s of recipe %s: %s", dir, r.Name, err)
+	}
+
+	return dir, nil
+}
+
+// logWorkingDir reports the directory the steps of the recipe run in.
+func logWorkingDir(recipeName string, dir string) {
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+
+	log.WithFields(log.Fields{
+		"recipe": recipeName,
+		"dir":    dir,
+	}).Debug("running recipe steps in working directory")
+}
@@ -0,0 +1,64 @@
+//go:build unit
+// +build unit
+
+package execution
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestResolveWorkingDir(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "working-dir")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	dir, err := resolveWorkingDir(types.OpenInstallationRecipe{Name: "none"}, types.RecipeVars{}, true)
+	require.NoError(t, err)
+	require.Equal(t, "", dir)
+
+	os.Setenv("NR_TEST_WORKING_ROOT", tmp)
+	defer os.Unsetenv("NR_TEST_WORKING_ROOT")
+
+	r := types.OpenInstallationRecipe{Name: "cwd", WorkingDir: "${NR_TEST_WORKING_ROOT}/${APP}"}
+
+	dir, err = resolveWorkingDir(r, types.RecipeVars{"APP": "web"}, false)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(tmp, "web"), dir)
+	require.NoDirExists(t, dir)
+
+	dir, err = resolveWorkingDir(r, types.RecipeVars{"APP": "web"}, true)
+	require.NoError(t, err)
+	require.DirExists(t, dir)
+
+	// Recipe variables take precedence over the environment.
+	dir, err = resolveWorkingDir(r, types.RecipeVars{"APP": "web", "NR_TEST_WORKING_ROOT": filepath.Join(tmp, "vars")}, true)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(tmp, "vars", "web"), dir)
+}
+
+func TestResolveWorkingDir_Invalid(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "working-dir")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	file := filepath.Join(tmp, "file")
+	require.NoError(t, ioutil.WriteFile(file, nil, 0644))
+
+	_, err = resolveWorkingDir(types.OpenInstallationRecipe{Name: "cwd", WorkingDir: file}, types.RecipeVars{}, true)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not a directory")
+
+	_, err = resolveWorkingDir(types.OpenInstallationRecipe{Name: "cwd", WorkingDir: filepath.Join(file, "sub")}, types.RecipeVars{}, true)
+	require.Error(t, err)
+
+	_, err = resolveWorkingDir(types.OpenInstallationRecipe{Name: "cwd", WorkingDir: "${NR_TEST_UNSET_DIR}"}, types.RecipeVars{}, true)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "expands to nothing")
+}
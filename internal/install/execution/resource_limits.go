@@ -0,0 +1,133 @@
+package execution
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-task/task/v3"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// cpuPeriodMicros is the period over which the CPU time of a recipe is
+	// limited.
+	cpuPeriodMicros = 100000
+
+	// minCPUQuotaMicros is the smallest CPU time a recipe is allowed per
+	// period.
+	minCPUQuotaMicros = 1000
+)
+
+// ResourceLimits are the CPU and memory the processes run by a recipe's steps
+// are limited to.  A zero value leaves the resource unlimited.
+type ResourceLimits struct {
+	// MemoryMB is the memory, in megabytes, the processes of a recipe may use
+	// together.  Processes exceeding it are terminated.
+	MemoryMB int
+	// CPUs is the number of CPUs the processes of a recipe may use together,
+	// which may be fractional.
+	CPUs float64
+}
+
+// IsSet returns whether any resource is limited.
+func (l ResourceLimits) IsSet() bool {
+	return l.MemoryMB > 0 || l.CPUs > 0
+}
+
+// Validate returns an error when a limit is negative.
+func (l ResourceLimits) Validate() error {
+	if l.MemoryMB < 0 {
+		return errors.New("the recipe memory limit cannot be negative")
+	}
+
+	if l.CPUs < 0 {
+		return errors.New("the recipe CPU limit cannot be negative")
+	}
+
+	return nil
+}
+
+// cpuMax returns the CPU limit as a quota of CPU time per period, in
+// microseconds, in the format of the cgroup cpu.max file.
+func (l ResourceLimits) cpuMax() string {
+	if l.CPUs <= 0 {
+		return fmt.Sprintf("max %d", cpuPeriodMicros)
+	}
+
+	quota := int(l.CPUs * cpuPeriodMicros)
+	if quota < minCPUQuotaMicros {
+		quota = minCPUQuotaMicros
+	}
+
+	return fmt.Sprintf("%d %d", quota, cpuPeriodMicros)
+}
+
+// memoryMax returns the memory limit in bytes, in the format of the cgroup
+// memory.max file.
+func (l ResourceLimits) memoryMax() string {
+	if l.MemoryMB <= 0 {
+		return "max"
+	}
+
+	return fmt.Sprintf("%d", int64(l.MemoryMB)*1024*1024)
+}
+
+// ErrMemoryLimitExceeded is returned when a process run by a recipe's steps
+// is terminated for exceeding the recipe memory limit.
+type ErrMemoryLimitExceeded struct {
+	RecipeName string
+	MemoryMB   int
+}
+
+func NewErrMemoryLimitExceeded(recipeName string, memoryMB int) ErrMemoryLimitExceeded {
+	return ErrMemoryLimitExceeded{
+		RecipeName: recipeName,
+		MemoryMB:   memoryMB,
+	}
+}
+
+func (e ErrMemoryLimitExceeded) Error() string {
+	return fmt.Sprintf("recipe %s exceeded its memory limit of %dMB and was terminated", e.RecipeName, e.MemoryMB)
+}
+
+// resourceLimiter confines the processes run by the steps of a recipe to the
+// resource limits.
+type resourceLimiter interface {
+	// wrap returns a command that runs cmd confined to the limits.
+	wrap(cmd string) string
+	// memoryExceeded returns whether a process was terminated for exceeding
+	// the memory limit.
+	memoryExceeded() bool
+	// release removes the limits once the recipe's processes have exited.
+	release()
+}
+
+// applyResourceLimits arranges for the steps of the recipe to run confined to
+// the resource limits, returning the limiter confining them.  Limits are not
+// applied, with a warning, where the host does not support them.  Only the
+// processes the steps start are confined, not the installer.
+func (re *GoTaskRecipeExecutor) applyResourceLimits(e *task.Executor, recipeName string) resourceLimiter {
+	limiter, err := newResourceLimiter(recipeName, re.ResourceLimits)
+	if err != nil {
+		log.Warnf("Resource limits will not be applied to recipe %s: %s", recipeName, err)
+		return nil
+	}
+
+	log.WithFields(log.Fields{
+		"name":      recipeName,
+		"memory_mb": re.ResourceLimits.MemoryMB,
+		"cpus":      re.ResourceLimits.CPUs,
+	}).Debug("limiting recipe resources")
+
+	for _, t := range e.Taskfile.Tasks {
+		for _, c := range t.Cmds {
+			if c.Cmd == "" {
+				continue
+			}
+
+			c.Cmd = limiter.wrap(c.Cmd)
+		}
+	}
+
+	return limiter
+}
@@ -0,0 +1,351 @@
+//go:build linux
+// +build linux
+
+package execution
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+	"mvdan.cc/sh/v3/expand"
+	"mvdan.cc/sh/v3/interp"
+	"mvdan.cc/sh/v3/syntax"
+)
+
+const (
+	// cgroupParentName is the control group, under the root, in which a
+	// control group is created for each recipe.
+	cgroupParentName = "newrelic-cli"
+
+	cgroupFilePerm = 0644
+	cgroupDirPerm  = 0755
+
+	// cgroupExecArg is the argument the installer is run again with to run a
+	// step's command in the control group of its recipe.
+	cgroupExecArg = "__newrelic-cli-cgroup-exec"
+)
+
+var (
+	// cgroupRoot is where the unified control group hierarchy is mounted, and
+	// is replaced in tests.
+	cgroupRoot = "/sys/fs/cgroup"
+
+	cgroupUnsafeChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+)
+
+// cgroupLimiter is an implementation of the resourceLimiter interface that
+// runs the steps of a recipe in a control group of their own, whose CPU and
+// memory controllers enforce the limits.  Each step's command is started
+// through a copy of the installer that joins the group, then runs the command
+// with go-task's interpreter, so that every process the step starts is
+// confined while the installer itself stays in its own group.  The changes
+// made to the hierarchy to create the group are undone once the recipe is
+// done.
+type cgroupLimiter struct {
+	dir string
+	// executable is the installer, run again to start each command.
+	executable string
+	// parentCreated is set when the parent of the group did not exist.
+	parentCreated bool
+	// enabled are the controllers enabled in each group of the hierarchy,
+	// keyed by its directory, to be disabled again on release.
+	enabled map[string][]string
+}
+
+// newResourceLimiter creates the control group of the recipe, with the limits
+// configured.  An error is returned when the host does not have the unified
+// control group hierarchy, its CPU or memory controllers, or when the group
+// cannot be created, as when not running as root.
+func newResourceLimiter(recipeName string, limits ResourceLimits) (resourceLimiter, error) {
+	controllers, err := ioutil.ReadFile(filepath.Join(cgroupRoot, "cgroup.controllers"))
+	if err != nil {
+		return nil, fmt.Errorf("cgroup v2 is not available: %s", err)
+	}
+
+	needed := cgroupControllers(limits)
+	available := strings.Fields(string(controllers))
+	for _, c := range needed {
+		if !containsString(available, c) {
+			return nil, fmt.Errorf("the %s cgroup controller is not available", c)
+		}
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("could not locate the installer: %s", err)
+	}
+
+	parent := filepath.Join(cgroupRoot, cgroupParentName)
+	l := &cgroupLimiter{
+		dir:        filepath.Join(parent, fmt.Sprintf("%s-%d", cgroupUnsafeChars.ReplaceAllString(recipeName, "_"), os.Getpid())),
+		executable: executable,
+		enabled:    map[string][]string{},
+	}
+
+	if _, err := os.Stat(parent); os.IsNotExist(err) {
+		l.parentCreated = true
+	}
+
+	if err := os.MkdirAll(parent, cgroupDirPerm); err != nil {
+		return nil, fmt.Errorf("could not create cgroup: %s", err)
+	}
+
+	for _, dir := range []string{cgroupRoot, parent} {
+		enabled, err := enableCgroupControllers(dir, needed)
+		l.enabled[dir] = enabled
+		if err != nil {
+			l.release()
+			return nil, err
+		}
+	}
+
+	if err := os.Mkdir(l.dir, cgroupDirPerm); err != nil && !os.IsExist(err) {
+		l.release()
+		return nil, fmt.Errorf("could not create cgroup: %s", err)
+	}
+
+	if err := l.configure(limits); err != nil {
+		l.release()
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// configure writes the limits to the control group.  Swap is disabled for a
+// memory limited group, so that its processes cannot exceed the limit by
+// swapping, where the host supports it.
+func (l *cgroupLimiter) configure(limits ResourceLimits) error {
+	if limits.MemoryMB > 0 {
+		if err := l.write("memory.max", limits.memoryMax()); err != nil {
+			return err
+		}
+
+		if _, err := os.Stat(filepath.Join(l.dir, "memory.swap.max")); err == nil {
+			if err := l.write("memory.swap.max", "0"); err != nil {
+				log.Debugf("could not disable swap for cgroup %s: %s", l.dir, err)
+			}
+		}
+	}
+
+	if limits.CPUs > 0 {
+		if err := l.write("cpu.max", limits.cpuMax()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *cgroupLimiter) write(name string, value string) error {
+	if err := ioutil.WriteFile(filepath.Join(l.dir, name), []byte(value), cgroupFilePerm); err != nil {
+		return fmt.Errorf("could not set cgroup %s: %s", name, err)
+	}
+
+	return nil
+}
+
+// wrap returns a command that starts the installer again, which joins the
+// control group and then runs cmd with go-task's interpreter.
+func (l *cgroupLimiter) wrap(cmd string) string {
+	return strings.Join([]string{
+		shellQuote(l.executable),
+		cgroupExecArg,
+		shellQuote(l.dir),
+		shellQuote(cmd),
+	}, " ")
+}
+
+func (l *cgroupLimiter) memoryExceeded() bool {
+	data, err := ioutil.ReadFile(filepath.Join(l.dir, "memory.events"))
+	if err != nil {
+		return false
+	}
+
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) != 2 || fields[0] != "oom_kill" {
+			continue
+		}
+
+		n, err := strconv.Atoi(fields[1])
+		return err == nil && n > 0
+	}
+
+	return false
+}
+
+// release removes the group of the recipe and undoes the changes made to
+// create it.  Changes another install still relies on are left in place.
+func (l *cgroupLimiter) release() {
+	if err := os.Remove(l.dir); err != nil && !os.IsNotExist(err) {
+		log.Debugf("could not remove cgroup %s: %s", l.dir, err)
+	}
+
+	parent := filepath.Dir(l.dir)
+	disableCgroupControllers(parent, l.enabled[parent])
+
+	if l.parentCreated {
+		if err := os.Remove(parent); err != nil && !os.IsNotExist(err) {
+			log.Debugf("could not remove cgroup %s: %s", parent, err)
+		}
+	}
+
+	disableCgroupControllers(cgroupRoot, l.enabled[cgroupRoot])
+}
+
+func init() {
+	if len(os.Args) == 4 && os.Args[1] == cgroupExecArg {
+		os.Exit(runInCgroup(os.Args[2], os.Args[3]))
+	}
+}
+
+// runInCgroup joins the control group, then runs cmd as go-task does, with
+// the standard streams, environment and working directory it was started
+// with.  It returns the exit status of the command.
+func runInCgroup(dir string, cmd string) int {
+	if err := ioutil.WriteFile(filepath.Join(dir, "cgroup.procs"), []byte(strconv.Itoa(os.Getpid())), cgroupFilePerm); err != nil {
+		fmt.Fprintf(os.Stderr, "could not join cgroup %s: %s\n", dir, err)
+		return 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		cancel()
+	}()
+
+	err := runShellCommand(ctx, cmd)
+	if status, ok := interp.IsExitStatus(err); ok {
+		return int(status)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+// runShellCommand runs cmd with the interpreter and options go-task runs
+// commands with.
+func runShellCommand(ctx context.Context, cmd string) error {
+	p, err := syntax.NewParser().Parse(strings.NewReader(cmd), "")
+	if err != nil {
+		return err
+	}
+
+	r, err := interp.New(
+		interp.Params("-e"),
+		interp.Env(expand.ListEnviron(os.Environ()...)),
+		interp.OpenHandler(openDevNull),
+		interp.StdIO(os.Stdin, os.Stdout, os.Stderr),
+	)
+	if err != nil {
+		return err
+	}
+
+	return r.Run(ctx, p)
+}
+
+// openDevNull opens files as the interpreter does, except that /dev/null is
+// discarded without being opened, as go-task does.
+func openDevNull(ctx context.Context, path string, flag int, perm os.FileMode) (io.ReadWriteCloser, error) {
+	if path == "/dev/null" {
+		return devNull{}, nil
+	}
+
+	return interp.DefaultOpenHandler()(ctx, path, flag, perm)
+}
+
+type devNull struct{}
+
+func (devNull) Read(p []byte) (int, error)  { return 0, io.EOF }
+func (devNull) Write(p []byte) (int, error) { return len(p), nil }
+func (devNull) Close() error                { return nil }
+
+// cgroupControllers returns the controllers enforcing the limits.
+func cgroupControllers(limits ResourceLimits) []string {
+	controllers := []string{}
+	if limits.CPUs > 0 {
+		controllers = append(controllers, "cpu")
+	}
+	if limits.MemoryMB > 0 {
+		controllers = append(controllers, "memory")
+	}
+
+	return controllers
+}
+
+// enableCgroupControllers makes the controllers available to the children of
+// the control group, returning those that were not already.
+func enableCgroupControllers(dir string, controllers []string) ([]string, error) {
+	enabled, _ := ioutil.ReadFile(filepath.Join(dir, "cgroup.subtree_control"))
+
+	missing := []string{}
+	for _, c := range controllers {
+		if !containsString(strings.Fields(string(enabled)), c) {
+			missing = append(missing, c)
+		}
+	}
+
+	if len(missing) == 0 {
+		return missing, nil
+	}
+
+	if err := writeSubtreeControl(dir, "+", missing); err != nil {
+		return nil, fmt.Errorf("could not enable cgroup controllers %s: %s", strings.Join(controllers, ", "), err)
+	}
+
+	return missing, nil
+}
+
+// disableCgroupControllers makes the controllers unavailable to the children
+// of the control group again.  This fails while another group below it still
+// uses them, in which case they are left enabled.
+func disableCgroupControllers(dir string, controllers []string) {
+	if len(controllers) == 0 {
+		return
+	}
+
+	if err := writeSubtreeControl(dir, "-", controllers); err != nil {
+		log.Debugf("could not disable cgroup controllers %s of %s: %s", strings.Join(controllers, ", "), dir, err)
+	}
+}
+
+func writeSubtreeControl(dir string, op string, controllers []string) error {
+	changes := make([]string, len(controllers))
+	for i, c := range controllers {
+		changes[i] = op + c
+	}
+
+	return ioutil.WriteFile(filepath.Join(dir, "cgroup.subtree_control"), []byte(strings.Join(changes, " ")), cgroupFilePerm)
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,176 @@
+//go:build unit && linux
+// +build unit,linux
+
+package execution
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func fakeCgroupRoot(t *testing.T, controllers string) (string, func()) {
+	dir, err := ioutil.TempDir("", "cgroup")
+	require.NoError(t, err)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "cgroup.controllers"), []byte(controllers), 0644))
+
+	root := cgroupRoot
+	cgroupRoot = dir
+
+	return dir, func() {
+		cgroupRoot = root
+		os.RemoveAll(dir)
+	}
+}
+
+func TestNewResourceLimiter_ConfiguresLimits(t *testing.T) {
+	dir, cleanup := fakeCgroupRoot(t, "cpuset cpu io memory pids")
+	defer cleanup()
+
+	l, err := newResourceLimiter("mysql/open source", ResourceLimits{MemoryMB: 256, CPUs: 0.5})
+	require.NoError(t, err)
+
+	group := filepath.Join(dir, cgroupParentName, "mysql_open_source-"+strconv.Itoa(os.Getpid()))
+	require.Equal(t, group, l.(*cgroupLimiter).dir)
+
+	for file, want := range map[string]string{
+		filepath.Join(dir, "cgroup.subtree_control"):                   "+cpu +memory",
+		filepath.Join(dir, cgroupParentName, "cgroup.subtree_control"): "+cpu +memory",
+		filepath.Join(group, "memory.max"):                             "268435456",
+		filepath.Join(group, "cpu.max"):                                "50000 100000",
+	} {
+		data, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		require.Equal(t, want, string(data))
+	}
+}
+
+func TestCgroupLimiter_ReleaseUndoesChanges(t *testing.T) {
+	dir, cleanup := fakeCgroupRoot(t, "cpu memory")
+	defer cleanup()
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "cgroup.subtree_control"), []byte("cpu"), 0644))
+
+	l, err := newResourceLimiter("mysql", ResourceLimits{MemoryMB: 256, CPUs: 1})
+	require.NoError(t, err)
+
+	// The fake group is a plain directory, so it is emptied as the kernel
+	// would for release to remove it.
+	group := l.(*cgroupLimiter).dir
+	for _, f := range []string{"memory.max", "cpu.max"} {
+		os.Remove(filepath.Join(group, f))
+	}
+
+	l.release()
+
+	_, err = os.Stat(group)
+	require.True(t, os.IsNotExist(err))
+
+	// Only the controllers the limiter enabled are disabled again.
+	for file, want := range map[string]string{
+		filepath.Join(dir, "cgroup.subtree_control"):                   "-memory",
+		filepath.Join(dir, cgroupParentName, "cgroup.subtree_control"): "-cpu -memory",
+	} {
+		data, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		require.Equal(t, want, string(data))
+	}
+}
+
+func TestNewResourceLimiter_OnlyMemory(t *testing.T) {
+	dir, cleanup := fakeCgroupRoot(t, "memory")
+	defer cleanup()
+
+	l, err := newResourceLimiter("mysql", ResourceLimits{MemoryMB: 256})
+	require.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(l.(*cgroupLimiter).dir, "cpu.max"))
+	require.True(t, os.IsNotExist(err))
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "cgroup.subtree_control"))
+	require.NoError(t, err)
+	require.Equal(t, "+memory", string(data))
+}
+
+func TestNewResourceLimiter_Unsupported(t *testing.T) {
+	_, cleanup := fakeCgroupRoot(t, "cpu io")
+	defer cleanup()
+
+	_, err := newResourceLimiter("mysql", ResourceLimits{MemoryMB: 256})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "memory")
+
+	cgroupRoot = filepath.Join(cgroupRoot, "missing")
+	_, err = newResourceLimiter("mysql", ResourceLimits{CPUs: 1})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cgroup v2 is not available")
+}
+
+func TestCgroupLimiter_MemoryExceeded(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cgroup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	l := &cgroupLimiter{dir: dir}
+	require.False(t, l.memoryExceeded())
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "memory.events"), []byte("low 0\nhigh 0\nmax 3\noom 1\noom_kill 0\n"), 0644))
+	require.False(t, l.memoryExceeded())
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "memory.events"), []byte("low 0\nhigh 0\nmax 3\noom 1\noom_kill 1\n"), 0644))
+	require.True(t, l.memoryExceeded())
+}
+
+func TestExecute_ResourceLimitsConfineStepsOnly(t *testing.T) {
+	dir, cleanup := fakeCgroupRoot(t, "cpu memory")
+	defer cleanup()
+
+	out, err := ioutil.TempDir("", "out")
+	require.NoError(t, err)
+	defer os.RemoveAll(out)
+
+	re := NewGoTaskRecipeExecutor()
+	re.ResourceLimits = ResourceLimits{MemoryMB: 256}
+	r := types.OpenInstallationRecipe{
+		Name: "limited",
+		Install: `
+version: '3'
+tasks:
+  default:
+    cmds:
+      - "[[ limited == limit* ]] && echo bash > ` + filepath.Join(out, "interpreter") + `"
+`,
+	}
+
+	err = re.Execute(context.Background(), types.DiscoveryManifest{}, r, types.RecipeVars{})
+	require.NoError(t, err)
+
+	// The step ran in go-task's interpreter rather than sh.
+	interpreter, err := ioutil.ReadFile(filepath.Join(out, "interpreter"))
+	require.NoError(t, err)
+	require.Equal(t, "bash", strings.TrimSpace(string(interpreter)))
+
+	// The fake group is not removed, as it still holds the files written to
+	// it, so the PID that joined it can be read back.  The step joined it,
+	// rather than the installer.
+	procs, err := ioutil.ReadFile(filepath.Join(dir, cgroupParentName, "limited-"+strconv.Itoa(os.Getpid()), "cgroup.procs"))
+	require.NoError(t, err)
+	pid, err := strconv.Atoi(strings.TrimSpace(string(procs)))
+	require.NoError(t, err)
+	require.NotEqual(t, os.Getpid(), pid)
+}
+
+func TestCgroupLimiter_WrapRunsInstallerAgain(t *testing.T) {
+	l := &cgroupLimiter{dir: "/sys/fs/cgroup/newrelic-cli/mysql-1", executable: "/usr/bin/newrelic"}
+
+	require.Equal(t, `'/usr/bin/newrelic' `+cgroupExecArg+` '/sys/fs/cgroup/newrelic-cli/mysql-1' 'echo '"'"'hi'"'"''`, l.wrap("echo 'hi'"))
+}
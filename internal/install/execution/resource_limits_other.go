@@ -0,0 +1,14 @@
+//go:build !linux
+// +build !linux
+
+package execution
+
+import (
+	"errors"
+)
+
+// newResourceLimiter returns an error, as resource limits rely on Linux
+// control groups.
+func newResourceLimiter(recipeName string, limits ResourceLimits) (resourceLimiter, error) {
+	return nil, errors.New("resource limits are only supported on Linux")
+}
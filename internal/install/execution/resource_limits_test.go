@@ -0,0 +1,38 @@
+//go:build unit
+// +build unit
+
+package execution
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceLimits_IsSet(t *testing.T) {
+	require.False(t, ResourceLimits{}.IsSet())
+	require.True(t, ResourceLimits{MemoryMB: 512}.IsSet())
+	require.True(t, ResourceLimits{CPUs: 0.5}.IsSet())
+}
+
+func TestResourceLimits_Validate(t *testing.T) {
+	require.NoError(t, ResourceLimits{}.Validate())
+	require.NoError(t, ResourceLimits{MemoryMB: 512, CPUs: 1.5}.Validate())
+	require.Error(t, ResourceLimits{MemoryMB: -1}.Validate())
+	require.Error(t, ResourceLimits{CPUs: -1}.Validate())
+}
+
+func TestResourceLimits_CgroupValues(t *testing.T) {
+	require.Equal(t, "max 100000", ResourceLimits{}.cpuMax())
+	require.Equal(t, "50000 100000", ResourceLimits{CPUs: 0.5}.cpuMax())
+	require.Equal(t, "200000 100000", ResourceLimits{CPUs: 2}.cpuMax())
+	require.Equal(t, "1000 100000", ResourceLimits{CPUs: 0.001}.cpuMax())
+
+	require.Equal(t, "max", ResourceLimits{}.memoryMax())
+	require.Equal(t, "536870912", ResourceLimits{MemoryMB: 512}.memoryMax())
+}
+
+func TestErrMemoryLimitExceeded(t *testing.T) {
+	err := NewErrMemoryLimitExceeded("mysql", 512)
+	require.Equal(t, "recipe mysql exceeded its memory limit of 512MB and was terminated", err.Error())
+}
@@ -0,0 +1,133 @@
+package execution
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+const defaultRetryBackoff = 5 * time.Second
+
+// exitCodePattern matches the exit code in the errors of failed local and
+// remote recipe installs.
+var exitCodePattern = regexp.MustCompile(`(?:exit status|exited with status) (\d+)`)
+
+// RetryingRecipeExecutor is an implementation of the RecipeExecutor interface
+// that retries a recipe whose install fails with a retryable exit code, such
+// as one caused by a transient network error.  Other failures are returned
+// immediately.
+type RetryingRecipeExecutor struct {
+	executor RecipeExecutor
+	// ExitCodes are the exit codes retried on, unless the recipe sets its own.
+	ExitCodes []int
+	// Retries is the number of times a recipe is retried, unless the recipe
+	// sets its own.
+	Retries int
+	// Backoff is waited before the first retry, and doubles with each retry.
+	Backoff time.Duration
+	// Retrying, when set, is called before each retry.
+	Retrying func(RecipeRetryEvent)
+}
+
+// NewRetryingRecipeExecutor returns a new instance of RetryingRecipeExecutor
+// retrying the installs of the given executor.
+func NewRetryingRecipeExecutor(executor RecipeExecutor) *RetryingRecipeExecutor {
+	re := RetryingRecipeExecutor{
+		executor: executor,
+		Backoff:  defaultRetryBackoff,
+	}
+
+	return &re
+}
+
+func (re *RetryingRecipeExecutor) Prepare(ctx context.Context, m types.DiscoveryManifest, r types.OpenInstallationRecipe, assumeYes bool, licenseKey string) (types.RecipeVars, error) {
+	return re.executor.Prepare(ctx, m, r, assumeYes, licenseKey)
+}
+
+func (re *RetryingRecipeExecutor) Execute(ctx context.Context, m types.DiscoveryManifest, r types.OpenInstallationRecipe, v types.RecipeVars) error {
+	exitCodes, retries := re.retryPolicy(r)
+
+	for attempt := 1; ; attempt++ {
+		err := re.executor.Execute(ctx, m, r, v)
+		if err == nil || err == types.ErrInterrupt || attempt > retries {
+			return err
+		}
+
+		code, ok := exitCode(err)
+		if !ok || !containsExitCode(exitCodes, code) {
+			return err
+		}
+
+		backoff := re.Backoff * time.Duration(1<<uint(attempt-1))
+
+		log.WithFields(log.Fields{
+			"name":      r.Name,
+			"exit_code": code,
+			"attempt":   attempt + 1,
+			"backoff":   backoff,
+		}).Debug("retrying recipe")
+
+		if re.Retrying != nil {
+			re.Retrying(RecipeRetryEvent{
+				Recipe:      r,
+				Attempt:     attempt + 1,
+				MaxAttempts: retries + 1,
+				ExitCode:    code,
+				Msg:         err.Error(),
+			})
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// retryPolicy returns the exit codes retried on and the number of retries
+// for the recipe, preferring those set by the recipe.
+func (re *RetryingRecipeExecutor) retryPolicy(r types.OpenInstallationRecipe) ([]int, int) {
+	exitCodes := re.ExitCodes
+	if len(r.RetryExitCodes) > 0 {
+		exitCodes = r.RetryExitCodes
+	}
+
+	retries := re.Retries
+	if r.Retries > 0 {
+		retries = r.Retries
+	}
+
+	return exitCodes, retries
+}
+
+// exitCode returns the exit code of a failed recipe install, if the error
+// carries one.
+func exitCode(err error) (int, bool) {
+	match := exitCodePattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return 0, false
+	}
+
+	code, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, false
+	}
+
+	return code, true
+}
+
+func containsExitCode(exitCodes []int, code int) bool {
+	for _, c := range exitCodes {
+		if c == code {
+			return true
+		}
+	}
+
+	return false
+}
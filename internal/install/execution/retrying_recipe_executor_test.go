@@ -0,0 +1,127 @@
+// +build unit
+
+package execution
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+var errExit2 = errors.New(`task: Failed to run task "default": exit status 2`)
+
+func newTestRetryingExecutor(e RecipeExecutor) (*RetryingRecipeExecutor, *[]RecipeRetryEvent) {
+	events := []RecipeRetryEvent{}
+
+	re := NewRetryingRecipeExecutor(e)
+	re.Backoff = 0
+	re.Retrying = func(event RecipeRetryEvent) {
+		events = append(events, event)
+	}
+
+	return re, &events
+}
+
+func TestRetryingRecipeExecutor_RetriesThenSucceeds(t *testing.T) {
+	e := NewMockRecipeExecutor()
+	e.ExecuteErrs = []error{errExit2, errExit2}
+
+	re, events := newTestRetryingExecutor(e)
+	re.ExitCodes = []int{2}
+	re.Retries = 3
+
+	err := re.Execute(context.Background(), types.DiscoveryManifest{}, types.OpenInstallationRecipe{Name: "test"}, types.RecipeVars{})
+	require.NoError(t, err)
+	require.Equal(t, 3, e.ExecuteCallCount)
+	require.Len(t, *events, 2)
+	require.Equal(t, 2, (*events)[0].Attempt)
+	require.Equal(t, 3, (*events)[1].Attempt)
+	require.Equal(t, 4, (*events)[1].MaxAttempts)
+	require.Equal(t, 2, (*events)[1].ExitCode)
+}
+
+func TestRetryingRecipeExecutor_GivesUp(t *testing.T) {
+	e := NewMockRecipeExecutor()
+	e.ExecuteErrs = []error{errExit2, errExit2, errExit2}
+
+	re, events := newTestRetryingExecutor(e)
+	re.ExitCodes = []int{2}
+	re.Retries = 1
+
+	err := re.Execute(context.Background(), types.DiscoveryManifest{}, types.OpenInstallationRecipe{Name: "test"}, types.RecipeVars{})
+	require.Equal(t, errExit2, err)
+	require.Equal(t, 2, e.ExecuteCallCount)
+	require.Len(t, *events, 1)
+}
+
+func TestRetryingRecipeExecutor_NonRetryableExitCode(t *testing.T) {
+	e := NewMockRecipeExecutor()
+	e.ExecuteErrs = []error{errors.New("exit status 1")}
+
+	re, events := newTestRetryingExecutor(e)
+	re.ExitCodes = []int{2}
+	re.Retries = 3
+
+	err := re.Execute(context.Background(), types.DiscoveryManifest{}, types.OpenInstallationRecipe{Name: "test"}, types.RecipeVars{})
+	require.Error(t, err)
+	require.Equal(t, 1, e.ExecuteCallCount)
+	require.Empty(t, *events)
+}
+
+func TestRetryingRecipeExecutor_Interrupt(t *testing.T) {
+	e := NewMockRecipeExecutor()
+	e.ExecuteErrs = []error{types.ErrInterrupt}
+
+	re, _ := newTestRetryingExecutor(e)
+	re.ExitCodes = []int{2}
+	re.Retries = 3
+
+	err := re.Execute(context.Background(), types.DiscoveryManifest{}, types.OpenInstallationRecipe{Name: "test"}, types.RecipeVars{})
+	require.Equal(t, types.ErrInterrupt, err)
+	require.Equal(t, 1, e.ExecuteCallCount)
+}
+
+func TestRetryingRecipeExecutor_RecipePolicy(t *testing.T) {
+	e := NewMockRecipeExecutor()
+	e.ExecuteErrs = []error{errors.New("Process exited with status 100")}
+
+	re, events := newTestRetryingExecutor(e)
+	re.ExitCodes = []int{2}
+
+	r := types.OpenInstallationRecipe{
+		Name:           "test",
+		RetryExitCodes: []int{100},
+		Retries:        1,
+	}
+
+	err := re.Execute(context.Background(), types.DiscoveryManifest{}, r, types.RecipeVars{})
+	require.NoError(t, err)
+	require.Equal(t, 2, e.ExecuteCallCount)
+	require.Len(t, *events, 1)
+	require.Equal(t, 100, (*events)[0].ExitCode)
+}
+
+func TestExitCode(t *testing.T) {
+	code, ok := exitCode(errExit2)
+	require.True(t, ok)
+	require.Equal(t, 2, code)
+
+	_, ok = exitCode(errors.New("could not unmarshal taskfile"))
+	require.False(t, ok)
+}
+
+func TestInstallStatus_RecipeRetrying(t *testing.T) {
+	reporter := NewMockStatusReporter()
+	s := NewInstallStatus([]StatusSubscriber{reporter}, NewConcreteSuccessLinkGenerator())
+	r := types.OpenInstallationRecipe{Name: "test"}
+
+	s.RecipeInstalling(RecipeStatusEvent{Recipe: r})
+	s.RecipeRetrying(RecipeRetryEvent{Recipe: r, Attempt: 2, MaxAttempts: 3, ExitCode: 2})
+
+	require.Equal(t, 1, reporter.RecipeRetryingCallCount)
+	require.Equal(t, 2, s.Statuses[0].Attempts)
+}
@@ -0,0 +1,173 @@
+package execution
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+
+	"github.com/newrelic/newrelic-cli/internal/install/remote"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+const defaultRemoteTaskCommand = "task"
+
+// SSHRecipeExecutor is an implementation of the recipeExecutor interface that
+// runs the steps defined in each recipe on a remote host over SSH.  The remote
+// host must have go-task installed.
+type SSHRecipeExecutor struct {
+	// TaskCommand is the go-task command run on the remote host.  Defaults
+	// to "task".
+	TaskCommand string
+
+	runner remote.Runner
+	local  RecipeExecutor
+}
+
+// NewSSHRecipeExecutor returns a new instance of SSHRecipeExecutor.  Recipe
+// variables are prepared locally by the given executor.
+func NewSSHRecipeExecutor(r remote.Runner, local RecipeExecutor) *SSHRecipeExecutor {
+	e := SSHRecipeExecutor{
+		TaskCommand: defaultRemoteTaskCommand,
+		runner:      r,
+		local:       local,
+	}
+
+	return &e
+}
+
+// Prepare prepares the recipe variables locally.  The environment variables
+// the recipe requires are checked on the remote host, where its steps run.
+func (re *SSHRecipeExecutor) Prepare(ctx context.Context, m types.DiscoveryManifest, r types.OpenInstallationRecipe, assumeYes bool, licenseKey string) (types.RecipeVars, error) {
+	local := r
+	local.RequiredEnvVars = nil
+
+	vars, err := re.local.Prepare(ctx, m, local, assumeYes, licenseKey)
+	if err != nil || len(r.RequiredEnvVars) == 0 {
+		return vars, err
+	}
+
+	lookup, err := remoteEnvLookup(ctx, re.runner, r.RequiredEnvVars)
+	if err != nil {
+		return types.RecipeVars{}, fmt.Errorf("could not check the environment of %s: %s", re.runner.Host(), err)
+	}
+
+	if err := checkRequiredEnvVars(r, vars, lookup); err != nil {
+		return types.RecipeVars{}, err
+	}
+
+	return vars, nil
+}
+
+// Execute uploads the recipe's task file, with the recipe variables merged in,
+// to a private temporary file on the remote host and runs it there.  Passing
+// variables in the file keeps secrets out of the remote process list.  Steps
+// whose condition is false for the remote host, as discovered, are emptied
+// out of the uploaded task file.
+func (re *SSHRecipeExecutor) Execute(ctx context.Context, m types.DiscoveryManifest, r types.OpenInstallationRecipe, recipeVars types.RecipeVars) error {
+	log.Debugf("executing recipe %s on %s", r.Name, re.runner.Host())
+
+	unmet, err := unmetStepConditions(m, r, recipeVars)
+	if err != nil {
+		return err
+	}
+
+	skipped := map[string]bool{}
+	for _, c := range unmet {
+		log.WithFields(log.Fields{
+			"name":   r.Name,
+			"step":   c.Step,
+			"host":   re.runner.Host(),
+			"reason": conditionNotMetMsg(c),
+		}).Debug("skipping recipe step")
+		skipped[c.Step] = true
+	}
+
+	taskfile, err := taskfileWithVars(r.Install, recipeVars, skipped)
+	if err != nil {
+		return fmt.Errorf("could not prepare taskfile: %s", err)
+	}
+
+	// The trap removes the task file however the command ends, including
+	// when it is interrupted.
+	cmd := fmt.Sprintf(`f=$(mktemp) && trap 'rm -f "$f"' EXIT INT TERM && cat > "$f" && %s --taskfile "$f"`, re.TaskCommand)
+
+	tail := newOutputTail(defaultOutputTailLines)
+	stdout := io.MultiWriter(os.Stdout, tail)
+	stderr := io.MultiWriter(os.Stderr, tail)
+
+	if err := re.runner.Run(ctx, cmd, bytes.NewReader(taskfile), stdout, stderr); err != nil {
+		log.WithFields(log.Fields{
+			"err":         err,
+			"host":        re.runner.Host(),
+			"output_tail": strings.Join(tail.Lines(), "\n"),
+		}).Debug("Remote task execution returned error")
+
+		if err == context.Canceled || strings.Contains(err.Error(), "status 130") {
+			return types.ErrInterrupt
+		}
+
+		return fmt.Errorf("%s: %s", re.runner.Host(), err)
+	}
+
+	return nil
+}
+
+// taskfileWithVars returns the given taskfile with the given variables set as
+// global vars, overriding any the taskfile declares, and with the skipped tasks
+// left without commands or dependencies, so that tasks calling them still run.
+func taskfileWithVars(install string, recipeVars types.RecipeVars, skipped map[string]bool) ([]byte, error) {
+	var tf yaml.MapSlice
+	if err := yaml.Unmarshal([]byte(install), &tf); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(recipeVars))
+	for k := range recipeVars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	vars := yaml.MapSlice{}
+	varsIndex := -1
+	for i, item := range tf {
+		if item.Key == "tasks" {
+			if tasks, ok := item.Value.(yaml.MapSlice); ok {
+				for j, t := range tasks {
+					if skipped[fmt.Sprint(t.Key)] {
+						tasks[j].Value = yaml.MapSlice{{Key: "cmds", Value: []string{}}}
+					}
+				}
+			}
+		}
+
+		if item.Key == "vars" {
+			varsIndex = i
+			if existing, ok := item.Value.(yaml.MapSlice); ok {
+				for _, v := range existing {
+					if _, overridden := recipeVars[fmt.Sprint(v.Key)]; !overridden {
+						vars = append(vars, v)
+					}
+				}
+			}
+		}
+	}
+
+	for _, k := range keys {
+		vars = append(vars, yaml.MapItem{Key: k, Value: recipeVars[k]})
+	}
+
+	if varsIndex >= 0 {
+		tf[varsIndex].Value = vars
+	} else {
+		tf = append(tf, yaml.MapItem{Key: "vars", Value: vars})
+	}
+
+	return yaml.Marshal(tf)
+}
@@ -0,0 +1,125 @@
+//go:build unit
+// +build unit
+
+package execution
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/newrelic/newrelic-cli/internal/install/remote"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+const testTaskfile = `
+version: '3'
+vars:
+  GREETING: hello
+  NEW_RELIC_LICENSE_KEY: placeholder
+tasks:
+  default:
+    cmds:
+      - echo {{.GREETING}}
+`
+
+func TestTaskfileWithVars(t *testing.T) {
+	out, err := taskfileWithVars(testTaskfile, types.RecipeVars{
+		"NEW_RELIC_LICENSE_KEY": "secret",
+		"HOSTNAME":              "db-1",
+	}, nil)
+	require.NoError(t, err)
+
+	var tf struct {
+		Version string            `yaml:"version"`
+		Vars    map[string]string `yaml:"vars"`
+		Tasks   map[string]interface{}
+	}
+	require.NoError(t, yaml.Unmarshal(out, &tf))
+	require.Equal(t, "3", tf.Version)
+	require.Equal(t, "hello", tf.Vars["GREETING"])
+	require.Equal(t, "secret", tf.Vars["NEW_RELIC_LICENSE_KEY"])
+	require.Equal(t, "db-1", tf.Vars["HOSTNAME"])
+	require.Contains(t, tf.Tasks, "default")
+}
+
+func TestTaskfileWithVars_NoVars(t *testing.T) {
+	out, err := taskfileWithVars("version: '3'\ntasks: {}\n", types.RecipeVars{"HOSTNAME": "db-1"}, nil)
+	require.NoError(t, err)
+
+	var tf struct {
+		Vars map[string]string `yaml:"vars"`
+	}
+	require.NoError(t, yaml.Unmarshal(out, &tf))
+	require.Equal(t, "db-1", tf.Vars["HOSTNAME"])
+}
+
+func TestSSHRecipeExecutor_Execute(t *testing.T) {
+	r := remote.NewMockRunner("db-1")
+	e := NewSSHRecipeExecutor(r, NewMockRecipeExecutor())
+
+	recipe := types.OpenInstallationRecipe{Name: "test", Install: testTaskfile}
+	err := e.Execute(context.Background(), types.DiscoveryManifest{}, recipe, types.RecipeVars{"NEW_RELIC_LICENSE_KEY": "secret"})
+	require.NoError(t, err)
+
+	require.Equal(t, 1, len(r.Commands))
+	require.Contains(t, r.Commands[0], `task --taskfile "$f"`)
+	require.NotContains(t, r.Commands[0], "secret")
+	require.Contains(t, r.Stdins[0], "secret")
+}
+
+func TestSSHRecipeExecutor_ExecuteSkipsStepsWhoseConditionIsFalse(t *testing.T) {
+	r := remote.NewMockRunner("db-1")
+	e := NewSSHRecipeExecutor(r, NewMockRecipeExecutor())
+
+	recipe := types.OpenInstallationRecipe{
+		Name: "test",
+		Install: `
+version: '3'
+tasks:
+  default:
+    cmds:
+      - task: configure
+      - task: install_systemd
+  configure:
+    cmds:
+      - echo configuring
+  install_systemd:
+    cmds:
+      - systemctl enable newrelic-infra
+`,
+		StepConditions: []types.OpenInstallationStepCondition{
+			{Step: "install_systemd", When: "os == 'linux'"},
+			{Step: "configure", When: "os == 'windows'"},
+		},
+	}
+	err := e.Execute(context.Background(), types.DiscoveryManifest{OS: "windows"}, recipe, types.RecipeVars{})
+	require.NoError(t, err)
+
+	require.Equal(t, 1, len(r.Stdins))
+	require.Contains(t, r.Stdins[0], "echo configuring")
+	require.Contains(t, r.Stdins[0], "task: install_systemd")
+	require.NotContains(t, r.Stdins[0], "systemctl")
+}
+
+func TestSSHRecipeExecutor_ExecuteError(t *testing.T) {
+	r := remote.NewMockRunner("db-1")
+	r.Responses["f=$(mktemp)"] = remote.MockResponse{Err: errors.New("Process exited with status 1")}
+	e := NewSSHRecipeExecutor(r, NewMockRecipeExecutor())
+
+	err := e.Execute(context.Background(), types.DiscoveryManifest{}, types.OpenInstallationRecipe{Install: testTaskfile}, types.RecipeVars{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "db-1")
+}
+
+func TestSSHRecipeExecutor_ExecuteInterrupted(t *testing.T) {
+	r := remote.NewMockRunner("db-1")
+	r.Responses["f=$(mktemp)"] = remote.MockResponse{Err: errors.New("Process exited with status 130")}
+	e := NewSSHRecipeExecutor(r, NewMockRecipeExecutor())
+
+	err := e.Execute(context.Background(), types.DiscoveryManifest{}, types.OpenInstallationRecipe{Install: testTaskfile}, types.RecipeVars{})
+	require.Equal(t, types.ErrInterrupt, err)
+}
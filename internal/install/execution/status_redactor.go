@@ -0,0 +1,83 @@
+package execution
+
+import (
+	"sync"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// statusRedactor masks sensitive values in status events before they are
+// recorded or passed to status subscribers.  The values of variables with
+// sensitive names, and of those the recipe marks as secret, are learned from
+// the variables of each event and masked wherever they appear afterwards.
+type statusRedactor struct {
+	masker *secretMasker
+	mu     sync.Mutex
+}
+
+func newStatusRedactor() *statusRedactor {
+	return &statusRedactor{
+		masker: &secretMasker{},
+	}
+}
+
+// AddSecret masks the given value from now on.
+func (r *statusRedactor) AddSecret(secret string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.masker.add(secret)
+}
+
+// Mask replaces every known secret in s.
+func (r *statusRedactor) Mask(s string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.masker.Mask(s)
+}
+
+// RedactEvent learns the secrets among the event's variables and returns the
+// event with its message and variables masked.
+func (r *statusRedactor) RedactEvent(e RecipeStatusEvent) RecipeStatusEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for k, v := range e.RecipeVars {
+		if isSecretVar(e.Recipe, k) {
+			r.masker.add(v)
+		}
+	}
+
+	e.Msg = r.masker.Mask(e.Msg)
+
+	if e.RecipeVars != nil {
+		vars := types.RecipeVars{}
+		for k, v := range e.RecipeVars {
+			if isSecretVar(e.Recipe, k) {
+				vars[k] = maskedValue
+			} else {
+				vars[k] = r.masker.Mask(v)
+			}
+		}
+		e.RecipeVars = vars
+	}
+
+	return e
+}
+
+// isSecretVar returns whether the named variable of the recipe holds a
+// sensitive value.
+func isSecretVar(r types.OpenInstallationRecipe, name string) bool {
+	if isSensitiveVar(name) {
+		return true
+	}
+
+	for _, v := range r.InputVars {
+		if v.Name == name && v.Secret {
+			return true
+		}
+	}
+
+	return false
+}
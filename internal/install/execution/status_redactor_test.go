@@ -0,0 +1,132 @@
+//go:build unit
+// +build unit
+
+package execution
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+const (
+	testLicenseKey = "0123456789abcdef0123456789abcdefNRAL"
+	testPassword   = "hunter2-db-password"
+)
+
+var redactedRecipe = types.OpenInstallationRecipe{
+	Name: "mysql",
+	InputVars: []types.OpenInstallationRecipeInputVariable{
+		{Name: "NR_CLI_DB_PASS", Secret: true},
+		{Name: "NR_CLI_DB_USER"},
+	},
+	PostInstall: types.OpenInstallationPostInstallConfiguration{
+		Info: "Reporting with {{.Vars.NEW_RELIC_LICENSE_KEY}} as {{.Vars.NR_CLI_DB_USER}}",
+	},
+}
+
+var redactedVars = types.RecipeVars{
+	"NEW_RELIC_LICENSE_KEY": testLicenseKey,
+	"NR_CLI_DB_PASS":        testPassword,
+	"NR_CLI_DB_USER":        "monitor",
+}
+
+func requireRedacted(t *testing.T, s string) {
+	require.NotContains(t, s, testLicenseKey)
+	require.NotContains(t, s, testPassword)
+}
+
+func TestStatusRedactor_RedactEvent(t *testing.T) {
+	r := newStatusRedactor()
+
+	e := r.RedactEvent(RecipeStatusEvent{
+		Recipe:     redactedRecipe,
+		Msg:        "failed to connect with " + testPassword,
+		RecipeVars: redactedVars,
+	})
+
+	require.Equal(t, "failed to connect with "+maskedValue, e.Msg)
+	require.Equal(t, maskedValue, e.RecipeVars["NEW_RELIC_LICENSE_KEY"])
+	require.Equal(t, maskedValue, e.RecipeVars["NR_CLI_DB_PASS"])
+	require.Equal(t, "monitor", e.RecipeVars["NR_CLI_DB_USER"])
+
+	// The original variables are left untouched for the executor.
+	require.Equal(t, testPassword, redactedVars["NR_CLI_DB_PASS"])
+
+	// Secrets learned from earlier events are masked in later ones.
+	e = r.RedactEvent(RecipeStatusEvent{Recipe: redactedRecipe, Msg: "key " + testLicenseKey + " rejected"})
+	require.Equal(t, "key "+maskedValue+" rejected", e.Msg)
+}
+
+func TestInstallStatus_AddSecret(t *testing.T) {
+	status := NewInstallStatus([]StatusSubscriber{}, NewMockSuccessLinkGenerator())
+	status.AddSecret(testLicenseKey)
+
+	status.InstallComplete(errors.New("invalid license key " + testLicenseKey))
+	require.Equal(t, "invalid license key "+maskedValue, status.Error.Message)
+}
+
+func TestRedaction_TerminalStatusReporter(t *testing.T) {
+	stdout := os.Stdout
+	rd, w, err := os.Pipe()
+	require.NoError(t, err)
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	status := NewInstallStatus([]StatusSubscriber{NewTerminalStatusReporter()}, NewMockSuccessLinkGenerator())
+	status.RecipeInstalled(RecipeStatusEvent{Recipe: redactedRecipe, RecipeVars: redactedVars})
+	status.RecipeSkipped(RecipeStatusEvent{Recipe: redactedRecipe, Msg: "password " + testPassword + " rejected"})
+
+	w.Close()
+	os.Stdout = stdout
+
+	out, err := ioutil.ReadAll(rd)
+	require.NoError(t, err)
+	require.Contains(t, string(out), "Reporting with "+maskedValue+" as monitor")
+	requireRedacted(t, string(out))
+}
+
+func TestRedaction_ArtifactStatusReporter(t *testing.T) {
+	base, err := ioutil.TempDir("", "artifacts")
+	require.NoError(t, err)
+	defer os.RemoveAll(base)
+
+	r := NewArtifactStatusReporter(base)
+	status := NewInstallStatus([]StatusSubscriber{r}, NewMockSuccessLinkGenerator())
+
+	status.RecipeInstalling(RecipeStatusEvent{Recipe: redactedRecipe, RecipeVars: redactedVars})
+	status.RecipeFailed(RecipeStatusEvent{Recipe: redactedRecipe, Msg: "exit status 1: mysql -p" + testPassword})
+	status.InstallComplete(errors.New("license key " + testLicenseKey + " is invalid"))
+
+	for _, name := range []string{"events.jsonl", "summary.json"} {
+		data, err := ioutil.ReadFile(filepath.Join(base, name))
+		require.NoError(t, err)
+		require.Contains(t, string(data), maskedValue)
+		requireRedacted(t, string(data))
+	}
+}
+
+func TestRedaction_NerdStorageStatusReporter(t *testing.T) {
+	c := NewMockNerdStorageClient()
+	r := NewNerdStorageStatusReporter(c)
+	status := NewInstallStatus([]StatusSubscriber{r}, NewMockSuccessLinkGenerator())
+
+	status.RecipeInstalling(RecipeStatusEvent{Recipe: redactedRecipe, RecipeVars: redactedVars})
+	status.RecipeFailed(RecipeStatusEvent{Recipe: redactedRecipe, Msg: "could not authenticate with " + testPassword})
+	status.InstallComplete(errors.New("license key " + testLicenseKey + " is invalid"))
+
+	require.NotEmpty(t, c.WrittenUserScopeDocuments)
+	for _, doc := range c.WrittenUserScopeDocuments {
+		data, err := json.Marshal(doc)
+		require.NoError(t, err)
+		require.Contains(t, string(data), maskedValue)
+		requireRedacted(t, string(data))
+	}
+}
@@ -0,0 +1,164 @@
+package execution
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// maxReplayLineSize bounds a line of the event stream, which holds a whole
+// discovery manifest.
+const maxReplayLineSize = 16 * 1024 * 1024
+
+// ReplayStatusEvents reads an event stream written by JSONStatusReporter and
+// replays each of its events through the given install status, so that its
+// status subscribers report the install as they would have while it ran.  The
+// install status takes the correlation and campaign IDs of the events.  Events
+// of an unknown kind are skipped.
+func ReplayStatusEvents(in io.Reader, status *InstallStatus) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxReplayLineSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var e jsonStatusEvent
+		if err := json.Unmarshal([]byte(text), &e); err != nil {
+			return fmt.Errorf("could not parse event on line %d: %s", line, err)
+		}
+
+		if err := replayStatusEvent(status, e); err != nil {
+			return fmt.Errorf("could not replay event on line %d: %s", line, err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+func replayStatusEvent(status *InstallStatus, e jsonStatusEvent) error {
+	if e.CorrelationID != "" {
+		status.CorrelationID = e.CorrelationID
+	}
+	if e.CampaignID != "" {
+		status.CampaignID = e.CampaignID
+	}
+
+	switch e.Event {
+	case jsonEventDiscoveryComplete:
+		if e.Manifest == nil {
+			return fmt.Errorf("%s event has no manifest", e.Event)
+		}
+		status.DiscoveryComplete(*e.Manifest)
+
+	case jsonEventRecipeAvailable:
+		if e.Recipe == nil {
+			return fmt.Errorf("%s event has no recipe", e.Event)
+		}
+		status.RecipeAvailable(e.Recipe.recipe())
+
+	case jsonEventRecipesAvailable:
+		status.RecipesAvailable(replayedRecipes(e.Recipes))
+
+	case jsonEventRecipesSelected:
+		status.RecipesSelected(replayedRecipes(e.Recipes))
+
+	case string(RecipeStatusTypes.INSTALLING), string(RecipeStatusTypes.INSTALLED), string(RecipeStatusTypes.FAILED),
+		string(RecipeStatusTypes.SKIPPED), string(RecipeStatusTypes.RECOMMENDED):
+		if e.Recipe == nil {
+			return fmt.Errorf("%s event has no recipe", e.Event)
+		}
+		replayRecipeEvent(status, e)
+
+	case jsonEventStepSkipped:
+		if e.Recipe == nil {
+			return fmt.Errorf("%s event has no recipe", e.Event)
+		}
+		status.RecipeStepSkipped(RecipeStepEvent{
+			Recipe: e.Recipe.recipe(),
+			Step:   e.Step,
+			Msg:    e.Msg,
+		})
+
+	case jsonEventRetrying:
+		if e.Recipe == nil {
+			return fmt.Errorf("%s event has no recipe", e.Event)
+		}
+		status.RecipeRetrying(RecipeRetryEvent{
+			Recipe:      e.Recipe.recipe(),
+			Attempt:     e.Attempt,
+			MaxAttempts: e.MaxAttempts,
+			ExitCode:    e.ExitCode,
+			Msg:         e.Msg,
+		})
+
+	case jsonEventInstallComplete:
+		if e.LogFilePath != "" {
+			status.LogFilePath = e.LogFilePath
+		}
+
+		var err error
+		if e.Error != "" {
+			err = errors.New(e.Error)
+		}
+		status.InstallComplete(err)
+
+	case jsonEventInstallCanceled:
+		if e.LogFilePath != "" {
+			status.LogFilePath = e.LogFilePath
+		}
+		status.InstallCanceled()
+
+	default:
+		log.Warnf("Skipping event of unknown kind %s", e.Event)
+	}
+
+	return nil
+}
+
+func replayRecipeEvent(status *InstallStatus, e jsonStatusEvent) {
+	event := RecipeStatusEvent{
+		Recipe:                         e.Recipe.recipe(),
+		Msg:                            e.Msg,
+		EntityGUID:                     e.EntityGUID,
+		ValidationDurationMilliseconds: e.ValidationMillis,
+		AlreadyInstalled:               e.AlreadyInstalled,
+		Reinstalled:                    e.Reinstalled,
+		ValidationFailed:               e.ValidationFailed,
+		RecipeVars:                     e.RecipeVars,
+	}
+
+	switch RecipeStatusType(e.Event) {
+	case RecipeStatusTypes.INSTALLING:
+		status.RecipeInstalling(event)
+	case RecipeStatusTypes.INSTALLED:
+		status.RecipeInstalled(event)
+	case RecipeStatusTypes.FAILED:
+		status.RecipeFailed(event)
+	case RecipeStatusTypes.SKIPPED:
+		status.RecipeSkipped(event)
+	case RecipeStatusTypes.RECOMMENDED:
+		status.RecipeRecommended(event)
+	}
+}
+
+func replayedRecipes(rs []jsonEventRecipe) []types.OpenInstallationRecipe {
+	recipes := make([]types.OpenInstallationRecipe, 0, len(rs))
+	for _, r := range rs {
+		recipes = append(recipes, r.recipe())
+	}
+
+	return recipes
+}
@@ -17,10 +17,60 @@ type StatusSubscriber interface {
 	RecipesSelected(status *InstallStatus, recipes []types.OpenInstallationRecipe) error
 }
 
+// StepStatusSubscriber is implemented by status subscribers that are also
+// notified of the individual steps of a recipe.
+type StepStatusSubscriber interface {
+	RecipeStepSkipped(status *InstallStatus, event RecipeStepEvent) error
+}
+
+// RetryStatusSubscriber is implemented by status subscribers that are also
+// notified when a recipe is retried.
+type RetryStatusSubscriber interface {
+	RecipeRetrying(status *InstallStatus, event RecipeRetryEvent) error
+}
+
+// RecipeRetryEvent represents a recipe about to be retried after its install
+// exited with a retryable exit code.
+type RecipeRetryEvent struct {
+	Recipe types.OpenInstallationRecipe
+	// Attempt is the number of the attempt about to be made, the first retry
+	// being attempt 2.
+	Attempt       int
+	MaxAttempts   int
+	ExitCode      int
+	Msg           string
+	CorrelationID string
+	CampaignID    string
+}
+
+// RecipeStepEvent represents an event for a single step of a recipe.
+type RecipeStepEvent struct {
+	Recipe        types.OpenInstallationRecipe
+	Step          string
+	Msg           string
+	CorrelationID string
+	CampaignID    string
+}
+
 // RecipeStatusEvent represents an event in a recipe's execution.
 type RecipeStatusEvent struct {
 	Recipe                         types.OpenInstallationRecipe
 	Msg                            string
 	EntityGUID                     string
 	ValidationDurationMilliseconds int64
+	// AlreadyInstalled indicates the recipe was found reporting data prior to
+	// installation, and was therefore not executed.
+	AlreadyInstalled bool
+	// Reinstalled indicates the recipe was found reporting data prior to
+	// installation, and was executed again as a reinstall was requested.
+	Reinstalled bool
+	// ValidationFailed indicates the recipe's steps ran, but its data could
+	// not be validated.
+	ValidationFailed bool
+	// RecipeVars holds the variables resolved for the recipe's execution.
+	RecipeVars types.RecipeVars
+	// CorrelationID identifies the install run the event belongs to.
+	CorrelationID string
+	// CampaignID labels the rollout campaign the install run is part of.
+	CampaignID string
 }
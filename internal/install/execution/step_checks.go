@@ -0,0 +1,143 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/go-task/task/v3"
+	"github.com/go-task/task/v3/taskfile"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+const (
+	stepMarkerDirPerm = 0700
+
+	stepCheckPassedMsg    = "already complete"
+	stepMarkerFoundMsg    = "already completed by a previous install"
+	stepAlwaysUpToDateCmd = "true"
+)
+
+var unsafeMarkerChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// StepMarkerPath returns the path of the marker recorded under dir once the
+// step of the given recipe with the given idempotency key succeeds.
+func StepMarkerPath(dir string, recipeName string, key string) string {
+	return filepath.Join(stepMarkerRecipeDir(dir, recipeName), unsafeMarkerChars.ReplaceAllString(key, "_"))
+}
+
+func stepMarkerRecipeDir(dir string, recipeName string) string {
+	return filepath.Join(dir, unsafeMarkerChars.ReplaceAllString(recipeName, "_"))
+}
+
+// applyStepChecks skips the steps of the recipe that its step checks report as
+// already complete, and arranges for the idempotency keys of the other steps
+// to be recorded once they succeed.  Skipped steps are reported through
+// StepSkipped.  Steps already skipped are neither checked nor recorded.  On a
+// reinstall, the markers of previous installs are cleared rather than honored.
+func (re *GoTaskRecipeExecutor) applyStepChecks(ctx context.Context, e *task.Executor, r types.OpenInstallationRecipe, skipped map[string]bool) {
+	if re.Reinstall && !re.DryRun {
+		re.clearStepMarkers(r)
+	}
+
+	for _, c := range r.StepChecks {
+		if skipped[c.Step] {
+			continue
+		}
+
+		t, ok := e.Taskfile.Tasks[c.Step]
+		if !ok {
+			log.Debugf("recipe %s declares a check for unknown step %s", r.Name, c.Step)
+			continue
+		}
+
+		if complete, msg := re.stepComplete(ctx, e, t, r, c); complete {
+			re.skipStep(t, r, c.Step, msg)
+			continue
+		}
+
+		// Nothing runs in a dry run, so nothing completes.
+		if !re.DryRun {
+			re.recordStepMarker(t, r, c)
+		}
+	}
+}
+
+// stepComplete returns whether the step was recorded as complete by a previous
+// install, or else whether its check command succeeds.  Markers are not looked
+// up on a reinstall.
+func (re *GoTaskRecipeExecutor) stepComplete(ctx context.Context, e *task.Executor, t *taskfile.Task, r types.OpenInstallationRecipe, c types.OpenInstallationStepCheck) (bool, string) {
+	if c.IdempotencyKey != "" && re.StepMarkerDir != "" && !re.Reinstall {
+		if _, err := os.Stat(StepMarkerPath(re.StepMarkerDir, r.Name, c.IdempotencyKey)); err == nil {
+			return true, stepMarkerFoundMsg
+		}
+	}
+
+	if c.Check == "" {
+		return false, ""
+	}
+
+	// The check is evaluated by go-task as a status command of the step, so
+	// it sees the same variables and working directory as the step itself.
+	t.Status = append(t.Status, c.Check)
+	if err := e.Status(ctx, taskfile.Call{Task: c.Step}); err != nil {
+		return false, ""
+	}
+
+	return true, stepCheckPassedMsg
+}
+
+// skipStep makes go-task treat the step as up to date, and reports it skipped
+// with the reason.
+func (re *GoTaskRecipeExecutor) skipStep(t *taskfile.Task, r types.OpenInstallationRecipe, step string, msg string) {
+	t.Status = []string{stepAlwaysUpToDateCmd}
+
+	log.WithFields(log.Fields{
+		"name":   r.Name,
+		"step":   step,
+		"reason": msg,
+	}).Debug("skipping recipe step")
+
+	if re.StepSkipped != nil {
+		re.StepSkipped(RecipeStepEvent{
+			Recipe: r,
+			Step:   step,
+			Msg:    msg,
+		})
+	}
+}
+
+// recordStepMarker appends a command to the step that records its idempotency
+// key once the rest of the step has succeeded.
+func (re *GoTaskRecipeExecutor) recordStepMarker(t *taskfile.Task, r types.OpenInstallationRecipe, c types.OpenInstallationStepCheck) {
+	if c.IdempotencyKey == "" || re.StepMarkerDir == "" {
+		return
+	}
+
+	path := StepMarkerPath(re.StepMarkerDir, r.Name, c.IdempotencyKey)
+	if err := os.MkdirAll(filepath.Dir(path), stepMarkerDirPerm); err != nil {
+		log.Warnf("Completion of step %s of recipe %s will not be recorded: %s", c.Step, r.Name, err)
+		return
+	}
+
+	t.Cmds = append(t.Cmds, &taskfile.Cmd{
+		Cmd:    fmt.Sprintf(`echo %q > "%s"`, c.IdempotencyKey, filepath.ToSlash(path)),
+		Silent: true,
+	})
+}
+
+// clearStepMarkers removes the markers recorded for the recipe by previous
+// installs.
+func (re *GoTaskRecipeExecutor) clearStepMarkers(r types.OpenInstallationRecipe) {
+	if re.StepMarkerDir == "" || len(r.StepChecks) == 0 {
+		return
+	}
+
+	if err := os.RemoveAll(stepMarkerRecipeDir(re.StepMarkerDir, r.Name)); err != nil {
+		log.Warnf("Could not clear the completed steps of recipe %s: %s", r.Name, err)
+	}
+}
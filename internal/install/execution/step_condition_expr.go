@@ -0,0 +1,350 @@
+package execution
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// A step condition is a boolean expression over the facts discovered about the
+// host and the variables resolved for the recipe.  It is evaluated without
+// running anything, so a recipe cannot use it to execute code.  The grammar is:
+//
+//   expr       = and { "||" and }
+//   and        = unary { "&&" unary }
+//   unary      = "!" unary | comparison
+//   comparison = operand [ ( "==" | "!=" | "=~" | "!~" | "in" ) operand ]
+//   operand    = "(" expr ")" | string | "true" | "false" | name
+//
+// Strings are quoted with single or double quotes.  Names are the facts of
+// conditionFacts, or else the names of recipe variables.  == and != compare
+// strings ignoring case, =~ and !~ match a string against a regular
+// expression, and in tests whether a string is in a list.  An operand alone
+// is true when it is true, a non-empty string or a non-empty list.
+
+// conditionFacts returns the discovery facts step conditions can refer to.
+func conditionFacts(m types.DiscoveryManifest) map[string]interface{} {
+	packageManagers := m.PackageManagers
+	if packageManagers == nil {
+		packageManagers = []string{}
+	}
+
+	return map[string]interface{}{
+		"hostname":        m.Hostname,
+		"os":              m.OS,
+		"platform":        m.Platform,
+		"platformFamily":  m.PlatformFamily,
+		"platformVersion": m.PlatformVersion,
+		"kernelArch":      m.KernelArch,
+		"kernelVersion":   m.KernelVersion,
+		"packageManager":  m.PackageManager,
+		"packageManagers": packageManagers,
+		"container":       m.Container,
+		"isContainer":     m.Container != "",
+	}
+}
+
+// evaluateCondition evaluates the step condition against the discovery facts
+// and recipe variables.  An error is returned when the expression is malformed
+// or refers to an unknown name.
+func evaluateCondition(expr string, facts map[string]interface{}, vars types.RecipeVars) (bool, error) {
+	tokens, err := tokenizeCondition(expr)
+	if err != nil {
+		return false, err
+	}
+
+	p := conditionParser{tokens: tokens, facts: facts, vars: vars}
+	v, err := p.parseOr()
+	if err != nil {
+		return false, err
+	}
+
+	if t := p.peek(); t.kind != conditionTokenEnd {
+		return false, fmt.Errorf("unexpected %s", t)
+	}
+
+	return conditionTruthy(v), nil
+}
+
+type conditionTokenKind int
+
+const (
+	conditionTokenEnd conditionTokenKind = iota
+	conditionTokenName
+	conditionTokenString
+	conditionTokenOperator
+)
+
+type conditionToken struct {
+	kind  conditionTokenKind
+	value string
+}
+
+func (t conditionToken) String() string {
+	switch t.kind {
+	case conditionTokenEnd:
+		return "end of condition"
+	case conditionTokenString:
+		return fmt.Sprintf("string %q", t.value)
+	}
+
+	return fmt.Sprintf("%q", t.value)
+}
+
+var conditionOperators = []string{"==", "!=", "=~", "!~", "&&", "||", "!", "(", ")"}
+
+func tokenizeCondition(expr string) ([]conditionToken, error) {
+	tokens := []conditionToken{}
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		c := runes[i]
+
+		switch {
+		case unicode.IsSpace(c):
+			i++
+
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != c {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, conditionToken{kind: conditionTokenString, value: string(runes[i+1 : end])})
+			i = end + 1
+
+		case c == '_' || unicode.IsLetter(c):
+			end := i
+			for end < len(runes) && (runes[end] == '_' || runes[end] == '.' || unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end])) {
+				end++
+			}
+			tokens = append(tokens, conditionToken{kind: conditionTokenName, value: string(runes[i:end])})
+			i = end
+
+		default:
+			op := ""
+			for _, o := range conditionOperators {
+				if strings.HasPrefix(string(runes[i:]), o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected %q at position %d", c, i)
+			}
+			tokens = append(tokens, conditionToken{kind: conditionTokenOperator, value: op})
+			i += len([]rune(op))
+		}
+	}
+
+	return tokens, nil
+}
+
+type conditionParser struct {
+	tokens []conditionToken
+	pos    int
+	facts  map[string]interface{}
+	vars   types.RecipeVars
+}
+
+func (p *conditionParser) peek() conditionToken {
+	if p.pos >= len(p.tokens) {
+		return conditionToken{kind: conditionTokenEnd}
+	}
+
+	return p.tokens[p.pos]
+}
+
+func (p *conditionParser) next() conditionToken {
+	t := p.peek()
+	if t.kind != conditionTokenEnd {
+		p.pos++
+	}
+
+	return t
+}
+
+func (p *conditionParser) isOperator(op string) bool {
+	t := p.peek()
+	return t.kind == conditionTokenOperator && t.value == op
+}
+
+func (p *conditionParser) parseOr() (interface{}, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOperator("||") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = conditionTruthy(left) || conditionTruthy(right)
+	}
+
+	return left, nil
+}
+
+func (p *conditionParser) parseAnd() (interface{}, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOperator("&&") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = conditionTruthy(left) && conditionTruthy(right)
+	}
+
+	return left, nil
+}
+
+func (p *conditionParser) parseUnary() (interface{}, error) {
+	if p.isOperator("!") {
+		p.next()
+		v, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return !conditionTruthy(v), nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *conditionParser) parseComparison() (interface{}, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	isComparison := (t.kind == conditionTokenOperator && (t.value == "==" || t.value == "!=" || t.value == "=~" || t.value == "!~")) ||
+		(t.kind == conditionTokenName && t.value == "in")
+	if !isComparison {
+		return left, nil
+	}
+	p.next()
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	return compareCondition(t.value, left, right)
+}
+
+func (p *conditionParser) parseOperand() (interface{}, error) {
+	t := p.next()
+
+	switch t.kind {
+	case conditionTokenString:
+		return t.value, nil
+
+	case conditionTokenName:
+		switch t.value {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+
+		if v, ok := p.facts[t.value]; ok {
+			return v, nil
+		}
+		if v, ok := p.vars[t.value]; ok {
+			return v, nil
+		}
+		return nil, fmt.Errorf("unknown name %s", t.value)
+
+	case conditionTokenOperator:
+		if t.value == "(" {
+			v, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if !p.isOperator(")") {
+				return nil, fmt.Errorf("expected \")\" but found %s", p.peek())
+			}
+			p.next()
+			return v, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unexpected %s", t)
+}
+
+func compareCondition(op string, left interface{}, right interface{}) (bool, error) {
+	switch op {
+	case "in":
+		s, ok := left.(string)
+		list, isList := right.([]string)
+		if !ok || !isList {
+			return false, fmt.Errorf("in requires a string and a list")
+		}
+		for _, v := range list {
+			if strings.EqualFold(s, v) {
+				return true, nil
+			}
+		}
+		return false, nil
+
+	case "=~", "!~":
+		s, ok := left.(string)
+		pattern, isString := right.(string)
+		if !ok || !isString {
+			return false, fmt.Errorf("%s requires a string and a regular expression", op)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, fmt.Errorf("invalid regular expression %q: %s", pattern, err)
+		}
+		return re.MatchString(s) == (op == "=~"), nil
+	}
+
+	equal, err := conditionEqual(left, right)
+	if err != nil {
+		return false, err
+	}
+
+	return equal == (op == "=="), nil
+}
+
+func conditionEqual(left interface{}, right interface{}) (bool, error) {
+	switch l := left.(type) {
+	case string:
+		if r, ok := right.(string); ok {
+			return strings.EqualFold(l, r), nil
+		}
+	case bool:
+		if r, ok := right.(bool); ok {
+			return l == r, nil
+		}
+	}
+
+	return false, fmt.Errorf("cannot compare %v and %v", left, right)
+}
+
+func conditionTruthy(v interface{}) bool {
+	switch t := v.(type) {
+	case bool:
+		return t
+	case string:
+		return t != ""
+	case []string:
+		return len(t) > 0
+	}
+
+	return false
+}
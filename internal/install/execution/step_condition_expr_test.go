@@ -0,0 +1,73 @@
+//go:build unit
+// +build unit
+
+package execution
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestEvaluateCondition(t *testing.T) {
+	facts := conditionFacts(types.DiscoveryManifest{
+		OS:              "linux",
+		Platform:        "ubuntu",
+		PlatformVersion: "20.04",
+		PackageManager:  "apt",
+		PackageManagers: []string{"apt", "snap"},
+	})
+	vars := types.RecipeVars{"NEW_RELIC_REGION": "EU"}
+
+	tests := map[string]bool{
+		`os == "linux"`: true,
+		`os == 'Linux'`: true,
+		`os != "linux"`: false,
+		`platform == "ubuntu" && packageManager == "apt"`: true,
+		`platform == "centos" || packageManager == "apt"`: true,
+		`platform == "centos" || packageManager == "yum"`: false,
+		`"snap" in packageManagers`:                       true,
+		`"yum" in packageManagers`:                        false,
+		`!("yum" in packageManagers)`:                     true,
+		`platformVersion =~ '^20\.'`:                      true,
+		`platformVersion !~ '^20\.'`:                      false,
+		`isContainer`:                                     false,
+		`!isContainer`:                                    true,
+		`container`:                                       false,
+		`packageManagers`:                                 true,
+		`isContainer == false`:                            true,
+		`NEW_RELIC_REGION == "eu"`:                        true,
+		`!(os == "windows") && (platform == "ubuntu" || platform == "debian")`: true,
+	}
+
+	for expr, want := range tests {
+		got, err := evaluateCondition(expr, facts, vars)
+		require.NoError(t, err, expr)
+		require.Equal(t, want, got, expr)
+	}
+}
+
+func TestEvaluateCondition_Errors(t *testing.T) {
+	facts := conditionFacts(types.DiscoveryManifest{OS: "linux"})
+
+	for _, expr := range []string{
+		``,
+		`os ==`,
+		`os == "linux`,
+		`unknown == "linux"`,
+		`(os == "linux"`,
+		`os == "linux")`,
+		`os == "linux" &&`,
+		`os in "linux"`,
+		`os =~ "("`,
+		`packageManagers == "apt"`,
+		`os == true`,
+		`os ; rm -rf /`,
+		`$(reboot)`,
+	} {
+		_, err := evaluateCondition(expr, facts, types.RecipeVars{})
+		require.Error(t, err, expr)
+	}
+}
@@ -0,0 +1,70 @@
+package execution
+
+import (
+	"fmt"
+
+	"github.com/go-task/task/v3"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// applyStepConditions skips the steps of the recipe whose condition is false
+// for the discovered host, reporting them through StepSkipped, and returns the
+// names of the skipped steps.  An error is returned when a condition cannot be
+// evaluated, so that a step is never run or skipped by mistake.
+func (re *GoTaskRecipeExecutor) applyStepConditions(e *task.Executor, m types.DiscoveryManifest, r types.OpenInstallationRecipe, recipeVars types.RecipeVars) (map[string]bool, error) {
+	skipped := map[string]bool{}
+
+	unmet, err := unmetStepConditions(m, r, recipeVars)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range unmet {
+		t, ok := e.Taskfile.Tasks[c.Step]
+		if !ok {
+			log.Debugf("recipe %s declares a condition for unknown step %s", r.Name, c.Step)
+			continue
+		}
+
+		re.skipStep(t, r, c.Step, conditionNotMetMsg(c))
+		skipped[c.Step] = true
+	}
+
+	return skipped, nil
+}
+
+// unmetStepConditions returns the step conditions of the recipe that are false
+// for the discovered host.
+func unmetStepConditions(m types.DiscoveryManifest, r types.OpenInstallationRecipe, recipeVars types.RecipeVars) ([]types.OpenInstallationStepCondition, error) {
+	unmet := []types.OpenInstallationStepCondition{}
+	if len(r.StepConditions) == 0 {
+		return unmet, nil
+	}
+
+	facts := conditionFacts(m)
+	for _, c := range r.StepConditions {
+		ok, err := evaluateCondition(c.When, facts, recipeVars)
+		if err != nil {
+			return nil, fmt.Errorf("could not evaluate the condition of step %s of recipe %s: %s", c.Step, r.Name, err)
+		}
+
+		log.WithFields(log.Fields{
+			"name":      r.Name,
+			"step":      c.Step,
+			"condition": c.When,
+			"result":    ok,
+		}).Debug("evaluated recipe step condition")
+
+		if !ok {
+			unmet = append(unmet, c)
+		}
+	}
+
+	return unmet, nil
+}
+
+func conditionNotMetMsg(c types.OpenInstallationStepCondition) string {
+	return fmt.Sprintf("condition not met: %s", c.When)
+}
@@ -1,10 +1,15 @@
 package execution
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 
+	log "github.com/sirupsen/logrus"
+
 	"github.com/newrelic/newrelic-cli/internal/credentials"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
 	"github.com/newrelic/newrelic-cli/internal/utils"
 	"github.com/newrelic/newrelic-client-go/pkg/region"
 )
@@ -12,6 +17,8 @@ import (
 type SuccessLinkGenerator interface {
 	GenerateExplorerLink(filter string) string
 	GenerateEntityLink(entityGUID string) string
+	GenerateDashboardLink(dashboardName string) string
+	GenerateNRQLLink(query string) string
 	GenerateRedirectURL(status InstallStatus) string
 }
 
@@ -39,49 +46,152 @@ func (g *ConcreteSuccessLinkGenerator) GenerateEntityLink(entityGUID string) str
 	return generateEntityLink(entityGUID)
 }
 
+// GenerateDashboardLink creates a link to the dashboard listing, searching for
+// the dashboard template with the given name.
+func (g *ConcreteSuccessLinkGenerator) GenerateDashboardLink(dashboardName string) string {
+	return generateDashboardLink(dashboardName)
+}
+
+// GenerateNRQLLink creates a link that opens the given query in the query builder.
+func (g *ConcreteSuccessLinkGenerator) GenerateNRQLLink(query string) string {
+	return generateNRQLLink(query)
+}
+
 // GenerateRedirectURL creates a URL for the user to navigate to after running
 // through an installation. The URL is displayed in the CLI out as well and is
 // also provided in the nerdstorage document. This provides the user two options
 // to see their data - click from the CLI output or from the frontend.
 func (g *ConcreteSuccessLinkGenerator) GenerateRedirectURL(status InstallStatus) string {
 	if status.hasAnyRecipeStatus(RecipeStatusTypes.INSTALLED) {
-		switch t := status.successLinkConfig.Type; {
-		case strings.EqualFold(string(t), "explorer"):
-			return g.GenerateExplorerLink(status.successLinkConfig.Filter)
-		default:
-			return g.GenerateEntityLink(status.HostEntityGUID())
-		}
+		_, link := successLink(g, status.successLinkConfig, status.HostEntityGUID())
+		return link
 	}
 
 	return ""
 }
 
+// The types of success link, as written to success link output.
+const (
+	successLinkTypeExplorer  = "explorer"
+	successLinkTypeDashboard = "dashboard"
+	successLinkTypeNRQL      = "nrql"
+	successLinkTypeEntity    = "entity"
+)
+
+// successLink returns the type of the given success link configuration and the
+// link it generates.  Configurations of the host type, or of no or an unknown
+// type, link to the given entity.
+func successLink(g SuccessLinkGenerator, c types.OpenInstallationSuccessLinkConfig, entityGUID string) (string, string) {
+	switch t := c.Type; {
+	case strings.EqualFold(string(t), "explorer"):
+		return successLinkTypeExplorer, g.GenerateExplorerLink(c.Filter)
+	case strings.EqualFold(string(t), "dashboard"):
+		return successLinkTypeDashboard, g.GenerateDashboardLink(c.Filter)
+	case strings.EqualFold(string(t), "nrql"):
+		return successLinkTypeNRQL, g.GenerateNRQLLink(c.Filter)
+	case t == "" || strings.EqualFold(string(t), "host"):
+		return successLinkTypeEntity, g.GenerateEntityLink(entityGUID)
+	default:
+		log.Debugf("unknown success link type %s, falling back to entity link", t)
+		return successLinkTypeEntity, g.GenerateEntityLink(entityGUID)
+	}
+}
+
 func generateExplorerLink(filter string) string {
-	return fmt.Sprintf("https://%s/launcher/nr1-core.explorer?platform[filters]=%s&platform[accountId]=%d",
-		nrPlatformHostname(),
-		utils.Base64Encode(filter),
-		credentials.DefaultProfile().AccountID,
+	return fmt.Sprintf("%s/launcher/nr1-core.explorer?platform[filters]=%s&platform[accountId]=%d",
+		nrPlatformBaseURL(),
+		utils.Base64Encode(encodeExplorerFilter(filter)),
+		defaultAccountID(),
 	)
 }
 
+func generateDashboardLink(dashboardName string) string {
+	pane := map[string]interface{}{
+		"nerdletId": "dashboards.list",
+		"search":    dashboardName,
+	}
+
+	return fmt.Sprintf("%s/launcher/dashboards.launcher?pane=%s&platform[accountId]=%d",
+		nrPlatformBaseURL(),
+		encodePane(pane),
+		defaultAccountID(),
+	)
+}
+
+func generateNRQLLink(query string) string {
+	accountID := defaultAccountID()
+	pane := map[string]interface{}{
+		"nerdletId":              "data-exploration.query-builder",
+		"initialActiveInterface": "nrqlEditor",
+		"initialAccountId":       accountID,
+		"initialNrqlValue":       query,
+	}
+
+	return fmt.Sprintf("%s/launcher/data-exploration.query-builder?pane=%s&platform[accountId]=%d",
+		nrPlatformBaseURL(),
+		encodePane(pane),
+		accountID,
+	)
+}
+
+// encodePane serializes nerdlet state the way the platform expects to find it
+// in the "pane" URL parameter.
+func encodePane(pane map[string]interface{}) string {
+	data, err := json.Marshal(pane)
+	if err != nil {
+		log.Debugf("could not encode link pane: %s", err)
+		return ""
+	}
+
+	return utils.Base64Encode(string(data))
+}
+
+func defaultAccountID() int {
+	defaultProfile := credentials.DefaultProfile()
+	if defaultProfile == nil {
+		return 0
+	}
+
+	return defaultProfile.AccountID
+}
+
 func generateEntityLink(entityGUID string) string {
-	return fmt.Sprintf("https://%s/redirect/entity/%s", nrPlatformHostname(), entityGUID)
+	return fmt.Sprintf("%s/redirect/entity/%s", nrPlatformBaseURL(), entityGUID)
+}
+
+// nrPlatformBaseURLEnv overrides the base URL of generated links, for regions
+// and environments without a known platform host.
+const nrPlatformBaseURLEnv = "NEW_RELIC_PLATFORM_URL"
+
+// nrPlatformBaseURL returns the base URL of the platform, based on the region
+// of the default profile unless overridden.
+func nrPlatformBaseURL() string {
+	if override := strings.TrimRight(os.Getenv(nrPlatformBaseURLEnv), "/"); override != "" {
+		return override
+	}
+
+	return "https://" + nrPlatformHostname()
 }
 
 // nrPlatformHostname returns the host for the platform based on the region set.
 func nrPlatformHostname() string {
 	defaultProfile := credentials.DefaultProfile()
-	if defaultProfile == nil {
+	if defaultProfile == nil || defaultProfile.Region == "" {
 		return nrPlatformHostnames.US
 	}
 
-	if strings.EqualFold(defaultProfile.Region, region.Staging.String()) {
-		return nrPlatformHostnames.Staging
+	r, err := region.Parse(defaultProfile.Region)
+	if err != nil {
+		log.Debugf("unknown region %s, linking to the US platform", defaultProfile.Region)
+		return nrPlatformHostnames.US
 	}
 
-	if strings.EqualFold(defaultProfile.Region, region.EU.String()) {
+	switch r {
+	case region.EU:
 		return nrPlatformHostnames.EU
+	case region.Staging:
+		return nrPlatformHostnames.Staging
+	default:
+		return nrPlatformHostnames.US
 	}
-
-	return nrPlatformHostnames.US
 }
@@ -0,0 +1,167 @@
+//go:build unit
+// +build unit
+
+package execution
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/credentials"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestGenerateExplorerLink(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+	g := NewConcreteSuccessLinkGenerator()
+
+	link := g.GenerateExplorerLink("testFilter")
+	require.True(t, strings.HasPrefix(link, "https://one.newrelic.com/launcher/nr1-core.explorer?"))
+	require.Contains(t, link, "platform[accountId]=12345")
+}
+
+func TestGenerateExplorerLink_EscapesFilter(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+	g := NewConcreteSuccessLinkGenerator()
+
+	link := g.GenerateExplorerLink("`tags.team name` = 'o''brien'")
+
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+
+	data, err := base64.StdEncoding.DecodeString(u.Query().Get("platform[filters]"))
+	require.NoError(t, err)
+	require.Equal(t, "\"`tags.team name` = 'o\\\\'brien'\"", string(data))
+}
+
+func TestGenerateEntityLink(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+	g := NewConcreteSuccessLinkGenerator()
+
+	link := g.GenerateEntityLink("testGuid")
+	require.Equal(t, "https://one.newrelic.com/redirect/entity/testGuid", link)
+}
+
+func TestGenerateDashboardLink(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+	g := NewConcreteSuccessLinkGenerator()
+
+	link := g.GenerateDashboardLink("MySQL overview")
+	require.True(t, strings.HasPrefix(link, "https://one.newrelic.com/launcher/dashboards.launcher?"))
+	require.Contains(t, link, "platform[accountId]=12345")
+
+	pane := decodePaneParam(t, link)
+	require.Equal(t, "dashboards.list", pane["nerdletId"])
+	require.Equal(t, "MySQL overview", pane["search"])
+}
+
+func TestGenerateNRQLLink(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+	g := NewConcreteSuccessLinkGenerator()
+
+	link := g.GenerateNRQLLink("SELECT count(*) FROM SystemSample")
+	require.True(t, strings.HasPrefix(link, "https://one.newrelic.com/launcher/data-exploration.query-builder?"))
+	require.Contains(t, link, "platform[accountId]=12345")
+
+	pane := decodePaneParam(t, link)
+	require.Equal(t, "data-exploration.query-builder", pane["nerdletId"])
+	require.Equal(t, "SELECT count(*) FROM SystemSample", pane["initialNrqlValue"])
+	require.Equal(t, float64(12345), pane["initialAccountId"])
+}
+
+func TestGenerateDashboardLink_EURegion(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345, Region: "EU"})
+	g := NewConcreteSuccessLinkGenerator()
+
+	link := g.GenerateDashboardLink("MySQL overview")
+	require.True(t, strings.HasPrefix(link, "https://one.eu.newrelic.com/"))
+}
+
+func TestGenerateLinks_Regions(t *testing.T) {
+	defer credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+
+	cases := []struct {
+		region string
+		prefix string
+	}{
+		{region: "", prefix: "https://one.newrelic.com/"},
+		{region: "US", prefix: "https://one.newrelic.com/"},
+		{region: "EU", prefix: "https://one.eu.newrelic.com/"},
+		{region: "eu", prefix: "https://one.eu.newrelic.com/"},
+		{region: "Staging", prefix: "https://staging-one.newrelic.com/"},
+		{region: "unknown", prefix: "https://one.newrelic.com/"},
+	}
+
+	for _, c := range cases {
+		credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345, Region: c.region})
+		g := NewConcreteSuccessLinkGenerator()
+
+		require.True(t, strings.HasPrefix(g.GenerateEntityLink("testGuid"), c.prefix), c.region)
+		require.True(t, strings.HasPrefix(g.GenerateExplorerLink("testFilter"), c.prefix), c.region)
+		require.True(t, strings.HasPrefix(g.GenerateDashboardLink("testDashboard"), c.prefix), c.region)
+		require.True(t, strings.HasPrefix(g.GenerateNRQLLink("SELECT 1"), c.prefix), c.region)
+	}
+}
+
+func TestGenerateLinks_BaseURLOverride(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345, Region: "EU"})
+	os.Setenv(nrPlatformBaseURLEnv, "https://one.example.com/")
+	defer os.Unsetenv(nrPlatformBaseURLEnv)
+
+	g := NewConcreteSuccessLinkGenerator()
+
+	require.Equal(t, "https://one.example.com/redirect/entity/testGuid", g.GenerateEntityLink("testGuid"))
+	require.True(t, strings.HasPrefix(g.GenerateExplorerLink("testFilter"), "https://one.example.com/launcher/"))
+}
+
+func TestGenerateRedirectURL_LinkTypes(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+	g := NewConcreteSuccessLinkGenerator()
+
+	cases := []struct {
+		linkType string
+		prefix   string
+	}{
+		{linkType: "explorer", prefix: "https://one.newrelic.com/launcher/nr1-core.explorer?"},
+		{linkType: "EXPLORER", prefix: "https://one.newrelic.com/launcher/nr1-core.explorer?"},
+		{linkType: "dashboard", prefix: "https://one.newrelic.com/launcher/dashboards.launcher?"},
+		{linkType: "nrql", prefix: "https://one.newrelic.com/launcher/data-exploration.query-builder?"},
+		{linkType: "host", prefix: "https://one.newrelic.com/redirect/entity/testGuid"},
+		{linkType: "", prefix: "https://one.newrelic.com/redirect/entity/testGuid"},
+		{linkType: "unknown", prefix: "https://one.newrelic.com/redirect/entity/testGuid"},
+	}
+
+	for _, c := range cases {
+		status := InstallStatus{
+			EntityGUIDs: []string{"testGuid"},
+			Statuses: []*RecipeStatus{
+				{Status: RecipeStatusTypes.INSTALLED},
+			},
+			successLinkConfig: types.OpenInstallationSuccessLinkConfig{
+				Type:   types.OpenInstallationSuccessLinkType(c.linkType),
+				Filter: "testFilter",
+			},
+		}
+
+		require.True(t, strings.HasPrefix(g.GenerateRedirectURL(status), c.prefix), c.linkType)
+	}
+}
+
+func decodePaneParam(t *testing.T, link string) map[string]interface{} {
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+
+	data, err := base64.StdEncoding.DecodeString(u.Query().Get("pane"))
+	require.NoError(t, err)
+
+	pane := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(data, &pane))
+
+	return pane
+}
@@ -0,0 +1,197 @@
+package execution
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+const (
+	// SuccessLinksStdout is the path that writes the success links to
+	// standard output rather than to a file.
+	SuccessLinksStdout = "-"
+
+	successLinksFilePerm = 0644
+)
+
+// SuccessLinksStatusReporter is an implementation of the StatusSubscriber
+// interface that writes the success links of the install as JSON: the link to
+// the installed data, along with the link of each installed recipe.  The links
+// are written once the install completes or is canceled.
+type SuccessLinksStatusReporter struct {
+	path   string
+	stdout io.Writer
+
+	mu        sync.Mutex
+	installed []installedRecipeLink
+}
+
+// installedRecipeLink is what an installed recipe's success link is generated
+// from.
+type installedRecipeLink struct {
+	name        string
+	displayName string
+	entityGUID  string
+	config      types.OpenInstallationSuccessLinkConfig
+}
+
+// SuccessLinks are the success links of an install.
+type SuccessLinks struct {
+	RedirectURL string              `json:"redirectUrl"`
+	CampaignID  string              `json:"campaignId,omitempty"`
+	Recipes     []RecipeSuccessLink `json:"recipes"`
+}
+
+// RecipeSuccessLink is the success link of an installed recipe.
+type RecipeSuccessLink struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName,omitempty"`
+	Type        string `json:"type"`
+	URL         string `json:"url"`
+}
+
+// NewSuccessLinksStatusReporter returns a new instance of
+// SuccessLinksStatusReporter writing to the given file, or to standard output
+// when the path is SuccessLinksStdout.
+func NewSuccessLinksStatusReporter(path string) *SuccessLinksStatusReporter {
+	r := SuccessLinksStatusReporter{
+		path:   path,
+		stdout: os.Stdout,
+	}
+
+	return &r
+}
+
+func (r *SuccessLinksStatusReporter) RecipeInstalled(status *InstallStatus, event RecipeStatusEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	l := installedRecipeLink{
+		name:        event.Recipe.Name,
+		displayName: event.Recipe.DisplayName,
+		entityGUID:  event.EntityGUID,
+		config:      event.Recipe.SuccessLinkConfig,
+	}
+
+	for n, i := range r.installed {
+		if i.name == l.name {
+			r.installed[n] = l
+			return nil
+		}
+	}
+
+	r.installed = append(r.installed, l)
+
+	return nil
+}
+
+func (r *SuccessLinksStatusReporter) RecipeInstalling(status *InstallStatus, event RecipeStatusEvent) error {
+	return nil
+}
+
+func (r *SuccessLinksStatusReporter) RecipeFailed(status *InstallStatus, event RecipeStatusEvent) error {
+	return nil
+}
+
+func (r *SuccessLinksStatusReporter) RecipeSkipped(status *InstallStatus, event RecipeStatusEvent) error {
+	return nil
+}
+
+func (r *SuccessLinksStatusReporter) RecipeRecommended(status *InstallStatus, event RecipeStatusEvent) error {
+	return nil
+}
+
+func (r *SuccessLinksStatusReporter) RecipeAvailable(status *InstallStatus, recipe types.OpenInstallationRecipe) error {
+	return nil
+}
+
+func (r *SuccessLinksStatusReporter) RecipesAvailable(status *InstallStatus, recipes []types.OpenInstallationRecipe) error {
+	return nil
+}
+
+func (r *SuccessLinksStatusReporter) RecipesSelected(status *InstallStatus, recipes []types.OpenInstallationRecipe) error {
+	return nil
+}
+
+func (r *SuccessLinksStatusReporter) DiscoveryComplete(status *InstallStatus, dm types.DiscoveryManifest) error {
+	return nil
+}
+
+func (r *SuccessLinksStatusReporter) InstallComplete(status *InstallStatus) error {
+	return r.writeLinks(status)
+}
+
+func (r *SuccessLinksStatusReporter) InstallCanceled(status *InstallStatus) error {
+	return r.writeLinks(status)
+}
+
+func (r *SuccessLinksStatusReporter) writeLinks(status *InstallStatus) error {
+	data, err := json.MarshalIndent(r.links(status), "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if r.path == SuccessLinksStdout {
+		_, err = r.stdout.Write(data)
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+
+	if err := utils.WriteFileAtomic(r.path, data, successLinksFilePerm); err != nil {
+		return err
+	}
+
+	log.Debugf("success links written to %s", r.path)
+
+	return nil
+}
+
+// links generates the success link of each installed recipe with the
+// install's link generator.  Recipes linking to their host link to the host
+// entity of the install when they did not report an entity of their own.
+func (r *SuccessLinksStatusReporter) links(status *InstallStatus) SuccessLinks {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := SuccessLinks{
+		RedirectURL: status.RedirectURL,
+		CampaignID:  status.CampaignID,
+		Recipes:     []RecipeSuccessLink{},
+	}
+
+	if status.successLinkGenerator == nil {
+		return out
+	}
+
+	for _, i := range r.installed {
+		guid := i.entityGUID
+		if guid == "" {
+			guid = status.HostEntityGUID()
+		}
+
+		t, url := successLink(status.successLinkGenerator, i.config, guid)
+		if url == "" {
+			continue
+		}
+
+		out.Recipes = append(out.Recipes, RecipeSuccessLink{
+			Name:        i.name,
+			DisplayName: i.displayName,
+			Type:        t,
+			URL:         url,
+		})
+	}
+
+	return out
+}
@@ -0,0 +1,81 @@
+//go:build unit
+// +build unit
+
+package execution
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestSuccessLinksStatusReporter_interface(t *testing.T) {
+	var r StatusSubscriber = NewSuccessLinksStatusReporter("")
+	require.NotNil(t, r)
+}
+
+func TestSuccessLinksStatusReporter_InstallComplete(t *testing.T) {
+	dir, err := ioutil.TempDir("", "links")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "out", "links.json")
+	r := NewSuccessLinksStatusReporter(path)
+	g := NewMockSuccessLinkGenerator()
+	g.GenerateEntityLinkVal = "https://one.newrelic.com/redirect/entity/host"
+	g.GenerateExplorerLinkVal = "https://one.newrelic.com/launcher/nr1-core.explorer"
+	s := NewInstallStatus([]StatusSubscriber{r}, g)
+
+	infra := types.OpenInstallationRecipe{Name: "infrastructure-agent-installer", DisplayName: "Infrastructure Agent"}
+	mysql := types.OpenInstallationRecipe{
+		Name: "mysql-open-source-integration",
+		SuccessLinkConfig: types.OpenInstallationSuccessLinkConfig{
+			Type:   types.OpenInstallationSuccessLinkTypeTypes.EXPLORER,
+			Filter: `"mysql"`,
+		},
+	}
+	failed := types.OpenInstallationRecipe{Name: "failed"}
+
+	s.RecipeFailed(RecipeStatusEvent{Recipe: failed, Msg: "exit status 1"})
+	s.RecipeInstalled(RecipeStatusEvent{Recipe: infra, EntityGUID: "host"})
+	s.RecipeInstalled(RecipeStatusEvent{Recipe: mysql})
+	s.InstallComplete(nil)
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	var links SuccessLinks
+	require.NoError(t, json.Unmarshal(data, &links))
+	require.Equal(t, g.GenerateExplorerLinkVal, links.RedirectURL)
+	require.Equal(t, []RecipeSuccessLink{
+		{
+			Name:        "infrastructure-agent-installer",
+			DisplayName: "Infrastructure Agent",
+			Type:        "entity",
+			URL:         g.GenerateEntityLinkVal,
+		},
+		{
+			Name: "mysql-open-source-integration",
+			Type: "explorer",
+			URL:  g.GenerateExplorerLinkVal,
+		},
+	}, links.Recipes)
+}
+
+func TestSuccessLinksStatusReporter_Stdout(t *testing.T) {
+	var out bytes.Buffer
+	r := NewSuccessLinksStatusReporter(SuccessLinksStdout)
+	r.stdout = &out
+	s := NewInstallStatus([]StatusSubscriber{r}, NewMockSuccessLinkGenerator())
+
+	s.InstallCanceled()
+
+	require.JSONEq(t, `{"redirectUrl": "", "recipes": []}`, out.String())
+}
@@ -0,0 +1,217 @@
+package execution
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/credentials"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+const (
+	installTelemetryEventType = "NewRelicCLIInstall"
+
+	// UsageDataOptOutEnvVar disables installer telemetry when set to a true
+	// value, regardless of the --send-usage-data flag.
+	UsageDataOptOutEnvVar = "NEW_RELIC_CLI_DISABLE_USAGE_DATA"
+
+	defaultTelemetrySendTimeout = 5 * time.Second
+
+	failureCategoryExecution  = "execution"
+	failureCategoryValidation = "validation"
+	failureCategoryOther      = "other"
+)
+
+// UsageDataOptedOut reports whether installer telemetry has been disabled via
+// the environment.
+func UsageDataOptedOut() bool {
+	optOut, err := strconv.ParseBool(os.Getenv(UsageDataOptOutEnvVar))
+	return err == nil && optOut
+}
+
+// TelemetryStatusReporter is an implementation of the StatusSubscriber
+// interface that records a summary of the install as a custom event, giving
+// aggregate insight into install reliability.  Sending the event is bounded by
+// a timeout and its errors are only logged, so it never blocks or fails the
+// install.
+type TelemetryStatusReporter struct {
+	client      EventsClient
+	start       time.Time
+	sendTimeout time.Duration
+	failures    map[string]int
+	mu          sync.Mutex
+}
+
+type installTelemetryEvent struct {
+	EventType          string `json:"eventType"`
+	InstallID          string `json:"installId"`
+	CorrelationID      string `json:"correlationId"`
+	CampaignID         string `json:"campaignId,omitempty"`
+	CLIVersion         string `json:"cliVersion"`
+	Outcome            string `json:"outcome"`
+	DurationMs         int64  `json:"durationMs"`
+	TargetedInstall    bool   `json:"targetedInstall"`
+	RecipesInstalled   int    `json:"recipesInstalled"`
+	RecipesFailed      int    `json:"recipesFailed"`
+	RecipesSkipped     int    `json:"recipesSkipped"`
+	RecipesCanceled    int    `json:"recipesCanceled"`
+	FailuresExecution  int    `json:"failuresExecution"`
+	FailuresValidation int    `json:"failuresValidation"`
+	FailuresOther      int    `json:"failuresOther"`
+	OS                 string `json:"os"`
+	Platform           string `json:"platform"`
+	PlatformFamily     string `json:"platformFamily"`
+	PlatformVersion    string `json:"platformVersion"`
+	KernelArch         string `json:"kernelArch"`
+}
+
+// NewTelemetryStatusReporter returns a new instance of TelemetryStatusReporter.
+func NewTelemetryStatusReporter(client EventsClient) *TelemetryStatusReporter {
+	r := TelemetryStatusReporter{
+		client:      client,
+		start:       time.Now(),
+		sendTimeout: defaultTelemetrySendTimeout,
+		failures:    map[string]int{},
+	}
+
+	return &r
+}
+
+func (r *TelemetryStatusReporter) RecipeFailed(status *InstallStatus, event RecipeStatusEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.failures[failureCategory(event.Msg)]++
+
+	return nil
+}
+
+func (r *TelemetryStatusReporter) InstallComplete(status *InstallStatus) error {
+	outcome := "success"
+	if status.Error.Message != "" {
+		outcome = "error"
+	} else if status.hasAnyRecipeStatus(RecipeStatusTypes.FAILED) {
+		outcome = "partial"
+	}
+
+	r.send(status, outcome)
+
+	return nil
+}
+
+func (r *TelemetryStatusReporter) InstallCanceled(status *InstallStatus) error {
+	r.send(status, "canceled")
+
+	return nil
+}
+
+func (r *TelemetryStatusReporter) DiscoveryComplete(status *InstallStatus, dm types.DiscoveryManifest) error {
+	return nil
+}
+
+func (r *TelemetryStatusReporter) RecipeAvailable(status *InstallStatus, recipe types.OpenInstallationRecipe) error {
+	return nil
+}
+
+func (r *TelemetryStatusReporter) RecipeInstalled(status *InstallStatus, event RecipeStatusEvent) error {
+	return nil
+}
+
+func (r *TelemetryStatusReporter) RecipeInstalling(status *InstallStatus, event RecipeStatusEvent) error {
+	return nil
+}
+
+func (r *TelemetryStatusReporter) RecipeRecommended(status *InstallStatus, event RecipeStatusEvent) error {
+	return nil
+}
+
+func (r *TelemetryStatusReporter) RecipeSkipped(status *InstallStatus, event RecipeStatusEvent) error {
+	return nil
+}
+
+func (r *TelemetryStatusReporter) RecipesAvailable(status *InstallStatus, recipes []types.OpenInstallationRecipe) error {
+	return nil
+}
+
+func (r *TelemetryStatusReporter) RecipesSelected(status *InstallStatus, recipes []types.OpenInstallationRecipe) error {
+	return nil
+}
+
+func (r *TelemetryStatusReporter) send(status *InstallStatus, outcome string) {
+	defaultProfile := credentials.DefaultProfile()
+	if defaultProfile == nil || defaultProfile.AccountID == 0 {
+		log.Debug("skipping install telemetry, no account configured")
+		return
+	}
+
+	evt := r.buildEvent(status, outcome)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- r.client.CreateEvent(defaultProfile.AccountID, evt)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			log.Debugf("could not send install telemetry: %s", err)
+		}
+	case <-time.After(r.sendTimeout):
+		log.Debug("timed out sending install telemetry")
+	}
+}
+
+func (r *TelemetryStatusReporter) buildEvent(status *InstallStatus, outcome string) installTelemetryEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	evt := installTelemetryEvent{
+		EventType:          installTelemetryEventType,
+		InstallID:          status.DocumentID,
+		CorrelationID:      status.CorrelationID,
+		CampaignID:         status.CampaignID,
+		CLIVersion:         status.CLIVersion,
+		Outcome:            outcome,
+		DurationMs:         time.Since(r.start).Milliseconds(),
+		TargetedInstall:    status.IsTargetedInstall(),
+		FailuresExecution:  r.failures[failureCategoryExecution],
+		FailuresValidation: r.failures[failureCategoryValidation],
+		FailuresOther:      r.failures[failureCategoryOther],
+		OS:                 status.DiscoveryManifest.OS,
+		Platform:           status.DiscoveryManifest.Platform,
+		PlatformFamily:     status.DiscoveryManifest.PlatformFamily,
+		PlatformVersion:    status.DiscoveryManifest.PlatformVersion,
+		KernelArch:         status.DiscoveryManifest.KernelArch,
+	}
+
+	for _, s := range status.Statuses {
+		switch s.Status {
+		case RecipeStatusTypes.INSTALLED:
+			evt.RecipesInstalled++
+		case RecipeStatusTypes.FAILED:
+			evt.RecipesFailed++
+		case RecipeStatusTypes.SKIPPED:
+			evt.RecipesSkipped++
+		case RecipeStatusTypes.CANCELED:
+			evt.RecipesCanceled++
+		}
+	}
+
+	return evt
+}
+
+func failureCategory(msg string) string {
+	switch {
+	case strings.Contains(msg, "while executing"):
+		return failureCategoryExecution
+	case strings.Contains(msg, "while validating"):
+		return failureCategoryValidation
+	default:
+		return failureCategoryOther
+	}
+}
@@ -0,0 +1,106 @@
+//go:build unit
+// +build unit
+
+package execution
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/credentials"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestTelemetryStatusReporter_interface(t *testing.T) {
+	var r StatusSubscriber = NewTelemetryStatusReporter(NewMockEventsClient())
+	require.NotNil(t, r)
+}
+
+func TestTelemetryStatusReporter_InstallComplete(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+	c := NewMockEventsClient()
+	r := NewTelemetryStatusReporter(c)
+	status := NewInstallStatus([]StatusSubscriber{r}, NewMockSuccessLinkGenerator())
+	status.CampaignID = "rollout-1"
+
+	status.DiscoveryComplete(types.DiscoveryManifest{OS: "linux", Platform: "ubuntu"})
+	status.RecipeInstalled(RecipeStatusEvent{Recipe: types.OpenInstallationRecipe{Name: "infra"}})
+	status.RecipeFailed(RecipeStatusEvent{
+		Recipe: types.OpenInstallationRecipe{Name: "mysql"},
+		Msg:    "encountered an error while validating receipt of data for mysql: timeout",
+	})
+	status.RecipeFailed(RecipeStatusEvent{
+		Recipe: types.OpenInstallationRecipe{Name: "nginx"},
+		Msg:    "encountered an error while executing nginx: exit status 1",
+	})
+	status.RecipeSkipped(RecipeStatusEvent{Recipe: types.OpenInstallationRecipe{Name: "redis"}})
+	status.InstallComplete(nil)
+
+	require.Equal(t, 1, c.CreateEventCallCount)
+	require.Equal(t, []int{12345}, c.AccountIDs)
+
+	evt := c.Events[0].(installTelemetryEvent)
+	require.Equal(t, installTelemetryEventType, evt.EventType)
+	require.Equal(t, "partial", evt.Outcome)
+	require.Equal(t, status.CorrelationID, evt.CorrelationID)
+	require.Equal(t, "rollout-1", evt.CampaignID)
+	require.Equal(t, "linux", evt.OS)
+	require.Equal(t, "ubuntu", evt.Platform)
+	require.Equal(t, 1, evt.RecipesInstalled)
+	require.Equal(t, 2, evt.RecipesFailed)
+	require.Equal(t, 1, evt.RecipesSkipped)
+	require.Equal(t, 1, evt.FailuresExecution)
+	require.Equal(t, 1, evt.FailuresValidation)
+	require.Equal(t, 0, evt.FailuresOther)
+}
+
+func TestTelemetryStatusReporter_InstallCanceled(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+	c := NewMockEventsClient()
+	r := NewTelemetryStatusReporter(c)
+	status := NewInstallStatus([]StatusSubscriber{r}, NewMockSuccessLinkGenerator())
+
+	status.InstallCanceled()
+
+	require.Equal(t, 1, c.CreateEventCallCount)
+	require.Equal(t, "canceled", c.Events[0].(installTelemetryEvent).Outcome)
+}
+
+func TestTelemetryStatusReporter_ErrorDoesNotFailInstall(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+	c := NewMockEventsClient()
+	c.CreateEventErr = errors.New("insert key missing")
+	r := NewTelemetryStatusReporter(c)
+
+	require.NoError(t, r.InstallComplete(&InstallStatus{}))
+	require.Equal(t, 1, c.CreateEventCallCount)
+}
+
+func TestTelemetryStatusReporter_DoesNotBlock(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+	c := NewMockEventsClient()
+	c.CreateEventDelay = time.Second
+	r := NewTelemetryStatusReporter(c)
+	r.sendTimeout = 10 * time.Millisecond
+
+	start := time.Now()
+	require.NoError(t, r.InstallComplete(&InstallStatus{}))
+	require.Less(t, int64(time.Since(start)), int64(500*time.Millisecond))
+}
+
+func TestUsageDataOptedOut(t *testing.T) {
+	defer os.Unsetenv(UsageDataOptOutEnvVar)
+
+	os.Unsetenv(UsageDataOptOutEnvVar)
+	require.False(t, UsageDataOptedOut())
+
+	os.Setenv(UsageDataOptOutEnvVar, "true")
+	require.True(t, UsageDataOptedOut())
+
+	os.Setenv(UsageDataOptOutEnvVar, "0")
+	require.False(t, UsageDataOptedOut())
+}
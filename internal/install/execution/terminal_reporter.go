@@ -2,13 +2,17 @@ package execution
 
 import (
 	"fmt"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/newrelic/newrelic-cli/internal/install/types"
 )
 
-type TerminalStatusReporter struct{}
+type TerminalStatusReporter struct {
+	// Quiet limits output to failures and the final summary line.
+	Quiet bool
+}
 
 // NewTerminalStatusReporter is an implementation of the ExecutionStatusReporter interface that reports execution status to STDOUT.
 func NewTerminalStatusReporter() *TerminalStatusReporter {
@@ -26,10 +30,65 @@ func (r TerminalStatusReporter) RecipeInstalling(status *InstallStatus, event Re
 }
 
 func (r TerminalStatusReporter) RecipeInstalled(status *InstallStatus, event RecipeStatusEvent) error {
+	if r.Quiet {
+		return nil
+	}
+
+	name := event.Recipe.DisplayName
+	if name == "" {
+		name = event.Recipe.Name
+	}
+
+	if event.AlreadyInstalled {
+		fmt.Printf("  %s is already installed and reporting data, skipping.  Use --reinstall to install it again.\n", name)
+
+		return nil
+	}
+
+	if event.Reinstalled {
+		fmt.Printf("  %s was already installed and has been reinstalled.\n", name)
+	}
+
+	msg, err := event.Recipe.RenderPostInstallMessage(installMessageData(status, event))
+	if err != nil {
+		log.Warnf("Could not render the post-install message for %s: %s", event.Recipe.Name, err)
+	}
+
+	if msg != "" {
+		fmt.Println(msg)
+	}
+
 	return nil
 }
 
+func installMessageData(status *InstallStatus, event RecipeStatusEvent) types.InstallMessageData {
+	return types.InstallMessageData{
+		Manifest: status.DiscoveryManifest,
+		Vars:     event.RecipeVars,
+	}
+}
+
 func (r TerminalStatusReporter) RecipeSkipped(status *InstallStatus, event RecipeStatusEvent) error {
+	if event.Msg != "" && !r.Quiet {
+		fmt.Printf("  Skipping %s (%s)\n", event.Recipe.Name, event.Msg)
+	}
+
+	return nil
+}
+
+func (r TerminalStatusReporter) RecipeStepSkipped(status *InstallStatus, event RecipeStepEvent) error {
+	if !r.Quiet {
+		fmt.Printf("  Skipping step %s of %s (%s)\n", event.Step, event.Recipe.Name, event.Msg)
+	}
+
+	return nil
+}
+
+func (r TerminalStatusReporter) RecipeRetrying(status *InstallStatus, event RecipeRetryEvent) error {
+	if !r.Quiet {
+		fmt.Printf("  Retrying %s after exit code %d (attempt %d of %d)\n", event.Recipe.Name, event.ExitCode, event.Attempt, event.MaxAttempts)
+	}
+
 	return nil
 }
 
@@ -42,6 +101,10 @@ func (r TerminalStatusReporter) RecipesAvailable(status *InstallStatus, recipes
 }
 
 func (r TerminalStatusReporter) RecipesSelected(status *InstallStatus, recipes []types.OpenInstallationRecipe) error {
+	if r.Quiet {
+		return nil
+	}
+
 	if len(recipes) > 0 {
 		fmt.Println("The following will be installed:")
 	}
@@ -51,10 +114,15 @@ func (r TerminalStatusReporter) RecipesSelected(status *InstallStatus, recipes [
 			"name": r.Name,
 		}).Debug("found available integration")
 
+		name := r.Name
 		if r.DisplayName != "" {
-			fmt.Printf("  %s\n", r.DisplayName)
+			name = r.DisplayName
+		}
+
+		if found := status.getStatus(r); found != nil && len(found.PrerequisiteOf) > 0 {
+			fmt.Printf("  %s (required by %s)\n", name, strings.Join(found.PrerequisiteOf, ", "))
 		} else {
-			fmt.Printf("  %s\n", r.Name)
+			fmt.Printf("  %s\n", name)
 		}
 	}
 
@@ -76,6 +144,16 @@ func (r TerminalStatusReporter) InstallComplete(status *InstallStatus) error {
 		fmt.Printf("  One or more installations failed.  Check the install log for more details: %s\n", status.LogFilePath)
 	}
 
+	linkToData := ""
+	if status.successLinkGenerator != nil {
+		linkToData = status.successLinkGenerator.GenerateRedirectURL(*status)
+	}
+
+	if r.Quiet {
+		printQuietSummary(linkToData)
+		return nil
+	}
+
 	recs := status.recommendations()
 
 	if len(recs) > 0 {
@@ -91,12 +169,9 @@ func (r TerminalStatusReporter) InstallComplete(status *InstallStatus) error {
 		fmt.Println("  ---")
 	}
 
-	fmt.Println("  New Relic installation complete!")
+	printSkippedSummary(status.RecipesSkipped)
 
-	linkToData := ""
-	if status.successLinkGenerator != nil {
-		linkToData = status.successLinkGenerator.GenerateRedirectURL(*status)
-	}
+	fmt.Println("  New Relic installation complete!")
 
 	if linkToData != "" {
 		fmt.Printf("  Your data is available at %s", linkToData)
@@ -107,6 +182,43 @@ func (r TerminalStatusReporter) InstallComplete(status *InstallStatus) error {
 	return nil
 }
 
+// printSkippedSummary lists the skipped recipes with the reason each was
+// skipped for.
+func printSkippedSummary(skipped []*RecipeStatus) {
+	if len(skipped) == 0 {
+		return
+	}
+
+	fmt.Println("  ---")
+	fmt.Println("  Skipped")
+
+	for _, s := range skipped {
+		name := s.DisplayName
+		if name == "" {
+			name = s.Name
+		}
+
+		reason := s.SkipReason
+		if reason == "" {
+			reason = "no reason given"
+		}
+
+		fmt.Printf("  - %s: %s\n", name, reason)
+	}
+
+	fmt.Println("  ---")
+}
+
+// printQuietSummary prints the single summary line of a quiet install.
+func printQuietSummary(linkToData string) {
+	if linkToData != "" {
+		fmt.Printf("New Relic installation complete. Your data is available at %s\n", linkToData)
+		return
+	}
+
+	fmt.Println("New Relic installation complete.")
+}
+
 func (r TerminalStatusReporter) InstallCanceled(status *InstallStatus) error {
 	return nil
 }
@@ -1,3 +1,4 @@
+//go:build unit
 // +build unit
 
 package execution
@@ -125,3 +126,63 @@ func Test_ShouldNotGenerateExplorerLinkWhenNoRecipes(t *testing.T) {
 	require.Equal(t, 0, g.GenerateEntityLinkCallCount)
 	require.Equal(t, 0, g.GenerateExplorerLinkCallCount)
 }
+
+func Test_ShouldGenerateDashboardLink(t *testing.T) {
+	r := NewTerminalStatusReporter()
+	g := NewMockSuccessLinkGenerator()
+
+	status := &InstallStatus{
+		successLinkGenerator: g,
+	}
+	recipeStatus := &RecipeStatus{
+		Status: RecipeStatusTypes.INSTALLED,
+	}
+	status.Statuses = append(status.Statuses, recipeStatus)
+	status.successLinkConfig = types.OpenInstallationSuccessLinkConfig{
+		Type:   "dashboard",
+		Filter: "MySQL overview",
+	}
+
+	err := r.InstallComplete(status)
+	require.NoError(t, err)
+	require.Equal(t, 0, g.GenerateEntityLinkCallCount)
+	require.Equal(t, 1, g.GenerateDashboardLinkCallCount)
+}
+
+func Test_ShouldGenerateNRQLLink(t *testing.T) {
+	r := NewTerminalStatusReporter()
+	g := NewMockSuccessLinkGenerator()
+
+	status := &InstallStatus{
+		successLinkGenerator: g,
+	}
+	recipeStatus := &RecipeStatus{
+		Status: RecipeStatusTypes.INSTALLED,
+	}
+	status.Statuses = append(status.Statuses, recipeStatus)
+	status.successLinkConfig = types.OpenInstallationSuccessLinkConfig{
+		Type:   "nrql",
+		Filter: "SELECT count(*) FROM SystemSample",
+	}
+
+	err := r.InstallComplete(status)
+	require.NoError(t, err)
+	require.Equal(t, 0, g.GenerateEntityLinkCallCount)
+	require.Equal(t, 1, g.GenerateNRQLLinkCallCount)
+}
+
+func TestTerminalStatusReporter_RecipeInstalledInvalidTemplate(t *testing.T) {
+	r := NewTerminalStatusReporter()
+	status := &InstallStatus{}
+	event := RecipeStatusEvent{
+		Recipe: types.OpenInstallationRecipe{
+			Name: "test",
+			PostInstall: types.OpenInstallationPostInstallConfiguration{
+				Info: "Broken {{.Vars",
+			},
+		},
+	}
+
+	require.NoError(t, r.RecipeInstalled(status, event))
+	require.NoError(t, r.RecipeInstalling(status, event))
+}
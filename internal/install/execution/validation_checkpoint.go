@@ -0,0 +1,181 @@
+package execution
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+const validationCheckpointExt = ".json"
+
+// ValidationCheckpoint records a recipe whose steps ran but whose data could
+// not be validated, with the context needed to validate it again later.
+type ValidationCheckpoint struct {
+	Recipe         types.OpenInstallationRecipe `json:"recipe"`
+	Manifest       types.DiscoveryManifest      `json:"manifest"`
+	AccountID      int                          `json:"accountId"`
+	HostEntityGUID string                       `json:"hostEntityGuid,omitempty"`
+	CorrelationID  string                       `json:"correlationId,omitempty"`
+	CampaignID     string                       `json:"campaignId,omitempty"`
+	Msg            string                       `json:"msg,omitempty"`
+	FailedAt       time.Time                    `json:"failedAt"`
+}
+
+// ValidationCheckpointStore persists validation checkpoints, one JSON file per
+// recipe.
+type ValidationCheckpointStore struct {
+	dir string
+}
+
+// NewValidationCheckpointStore returns a new instance of
+// ValidationCheckpointStore keeping checkpoints in the given directory.
+func NewValidationCheckpointStore(dir string) *ValidationCheckpointStore {
+	s := ValidationCheckpointStore{
+		dir: dir,
+	}
+
+	return &s
+}
+
+// Save stores the checkpoint, replacing any previous one for the recipe.
+func (s *ValidationCheckpointStore) Save(cp ValidationCheckpoint) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return utils.WriteFileAtomic(s.path(cp.Recipe.Name), data, 0600)
+}
+
+// Load returns the checkpoint of the given recipe, or nil if there is none.
+func (s *ValidationCheckpointStore) Load(recipeName string) (*ValidationCheckpoint, error) {
+	data, err := ioutil.ReadFile(s.path(recipeName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cp ValidationCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("could not read validation checkpoint of %s: %s", recipeName, err)
+	}
+
+	return &cp, nil
+}
+
+// Remove deletes the checkpoint of the given recipe, if any.
+func (s *ValidationCheckpointStore) Remove(recipeName string) error {
+	err := os.Remove(s.path(recipeName))
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *ValidationCheckpointStore) path(recipeName string) string {
+	name := strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(recipeName)
+	return filepath.Join(s.dir, name+validationCheckpointExt)
+}
+
+// ValidationCheckpointReporter is an implementation of the StatusSubscriber
+// interface that saves a checkpoint for each recipe failing validation after
+// its steps ran, and removes it once the recipe is installed.
+type ValidationCheckpointReporter struct {
+	store *ValidationCheckpointStore
+}
+
+// NewValidationCheckpointReporter returns a new instance of
+// ValidationCheckpointReporter.
+func NewValidationCheckpointReporter(store *ValidationCheckpointStore) *ValidationCheckpointReporter {
+	r := ValidationCheckpointReporter{
+		store: store,
+	}
+
+	return &r
+}
+
+func (r *ValidationCheckpointReporter) RecipeFailed(status *InstallStatus, event RecipeStatusEvent) error {
+	if !event.ValidationFailed {
+		return nil
+	}
+
+	cp := ValidationCheckpoint{
+		Recipe:         event.Recipe,
+		Manifest:       status.DiscoveryManifest,
+		AccountID:      defaultAccountID(),
+		HostEntityGUID: status.HostEntityGUID(),
+		CorrelationID:  status.CorrelationID,
+		CampaignID:     status.CampaignID,
+		Msg:            event.Msg,
+		FailedAt:       time.Now(),
+	}
+
+	if err := r.store.Save(cp); err != nil {
+		log.Debugf("could not save validation checkpoint of %s: %s", event.Recipe.Name, err)
+		return nil
+	}
+
+	log.Infof("The validation of %s can be retried, without installing it again, with: newrelic install validate %s", event.Recipe.Name, event.Recipe.Name)
+
+	return nil
+}
+
+func (r *ValidationCheckpointReporter) RecipeInstalled(status *InstallStatus, event RecipeStatusEvent) error {
+	if err := r.store.Remove(event.Recipe.Name); err != nil {
+		log.Debugf("could not remove validation checkpoint of %s: %s", event.Recipe.Name, err)
+	}
+
+	return nil
+}
+
+func (r *ValidationCheckpointReporter) InstallCanceled(status *InstallStatus) error {
+	return nil
+}
+
+func (r *ValidationCheckpointReporter) InstallComplete(status *InstallStatus) error {
+	return nil
+}
+
+func (r *ValidationCheckpointReporter) DiscoveryComplete(status *InstallStatus, dm types.DiscoveryManifest) error {
+	return nil
+}
+
+func (r *ValidationCheckpointReporter) RecipeAvailable(status *InstallStatus, recipe types.OpenInstallationRecipe) error {
+	return nil
+}
+
+func (r *ValidationCheckpointReporter) RecipeInstalling(status *InstallStatus, event RecipeStatusEvent) error {
+	return nil
+}
+
+func (r *ValidationCheckpointReporter) RecipeRecommended(status *InstallStatus, event RecipeStatusEvent) error {
+	return nil
+}
+
+func (r *ValidationCheckpointReporter) RecipeSkipped(status *InstallStatus, event RecipeStatusEvent) error {
+	return nil
+}
+
+func (r *ValidationCheckpointReporter) RecipesAvailable(status *InstallStatus, recipes []types.OpenInstallationRecipe) error {
+	return nil
+}
+
+func (r *ValidationCheckpointReporter) RecipesSelected(status *InstallStatus, recipes []types.OpenInstallationRecipe) error {
+	return nil
+}
@@ -0,0 +1,82 @@
+// +build unit
+
+package execution
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/credentials"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestValidationCheckpointStore(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "newrelic")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	s := NewValidationCheckpointStore(filepath.Join(tmp, "checkpoints"))
+
+	cp, err := s.Load("mysql")
+	require.NoError(t, err)
+	require.Nil(t, cp)
+
+	err = s.Save(ValidationCheckpoint{
+		Recipe:    types.OpenInstallationRecipe{Name: "mysql", ValidationNRQL: "SELECT count(*) FROM MysqlSample"},
+		Manifest:  types.DiscoveryManifest{Hostname: "db-1"},
+		AccountID: 12345,
+	})
+	require.NoError(t, err)
+
+	cp, err = s.Load("mysql")
+	require.NoError(t, err)
+	require.Equal(t, types.NRQL("SELECT count(*) FROM MysqlSample"), cp.Recipe.ValidationNRQL)
+	require.Equal(t, "db-1", cp.Manifest.Hostname)
+	require.Equal(t, 12345, cp.AccountID)
+
+	require.NoError(t, s.Remove("mysql"))
+	require.NoError(t, s.Remove("mysql"))
+
+	cp, err = s.Load("mysql")
+	require.NoError(t, err)
+	require.Nil(t, cp)
+}
+
+func TestValidationCheckpointReporter(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+
+	tmp, err := ioutil.TempDir("", "newrelic")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	s := NewValidationCheckpointStore(tmp)
+	status := NewInstallStatus([]StatusSubscriber{NewValidationCheckpointReporter(s)}, NewMockSuccessLinkGenerator())
+	status.DiscoveryComplete(types.DiscoveryManifest{Hostname: "db-1"})
+
+	mysql := types.OpenInstallationRecipe{Name: "mysql"}
+	redis := types.OpenInstallationRecipe{Name: "redis"}
+
+	status.RecipeFailed(RecipeStatusEvent{Recipe: mysql, Msg: "timed out", ValidationFailed: true})
+	status.RecipeFailed(RecipeStatusEvent{Recipe: redis, Msg: "exit status 1"})
+
+	cp, err := s.Load("mysql")
+	require.NoError(t, err)
+	require.Equal(t, "timed out", cp.Msg)
+	require.Equal(t, "db-1", cp.Manifest.Hostname)
+	require.Equal(t, 12345, cp.AccountID)
+	require.Equal(t, status.CorrelationID, cp.CorrelationID)
+
+	cp, err = s.Load("redis")
+	require.NoError(t, err)
+	require.Nil(t, cp)
+
+	status.RecipeInstalled(RecipeStatusEvent{Recipe: mysql})
+
+	cp, err = s.Load("mysql")
+	require.NoError(t, err)
+	require.Nil(t, cp)
+}
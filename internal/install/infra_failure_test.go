@@ -0,0 +1,90 @@
+// +build unit
+
+package install
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/validation"
+)
+
+func installWithFailingInfraAgent(ic InstallerContext) (*InstallResult, error) {
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{
+		{Name: "recipe1", DisplayName: "recipe1", ValidationNRQL: "testNrql"},
+	}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+	v = validation.NewMockRecipeValidator()
+	v.ValidateErrs = []error{errors.New("no data received"), nil}
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	return i.InstallWithResult()
+}
+
+func TestInstall_InfraFailureAbortsByDefault(t *testing.T) {
+	_, err := installWithFailingInfraAgent(InstallerContext{
+		SkipLoggingInstall: true,
+		AssumeYes:          true,
+	})
+	require.Error(t, err)
+
+	reporter := statusReporters[0].(*execution.MockStatusReporter)
+	require.Equal(t, 1, reporter.ReportFailed[types.InfraAgentRecipeName])
+	require.Equal(t, 0, reporter.ReportInstalled["recipe1"])
+}
+
+func TestInstall_ContinueOnInfraFailureInstallsIntegrations(t *testing.T) {
+	result, err := installWithFailingInfraAgent(InstallerContext{
+		SkipLoggingInstall:     true,
+		AssumeYes:              true,
+		ContinueOnInfraFailure: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, InstallResultStatuses.PARTIAL, result.Status)
+
+	reporter := statusReporters[0].(*execution.MockStatusReporter)
+	require.Equal(t, 1, reporter.ReportFailed[types.InfraAgentRecipeName])
+	require.Equal(t, 1, reporter.ReportInstalled["recipe1"])
+}
+
+func TestInstall_ContinueOnInfraFailureContinuesAfterLoggingFailure(t *testing.T) {
+	// The mock fetcher returns the infra agent recipe for logging as well, and
+	// the validator fails for both.
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{
+		{Name: "recipe1", DisplayName: "recipe1", ValidationNRQL: "testNrql"},
+	}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{Name: types.InfraAgentRecipeName, DisplayName: "Infrastructure Agent", ValidationNRQL: "testNrql"},
+		{Name: types.LoggingRecipeName, DisplayName: "Logs", ValidationNRQL: "testNrql"},
+	}
+	v = validation.NewMockRecipeValidator()
+	v.ValidateErrs = []error{errors.New("no data received"), errors.New("no data received"), nil}
+
+	ic := InstallerContext{
+		AssumeYes:              true,
+		ContinueOnInfraFailure: true,
+	}
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	require.NoError(t, i.Install())
+
+	reporter := statusReporters[0].(*execution.MockStatusReporter)
+	require.Equal(t, 1, reporter.ReportFailed[types.InfraAgentRecipeName])
+	require.Equal(t, 1, reporter.ReportFailed[types.LoggingRecipeName])
+	require.Equal(t, 1, reporter.ReportInstalled["recipe1"])
+}
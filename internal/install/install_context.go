@@ -1,5 +1,12 @@
 package install
 
+import (
+	"time"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
 // nolint: maligned
 type InstallerContext struct {
 	AssumeYes   bool
@@ -12,6 +19,275 @@ type InstallerContext struct {
 	SkipLoggingInstall bool
 	SkipApm            bool
 	SkipInfra          bool
+	// VerboseRecipeSteps streams each recipe step and its output as it runs.
+	VerboseRecipeSteps bool
+	// ResetSelections clears recipe selections remembered from previous runs.
+	ResetSelections bool
+	// Reinstall executes recipes even when they are already installed and
+	// reporting, validating them again afterwards.
+	Reinstall bool
+	// RecipeVarsFiles maps a recipe name to a YAML file of variables for it.
+	RecipeVarsFiles map[string]string
+	// Plan shows the full installation plan for confirmation before any
+	// recipe is executed.
+	Plan bool
+	// SendUsageData opts in to sending a summary of the install to New Relic.
+	SendUsageData bool
+	// InfraAgentRecipeName overrides the name of the infrastructure agent
+	// recipe, e.g. to install a custom agent distribution.
+	InfraAgentRecipeName string
+	// LoggingRecipeName overrides the name of the logging recipe.
+	LoggingRecipeName string
+	// LogMaxMatches overrides the maximum number of files matched per log
+	// file pattern.
+	LogMaxMatches int
+	// LogMaxAgeDays overrides the age in days beyond which log files are
+	// not matched.
+	LogMaxAgeDays int
+	// LogExclude holds glob patterns of log files to exclude from matching.
+	LogExclude []string
+	// MaxRecipeFailures aborts the install when more than this many recipes
+	// fail.  Zero means unlimited.
+	MaxRecipeFailures int
+	// OutputDir is the directory under which a timestamped directory of run
+	// artifacts is written.  Defaults to the CLI config directory.  Only the
+	// most recent runs are kept.
+	OutputDir string
+	// IncludeTags limits recommended integrations to those with at least one
+	// of these keywords.
+	IncludeTags []string
+	// ExcludeTags skips recommended integrations with any of these keywords.
+	ExcludeTags []string
+	// PromptTimeout bounds how long interactive prompts wait for a response
+	// before answering with their default.  Zero waits indefinitely.
+	PromptTimeout time.Duration
+	// PromptTimeoutFails cancels the install when a prompt times out, instead
+	// of answering with its default.
+	PromptTimeoutFails bool
+	// SSHHosts are remote hosts, as [user@]host[:port], to install on over
+	// SSH instead of the local host.
+	SSHHosts []string
+	// SSHKeyFile is the private key used to authenticate to SSHHosts.  When
+	// empty, the SSH agent and default keys are used.
+	SSHKeyFile string
+	// RecipeSources are the ordered sources recipes are fetched from, each
+	// "service", "cache", or a local recipe directory.  Later sources are only
+	// used when earlier ones are unavailable.  Defaults to the recipe service.
+	RecipeSources []string
+	// Quiet suppresses progress and informational output, leaving warnings,
+	// errors and the final summary line.  Requires AssumeYes.
+	Quiet bool
+	// Audit records every shell command run by recipes, with its working
+	// directory and environment, to a per-recipe audit log in the run's
+	// output directory.  Sensitive values are masked.
+	Audit bool
+	// IgnorePortConflicts installs recipes whose required ports are already in
+	// use, warning instead of failing them.
+	IgnorePortConflicts bool
+	// JUnitOutput is the path of a JUnit XML report of the install, in which
+	// each recipe is a test case.
+	JUnitOutput string
+	// InstallTimeout bounds the duration of the whole install.  Once exceeded,
+	// the recipe being installed is stopped and no further recipes are
+	// started.  Zero means unlimited.
+	InstallTimeout time.Duration
+	// Shell is the shell the steps of every recipe are run with, overriding
+	// the shell named by the recipe.
+	Shell string
+	// IterativeRecommendations refreshes the recommendations after
+	// integrations are installed, offering those newly surfaced.
+	IterativeRecommendations bool
+	// PreInstallHook, PostRecipeHook and PostInstallHook, when set, are called
+	// around the install.  See PreInstallHook for the order they are called in.
+	PreInstallHook  PreInstallHook
+	PostRecipeHook  PostRecipeHook
+	PostInstallHook PostInstallHook
+	// HookErrorsFatal stops the install when a hook fails, rather than logging
+	// the failure as a warning.
+	HookErrorsFatal bool
+	// ContinueOnInfraFailure continues a guided install with the remaining
+	// recipes when the infrastructure agent fails to install, rather than
+	// aborting it.
+	ContinueOnInfraFailure bool
+	// RecipePublicKeys are the paths of the public keys recipe signatures are
+	// verified against.
+	RecipePublicKeys []string
+	// RequireSignedRecipes rejects recipes that are not signed by one of the
+	// RecipePublicKeys.
+	RequireSignedRecipes bool
+	// AnswersFile is the path of a file of scripted answers to the prompts,
+	// which are then not shown.
+	AnswersFile string
+	// IsolatedEnv runs recipes with a temporary home directory and a clean
+	// environment, so they cannot change the invoking user's dotfiles.
+	IsolatedEnv bool
+	// RecipeSourceURL is the GraphQL endpoint of the recipe service recipes
+	// are fetched from, such as an internal mirror.  Defaults to New Relic's.
+	RecipeSourceURL string
+	// RetryExitCodes are the exit codes of a recipe's install on which it is
+	// retried, up to RecipeRetries times.  Recipes may set their own.
+	RetryExitCodes []int
+	RecipeRetries  int
+	// ManifestFile is a discovery manifest saved by an earlier install, used
+	// instead of discovering the host.
+	ManifestFile string
+	// SaveManifestFile is the path the discovery manifest is saved to.
+	SaveManifestFile string
+	// HeartbeatFile is kept updated with the progress of the install, every
+	// HeartbeatInterval, so an external watcher can detect stalls.
+	HeartbeatFile     string
+	HeartbeatInterval time.Duration
+	// LogAllow and LogDeny hold glob patterns of log matches, by file pattern
+	// or name, to accept or reject without prompting.
+	LogAllow []string
+	LogDeny  []string
+	// SkipPreflight bypasses the check that the New Relic endpoints can be
+	// reached before installing.
+	SkipPreflight bool
+	// DryRun shows the commands each recipe would run, using go-task's
+	// dry-run mode, without running them or validating the recipes.
+	DryRun bool
+	// CampaignID, when set, labels every status event, NerdStorage document
+	// and telemetry record of the install with the campaign, such as a fleet
+	// rollout, and also writes a summary of the install to a NerdStorage
+	// collection shared by the installs of the campaign.
+	CampaignID string
+	// AssumeContainer and AssumeHost override whether discovery detected the
+	// installer running inside a container.
+	AssumeContainer bool
+	AssumeHost      bool
+	// SkipRecipes are the names of recipes not to install, which are reported
+	// as skipped.
+	SkipRecipes []string
+	// LogGlobConcurrency is the number of log file patterns searched for at
+	// once, or zero for the default.
+	LogGlobConcurrency int
+	// CredentialsFile is the path of a file holding the API key, account ID
+	// and region to install with.
+	CredentialsFile string
+	// SuccessLinksOutput is the path the success links of the installed
+	// recipes are written to as JSON, or "-" for standard output.
+	SuccessLinksOutput string
+	// ExportCandidates is the path the recipes a guided install would install
+	// are exported to as JSON, or "-" for standard output, in place of
+	// installing them.
+	ExportCandidates string
+	// Progress is the style the progress of each recipe is reported in:
+	// "plain", the default, or "lines".
+	Progress string
+	// RecipeMemoryLimitMB and RecipeCPULimit limit the memory, in megabytes,
+	// and the number of CPUs the processes run by each recipe may use, where
+	// the host supports it.  Zero leaves the resource unlimited.
+	RecipeMemoryLimitMB int
+	RecipeCPULimit      float64
+	// EntitlementChecker checks the account has the entitlements recipes
+	// require.  Recipes are not gated by entitlement when it is nil.
+	EntitlementChecker EntitlementChecker
+	// JSONOutput is the path each status event of the install is written to
+	// as a line of JSON, or "-" for standard output.
+	JSONOutput string
+	// Messages override the text of the guided install's introductions and
+	// prompts, and MessagesFile is the path of a YAML or JSON file of
+	// messages to override those left empty.
+	Messages     Messages
+	MessagesFile string
+	// RecipePolicy restricts the recipes the install may run, and
+	// RecipePolicyFile is the path of a YAML or JSON file of patterns to add
+	// to it.
+	RecipePolicy     RecipePolicy
+	RecipePolicyFile string
+	// Lang is the language of the install's messages, such as es, taken from
+	// the catalog of the messages available.
+	Lang string
+	// DiagnosticsOnFailure bundles the artifacts of an install that fails, with
+	// a summary of its environment, into a zip under the output directory.
+	DiagnosticsOnFailure bool
+	// CABundle is the absolute path of a PEM bundle of CA certificates trusted,
+	// in addition to the system's, by the installer's HTTP clients and the
+	// recipes it runs.
+	CABundle string
+	// ObserveValidation, when set, is how long the validation query of each
+	// recipe is watched after it installs, reporting when its data was first
+	// seen and when it became stable.
+	ObserveValidation time.Duration
+	// MetricsAddr, when set, is the address the progress of the install is
+	// served on as Prometheus metrics, until the install ends.
+	MetricsAddr string
+
+	// runDir is the directory the artifacts of the install are written to.
+	runDir string
+	// heartbeat and metrics, when set, report the progress of the install
+	// once it is started.
+	heartbeat *execution.HeartbeatStatusReporter
+	metrics   *execution.MetricsStatusReporter
+}
+
+// recipeResourceLimits returns the limits of the resources each recipe may
+// use.
+func (i *InstallerContext) recipeResourceLimits() execution.ResourceLimits {
+	return execution.ResourceLimits{
+		MemoryMB: i.RecipeMemoryLimitMB,
+		CPUs:     i.RecipeCPULimit,
+	}
+}
+
+func (i *InstallerContext) infraAgentRecipeName() string {
+	if i.InfraAgentRecipeName != "" {
+		return i.InfraAgentRecipeName
+	}
+
+	return types.InfraAgentRecipeName
+}
+
+func (i *InstallerContext) loggingRecipeName() string {
+	if i.LoggingRecipeName != "" {
+		return i.LoggingRecipeName
+	}
+
+	return types.LoggingRecipeName
+}
+
+// SkipsRecipe reports whether the named recipe is excluded with --skip.
+func (i *InstallerContext) SkipsRecipe(name string) bool {
+	for _, n := range i.SkipRecipes {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TagFiltersProvided reports whether recipes are being filtered by keyword.
+func (i *InstallerContext) TagFiltersProvided() bool {
+	return len(i.IncludeTags) > 0 || len(i.ExcludeTags) > 0
+}
+
+// MatchesTagFilters reports whether the given recipe passes the include and
+// exclude tag filters.  Exclusions take precedence over inclusions.
+func (i *InstallerContext) MatchesTagFilters(r types.OpenInstallationRecipe) bool {
+	for _, t := range i.ExcludeTags {
+		if r.HasKeyword(t) {
+			return false
+		}
+	}
+
+	if len(i.IncludeTags) == 0 {
+		return true
+	}
+
+	for _, t := range i.IncludeTags {
+		if r.HasKeyword(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RemoteInstall reports whether to install on remote hosts over SSH.
+func (i *InstallerContext) RemoteInstall() bool {
+	return len(i.SSHHosts) > 0
 }
 
 func (i *InstallerContext) ShouldRunDiscovery() bool {
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
 )
 
 func TestShouldRunDiscovery_Default(t *testing.T) {
@@ -102,3 +104,53 @@ func TestRecipesProvided(t *testing.T) {
 	ic.RecipeNames = []string{"testName"}
 	require.True(t, ic.RecipesProvided())
 }
+
+func TestRecipeNames_Default(t *testing.T) {
+	ic := InstallerContext{}
+	require.Equal(t, types.InfraAgentRecipeName, ic.infraAgentRecipeName())
+	require.Equal(t, types.LoggingRecipeName, ic.loggingRecipeName())
+}
+
+func TestRecipeNames_Override(t *testing.T) {
+	ic := InstallerContext{
+		InfraAgentRecipeName: "custom-infra",
+		LoggingRecipeName:    "custom-logging",
+	}
+	require.Equal(t, "custom-infra", ic.infraAgentRecipeName())
+	require.Equal(t, "custom-logging", ic.loggingRecipeName())
+}
+
+func TestMatchesTagFilters(t *testing.T) {
+	db := types.OpenInstallationRecipe{Keywords: []string{"Database", "mysql"}}
+	web := types.OpenInstallationRecipe{Keywords: []string{"web"}}
+	untagged := types.OpenInstallationRecipe{}
+
+	ic := InstallerContext{}
+	require.False(t, ic.TagFiltersProvided())
+	require.True(t, ic.MatchesTagFilters(db))
+	require.True(t, ic.MatchesTagFilters(untagged))
+
+	ic.IncludeTags = []string{"database"}
+	require.True(t, ic.TagFiltersProvided())
+	require.True(t, ic.MatchesTagFilters(db))
+	require.False(t, ic.MatchesTagFilters(web))
+	require.False(t, ic.MatchesTagFilters(untagged))
+
+	ic.ExcludeTags = []string{"mysql"}
+	require.False(t, ic.MatchesTagFilters(db))
+
+	ic.IncludeTags = []string{}
+	require.True(t, ic.MatchesTagFilters(web))
+	require.True(t, ic.MatchesTagFilters(untagged))
+	require.False(t, ic.MatchesTagFilters(db))
+}
+
+func TestInstallerContext_SkipsRecipe(t *testing.T) {
+	ic := InstallerContext{}
+	require.False(t, ic.SkipsRecipe("mysql"))
+
+	ic.SkipRecipes = []string{"mysql", "nginx"}
+	require.True(t, ic.SkipsRecipe("mysql"))
+	require.True(t, ic.SkipsRecipe("nginx"))
+	require.False(t, ic.SkipsRecipe("mysql-open-source-integration"))
+}
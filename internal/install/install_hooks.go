@@ -0,0 +1,98 @@
+package install
+
+import (
+	"context"
+	"errors"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// PreInstallHook is called with the discovered manifest before any recipe is
+// installed.  Install hooks run custom logic around an install, and are
+// invoked in the following order:
+//
+//  1. PreInstallHook, once discovery completes and before any recipe is
+//     installed.
+//  2. PostRecipeHook, after each attempt to install a recipe, including
+//     recipes found already installed or skipped, with the attempt's error.
+//  3. PostInstallHook, once the install completes or is canceled, with the
+//     install's error.
+//
+// A hook error is logged as a warning, unless HookErrorsFatal is set, in which
+// case the install stops and returns an ErrInstallHook.  The post-install
+// hook cannot stop a canceled install.
+type PreInstallHook func(ctx context.Context, m types.DiscoveryManifest) error
+
+// PostRecipeHook is called after each attempt to install a recipe, with the
+// error of the attempt, if any.
+type PostRecipeHook func(ctx context.Context, m types.DiscoveryManifest, r types.OpenInstallationRecipe, err error) error
+
+// PostInstallHook is called once the install completes, with the error of the
+// install, if any.
+type PostInstallHook func(ctx context.Context, m types.DiscoveryManifest, err error) error
+
+const (
+	preInstallHookName  = "pre-install"
+	postRecipeHookName  = "post-recipe"
+	postInstallHookName = "post-install"
+)
+
+func (i *RecipeInstaller) runPreInstallHook(ctx context.Context, m *types.DiscoveryManifest) error {
+	if i.PreInstallHook == nil {
+		return nil
+	}
+
+	return i.hookError(preInstallHookName, i.PreInstallHook(ctx, *m))
+}
+
+// runPostRecipeHook runs the post-recipe hook for the given install attempt,
+// returning the attempt's error unless the hook fails fatally.  An interrupt
+// is always returned.
+func (i *RecipeInstaller) runPostRecipeHook(ctx context.Context, m *types.DiscoveryManifest, r *types.OpenInstallationRecipe, err error) error {
+	if i.PostRecipeHook == nil {
+		return err
+	}
+
+	if hookErr := i.hookError(postRecipeHookName, i.PostRecipeHook(ctx, *m, *r, err)); hookErr != nil && !isCanceled(err) {
+		return hookErr
+	}
+
+	return err
+}
+
+// runPostInstallHook runs the post-install hook, returning the install's error
+// unless the hook fails fatally.
+func (i *RecipeInstaller) runPostInstallHook(ctx context.Context, err error) error {
+	if i.PostInstallHook == nil {
+		return err
+	}
+
+	if hookErr := i.hookError(postInstallHookName, i.PostInstallHook(ctx, i.status.DiscoveryManifest, err)); hookErr != nil {
+		return hookErr
+	}
+
+	return err
+}
+
+// hookError returns the error of the named hook when hook errors are fatal,
+// and otherwise logs it.
+func (i *RecipeInstaller) hookError(hook string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if i.HookErrorsFatal {
+		return NewErrInstallHook(hook, err)
+	}
+
+	log.Warnf("The %s hook failed: %s", hook, err)
+
+	return nil
+}
+
+func isInstallHookError(err error) bool {
+	var herr ErrInstallHook
+	return errors.As(err, &herr)
+}
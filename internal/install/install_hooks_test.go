@@ -0,0 +1,142 @@
+// +build unit
+
+package install
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/validation"
+)
+
+func newHooksInstaller(ic InstallerContext) *RecipeInstaller {
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{
+		{Name: "recipe1", DisplayName: "recipe1", ValidationNRQL: "testNrql"},
+		{Name: "recipe2", DisplayName: "recipe2", ValidationNRQL: "testNrql"},
+	}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+	v = validation.NewMockRecipeValidator()
+
+	return &RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+}
+
+func TestInstall_HooksInvokedInOrder(t *testing.T) {
+	calls := []string{}
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+		AssumeYes:          true,
+		PreInstallHook: func(ctx context.Context, m types.DiscoveryManifest) error {
+			calls = append(calls, "pre")
+			return nil
+		},
+		PostRecipeHook: func(ctx context.Context, m types.DiscoveryManifest, r types.OpenInstallationRecipe, err error) error {
+			calls = append(calls, "recipe:"+r.Name)
+			return nil
+		},
+		PostInstallHook: func(ctx context.Context, m types.DiscoveryManifest, err error) error {
+			calls = append(calls, "post")
+			return nil
+		},
+	}
+
+	require.NoError(t, newHooksInstaller(ic).Install())
+	require.Equal(t, []string{
+		"pre",
+		"recipe:" + types.InfraAgentRecipeName,
+		"recipe:recipe1",
+		"recipe:recipe2",
+		"post",
+	}, calls)
+}
+
+func TestInstall_HookErrorsNotFatalByDefault(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+		AssumeYes:          true,
+		PostRecipeHook: func(ctx context.Context, m types.DiscoveryManifest, r types.OpenInstallationRecipe, err error) error {
+			return errors.New("notification failed")
+		},
+	}
+
+	require.NoError(t, newHooksInstaller(ic).Install())
+
+	reporter := statusReporters[0].(*execution.MockStatusReporter)
+	require.Equal(t, 1, reporter.ReportInstalled["recipe1"])
+	require.Equal(t, 1, reporter.ReportInstalled["recipe2"])
+}
+
+func TestInstall_FatalPostRecipeHookStopsInstall(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+		AssumeYes:          true,
+		HookErrorsFatal:    true,
+		PostRecipeHook: func(ctx context.Context, m types.DiscoveryManifest, r types.OpenInstallationRecipe, err error) error {
+			if r.Name == "recipe1" {
+				return errors.New("validation failed")
+			}
+			return nil
+		},
+	}
+
+	err := newHooksInstaller(ic).Install()
+	require.Error(t, err)
+
+	var herr ErrInstallHook
+	require.True(t, errors.As(err, &herr))
+	require.Equal(t, postRecipeHookName, herr.Hook)
+
+	reporter := statusReporters[0].(*execution.MockStatusReporter)
+	require.Equal(t, 0, reporter.ReportInstalling["recipe2"])
+}
+
+func TestInstall_FatalPreInstallHookStopsInstall(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+		AssumeYes:          true,
+		HookErrorsFatal:    true,
+		PreInstallHook: func(ctx context.Context, m types.DiscoveryManifest) error {
+			return errors.New("host not allowed")
+		},
+	}
+
+	err := newHooksInstaller(ic).Install()
+	require.True(t, isInstallHookError(err))
+	require.Contains(t, err.Error(), "host not allowed")
+
+	reporter := statusReporters[0].(*execution.MockStatusReporter)
+	require.Equal(t, 0, reporter.RecipeInstallingCallCount)
+}
+
+func TestInstall_PostInstallHookReceivesError(t *testing.T) {
+	var got error
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+		AssumeYes:          true,
+		HookErrorsFatal:    true,
+		PreInstallHook: func(ctx context.Context, m types.DiscoveryManifest) error {
+			return errors.New("host not allowed")
+		},
+		PostInstallHook: func(ctx context.Context, m types.DiscoveryManifest, err error) error {
+			got = err
+			return nil
+		},
+	}
+
+	err := newHooksInstaller(ic).Install()
+	require.Error(t, err)
+	require.Equal(t, err, got)
+}
@@ -0,0 +1,138 @@
+package install
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+const planSecretMask = "********"
+
+// installPlan describes the recipes that will be installed, in the order they
+// will be executed.
+type installPlan struct {
+	manifest  *types.DiscoveryManifest
+	recipes   []types.OpenInstallationRecipe
+	varsFiles map[string]string
+}
+
+func newInstallPlan(m *types.DiscoveryManifest, recipes []types.OpenInstallationRecipe, varsFiles map[string]string) *installPlan {
+	p := installPlan{
+		manifest:  m,
+		recipes:   recipes,
+		varsFiles: varsFiles,
+	}
+
+	return &p
+}
+
+func (p *installPlan) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Installation plan for %s (%s/%s %s):\n\n", p.manifest.Hostname, p.manifest.OS, p.manifest.Platform, p.manifest.PlatformVersion)
+
+	for n, r := range p.recipes {
+		name := r.DisplayName
+		if name == "" {
+			name = r.Name
+		}
+
+		fmt.Fprintf(&b, "  %d. %s (%s)\n", n+1, name, r.Name)
+
+		if targets := p.targets(r); len(targets) > 0 {
+			fmt.Fprintf(&b, "     Targets:   %s\n", strings.Join(targets, ", "))
+		}
+
+		if path, ok := p.varsFiles[r.Name]; ok {
+			fmt.Fprintf(&b, "     Vars file: %s\n", path)
+		}
+
+		if vars := p.variables(r); len(vars) > 0 {
+			fmt.Fprintf(&b, "     Variables: %s\n", strings.Join(vars, ", "))
+		}
+	}
+
+	return b.String()
+}
+
+// targets returns the install targets of the recipe that match the discovered
+// host, falling back to the target types when none match.
+func (p *installPlan) targets(r types.OpenInstallationRecipe) []string {
+	targets := []string{}
+
+	for _, t := range r.InstallTargets {
+		if t.Os != "" && !strings.EqualFold(string(t.Os), p.manifest.OS) {
+			continue
+		}
+
+		if t.Platform != "" && !strings.EqualFold(string(t.Platform), p.manifest.Platform) {
+			continue
+		}
+
+		target := string(t.Type)
+		if t.Os != "" {
+			target = fmt.Sprintf("%s %s", target, strings.ToLower(string(t.Os)))
+		}
+
+		if t.Platform != "" {
+			target = fmt.Sprintf("%s/%s", target, strings.ToLower(string(t.Platform)))
+		}
+
+		if !utils.StringInSlice(target, targets) {
+			targets = append(targets, target)
+		}
+	}
+
+	return targets
+}
+
+// variables describes how each input variable of the recipe will be set.
+func (p *installPlan) variables(r types.OpenInstallationRecipe) []string {
+	vars := []string{}
+
+	for _, v := range r.InputVars {
+		var desc string
+
+		if value := os.Getenv(v.Name); value != "" {
+			if v.Secret {
+				value = planSecretMask
+			}
+			desc = fmt.Sprintf("%s=%s (environment)", v.Name, value)
+		} else if _, ok := p.varsFiles[r.Name]; ok {
+			desc = fmt.Sprintf("%s (vars file)", v.Name)
+		} else if v.Default != "" && !v.Secret {
+			desc = fmt.Sprintf("%s (prompted, default %s)", v.Name, v.Default)
+		} else {
+			desc = fmt.Sprintf("%s (prompted)", v.Name)
+		}
+
+		vars = append(vars, desc)
+	}
+
+	return vars
+}
+
+// confirmInstallPlan shows the install plan and asks the user to confirm it
+// before any recipe is executed.  It is a no-op unless a plan was requested,
+// and is skipped when all prompts are assumed to be accepted.
+func (i *RecipeInstaller) confirmInstallPlan(m *types.DiscoveryManifest, recipes []types.OpenInstallationRecipe) error {
+	if !i.Plan || i.AssumeYes || len(recipes) == 0 {
+		return nil
+	}
+
+	fmt.Println(newInstallPlan(m, recipes, i.RecipeVarsFiles).String())
+
+	ok, err := i.prompter.PromptYesNo(showMessage(i.messages().ConfirmInstallPlan, messageData{}))
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return NewErrUserCanceled("the installation plan was declined")
+	}
+
+	return nil
+}
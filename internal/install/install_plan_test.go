@@ -0,0 +1,91 @@
+// +build unit
+
+package install
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/ux"
+)
+
+func TestInstallPlan_String(t *testing.T) {
+	os.Setenv("TEST_PLAN_PASSWORD", "hunter2")
+	defer os.Unsetenv("TEST_PLAN_PASSWORD")
+
+	m := &types.DiscoveryManifest{
+		Hostname:        "testHost",
+		OS:              "linux",
+		Platform:        "ubuntu",
+		PlatformVersion: "20.04",
+	}
+	recipes := []types.OpenInstallationRecipe{
+		{
+			Name:        types.InfraAgentRecipeName,
+			DisplayName: "Infrastructure Agent",
+			InstallTargets: []types.OpenInstallationRecipeInstallTarget{
+				{Type: types.OpenInstallationTargetTypeTypes.HOST, Os: "LINUX", Platform: "UBUNTU"},
+				{Type: types.OpenInstallationTargetTypeTypes.HOST, Os: "WINDOWS"},
+			},
+		},
+		{
+			Name:        "mysql-open-source-integration",
+			DisplayName: "MySQL Integration",
+			InputVars: []types.OpenInstallationRecipeInputVariable{
+				{Name: "TEST_PLAN_PASSWORD", Secret: true},
+				{Name: "TEST_PLAN_PORT", Default: "3306"},
+				{Name: "TEST_PLAN_USER"},
+			},
+		},
+	}
+
+	plan := newInstallPlan(m, recipes, map[string]string{}).String()
+
+	require.Contains(t, plan, "testHost (linux/ubuntu 20.04)")
+	require.True(t, strings.Index(plan, "1. Infrastructure Agent") < strings.Index(plan, "2. MySQL Integration"))
+	require.Contains(t, plan, "Targets:   HOST linux/ubuntu\n")
+	require.Contains(t, plan, "TEST_PLAN_PASSWORD=******** (environment)")
+	require.NotContains(t, plan, "hunter2")
+	require.Contains(t, plan, "TEST_PLAN_PORT (prompted, default 3306)")
+	require.Contains(t, plan, "TEST_PLAN_USER (prompted)")
+}
+
+func TestInstallPlan_VarsFile(t *testing.T) {
+	m := &types.DiscoveryManifest{}
+	recipes := []types.OpenInstallationRecipe{{
+		Name:      "test",
+		InputVars: []types.OpenInstallationRecipeInputVariable{{Name: "TEST_PLAN_USER"}},
+	}}
+
+	plan := newInstallPlan(m, recipes, map[string]string{"test": "vars.yml"}).String()
+
+	require.Contains(t, plan, "Vars file: vars.yml")
+	require.Contains(t, plan, "TEST_PLAN_USER (vars file)")
+}
+
+func TestConfirmInstallPlan(t *testing.T) {
+	m := &types.DiscoveryManifest{}
+	recipes := []types.OpenInstallationRecipe{{Name: "test"}}
+
+	mp := ux.NewMockPrompter()
+	i := RecipeInstaller{InstallerContext: InstallerContext{}, prompter: mp}
+	require.NoError(t, i.confirmInstallPlan(m, recipes))
+	require.Equal(t, 0, mp.PromptYesNoCallCount)
+
+	i.Plan = true
+	i.AssumeYes = true
+	require.NoError(t, i.confirmInstallPlan(m, recipes))
+	require.Equal(t, 0, mp.PromptYesNoCallCount)
+
+	i.AssumeYes = false
+	mp.PromptYesNoVal = true
+	require.NoError(t, i.confirmInstallPlan(m, recipes))
+	require.Equal(t, 1, mp.PromptYesNoCallCount)
+
+	mp.PromptYesNoVal = false
+	require.ErrorIs(t, i.confirmInstallPlan(m, recipes), types.ErrInterrupt)
+}
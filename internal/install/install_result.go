@@ -0,0 +1,91 @@
+package install
+
+import (
+	"errors"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+)
+
+// InstallResultStatus is the overall outcome of an installation.
+type InstallResultStatus string
+
+var InstallResultStatuses = struct {
+	SUCCESS  InstallResultStatus
+	PARTIAL  InstallResultStatus
+	FAILED   InstallResultStatus
+	CANCELED InstallResultStatus
+}{
+	SUCCESS:  "SUCCESS",
+	PARTIAL:  "PARTIAL",
+	FAILED:   "FAILED",
+	CANCELED: "CANCELED",
+}
+
+// InstallResult summarizes the outcome of an installation.
+type InstallResult struct {
+	// Status is the overall outcome of the installation.
+	Status InstallResultStatus
+	// Recipes holds the outcome of each recipe that was considered.
+	Recipes []RecipeResult
+	// EntityGUIDs holds the GUIDs of the entities reporting data as a result of
+	// the installation.
+	EntityGUIDs []string
+	// SuccessLink is the link to the installed data, if any.
+	SuccessLink string
+	// Errors holds the non-fatal recipe failures encountered during the
+	// installation.
+	Errors []error
+	// DiagnosticsBundle is the path of the diagnostics bundle of a failed
+	// installation, when one was written.
+	DiagnosticsBundle string
+}
+
+// RecipeResult is the outcome of a single recipe.
+type RecipeResult struct {
+	Name        string
+	DisplayName string
+	Status      execution.RecipeStatusType
+	EntityGUID  string
+	Error       string
+}
+
+func newInstallResult(status *execution.InstallStatus, err error, canceled bool) *InstallResult {
+	result := InstallResult{
+		Recipes:     []RecipeResult{},
+		EntityGUIDs: status.EntityGUIDs,
+		SuccessLink: status.RedirectURL,
+		Errors:      []error{},
+	}
+
+	failed := false
+	for _, s := range status.Statuses {
+		result.Recipes = append(result.Recipes, RecipeResult{
+			Name:        s.Name,
+			DisplayName: s.DisplayName,
+			Status:      s.Status,
+			EntityGUID:  s.EntityGUID,
+			Error:       s.Error.Message,
+		})
+
+		if s.Status == execution.RecipeStatusTypes.FAILED {
+			failed = true
+
+			if s.Error.Message != "" {
+				result.Errors = append(result.Errors, errors.New(s.Error.Message))
+			}
+		}
+	}
+
+	switch {
+	case canceled:
+		result.Status = InstallResultStatuses.CANCELED
+	case err != nil:
+		result.Status = InstallResultStatuses.FAILED
+	case failed:
+		result.Status = InstallResultStatuses.PARTIAL
+	default:
+		result.Status = InstallResultStatuses.SUCCESS
+	}
+
+	return &result
+}
@@ -0,0 +1,55 @@
+package install
+
+import (
+	"context"
+	"errors"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+const installTimedOutMsg = "timed out"
+
+// withInstallTimeout returns a context that expires once the install has run
+// for --install-timeout, stopping any recipe still being executed.
+func (i *RecipeInstaller) withInstallTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if i.InstallTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, i.InstallTimeout)
+}
+
+// checkInstallTimeout ensures the install time budget is not exhausted before
+// a recipe is started.  Otherwise the recipe is reported as skipped.
+func (i *RecipeInstaller) checkInstallTimeout(ctx context.Context, r *types.OpenInstallationRecipe) error {
+	if i.InstallTimeout <= 0 || ctx.Err() != context.DeadlineExceeded {
+		return nil
+	}
+
+	i.skipTimedOutRecipes(*r)
+
+	return NewErrInstallTimeout(i.InstallTimeout)
+}
+
+// skipTimedOutRecipes reports recipes never started because the install
+// timed out as skipped.
+func (i *RecipeInstaller) skipTimedOutRecipes(recipes ...types.OpenInstallationRecipe) {
+	for _, r := range recipes {
+		log.WithFields(log.Fields{
+			"name": r.Name,
+		}).Debug("skipping recipe after install timeout")
+
+		i.status.RecipeSkipped(execution.RecipeStatusEvent{
+			Recipe: r,
+			Msg:    installTimedOutMsg,
+		})
+	}
+}
+
+func isInstallTimeout(err error) bool {
+	var terr ErrInstallTimeout
+	return errors.As(err, &terr)
+}
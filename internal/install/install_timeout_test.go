@@ -0,0 +1,91 @@
+// +build unit
+
+package install
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/validation"
+)
+
+func TestInstall_InstallTimeoutSkipsRemainingRecipes(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+		AssumeYes:          true,
+		InstallTimeout:     time.Nanosecond,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{
+		{Name: "recipe1", DisplayName: "recipe1", ValidationNRQL: "testNrql"},
+		{Name: "recipe2", DisplayName: "recipe2", ValidationNRQL: "testNrql"},
+	}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+	v = validation.NewMockRecipeValidator()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.Error(t, err)
+
+	var terr ErrInstallTimeout
+	require.True(t, errors.As(err, &terr))
+	require.Equal(t, time.Nanosecond, terr.Timeout)
+	require.Equal(t, 0, v.ValidateCallCount)
+
+	reporter := statusReporters[0].(*execution.MockStatusReporter)
+	// The infra agent is skipped along with the logging recipe, which the mock
+	// fetcher also returns as the infra agent recipe.
+	require.Equal(t, 2, reporter.ReportSkipped[types.InfraAgentRecipeName])
+	require.Equal(t, 1, reporter.ReportSkipped["recipe1"])
+	require.Equal(t, 1, reporter.ReportSkipped["recipe2"])
+	require.Equal(t, 1, reporter.InstallCompleteCallCount)
+	require.Equal(t, 0, reporter.InstallCanceledCallCount)
+}
+
+func TestInstallRecipes_InstallTimeoutExceeded(t *testing.T) {
+	ic := InstallerContext{
+		AssumeYes:      true,
+		InstallTimeout: time.Minute,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	v = validation.NewMockRecipeValidator()
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.installRecipes(ctx, &types.DiscoveryManifest{}, []types.OpenInstallationRecipe{
+		{Name: "recipe1", ValidationNRQL: "testNrql"},
+		{Name: "recipe2", ValidationNRQL: "testNrql"},
+	})
+	require.True(t, isInstallTimeout(err))
+	require.Equal(t, 0, v.ValidateCallCount)
+
+	reporter := statusReporters[0].(*execution.MockStatusReporter)
+	require.Equal(t, 2, reporter.RecipeSkippedCallCount)
+	require.Equal(t, 1, reporter.ReportSkipped["recipe1"])
+	require.Equal(t, 1, reporter.ReportSkipped["recipe2"])
+}
+
+func TestCheckInstallTimeout_NoTimeout(t *testing.T) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	i := RecipeInstaller{InstallerContext: InstallerContext{}}
+	require.NoError(t, i.checkInstallTimeout(ctx, &types.OpenInstallationRecipe{Name: "recipe1"}))
+}
@@ -0,0 +1,108 @@
+package install
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// maxRecommendationRounds bounds the number of times recommendations are
+// refreshed, in case installed integrations keep surfacing new ones.
+const maxRecommendationRounds = 3
+
+// installIterativeRecommendations refreshes the recommendations after
+// integrations are installed, offering those newly surfaced.  Recipes already
+// handled, whether installed, skipped or declined, are not offered again.
+// Refreshing stops once a round installs nothing new, surfaces nothing new, or
+// maxRecommendationRounds is reached.
+func (i *RecipeInstaller) installIterativeRecommendations(ctx context.Context, m *types.DiscoveryManifest, handled []types.OpenInstallationRecipe) error {
+	handledNames := map[string]bool{}
+	for _, r := range handled {
+		handledNames[r.Name] = true
+	}
+
+	installed := i.installedRecipeCount()
+	if installed == 0 {
+		return nil
+	}
+
+	for round := 1; round <= maxRecommendationRounds; round++ {
+		m = i.rediscover(ctx, m)
+
+		recommended, err := i.fetchRecommendations(m)
+		if err != nil {
+			log.Debugf("could not refresh recommendations: %s", err)
+			return nil
+		}
+
+		surfaced := []types.OpenInstallationRecipe{}
+		for _, r := range recommended {
+			if !handledNames[r.Name] {
+				handledNames[r.Name] = true
+				surfaced = append(surfaced, r)
+			}
+		}
+
+		log.WithFields(log.Fields{
+			"round":        round,
+			"recipe_count": len(surfaced),
+		}).Debug("refreshed recommendations")
+
+		if len(surfaced) == 0 {
+			return nil
+		}
+
+		selected, err := i.filterIntegrationsWithIntro(m, surfaced, showMessage(i.messages().MoreIntegrationsIntro, messageData{}))
+		if err != nil {
+			return err
+		}
+
+		selected, err = i.resolveConflicts(selected)
+		if err != nil {
+			return err
+		}
+
+		i.status.RecipesAvailable(selected)
+		i.status.RecipesSelected(selected)
+
+		if err = i.installIntegrations(ctx, m, selected); err != nil {
+			return err
+		}
+
+		previous := installed
+		installed = i.installedRecipeCount()
+		if installed == previous {
+			return nil
+		}
+	}
+
+	log.Debugf("stopped refreshing recommendations after %d rounds", maxRecommendationRounds)
+
+	return nil
+}
+
+// rediscover returns the host's processes as they are after the installs so
+// far, or the given manifest if discovery fails.
+func (i *RecipeInstaller) rediscover(ctx context.Context, m *types.DiscoveryManifest) *types.DiscoveryManifest {
+	refreshed, err := i.discover(ctx)
+	if err != nil {
+		log.Debugf("could not refresh discovery, using the original manifest: %s", err)
+		return m
+	}
+
+	return refreshed
+}
+
+func (i *RecipeInstaller) installedRecipeCount() int {
+	count := 0
+	for _, s := range i.status.Statuses {
+		if s.Status == execution.RecipeStatusTypes.INSTALLED {
+			count++
+		}
+	}
+
+	return count
+}
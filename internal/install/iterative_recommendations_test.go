@@ -0,0 +1,91 @@
+// +build unit
+
+package install
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/validation"
+)
+
+func newIterativeRecommendationsInstaller(ic InstallerContext, recommendations ...[]types.OpenInstallationRecipe) (*RecipeInstaller, *recipes.MockRecipeFetcher) {
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVals = recommendations
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+	v = validation.NewMockRecipeValidator()
+
+	return &RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}, f
+}
+
+func testRecommendation(name string) types.OpenInstallationRecipe {
+	return types.OpenInstallationRecipe{Name: name, DisplayName: name, ValidationNRQL: "testNrql"}
+}
+
+func TestInstall_IterativeRecommendationsOffersNewlySurfaced(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall:       true,
+		AssumeYes:                true,
+		IterativeRecommendations: true,
+	}
+	i, f := newIterativeRecommendationsInstaller(ic,
+		[]types.OpenInstallationRecipe{testRecommendation("mysql")},
+		[]types.OpenInstallationRecipe{testRecommendation("mysql"), testRecommendation("mysql-exporter")},
+	)
+
+	require.NoError(t, i.Install())
+
+	reporter := statusReporters[0].(*execution.MockStatusReporter)
+	require.Equal(t, 1, reporter.ReportInstalled["mysql"])
+	require.Equal(t, 1, reporter.ReportInstalled["mysql-exporter"])
+	// The second refresh surfaces nothing new, ending the refreshes.
+	require.Equal(t, 3, f.FetchRecommendationsCallCount)
+}
+
+func TestInstall_IterativeRecommendationsIsBounded(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall:       true,
+		AssumeYes:                true,
+		IterativeRecommendations: true,
+	}
+	rounds := [][]types.OpenInstallationRecipe{}
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		rounds = append(rounds, []types.OpenInstallationRecipe{testRecommendation(name)})
+	}
+	i, f := newIterativeRecommendationsInstaller(ic, rounds...)
+
+	require.NoError(t, i.Install())
+	require.Equal(t, 1+maxRecommendationRounds, f.FetchRecommendationsCallCount)
+
+	reporter := statusReporters[0].(*execution.MockStatusReporter)
+	require.Equal(t, 1, reporter.ReportInstalled["d"])
+	require.Equal(t, 0, reporter.ReportInstalled["e"])
+}
+
+func TestInstall_IterativeRecommendationsDisabled(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+		AssumeYes:          true,
+	}
+	i, f := newIterativeRecommendationsInstaller(ic,
+		[]types.OpenInstallationRecipe{testRecommendation("mysql")},
+		[]types.OpenInstallationRecipe{testRecommendation("mysql-exporter")},
+	)
+
+	require.NoError(t, i.Install())
+	require.Equal(t, 1, f.FetchRecommendationsCallCount)
+
+	reporter := statusReporters[0].(*execution.MockStatusReporter)
+	require.Equal(t, 0, reporter.ReportInstalled["mysql-exporter"])
+}
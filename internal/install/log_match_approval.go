@@ -0,0 +1,97 @@
+package install
+
+import (
+	"fmt"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// logMatchApproval is the decision of the log approval policy for a log match.
+type logMatchApproval int
+
+const (
+	logMatchUndecided logMatchApproval = iota
+	logMatchAllowed
+	logMatchDenied
+)
+
+// assertLogApprovalIsValid ensures the patterns of the log approval policy can
+// be evaluated.
+func assertLogApprovalIsValid(ic InstallerContext) error {
+	for _, p := range ic.LogAllow {
+		if _, err := filepath.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid --log-allow pattern %s: %s", p, err)
+		}
+	}
+
+	for _, p := range ic.LogDeny {
+		if _, err := filepath.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid --log-deny pattern %s: %s", p, err)
+		}
+	}
+
+	return nil
+}
+
+// logMatchApproval evaluates the log approval policy for the log match.  A log
+// match is denied when its file pattern, the base name of it, or its name
+// matches a --log-deny pattern, and allowed when it matches a --log-allow
+// pattern.  Denials take precedence.
+func (i *InstallerContext) logMatchApproval(match types.OpenInstallationLogMatch) logMatchApproval {
+	if logMatchMatchesAny(match, i.LogDeny) {
+		return logMatchDenied
+	}
+
+	if logMatchMatchesAny(match, i.LogAllow) {
+		return logMatchAllowed
+	}
+
+	return logMatchUndecided
+}
+
+func logMatchMatchesAny(match types.OpenInstallationLogMatch, patterns []string) bool {
+	for _, p := range patterns {
+		if matched, _ := filepath.Match(p, match.File); matched {
+			return true
+		}
+
+		if matched, _ := filepath.Match(p, filepath.Base(match.File)); matched {
+			return true
+		}
+
+		if match.Name != "" {
+			if matched, _ := filepath.Match(p, match.Name); matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// userAcceptsLogFile decides whether the files of the log match are watched.
+// Log matches the approval policy decides on are accepted or rejected without
+// prompting.  Others are prompted for, except with --assumeYes, where they are
+// accepted unless an allowlist was given, in which case only the allowed log
+// matches are accepted.
+func (i *RecipeInstaller) userAcceptsLogFile(match types.OpenInstallationLogMatch) (bool, error) {
+	switch i.logMatchApproval(match) {
+	case logMatchAllowed:
+		log.Debugf("log files at %s allowed by --log-allow", match.File)
+		return true, nil
+	case logMatchDenied:
+		log.Debugf("log files at %s denied by --log-deny", match.File)
+		return false, nil
+	}
+
+	if i.AssumeYes && len(i.LogAllow) > 0 {
+		log.Debugf("log files at %s not in --log-allow", match.File)
+		return false, nil
+	}
+
+	msg := fmt.Sprintf("Files have been found at the following pattern: %s Do you want to watch them?", match.File)
+	return i.userAccepts(msg)
+}
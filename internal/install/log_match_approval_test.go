@@ -0,0 +1,104 @@
+// +build unit
+
+package install
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/ux"
+)
+
+func TestLogMatchApproval(t *testing.T) {
+	ic := InstallerContext{
+		LogAllow: []string{"/var/log/nginx/*", "mysql"},
+		LogDeny:  []string{"*.gz", "/var/log/nginx/debug*"},
+	}
+
+	tests := map[string]struct {
+		match    types.OpenInstallationLogMatch
+		expected logMatchApproval
+	}{
+		"allowed by file":   {types.OpenInstallationLogMatch{File: "/var/log/nginx/access.log"}, logMatchAllowed},
+		"allowed by name":   {types.OpenInstallationLogMatch{Name: "mysql", File: "/var/log/mysql/*.log"}, logMatchAllowed},
+		"denied":            {types.OpenInstallationLogMatch{File: "/var/log/nginx/debug.log"}, logMatchDenied},
+		"denial wins":       {types.OpenInstallationLogMatch{Name: "mysql", File: "*.gz"}, logMatchDenied},
+		"neither":           {types.OpenInstallationLogMatch{Name: "redis", File: "/var/log/redis/*.log"}, logMatchUndecided},
+		"glob in file name": {types.OpenInstallationLogMatch{File: "/var/log/nginx/*.log"}, logMatchAllowed},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expected, ic.logMatchApproval(tc.match))
+		})
+	}
+}
+
+func TestUserAcceptsLogFile_Policy(t *testing.T) {
+	allowed := types.OpenInstallationLogMatch{File: "/var/log/nginx/*.log"}
+	denied := types.OpenInstallationLogMatch{File: "/var/log/syslog"}
+	other := types.OpenInstallationLogMatch{File: "/var/log/redis/*.log"}
+
+	p := &ux.MockPrompter{PromptYesNoVal: true}
+	i := RecipeInstaller{
+		InstallerContext: InstallerContext{
+			LogAllow: []string{"/var/log/nginx/*"},
+			LogDeny:  []string{"/var/log/syslog"},
+		},
+		prompter: p,
+	}
+
+	ok, err := i.userAcceptsLogFile(allowed)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = i.userAcceptsLogFile(denied)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, 0, p.PromptYesNoCallCount)
+
+	ok, err = i.userAcceptsLogFile(other)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 1, p.PromptYesNoCallCount)
+
+	// Non-interactively, only the allowed log matches are accepted.
+	i.AssumeYes = true
+
+	ok, err = i.userAcceptsLogFile(other)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = i.userAcceptsLogFile(allowed)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 1, p.PromptYesNoCallCount)
+}
+
+func TestUserAcceptsLogFile_AssumeYesWithoutAllowlist(t *testing.T) {
+	p := &ux.MockPrompter{}
+	i := RecipeInstaller{
+		InstallerContext: InstallerContext{
+			AssumeYes: true,
+			LogDeny:   []string{"*.gz"},
+		},
+		prompter: p,
+	}
+
+	ok, err := i.userAcceptsLogFile(types.OpenInstallationLogMatch{File: "/var/log/app/*.log"})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = i.userAcceptsLogFile(types.OpenInstallationLogMatch{File: "/var/log/app/*.gz"})
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, 0, p.PromptYesNoCallCount)
+}
+
+func TestAssertLogApprovalIsValid(t *testing.T) {
+	require.NoError(t, assertLogApprovalIsValid(InstallerContext{LogAllow: []string{"*.log"}, LogDeny: []string{"*.gz"}}))
+	require.Error(t, assertLogApprovalIsValid(InstallerContext{LogAllow: []string{"[a-"}}))
+	require.Error(t, assertLogApprovalIsValid(InstallerContext{LogDeny: []string{"[a-"}}))
+}
@@ -0,0 +1,203 @@
+package install
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"text/template"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+// Messages are the user-facing text of the install's introductions, prompts,
+// progress and summaries, so that those redistributing the CLI can reword
+// them, and so that they can be translated.  Each message is a Go template,
+// some of which are given values such as {{.Count}}.  A message left empty
+// takes its text from the catalog of the install's language.
+type Messages struct {
+	// Welcome greets the user when the install starts.
+	Welcome string `yaml:"welcome,omitempty"`
+	// InstallID shows the {{.ID}} of the install.
+	InstallID string `yaml:"installID,omitempty"`
+	// Installing labels the progress of installing the {{.Recipe}}.
+	Installing string `yaml:"installing,omitempty"`
+	// TagFilterSummary summarizes the {{.Included}} and {{.Excluded}} integrations of the tag filters.
+	TagFilterSummary string `yaml:"tagFilterSummary,omitempty"`
+	// GuidedInstallIntro introduces the integrations offered by a guided install.
+	GuidedInstallIntro string `yaml:"guidedInstallIntro,omitempty"`
+	// MoreIntegrationsIntro introduces the integrations revealed by those just installed.
+	MoreIntegrationsIntro string `yaml:"moreIntegrationsIntro,omitempty"`
+	// SelectIntegrations asks which of the recommended integrations to install.
+	SelectIntegrations string `yaml:"selectIntegrations,omitempty"`
+	// ConfirmEmptySelection asks whether to continue when no integration was selected.
+	ConfirmEmptySelection string `yaml:"confirmEmptySelection,omitempty"`
+	// SelectCategories asks which kinds of the {{.Count}} integrations recommended are of interest.
+	SelectCategories string `yaml:"selectCategories,omitempty"`
+	// ConfirmInstallPlan asks whether to proceed with the installation plan shown.
+	ConfirmInstallPlan string `yaml:"confirmInstallPlan,omitempty"`
+	// ConfirmPrerequisite asks whether to install the {{.Dependency}} the {{.Recipe}} depends on.
+	ConfirmPrerequisite string `yaml:"confirmPrerequisite,omitempty"`
+	// ConfirmMonitoringAgents asks whether to install alongside the {{.Count}} monitoring agents found.
+	ConfirmMonitoringAgents string `yaml:"confirmMonitoringAgents,omitempty"`
+	// ConfirmContainerInstall asks whether to install inside the kind of {{.Container}} found.
+	ConfirmContainerInstall string `yaml:"confirmContainerInstall,omitempty"`
+}
+
+// messageData holds the values messages are rendered with.
+type messageData struct {
+	ID         string
+	Count      int
+	Included   int
+	Excluded   int
+	Recipe     string
+	Dependency string
+	Container  string
+}
+
+// DefaultMessages returns the default text of each message, in English.
+func DefaultMessages() Messages {
+	return Messages{
+		Welcome:                 "Welcome to New Relic. Let's install some instrumentation.\n\nQuestions? Read more about our installation process at\nhttps://docs.newrelic.com/",
+		InstallID:               "Install ID: {{.ID}}",
+		Installing:              "Installing {{.Recipe}}",
+		TagFilterSummary:        "Tag filters included {{.Included}} and excluded {{.Excluded}} recommended integrations.",
+		GuidedInstallIntro:      "The guided installation will begin by installing the latest version of the New Relic Infrastructure agent, which is required for additional instrumentation.",
+		MoreIntegrationsIntro:   "The integrations installed revealed more integrations you can install.",
+		SelectIntegrations:      "Please choose from the additional recommended instrumentation to be installed:",
+		ConfirmEmptySelection:   "You selected no additional integrations. Continue with the infrastructure agent only?",
+		SelectCategories:        "{{.Count}} integrations are recommended. Which kinds of instrumentation are you interested in?",
+		ConfirmInstallPlan:      "Proceed with this installation plan?",
+		ConfirmPrerequisite:     "{{.Recipe}} depends on {{.Dependency}}, which is not selected. Install {{.Dependency}} too?",
+		ConfirmMonitoringAgents: "Continue installing the infrastructure agent alongside {{.Count}} other monitoring agent(s)?",
+		ConfirmContainerInstall: "Install the infrastructure agent inside this {{.Container}} container anyway?",
+	}
+}
+
+// LoadMessages reads the messages to override from a YAML or JSON file, in
+// which messages not given take their text from the install's language.  An error is returned
+// when a message is not a valid template.
+func LoadMessages(path string) (Messages, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Messages{}, fmt.Errorf("could not read messages file %s: %s", path, err)
+	}
+
+	m := Messages{}
+	if err := yaml.UnmarshalStrict(data, &m); err != nil {
+		return Messages{}, fmt.Errorf("could not parse messages file %s: %s", path, err)
+	}
+
+	if err := m.validate(); err != nil {
+		return Messages{}, fmt.Errorf("invalid messages file %s: %s", path, err)
+	}
+
+	return m, nil
+}
+
+// merged returns the messages, with those left empty taken from other.
+func (m Messages) merged(other Messages) Messages {
+	v := reflect.ValueOf(&m).Elem()
+	o := reflect.ValueOf(other)
+
+	for n := 0; n < v.NumField(); n++ {
+		if v.Field(n).String() == "" {
+			v.Field(n).SetString(o.Field(n).String())
+		}
+	}
+
+	return m
+}
+
+// validate ensures each message given renders.
+func (m Messages) validate() error {
+	v := reflect.ValueOf(m)
+	t := v.Type()
+
+	for n := 0; n < v.NumField(); n++ {
+		if v.Field(n).String() == "" {
+			continue
+		}
+
+		if _, err := renderMessage(v.Field(n).String(), messageData{}); err != nil {
+			name, _ := t.Field(n).Tag.Lookup("yaml")
+			return fmt.Errorf("%s: %s", name, err)
+		}
+	}
+
+	return nil
+}
+
+func renderMessage(msg string, data messageData) (string, error) {
+	tmpl, err := template.New("message").Parse(msg)
+	if err != nil {
+		return "", err
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// messages returns the messages of the installer, with those left empty taken
+// from the catalog of the install's language.
+func (i *RecipeInstaller) messages() Messages {
+	return i.Messages.merged(catalogMessages(i.Lang))
+}
+
+// showMessage renders the message to show.  A message that cannot be rendered
+// is shown as is.
+func showMessage(msg string, data messageData) string {
+	rendered, err := renderMessage(msg, data)
+	if err != nil {
+		log.Warnf("Could not render the message %q: %s", msg, err)
+		return msg
+	}
+
+	return rendered
+}
+
+// indentMessage indents each line of the message shown beneath the logo.
+func indentMessage(msg string) string {
+	lines := strings.Split(msg, "\n")
+	for n, line := range lines {
+		if line != "" {
+			lines[n] = "  " + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// loadMessages returns the messages of the context, with those it leaves empty
+// taken from the messages file, when given.  Should the file fail to load,
+// its messages take their text from the install's language.
+func loadMessages(ic InstallerContext) Messages {
+	if ic.MessagesFile == "" {
+		return ic.Messages
+	}
+
+	m, err := LoadMessages(ic.MessagesFile)
+	if err != nil {
+		log.Warnf("The messages file could not be loaded: %s", err)
+		return ic.Messages
+	}
+
+	return ic.Messages.merged(m)
+}
+
+// assertMessagesFileIsValid ensures the messages file, when given, can be
+// loaded.
+func assertMessagesFileIsValid(ic InstallerContext) error {
+	if ic.MessagesFile == "" {
+		return nil
+	}
+
+	_, err := LoadMessages(ic.MessagesFile)
+	return err
+}
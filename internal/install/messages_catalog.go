@@ -0,0 +1,99 @@
+package install
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	// LangEnvVar selects the language of the install's messages when --lang
+	// is not given.
+	LangEnvVar = "NEW_RELIC_CLI_LANG"
+
+	defaultLang = "en"
+)
+
+// localeEnvVars are the standard locale variables the language is taken from
+// when neither --lang nor LangEnvVar is set, in order of precedence.
+var localeEnvVars = []string{"LC_ALL", "LC_MESSAGES", "LANG"}
+
+// messageCatalog holds the messages of each language, keyed by language tag.
+var messageCatalog = map[string]func() Messages{
+	defaultLang: DefaultMessages,
+	"es":        spanishMessages,
+}
+
+// Langs returns the tags of the languages the install's messages are
+// available in.
+func Langs() []string {
+	langs := []string{}
+	for l := range messageCatalog {
+		langs = append(langs, l)
+	}
+	sort.Strings(langs)
+
+	return langs
+}
+
+// catalogMessages returns the messages of the given language, with those it
+// lacks in English.  The messages of an unknown language are in English.
+func catalogMessages(lang string) Messages {
+	m := DefaultMessages()
+
+	if l, ok := lookupLang(lang); ok && l != defaultLang {
+		m = messageCatalog[l]().merged(m)
+	}
+
+	return m
+}
+
+// lookupLang returns the catalog language of the given language tag or
+// locale, such as es, es-MX or es_MX.UTF-8, falling back from a regional
+// variant to its base language.
+func lookupLang(lang string) (string, bool) {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if n := strings.IndexAny(lang, ".@"); n >= 0 {
+		lang = lang[:n]
+	}
+	lang = strings.Replace(lang, "_", "-", -1)
+
+	if _, ok := messageCatalog[lang]; ok {
+		return lang, true
+	}
+
+	if n := strings.Index(lang, "-"); n > 0 {
+		if _, ok := messageCatalog[lang[:n]]; ok {
+			return lang[:n], true
+		}
+	}
+
+	return "", false
+}
+
+// resolveLang returns the catalog language of the install: the one given with
+// --lang, which must be available, or else the one of LangEnvVar or of the
+// locale, when available, or else English.
+func resolveLang(lang string) (string, error) {
+	if lang != "" {
+		l, ok := lookupLang(lang)
+		if !ok {
+			return "", fmt.Errorf("invalid --lang %s.  Valid values are %s", lang, strings.Join(Langs(), ", "))
+		}
+
+		return l, nil
+	}
+
+	for _, v := range append([]string{LangEnvVar}, localeEnvVars...) {
+		if value := os.Getenv(v); value != "" {
+			if l, ok := lookupLang(value); ok {
+				return l, nil
+			}
+
+			return defaultLang, nil
+		}
+	}
+
+	return defaultLang, nil
+}
@@ -0,0 +1,123 @@
+// +build unit
+
+package install
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/ux"
+)
+
+func TestLookupLang(t *testing.T) {
+	for lang, expected := range map[string]string{
+		"en":          "en",
+		"es":          "es",
+		"ES":          "es",
+		"es-MX":       "es",
+		"es_MX.UTF-8": "es",
+		"en_US@euro":  "en",
+	} {
+		l, ok := lookupLang(lang)
+		require.True(t, ok, lang)
+		require.Equal(t, expected, l, lang)
+	}
+
+	for _, lang := range []string{"", "fr", "C", "POSIX", "-es"} {
+		_, ok := lookupLang(lang)
+		require.False(t, ok, lang)
+	}
+}
+
+func TestResolveLang(t *testing.T) {
+	vars := append([]string{LangEnvVar}, localeEnvVars...)
+	for _, v := range vars {
+		value, ok := os.LookupEnv(v)
+		os.Unsetenv(v)
+		if ok {
+			defer os.Setenv(v, value)
+		}
+	}
+	for _, v := range vars {
+		defer os.Unsetenv(v)
+	}
+
+	lang, err := resolveLang("")
+	require.NoError(t, err)
+	require.Equal(t, "en", lang)
+
+	os.Setenv("LANG", "es_ES.UTF-8")
+	lang, err = resolveLang("")
+	require.NoError(t, err)
+	require.Equal(t, "es", lang)
+
+	os.Setenv(LangEnvVar, "fr")
+	lang, err = resolveLang("")
+	require.NoError(t, err)
+	require.Equal(t, "en", lang)
+
+	lang, err = resolveLang("es-AR")
+	require.NoError(t, err)
+	require.Equal(t, "es", lang)
+
+	_, err = resolveLang("fr")
+	require.Error(t, err)
+}
+
+func TestCatalogMessages(t *testing.T) {
+	require.Equal(t, DefaultMessages(), catalogMessages(""))
+	require.Equal(t, DefaultMessages(), catalogMessages("fr"))
+
+	m := catalogMessages("es")
+	require.Equal(t, "Instalando mysql", showMessage(m.Installing, messageData{Recipe: "mysql"}))
+	require.NoError(t, m.validate())
+
+	for _, l := range Langs() {
+		require.NoError(t, messageCatalog[l]().validate(), l)
+	}
+}
+
+func TestCatalogMessages_MissingFallsBackToEnglish(t *testing.T) {
+	messageCatalog["xx"] = func() Messages {
+		return Messages{Installing: "Xx {{.Recipe}}"}
+	}
+	defer delete(messageCatalog, "xx")
+
+	m := catalogMessages("xx")
+	require.Equal(t, "Xx {{.Recipe}}", m.Installing)
+	require.Equal(t, DefaultMessages().SelectIntegrations, m.SelectIntegrations)
+}
+
+func TestInstall_UsesLangMessages(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+		Plan:               true,
+		Lang:               "es",
+		Messages: Messages{
+			ConfirmInstallPlan: "Go ahead with this plan?",
+		},
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{Name: "mysql"}}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{Name: types.InfraAgentRecipeName},
+		{Name: types.LoggingRecipeName},
+	}
+
+	mp := ux.NewMockPrompter()
+	mp.PromptMultiSelectAll = true
+	mp.PromptYesNoVal = false
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, mp, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.ErrorIs(t, err, types.ErrInterrupt)
+	require.Equal(t, spanishMessages().SelectIntegrations, mp.PromptMultiSelectMsg)
+	require.Equal(t, "Go ahead with this plan?", mp.PromptYesNoMsg)
+}
@@ -0,0 +1,20 @@
+package install
+
+// spanishMessages returns the text of each message in Spanish.
+func spanishMessages() Messages {
+	return Messages{
+		Welcome:                 "Bienvenido a New Relic. Instalemos algo de instrumentación.\n\n¿Preguntas? Obtenga más información sobre nuestro proceso de instalación en\nhttps://docs.newrelic.com/",
+		InstallID:               "ID de instalación: {{.ID}}",
+		Installing:              "Instalando {{.Recipe}}",
+		TagFilterSummary:        "Los filtros de etiquetas incluyeron {{.Included}} y excluyeron {{.Excluded}} integraciones recomendadas.",
+		GuidedInstallIntro:      "La instalación guiada comenzará instalando la última versión del agente de New Relic Infrastructure, necesario para la instrumentación adicional.",
+		MoreIntegrationsIntro:   "Las integraciones instaladas revelaron más integraciones que puede instalar.",
+		SelectIntegrations:      "Elija la instrumentación recomendada adicional que desea instalar:",
+		ConfirmEmptySelection:   "No seleccionó ninguna integración adicional. ¿Continuar solo con el agente de infraestructura?",
+		SelectCategories:        "Se recomiendan {{.Count}} integraciones. ¿Qué tipos de instrumentación le interesan?",
+		ConfirmInstallPlan:      "¿Continuar con este plan de instalación?",
+		ConfirmPrerequisite:     "{{.Recipe}} depende de {{.Dependency}}, que no está seleccionado. ¿Instalar también {{.Dependency}}?",
+		ConfirmMonitoringAgents: "¿Continuar instalando el agente de infraestructura junto con {{.Count}} agente(s) de monitoreo más?",
+		ConfirmContainerInstall: "¿Instalar el agente de infraestructura dentro de este contenedor {{.Container}} de todos modos?",
+	}
+}
@@ -0,0 +1,101 @@
+// +build unit
+
+package install
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/ux"
+)
+
+func writeMessagesFile(t *testing.T, dir string, content string) string {
+	path := filepath.Join(dir, "messages.yml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+
+	return path
+}
+
+func TestLoadMessages(t *testing.T) {
+	dir, err := ioutil.TempDir("", "messages")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := writeMessagesFile(t, dir, `
+selectIntegrations: "Choisissez les intégrations à installer :"
+selectCategories: "{{.Count}} intégrations sont recommandées."
+`)
+
+	m, err := LoadMessages(path)
+	require.NoError(t, err)
+	require.Equal(t, "Choisissez les intégrations à installer :", m.SelectIntegrations)
+	require.Empty(t, m.ConfirmInstallPlan)
+
+	merged := m.merged(DefaultMessages())
+	require.Equal(t, "Choisissez les intégrations à installer :", merged.SelectIntegrations)
+	require.Equal(t, DefaultMessages().ConfirmInstallPlan, merged.ConfirmInstallPlan)
+	require.Equal(t, "3 intégrations sont recommandées.", showMessage(merged.SelectCategories, messageData{Count: 3}))
+}
+
+func TestLoadMessages_Invalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "messages")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, content := range []string{
+		"selectIntegrations: [not, a, string]",
+		"unknownMessage: Hello",
+		"confirmInstallPlan: \"Proceed {{.Unknown}}?\"",
+		"confirmInstallPlan: \"Proceed {{.Count\"",
+	} {
+		_, err := LoadMessages(writeMessagesFile(t, dir, content))
+		require.Error(t, err, content)
+	}
+
+	_, err = LoadMessages(filepath.Join(dir, "missing.yml"))
+	require.Error(t, err)
+}
+
+func TestDefaultMessages_Render(t *testing.T) {
+	m := DefaultMessages()
+
+	require.Equal(t, "mysql depends on infra, which is not selected. Install infra too?", showMessage(m.ConfirmPrerequisite, messageData{Recipe: "mysql", Dependency: "infra"}))
+	require.Equal(t, "Install the infrastructure agent inside this docker container anyway?", showMessage(m.ConfirmContainerInstall, messageData{Container: "docker"}))
+	require.NoError(t, m.validate())
+}
+
+func TestInstall_UsesOverriddenMessages(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+		Plan:               true,
+		Messages: Messages{
+			SelectIntegrations: "Pick your integrations:",
+			ConfirmInstallPlan: "Go ahead with this plan?",
+		},
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{Name: "mysql"}}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{Name: types.InfraAgentRecipeName},
+		{Name: types.LoggingRecipeName},
+	}
+
+	mp := ux.NewMockPrompter()
+	mp.PromptMultiSelectAll = true
+	mp.PromptYesNoVal = false
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, mp, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.ErrorIs(t, err, types.ErrInterrupt)
+	require.Equal(t, "Pick your integrations:", mp.PromptMultiSelectMsg)
+	require.Equal(t, "Go ahead with this plan?", mp.PromptYesNoMsg)
+}
@@ -0,0 +1,31 @@
+package install
+
+import (
+	"context"
+)
+
+type MockEntitlementChecker struct {
+	Entitlements   []string
+	Err            error
+	CheckCallCount int
+}
+
+func NewMockEntitlementChecker() *MockEntitlementChecker {
+	return &MockEntitlementChecker{}
+}
+
+func (c *MockEntitlementChecker) HasEntitlement(ctx context.Context, name string) (bool, error) {
+	c.CheckCallCount++
+
+	if c.Err != nil {
+		return false, c.Err
+	}
+
+	for _, e := range c.Entitlements {
+		if e == name {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,50 @@
+package install
+
+type MockSelectionStore struct {
+	Selections     map[string]RecipeSelection
+	LoadErr        error
+	SaveErr        error
+	LoadCallCount  int
+	SaveCallCount  int
+	ResetCallCount int
+}
+
+func NewMockSelectionStore() *MockSelectionStore {
+	return &MockSelectionStore{
+		Selections: map[string]RecipeSelection{},
+	}
+}
+
+func (s *MockSelectionStore) Load(fingerprint string) (*RecipeSelection, error) {
+	s.LoadCallCount++
+
+	if s.LoadErr != nil {
+		return nil, s.LoadErr
+	}
+
+	selection, ok := s.Selections[fingerprint]
+	if !ok {
+		return nil, nil
+	}
+
+	return &selection, nil
+}
+
+func (s *MockSelectionStore) Save(fingerprint string, selection RecipeSelection) error {
+	s.SaveCallCount++
+
+	if s.SaveErr != nil {
+		return s.SaveErr
+	}
+
+	s.Selections[fingerprint] = selection
+
+	return nil
+}
+
+func (s *MockSelectionStore) Reset() error {
+	s.ResetCallCount++
+	s.Selections = map[string]RecipeSelection{}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package install
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// confirmMonitoringAgents notifies the user of the monitoring agents of other
+// vendors found running during discovery, whose data may overlap with that of
+// the infrastructure agent, and asks whether to continue unless --assumeYes
+// is set.
+func (i *RecipeInstaller) confirmMonitoringAgents(m *types.DiscoveryManifest) error {
+	if len(m.MonitoringAgents) == 0 {
+		return nil
+	}
+
+	log.Warn("Other monitoring agents are running on this host, which may collect the same data as the infrastructure agent:")
+	for _, a := range m.MonitoringAgents {
+		log.Warnf("  %s (%s, pid %d)", a.Name, a.Command, a.PID)
+	}
+
+	if i.AssumeYes {
+		return nil
+	}
+
+	ok, err := i.prompter.PromptYesNo(showMessage(i.messages().ConfirmMonitoringAgents, messageData{Count: len(m.MonitoringAgents)}))
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return NewErrUserCanceled("installing alongside other monitoring agents was declined")
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+// +build unit
+
+package install
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/ux"
+)
+
+func TestConfirmMonitoringAgents(t *testing.T) {
+	mp := ux.NewMockPrompter()
+	i := RecipeInstaller{InstallerContext: InstallerContext{}, prompter: mp}
+
+	require.NoError(t, i.confirmMonitoringAgents(&types.DiscoveryManifest{}))
+	require.Equal(t, 0, mp.PromptYesNoCallCount)
+
+	m := &types.DiscoveryManifest{
+		MonitoringAgents: []types.MonitoringAgent{{Name: "Datadog Agent", Vendor: "Datadog", Command: "datadog-agent", PID: 1234}},
+	}
+
+	mp.PromptYesNoVal = true
+	require.NoError(t, i.confirmMonitoringAgents(m))
+	require.Equal(t, 1, mp.PromptYesNoCallCount)
+
+	mp.PromptYesNoVal = false
+	require.ErrorIs(t, i.confirmMonitoringAgents(m), types.ErrInterrupt)
+
+	i.AssumeYes = true
+	require.NoError(t, i.confirmMonitoringAgents(m))
+	require.Equal(t, 2, mp.PromptYesNoCallCount)
+}
@@ -0,0 +1,196 @@
+package install
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/credentials"
+	"github.com/newrelic/newrelic-client-go/pkg/region"
+)
+
+// preflightTimeout bounds the connectivity check of each endpoint.
+const preflightTimeout = 10 * time.Second
+
+// downloadURL is where recipes download New Relic agents and integrations
+// from, in every region.
+const downloadURL = "https://download.newrelic.com"
+
+// The kinds of failure to reach an endpoint.
+const (
+	networkFailureDNS        = "DNS"
+	networkFailureTLS        = "TLS"
+	networkFailureProxy      = "proxy"
+	networkFailureTimeout    = "timeout"
+	networkFailureConnection = "connection"
+)
+
+// preflightEndpoint is a New Relic endpoint the install needs to reach.
+type preflightEndpoint struct {
+	Name string
+	URL  string
+}
+
+// preflightEndpoints returns the endpoints of the profile's region the install
+// and the agents it installs need to reach.  The US region is assumed when the
+// profile has none.
+func preflightEndpoints(profile *credentials.Profile) ([]preflightEndpoint, error) {
+	name := region.Default
+	if profile != nil && profile.Region != "" {
+		var err error
+		if name, err = region.Parse(profile.Region); err != nil {
+			return nil, err
+		}
+	}
+
+	r, err := region.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return []preflightEndpoint{
+		{Name: "NerdGraph API", URL: r.NerdGraphURL()},
+		{Name: "Infrastructure API", URL: r.InfrastructureURL()},
+		{Name: "Log API", URL: r.LogsURL()},
+		{Name: "Downloads", URL: downloadURL},
+	}, nil
+}
+
+// checkNetworkConnectivity ensures the host can reach the New Relic endpoints
+// the install needs, through the proxy configured in the environment, before
+// anything is installed.  Remote installs are not checked, as the endpoints
+// must be reachable from the remote hosts rather than from this one.
+func checkNetworkConnectivity(ctx context.Context, ic InstallerContext, profile *credentials.Profile) error {
+	if ic.SkipPreflight || ic.RemoteInstall() {
+		return nil
+	}
+
+	endpoints, err := preflightEndpoints(profile)
+	if err != nil {
+		return err
+	}
+
+	client := newPreflightClient()
+	if ic.CABundle != "" {
+		t, err := newHTTPTransport(ic.CABundle)
+		if err != nil {
+			return err
+		}
+		client.Transport = t
+	}
+
+	return checkEndpoints(ctx, client, endpoints)
+}
+
+// newPreflightClient returns an HTTP client connecting through the proxy
+// configured by HTTPS_PROXY, HTTP_PROXY and NO_PROXY.
+func newPreflightClient() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+		},
+		Timeout: preflightTimeout,
+		// Redirects are not followed, since any response shows the
+		// endpoint is reachable.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// checkEndpoints requests each endpoint concurrently, returning the endpoints
+// that could not be reached as an ErrEndpointsUnreachable.  Any response,
+// whatever its status, shows an endpoint is reachable.
+func checkEndpoints(ctx context.Context, client *http.Client, endpoints []preflightEndpoint) error {
+	failures := make([]*ErrEndpointUnreachable, len(endpoints))
+
+	var wg sync.WaitGroup
+	for n, e := range endpoints {
+		wg.Add(1)
+		go func(n int, e preflightEndpoint) {
+			defer wg.Done()
+
+			if err := checkEndpoint(ctx, client, e); err != nil {
+				failures[n] = err
+			}
+		}(n, e)
+	}
+	wg.Wait()
+
+	unreachable := []ErrEndpointUnreachable{}
+	for _, f := range failures {
+		if f != nil {
+			unreachable = append(unreachable, *f)
+		}
+	}
+
+	if len(unreachable) > 0 {
+		return NewErrEndpointsUnreachable(unreachable)
+	}
+
+	return nil
+}
+
+func checkEndpoint(ctx context.Context, client *http.Client, e preflightEndpoint) *ErrEndpointUnreachable {
+	log.WithFields(log.Fields{
+		"url": e.URL,
+	}).Debug("checking endpoint connectivity")
+
+	req, err := http.NewRequest(http.MethodHead, e.URL, nil)
+	if err != nil {
+		return &ErrEndpointUnreachable{Name: e.Name, URL: e.URL, Kind: networkFailureConnection, Err: err}
+	}
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return &ErrEndpointUnreachable{Name: e.Name, URL: e.URL, Kind: classifyNetworkError(err), Err: err}
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// classifyNetworkError returns the kind of failure a request failed with.
+func classifyNetworkError(err error) string {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "proxyconnect" {
+		return networkFailureProxy
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return networkFailureDNS
+	}
+
+	if isTLSError(err) {
+		return networkFailureTLS
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return networkFailureTimeout
+	}
+
+	return networkFailureConnection
+}
+
+func isTLSError(err error) bool {
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	var systemRoots x509.SystemRootsError
+	var recordHeader tls.RecordHeaderError
+
+	return errors.As(err, &unknownAuthority) ||
+		errors.As(err, &hostname) ||
+		errors.As(err, &invalid) ||
+		errors.As(err, &systemRoots) ||
+		errors.As(err, &recordHeader)
+}
@@ -0,0 +1,95 @@
+// +build unit
+
+package install
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/credentials"
+)
+
+func TestPreflightEndpoints_Region(t *testing.T) {
+	endpoints, err := preflightEndpoints(nil)
+	require.NoError(t, err)
+	require.Equal(t, "https://api.newrelic.com/graphql", endpoints[0].URL)
+
+	endpoints, err = preflightEndpoints(&credentials.Profile{Region: "EU"})
+	require.NoError(t, err)
+	require.Equal(t, "https://api.eu.newrelic.com/graphql", endpoints[0].URL)
+	require.Equal(t, "https://log-api.eu.newrelic.com/log/v1", endpoints[2].URL)
+
+	_, err = preflightEndpoints(&credentials.Profile{Region: "mars"})
+	require.Error(t, err)
+}
+
+func TestCheckNetworkConnectivity_Skipped(t *testing.T) {
+	err := checkNetworkConnectivity(context.Background(), InstallerContext{SkipPreflight: true}, &credentials.Profile{Region: "mars"})
+	require.NoError(t, err)
+
+	err = checkNetworkConnectivity(context.Background(), InstallerContext{SSHHosts: []string{"host"}}, &credentials.Profile{Region: "mars"})
+	require.NoError(t, err)
+}
+
+func TestCheckEndpoints_Reachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	err := checkEndpoints(context.Background(), newPreflightClient(), []preflightEndpoint{{Name: "API", URL: server.URL}})
+	require.NoError(t, err)
+}
+
+func TestCheckEndpoints_ClassifiesFailures(t *testing.T) {
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer tlsServer.Close()
+
+	endpoints := []preflightEndpoint{
+		{Name: "Untrusted", URL: tlsServer.URL},
+		{Name: "Unresolvable", URL: "https://nr-preflight.invalid"},
+		{Name: "Refused", URL: "http://" + closedAddress(t)},
+	}
+
+	err := checkEndpoints(context.Background(), newPreflightClient(), endpoints)
+
+	var uerr ErrEndpointsUnreachable
+	require.True(t, errors.As(err, &uerr))
+	require.Len(t, uerr.Endpoints, 3)
+	require.Equal(t, networkFailureTLS, uerr.Endpoints[0].Kind)
+	require.Equal(t, networkFailureDNS, uerr.Endpoints[1].Kind)
+	require.Equal(t, networkFailureConnection, uerr.Endpoints[2].Kind)
+	require.Contains(t, err.Error(), "Unresolvable (https://nr-preflight.invalid): DNS failure")
+	require.Contains(t, err.Error(), "--skip-preflight")
+}
+
+func TestCheckEndpoints_Proxy(t *testing.T) {
+	proxyURL, err := url.Parse("http://" + closedAddress(t))
+	require.NoError(t, err)
+
+	client := newPreflightClient()
+	client.Transport = &http.Transport{Proxy: http.ProxyURL(proxyURL)}
+
+	err = checkEndpoints(context.Background(), client, []preflightEndpoint{{Name: "API", URL: "https://api.newrelic.com/graphql"}})
+
+	var uerr ErrEndpointsUnreachable
+	require.True(t, errors.As(err, &uerr))
+	require.Equal(t, networkFailureProxy, uerr.Endpoints[0].Kind)
+}
+
+// closedAddress returns a local address nothing listens on.
+func closedAddress(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	return addr
+}
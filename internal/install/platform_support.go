@@ -0,0 +1,54 @@
+package install
+
+import (
+	"context"
+	"errors"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// unsupportedPlatformExitCode is the exit code of an install refused because
+// the host's platform is not supported, so that scripts can tell it apart from
+// an install that failed.
+const unsupportedPlatformExitCode = 3
+
+// fetchInfraAgentRecipe fetches the infrastructure agent recipe and marks it as
+// available.  Every other recipe builds on the infrastructure agent, so when
+// none is available for the host's platform, the platform is not supported and
+// an ErrUnsupportedPlatform is returned before anything is installed.
+func (i *RecipeInstaller) fetchInfraAgentRecipe(ctx context.Context, m *types.DiscoveryManifest) (*types.OpenInstallationRecipe, error) {
+	r, err := fetchPlatformInfraAgentRecipe(ctx, i.recipeFetcher, m, i.infraAgentRecipeName())
+	if err != nil {
+		return nil, err
+	}
+
+	i.status.RecipeAvailable(*r)
+
+	return r, nil
+}
+
+// fetchPlatformInfraAgentRecipe fetches the named infrastructure agent recipe,
+// failing with an ErrUnsupportedPlatform when there is none for the host's
+// platform.
+func fetchPlatformInfraAgentRecipe(ctx context.Context, f recipes.RecipeFetcher, m *types.DiscoveryManifest, recipeName string) (*types.OpenInstallationRecipe, error) {
+	r, err := fetchRecipe(ctx, f, m, recipeName)
+	if err != nil {
+		if !isCanceled(err) && errors.Is(err, recipes.ErrRecipeNotFound) {
+			log.WithFields(log.Fields{
+				"os":               m.OS,
+				"platform":         m.Platform,
+				"platform_version": m.PlatformVersion,
+				"kernel_arch":      m.KernelArch,
+			}).Debug("no infrastructure agent recipe for platform")
+
+			return nil, NewErrUnsupportedPlatform(*m, err)
+		}
+
+		return nil, err
+	}
+
+	return r, nil
+}
@@ -0,0 +1,138 @@
+package install
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// categoryPromptMinCandidates is the number of recommended integrations from
+// which the user is first asked which categories to choose from.
+const categoryPromptMinCandidates = 8
+
+// otherCategory holds the integrations that fit no known category.
+const otherCategory = "Other"
+
+// recipeCategory groups integrations by the keywords of their recipes.
+type recipeCategory struct {
+	name     string
+	keywords []string
+}
+
+// recipeCategories are the categories integrations are offered in, in the
+// order they are listed.  An integration may fall into several.
+var recipeCategories = []recipeCategory{
+	{name: "Databases", keywords: []string{"database", "databases", "db", "mysql", "mariadb", "postgres", "postgresql", "mongodb", "mssql", "oracle", "cassandra", "couchbase", "elasticsearch", "redis"}},
+	{name: "Web servers", keywords: []string{"web server", "webserver", "web", "http", "apache", "nginx", "haproxy", "iis", "tomcat"}},
+	{name: "Messaging", keywords: []string{"messaging", "queue", "message queue", "kafka", "rabbitmq", "activemq", "nats"}},
+	{name: "Caching", keywords: []string{"cache", "caching", "memcached", "varnish"}},
+	{name: "Containers", keywords: []string{"container", "containers", "docker", "kubernetes", "k8s"}},
+	{name: "Application monitoring (APM)", keywords: []string{"apm"}},
+	{name: "Logs", keywords: []string{"logs", "logging", "log"}},
+}
+
+// recipeCategoryNames returns the names of the categories the recipe falls
+// into, or the other category when none.
+func recipeCategoryNames(r types.OpenInstallationRecipe) []string {
+	names := []string{}
+	for _, c := range recipeCategories {
+		for _, k := range c.keywords {
+			if r.HasKeyword(k) {
+				names = append(names, c.name)
+				break
+			}
+		}
+	}
+
+	if len(names) == 0 {
+		names = append(names, otherCategory)
+	}
+
+	return names
+}
+
+// categorizeRecipes returns the names of the categories the recipes fall
+// into, in listing order, along with the number of recipes in each.
+func categorizeRecipes(recipes []types.OpenInstallationRecipe) ([]string, map[string]int) {
+	counts := map[string]int{}
+	for _, r := range recipes {
+		for _, n := range recipeCategoryNames(r) {
+			counts[n]++
+		}
+	}
+
+	names := []string{}
+	for _, c := range recipeCategories {
+		if counts[c.name] > 0 {
+			names = append(names, c.name)
+		}
+	}
+	if counts[otherCategory] > 0 {
+		names = append(names, otherCategory)
+	}
+
+	return names, counts
+}
+
+// categoryOptionName returns the name under which a category is offered for
+// selection, along with the number of integrations in it.
+func categoryOptionName(name string, count int) string {
+	return fmt.Sprintf("%s (%d)", name, count)
+}
+
+// selectCategories narrows a large set of candidate integrations down to
+// those in the categories the user chooses, so they are then offered fewer
+// integrations at once.  Required integrations are always offered.  Every
+// candidate is offered when there are few, when they fall into a single
+// category, or when no category is chosen.
+func (i *RecipeInstaller) selectCategories(candidates []types.OpenInstallationRecipe) ([]types.OpenInstallationRecipe, error) {
+	if len(candidates) < categoryPromptMinCandidates {
+		return candidates, nil
+	}
+
+	names, counts := categorizeRecipes(candidates)
+	if len(names) < 2 {
+		return candidates, nil
+	}
+
+	options := []string{}
+	for _, n := range names {
+		options = append(options, categoryOptionName(n, counts[n]))
+	}
+
+	selected, err := i.prompter.MultiSelect(showMessage(i.messages().SelectCategories, messageData{Count: len(candidates)}), options, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(selected) == 0 {
+		return candidates, nil
+	}
+
+	chosen := map[string]bool{}
+	for _, n := range names {
+		for _, s := range selected {
+			if strings.EqualFold(s, categoryOptionName(n, counts[n])) {
+				chosen[n] = true
+			}
+		}
+	}
+
+	offered := []types.OpenInstallationRecipe{}
+	for _, r := range candidates {
+		if r.IsRequired() {
+			offered = append(offered, r)
+			continue
+		}
+
+		for _, n := range recipeCategoryNames(r) {
+			if chosen[n] {
+				offered = append(offered, r)
+				break
+			}
+		}
+	}
+
+	return offered, nil
+}
@@ -0,0 +1,108 @@
+// +build unit
+
+package install
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/ux"
+	"github.com/newrelic/newrelic-cli/internal/install/validation"
+)
+
+func categorizedRecipes() []types.OpenInstallationRecipe {
+	recipes := []types.OpenInstallationRecipe{}
+	for n, keyword := range []string{"MySQL", "postgres", "redis", "nginx", "apache", "kafka", "rabbitmq", "memcached", "docker"} {
+		recipes = append(recipes, types.OpenInstallationRecipe{
+			Name:           fmt.Sprintf("recipe-%d", n),
+			DisplayName:    keyword,
+			Keywords:       []string{keyword},
+			ValidationNRQL: "testNrql",
+		})
+	}
+
+	return append(recipes, types.OpenInstallationRecipe{
+		Name:           "custom",
+		DisplayName:    "custom",
+		ValidationNRQL: "testNrql",
+	})
+}
+
+func TestCategorizeRecipes(t *testing.T) {
+	names, counts := categorizeRecipes(categorizedRecipes())
+	require.Equal(t, []string{"Databases", "Web servers", "Messaging", "Caching", "Containers", "Other"}, names)
+	require.Equal(t, 3, counts["Databases"])
+	require.Equal(t, 2, counts["Web servers"])
+	require.Equal(t, 1, counts["Other"])
+
+	require.Equal(t, []string{"Databases", "Logs"}, recipeCategoryNames(types.OpenInstallationRecipe{Keywords: []string{"mysql", "logging"}}))
+}
+
+func TestSelectCategories(t *testing.T) {
+	candidates := categorizedRecipes()
+	candidates[8].Requirement = types.OpenInstallationRequirementTypes.REQUIRED
+
+	mp := ux.NewMockPrompter()
+	i := RecipeInstaller{prompter: mp}
+
+	// Few candidates are offered at once.
+	offered, err := i.selectCategories(candidates[:3])
+	require.NoError(t, err)
+	require.Len(t, offered, 3)
+	require.Equal(t, 0, mp.PromptMultiSelectCallCount)
+
+	mp.PromptMultiSelectVal = []string{"Databases (3)", "Messaging (2)"}
+	offered, err = i.selectCategories(candidates)
+	require.NoError(t, err)
+	require.Equal(t, 1, mp.PromptMultiSelectCallCount)
+	require.Contains(t, mp.PromptMultiSelectOptions, "Other (1)")
+
+	names := []string{}
+	for _, r := range offered {
+		names = append(names, r.DisplayName)
+	}
+	require.Equal(t, []string{"MySQL", "postgres", "redis", "kafka", "rabbitmq", "docker"}, names)
+
+	// Choosing no category offers every candidate.
+	mp.PromptMultiSelectVal = []string{}
+	offered, err = i.selectCategories(candidates)
+	require.NoError(t, err)
+	require.Len(t, offered, len(candidates))
+}
+
+func TestInstall_CategoryPromptNarrowsSelection(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = categorizedRecipes()
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+	v = validation.NewMockRecipeValidator()
+	p := &ux.MockPrompter{
+		PromptYesNoVal:        true,
+		PromptMultiSelectVals: [][]string{{"Caching (1)"}, {"memcached"}},
+	}
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+	require.Equal(t, 2, p.PromptMultiSelectCallCount)
+	require.Equal(t, []string{"memcached"}, p.PromptMultiSelectOptions)
+
+	reporter := statusReporters[0].(*execution.MockStatusReporter)
+	require.Equal(t, 1, reporter.ReportInstalled["recipe-7"])
+	require.Equal(t, 1, reporter.ReportSkipped["recipe-0"])
+	require.Equal(t, 1, reporter.ReportSkipped["custom"])
+}
@@ -0,0 +1,93 @@
+package install
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// resolveConflicts ensures that no two of the given recipes conflict with each
+// other.  For each conflicting pair the user is prompted to choose one, and the
+// other is reported as skipped.  When prompts are disabled via --assumeYes, a
+// conflict results in an error instead.
+func (i *RecipeInstaller) resolveConflicts(recipes []types.OpenInstallationRecipe) ([]types.OpenInstallationRecipe, error) {
+	resolved := []types.OpenInstallationRecipe{}
+
+	for _, r := range recipes {
+		conflict := -1
+		for n, existing := range resolved {
+			if r.ConflictsWithRecipe(existing) {
+				conflict = n
+				break
+			}
+		}
+
+		if conflict < 0 {
+			resolved = append(resolved, r)
+			continue
+		}
+
+		existing := resolved[conflict]
+		if i.AssumeYes {
+			return nil, fmt.Errorf("recipes %s and %s conflict with each other, please select only one of them to install", existing.Name, r.Name)
+		}
+
+		chosen, err := i.chooseConflictingRecipe(existing, r)
+		if err != nil {
+			return nil, err
+		}
+
+		if chosen.Name == existing.Name {
+			i.skipConflictingRecipe(r, existing)
+		} else {
+			i.skipConflictingRecipe(existing, r)
+			resolved[conflict] = r
+		}
+	}
+
+	return resolved, nil
+}
+
+func (i *RecipeInstaller) chooseConflictingRecipe(a types.OpenInstallationRecipe, b types.OpenInstallationRecipe) (types.OpenInstallationRecipe, error) {
+	aName := recipeDisplayName(a)
+	bName := recipeDisplayName(b)
+
+	msg := fmt.Sprintf("%s and %s conflict with each other. Which would you like to install?", aName, bName)
+	choice, err := i.prompter.Select(msg, []string{aName, bName}, aName)
+	if err != nil {
+		return types.OpenInstallationRecipe{}, err
+	}
+
+	if choice == bName {
+		return b, nil
+	}
+
+	return a, nil
+}
+
+func (i *RecipeInstaller) skipConflictingRecipe(skipped types.OpenInstallationRecipe, kept types.OpenInstallationRecipe) {
+	log.WithFields(log.Fields{
+		"skipped": skipped.Name,
+		"kept":    kept.Name,
+	}).Debug("skipping conflicting recipe")
+
+	i.status.RecipeSkipped(execution.RecipeStatusEvent{
+		Recipe: skipped,
+		Msg:    fmt.Sprintf("conflict: %s conflicts with %s", skipped.Name, kept.Name),
+	})
+
+	if skipped.Name == i.loggingRecipeName() {
+		i.SkipLoggingInstall = true
+	}
+}
+
+func recipeDisplayName(r types.OpenInstallationRecipe) string {
+	if r.DisplayName != "" {
+		return r.DisplayName
+	}
+
+	return r.Name
+}
@@ -0,0 +1,106 @@
+// +build unit
+
+package install
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/ux"
+)
+
+var (
+	agentARecipe = types.OpenInstallationRecipe{
+		Name:          "agent-a",
+		DisplayName:   "Agent A",
+		ConflictsWith: []string{"agent-b"},
+	}
+	agentBRecipe = types.OpenInstallationRecipe{
+		Name:        "agent-b",
+		DisplayName: "Agent B",
+	}
+	otherRecipe = types.OpenInstallationRecipe{
+		Name:        "other",
+		DisplayName: "Other",
+	}
+)
+
+func TestResolveConflicts_NoConflicts(t *testing.T) {
+	statusReporter := execution.NewMockStatusReporter()
+	mp := ux.NewMockPrompter()
+	i := RecipeInstaller{
+		prompter: mp,
+		status:   execution.NewInstallStatus([]execution.StatusSubscriber{statusReporter}, nil),
+	}
+
+	resolved, err := i.resolveConflicts([]types.OpenInstallationRecipe{agentARecipe, otherRecipe})
+	require.NoError(t, err)
+	require.Equal(t, []types.OpenInstallationRecipe{agentARecipe, otherRecipe}, resolved)
+	require.Equal(t, 0, mp.PromptSelectCallCount)
+	require.Equal(t, 0, statusReporter.RecipeSkippedCallCount)
+}
+
+func TestResolveConflicts_PromptKeepsChosen(t *testing.T) {
+	statusReporter := execution.NewMockStatusReporter()
+	mp := ux.NewMockPrompter()
+	mp.PromptSelectVal = "Agent B"
+	i := RecipeInstaller{
+		prompter: mp,
+		status:   execution.NewInstallStatus([]execution.StatusSubscriber{statusReporter}, nil),
+	}
+
+	resolved, err := i.resolveConflicts([]types.OpenInstallationRecipe{agentARecipe, otherRecipe, agentBRecipe})
+	require.NoError(t, err)
+	require.Equal(t, []types.OpenInstallationRecipe{agentBRecipe, otherRecipe}, resolved)
+	require.Equal(t, 1, mp.PromptSelectCallCount)
+	require.Equal(t, 1, statusReporter.ReportSkipped["agent-a"])
+}
+
+func TestResolveConflicts_PromptDefaultKeepsFirst(t *testing.T) {
+	statusReporter := execution.NewMockStatusReporter()
+	mp := ux.NewMockPrompter()
+	i := RecipeInstaller{
+		prompter: mp,
+		status:   execution.NewInstallStatus([]execution.StatusSubscriber{statusReporter}, nil),
+	}
+
+	resolved, err := i.resolveConflicts([]types.OpenInstallationRecipe{agentBRecipe, agentARecipe})
+	require.NoError(t, err)
+	require.Equal(t, []types.OpenInstallationRecipe{agentBRecipe}, resolved)
+	require.Equal(t, 1, statusReporter.ReportSkipped["agent-a"])
+}
+
+func TestResolveConflicts_AssumeYesErrors(t *testing.T) {
+	mp := ux.NewMockPrompter()
+	i := RecipeInstaller{
+		InstallerContext: InstallerContext{AssumeYes: true},
+		prompter:         mp,
+		status:           execution.NewInstallStatus([]execution.StatusSubscriber{}, nil),
+	}
+
+	_, err := i.resolveConflicts([]types.OpenInstallationRecipe{agentARecipe, agentBRecipe})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "conflict")
+	require.Equal(t, 0, mp.PromptSelectCallCount)
+}
+
+func TestResolveConflicts_SkippedLogging(t *testing.T) {
+	logging := types.OpenInstallationRecipe{
+		Name:          types.LoggingRecipeName,
+		ConflictsWith: []string{"other"},
+	}
+	mp := ux.NewMockPrompter()
+	mp.PromptSelectVal = "Other"
+	i := RecipeInstaller{
+		prompter: mp,
+		status:   execution.NewInstallStatus([]execution.StatusSubscriber{}, nil),
+	}
+
+	resolved, err := i.resolveConflicts([]types.OpenInstallationRecipe{logging, otherRecipe})
+	require.NoError(t, err)
+	require.Equal(t, []types.OpenInstallationRecipe{otherRecipe}, resolved)
+	require.True(t, i.SkipLoggingInstall)
+}
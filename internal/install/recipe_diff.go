@@ -0,0 +1,113 @@
+package install
+
+import (
+	"context"
+	"sort"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+const (
+	diffedRecipeAdded   = "ADDED"
+	diffedRecipeRemoved = "REMOVED"
+	diffedRecipeChanged = "CHANGED"
+)
+
+// DiffedRecipe is a difference between the recipes recorded as installed and
+// those currently offered for the host.
+type DiffedRecipe struct {
+	Name              string `json:"name"`
+	DisplayName       string `json:"displayName"`
+	Change            string `json:"change"`
+	InstalledChecksum string `json:"installedChecksum,omitempty"`
+	CurrentChecksum   string `json:"currentChecksum,omitempty"`
+}
+
+// diffRecipes discovers the host and compares the recipes that can be
+// installed on it with those recorded as installed.  Recipes offered but not
+// installed are added, recipes installed but no longer offered are removed,
+// and installed recipes whose definition has since been updated are changed.
+// Nothing is prompted for or installed.
+func (i *RecipeInstaller) diffRecipes(ctx context.Context, installed map[string]*execution.RecipeStatus) ([]DiffedRecipe, error) {
+	m, err := i.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hostErr := i.manifestValidator.Execute(m)
+
+	offered, err := i.offeredRecipes(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+
+	desired := []types.OpenInstallationRecipe{}
+	for _, r := range offered {
+		if i.listRecipe(ctx, m, r, hostErr).Status != listedRecipeUnsupported {
+			desired = append(desired, r)
+		}
+	}
+
+	return diffInstalledRecipes(installed, desired), nil
+}
+
+// diffInstalledRecipes compares the installed recipes with the desired ones,
+// listing additions and changes in the order desired, followed by removals by
+// name.
+func diffInstalledRecipes(installed map[string]*execution.RecipeStatus, desired []types.OpenInstallationRecipe) []DiffedRecipe {
+	diffed := []DiffedRecipe{}
+	seen := map[string]bool{}
+
+	for _, r := range desired {
+		seen[r.Name] = true
+		current := r.Checksum()
+
+		rs, ok := installed[r.Name]
+		if !ok {
+			diffed = append(diffed, DiffedRecipe{
+				Name:            r.Name,
+				DisplayName:     recipeDisplayName(r),
+				Change:          diffedRecipeAdded,
+				CurrentChecksum: current,
+			})
+			continue
+		}
+
+		// Installs recorded before checksums were kept cannot be compared.
+		if rs.Checksum != "" && rs.Checksum != current {
+			diffed = append(diffed, DiffedRecipe{
+				Name:              r.Name,
+				DisplayName:       recipeDisplayName(r),
+				Change:            diffedRecipeChanged,
+				InstalledChecksum: rs.Checksum,
+				CurrentChecksum:   current,
+			})
+		}
+	}
+
+	removed := []string{}
+	for name := range installed {
+		if !seen[name] {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+
+	for _, name := range removed {
+		rs := installed[name]
+		displayName := rs.DisplayName
+		if displayName == "" {
+			displayName = rs.Name
+		}
+
+		diffed = append(diffed, DiffedRecipe{
+			Name:              rs.Name,
+			DisplayName:       displayName,
+			Change:            diffedRecipeRemoved,
+			InstalledChecksum: rs.Checksum,
+		})
+	}
+
+	return diffed
+}
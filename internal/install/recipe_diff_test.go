@@ -0,0 +1,76 @@
+// +build unit
+
+package install
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/discovery"
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestDiffInstalledRecipes(t *testing.T) {
+	infra := types.OpenInstallationRecipe{Name: types.InfraAgentRecipeName, DisplayName: "Infrastructure Agent", File: "infra"}
+	mysql := types.OpenInstallationRecipe{Name: "mysql", File: "mysql v2"}
+	nginx := types.OpenInstallationRecipe{Name: "nginx", File: "nginx"}
+
+	installed := map[string]*execution.RecipeStatus{
+		types.InfraAgentRecipeName: {Name: types.InfraAgentRecipeName, Checksum: infra.Checksum()},
+		"mysql":                    {Name: "mysql", Checksum: "outdated"},
+		"redis":                    {Name: "redis", DisplayName: "Redis", Checksum: "r"},
+		"apache":                   {Name: "apache"},
+	}
+
+	diffed := diffInstalledRecipes(installed, []types.OpenInstallationRecipe{infra, mysql, nginx})
+	require.Equal(t, []DiffedRecipe{
+		{Name: "mysql", DisplayName: "mysql", Change: diffedRecipeChanged, InstalledChecksum: "outdated", CurrentChecksum: mysql.Checksum()},
+		{Name: "nginx", DisplayName: "nginx", Change: diffedRecipeAdded, CurrentChecksum: nginx.Checksum()},
+		{Name: "apache", DisplayName: "apache", Change: diffedRecipeRemoved},
+		{Name: "redis", DisplayName: "Redis", Change: diffedRecipeRemoved, InstalledChecksum: "r"},
+	}, diffed)
+}
+
+func TestDiffInstalledRecipes_NoDrift(t *testing.T) {
+	infra := types.OpenInstallationRecipe{Name: types.InfraAgentRecipeName, File: "infra"}
+
+	installed := map[string]*execution.RecipeStatus{
+		types.InfraAgentRecipeName: {Name: types.InfraAgentRecipeName},
+	}
+
+	require.Empty(t, diffInstalledRecipes(installed, []types.OpenInstallationRecipe{infra}))
+}
+
+func TestDiffRecipes_IgnoresUnsupportedRecipes(t *testing.T) {
+	md := discovery.NewMockDiscovererWithManifest(types.DiscoveryManifest{OS: "linux"})
+	rf := recipes.NewMockRecipeFetcher()
+	rf.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{Name: types.InfraAgentRecipeName},
+		{Name: types.LoggingRecipeName},
+	}
+	rf.FetchRecommendationsVal = []types.OpenInstallationRecipe{
+		{Name: "iis", InstallTargets: []types.OpenInstallationRecipeInstallTarget{{Os: types.OpenInstallationOperatingSystemTypes.WINDOWS}}},
+		{Name: "nginx"},
+	}
+
+	i := RecipeInstaller{
+		discoverer:        md,
+		manifestValidator: discovery.NewEmptyManifestValidator(),
+		recipeFetcher:     rf,
+	}
+
+	installed := map[string]*execution.RecipeStatus{
+		types.InfraAgentRecipeName: {Name: types.InfraAgentRecipeName},
+		types.LoggingRecipeName:    {Name: types.LoggingRecipeName},
+	}
+
+	diffed, err := i.diffRecipes(context.Background(), installed)
+	require.NoError(t, err)
+	require.Len(t, diffed, 1)
+	require.Equal(t, "nginx", diffed[0].Name)
+	require.Equal(t, diffedRecipeAdded, diffed[0].Change)
+}
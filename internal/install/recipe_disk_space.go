@@ -0,0 +1,35 @@
+package install
+
+import (
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// defaultRequiredDiskMB is the free disk space, in megabytes, required by
+// recipes that do not declare their own.
+const defaultRequiredDiskMB = 100
+
+// checkDiskSpace ensures the filesystems discovered have the free space the
+// recipe requires.  A shortage is reported as a failure of the recipe before
+// it is executed.  The check is skipped when disk space could not be
+// discovered.
+func (i *RecipeInstaller) checkDiskSpace(m *types.DiscoveryManifest, r *types.OpenInstallationRecipe) error {
+	required := r.RequiredDiskMB
+	if required <= 0 {
+		required = defaultRequiredDiskMB
+	}
+
+	fs, ok := m.LowestFreeFilesystem()
+	if !ok || fs.FreeMB >= uint64(required) {
+		return nil
+	}
+
+	err := NewErrInsufficientDiskSpace(r.Name, fs.Path, required, fs.FreeMB)
+
+	i.status.RecipeFailed(execution.RecipeStatusEvent{
+		Recipe: *r,
+		Msg:    err.Error(),
+	})
+
+	return err
+}
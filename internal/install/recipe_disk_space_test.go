@@ -0,0 +1,75 @@
+// +build unit
+
+package install
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/discovery"
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func installWithFilesystems(recipe types.OpenInstallationRecipe, filesystems []types.Filesystem) error {
+	ic := InstallerContext{
+		RecipeNames: []string{recipe.Name},
+		AssumeYes:   true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVal = &recipe
+
+	md := discovery.NewMockDiscoverer()
+	md.DiscoveryManifest.Filesystems = filesystems
+
+	i := RecipeInstaller{ic, md, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	return i.Install()
+}
+
+func TestInstall_InsufficientDiskSpaceFailsRecipe(t *testing.T) {
+	err := installWithFilesystems(
+		types.OpenInstallationRecipe{Name: testRecipeName, RequiredDiskMB: 500},
+		[]types.Filesystem{{Path: "/", FreeMB: 4096}, {Path: "/var", FreeMB: 300}},
+	)
+
+	var derr ErrInsufficientDiskSpace
+	require.True(t, errors.As(err, &derr))
+	require.Equal(t, "/var", derr.Path)
+	require.Equal(t, 500, derr.RequiredMB)
+	require.Equal(t, uint64(300), derr.AvailableMB)
+
+	reporter := statusReporters[0].(*execution.MockStatusReporter)
+	require.Equal(t, 1, reporter.RecipeFailedCallCount)
+	require.Equal(t, 0, reporter.RecipeInstallingCallCount)
+}
+
+func TestInstall_InsufficientDiskSpaceUsesDefault(t *testing.T) {
+	err := installWithFilesystems(
+		types.OpenInstallationRecipe{Name: testRecipeName},
+		[]types.Filesystem{{Path: "/", FreeMB: defaultRequiredDiskMB - 1}},
+	)
+
+	var derr ErrInsufficientDiskSpace
+	require.True(t, errors.As(err, &derr))
+	require.Equal(t, defaultRequiredDiskMB, derr.RequiredMB)
+}
+
+func TestInstall_SufficientDiskSpaceInstallsRecipe(t *testing.T) {
+	err := installWithFilesystems(
+		types.OpenInstallationRecipe{Name: testRecipeName, RequiredDiskMB: 500},
+		[]types.Filesystem{{Path: "/", FreeMB: 500}},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
+}
+
+func TestInstall_UndiscoveredDiskSpaceInstallsRecipe(t *testing.T) {
+	err := installWithFilesystems(types.OpenInstallationRecipe{Name: testRecipeName, RequiredDiskMB: 500}, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
+}
@@ -0,0 +1,261 @@
+package install
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// RecipeExplanation describes what a recipe does when installed, without
+// running it.
+type RecipeExplanation struct {
+	Name         string                `json:"name"`
+	DisplayName  string                `json:"displayName,omitempty"`
+	Description  string                `json:"description,omitempty"`
+	Repository   string                `json:"repository,omitempty"`
+	Targets      []string              `json:"targets"`
+	Dependencies []string              `json:"dependencies,omitempty"`
+	PreInstall   string                `json:"preInstall,omitempty"`
+	PostInstall  string                `json:"postInstall,omitempty"`
+	Variables    []ExplainedVariable   `json:"variables"`
+	Steps        []ExplainedRecipeStep `json:"steps"`
+	Validation   string                `json:"validation"`
+}
+
+// ExplainedVariable is an input variable a recipe asks for.
+type ExplainedVariable struct {
+	Name    string `json:"name"`
+	Prompt  string `json:"prompt,omitempty"`
+	Default string `json:"default,omitempty"`
+	Secret  bool   `json:"secret,omitempty"`
+}
+
+// ExplainedRecipeStep is a task a recipe runs and its commands.
+type ExplainedRecipeStep struct {
+	Task        string   `json:"task"`
+	Description string   `json:"description,omitempty"`
+	Commands    []string `json:"commands"`
+}
+
+// explainRecipe describes the given recipe.
+func explainRecipe(r types.OpenInstallationRecipe) (*RecipeExplanation, error) {
+	steps, err := explainSteps(r.Install)
+	if err != nil {
+		return nil, fmt.Errorf("could not read install steps of %s: %s", r.Name, err)
+	}
+
+	e := RecipeExplanation{
+		Name:         r.Name,
+		DisplayName:  r.DisplayName,
+		Description:  strings.TrimSpace(r.Description),
+		Repository:   r.Repository,
+		Targets:      explainTargets(r),
+		Dependencies: r.Dependencies,
+		PreInstall:   strings.TrimSpace(r.PreInstall.Info),
+		PostInstall:  strings.TrimSpace(r.PostInstall.Info),
+		Variables:    []ExplainedVariable{},
+		Steps:        steps,
+		Validation:   "none, the recipe is considered installed once its steps succeed",
+	}
+
+	if r.ValidationNRQL != "" {
+		e.Validation = fmt.Sprintf("waits for data from the query: %s", strings.TrimSpace(string(r.ValidationNRQL)))
+	}
+
+	for _, v := range r.InputVars {
+		ev := ExplainedVariable{
+			Name:   v.Name,
+			Prompt: v.Prompt,
+			Secret: v.Secret,
+		}
+
+		if !v.Secret {
+			ev.Default = v.Default
+		}
+
+		e.Variables = append(e.Variables, ev)
+	}
+
+	return &e, nil
+}
+
+func explainTargets(r types.OpenInstallationRecipe) []string {
+	targets := []string{}
+
+	for _, t := range r.InstallTargets {
+		target := string(t.Type)
+
+		details := []string{}
+		for _, d := range []string{string(t.Os), string(t.Platform), t.PlatformVersion, t.KernelArch} {
+			if d != "" {
+				details = append(details, strings.ToLower(d))
+			}
+		}
+
+		if len(details) > 0 {
+			target = fmt.Sprintf("%s (%s)", target, strings.Join(details, " "))
+		}
+
+		targets = append(targets, target)
+	}
+
+	return targets
+}
+
+// explainSteps lists the tasks of a recipe's task file in the order they are
+// declared.
+func explainSteps(install string) ([]ExplainedRecipeStep, error) {
+	steps := []ExplainedRecipeStep{}
+
+	var tf yaml.MapSlice
+	if err := yaml.Unmarshal([]byte(install), &tf); err != nil {
+		return nil, err
+	}
+
+	for _, item := range tf {
+		if item.Key != "tasks" {
+			continue
+		}
+
+		tasks, ok := item.Value.(yaml.MapSlice)
+		if !ok {
+			continue
+		}
+
+		for _, task := range tasks {
+			step := ExplainedRecipeStep{
+				Task:     fmt.Sprint(task.Key),
+				Commands: []string{},
+			}
+
+			def, ok := task.Value.(yaml.MapSlice)
+			if !ok {
+				steps = append(steps, step)
+				continue
+			}
+
+			for _, field := range def {
+				switch field.Key {
+				case "desc":
+					step.Description = fmt.Sprint(field.Value)
+				case "cmds":
+					if cmds, ok := field.Value.([]interface{}); ok {
+						for _, c := range cmds {
+							step.Commands = append(step.Commands, explainCommand(c))
+						}
+					}
+				}
+			}
+
+			steps = append(steps, step)
+		}
+	}
+
+	return steps, nil
+}
+
+// explainCommand describes a task command, which is either a shell command or
+// a call to another task.
+func explainCommand(c interface{}) string {
+	m, ok := c.(yaml.MapSlice)
+	if !ok {
+		return strings.TrimSpace(fmt.Sprint(c))
+	}
+
+	for _, field := range m {
+		switch field.Key {
+		case "cmd":
+			return strings.TrimSpace(fmt.Sprint(field.Value))
+		case "task":
+			return fmt.Sprintf("run task %s", field.Value)
+		}
+	}
+
+	return ""
+}
+
+func (e *RecipeExplanation) String() string {
+	var b strings.Builder
+
+	name := e.DisplayName
+	if name == "" {
+		name = e.Name
+	}
+
+	fmt.Fprintf(&b, "%s (%s)\n", name, e.Name)
+	if e.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", e.Description)
+	}
+	if e.Repository != "" {
+		fmt.Fprintf(&b, "\nRepository: %s\n", e.Repository)
+	}
+
+	if len(e.Targets) > 0 {
+		fmt.Fprintf(&b, "\nTargets:\n")
+		for _, t := range e.Targets {
+			fmt.Fprintf(&b, "  - %s\n", t)
+		}
+	}
+
+	if len(e.Dependencies) > 0 {
+		fmt.Fprintf(&b, "\nDepends on: %s\n", strings.Join(e.Dependencies, ", "))
+	}
+
+	if e.PreInstall != "" {
+		fmt.Fprintf(&b, "\nBefore installing:\n%s\n", indent(e.PreInstall))
+	}
+
+	fmt.Fprintf(&b, "\nVariables:\n")
+	if len(e.Variables) == 0 {
+		fmt.Fprintf(&b, "  none\n")
+	}
+	for _, v := range e.Variables {
+		desc := v.Name
+		if v.Prompt != "" {
+			desc = fmt.Sprintf("%s: %s", desc, v.Prompt)
+		}
+		if v.Default != "" {
+			desc = fmt.Sprintf("%s (default %s)", desc, v.Default)
+		}
+		if v.Secret {
+			desc = fmt.Sprintf("%s (secret)", desc)
+		}
+		fmt.Fprintf(&b, "  - %s\n", desc)
+	}
+
+	fmt.Fprintf(&b, "\nSteps:\n")
+	for _, s := range e.Steps {
+		if s.Description != "" {
+			fmt.Fprintf(&b, "  %s: %s\n", s.Task, s.Description)
+		} else {
+			fmt.Fprintf(&b, "  %s\n", s.Task)
+		}
+		for _, c := range s.Commands {
+			fmt.Fprintf(&b, "%s\n", indentBy(c, "    | "))
+		}
+	}
+
+	fmt.Fprintf(&b, "\nValidation: %s\n", e.Validation)
+
+	if e.PostInstall != "" {
+		fmt.Fprintf(&b, "\nAfter installing:\n%s\n", indent(e.PostInstall))
+	}
+
+	return b.String()
+}
+
+func indent(s string) string {
+	return indentBy(s, "  ")
+}
+
+func indentBy(s string, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = prefix + l
+	}
+
+	return strings.Join(lines, "\n")
+}
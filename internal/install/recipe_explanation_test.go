@@ -0,0 +1,101 @@
+// +build unit
+
+package install
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+const testExplainInstall = `
+version: "3"
+tasks:
+  default:
+    cmds:
+      - task: setup
+  setup:
+    desc: Set things up
+    cmds:
+      - echo hi
+      - cmd: echo there
+`
+
+func testExplainRecipe() types.OpenInstallationRecipe {
+	return types.OpenInstallationRecipe{
+		Name:        "test-recipe",
+		DisplayName: "Test Recipe",
+		Description: "A test recipe",
+		InstallTargets: []types.OpenInstallationRecipeInstallTarget{
+			{
+				Type:     types.OpenInstallationTargetTypeTypes.HOST,
+				Os:       types.OpenInstallationOperatingSystemTypes.LINUX,
+				Platform: types.OpenInstallationPlatformTypes.UBUNTU,
+			},
+		},
+		InputVars: []types.OpenInstallationRecipeInputVariable{
+			{Name: "DB_USER", Prompt: "Database user", Default: "root"},
+			{Name: "DB_PASS", Prompt: "Database password", Default: "hunter2", Secret: true},
+		},
+		PreInstall:     types.OpenInstallationPreInstallConfiguration{Info: "Before."},
+		PostInstall:    types.OpenInstallationPostInstallConfiguration{Info: "After."},
+		ValidationNRQL: "SELECT count(*) FROM SystemSample",
+		Install:        testExplainInstall,
+	}
+}
+
+func TestExplainRecipe(t *testing.T) {
+	e, err := explainRecipe(testExplainRecipe())
+	require.NoError(t, err)
+
+	require.Equal(t, "test-recipe", e.Name)
+	require.Equal(t, []string{"HOST (linux ubuntu)"}, e.Targets)
+	require.Equal(t, "Before.", e.PreInstall)
+	require.Equal(t, "After.", e.PostInstall)
+	require.Contains(t, e.Validation, "SELECT count(*) FROM SystemSample")
+
+	require.Equal(t, 2, len(e.Variables))
+	require.Equal(t, "root", e.Variables[0].Default)
+	require.True(t, e.Variables[1].Secret)
+	require.Equal(t, "", e.Variables[1].Default)
+
+	require.Equal(t, 2, len(e.Steps))
+	require.Equal(t, "default", e.Steps[0].Task)
+	require.Equal(t, []string{"run task setup"}, e.Steps[0].Commands)
+	require.Equal(t, "setup", e.Steps[1].Task)
+	require.Equal(t, "Set things up", e.Steps[1].Description)
+	require.Equal(t, []string{"echo hi", "echo there"}, e.Steps[1].Commands)
+}
+
+func TestExplainRecipe_NoValidation(t *testing.T) {
+	r := testExplainRecipe()
+	r.ValidationNRQL = ""
+
+	e, err := explainRecipe(r)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(e.Validation, "none"))
+}
+
+func TestExplainRecipe_InvalidInstall(t *testing.T) {
+	r := testExplainRecipe()
+	r.Install = "tasks: [unclosed"
+
+	_, err := explainRecipe(r)
+	require.Error(t, err)
+}
+
+func TestRecipeExplanation_String(t *testing.T) {
+	e, err := explainRecipe(testExplainRecipe())
+	require.NoError(t, err)
+
+	s := e.String()
+	require.Contains(t, s, "Test Recipe (test-recipe)")
+	require.Contains(t, s, "DB_USER: Database user (default root)")
+	require.Contains(t, s, "DB_PASS: Database password (secret)")
+	require.NotContains(t, s, "hunter2")
+	require.Contains(t, s, "    | echo hi")
+	require.Contains(t, s, "Validation: waits for data")
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/newrelic/newrelic-cli/internal/config"
+	"github.com/newrelic/newrelic-cli/internal/credentials"
 	"github.com/newrelic/newrelic-cli/internal/install/discovery"
 	"github.com/newrelic/newrelic-cli/internal/install/execution"
 	"github.com/newrelic/newrelic-cli/internal/install/recipes"
@@ -15,9 +18,39 @@ import (
 	"github.com/newrelic/newrelic-cli/internal/install/ux"
 	"github.com/newrelic/newrelic-cli/internal/install/validation"
 	"github.com/newrelic/newrelic-cli/internal/utils"
+	utilsValidation "github.com/newrelic/newrelic-cli/internal/utils/validation"
 	"github.com/newrelic/newrelic-client-go/newrelic"
 )
 
+const (
+	// maxRecipeRetries caps how many times a failed recipe can be retried.
+	maxRecipeRetries = 3
+
+	recipeFailureRetry = "Retry"
+	recipeFailureSkip  = "Skip"
+	recipeFailureAbort = "Abort"
+
+	emptySelectionContinue    = "Continue with the infrastructure agent only"
+	emptySelectionChooseAgain = "Choose integrations again"
+
+	// stepMarkerDirName is the directory under the config directory in which
+	// completed recipe steps are recorded.
+	stepMarkerDirName = "step-markers"
+
+	// validationCheckpointDirName is the directory under the config directory
+	// in which recipes whose validation failed are recorded.
+	validationCheckpointDirName = "validation-checkpoints"
+
+	// dryRunSkippedMsg is the reason recipes run in a dry run are reported
+	// skipped rather than installed.
+	dryRunSkippedMsg = "dry run, no commands were run"
+)
+
+var (
+	recipeFailureOptions  = []string{recipeFailureRetry, recipeFailureSkip, recipeFailureAbort}
+	emptySelectionOptions = []string{emptySelectionContinue, emptySelectionChooseAgain}
+)
+
 type RecipeInstaller struct {
 	InstallerContext
 	discoverer        discovery.Discoverer
@@ -31,75 +64,242 @@ type RecipeInstaller struct {
 	prompter          ux.Prompter
 	progressIndicator ux.ProgressIndicator
 	licenseKeyFetcher LicenseKeyFetcher
+	selectionStore    SelectionStore
+	recipeDetector    validation.RecipeDetector
+	cleanup           *execution.CleanupRegistry
 }
 
 func NewRecipeInstaller(ic InstallerContext, nrClient *newrelic.NewRelic) *RecipeInstaller {
 
-	var recipeFetcher recipes.RecipeFetcher
+	recipeFetcher := newRecipeFetcher(ic, nrClient)
+	ff := newRecipeFileFetcher(ic.CABundle)
 
-	if ic.LocalRecipes != "" {
-		recipeFetcher = &recipes.LocalRecipeFetcher{
-			Path: ic.LocalRecipes,
-		}
-
-	} else {
-		recipeFetcher = recipes.NewServiceRecipeFetcher(&nrClient.NerdGraph)
+	if v := newRecipeVerifier(ic); v != nil {
+		recipeFetcher = recipes.NewVerifyingRecipeFetcher(recipeFetcher, v)
+		ff = newVerifyingRecipeFileFetcher(ff, v, ic.CABundle)
 	}
 
 	pf := discovery.NewRegexProcessFilterer(recipeFetcher)
 	mv := discovery.NewManifestValidator()
-	ff := recipes.NewRecipeFileFetcher()
 	ers := []execution.StatusSubscriber{
-		execution.NewNerdStorageStatusReporter(&nrClient.NerdStorage),
-		execution.NewTerminalStatusReporter(),
+		execution.NewNerdStorageStatusReporter(&nrClient.NerdStorage, nerdStorageStatusOptions(ic)...),
+		newTerminalStatusReporter(ic.Quiet),
+		execution.NewValidationCheckpointReporter(newValidationCheckpointStore()),
+	}
+	if ic.SendUsageData && !execution.UsageDataOptedOut() {
+		ers = append(ers, execution.NewTelemetryStatusReporter(&nrClient.Events))
+	}
+	if ic.JUnitOutput != "" {
+		ers = append(ers, execution.NewJUnitStatusReporter(ic.JUnitOutput))
+	}
+	if ic.JSONOutput != "" {
+		ers = append(ers, execution.NewJSONStatusReporter(ic.JSONOutput))
+	}
+	if ic.SuccessLinksOutput != "" {
+		ers = append(ers, execution.NewSuccessLinksStatusReporter(ic.SuccessLinksOutput))
+	}
+	runDir := createRunDirectory(ic.OutputDir)
+	if runDir != "" {
+		ers = append(ers, execution.NewArtifactStatusReporter(runDir))
+	}
+	var hb *execution.HeartbeatStatusReporter
+	if ic.HeartbeatFile != "" {
+		hb = execution.NewHeartbeatStatusReporter(ic.HeartbeatFile, ic.HeartbeatInterval)
+		ers = append(ers, hb)
+	}
+	var mr *execution.MetricsStatusReporter
+	if ic.MetricsAddr != "" {
+		mr = execution.NewMetricsStatusReporter(ic.MetricsAddr)
+		ers = append(ers, mr)
 	}
 	lkf := NewServiceLicenseKeyFetcher(&nrClient.NerdGraph)
 	slg := execution.NewConcreteSuccessLinkGenerator()
 	statusRollup := execution.NewInstallStatus(ers, slg)
+	statusRollup.CampaignID = ic.CampaignID
+	config.SetFileLogField("correlation_id", statusRollup.CorrelationID)
 
-	d := discovery.NewPSUtilDiscoverer(pf)
+	var d discovery.Discoverer = discovery.NewPSUtilDiscoverer(pf)
+	if ic.ManifestFile != "" {
+		d = discovery.NewFileDiscoverer(ic.ManifestFile)
+	}
 	gff := discovery.NewGlobFileFilterer()
+	gff.MaxMatches = ic.LogMaxMatches
+	gff.MaxAgeDays = ic.LogMaxAgeDays
+	gff.Exclude = ic.LogExclude
+	gff.Concurrency = ic.LogGlobConcurrency
+	cr := execution.NewCleanupRegistry()
 	re := execution.NewGoTaskRecipeExecutor()
-	v := validation.NewPollingRecipeValidator(&nrClient.Nrdb)
-	p := ux.NewPromptUIPrompter()
-	pi := ux.NewPlainProgress()
+	re.Cleanup = cr
+	re.VerboseSteps = ic.VerboseRecipeSteps
+	re.VarsFiles = ic.RecipeVarsFiles
+	re.OutputDir = runDir
+	re.Quiet = ic.Quiet
+	re.Audit = ic.Audit
+	re.Shell = ic.Shell
+	re.IsolatedEnv = ic.IsolatedEnv
+	re.CABundle = ic.CABundle
+	re.DryRun = ic.DryRun
+	re.ResourceLimits = ic.recipeResourceLimits()
+	re.StepMarkerDir = filepath.Join(config.DefaultConfigDirectory, stepMarkerDirName)
+	re.Reinstall = ic.Reinstall
+	re.StepSkipped = statusRollup.RecipeStepSkipped
+	qc := utilsValidation.NewCachingNRDBClient(&nrClient.Nrdb, utilsValidation.DefaultQueryCacheTTL)
+	v := validation.NewAccountRecipeValidator(validation.NewPollingRecipeValidator(qc))
+	rd := validation.NewNRQLRecipeDetector(&nrClient.Nrdb)
+	p := newPrompter(ic)
+	re.Prompter = p
+	pi := newProgressIndicator(ic)
+	ss := NewFileSelectionStore(config.DefaultConfigDirectory)
 
 	i := RecipeInstaller{
 		discoverer:        d,
 		fileFilterer:      gff,
 		manifestValidator: mv,
 		recipeFetcher:     recipeFetcher,
-		recipeExecutor:    re,
+		recipeExecutor:    newRetryingRecipeExecutor(ic, re, statusRollup),
 		recipeValidator:   v,
 		recipeFileFetcher: ff,
 		status:            statusRollup,
 		prompter:          p,
 		progressIndicator: pi,
 		licenseKeyFetcher: lkf,
+		selectionStore:    ss,
+		recipeDetector:    rd,
+		cleanup:           cr,
 	}
 
 	i.InstallerContext = ic
+	i.Messages = loadMessages(ic)
+	i.RecipePolicy = loadRecipePolicy(ic)
+	i.runDir = runDir
+	i.heartbeat = hb
+	i.metrics = mr
+	if i.EntitlementChecker == nil {
+		i.EntitlementChecker = newEntitlementChecker(nrClient)
+	}
 
 	return &i
 }
 
+// newEntitlementChecker returns the checker of the entitlements of the default
+// profile's account, or nil when there is no account to check.
+func newEntitlementChecker(nrClient *newrelic.NewRelic) EntitlementChecker {
+	p := credentials.DefaultProfile()
+	if p == nil || p.AccountID == 0 {
+		return nil
+	}
+
+	return NewServiceEntitlementChecker(&nrClient.NerdGraph, p.AccountID)
+}
+
+// nerdStorageStatusOptions returns the options of the NerdStorage status
+// reporter, which also writes to the campaign's shared status when the install
+// is part of one.
+func nerdStorageStatusOptions(ic InstallerContext) []execution.NerdStorageStatusReporterOption {
+	if ic.CampaignID == "" {
+		return nil
+	}
+
+	accountID := 0
+	if p := credentials.DefaultProfile(); p != nil {
+		accountID = p.AccountID
+	}
+
+	return []execution.NerdStorageStatusReporterOption{
+		execution.WithAggregateStatus(ic.CampaignID, accountID, execution.DefaultAggregateWriteInterval),
+	}
+}
+
+// newProgressIndicator returns the progress indicator of the style given by
+// --progress, or one reporting nothing for quiet installs.
+func newProgressIndicator(ic InstallerContext) ux.ProgressIndicator {
+	if ic.Quiet {
+		return ux.NewNoOpProgress()
+	}
+
+	if ic.Progress == ux.ProgressLines {
+		return ux.NewLinesProgress()
+	}
+
+	return ux.NewPlainProgress()
+}
+
+func newTerminalStatusReporter(quiet bool) *execution.TerminalStatusReporter {
+	r := execution.NewTerminalStatusReporter()
+	r.Quiet = quiet
+
+	return r
+}
+
+// createRunDirectory creates the directory for this run's artifacts under the
+// given output directory, or the config directory if none is given, removing
+// the oldest so that only the most recent runs are kept.  Artifacts are
+// best-effort, so a failure is only warned about.
+func createRunDirectory(outputDir string) string {
+	if outputDir == "" {
+		outputDir = config.DefaultConfigDirectory
+	}
+
+	runDir, err := execution.CreateRunDirectory(outputDir, time.Now())
+	if err != nil {
+		log.Warnf("Install artifacts will not be written: %s", err)
+		return ""
+	}
+
+	log.Debugf("writing install artifacts to %s", runDir)
+
+	removed, err := execution.PruneRunDirectories(outputDir, execution.MaxRunDirectories)
+	if err != nil {
+		log.Debugf("could not remove old install artifacts: %s", err)
+	}
+	for _, dir := range removed {
+		log.Debugf("removed old install artifacts %s", dir)
+	}
+
+	return runDir
+}
+
+// startStatusReporters starts the status subscribers that report the progress
+// of the install while it runs.  They stop once the install ends.
+func (i *RecipeInstaller) startStatusReporters() {
+	if i.heartbeat != nil {
+		i.heartbeat.Start()
+	}
+
+	if i.metrics != nil {
+		if err := i.metrics.Start(); err != nil {
+			log.Warnf("The install metrics could not be served on %s: %s", i.MetricsAddr, err)
+		}
+	}
+}
+
+// Install runs the installation, reporting its progress to the configured
+// status subscribers.
 func (i *RecipeInstaller) Install() error {
-	fmt.Printf(`
+	_, err := i.InstallWithResult()
+	return err
+}
+
+// InstallWithResult runs the installation like Install, and additionally
+// returns a summary of its outcome for programmatic use.
+func (i *RecipeInstaller) InstallWithResult() (*InstallResult, error) {
+	if !i.Quiet {
+		fmt.Printf(`
    _   _                 ____      _ _
   | \ | | _____      __ |  _ \ ___| (_) ___
   |  \| |/ _ \ \ /\ / / | |_) / _ | | |/ __|
   | |\  |  __/\ V  V /  |  _ |  __| | | (__
   |_| \_|\___| \_/\_/   |_| \_\___|_|_|\___|
 
-  Welcome to New Relic. Let's install some instrumentation.
-
-  Questions? Read more about our installation process at
-  https://docs.newrelic.com/
-
-	`)
-	fmt.Println()
+`)
+		fmt.Printf("%s\n\n\n", indentMessage(showMessage(i.messages().Welcome, messageData{})))
+		fmt.Printf("%s\n\n", indentMessage(showMessage(i.messages().InstallID, messageData{ID: i.status.CorrelationID})))
+	}
 
+	log.Debugf("install correlation ID: %s", i.status.CorrelationID)
 	log.Tracef("InstallerContext: %+v", i.InstallerContext)
+
+	i.startStatusReporters()
 	log.WithFields(log.Fields{
 		"ShouldRunDiscovery":        i.ShouldRunDiscovery(),
 		"ShouldInstallInfraAgent":   i.ShouldInstallInfraAgent(),
@@ -113,26 +313,46 @@ func (i *RecipeInstaller) Install() error {
 	ctx, cancel := context.WithCancel(utils.SignalCtx)
 	defer cancel()
 
+	// Remove temporary files left by recipes that did not finish, whether the
+	// install was canceled, including by a signal, or failed.  Run artifacts
+	// under the output directory are kept.
+	defer i.cleanup.Cleanup()
+
 	errChan := make(chan error)
 	var err error
 
+	// The install time budget stops recipes, but unlike a signal does not
+	// cancel the install.
+	runCtx, cancelRun := i.withInstallTimeout(ctx)
+	defer cancelRun()
+
 	go func(ctx context.Context) {
 		errChan <- i.discoverAndRun(ctx)
-	}(ctx)
+	}(runCtx)
 
 	select {
 	case <-ctx.Done():
+		_ = i.runPostInstallHook(context.Background(), types.ErrInterrupt)
 		i.status.InstallCanceled()
-		return nil
+		return newInstallResult(i.status, nil, true), nil
 	case err = <-errChan:
-		if err == types.ErrInterrupt {
+		err = classifyAuthError(err)
+
+		if isCanceled(err) || err == types.ErrPromptTimeout {
+			_ = i.runPostInstallHook(ctx, err)
 			i.status.InstallCanceled()
-			return err
+			return newInstallResult(i.status, err, true), err
 		}
 
+		err = i.runPostInstallHook(ctx, err)
 		i.status.InstallComplete(err)
 
-		return err
+		result := newInstallResult(i.status, err, false)
+		if err != nil {
+			result.DiagnosticsBundle = i.writeDiagnosticsBundle()
+		}
+
+		return result, err
 	}
 }
 
@@ -150,6 +370,15 @@ func (i *RecipeInstaller) discoverAndRun(ctx context.Context) error {
 		return err
 	}
 
+	// Export the candidates of a guided install in place of installing.
+	if i.ExportCandidates != "" {
+		return i.exportCandidates(ctx, m)
+	}
+
+	if err = i.runPreInstallHook(ctx, m); err != nil {
+		return err
+	}
+
 	if i.RecipesProvided() {
 		// Run the targeted (AKA stitched path) installer.
 		return i.targetedInstall(ctx, m)
@@ -168,38 +397,110 @@ func (i *RecipeInstaller) assertDiscoveryValid(ctx context.Context, m *types.Dis
 	return nil
 }
 
+// installRecipes installs the recipes in the order given, which places each
+// after its prerequisites, so it is not sorted again.  The order is the same
+// across runs on identical hosts, since integrations are ordered by name before
+// being filtered.
 func (i *RecipeInstaller) installRecipes(ctx context.Context, m *types.DiscoveryManifest, recipes []types.OpenInstallationRecipe) error {
 	log.WithFields(log.Fields{
 		"recipe_count": len(recipes),
 	}).Debug("installing recipes")
 
+	if s, ok := i.progressIndicator.(ux.StagedProgressIndicator); ok {
+		for _, r := range recipes {
+			s.Pending(i.installingMsg(r))
+		}
+	}
+
+	failed := 0
+	var timeoutErr error
 	for _, r := range recipes {
 		var err error
 
+		if timeoutErr != nil {
+			i.skipTimedOutRecipes(r)
+			continue
+		}
+
+		// Guard against running a recipe the policy disallows, such as a
+		// prerequisite of one requested.
+		if i.skipIfDisallowed(r) {
+			continue
+		}
+
 		log.WithFields(log.Fields{
 			"name": r.Name,
 		}).Debug("installing recipe")
 
-		_, err = i.executeAndValidateWithProgress(ctx, m, &r)
+		err = i.installRecipeWithRetry(ctx, m, &r)
 		if err != nil {
-			if err == types.ErrInterrupt {
+			if isCanceled(err) || err == types.ErrPromptTimeout || isInstallHookError(err) {
 				return err
 			}
 
-			log.Debugf("Failed while executing and validating with progress for recipe name %s, detail:%s", r.Name, err)
-			log.Warn(err)
-			log.Warn(i.failMessage(r.DisplayName))
+			if isInstallTimeout(err) {
+				timeoutErr = err
+				continue
+			}
 
 			if len(recipes) == 1 {
 				return err
 			}
+
+			failed++
+			if i.MaxRecipeFailures > 0 && failed > i.MaxRecipeFailures {
+				return NewErrMaxRecipeFailures(failed, i.MaxRecipeFailures)
+			}
 		}
 		log.Debugf("Done executing and validating with progress for recipe name %s.", r.Name)
 	}
 
+	if timeoutErr != nil {
+		return timeoutErr
+	}
+
+	if i.MaxRecipeFailures > 0 && failed > 0 {
+		log.Warnf("%d of %d recipes failed, within the maximum of %d allowed failures.", failed, len(recipes), i.MaxRecipeFailures)
+	}
+
 	return nil
 }
 
+// installRecipeWithRetry installs a single recipe, offering to retry, skip or
+// abort when it fails.  Failures are skipped without prompting when --assumeYes
+// is set, and retries are capped at maxRecipeRetries.
+func (i *RecipeInstaller) installRecipeWithRetry(ctx context.Context, m *types.DiscoveryManifest, r *types.OpenInstallationRecipe) error {
+	for attempt := 0; ; attempt++ {
+		_, err := i.executeAndValidateWithProgress(ctx, m, r)
+		if err == nil || isCanceled(err) || isInstallTimeout(err) || isInstallHookError(err) {
+			return err
+		}
+
+		log.Debugf("Failed while executing and validating with progress for recipe name %s, detail:%s", r.Name, err)
+		log.Warn(err)
+		log.Warn(i.failMessage(r.DisplayName))
+
+		if i.AssumeYes || attempt >= maxRecipeRetries || ctx.Err() == context.DeadlineExceeded {
+			return err
+		}
+
+		msg := fmt.Sprintf("%s failed to install. What would you like to do?", r.DisplayName)
+		choice, promptErr := i.prompter.Select(msg, recipeFailureOptions, recipeFailureSkip)
+		if promptErr != nil {
+			return promptErr
+		}
+
+		switch choice {
+		case recipeFailureRetry:
+			log.Debugf("Retrying recipe %s, attempt %d", r.Name, attempt+2)
+		case recipeFailureAbort:
+			return NewErrUserCanceled(fmt.Sprintf("aborted after %s failed", r.Name))
+		default:
+			return err
+		}
+	}
+}
+
 func (i *RecipeInstaller) discover(ctx context.Context) (*types.DiscoveryManifest, error) {
 	log.Debug("discovering system information")
 
@@ -208,11 +509,41 @@ func (i *RecipeInstaller) discover(ctx context.Context) (*types.DiscoveryManifes
 		return nil, fmt.Errorf("there was an error discovering system info: %s", err)
 	}
 
+	if i.SaveManifestFile != "" {
+		if err := discovery.SaveManifest(i.SaveManifestFile, *m); err != nil {
+			log.Warn(err)
+		} else {
+			log.Debugf("saved discovery manifest to %s", i.SaveManifestFile)
+		}
+	}
+
+	// The manifest is saved as discovered, so the flags overriding the
+	// detection of a container apply again when installing against it.
+	i.applyContainerAssumption(m)
+
 	return m, nil
 }
 
-func (i *RecipeInstaller) executeAndValidate(ctx context.Context, m *types.DiscoveryManifest, r *types.OpenInstallationRecipe, vars types.RecipeVars) (string, error) {
-	i.status.RecipeInstalling(execution.RecipeStatusEvent{Recipe: *r})
+// executeAndValidate executes the recipe and validates its data.  Reinstall
+// marks a recipe already installed that is being executed again.
+func (i *RecipeInstaller) executeAndValidate(ctx context.Context, m *types.DiscoveryManifest, r *types.OpenInstallationRecipe, vars types.RecipeVars, reinstall bool) (string, error) {
+	// The query is resolved before the recipe runs, so a recipe that could
+	// not be validated is not installed.
+	query, err := validation.ResolveValidationNRQL(*m, *r, vars)
+	if err != nil {
+		i.status.RecipeFailed(execution.RecipeStatusEvent{
+			Recipe: *r,
+			Msg:    err.Error(),
+		})
+		return "", err
+	}
+	r.ValidationNRQL = query
+
+	i.status.RecipeInstalling(execution.RecipeStatusEvent{
+		Recipe:      *r,
+		RecipeVars:  vars,
+		Reinstalled: reinstall,
+	})
 
 	// Execute the recipe steps.
 	if err := i.recipeExecutor.Execute(ctx, *m, *r, vars); err != nil {
@@ -220,28 +551,54 @@ func (i *RecipeInstaller) executeAndValidate(ctx context.Context, m *types.Disco
 			return "", err
 		}
 
-		msg := fmt.Sprintf("encountered an error while executing %s: %s", r.Name, err)
+		err = NewErrExecution(r.Name, err)
 		i.status.RecipeFailed(execution.RecipeStatusEvent{
 			Recipe: *r,
-			Msg:    msg,
+			Msg:    err.Error(),
+		})
+		return "", err
+	}
+
+	if i.DryRun {
+		i.status.RecipeSkipped(execution.RecipeStatusEvent{
+			Recipe: *r,
+			Msg:    dryRunSkippedMsg,
 		})
-		return "", errors.New(msg)
+		return "", nil
 	}
 
 	var entityGUID string
-	var err error
 	var validationDurationMilliseconds int64
 	start := time.Now()
 	if r.ValidationNRQL != "" {
+		if s, ok := i.progressIndicator.(ux.StagedProgressIndicator); ok {
+			s.Validating(i.installingMsg(*r))
+		}
+
+		if i.ObserveValidation > 0 {
+			i.observeValidation(ctx, m, r)
+		}
+
 		entityGUID, err = i.recipeValidator.ValidateRecipe(ctx, *m, *r)
 		if err != nil {
 			validationDurationMilliseconds = time.Since(start).Milliseconds()
 			msg := fmt.Sprintf("encountered an error while validating receipt of data for %s: %s", r.Name, err)
+			var merr validation.ErrAccountMismatch
+			mismatch := errors.As(err, &merr)
+			if mismatch {
+				msg = fmt.Sprintf("data for %s is reporting to the wrong account: %s", r.Name, err)
+			}
 			i.status.RecipeFailed(execution.RecipeStatusEvent{
 				Recipe:                         *r,
 				Msg:                            msg,
 				ValidationDurationMilliseconds: validationDurationMilliseconds,
+				ValidationFailed:               !mismatch && err != types.ErrInterrupt,
 			})
+
+			if errors.Is(err, utilsValidation.ErrMaxAttemptsReached) {
+				return "", NewErrValidationTimeout(r.Name, err)
+			}
+
 			return "", errors.New(msg)
 		}
 	} else {
@@ -253,44 +610,144 @@ func (i *RecipeInstaller) executeAndValidate(ctx context.Context, m *types.Disco
 		Recipe:                         *r,
 		EntityGUID:                     entityGUID,
 		ValidationDurationMilliseconds: validationDurationMilliseconds,
+		RecipeVars:                     vars,
+		Reinstalled:                    reinstall,
 	})
 
 	return entityGUID, nil
 }
 
+// executeAndValidateWithProgress installs the recipe, then runs the
+// post-recipe hook with the outcome.
 func (i *RecipeInstaller) executeAndValidateWithProgress(ctx context.Context, m *types.DiscoveryManifest, r *types.OpenInstallationRecipe) (string, error) {
-	msg := fmt.Sprintf("Installing %s", r.Name)
+	entityGUID, err := i.executeAndValidateRecipeWithProgress(ctx, m, r)
+	return entityGUID, i.runPostRecipeHook(ctx, m, r, err)
+}
+
+func (i *RecipeInstaller) executeAndValidateRecipeWithProgress(ctx context.Context, m *types.DiscoveryManifest, r *types.OpenInstallationRecipe) (string, error) {
+	if err := i.checkInstallTimeout(ctx, r); err != nil {
+		return "", err
+	}
+
+	reinstall := false
+	if i.Reinstall {
+		reinstall, _ = i.detectInstalled(ctx, m, r)
+	} else if installed, entityGUID := i.reportIfAlreadyInstalled(ctx, m, r); installed {
+		return entityGUID, nil
+	}
+
+	if err := i.checkRequiredPorts(m, r); err != nil {
+		return "", err
+	}
+
+	if err := i.checkDiskSpace(m, r); err != nil {
+		return "", err
+	}
+
+	i.checkPackageManagers(m, r)
+
+	msg := i.installingMsg(*r)
 	i.progressIndicator.Start(msg)
 	defer func() { i.progressIndicator.Stop() }()
 
-	if r.PreInstallMessage() != "" {
-		fmt.Println(r.PreInstallMessage())
-	}
+	i.showPreInstallMessage(m, r)
 
 	licenseKey, err := i.licenseKeyFetcher.FetchLicenseKey(ctx)
 	if err != nil {
 		return "", err
 	}
+	i.status.AddSecret(licenseKey)
 
 	vars, err := i.recipeExecutor.Prepare(ctx, *m, *r, i.AssumeYes, licenseKey)
 	if err != nil {
+		var eerr execution.ErrMissingEnvVars
+		if errors.As(err, &eerr) {
+			i.progressIndicator.Fail(msg)
+			i.status.RecipeFailed(execution.RecipeStatusEvent{
+				Recipe: *r,
+				Msg:    err.Error(),
+			})
+		}
 		return "", err
 	}
 
-	entityGUID, err := i.executeAndValidate(ctx, m, r, vars)
+	entityGUID, err := i.executeAndValidate(ctx, m, r, vars, reinstall)
 	if err != nil {
 		i.progressIndicator.Fail(msg)
 		return "", err
 	}
 
-	if r.PostInstallMessage() != "" {
-		fmt.Println(r.PostInstallMessage())
-	}
-
 	i.progressIndicator.Success(msg)
 	return entityGUID, nil
 }
 
+// showPreInstallMessage prints the pre-install message of the recipe before
+// its variables are prompted for, rendered with the variables known for the
+// host up front.
+func (i *RecipeInstaller) showPreInstallMessage(m *types.DiscoveryManifest, r *types.OpenInstallationRecipe) {
+	if i.Quiet {
+		return
+	}
+
+	vars, _ := r.PlatformDefaultVars(*m)
+	msg, err := r.RenderPreInstallMessage(types.InstallMessageData{
+		Manifest: *m,
+		Vars:     vars,
+	})
+	if err != nil {
+		log.Warnf("Could not render the pre-install message for %s: %s", r.Name, err)
+	}
+
+	if msg != "" {
+		fmt.Println(msg)
+	}
+}
+
+// installingMsg is what the progress indicator shows for the recipe.
+func (i *RecipeInstaller) installingMsg(r types.OpenInstallationRecipe) string {
+	return showMessage(i.messages().Installing, messageData{Recipe: r.Name})
+}
+
+// reportIfAlreadyInstalled checks whether the given recipe is already installed
+// and reporting data, and if so reports it as installed without executing it.
+// Callers bypass the check when a reinstall is requested.
+func (i *RecipeInstaller) reportIfAlreadyInstalled(ctx context.Context, m *types.DiscoveryManifest, r *types.OpenInstallationRecipe) (bool, string) {
+	installed, entityGUID := i.detectInstalled(ctx, m, r)
+	if !installed {
+		return false, ""
+	}
+
+	log.WithFields(log.Fields{
+		"name": r.Name,
+		"guid": entityGUID,
+	}).Debug("recipe already installed")
+
+	i.status.RecipeInstalled(execution.RecipeStatusEvent{
+		Recipe:           *r,
+		EntityGUID:       entityGUID,
+		AlreadyInstalled: true,
+	})
+
+	return true, entityGUID
+}
+
+// detectInstalled checks whether the given recipe is already installed and
+// reporting data.  A recipe whose installation cannot be determined is
+// considered not installed.
+func (i *RecipeInstaller) detectInstalled(ctx context.Context, m *types.DiscoveryManifest, r *types.OpenInstallationRecipe) (bool, string) {
+	if i.recipeDetector == nil {
+		return false, ""
+	}
+
+	installed, entityGUID, err := i.recipeDetector.DetectRecipe(ctx, *m, *r)
+	if err != nil {
+		log.Debugf("could not determine whether %s is already installed: %s", r.Name, err)
+		return false, ""
+	}
+
+	return installed, entityGUID
+}
+
 func (i *RecipeInstaller) failMessage(componentName string) error {
 	searchURL := "https://docs.newrelic.com/docs/using-new-relic/cross-product-functions/troubleshooting/not-seeing-data/"
 
@@ -313,14 +770,24 @@ func (i *RecipeInstaller) fetchRecipeAndReportAvailable(ctx context.Context, m *
 }
 
 func (i *RecipeInstaller) fetch(ctx context.Context, m *types.DiscoveryManifest, recipeName string) (*types.OpenInstallationRecipe, error) {
-	r, err := i.recipeFetcher.FetchRecipe(ctx, m, recipeName)
+	return fetchRecipe(ctx, i.recipeFetcher, m, recipeName)
+}
+
+// fetchRecipe fetches the named recipe for the host, failing with an
+// ErrRecipeFetch when it cannot be fetched or is not found.
+func fetchRecipe(ctx context.Context, f recipes.RecipeFetcher, m *types.DiscoveryManifest, recipeName string) (*types.OpenInstallationRecipe, error) {
+	r, err := f.FetchRecipe(ctx, m, recipeName)
 	if err != nil {
 		log.Errorf("error retrieving recipe %s: %s", recipeName, err)
-		return nil, err
+		if isCanceled(err) {
+			return nil, err
+		}
+
+		return nil, NewErrRecipeFetch(recipeName, err)
 	}
 
 	if r == nil {
-		return nil, fmt.Errorf("recipe %s not found", recipeName)
+		return nil, NewErrRecipeFetch(recipeName, recipes.ErrRecipeNotFound)
 	}
 
 	return r, nil
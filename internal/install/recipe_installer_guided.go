@@ -2,12 +2,14 @@ package install
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
 	"github.com/newrelic/newrelic-cli/internal/install/types"
 	"github.com/newrelic/newrelic-cli/internal/utils"
 )
@@ -21,19 +23,38 @@ func (i *RecipeInstaller) guidedInstall(ctx context.Context, m *types.DiscoveryM
 	var selectedIntegrations []types.OpenInstallationRecipe
 	var recommendedIntegrations []types.OpenInstallationRecipe
 
-	// Fetch the infra agent recipe and mark it as available.
-	infraAgentRecipe, err := i.fetchRecipeAndReportAvailable(ctx, m, types.InfraAgentRecipeName)
+	// Fetch the infra agent, logging and recommended recipes, refusing to
+	// install on a platform the infra agent is not available for.
+	q, err := QueryInstallableRecipes(ctx, m, i.recipeFetcher, RecipeQuery{
+		InfraAgentRecipeName: i.infraAgentRecipeName(),
+		LoggingRecipeName:    i.loggingRecipeName(),
+		SkipRecommendations:  i.SkipDiscovery,
+	})
 	if err != nil {
+		log.Debugf("error fetching recipes: %s", err)
 		return err
 	}
+
+	// Mark the infra agent recipe as available.
+	infraAgentRecipe := &q.InfraAgent
+	i.status.RecipeAvailable(*infraAgentRecipe)
 	recipesForInstallation = append(recipesForInstallation, *infraAgentRecipe)
 
-	// Fetch the logging recipe and mark it as available.
-	loggingRecipe, err := i.fetchRecipeAndReportAvailable(ctx, m, types.LoggingRecipeName)
-	if err != nil {
-		return err
+	// The infra agent is required by a guided install, so one the recipe
+	// policy disallows cannot proceed.
+	if reason := i.RecipePolicy.disallowedReason(infraAgentRecipe.Name); reason != "" {
+		return fmt.Errorf("the infrastructure agent is required by a guided installation, but the recipe policy disallows it: %s", reason)
 	}
 
+	// The logging recipe is required by a guided install as well.
+	if q.Logging == nil {
+		return NewErrRecipeFetch(i.loggingRecipeName(), recipes.ErrRecipeNotFound)
+	}
+
+	// Mark the logging recipe as available.
+	loggingRecipe := q.Logging
+	i.status.RecipeAvailable(*loggingRecipe)
+
 	// Warn the user that --skipInfra is only implemented for targetedInstall
 	if i.SkipInfra {
 		return fmt.Errorf("--skipInfra is only applicable to targeted installation. Run newrelic install --help for usage")
@@ -41,29 +62,64 @@ func (i *RecipeInstaller) guidedInstall(ctx context.Context, m *types.DiscoveryM
 
 	// Mark the logging recipe as skipped if necessary.
 	if i.SkipLoggingInstall {
-		i.status.RecipeSkipped(execution.RecipeStatusEvent{Recipe: *loggingRecipe})
+		i.status.RecipeSkipped(execution.RecipeStatusEvent{
+			Recipe: *loggingRecipe,
+			Msg:    "--skipLoggingInstall is set",
+		})
 	} else {
 		recommendedIntegrations = append(recommendedIntegrations, *loggingRecipe)
 	}
 
-	// If necessary, fetch additional integration recommendations from the recipe service.
-	if !i.SkipDiscovery {
-		var recommended []types.OpenInstallationRecipe
-		recommended, err = i.fetchRecommendations(m)
-		if err != nil {
-			log.Debugf("error fetching additional integrations: %s", err)
-			return err
-		}
+	if !i.SkipDiscovery && len(q.Recommended) == 0 {
+		log.Debug("no additional integrations found")
+	}
+	recommendedIntegrations = append(recommendedIntegrations, q.Recommended...)
 
-		if len(recommendedIntegrations) == 0 {
-			log.Debug("no additional integrations found")
+	// Integrations with application targets are reported as recommended once
+	// the host has an entity; others that cannot be installed are skipped.
+	for _, u := range q.Unsupported {
+		if u.Recipe.HasApplicationTargetType() {
+			continue
 		}
 
-		recommendedIntegrations = append(recommendedIntegrations, recommended...)
+		i.status.RecipeSkipped(execution.RecipeStatusEvent{
+			Recipe: u.Recipe,
+			Msg:    "unsupported: " + u.Reason,
+		})
 	}
 
 	// Filter integrations, based on recipe metadata, command flags and prompts.
-	selectedIntegrations, err = i.filterIntegrations(recommendedIntegrations)
+	selectedIntegrations, err = i.filterIntegrations(m, recommendedIntegrations)
+	if err != nil {
+		return err
+	}
+
+	if i.SkipsRecipe(infraAgentRecipe.Name) {
+		log.Warnf("The infrastructure agent is required by a guided installation, so --skip %s is ignored.", infraAgentRecipe.Name)
+	}
+	offered := append([]types.OpenInstallationRecipe{*infraAgentRecipe, *loggingRecipe}, recommendedIntegrations...)
+	for _, u := range q.Unsupported {
+		offered = append(offered, u.Recipe)
+	}
+	warnUnknownSkippedRecipes(i.SkipRecipes, offered)
+
+	// Order the selected integrations after their prerequisites.  The infra
+	// agent is always installed first, and skipped logging cannot be added.
+	provided := []string{infraAgentRecipe.Name}
+	unavailable := []string{}
+	if i.ShouldInstallLogging() {
+		provided = append(provided, loggingRecipe.Name)
+	} else {
+		unavailable = append(unavailable, loggingRecipe.Name)
+	}
+
+	selectedIntegrations, err = i.withPrerequisites(ctx, m, selectedIntegrations, provided, unavailable)
+	if err != nil {
+		return err
+	}
+
+	// Ensure no two selected integrations conflict with each other.
+	selectedIntegrations, err = i.resolveConflicts(selectedIntegrations)
 	if err != nil {
 		return err
 	}
@@ -78,18 +134,42 @@ func (i *RecipeInstaller) guidedInstall(ctx context.Context, m *types.DiscoveryM
 	// Remove logging from the integrations list since it will be installed explicitly.
 	selectedIntegrations = i.removeRecipes(selectedIntegrations, *loggingRecipe)
 
+	// Confirm the plan, in execution order, before anything is installed.
+	if err = i.confirmInstallPlan(m, i.guidedInstallOrder(infraAgentRecipe, loggingRecipe, selectedIntegrations)); err != nil {
+		return err
+	}
+
+	// Notify the user of monitoring agents the infra agent may overlap with.
+	if err = i.confirmMonitoringAgents(m); err != nil {
+		return err
+	}
+
+	// Guard against installing the infra agent inside a container.
+	if err = i.confirmContainerInstall(m); err != nil {
+		return err
+	}
+
 	// Install the infra agent.
 	log.Debugf("Installing infrastructure agent")
 	entityGUID, err := i.executeAndValidateWithProgress(ctx, m, infraAgentRecipe)
+	infraFailed := err != nil
 	if err != nil {
-		log.Error(i.failMessage(types.InfraAgentRecipeName))
-		return err
+		if isInstallTimeout(err) {
+			i.skipTimedOutRecipes(i.guidedInstallOrder(infraAgentRecipe, loggingRecipe, selectedIntegrations)[1:]...)
+			return err
+		}
+
+		if !i.continueAfterInfraFailure(err) {
+			log.Error(i.failMessage(i.infraAgentRecipeName()))
+			return err
+		}
+	} else {
+		log.Debugf("Done installing infrastructure agent.")
 	}
-	log.Debugf("Done installing infrastructure agent.")
 
 	// Now that we have a host entity GUID, report recommended integrations
 	// with application targets for that host.
-	for _, r := range recommendedIntegrations {
+	for _, r := range offered {
 		if r.HasApplicationTargetType() {
 			i.status.RecipeRecommended(execution.RecipeStatusEvent{
 				Recipe:     r,
@@ -102,33 +182,99 @@ func (i *RecipeInstaller) guidedInstall(ctx context.Context, m *types.DiscoveryM
 	if i.ShouldInstallLogging() {
 		log.Debugf("Installing logging")
 		if err = i.installLogging(ctx, m, loggingRecipe, recipesForInstallation); err != nil {
-			log.Error(i.failMessage(types.LoggingRecipeName))
-			return err
+			if isInstallTimeout(err) {
+				if i.ShouldInstallIntegrations() {
+					i.skipTimedOutRecipes(selectedIntegrations...)
+				}
+				return err
+			}
+
+			// Logging is forwarded by the infrastructure agent, so its failure
+			// is expected when the agent failed to install.
+			if !infraFailed || isCanceled(err) || err == types.ErrPromptTimeout || isInstallHookError(err) {
+				log.Error(i.failMessage(i.loggingRecipeName()))
+				return err
+			}
+
+			log.Warnf("Logging failed to install without the infrastructure agent, continuing: %s", err)
+		} else {
+			log.Debugf("Done installing logging.")
 		}
-		log.Debugf("Done installing logging.")
 	}
 
 	// Install integrations if necessary, continuing on failure with warnings.
 	if i.ShouldInstallIntegrations() {
 		log.Debugf("Installing integrations")
-		if err = i.installRecipes(ctx, m, selectedIntegrations); err != nil {
-			if err == types.ErrInterrupt {
-				return err
-			}
+		if err = i.installIntegrations(ctx, m, selectedIntegrations); err != nil {
+			return err
+		}
 
-			return nil
+		// Offer the integrations recommended once those installed are running.
+		if i.IterativeRecommendations && !i.SkipDiscovery {
+			return i.installIterativeRecommendations(ctx, m, offered)
 		}
-		log.Debugf("Done installing integrations.")
 	}
 
 	return nil
 }
 
+// installIntegrations installs the given integrations, continuing on failure
+// with warnings.  Only errors that end the install are returned.
+func (i *RecipeInstaller) installIntegrations(ctx context.Context, m *types.DiscoveryManifest, recipes []types.OpenInstallationRecipe) error {
+	if err := i.installRecipes(ctx, m, recipes); err != nil {
+		if isCanceled(err) || err == types.ErrPromptTimeout || isInstallTimeout(err) || isInstallHookError(err) {
+			return err
+		}
+
+		var ferr ErrMaxRecipeFailures
+		if errors.As(err, &ferr) {
+			return err
+		}
+
+		return nil
+	}
+	log.Debugf("Done installing integrations.")
+
+	return nil
+}
+
+// continueAfterInfraFailure returns whether the guided install continues after
+// the infrastructure agent failed to install, which it does with a warning when
+// --continue-on-infra-failure is set.  The failure is recorded in the install
+// status either way.  Cancellations always stop the install.
+func (i *RecipeInstaller) continueAfterInfraFailure(err error) bool {
+	if !i.ContinueOnInfraFailure || isCanceled(err) || err == types.ErrPromptTimeout || isInstallHookError(err) {
+		return false
+	}
+
+	log.Warnf("The infrastructure agent failed to install: %s", err)
+	log.Warn("Continuing with the remaining recipes since --continue-on-infra-failure is set. " +
+		"Integrations report data through the infrastructure agent, so their validation only succeeds if an agent managed separately is running on this host.")
+
+	return true
+}
+
+// guidedInstallOrder returns the recipes of a guided install in the order in
+// which they are executed.
+func (i *RecipeInstaller) guidedInstallOrder(infraAgentRecipe *types.OpenInstallationRecipe, loggingRecipe *types.OpenInstallationRecipe, integrations []types.OpenInstallationRecipe) []types.OpenInstallationRecipe {
+	recipes := []types.OpenInstallationRecipe{*infraAgentRecipe}
+
+	if i.ShouldInstallLogging() {
+		recipes = append(recipes, *loggingRecipe)
+	}
+
+	if i.ShouldInstallIntegrations() {
+		recipes = append(recipes, integrations...)
+	}
+
+	return recipes
+}
+
 func (i *RecipeInstaller) installLogging(ctx context.Context, m *types.DiscoveryManifest, r *types.OpenInstallationRecipe, recipes []types.OpenInstallationRecipe) error {
 	log.WithFields(log.Fields{
 		"recipe_count": len(recipes),
 	}).Debug("filtering log matches")
-	logMatches, err := i.fileFilterer.Filter(utils.SignalCtx, recipes)
+	logMatches, err := i.fileFilterer.Filter(utils.SignalCtx, expandContainerLogMatches(m, recipes))
 	if err != nil {
 		return err
 	}
@@ -172,14 +318,30 @@ func (i *RecipeInstaller) installLogging(ctx context.Context, m *types.Discovery
 }
 
 func (i *RecipeInstaller) fetchRecommendations(m *types.DiscoveryManifest) ([]types.OpenInstallationRecipe, error) {
+	return fetchRecommendedRecipes(utils.SignalCtx, i.recipeFetcher, m, i.infraAgentRecipeName(), i.loggingRecipeName())
+}
+
+// fetchRecommendedRecipes fetches the recipes recommended for the host, other
+// than the infrastructure agent and logging recipes of the given names, which
+// are installed explicitly.  Should only some recommendations be retrieved,
+// those are returned.
+func fetchRecommendedRecipes(ctx context.Context, f recipes.RecipeFetcher, m *types.DiscoveryManifest, infraAgentRecipeName string, loggingRecipeName string) ([]types.OpenInstallationRecipe, error) {
 	log.Debug("fetching recommended recipes")
 
-	recommendations, err := i.recipeFetcher.FetchRecommendations(utils.SignalCtx, m)
-	if err != nil {
-		return nil, fmt.Errorf("error retrieving recipe recommendations: %s", err)
+	recommendations, err := f.FetchRecommendations(ctx, m)
+
+	var perr recipes.ErrPartialRecommendations
+	if errors.As(err, &perr) {
+		log.Warnf("Only some recipe recommendations could be retrieved, continuing with those: %s", perr)
+	} else if err != nil {
+		if isCanceled(err) {
+			return nil, err
+		}
+
+		return nil, NewErrRecipeFetch("", err)
 	}
 
-	recommendations = i.filterRecommendations(recommendations)
+	recommendations = filterRecommendations(recommendations, infraAgentRecipeName, loggingRecipeName)
 
 	if log.IsLevelEnabled(log.DebugLevel) {
 		names := []string{}
@@ -198,10 +360,10 @@ func (i *RecipeInstaller) fetchRecommendations(m *types.DiscoveryManifest) ([]ty
 
 // Filter out infra and logging recipes from recommendations, since they are
 // handled explicitly elsewhere.  This avoids duplicate installation.
-func (i *RecipeInstaller) filterRecommendations(recipes []types.OpenInstallationRecipe) []types.OpenInstallationRecipe {
+func filterRecommendations(recipes []types.OpenInstallationRecipe, infraAgentRecipeName string, loggingRecipeName string) []types.OpenInstallationRecipe {
 	filteredRecommendations := []types.OpenInstallationRecipe{}
 	for _, r := range recipes {
-		if r.Name == types.InfraAgentRecipeName || r.Name == types.LoggingRecipeName {
+		if r.Name == infraAgentRecipeName || r.Name == loggingRecipeName {
 			log.WithFields(log.Fields{
 				"name": r.Name,
 			}).Debug("skipping redundant recipe")
@@ -215,6 +377,18 @@ func (i *RecipeInstaller) filterRecommendations(recipes []types.OpenInstallation
 	return filteredRecommendations
 }
 
+// alreadyInstalled reports the recipe as installed when it already is, unless
+// a reinstall is requested.
+func (i *RecipeInstaller) alreadyInstalled(m *types.DiscoveryManifest, r *types.OpenInstallationRecipe) bool {
+	if i.Reinstall {
+		return false
+	}
+
+	installed, _ := i.reportIfAlreadyInstalled(utils.SignalCtx, m, r)
+
+	return installed
+}
+
 func (i *RecipeInstaller) userAccepts(msg string) (bool, error) {
 	if i.AssumeYes {
 		return true, nil
@@ -228,9 +402,16 @@ func (i *RecipeInstaller) userAccepts(msg string) (bool, error) {
 	return val, nil
 }
 
-func (i *RecipeInstaller) userAcceptsLogFile(match types.OpenInstallationLogMatch) (bool, error) {
-	msg := fmt.Sprintf("Files have been found at the following pattern: %s Do you want to watch them?", match.File)
-	return i.userAccepts(msg)
+// expandContainerLogMatches returns the recipes with references to container
+// log roots in their log matches replaced by those discovered on the host.
+func expandContainerLogMatches(m *types.DiscoveryManifest, recipes []types.OpenInstallationRecipe) []types.OpenInstallationRecipe {
+	expanded := make([]types.OpenInstallationRecipe, len(recipes))
+	for n, r := range recipes {
+		r.LogMatch = m.ExpandLogMatches(r.LogMatch)
+		expanded[n] = r
+	}
+
+	return expanded
 }
 
 func (i *RecipeInstaller) recipeInRecipes(recipe types.OpenInstallationRecipe, recipes []types.OpenInstallationRecipe) bool {
@@ -258,27 +439,86 @@ func (i *RecipeInstaller) removeRecipes(recipes []types.OpenInstallationRecipe,
 
 // filterIntegration has several purposes:
 //   - create a filtered list of install candidates based on command flags and user prompt input
+//   - mark recipes as SKIPPED when the recipe policy disallows them
 //   - mark recipes as SKIPPED based on the SkipIntegrations command flag
 //   - mark recipes as SKIPPED if designated by user prompt input
 //   - ensure the logging recipe is skipped if designated by user prompt input
 //   - filter out recipes with APPLICATION target types
-func (i *RecipeInstaller) filterIntegrations(recommendedIntegrations []types.OpenInstallationRecipe) ([]types.OpenInstallationRecipe, error) {
+//   - mark recipes as INSTALLED if they are already present and reporting data
+func (i *RecipeInstaller) filterIntegrations(m *types.DiscoveryManifest, recommendedIntegrations []types.OpenInstallationRecipe) ([]types.OpenInstallationRecipe, error) {
+	return i.filterIntegrationsWithIntro(m, recommendedIntegrations, showMessage(i.messages().GuidedInstallIntro, messageData{}))
+}
+
+// filterIntegrationsWithIntro filters integrations like filterIntegrations,
+// showing the given introduction before prompting for the integrations to install.
+func (i *RecipeInstaller) filterIntegrationsWithIntro(m *types.DiscoveryManifest, recommendedIntegrations []types.OpenInstallationRecipe, intro string) ([]types.OpenInstallationRecipe, error) {
 	installCandidates := []types.OpenInstallationRecipe{}
-	for _, r := range recommendedIntegrations {
+	tagIncluded, tagExcluded := 0, 0
+	for _, r := range byName(recommendedIntegrations) {
 		if r.HasApplicationTargetType() && !r.IsApm() {
 			// do nothing
+		} else if i.skipIfDisallowed(r) {
+			if r.Name == i.loggingRecipeName() {
+				i.SkipLoggingInstall = true
+			}
+		} else if i.SkipsRecipe(r.Name) {
+			i.status.RecipeSkipped(execution.RecipeStatusEvent{
+				Recipe: r,
+				Msg:    "--skip is set for " + r.Name,
+			})
+
+			if r.Name == i.loggingRecipeName() {
+				i.SkipLoggingInstall = true
+			}
 		} else if i.SkipIntegrations {
-			i.status.RecipeSkipped(execution.RecipeStatusEvent{Recipe: r})
+			i.status.RecipeSkipped(execution.RecipeStatusEvent{
+				Recipe: r,
+				Msg:    "--skipIntegrations is set",
+			})
 		} else if i.SkipApm && r.IsApm() {
-			i.status.RecipeSkipped(execution.RecipeStatusEvent{Recipe: r})
+			i.status.RecipeSkipped(execution.RecipeStatusEvent{
+				Recipe: r,
+				Msg:    "--skipApm is set",
+			})
+		} else if reason := i.unentitledReason(utils.SignalCtx, r); reason != "" {
+			i.status.RecipeSkipped(execution.RecipeStatusEvent{
+				Recipe: r,
+				Msg:    "unsupported: " + reason,
+			})
+
+			if r.Name == i.loggingRecipeName() {
+				i.SkipLoggingInstall = true
+			}
+		} else if !i.MatchesTagFilters(r) {
+			tagExcluded++
+			i.status.RecipeSkipped(execution.RecipeStatusEvent{
+				Recipe: r,
+				Msg:    fmt.Sprintf("%s does not match the tag filters", recipeDisplayName(r)),
+			})
+
+			if r.Name == i.loggingRecipeName() {
+				i.SkipLoggingInstall = true
+			}
+		} else if i.alreadyInstalled(m, &r) {
+			if r.Name == i.loggingRecipeName() {
+				i.SkipLoggingInstall = true
+			}
 		} else {
+			tagIncluded++
 			installCandidates = append(installCandidates, r)
 		}
 	}
 
+	if i.TagFiltersProvided() && !i.Quiet {
+		fmt.Printf("%s\n\n", showMessage(i.messages().TagFilterSummary, messageData{Included: tagIncluded, Excluded: tagExcluded}))
+	}
+
+	// Candidates are in name order, which the stable sorts by relevance and
+	// requirement keep among equals.
+	installCandidates = requiredFirst(byRelevance(m, installCandidates))
 	installCandidateNames := []string{}
 	for _, r := range installCandidates {
-		installCandidateNames = append(installCandidateNames, r.DisplayName)
+		installCandidateNames = append(installCandidateNames, integrationOptionName(r))
 	}
 
 	var selectedIntegrationNames []string
@@ -286,21 +526,38 @@ func (i *RecipeInstaller) filterIntegrations(recommendedIntegrations []types.Ope
 		// When -y is supplied, select all the recipes that were in the report for install.
 		selectedIntegrationNames = installCandidateNames
 	} else if len(installCandidateNames) > 0 {
-		fmt.Printf("The guided installation will begin by installing the latest version of the New Relic Infrastructure agent, which is required for additional instrumentation.\n\n")
+		fmt.Printf("%s\n\n", intro)
+
+		// Candidates outside the categories chosen are not offered, and
+		// are skipped as not selected.
+		offered, promptErr := i.selectCategories(installCandidates)
+		if promptErr != nil {
+			return nil, promptErr
+		}
 
-		var promptErr error
-		selectedIntegrationNames, promptErr = i.prompter.MultiSelect("Please choose from the additional recommended instrumentation to be installed:", installCandidateNames)
+		offeredNames := []string{}
+		for _, r := range offered {
+			offeredNames = append(offeredNames, integrationOptionName(r))
+		}
+
+		defaults := integrationOptionDefaults(offered, i.previousSelectionDefaults(m, offered))
+
+		selectedIntegrationNames, promptErr = i.selectIntegrations(offeredNames, defaults)
 		if promptErr != nil {
 			return nil, promptErr
 		}
 
+		warnDeselectedRequired(offered, selectedIntegrationNames)
+
+		i.saveSelection(m, offered, selectedIntegrationNames)
+
 		fmt.Println()
 	}
 
 	var integrationsForInstall []types.OpenInstallationRecipe
 	for _, selectedIntegrationName := range selectedIntegrationNames {
-		for _, r := range recommendedIntegrations {
-			if r.DisplayName == selectedIntegrationName {
+		for _, r := range installCandidates {
+			if integrationOptionName(r) == selectedIntegrationName {
 				integrationsForInstall = append(integrationsForInstall, r)
 			}
 		}
@@ -309,9 +566,12 @@ func (i *RecipeInstaller) filterIntegrations(recommendedIntegrations []types.Ope
 	log.Debug("skipping recipes that were not selected")
 	for _, r := range installCandidates {
 		if !i.recipeInRecipes(r, integrationsForInstall) {
-			i.status.RecipeSkipped(execution.RecipeStatusEvent{Recipe: r})
+			i.status.RecipeSkipped(execution.RecipeStatusEvent{
+				Recipe: r,
+				Msg:    "not selected",
+			})
 
-			if r.Name == types.LoggingRecipeName {
+			if r.Name == i.loggingRecipeName() {
 				i.SkipLoggingInstall = true
 			}
 		}
@@ -319,3 +579,90 @@ func (i *RecipeInstaller) filterIntegrations(recommendedIntegrations []types.Ope
 
 	return integrationsForInstall, nil
 }
+
+// selectIntegrations prompts for the recommended integrations to install.  An
+// empty selection is confirmed, with the option to choose again, since it
+// leaves only the infrastructure agent to be installed.
+func (i *RecipeInstaller) selectIntegrations(candidateNames []string, defaults []string) ([]string, error) {
+	for {
+		selected, err := i.prompter.MultiSelect(showMessage(i.messages().SelectIntegrations, messageData{}), candidateNames, defaults)
+		if err != nil || len(selected) > 0 {
+			return selected, err
+		}
+
+		choice, err := i.prompter.Select(showMessage(i.messages().ConfirmEmptySelection, messageData{}), emptySelectionOptions, emptySelectionContinue)
+		if err != nil {
+			return nil, err
+		}
+
+		if choice != emptySelectionChooseAgain {
+			return selected, nil
+		}
+
+		log.Debug("reopening the integration selection")
+	}
+}
+
+// previousSelectionDefaults returns the display names to pre-select based on
+// the selection saved during a previous run on this host.  Candidates the user
+// has not seen before are pre-selected, while previously declined ones are not.
+// A nil result pre-selects every candidate.
+func (i *RecipeInstaller) previousSelectionDefaults(m *types.DiscoveryManifest, candidates []types.OpenInstallationRecipe) []string {
+	if i.selectionStore == nil {
+		return nil
+	}
+
+	if i.ResetSelections {
+		if err := i.selectionStore.Reset(); err != nil {
+			log.Debugf("could not reset saved recipe selections: %s", err)
+		}
+
+		return nil
+	}
+
+	previous, err := i.selectionStore.Load(m.Fingerprint())
+	if err != nil {
+		log.Debugf("could not load saved recipe selections: %s", err)
+		return nil
+	}
+
+	if previous == nil {
+		return nil
+	}
+
+	defaults := []string{}
+	for _, r := range candidates {
+		if !utils.StringInSlice(r.Name, previous.Declined) {
+			defaults = append(defaults, r.DisplayName)
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"defaults": defaults,
+	}).Debug("restored previous recipe selection")
+
+	return defaults
+}
+
+func (i *RecipeInstaller) saveSelection(m *types.DiscoveryManifest, candidates []types.OpenInstallationRecipe, selectedNames []string) {
+	if i.selectionStore == nil {
+		return
+	}
+
+	selection := RecipeSelection{
+		Selected: []string{},
+		Declined: []string{},
+	}
+
+	for _, r := range candidates {
+		if utils.StringInSlice(integrationOptionName(r), selectedNames) {
+			selection.Selected = append(selection.Selected, r.Name)
+		} else {
+			selection.Declined = append(selection.Declined, r.Name)
+		}
+	}
+
+	if err := i.selectionStore.Save(m.Fingerprint(), selection); err != nil {
+		log.Debugf("could not save recipe selections: %s", err)
+	}
+}
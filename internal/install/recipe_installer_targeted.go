@@ -7,31 +7,11 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
 	"github.com/newrelic/newrelic-cli/internal/install/types"
 	"github.com/newrelic/newrelic-cli/internal/utils"
 )
 
-func (i *RecipeInstaller) resolveRecipeDependencies(ctx context.Context, recipe types.OpenInstallationRecipe, manifest *types.DiscoveryManifest) ([]*types.OpenInstallationRecipe, error) {
-	dependencies := []*types.OpenInstallationRecipe{}
-
-	if len(recipe.Dependencies) == 0 {
-		return dependencies, nil
-	}
-
-	for _, recipeName := range recipe.Dependencies {
-		recipe, err := i.fetchRecipeAndReportAvailable(ctx, manifest, recipeName)
-		if err != nil {
-			return dependencies, err
-		}
-
-		if recipe != nil {
-			dependencies = append(dependencies, recipe)
-		}
-	}
-
-	return dependencies, nil
-}
-
 func (i *RecipeInstaller) collectRecipes(m *types.DiscoveryManifest) ([]types.OpenInstallationRecipe, error) {
 	var recipes []types.OpenInstallationRecipe
 
@@ -39,7 +19,7 @@ func (i *RecipeInstaller) collectRecipes(m *types.DiscoveryManifest) ([]types.Op
 		// Load the recipes from the provided file names.
 		for _, n := range i.RecipePaths {
 			// Early continue when skipInfra is set
-			if i.SkipInfra && n == types.InfraAgentRecipeName {
+			if i.SkipInfra && n == i.infraAgentRecipeName() {
 				continue
 			}
 
@@ -62,7 +42,7 @@ func (i *RecipeInstaller) collectRecipes(m *types.DiscoveryManifest) ([]types.Op
 		// Fetch the provided recipes from the recipe service.
 		for _, n := range i.RecipeNames {
 			// Early continue when skipInfra is set
-			if i.SkipInfra && n == types.InfraAgentRecipeName {
+			if i.SkipInfra && n == i.infraAgentRecipeName() {
 				continue
 			}
 
@@ -84,8 +64,6 @@ func (i *RecipeInstaller) collectRecipes(m *types.DiscoveryManifest) ([]types.Op
 }
 
 func (i *RecipeInstaller) targetedInstall(ctx context.Context, m *types.DiscoveryManifest) error {
-	var recipes []types.OpenInstallationRecipe
-
 	i.status.SetTargetedInstall()
 
 	providedRecipes, err := i.collectRecipes(m)
@@ -93,26 +71,47 @@ func (i *RecipeInstaller) targetedInstall(ctx context.Context, m *types.Discover
 		return err
 	}
 
-	for _, r := range providedRecipes {
-		dependencies, err := i.resolveRecipeDependencies(ctx, r, m)
-		if err != nil {
-			return err
-		}
+	warnUnknownSkippedRecipes(i.SkipRecipes, providedRecipes)
+	providedRecipes = i.skipNamedRecipes(providedRecipes)
+	providedRecipes = i.skipDisallowedRecipes(providedRecipes)
+	providedRecipes = i.skipUnentitledRecipes(ctx, providedRecipes)
 
-		for _, d := range dependencies {
-			if i.SkipInfra && types.InfraAgentRecipeName == d.Name {
-				continue
-			} else {
-				recipes = append(recipes, *d)
-			}
-		}
-		recipes = append(recipes, r)
+	// Order the requested recipes after their prerequisites.  A skipped infra
+	// agent is assumed to be installed already.
+	provided := []string{}
+	if i.SkipInfra || i.SkipsRecipe(i.infraAgentRecipeName()) {
+		provided = append(provided, i.infraAgentRecipeName())
+	}
+
+	recipes, err := i.withPrerequisites(ctx, m, providedRecipes, provided, nil)
+	if err != nil {
+		return err
+	}
+	// Ensure no two requested recipes conflict with each other.
+	recipes, err = i.resolveConflicts(recipes)
+	if err != nil {
+		return err
 	}
 
 	// Show the user what will be installed.
 	i.status.RecipesAvailable(recipes)
 	i.status.RecipesSelected(recipes)
 
+	// Confirm the plan, including resolved dependencies, before installing.
+	if err := i.confirmInstallPlan(m, recipes); err != nil {
+		return err
+	}
+
+	// Guard against installing the infra agent inside a container.
+	for _, r := range recipes {
+		if r.Name == i.infraAgentRecipeName() {
+			if err := i.confirmContainerInstall(m); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
 	// Install the requested integrations.
 	log.Debugf("Installing integrations")
 	if err := i.installRecipes(ctx, m, recipes); err != nil {
@@ -163,3 +162,40 @@ func (i *RecipeInstaller) fetchWarn(m *types.DiscoveryManifest, recipeName strin
 
 	return r
 }
+
+// skipNamedRecipes reports the recipes named with --skip as skipped, returning
+// the others.
+func (i *RecipeInstaller) skipNamedRecipes(recipes []types.OpenInstallationRecipe) []types.OpenInstallationRecipe {
+	remaining := []types.OpenInstallationRecipe{}
+	for _, r := range recipes {
+		if i.SkipsRecipe(r.Name) {
+			i.status.RecipeSkipped(execution.RecipeStatusEvent{
+				Recipe: r,
+				Msg:    "--skip is set for " + r.Name,
+			})
+			continue
+		}
+
+		remaining = append(remaining, r)
+	}
+
+	return remaining
+}
+
+// warnUnknownSkippedRecipes warns of the names given with --skip that match
+// none of the recipes considered for installation.
+func warnUnknownSkippedRecipes(names []string, recipes []types.OpenInstallationRecipe) {
+	for _, n := range names {
+		known := false
+		for _, r := range recipes {
+			if r.Name == n {
+				known = true
+				break
+			}
+		}
+
+		if !known {
+			log.Warnf("--skip %s does not match any recipe being considered for installation, ignoring it.", n)
+		}
+	}
+}
@@ -3,22 +3,28 @@
 package install
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"io/ioutil"
 	"net/url"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/newrelic/newrelic-cli/internal/credentials"
 	"github.com/newrelic/newrelic-cli/internal/install/discovery"
 	"github.com/newrelic/newrelic-cli/internal/install/execution"
 	"github.com/newrelic/newrelic-cli/internal/install/recipes"
 	"github.com/newrelic/newrelic-cli/internal/install/types"
 	"github.com/newrelic/newrelic-cli/internal/install/ux"
 	"github.com/newrelic/newrelic-cli/internal/install/validation"
+	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
 )
 
 var (
@@ -40,6 +46,9 @@ var (
 	p               = ux.NewMockPrompter()
 	pi              = ux.NewMockProgressIndicator()
 	lkf             = NewMockLicenseKeyFetcher()
+	ss              = NewMockSelectionStore()
+	rd              = validation.NewMockRecipeDetector()
+	cr              = execution.NewCleanupRegistry()
 )
 
 func TestInstall(t *testing.T) {
@@ -56,16 +65,29 @@ func TestNewRecipeInstaller_InstallerContextFields(t *testing.T) {
 		SkipApm:            true,
 	}
 
-	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf}
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
 
 	require.True(t, reflect.DeepEqual(ic, i.InstallerContext))
 }
 
+func TestStartStatusReporters(t *testing.T) {
+	ic := InstallerContext{}
+	ic.metrics = execution.NewMetricsStatusReporter("127.0.0.1:0")
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	require.Empty(t, ic.metrics.Addr())
+
+	i.startStatusReporters()
+	defer ic.metrics.Stop()
+
+	require.NotEmpty(t, ic.metrics.Addr())
+}
+
 func TestShouldGetRecipeFromURL(t *testing.T) {
 	ic := InstallerContext{}
 	ff = recipes.NewMockRecipeFileFetcher()
 	ff.FetchRecipeFileFunc = fetchRecipeFileFunc
-	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf}
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
 
 	recipe, err := i.recipeFromPath("http://recipe/URL")
 	require.NoError(t, err)
@@ -77,7 +99,7 @@ func TestShouldGetRecipeFromFile(t *testing.T) {
 	ic := InstallerContext{}
 	ff = recipes.NewMockRecipeFileFetcher()
 	ff.LoadRecipeFileFunc = loadRecipeFileFunc
-	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf}
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
 
 	recipe, err := i.recipeFromPath("file.txt")
 	require.NoError(t, err)
@@ -92,7 +114,7 @@ func TestInstall_Basic(t *testing.T) {
 		{Name: types.InfraAgentRecipeName},
 		{Name: types.LoggingRecipeName},
 	}
-	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf}
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
 	err := i.Install()
 	require.NoError(t, err)
 	require.Equal(t, f.FetchRecipeNameCount[types.InfraAgentRecipeName], 1)
@@ -112,7 +134,7 @@ func TestInstall_DiscoveryComplete(t *testing.T) {
 		},
 	}
 
-	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf}
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
 
 	err := i.Install()
 	require.NoError(t, err)
@@ -135,7 +157,7 @@ func TestInstall_FailsOnInvalidOs(t *testing.T) {
 		},
 	}
 
-	i := RecipeInstaller{ic, discover, l, mv, f, e, v, ff, status, p, pi, lkf}
+	i := RecipeInstaller{ic, discover, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
 
 	err := i.Install()
 	require.Error(t, err)
@@ -163,7 +185,7 @@ func TestInstall_RecipesAvailable(t *testing.T) {
 		},
 	}
 
-	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf}
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
 	err := i.Install()
 	require.NoError(t, err)
 	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipesAvailableCallCount)
@@ -205,7 +227,7 @@ func TestInstall_RecipeInstalled(t *testing.T) {
 
 	v = validation.NewMockRecipeValidator()
 
-	i := RecipeInstaller{ic, d, l, mv, f2, e, v, ff, status, p, pi, lkf}
+	i := RecipeInstaller{ic, d, l, mv, f2, e, v, ff, status, p, pi, lkf, ss, rd, cr}
 	err := i.Install()
 	require.NoError(t, err)
 	require.Equal(t, 3, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
@@ -244,7 +266,7 @@ func TestInstall_RecipeFailed(t *testing.T) {
 	v = validation.NewMockRecipeValidator()
 	v.ValidateErr = errors.New("validationErr")
 
-	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf}
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
 	err := i.Install()
 	require.Error(t, err)
 	require.Equal(t, 1, v.ValidateCallCount)
@@ -269,7 +291,7 @@ func TestInstall_InstallComplete(t *testing.T) {
 
 	v = validation.NewMockRecipeValidator()
 
-	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf}
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
 	err := i.Install()
 	require.NoError(t, err)
 	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).InstallCompleteCallCount)
@@ -288,137 +310,204 @@ func TestInstall_InstallCanceled(t *testing.T) {
 
 	v = validation.NewMockRecipeValidator()
 
-	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf}
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
 	err := i.Install()
 	require.Error(t, err)
 	require.Equal(t, 0, statusReporters[0].(*execution.MockStatusReporter).InstallCompleteCallCount)
 	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).InstallCanceledCallCount)
 }
 
-func TestInstall_InstallCompleteError(t *testing.T) {
+func TestInstall_InterruptedSelectionCancelsInstall(t *testing.T) {
 	ic := InstallerContext{
 		SkipLoggingInstall: true,
 	}
 	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
 	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
 	f = recipes.NewMockRecipeFetcher()
-	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{}
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{Name: "mysql"}}
 	f.FetchRecipeVals = []types.OpenInstallationRecipe{
-		{
-			Name:           types.InfraAgentRecipeName,
-			ValidationNRQL: "testNrql",
-		},
+		{Name: types.InfraAgentRecipeName},
+		{Name: types.LoggingRecipeName},
 	}
 
-	p = &ux.MockPrompter{
-		PromptYesNoVal: true,
+	e := execution.NewMockRecipeExecutor()
+	p := ux.NewMockPrompter()
+	p.PromptMultiSelectErr = types.ErrInterrupt
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.ErrorIs(t, err, types.ErrInterrupt)
+	require.Equal(t, 1, p.PromptMultiSelectCallCount)
+	require.Equal(t, 0, e.ExecuteCallCount)
+	require.Equal(t, 0, statusReporters[0].(*execution.MockStatusReporter).InstallCompleteCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).InstallCanceledCallCount)
+}
+
+func TestInstall_InstallCanceledCleansUp(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeErr = types.ErrInterrupt
+
+	// A task file left behind by a recipe interrupted mid-run.
+	tmp, err := ioutil.TempFile("", "taskfile")
+	require.NoError(t, err)
+	tmp.Close()
+
+	cleanup := execution.NewCleanupRegistry()
+	cleanup.Register(tmp.Name())
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cleanup}
+	err = i.Install()
+	require.Equal(t, types.ErrInterrupt, err)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).InstallCanceledCallCount)
+	require.Equal(t, 0, cleanup.Pending())
+
+	_, err = os.Stat(tmp.Name())
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestInstall_RecipeFetcherAuthError(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
 	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeErr = nrErrors.NewUnauthorizedError()
 
 	v = validation.NewMockRecipeValidator()
-	v.ValidateErr = errors.New("test error")
 
-	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf}
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
 	err := i.Install()
 	require.Error(t, err)
-	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).InstallCompleteCallCount)
-	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeFailedCallCount)
+
+	var aerr ErrAuthentication
+	require.True(t, errors.As(err, &aerr))
+	require.False(t, aerr.Forbidden())
 }
 
-// TestInstall_InstallCompleteError_guidedRecipeFail ensures that when we
-// perform a guided install, that we only exit with an error if the infra-agent
-// recipe fails.  If a recommended recipe fails, a log is emittd, but we do not
-// want the error being returned.
-// https://newrelic.atlassian.net/browse/VIRTUOSO-454
-func TestInstall_InstallCompleteError_guidedRecipeFail(t *testing.T) {
+func TestInstall_UnsupportedPlatform(t *testing.T) {
+	ic := InstallerContext{}
+	discover := discovery.NewMockDiscovererWithManifest(types.DiscoveryManifest{
+		OS:              "linux",
+		Platform:        "alpine",
+		PlatformVersion: "3.15",
+		KernelArch:      "s390x",
+	})
+	statusReporter := execution.NewMockStatusReporter()
+	statusReporters = []execution.StatusSubscriber{statusReporter}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeErr = recipes.NewErrRecipeNotFoundMessage("infrastructure agent was unable to be installed for your operating system")
+	e = execution.NewMockRecipeExecutor()
+
+	i := RecipeInstaller{ic, discover, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.Error(t, err)
+
+	var perr ErrUnsupportedPlatform
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, "linux", perr.OS)
+	require.Equal(t, "s390x", perr.KernelArch)
+	require.Contains(t, err.Error(), "this platform is not supported")
+	require.Contains(t, err.Error(), "linux/s390x (alpine 3.15)")
+	require.Equal(t, 1, f.FetchRecipeNameCount[types.InfraAgentRecipeName])
+	require.Equal(t, 0, f.FetchRecipeNameCount[types.LoggingRecipeName])
+	require.Equal(t, 0, e.ExecuteCallCount)
+	require.Equal(t, 1, statusReporter.InstallCompleteCallCount)
+}
+
+func TestInstall_PlanDeclined(t *testing.T) {
 	ic := InstallerContext{
 		SkipLoggingInstall: true,
+		Plan:               true,
 	}
 	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
 	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
 	f = recipes.NewMockRecipeFetcher()
-	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{
-		Name:           "badRecipe",
-		ValidationNRQL: "testNrql",
-	}}
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{}
 	f.FetchRecipeVals = []types.OpenInstallationRecipe{
 		{
 			Name:           types.InfraAgentRecipeName,
 			ValidationNRQL: "testNrql",
 		},
-	}
-
-	p = &ux.MockPrompter{
-		PromptYesNoVal:       true,
-		PromptMultiSelectAll: true,
+		{
+			Name:           types.LoggingRecipeName,
+			ValidationNRQL: "testNrql",
+		},
 	}
 
 	v = validation.NewMockRecipeValidator()
-	v.ValidateErrs = []error{
-		nil,
-		errors.New("testing error"),
-	}
+	mp := ux.NewMockPrompter()
+	mp.PromptYesNoVal = false
 
-	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf}
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, mp, pi, lkf, ss, rd, cr}
 	err := i.Install()
-	require.NoError(t, err)
-	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).InstallCompleteCallCount)
-	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeFailedCallCount)
+	require.ErrorIs(t, err, types.ErrInterrupt)
+	require.Equal(t, 1, mp.PromptYesNoCallCount)
+	require.Equal(t, 0, v.ValidateCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).InstallCanceledCallCount)
 }
 
-func TestInstall_RecipeSkipped(t *testing.T) {
+func TestInstall_CustomInfraAndLoggingRecipeNames(t *testing.T) {
 	ic := InstallerContext{
-		SkipLoggingInstall: true,
+		InfraAgentRecipeName: "custom-infra",
+		LoggingRecipeName:    "custom-logging",
 	}
-	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	statusReporter := execution.NewMockStatusReporter()
+	statusReporters = []execution.StatusSubscriber{statusReporter}
 	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
 	f = recipes.NewMockRecipeFetcher()
-	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{
-		Name:           testRecipeName,
-		DisplayName:    "test displayName",
-		ValidationNRQL: "testNrql",
-	}}
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{
+		{Name: "custom-infra", DisplayName: "Custom Infra"},
+		{Name: "custom-logging", DisplayName: "Custom Logging"},
+		{Name: testRecipeName, DisplayName: testRecipeName},
+	}
 	f.FetchRecipeVals = []types.OpenInstallationRecipe{
 		{
-			Name:        types.InfraAgentRecipeName,
-			DisplayName: "Infra Recipe",
+			Name:        "custom-infra",
+			DisplayName: "Custom Infra",
 		},
 		{
-			Name:        types.LoggingRecipeName,
-			DisplayName: "Logging Recipe",
+			Name:        "custom-logging",
+			DisplayName: "Custom Logging",
 		},
 	}
 
 	v = validation.NewMockRecipeValidator()
-	p = &ux.MockPrompter{
-		PromptYesNoVal:       true,
+	mp := &ux.MockPrompter{
 		PromptMultiSelectAll: true,
 	}
 
-	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf}
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, mp, pi, lkf, ss, rd, cr}
 	err := i.Install()
 	require.NoError(t, err)
-	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeSkippedCallCount)
-	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeInstallingCallCount)
-	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
+	require.Equal(t, 1, f.FetchRecipeNameCount["custom-infra"])
+	require.Equal(t, 1, f.FetchRecipeNameCount["custom-logging"])
+	require.Equal(t, 0, f.FetchRecipeNameCount[types.InfraAgentRecipeName])
+	require.Equal(t, 0, f.FetchRecipeNameCount[types.LoggingRecipeName])
+	require.Equal(t, 1, statusReporter.ReportInstalled["custom-infra"])
+	require.Equal(t, 1, statusReporter.ReportInstalled["custom-logging"])
+	require.Equal(t, 1, statusReporter.ReportInstalled[testRecipeName])
 }
 
-func TestInstall_RecipeSkippedApm(t *testing.T) {
+func TestInstallWithResult_Success(t *testing.T) {
 	ic := InstallerContext{
-		SkipApm: true,
+		SkipLoggingInstall: true,
 	}
 	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
 	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
 	f = recipes.NewMockRecipeFetcher()
-	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{
-		Name:           testRecipeName,
-		DisplayName:    "test displayName",
-		ValidationNRQL: "testNrql",
-		Keywords:       []string{"apm"},
-	}}
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{}
 	f.FetchRecipeVals = []types.OpenInstallationRecipe{
 		{
-			Name:        types.InfraAgentRecipeName,
-			DisplayName: "Infra Recipe",
+			Name:           types.InfraAgentRecipeName,
+			DisplayName:    "Infra Recipe",
+			ValidationNRQL: "testNrql",
 		},
 		{
 			Name:        types.LoggingRecipeName,
@@ -427,67 +516,117 @@ func TestInstall_RecipeSkippedApm(t *testing.T) {
 	}
 
 	v = validation.NewMockRecipeValidator()
-	p = &ux.MockPrompter{
-		PromptYesNoVal:       true,
-		PromptMultiSelectAll: true,
-	}
+	v.ValidateVal = "testGUID"
 
-	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf}
-	err := i.Install()
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	result, err := i.InstallWithResult()
 	require.NoError(t, err)
-	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeSkippedCallCount)
-	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeInstallingCallCount)
-	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
+	require.Equal(t, InstallResultStatuses.SUCCESS, result.Status)
+	require.Equal(t, []string{"testGUID"}, result.EntityGUIDs)
+	require.NotEmpty(t, result.SuccessLink)
+	require.Empty(t, result.Errors)
+
+	outcomes := map[string]execution.RecipeStatusType{}
+	for _, r := range result.Recipes {
+		outcomes[r.Name] = r.Status
+	}
+	require.Equal(t, execution.RecipeStatusTypes.INSTALLED, outcomes[types.InfraAgentRecipeName])
+	require.Equal(t, execution.RecipeStatusTypes.SKIPPED, outcomes[types.LoggingRecipeName])
 }
 
-func TestInstall_RecipeSkippedApmAnyKeyword(t *testing.T) {
+func TestInstallWithResult_Partial(t *testing.T) {
 	ic := InstallerContext{
-		SkipApm: true,
+		SkipLoggingInstall: true,
+		AssumeYes:          true,
 	}
 	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
 	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
 	f = recipes.NewMockRecipeFetcher()
 	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{
-		Name:           testRecipeName,
-		DisplayName:    "test displayName",
+		Name:           "badRecipe",
 		ValidationNRQL: "testNrql",
-		Keywords:       []string{"xy", "apm", "z"},
 	}}
 	f.FetchRecipeVals = []types.OpenInstallationRecipe{
 		{
-			Name:        types.InfraAgentRecipeName,
-			DisplayName: "Infra Recipe",
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
 		},
+	}
+
+	v = validation.NewMockRecipeValidator()
+	v.ValidateErrs = []error{
+		nil,
+		errors.New("testing error"),
+	}
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	result, err := i.InstallWithResult()
+	require.NoError(t, err)
+	require.Equal(t, InstallResultStatuses.PARTIAL, result.Status)
+	require.Len(t, result.Errors, 1)
+	require.Contains(t, result.Errors[0].Error(), "badRecipe")
+}
+
+func TestInstallWithResult_Canceled(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeErr = types.ErrInterrupt
+
+	v = validation.NewMockRecipeValidator()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	result, err := i.InstallWithResult()
+	require.Equal(t, types.ErrInterrupt, err)
+	require.Equal(t, InstallResultStatuses.CANCELED, result.Status)
+}
+
+func TestInstall_InstallCompleteError(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
 		{
-			Name:        types.LoggingRecipeName,
-			DisplayName: "Logging Recipe",
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
 		},
 	}
 
-	v = validation.NewMockRecipeValidator()
 	p = &ux.MockPrompter{
-		PromptYesNoVal:       true,
-		PromptMultiSelectAll: true,
+		PromptYesNoVal: true,
 	}
 
-	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf}
+	v = validation.NewMockRecipeValidator()
+	v.ValidateErr = errors.New("test error")
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
 	err := i.Install()
-	require.NoError(t, err)
-	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeSkippedCallCount)
-	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeInstallingCallCount)
-	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
+	require.Error(t, err)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).InstallCompleteCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeFailedCallCount)
 }
 
-func TestInstall_RecipeSkipped_SkipAll(t *testing.T) {
+// TestInstall_InstallCompleteError_guidedRecipeFail ensures that when we
+// perform a guided install, that we only exit with an error if the infra-agent
+// recipe fails.  If a recommended recipe fails, a log is emittd, but we do not
+// want the error being returned.
+// https://newrelic.atlassian.net/browse/VIRTUOSO-454
+func TestInstall_InstallCompleteError_guidedRecipeFail(t *testing.T) {
 	ic := InstallerContext{
 		SkipLoggingInstall: true,
-		SkipIntegrations:   true,
 	}
 	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
 	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
 	f = recipes.NewMockRecipeFetcher()
 	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{
-		Name:           "test-recipe",
+		Name:           "badRecipe",
 		ValidationNRQL: "testNrql",
 	}}
 	f.FetchRecipeVals = []types.OpenInstallationRecipe{
@@ -495,26 +634,27 @@ func TestInstall_RecipeSkipped_SkipAll(t *testing.T) {
 			Name:           types.InfraAgentRecipeName,
 			ValidationNRQL: "testNrql",
 		},
-		{
-			Name:           types.LoggingRecipeName,
-			ValidationNRQL: "testNrql",
-		},
 	}
 
-	v = validation.NewMockRecipeValidator()
 	p = &ux.MockPrompter{
-		PromptYesNoVal:       false,
-		PromptMultiSelectVal: []string{},
+		PromptYesNoVal:       true,
+		PromptMultiSelectAll: true,
+	}
+
+	v = validation.NewMockRecipeValidator()
+	v.ValidateErrs = []error{
+		nil,
+		errors.New("testing error"),
 	}
 
-	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf}
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
 	err := i.Install()
 	require.NoError(t, err)
-	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeSkippedCallCount)
-	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).InstallCompleteCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeFailedCallCount)
 }
 
-func TestInstall_RecipeSkipped_MultiSelect(t *testing.T) {
+func TestInstall_RecipeFailedRetry(t *testing.T) {
 	ic := InstallerContext{
 		SkipLoggingInstall: true,
 	}
@@ -522,8 +662,7 @@ func TestInstall_RecipeSkipped_MultiSelect(t *testing.T) {
 	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
 	f = recipes.NewMockRecipeFetcher()
 	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{
-		Name:           testRecipeName,
-		DisplayName:    testRecipeName,
+		Name:           "badRecipe",
 		ValidationNRQL: "testNrql",
 	}}
 	f.FetchRecipeVals = []types.OpenInstallationRecipe{
@@ -531,140 +670,114 @@ func TestInstall_RecipeSkipped_MultiSelect(t *testing.T) {
 			Name:           types.InfraAgentRecipeName,
 			ValidationNRQL: "testNrql",
 		},
-		{
-			Name:           types.LoggingRecipeName,
-			ValidationNRQL: "testNrql",
-		},
+	}
+
+	mp := &ux.MockPrompter{
+		PromptMultiSelectAll: true,
+		PromptSelectVal:      recipeFailureRetry,
 	}
 
 	v = validation.NewMockRecipeValidator()
-	p = &ux.MockPrompter{
-		PromptYesNoVal:       true,
-		PromptMultiSelectVal: []string{testRecipeName},
+	v.ValidateErrs = []error{
+		nil,
+		errors.New("testing error"),
+		nil,
 	}
 
-	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf}
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, mp, pi, lkf, ss, rd, cr}
 	err := i.Install()
 	require.NoError(t, err)
-	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeSkippedCallCount)
-	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeInstallingCallCount)
+	require.Equal(t, 1, mp.PromptSelectCallCount)
+	require.Equal(t, 3, v.ValidateCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeFailedCallCount)
 	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
 }
 
-func TestInstall_RecipeRecommended(t *testing.T) {
+func TestInstall_RecipeFailedRetryCapped(t *testing.T) {
 	ic := InstallerContext{
 		SkipLoggingInstall: true,
 	}
 	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
 	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
 	f = recipes.NewMockRecipeFetcher()
-	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{
+		Name:           "badRecipe",
+		ValidationNRQL: "testNrql",
+	}}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
 		{
-			Name:           testRecipeName,
-			DisplayName:    testRecipeName,
+			Name:           types.InfraAgentRecipeName,
 			ValidationNRQL: "testNrql",
-			InstallTargets: []types.OpenInstallationRecipeInstallTarget{
-				{
-					Type: types.OpenInstallationTargetTypeTypes.HOST,
-				},
-			},
-		},
-		{
-			Name:           anotherTestRecipeName,
-			DisplayName:    anotherTestRecipeName,
-			ValidationNRQL: "testNrql",
-			InstallTargets: []types.OpenInstallationRecipeInstallTarget{
-				{
-					Type: types.OpenInstallationTargetTypeTypes.HOST,
-				},
-			},
-		},
-		{
-			Name:           "java-java-java",
-			ValidationNRQL: "testNrql",
-			InstallTargets: []types.OpenInstallationRecipeInstallTarget{
-				{
-					Type: types.OpenInstallationTargetTypeTypes.APPLICATION,
-				},
-			},
 		},
 	}
-	f.FetchRecipeVals = []types.OpenInstallationRecipe{
-		{
-			Name:           types.InfraAgentRecipeName,
-			ValidationNRQL: "testNrql",
-		},
-		{
-			Name:           types.LoggingRecipeName,
-			ValidationNRQL: "testNrql",
-		},
+
+	mp := &ux.MockPrompter{
+		PromptMultiSelectAll: true,
+		PromptSelectVal:      recipeFailureRetry,
 	}
 
 	v = validation.NewMockRecipeValidator()
-	p = &ux.MockPrompter{
-		PromptYesNoVal:       true,
-		PromptMultiSelectVal: []string{testRecipeName},
+	v.ValidateErrs = []error{
+		nil,
+		errors.New("testing error"),
 	}
 
-	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf}
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, mp, pi, lkf, ss, rd, cr}
 	err := i.Install()
 	require.NoError(t, err)
-	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeSkippedCallCount)
-	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
-	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).ReportInstalled[testRecipeName])
-	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).ReportInstalled[types.InfraAgentRecipeName])
-	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeRecommendedCallCount)
-	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeAvailableCallCount)
-	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipesAvailableCallCount)
-	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).ReportRecommended["java-java-java"])
+	require.Equal(t, maxRecipeRetries, mp.PromptSelectCallCount)
+	require.Equal(t, maxRecipeRetries+2, v.ValidateCallCount)
+	require.Equal(t, maxRecipeRetries+1, statusReporters[0].(*execution.MockStatusReporter).RecipeFailedCallCount)
 }
 
-func TestInstall_RecipeSkipped_AssumeYes(t *testing.T) {
+func TestInstall_RecipeFailedAbort(t *testing.T) {
 	ic := InstallerContext{
-		AssumeYes: true,
+		SkipLoggingInstall: true,
 	}
-
 	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
 	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
 	f = recipes.NewMockRecipeFetcher()
 	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{
-		Name:           testRecipeName,
-		DisplayName:    "test displayName",
+		Name:           "badRecipe",
 		ValidationNRQL: "testNrql",
 	}}
 	f.FetchRecipeVals = []types.OpenInstallationRecipe{
 		{
-			Name:        types.InfraAgentRecipeName,
-			DisplayName: "Infra Recipe",
-		},
-		{
-			Name:        types.LoggingRecipeName,
-			DisplayName: "Logging Recipe",
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
 		},
 	}
 
+	mp := &ux.MockPrompter{
+		PromptMultiSelectAll: true,
+		PromptSelectVal:      recipeFailureAbort,
+	}
+
 	v = validation.NewMockRecipeValidator()
-	p = &ux.MockPrompter{
-		PromptYesNoVal: true,
+	v.ValidateErrs = []error{
+		nil,
+		errors.New("testing error"),
 	}
 
-	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf}
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, mp, pi, lkf, ss, rd, cr}
 	err := i.Install()
-	require.NoError(t, err)
-	require.Equal(t, 0, statusReporters[0].(*execution.MockStatusReporter).RecipeSkippedCallCount)
-	require.Equal(t, 3, statusReporters[0].(*execution.MockStatusReporter).RecipeInstallingCallCount)
-	require.Equal(t, 3, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
+	require.ErrorIs(t, err, types.ErrInterrupt)
+	require.True(t, errors.As(err, &ErrUserCanceled{}))
+	require.Equal(t, 1, mp.PromptSelectCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).InstallCanceledCallCount)
 }
 
-func TestInstall_TargetedInstall_InstallsInfraAgent(t *testing.T) {
-	log.SetLevel(log.TraceLevel)
+func TestInstall_PromptTimeoutCancels(t *testing.T) {
 	ic := InstallerContext{
-		RecipeNames: []string{types.InfraAgentRecipeName},
+		SkipLoggingInstall: true,
 	}
 	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
 	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
 	f = recipes.NewMockRecipeFetcher()
-	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{}
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{
+		Name:           "badRecipe",
+		ValidationNRQL: "testNrql",
+	}}
 	f.FetchRecipeVals = []types.OpenInstallationRecipe{
 		{
 			Name:           types.InfraAgentRecipeName,
@@ -672,54 +785,76 @@ func TestInstall_TargetedInstall_InstallsInfraAgent(t *testing.T) {
 		},
 	}
 
+	mp := &ux.MockPrompter{
+		PromptMultiSelectAll: true,
+		PromptSelectErr:      types.ErrPromptTimeout,
+	}
+
 	v = validation.NewMockRecipeValidator()
+	v.ValidateErrs = []error{
+		nil,
+		errors.New("testing error"),
+	}
 
-	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf}
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, mp, pi, lkf, ss, rd, cr}
 	err := i.Install()
-	require.NoError(t, err)
-	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
-	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).InstallCompleteCallCount)
+	require.Equal(t, types.ErrPromptTimeout, err)
+	require.Equal(t, 1, mp.PromptSelectCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).InstallCanceledCallCount)
+	require.Equal(t, 0, statusReporters[0].(*execution.MockStatusReporter).InstallCompleteCallCount)
 }
 
-func TestInstall_TargetedInstall_InstallsInfraAgentDependency(t *testing.T) {
-	log.SetLevel(log.TraceLevel)
+func TestInstall_RecipeFailedAssumeYesSkips(t *testing.T) {
 	ic := InstallerContext{
-		RecipeNames: []string{"testRecipe"},
+		SkipLoggingInstall: true,
+		AssumeYes:          true,
 	}
 	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
 	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
 	f = recipes.NewMockRecipeFetcher()
-	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{}
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{
+		Name:           "badRecipe",
+		ValidationNRQL: "testNrql",
+	}}
 	f.FetchRecipeVals = []types.OpenInstallationRecipe{
-		{
-			Name:           "testRecipe",
-			ValidationNRQL: "testNrql",
-			Dependencies:   []string{types.InfraAgentRecipeName},
-		},
 		{
 			Name:           types.InfraAgentRecipeName,
 			ValidationNRQL: "testNrql",
 		},
 	}
 
+	mp := &ux.MockPrompter{
+		PromptMultiSelectAll: true,
+	}
+
 	v = validation.NewMockRecipeValidator()
+	v.ValidateErrs = []error{
+		nil,
+		errors.New("testing error"),
+	}
 
-	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf}
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, mp, pi, lkf, ss, rd, cr}
 	err := i.Install()
 	require.NoError(t, err)
-	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
-	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).InstallCompleteCallCount)
+	require.Equal(t, 0, mp.PromptSelectCallCount)
+	require.Equal(t, 2, v.ValidateCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeFailedCallCount)
 }
 
-func TestInstall_TargetedInstallInfraAgent_NoInfraAgentDuplicate(t *testing.T) {
-	log.SetLevel(log.TraceLevel)
+func TestInstall_MaxRecipeFailuresExceeded(t *testing.T) {
 	ic := InstallerContext{
-		RecipeNames: []string{types.InfraAgentRecipeName},
+		SkipLoggingInstall: true,
+		AssumeYes:          true,
+		MaxRecipeFailures:  1,
 	}
 	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
 	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
 	f = recipes.NewMockRecipeFetcher()
-	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{}
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{
+		{Name: "badRecipe1", DisplayName: "badRecipe1", ValidationNRQL: "testNrql"},
+		{Name: "badRecipe2", DisplayName: "badRecipe2", ValidationNRQL: "testNrql"},
+		{Name: "badRecipe3", DisplayName: "badRecipe3", ValidationNRQL: "testNrql"},
+	}
 	f.FetchRecipeVals = []types.OpenInstallationRecipe{
 		{
 			Name:           types.InfraAgentRecipeName,
@@ -728,24 +863,37 @@ func TestInstall_TargetedInstallInfraAgent_NoInfraAgentDuplicate(t *testing.T) {
 	}
 
 	v = validation.NewMockRecipeValidator()
+	v.ValidateErrs = []error{
+		nil,
+		errors.New("testing error"),
+	}
 
-	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf}
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
 	err := i.Install()
-	require.NoError(t, err)
-	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
+	require.Error(t, err)
+
+	var ferr ErrMaxRecipeFailures
+	require.True(t, errors.As(err, &ferr))
+	require.Equal(t, 2, ferr.Failed)
+	require.Equal(t, 1, ferr.Max)
+	require.Equal(t, 3, v.ValidateCallCount)
 	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).InstallCompleteCallCount)
 }
 
-func TestInstall_TargetedInstall_SkipInfra(t *testing.T) {
-	log.SetLevel(log.TraceLevel)
+func TestInstall_MaxRecipeFailuresWithinLimit(t *testing.T) {
 	ic := InstallerContext{
-		RecipeNames: []string{types.InfraAgentRecipeName},
-		SkipInfra:   true,
+		SkipLoggingInstall: true,
+		AssumeYes:          true,
+		MaxRecipeFailures:  3,
 	}
 	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
 	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
 	f = recipes.NewMockRecipeFetcher()
-	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{}
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{
+		{Name: "badRecipe1", DisplayName: "badRecipe1", ValidationNRQL: "testNrql"},
+		{Name: "badRecipe2", DisplayName: "badRecipe2", ValidationNRQL: "testNrql"},
+		{Name: "badRecipe3", DisplayName: "badRecipe3", ValidationNRQL: "testNrql"},
+	}
 	f.FetchRecipeVals = []types.OpenInstallationRecipe{
 		{
 			Name:           types.InfraAgentRecipeName,
@@ -754,104 +902,1214 @@ func TestInstall_TargetedInstall_SkipInfra(t *testing.T) {
 	}
 
 	v = validation.NewMockRecipeValidator()
+	v.ValidateErrs = []error{
+		nil,
+		errors.New("testing error"),
+	}
 
-	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf}
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
 	err := i.Install()
 	require.NoError(t, err)
-	require.Equal(t, 0, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
-	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).InstallCompleteCallCount)
+	require.Equal(t, 4, v.ValidateCallCount)
+	require.Equal(t, 3, statusReporters[0].(*execution.MockStatusReporter).RecipeFailedCallCount)
 }
 
-func TestInstall_TargetedInstall_SkipInfraDependency(t *testing.T) {
-	log.SetLevel(log.TraceLevel)
+func TestInstall_RecipeSkipped(t *testing.T) {
 	ic := InstallerContext{
-		RecipeNames: []string{"testRecipe"},
-		SkipInfra:   true,
+		SkipLoggingInstall: true,
 	}
 	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
 	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
 	f = recipes.NewMockRecipeFetcher()
-	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{}
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{
+		Name:           testRecipeName,
+		DisplayName:    "test displayName",
+		ValidationNRQL: "testNrql",
+	}}
 	f.FetchRecipeVals = []types.OpenInstallationRecipe{
 		{
-			Name:           "testRecipe",
-			ValidationNRQL: "testNrql",
-			Dependencies:   []string{types.InfraAgentRecipeName},
+			Name:        types.InfraAgentRecipeName,
+			DisplayName: "Infra Recipe",
 		},
 		{
-			Name:           types.InfraAgentRecipeName,
-			ValidationNRQL: "testNrql",
+			Name:        types.LoggingRecipeName,
+			DisplayName: "Logging Recipe",
 		},
 	}
 
 	v = validation.NewMockRecipeValidator()
+	p = &ux.MockPrompter{
+		PromptYesNoVal:       true,
+		PromptMultiSelectAll: true,
+	}
 
-	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf}
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
 	err := i.Install()
 	require.NoError(t, err)
-	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
-	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).InstallCompleteCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeSkippedCallCount)
+	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeInstallingCallCount)
+	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
 }
 
-func TestInstall_GuidReport(t *testing.T) {
-	log.SetLevel(log.DebugLevel)
+func TestInstall_RecipeSkippedApm(t *testing.T) {
 	ic := InstallerContext{
-		SkipLoggingInstall: true,
+		SkipApm: true,
 	}
 	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
 	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
 	f = recipes.NewMockRecipeFetcher()
 	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{
 		Name:           testRecipeName,
-		DisplayName:    testRecipeName,
+		DisplayName:    "test displayName",
 		ValidationNRQL: "testNrql",
+		Keywords:       []string{"apm"},
 	}}
 	f.FetchRecipeVals = []types.OpenInstallationRecipe{
 		{
-			Name:           types.InfraAgentRecipeName,
-			DisplayName:    types.InfraAgentRecipeName,
-			ValidationNRQL: "testNrql",
+			Name:        types.InfraAgentRecipeName,
+			DisplayName: "Infra Recipe",
 		},
 		{
-			Name:           types.LoggingRecipeName,
-			DisplayName:    types.LoggingRecipeName,
-			ValidationNRQL: "testNrql",
+			Name:        types.LoggingRecipeName,
+			DisplayName: "Logging Recipe",
 		},
 	}
 
+	v = validation.NewMockRecipeValidator()
 	p = &ux.MockPrompter{
 		PromptYesNoVal:       true,
 		PromptMultiSelectAll: true,
 	}
 
-	v = validation.NewMockRecipeValidator()
-	v.ValidateVals = []string{
-		"INFRAGUID",
-		"TESTRECIPEGUID",
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeSkippedCallCount)
+	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeInstallingCallCount)
+	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
+}
+
+func TestInstall_RecipeSkippedApmAnyKeyword(t *testing.T) {
+	ic := InstallerContext{
+		SkipApm: true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{
+		Name:           testRecipeName,
+		DisplayName:    "test displayName",
+		ValidationNRQL: "testNrql",
+		Keywords:       []string{"xy", "apm", "z"},
+	}}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:        types.InfraAgentRecipeName,
+			DisplayName: "Infra Recipe",
+		},
+		{
+			Name:        types.LoggingRecipeName,
+			DisplayName: "Logging Recipe",
+		},
 	}
 
-	// Test for NEW_RELIC_CLI_VERSION
-	os.Setenv("NEW_RELIC_CLI_VERSION", "testversion0.0.1")
+	v = validation.NewMockRecipeValidator()
+	p = &ux.MockPrompter{
+		PromptYesNoVal:       true,
+		PromptMultiSelectAll: true,
+	}
 
-	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf}
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
 	err := i.Install()
 	require.NoError(t, err)
-	require.Equal(t, 2, v.ValidateCallCount)
-	require.Equal(t, 0, statusReporters[0].(*execution.MockStatusReporter).RecipeFailedCallCount)
 	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeSkippedCallCount)
-	require.Equal(t, v.ValidateVals[0], statusReporters[0].(*execution.MockStatusReporter).RecipeGUID[types.InfraAgentRecipeName])
-	require.Equal(t, v.ValidateVals[1], statusReporters[0].(*execution.MockStatusReporter).RecipeGUID[testRecipeName])
-	require.Equal(t, status.CLIVersion, "testversion0.0.1")
-	require.Equal(t, 3, len(statusReporters[0].(*execution.MockStatusReporter).Durations))
-	for _, duration := range statusReporters[0].(*execution.MockStatusReporter).Durations {
-		require.Less(t, int64(0), duration)
-	}
-}
-
-func fetchRecipeFileFunc(recipeURL *url.URL) (*types.OpenInstallationRecipe, error) {
-	return testRecipeFile, nil
+	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeInstallingCallCount)
+	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
 }
 
-func loadRecipeFileFunc(filename string) (*types.OpenInstallationRecipe, error) {
-	return testRecipeFile, nil
+func TestInstall_GuidedSkipsUnsupportedIntegrations(t *testing.T) {
+	ic := InstallerContext{}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{
+		Name:           testRecipeName,
+		DisplayName:    "test displayName",
+		ValidationNRQL: "testNrql",
+		InstallTargets: []types.OpenInstallationRecipeInstallTarget{
+			{Type: types.OpenInstallationTargetTypeTypes.HOST, Os: "WINDOWS"},
+		},
+	}}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:        types.InfraAgentRecipeName,
+			DisplayName: "Infra Recipe",
+		},
+		{
+			Name:        types.LoggingRecipeName,
+			DisplayName: "Logging Recipe",
+		},
+	}
+
+	v = validation.NewMockRecipeValidator()
+	p = &ux.MockPrompter{
+		PromptYesNoVal:       true,
+		PromptMultiSelectAll: true,
+	}
+
+	md := discovery.NewMockDiscoverer()
+	md.DiscoveryManifest.OS = "linux"
+
+	i := RecipeInstaller{ic, md, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeSkippedCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).ReportSkipped[testRecipeName])
+	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
+}
+
+func TestInstall_RecipeSkipped_SkipAll(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+		SkipIntegrations:   true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{
+		Name:           "test-recipe",
+		ValidationNRQL: "testNrql",
+	}}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+		{
+			Name:           types.LoggingRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+
+	v = validation.NewMockRecipeValidator()
+	p = &ux.MockPrompter{
+		PromptYesNoVal:       false,
+		PromptMultiSelectVal: []string{},
+	}
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeSkippedCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
+}
+
+func TestInstall_RecipeSkipped_MultiSelect(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{
+		Name:           testRecipeName,
+		DisplayName:    testRecipeName,
+		ValidationNRQL: "testNrql",
+	}}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+		{
+			Name:           types.LoggingRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+
+	v = validation.NewMockRecipeValidator()
+	p = &ux.MockPrompter{
+		PromptYesNoVal:       true,
+		PromptMultiSelectVal: []string{testRecipeName},
+	}
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeSkippedCallCount)
+	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeInstallingCallCount)
+	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
+}
+
+func TestInstall_RecipeSkipped_TagFilters(t *testing.T) {
+	ic := InstallerContext{
+		AssumeYes:          true,
+		SkipLoggingInstall: true,
+		IncludeTags:        []string{"database"},
+		ExcludeTags:        []string{"deprecated"},
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{
+		{
+			Name:           "mysql",
+			DisplayName:    "mysql",
+			Keywords:       []string{"Database"},
+			ValidationNRQL: "testNrql",
+		},
+		{
+			Name:           "legacy-db",
+			DisplayName:    "legacy-db",
+			Keywords:       []string{"database", "deprecated"},
+			ValidationNRQL: "testNrql",
+		},
+		{
+			Name:           "nginx",
+			DisplayName:    "nginx",
+			Keywords:       []string{"web"},
+			ValidationNRQL: "testNrql",
+		},
+	}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+
+	v = validation.NewMockRecipeValidator()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+
+	reporter := statusReporters[0].(*execution.MockStatusReporter)
+	require.Equal(t, 1, reporter.ReportSkipped["legacy-db"])
+	require.Equal(t, 1, reporter.ReportSkipped["nginx"])
+	require.Equal(t, 1, reporter.ReportInstalled["mysql"])
+	require.Equal(t, 1, reporter.ReportInstalled[types.InfraAgentRecipeName])
+}
+
+func TestInstall_RecipeSkipped_ByName(t *testing.T) {
+	ic := InstallerContext{
+		AssumeYes:   true,
+		SkipRecipes: []string{"nginx", types.LoggingRecipeName, "not-a-recipe"},
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{
+		{
+			Name:           "mysql",
+			DisplayName:    "mysql",
+			ValidationNRQL: "testNrql",
+		},
+		{
+			Name:           "nginx",
+			DisplayName:    "nginx",
+			ValidationNRQL: "testNrql",
+		},
+	}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+		{
+			Name:           types.LoggingRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+
+	v = validation.NewMockRecipeValidator()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+
+	reporter := statusReporters[0].(*execution.MockStatusReporter)
+	require.Equal(t, 1, reporter.ReportSkipped["nginx"])
+	require.Equal(t, 1, reporter.ReportSkipped[types.LoggingRecipeName])
+	require.Equal(t, 0, reporter.ReportInstalled["nginx"])
+	require.Equal(t, 0, reporter.ReportInstalled[types.LoggingRecipeName])
+	require.Equal(t, 1, reporter.ReportInstalled["mysql"])
+	require.Equal(t, 1, reporter.ReportInstalled[types.InfraAgentRecipeName])
+}
+
+func TestInstall_TargetedRecipeSkipped_ByName(t *testing.T) {
+	ic := InstallerContext{
+		RecipeNames: []string{types.InfraAgentRecipeName, testRecipeName},
+		SkipRecipes: []string{types.InfraAgentRecipeName},
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+		{
+			Name:           testRecipeName,
+			ValidationNRQL: "testNrql",
+			Dependencies:   []string{types.InfraAgentRecipeName},
+		},
+	}
+	e := execution.NewMockRecipeExecutor()
+	v = validation.NewMockRecipeValidator()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+
+	reporter := statusReporters[0].(*execution.MockStatusReporter)
+	require.Equal(t, 1, reporter.ReportSkipped[types.InfraAgentRecipeName])
+	require.Equal(t, 0, reporter.ReportInstalled[types.InfraAgentRecipeName])
+	require.Equal(t, 1, reporter.ReportInstalled[testRecipeName])
+	require.Equal(t, 1, e.ExecuteCallCount)
+}
+
+func TestInstall_LinesProgress(t *testing.T) {
+	ic := InstallerContext{
+		RecipeNames: []string{types.InfraAgentRecipeName, testRecipeName},
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+		{
+			Name:         testRecipeName,
+			Dependencies: []string{types.InfraAgentRecipeName},
+		},
+	}
+	v = validation.NewMockRecipeValidator()
+	var out bytes.Buffer
+	pi := ux.NewLinesProgressWithWriter(&out, false)
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"pending    Installing " + types.InfraAgentRecipeName,
+		"pending    Installing " + testRecipeName,
+		"installing Installing " + types.InfraAgentRecipeName,
+		"validating Installing " + types.InfraAgentRecipeName,
+		"done       Installing " + types.InfraAgentRecipeName,
+		"installing Installing " + testRecipeName,
+		"done       Installing " + testRecipeName,
+	}, strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n"))
+}
+
+func TestInstall_RecipeRecommended(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{
+		{
+			Name:           testRecipeName,
+			DisplayName:    testRecipeName,
+			ValidationNRQL: "testNrql",
+			InstallTargets: []types.OpenInstallationRecipeInstallTarget{
+				{
+					Type: types.OpenInstallationTargetTypeTypes.HOST,
+				},
+			},
+		},
+		{
+			Name:           anotherTestRecipeName,
+			DisplayName:    anotherTestRecipeName,
+			ValidationNRQL: "testNrql",
+			InstallTargets: []types.OpenInstallationRecipeInstallTarget{
+				{
+					Type: types.OpenInstallationTargetTypeTypes.HOST,
+				},
+			},
+		},
+		{
+			Name:           "java-java-java",
+			ValidationNRQL: "testNrql",
+			InstallTargets: []types.OpenInstallationRecipeInstallTarget{
+				{
+					Type: types.OpenInstallationTargetTypeTypes.APPLICATION,
+				},
+			},
+		},
+	}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+		{
+			Name:           types.LoggingRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+
+	v = validation.NewMockRecipeValidator()
+	p = &ux.MockPrompter{
+		PromptYesNoVal:       true,
+		PromptMultiSelectVal: []string{testRecipeName},
+	}
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeSkippedCallCount)
+	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).ReportInstalled[testRecipeName])
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).ReportInstalled[types.InfraAgentRecipeName])
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeRecommendedCallCount)
+	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeAvailableCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipesAvailableCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).ReportRecommended["java-java-java"])
+}
+
+func TestInstall_RecipeSkipped_AssumeYes(t *testing.T) {
+	ic := InstallerContext{
+		AssumeYes: true,
+	}
+
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{
+		Name:           testRecipeName,
+		DisplayName:    "test displayName",
+		ValidationNRQL: "testNrql",
+	}}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:        types.InfraAgentRecipeName,
+			DisplayName: "Infra Recipe",
+		},
+		{
+			Name:        types.LoggingRecipeName,
+			DisplayName: "Logging Recipe",
+		},
+	}
+
+	v = validation.NewMockRecipeValidator()
+	p = &ux.MockPrompter{
+		PromptYesNoVal: true,
+	}
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+	require.Equal(t, 0, statusReporters[0].(*execution.MockStatusReporter).RecipeSkippedCallCount)
+	require.Equal(t, 3, statusReporters[0].(*execution.MockStatusReporter).RecipeInstallingCallCount)
+	require.Equal(t, 3, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
+}
+
+func TestInstall_TargetedInstall_InstallsInfraAgent(t *testing.T) {
+	log.SetLevel(log.TraceLevel)
+	ic := InstallerContext{
+		RecipeNames: []string{types.InfraAgentRecipeName},
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+
+	v = validation.NewMockRecipeValidator()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).InstallCompleteCallCount)
+}
+
+func TestInstall_TargetedInstall_InstallsInfraAgentDependency(t *testing.T) {
+	log.SetLevel(log.TraceLevel)
+	ic := InstallerContext{
+		RecipeNames: []string{"testRecipe"},
+	}
+	p = &ux.MockPrompter{
+		PromptYesNoVal: true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           "testRecipe",
+			ValidationNRQL: "testNrql",
+			Dependencies:   []string{types.InfraAgentRecipeName},
+		},
+		{
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+
+	v = validation.NewMockRecipeValidator()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).InstallCompleteCallCount)
+}
+
+func TestInstall_TargetedInstallInfraAgent_NoInfraAgentDuplicate(t *testing.T) {
+	log.SetLevel(log.TraceLevel)
+	ic := InstallerContext{
+		RecipeNames: []string{types.InfraAgentRecipeName},
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+
+	v = validation.NewMockRecipeValidator()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).InstallCompleteCallCount)
+}
+
+func TestInstall_TargetedInstall_SkipInfra(t *testing.T) {
+	log.SetLevel(log.TraceLevel)
+	ic := InstallerContext{
+		RecipeNames: []string{types.InfraAgentRecipeName},
+		SkipInfra:   true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+
+	v = validation.NewMockRecipeValidator()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+	require.Equal(t, 0, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).InstallCompleteCallCount)
+}
+
+func TestInstall_TargetedInstall_SkipInfraDependency(t *testing.T) {
+	log.SetLevel(log.TraceLevel)
+	ic := InstallerContext{
+		RecipeNames: []string{"testRecipe"},
+		SkipInfra:   true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           "testRecipe",
+			ValidationNRQL: "testNrql",
+			Dependencies:   []string{types.InfraAgentRecipeName},
+		},
+		{
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+
+	v = validation.NewMockRecipeValidator()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).InstallCompleteCallCount)
+}
+
+func TestInstall_GuidReport(t *testing.T) {
+	log.SetLevel(log.DebugLevel)
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{
+		Name:           testRecipeName,
+		DisplayName:    testRecipeName,
+		ValidationNRQL: "testNrql",
+	}}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           types.InfraAgentRecipeName,
+			DisplayName:    types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+		{
+			Name:           types.LoggingRecipeName,
+			DisplayName:    types.LoggingRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+
+	p = &ux.MockPrompter{
+		PromptYesNoVal:       true,
+		PromptMultiSelectAll: true,
+	}
+
+	v = validation.NewMockRecipeValidator()
+	v.ValidateVals = []string{
+		"INFRAGUID",
+		"TESTRECIPEGUID",
+	}
+
+	// Test for NEW_RELIC_CLI_VERSION
+	os.Setenv("NEW_RELIC_CLI_VERSION", "testversion0.0.1")
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+	require.Equal(t, 2, v.ValidateCallCount)
+	require.Equal(t, 0, statusReporters[0].(*execution.MockStatusReporter).RecipeFailedCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeSkippedCallCount)
+	require.Equal(t, v.ValidateVals[0], statusReporters[0].(*execution.MockStatusReporter).RecipeGUID[types.InfraAgentRecipeName])
+	require.Equal(t, v.ValidateVals[1], statusReporters[0].(*execution.MockStatusReporter).RecipeGUID[testRecipeName])
+	require.Equal(t, status.CLIVersion, "testversion0.0.1")
+	require.Equal(t, 3, len(statusReporters[0].(*execution.MockStatusReporter).Durations))
+	for _, duration := range statusReporters[0].(*execution.MockStatusReporter).Durations {
+		require.Less(t, int64(0), duration)
+	}
+}
+
+func TestInstall_RecipeSelectionRemembered(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{
+		{
+			Name:           testRecipeName,
+			DisplayName:    testRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+		{
+			Name:           anotherTestRecipeName,
+			DisplayName:    anotherTestRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+		{
+			Name:           types.LoggingRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+
+	v = validation.NewMockRecipeValidator()
+	mp := &ux.MockPrompter{
+		PromptMultiSelectVal: []string{testRecipeName},
+	}
+	store := NewMockSelectionStore()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, mp, pi, lkf, store, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+	require.Nil(t, mp.PromptMultiSelectDefaults)
+	require.Equal(t, 1, store.SaveCallCount)
+
+	saved := store.Selections[d.GetManifest().Fingerprint()]
+	require.Equal(t, []string{testRecipeName}, saved.Selected)
+	require.Equal(t, []string{anotherTestRecipeName}, saved.Declined)
+
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f.FetchRecipeCallCount = 0
+	i = RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, mp, pi, lkf, store, rd, cr}
+	err = i.Install()
+	require.NoError(t, err)
+	require.Equal(t, []string{testRecipeName}, mp.PromptMultiSelectDefaults)
+}
+
+func TestInstall_RecipeSelectionReset(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+		ResetSelections:    true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{
+		Name:           testRecipeName,
+		DisplayName:    testRecipeName,
+		ValidationNRQL: "testNrql",
+	}}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+		{
+			Name:           types.LoggingRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+
+	v = validation.NewMockRecipeValidator()
+	mp := &ux.MockPrompter{
+		PromptMultiSelectAll: true,
+	}
+	store := NewMockSelectionStore()
+	store.Selections[d.GetManifest().Fingerprint()] = RecipeSelection{
+		Declined: []string{testRecipeName},
+	}
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, mp, pi, lkf, store, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+	require.Equal(t, 1, store.ResetCallCount)
+	require.Nil(t, mp.PromptMultiSelectDefaults)
+}
+
+func TestInstall_AlreadyInstalledRecipesNotReinstalled(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+	}
+	statusReporter := execution.NewMockStatusReporter()
+	statusReporters = []execution.StatusSubscriber{statusReporter}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{
+		Name:           testRecipeName,
+		DisplayName:    testRecipeName,
+		ValidationNRQL: "testNrql",
+	}}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+		{
+			Name:           types.LoggingRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+
+	c := validation.NewMockNRDBClient()
+	c.ReturnResultsAfterNAttempts(nonEmptyResults, nonEmptyResults, 0)
+	detector := validation.NewNRQLRecipeDetector(c)
+
+	v = validation.NewMockRecipeValidator()
+	mp := &ux.MockPrompter{
+		PromptMultiSelectAll: true,
+	}
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, mp, pi, lkf, ss, detector, cr}
+	err := i.Install()
+	require.NoError(t, err)
+	require.Equal(t, 0, v.ValidateCallCount)
+	require.Equal(t, 0, mp.PromptMultiSelectCallCount)
+	require.Equal(t, 0, statusReporter.RecipeInstallingCallCount)
+	require.Equal(t, 1, statusReporter.ReportInstalled[types.InfraAgentRecipeName])
+	require.Equal(t, 1, statusReporter.ReportInstalled[testRecipeName])
+}
+
+func TestInstall_AlreadyInstalledRecipesReinstalled(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+		Reinstall:          true,
+	}
+	statusReporter := execution.NewMockStatusReporter()
+	statusReporters = []execution.StatusSubscriber{statusReporter}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{
+		Name:           testRecipeName,
+		DisplayName:    testRecipeName,
+		ValidationNRQL: "testNrql",
+	}}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           types.InfraAgentRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+		{
+			Name:           types.LoggingRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+
+	c := validation.NewMockNRDBClient()
+	c.ReturnResultsAfterNAttempts(nonEmptyResults, nonEmptyResults, 0)
+	detector := validation.NewNRQLRecipeDetector(c)
+
+	v = validation.NewMockRecipeValidator()
+	mp := &ux.MockPrompter{
+		PromptMultiSelectAll: true,
+	}
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, mp, pi, lkf, ss, detector, cr}
+	err := i.Install()
+	require.NoError(t, err)
+	require.Equal(t, 2, v.ValidateCallCount)
+	require.Equal(t, 2, statusReporter.RecipeInstallingCallCount)
+
+	reinstalled := []string{}
+	for _, rs := range status.Statuses {
+		if rs.Status == execution.RecipeStatusTypes.INSTALLED {
+			require.True(t, rs.Reinstalled, rs.Name)
+			require.False(t, rs.AlreadyInstalled, rs.Name)
+			reinstalled = append(reinstalled, rs.Name)
+		}
+	}
+	require.ElementsMatch(t, []string{types.InfraAgentRecipeName, testRecipeName}, reinstalled)
+}
+
+func TestInstall_TargetedReinstall(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+	ic := InstallerContext{
+		RecipeNames: []string{testRecipeName},
+		SkipInfra:   true,
+		Reinstall:   true,
+	}
+	statusReporter := execution.NewMockStatusReporter()
+	statusReporters = []execution.StatusSubscriber{statusReporter}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           testRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+
+	c := validation.NewMockNRDBClient()
+	c.ReturnResultsAfterNAttempts(nonEmptyResults, nonEmptyResults, 0)
+	detector := validation.NewNRQLRecipeDetector(c)
+
+	v = validation.NewMockRecipeValidator()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, detector, cr}
+	err := i.Install()
+	require.NoError(t, err)
+	require.Equal(t, 1, v.ValidateCallCount)
+	require.Equal(t, 1, statusReporter.RecipeInstallingCallCount)
+	require.Equal(t, 1, statusReporter.ReportInstalled[testRecipeName])
+	require.Len(t, status.Statuses, 1)
+	require.True(t, status.Statuses[0].Reinstalled)
+}
+
+func TestInstall_DryRunSkipsValidation(t *testing.T) {
+	ic := InstallerContext{
+		RecipeNames: []string{testRecipeName},
+		SkipInfra:   true,
+		DryRun:      true,
+	}
+	statusReporter := execution.NewMockStatusReporter()
+	statusReporters = []execution.StatusSubscriber{statusReporter}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           testRecipeName,
+			ValidationNRQL: "testNrql",
+		},
+	}
+	e := execution.NewMockRecipeExecutor()
+	v = validation.NewMockRecipeValidator()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+	require.Equal(t, 1, e.ExecuteCallCount)
+	require.Equal(t, 0, v.ValidateCallCount)
+	require.Equal(t, 0, statusReporter.RecipeInstalledCallCount)
+	require.Equal(t, 1, statusReporter.RecipeSkippedCallCount)
+	require.Equal(t, execution.RecipeStatusTypes.SKIPPED, status.Statuses[0].Status)
+}
+
+func TestInstall_UnresolvedValidationQueryFailsBeforeExecution(t *testing.T) {
+	ic := InstallerContext{
+		RecipeNames: []string{testRecipeName},
+		SkipInfra:   true,
+	}
+	statusReporter := execution.NewMockStatusReporter()
+	statusReporters = []execution.StatusSubscriber{statusReporter}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{
+			Name:           testRecipeName,
+			ValidationNRQL: "SELECT count(*) FROM RedisSample WHERE port = '{{.REDIS_PORT}}'",
+		},
+	}
+	e := execution.NewMockRecipeExecutor()
+	v = validation.NewMockRecipeValidator()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+
+	var merr validation.ErrMissingQueryVars
+	require.True(t, errors.As(err, &merr))
+	require.Equal(t, []string{"REDIS_PORT"}, merr.Names)
+	require.Equal(t, 0, e.ExecuteCallCount)
+	require.Equal(t, 0, v.ValidateCallCount)
+	require.Equal(t, 1, statusReporter.RecipeFailedCallCount)
+}
+
+func fetchRecipeFileFunc(recipeURL *url.URL) (*types.OpenInstallationRecipe, error) {
+	return testRecipeFile, nil
+}
+
+func loadRecipeFileFunc(filename string) (*types.OpenInstallationRecipe, error) {
+	return testRecipeFile, nil
+}
+
+func TestInstall_RequiredPortInUseFailsRecipe(t *testing.T) {
+	ic := InstallerContext{
+		RecipeNames: []string{testRecipeName},
+		AssumeYes:   true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVal = &types.OpenInstallationRecipe{
+		Name:          testRecipeName,
+		RequiredPorts: []int{8080, 9090},
+	}
+
+	md := discovery.NewMockDiscoverer()
+	md.DiscoveryManifest.ListeningPorts = []int{22, 8080}
+
+	i := RecipeInstaller{ic, md, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+
+	var perr ErrPortsInUse
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, []int{8080}, perr.Ports)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeFailedCallCount)
+	require.Equal(t, 0, statusReporters[0].(*execution.MockStatusReporter).RecipeInstallingCallCount)
+}
+
+func TestInstall_MissingEnvVarsFailsRecipe(t *testing.T) {
+	ic := InstallerContext{
+		RecipeNames: []string{testRecipeName},
+		AssumeYes:   true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVal = &types.OpenInstallationRecipe{
+		Name:            testRecipeName,
+		RequiredEnvVars: []string{"MYSQL_USER"},
+	}
+
+	e := execution.NewMockRecipeExecutor()
+	e.PrepareErr = execution.NewErrMissingEnvVars(testRecipeName, []string{"MYSQL_USER"})
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+
+	var eerr execution.ErrMissingEnvVars
+	require.True(t, errors.As(err, &eerr))
+	require.Equal(t, []string{"MYSQL_USER"}, eerr.Names)
+	require.Equal(t, 0, e.ExecuteCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeFailedCallCount)
+	require.Equal(t, 0, statusReporters[0].(*execution.MockStatusReporter).RecipeInstallingCallCount)
+}
+
+func TestInstall_IgnorePortConflictsInstallsRecipe(t *testing.T) {
+	ic := InstallerContext{
+		RecipeNames:         []string{testRecipeName},
+		AssumeYes:           true,
+		IgnorePortConflicts: true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVal = &types.OpenInstallationRecipe{
+		Name:          testRecipeName,
+		RequiredPorts: []int{8080},
+	}
+
+	md := discovery.NewMockDiscoverer()
+	md.DiscoveryManifest.ListeningPorts = []int{8080}
+
+	i := RecipeInstaller{ic, md, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+	require.Equal(t, 0, statusReporters[0].(*execution.MockStatusReporter).RecipeFailedCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
+}
+
+func TestInstall_ReinstallIgnoresRequiredPorts(t *testing.T) {
+	ic := InstallerContext{
+		RecipeNames: []string{testRecipeName},
+		AssumeYes:   true,
+		Reinstall:   true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVal = &types.OpenInstallationRecipe{
+		Name:          testRecipeName,
+		RequiredPorts: []int{8080},
+	}
+
+	md := discovery.NewMockDiscoverer()
+	md.DiscoveryManifest.ListeningPorts = []int{8080}
+
+	i := RecipeInstaller{ic, md, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+	require.Equal(t, 0, statusReporters[0].(*execution.MockStatusReporter).RecipeFailedCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
+}
+
+func TestInstall_PreInstallMessageShownBeforePrompting(t *testing.T) {
+	out, err := ioutil.TempFile("", "stdout")
+	require.NoError(t, err)
+	defer os.Remove(out.Name())
+	defer out.Close()
+
+	stdout := os.Stdout
+	os.Stdout = out
+	defer func() { os.Stdout = stdout }()
+
+	ic := InstallerContext{
+		RecipeNames: []string{testRecipeName},
+		AssumeYes:   true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVal = &types.OpenInstallationRecipe{
+		Name:             testRecipeName,
+		PlatformDefaults: map[string]map[string]string{"linux": {"CONFIG_DIR": "/etc/test"}},
+		PreInstall: types.OpenInstallationPreInstallConfiguration{
+			Info: "Configuring {{.Vars.CONFIG_DIR}}",
+		},
+	}
+
+	pe := &outputRecordingExecutor{MockRecipeExecutor: execution.NewMockRecipeExecutor(), out: out}
+
+	i := RecipeInstaller{ic, d, l, mv, f, pe, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err = i.Install()
+	require.NoError(t, err)
+	require.Contains(t, pe.outputBeforePrepare, "Configuring /etc/test")
+}
+
+// outputRecordingExecutor records what was written to out before variables
+// were prepared.
+type outputRecordingExecutor struct {
+	*execution.MockRecipeExecutor
+	out                 *os.File
+	outputBeforePrepare string
+}
+
+func (e *outputRecordingExecutor) Prepare(ctx context.Context, m types.DiscoveryManifest, r types.OpenInstallationRecipe, assumeYes bool, licenseKey string) (types.RecipeVars, error) {
+	data, err := ioutil.ReadFile(e.out.Name())
+	if err != nil {
+		return nil, err
+	}
+	e.outputBeforePrepare = string(data)
+
+	return e.MockRecipeExecutor.Prepare(ctx, m, r, assumeYes, licenseKey)
+}
+
+func TestInstall_EmptySelectionCanBeChosenAgain(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{
+		Name:        "recommended",
+		DisplayName: "Recommended Recipe",
+	}}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{{
+		Name: types.InfraAgentRecipeName,
+	}}
+
+	mp := &ux.MockPrompter{
+		PromptMultiSelectVal: []string{},
+		PromptSelectVals:     []string{emptySelectionChooseAgain, emptySelectionContinue},
+	}
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, mp, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+	require.Equal(t, 2, mp.PromptMultiSelectCallCount)
+	require.Equal(t, 2, mp.PromptSelectCallCount)
+	require.Equal(t, 1, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
+}
+
+func TestInstall_EmptySelectionNotConfirmedWithAssumeYes(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+		AssumeYes:          true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{{
+		Name: types.InfraAgentRecipeName,
+	}}
+
+	mp := ux.NewMockPrompter()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, mp, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+	require.Equal(t, 0, mp.PromptMultiSelectCallCount)
+	require.Equal(t, 0, mp.PromptSelectCallCount)
+}
+
+func TestFetchRecommendations_Partial(t *testing.T) {
+	f2 := recipes.NewMockRecipeFetcher()
+	f2.FetchRecommendationsVal = []types.OpenInstallationRecipe{{Name: testRecipeName}}
+	f2.FetchRecommendationsErr = recipes.NewErrPartialRecommendations("p2", 1, 3, errors.New("timeout"))
+
+	i := RecipeInstaller{InstallerContext: InstallerContext{}, recipeFetcher: f2}
+
+	r, err := i.fetchRecommendations(&types.DiscoveryManifest{})
+	require.NoError(t, err)
+	require.Len(t, r, 1)
+
+	f2.FetchRecommendationsErr = errors.New("timeout")
+
+	_, err = i.fetchRecommendations(&types.DiscoveryManifest{})
+	require.Error(t, err)
 }
@@ -0,0 +1,349 @@
+package install
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+const (
+	lintSeverityError   = "error"
+	lintSeverityWarning = "warning"
+)
+
+var (
+	yamlErrorLineRegex = regexp.MustCompile(`line (\d+)`)
+	templateVarRegex   = regexp.MustCompile(`\{\{-?\s*\.([A-Za-z_][A-Za-z0-9_]*)`)
+
+	// goTaskVarNames are the variables go-task sets for every task.
+	goTaskVarNames = []string{"CHECKSUM", "CLI_ARGS", "ROOT_DIR", "TASK", "TASKFILE_DIR", "TIMESTAMP", "USER_WORKING_DIR"}
+)
+
+// RecipeLintIssue is a problem found in a recipe file.  Line is the line of
+// the file the problem was found on, or zero when it applies to the recipe as a
+// whole.
+type RecipeLintIssue struct {
+	Severity string `json:"severity"`
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message"`
+}
+
+// recipeLinter collects the issues found in the content of a recipe file.
+type recipeLinter struct {
+	lines  []string
+	issues []RecipeLintIssue
+}
+
+// lintRecipe checks the content of a recipe file for problems that would
+// prevent it from being installed, or that are common mistakes, returning the
+// issues found ordered by line.
+func lintRecipe(content string) []RecipeLintIssue {
+	l := recipeLinter{
+		lines: strings.Split(content, "\n"),
+	}
+
+	r, err := parseRecipeForLint(content)
+	if err != nil {
+		l.addf(lintSeverityError, yamlErrorLine(err), "the recipe could not be parsed: %s", err)
+		return l.issues
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal([]byte(content), &raw); err == nil {
+		l.lintKeys(raw)
+	}
+
+	l.lintRequiredFields(r)
+	l.lintValidationNRQL(r)
+	l.lintInputVars(r)
+	l.lintInstall(r)
+	l.lintMatches(r)
+
+	sort.SliceStable(l.issues, func(i, j int) bool {
+		return l.issues[i].Line < l.issues[j].Line
+	})
+
+	return l.issues
+}
+
+// parseRecipeForLint parses the recipe as it is when installed.  Values of
+// unexpected types make the parser panic, which is reported as an error.
+func parseRecipeForLint(content string) (r *types.OpenInstallationRecipe, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			r = nil
+			err = fmt.Errorf("a field has a value of the wrong type: %v", p)
+		}
+	}()
+
+	return recipes.NewRecipeFile(content)
+}
+
+func (l *recipeLinter) addf(severity string, line int, format string, args ...interface{}) {
+	l.issues = append(l.issues, RecipeLintIssue{
+		Severity: severity,
+		Line:     line,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// lineOf returns the first line containing s, or zero when none does.
+func (l *recipeLinter) lineOf(s string) int {
+	for n, line := range l.lines {
+		if strings.Contains(line, s) {
+			return n + 1
+		}
+	}
+
+	return 0
+}
+
+// templateVarLine returns the first line referencing the named variable in a
+// template, or zero when none does.
+func (l *recipeLinter) templateVarLine(name string) int {
+	re := regexp.MustCompile(`\{\{-?\s*\.` + regexp.QuoteMeta(name) + `\b`)
+	for n, line := range l.lines {
+		if re.MatchString(line) {
+			return n + 1
+		}
+	}
+
+	return 0
+}
+
+// keyLine returns the line of the given top level key, or zero when the key is
+// not set.
+func (l *recipeLinter) keyLine(key string) int {
+	for n, line := range l.lines {
+		if strings.HasPrefix(line, key+":") {
+			return n + 1
+		}
+	}
+
+	return 0
+}
+
+// lintKeys flags top level keys that are not recipe fields, which are most
+// often misspelled and silently ignored.
+func (l *recipeLinter) lintKeys(raw map[string]interface{}) {
+	known := recipeFieldNames()
+
+	keys := []string{}
+	for k := range raw {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if !known[k] {
+			l.addf(lintSeverityWarning, l.keyLine(k), "unknown field %s will be ignored", k)
+		}
+	}
+}
+
+func (l *recipeLinter) lintRequiredFields(r *types.OpenInstallationRecipe) {
+	if r.Name == "" {
+		l.addf(lintSeverityError, 0, "name is required")
+	}
+
+	if strings.TrimSpace(r.Install) == "" {
+		l.addf(lintSeverityError, 0, "install is required")
+	}
+
+	if r.DisplayName == "" {
+		l.addf(lintSeverityWarning, 0, "displayName is not set, the name will be shown to users")
+	}
+
+	if r.Description == "" {
+		l.addf(lintSeverityWarning, 0, "description is not set")
+	}
+
+	if len(r.InstallTargets) == 0 {
+		l.addf(lintSeverityWarning, 0, "installTargets is not set, the recipe will be offered on every host")
+	}
+
+	for _, d := range r.Dependencies {
+		if d == r.Name {
+			l.addf(lintSeverityError, l.keyLine("dependencies"), "the recipe depends on itself")
+		}
+	}
+}
+
+func (l *recipeLinter) lintValidationNRQL(r *types.OpenInstallationRecipe) {
+	nrql := strings.TrimSpace(string(r.ValidationNRQL))
+	line := l.keyLine("validationNrql")
+
+	if nrql == "" {
+		l.addf(lintSeverityWarning, 0, "validationNrql is not set, the data of the recipe will not be validated")
+		return
+	}
+
+	upper := strings.ToUpper(nrql)
+	if !strings.HasPrefix(upper, "SELECT ") && !strings.HasPrefix(upper, "FROM ") {
+		l.addf(lintSeverityError, line, "validationNrql must be a NRQL query starting with SELECT or FROM")
+	}
+
+	// The query is resolved with the variables of the recipe's execution.
+	declared := map[string]bool{}
+	for _, name := range execution.BuiltinRecipeVarNames() {
+		declared[name] = true
+	}
+	for _, v := range r.InputVars {
+		declared[v.Name] = true
+	}
+
+	for _, name := range templateVarNames(nrql) {
+		if !declared[name] {
+			l.addf(lintSeverityError, line, "validationNrql references %s, which is not declared as an input variable and is not set by the installer", name)
+		}
+	}
+}
+
+func (l *recipeLinter) lintInputVars(r *types.OpenInstallationRecipe) {
+	seen := map[string]bool{}
+
+	for n, v := range r.InputVars {
+		if v.Name == "" {
+			l.addf(lintSeverityError, l.keyLine("inputVars"), "input variable %d has no name", n+1)
+			continue
+		}
+
+		if seen[v.Name] {
+			l.addf(lintSeverityWarning, l.lineOf("name: "+v.Name), "input variable %s is declared more than once", v.Name)
+		}
+		seen[v.Name] = true
+
+		if utils.StringInSlice(v.Name, execution.BuiltinRecipeVarNames()) {
+			l.addf(lintSeverityWarning, l.lineOf("name: "+v.Name), "input variable %s overrides the variable of the same name set by the installer", v.Name)
+		}
+	}
+}
+
+// lintInstall checks the install is a go-task taskfile with a default task,
+// whose steps only reference declared variables.
+func (l *recipeLinter) lintInstall(r *types.OpenInstallationRecipe) {
+	if strings.TrimSpace(r.Install) == "" {
+		return
+	}
+
+	installLine := l.keyLine("install")
+
+	var taskfile struct {
+		Version interface{}                       `yaml:"version"`
+		Vars    map[string]interface{}            `yaml:"vars"`
+		Tasks   map[string]map[string]interface{} `yaml:"tasks"`
+	}
+	if err := yaml.Unmarshal([]byte(r.Install), &taskfile); err != nil {
+		l.addf(lintSeverityError, installLine, "install is not a valid taskfile: %s", err)
+		return
+	}
+
+	if taskfile.Version == nil {
+		l.addf(lintSeverityWarning, installLine, "install does not set the taskfile version")
+	}
+
+	if len(taskfile.Tasks) == 0 {
+		l.addf(lintSeverityError, installLine, "install defines no tasks")
+		return
+	}
+
+	if _, ok := taskfile.Tasks["default"]; !ok {
+		l.addf(lintSeverityError, installLine, "install has no default task, which is the task that is run")
+	}
+
+	declared := map[string]bool{}
+	for _, names := range [][]string{execution.BuiltinRecipeVarNames(), goTaskVarNames} {
+		for _, name := range names {
+			declared[name] = true
+		}
+	}
+	for _, v := range r.InputVars {
+		declared[v.Name] = true
+	}
+	for _, defaults := range r.PlatformDefaults {
+		for name := range defaults {
+			declared[name] = true
+		}
+	}
+	for name := range taskfile.Vars {
+		declared[name] = true
+	}
+	for _, task := range taskfile.Tasks {
+		if vars, ok := task["vars"].(map[interface{}]interface{}); ok {
+			for name := range vars {
+				declared[fmt.Sprint(name)] = true
+			}
+		}
+	}
+
+	for _, name := range templateVarNames(r.Install) {
+		if !declared[name] {
+			l.addf(lintSeverityWarning, l.templateVarLine(name), "install references variable %s, which is not declared as an input variable or taskfile variable, and is not set by the installer", name)
+		}
+	}
+}
+
+func (l *recipeLinter) lintMatches(r *types.OpenInstallationRecipe) {
+	for _, p := range r.ProcessMatch {
+		if _, err := regexp.Compile(p); err != nil {
+			l.addf(lintSeverityError, l.lineOf(p), "processMatch %s is not a valid regular expression: %s", p, err)
+		}
+	}
+
+	for n, m := range r.LogMatch {
+		if m.File == "" {
+			l.addf(lintSeverityError, l.keyLine("logMatch"), "logMatch %d has no file", n+1)
+		}
+	}
+}
+
+// templateVarNames returns the distinct names of the variables referenced by
+// templates in s, in order of first reference.
+func templateVarNames(s string) []string {
+	names := []string{}
+	seen := map[string]bool{}
+
+	for _, m := range templateVarRegex.FindAllStringSubmatch(s, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			names = append(names, m[1])
+		}
+	}
+
+	return names
+}
+
+// recipeFieldNames returns the names of the fields of a recipe file.
+func recipeFieldNames() map[string]bool {
+	names := map[string]bool{}
+
+	t := reflect.TypeOf(types.OpenInstallationRecipe{})
+	for n := 0; n < t.NumField(); n++ {
+		name := strings.Split(t.Field(n).Tag.Get("yaml"), ",")[0]
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+
+	return names
+}
+
+func yamlErrorLine(err error) int {
+	m := yamlErrorLineRegex.FindStringSubmatch(err.Error())
+	if m == nil {
+		return 0
+	}
+
+	line, _ := strconv.Atoi(m[1])
+	return line
+}
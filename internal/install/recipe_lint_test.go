@@ -0,0 +1,148 @@
+// +build unit
+
+package install
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const lintValidRecipe = `name: test-recipe
+displayName: Test Recipe
+description: Installs the test integration
+installTargets:
+  - type: host
+    os: linux
+processMatch:
+  - mysqld
+inputVars:
+  - name: DB_USER
+    prompt: Database user
+validationNrql: "SELECT count(*) FROM SystemSample WHERE hostname = '{{.HOSTNAME}}'"
+install:
+  version: "3"
+  vars:
+    CONFIG_DIR: /etc/newrelic-infra
+  tasks:
+    default:
+      cmds:
+        - task: configure
+    configure:
+      vars:
+        CONFIG_FILE: mysql.yml
+      cmds:
+        - echo {{.DB_USER}} {{.NEW_RELIC_LICENSE_KEY}} > {{.CONFIG_DIR}}/{{.CONFIG_FILE}}
+`
+
+func TestLintRecipe_Valid(t *testing.T) {
+	require.Empty(t, lintRecipe(lintValidRecipe))
+}
+
+func TestLintRecipe_Unparseable(t *testing.T) {
+	issues := lintRecipe("name: test\ninstall: [\n")
+
+	require.Len(t, issues, 1)
+	require.Equal(t, lintSeverityError, issues[0].Severity)
+	require.Contains(t, issues[0].Message, "could not be parsed")
+}
+
+func TestLintRecipe_WrongType(t *testing.T) {
+	issues := lintRecipe("name: test\nkeywords: mysql\n")
+
+	require.Len(t, issues, 1)
+	require.Equal(t, lintSeverityError, issues[0].Severity)
+}
+
+func TestLintRecipe_RequiredFields(t *testing.T) {
+	issues := lintRecipe("description: no name or install\n")
+
+	require.Contains(t, lintMessages(issues, lintSeverityError), "name is required")
+	require.Contains(t, lintMessages(issues, lintSeverityError), "install is required")
+	require.Contains(t, lintMessages(issues, lintSeverityWarning), "validationNrql is not set, the data of the recipe will not be validated")
+}
+
+func TestLintRecipe_ValidationNRQL(t *testing.T) {
+	recipe := strings.Replace(lintValidRecipe,
+		`"SELECT count(*) FROM SystemSample WHERE hostname = '{{.HOSTNAME}}'"`,
+		`"SELEC count(*) FROM SystemSample WHERE port = '{{.PORT}}'"`, 1)
+
+	issues := lintRecipe(recipe)
+
+	require.Equal(t, []RecipeLintIssue{
+		{Severity: lintSeverityError, Line: 12, Message: "validationNrql must be a NRQL query starting with SELECT or FROM"},
+		{Severity: lintSeverityError, Line: 12, Message: "validationNrql references PORT, which is not declared as an input variable and is not set by the installer"},
+	}, issues)
+}
+
+func TestLintRecipe_UndeclaredVariable(t *testing.T) {
+	recipe := strings.Replace(lintValidRecipe, "{{.DB_USER}}", "{{.DB_USR}}", 1)
+
+	issues := lintRecipe(recipe)
+
+	require.Len(t, issues, 1)
+	require.Equal(t, lintSeverityWarning, issues[0].Severity)
+	require.Equal(t, 25, issues[0].Line)
+	require.Contains(t, issues[0].Message, "DB_USR")
+}
+
+func TestLintRecipe_NoDefaultTask(t *testing.T) {
+	recipe := strings.Replace(lintValidRecipe, "    default:\n      cmds:\n        - task: configure\n", "", 1)
+
+	issues := lintRecipe(recipe)
+
+	require.Equal(t, []string{"install has no default task, which is the task that is run"}, lintMessages(issues, lintSeverityError))
+	require.Equal(t, 13, issues[0].Line)
+}
+
+func TestLintRecipe_CommonMistakes(t *testing.T) {
+	recipe := lintValidRecipe + `successLink:
+  type: EXPLORER
+dependencies:
+  - test-recipe
+logMatch:
+  - name: mysql
+`
+	recipe = strings.Replace(recipe, "  - mysqld\n", "  - mysqld(\n", 1)
+	recipe = strings.Replace(recipe, "  - name: DB_USER\n", "  - name: DB_USER\n  - name: HOSTNAME\n", 1)
+
+	issues := lintRecipe(recipe)
+
+	require.ElementsMatch(t, []string{
+		"the recipe depends on itself",
+		"logMatch 1 has no file",
+		"processMatch mysqld( is not a valid regular expression: error parsing regexp: missing closing ): `mysqld(`",
+	}, lintMessages(issues, lintSeverityError))
+	require.ElementsMatch(t, []string{
+		"unknown field successLink will be ignored",
+		"input variable HOSTNAME overrides the variable of the same name set by the installer",
+	}, lintMessages(issues, lintSeverityWarning))
+}
+
+func TestFormatLintIssues(t *testing.T) {
+	content := "name: test\nvalidationNrql: SELEC 1\n"
+	issues := []RecipeLintIssue{
+		{Severity: lintSeverityWarning, Message: "description is not set"},
+		{Severity: lintSeverityError, Line: 2, Message: "validationNrql must be a NRQL query starting with SELECT or FROM"},
+	}
+
+	require.Equal(t, `recipe.yml: warning: description is not set
+recipe.yml:2: error: validationNrql must be a NRQL query starting with SELECT or FROM
+     2 | validationNrql: SELEC 1
+1 error(s), 1 warning(s)
+`, formatLintIssues("recipe.yml", content, issues))
+
+	require.Equal(t, "recipe.yml: no problems found\n", formatLintIssues("recipe.yml", content, nil))
+}
+
+func lintMessages(issues []RecipeLintIssue, severity string) []string {
+	messages := []string{}
+	for _, issue := range issues {
+		if issue.Severity == severity {
+			messages = append(messages, issue.Message)
+		}
+	}
+
+	return messages
+}
@@ -0,0 +1,154 @@
+package install
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+const (
+	listedRecipeInstalled   = "INSTALLED"
+	listedRecipeAvailable   = "AVAILABLE"
+	listedRecipeUnsupported = "UNSUPPORTED"
+)
+
+// ListedRecipe is a recipe offered for the host, and whether it can be
+// installed on it.
+type ListedRecipe struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Status      string `json:"status"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// listRecipes discovers the host and lists every recipe offered for it: the
+// infrastructure agent and logging recipes followed by the recommended
+// integrations.  Nothing is prompted for or installed.
+func (i *RecipeInstaller) listRecipes(ctx context.Context) ([]ListedRecipe, error) {
+	m, err := i.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// An unsupported host is listed rather than failed, so that the reason is
+	// shown against each recipe.
+	hostErr := i.manifestValidator.Execute(m)
+
+	offered, err := i.offeredRecipes(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+
+	listed := make([]ListedRecipe, len(offered))
+	for n, r := range offered {
+		listed[n] = i.listRecipe(ctx, m, r, hostErr)
+	}
+
+	return listed, nil
+}
+
+// offeredRecipes returns every recipe offered for the host: the
+// infrastructure agent and logging recipes followed by the recommended
+// integrations, each once.
+func (i *RecipeInstaller) offeredRecipes(ctx context.Context, m *types.DiscoveryManifest) ([]types.OpenInstallationRecipe, error) {
+	offered := []types.OpenInstallationRecipe{}
+	for _, name := range []string{i.infraAgentRecipeName(), i.loggingRecipeName()} {
+		r, err := i.recipeFetcher.FetchRecipe(ctx, m, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if r == nil {
+			log.Debugf("recipe %s not found", name)
+			continue
+		}
+
+		offered = append(offered, *r)
+	}
+
+	recommendations, err := i.fetchRecommendations(m)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	for _, r := range offered {
+		seen[r.Name] = true
+	}
+
+	for _, r := range recommendations {
+		if !seen[r.Name] {
+			seen[r.Name] = true
+			offered = append(offered, r)
+		}
+	}
+
+	return offered, nil
+}
+
+// listRecipe applies the checks made before installing a recipe, without
+// reporting their outcome, to determine whether it is installed, available or
+// unsupported on the host.
+func (i *RecipeInstaller) listRecipe(ctx context.Context, m *types.DiscoveryManifest, r types.OpenInstallationRecipe, hostErr error) ListedRecipe {
+	l := ListedRecipe{
+		Name:        r.Name,
+		DisplayName: recipeDisplayName(r),
+		Status:      listedRecipeUnsupported,
+	}
+
+	if hostErr != nil {
+		l.Reason = hostErr.Error()
+		return l
+	}
+
+	if reason := hostUnsupportedReason(m, r); reason != "" {
+		l.Reason = reason
+		return l
+	}
+
+	if reason := i.unentitledReason(ctx, r); reason != "" {
+		l.Reason = reason
+		return l
+	}
+
+	if inUse := m.PortsInUse(r.RequiredPorts); len(inUse) > 0 {
+		ports := make([]string, len(inUse))
+		for n, p := range inUse {
+			ports[n] = strconv.Itoa(p)
+		}
+		l.Reason = fmt.Sprintf("requires ports already in use: %s", strings.Join(ports, ", "))
+		return l
+	}
+
+	l.Status = listedRecipeAvailable
+
+	if i.recipeDetector != nil {
+		installed, _, err := i.recipeDetector.DetectRecipe(ctx, *m, r)
+		if err != nil {
+			log.Debugf("could not determine whether %s is already installed: %s", r.Name, err)
+		} else if installed {
+			l.Status = listedRecipeInstalled
+		}
+	}
+
+	return l
+}
+
+// hostUnsupportedReason returns why the recipe cannot be installed on the
+// host, or "" when it can.
+func hostUnsupportedReason(m *types.DiscoveryManifest, r types.OpenInstallationRecipe) string {
+	if len(r.InstallTargets) > 0 && len(m.ConstrainRecipes([]types.OpenInstallationRecipe{r})) == 0 {
+		return "no install target matches this host"
+	}
+
+	if r.HasApplicationTargetType() && !r.IsApm() {
+		return "installed with the application rather than on the host"
+	}
+
+	return ""
+}
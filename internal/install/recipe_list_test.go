@@ -0,0 +1,73 @@
+// +build unit
+
+package install
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/discovery"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/validation"
+)
+
+func TestListRecipes(t *testing.T) {
+	md := discovery.NewMockDiscovererWithManifest(types.DiscoveryManifest{
+		OS:             "linux",
+		ListeningPorts: []int{3306},
+	})
+	rf := recipes.NewMockRecipeFetcher()
+	rf.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{Name: types.InfraAgentRecipeName, DisplayName: "Infrastructure Agent"},
+		{Name: types.LoggingRecipeName},
+	}
+	rf.FetchRecommendationsVal = []types.OpenInstallationRecipe{
+		{Name: types.InfraAgentRecipeName},
+		{Name: "mysql", RequiredPorts: []int{3306}},
+		{Name: "iis", InstallTargets: []types.OpenInstallationRecipeInstallTarget{{Os: types.OpenInstallationOperatingSystemTypes.WINDOWS}}},
+		{Name: "nginx"},
+	}
+	detector := validation.NewMockRecipeDetector()
+	detector.SetInstalled(types.InfraAgentRecipeName, "testGUID")
+
+	i := RecipeInstaller{
+		discoverer:        md,
+		manifestValidator: discovery.NewEmptyManifestValidator(),
+		recipeFetcher:     rf,
+		recipeDetector:    detector,
+	}
+
+	listed, err := i.listRecipes(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []ListedRecipe{
+		{Name: types.InfraAgentRecipeName, DisplayName: "Infrastructure Agent", Status: listedRecipeInstalled},
+		{Name: types.LoggingRecipeName, DisplayName: types.LoggingRecipeName, Status: listedRecipeAvailable},
+		{Name: "mysql", DisplayName: "mysql", Status: listedRecipeUnsupported, Reason: "requires ports already in use: 3306"},
+		{Name: "iis", DisplayName: "iis", Status: listedRecipeUnsupported, Reason: "no install target matches this host"},
+		{Name: "nginx", DisplayName: "nginx", Status: listedRecipeAvailable},
+	}, listed)
+}
+
+func TestListRecipes_UnsupportedHost(t *testing.T) {
+	md := discovery.NewMockDiscovererWithManifest(types.DiscoveryManifest{OS: "darwin"})
+	rf := recipes.NewMockRecipeFetcher()
+	rf.FetchRecipeVal = &types.OpenInstallationRecipe{Name: types.InfraAgentRecipeName}
+
+	i := RecipeInstaller{
+		discoverer:        md,
+		manifestValidator: discovery.NewManifestValidator(),
+		recipeFetcher:     rf,
+		recipeDetector:    validation.NewMockRecipeDetector(),
+	}
+
+	listed, err := i.listRecipes(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, listed)
+	for _, l := range listed {
+		require.Equal(t, listedRecipeUnsupported, l.Status)
+		require.NotEmpty(t, l.Reason)
+	}
+}
@@ -0,0 +1,20 @@
+package install
+
+import (
+	"sort"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// byName returns the recipes ordered by name.  Recipes are put in this order
+// before any other is applied, so that recipes fetched in a different order,
+// such as from a paginated or cached source, are offered and installed in the
+// same order on identical hosts.
+func byName(recipes []types.OpenInstallationRecipe) []types.OpenInstallationRecipe {
+	sorted := append([]types.OpenInstallationRecipe{}, recipes...)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		return sorted[a].Name < sorted[b].Name
+	})
+
+	return sorted
+}
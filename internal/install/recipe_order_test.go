@@ -0,0 +1,51 @@
+// +build unit
+
+package install
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func orderedNames(recipes []types.OpenInstallationRecipe) []string {
+	names := []string{}
+	for _, r := range recipes {
+		names = append(names, r.Name)
+	}
+
+	return names
+}
+
+func TestByName(t *testing.T) {
+	recipes := []types.OpenInstallationRecipe{{Name: "redis"}, {Name: "apache"}, {Name: "mysql"}}
+
+	require.Equal(t, []string{"apache", "mysql", "redis"}, orderedNames(byName(recipes)))
+	require.Equal(t, []string{"redis", "apache", "mysql"}, orderedNames(recipes))
+}
+
+func TestFilterIntegrations_StableOrder(t *testing.T) {
+	redis := types.OpenInstallationRecipe{Name: "redis", DisplayName: "Redis", RelevanceScore: 0.5}
+	mysql := types.OpenInstallationRecipe{Name: "mysql", DisplayName: "MySQL", RelevanceScore: 0.5}
+	apache := types.OpenInstallationRecipe{Name: "apache", DisplayName: "Apache"}
+	nginx := types.OpenInstallationRecipe{Name: "nginx", DisplayName: "NGINX"}
+	required := types.OpenInstallationRecipe{Name: "zookeeper", DisplayName: "ZooKeeper", Requirement: types.OpenInstallationRequirementTypes.REQUIRED}
+
+	shuffles := [][]types.OpenInstallationRecipe{
+		{redis, mysql, apache, nginx, required},
+		{nginx, required, apache, redis, mysql},
+		{apache, mysql, required, nginx, redis},
+	}
+
+	for _, recommended := range shuffles {
+		status = execution.NewInstallStatus([]execution.StatusSubscriber{execution.NewMockStatusReporter()}, execution.NewConcreteSuccessLinkGenerator())
+		i := RecipeInstaller{InstallerContext{AssumeYes: true}, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+
+		selected, err := i.filterIntegrations(&types.DiscoveryManifest{}, recommended)
+		require.NoError(t, err)
+		require.Equal(t, []string{"zookeeper", "mysql", "redis", "apache", "nginx"}, orderedNames(selected))
+	}
+}
@@ -0,0 +1,20 @@
+package install
+
+import (
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// checkPackageManagers warns when none of the package managers the recipe
+// supports was detected on the host.  Detection can miss package managers
+// installed in unusual locations, so the recipe is still executed.
+func (i *RecipeInstaller) checkPackageManagers(m *types.DiscoveryManifest, r *types.OpenInstallationRecipe) {
+	if m.HasAnyPackageManager(r.PackageManagers) {
+		return
+	}
+
+	log.Warnf("%s supports the %s package managers, none of which were found on this host", r.Name, strings.Join(r.PackageManagers, ", "))
+}
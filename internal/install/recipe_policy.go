@@ -0,0 +1,168 @@
+package install
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+
+	"github.com/newrelic/newrelic-cli/internal/config"
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// DefaultRecipePolicyFile is the name of the recipe policy file in the CLI's
+// config directory, enforced when no --recipe-policy is given.
+const DefaultRecipePolicyFile = "recipe-policy.yml"
+
+// recipePolicySkipPrefix prefixes the reason of recipes skipped by the recipe
+// policy.
+const recipePolicySkipPrefix = "policy: "
+
+// RecipePolicy restricts the recipes an install may run to those whose names
+// match an Allow pattern, when any are given, and none of the Deny patterns.
+// Patterns are those of filepath.Match, such as mysql or *-open-source-*.
+// Denials take precedence, so that a denied recipe never runs, even when
+// requested by name.
+type RecipePolicy struct {
+	Allow []string `yaml:"allow,omitempty"`
+	Deny  []string `yaml:"deny,omitempty"`
+}
+
+// LoadRecipePolicy reads the recipe policy from a YAML or JSON file.  An error
+// is returned when a pattern is invalid.
+func LoadRecipePolicy(path string) (RecipePolicy, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return RecipePolicy{}, fmt.Errorf("could not read recipe policy file %s: %s", path, err)
+	}
+
+	p := RecipePolicy{}
+	if err := yaml.UnmarshalStrict(data, &p); err != nil {
+		return RecipePolicy{}, fmt.Errorf("could not parse recipe policy file %s: %s", path, err)
+	}
+
+	if err := p.validate(); err != nil {
+		return RecipePolicy{}, fmt.Errorf("invalid recipe policy file %s: %s", path, err)
+	}
+
+	return p, nil
+}
+
+func (p RecipePolicy) validate() error {
+	for _, pattern := range append(append([]string{}, p.Allow...), p.Deny...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %s: %s", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// disallowedReason returns why the policy disallows the recipe of the given
+// name, or "" when it is allowed.
+func (p RecipePolicy) disallowedReason(name string) string {
+	if pattern, ok := matchingPattern(name, p.Deny); ok {
+		return fmt.Sprintf("%s matches the denied pattern %s", name, pattern)
+	}
+
+	if len(p.Allow) == 0 {
+		return ""
+	}
+
+	if _, ok := matchingPattern(name, p.Allow); !ok {
+		return fmt.Sprintf("%s matches none of the allowed patterns", name)
+	}
+
+	return ""
+}
+
+func matchingPattern(name string, patterns []string) (string, bool) {
+	for _, p := range patterns {
+		if matched, _ := filepath.Match(p, name); matched {
+			return p, true
+		}
+	}
+
+	return "", false
+}
+
+// defaultRecipePolicyPath returns the path of the recipe policy file in the
+// CLI's config directory when there is one, or else "".
+func defaultRecipePolicyPath() string {
+	path := filepath.Join(config.DefaultConfigDirectory, DefaultRecipePolicyFile)
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+
+	return path
+}
+
+// loadRecipePolicy returns the recipe policy of the context, with the patterns
+// of the recipe policy file, when given, added to its own.  Should the file
+// fail to load, every recipe is denied, so that the policy is never bypassed.
+func loadRecipePolicy(ic InstallerContext) RecipePolicy {
+	if ic.RecipePolicyFile == "" {
+		return ic.RecipePolicy
+	}
+
+	p, err := LoadRecipePolicy(ic.RecipePolicyFile)
+	if err != nil {
+		log.Warnf("The recipe policy file could not be loaded, denying every recipe: %s", err)
+		p = RecipePolicy{Deny: []string{"*"}}
+	}
+
+	return RecipePolicy{
+		Allow: append(append([]string{}, ic.RecipePolicy.Allow...), p.Allow...),
+		Deny:  append(append([]string{}, ic.RecipePolicy.Deny...), p.Deny...),
+	}
+}
+
+// assertRecipePolicyIsValid ensures the recipe policy file, when given, can be
+// loaded.
+func assertRecipePolicyIsValid(ic InstallerContext) error {
+	if err := ic.RecipePolicy.validate(); err != nil {
+		return fmt.Errorf("invalid recipe policy: %s", err)
+	}
+
+	if ic.RecipePolicyFile == "" {
+		return nil
+	}
+
+	_, err := LoadRecipePolicy(ic.RecipePolicyFile)
+	return err
+}
+
+// skipIfDisallowed reports the recipe as skipped when the recipe policy
+// disallows it, returning whether it did.
+func (i *RecipeInstaller) skipIfDisallowed(r types.OpenInstallationRecipe) bool {
+	reason := i.RecipePolicy.disallowedReason(r.Name)
+	if reason == "" {
+		return false
+	}
+
+	i.status.RecipeSkipped(execution.RecipeStatusEvent{
+		Recipe: r,
+		Msg:    recipePolicySkipPrefix + reason,
+	})
+
+	return true
+}
+
+// skipDisallowedRecipes reports the recipes the recipe policy disallows as
+// skipped, returning the others.
+func (i *RecipeInstaller) skipDisallowedRecipes(recipes []types.OpenInstallationRecipe) []types.OpenInstallationRecipe {
+	allowed := []types.OpenInstallationRecipe{}
+	for _, r := range recipes {
+		if i.skipIfDisallowed(r) {
+			continue
+		}
+
+		allowed = append(allowed, r)
+	}
+
+	return allowed
+}
@@ -0,0 +1,181 @@
+// +build unit
+
+package install
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/validation"
+)
+
+func TestRecipePolicy_DisallowedReason(t *testing.T) {
+	tests := map[string]struct {
+		policy  RecipePolicy
+		name    string
+		allowed bool
+	}{
+		"no policy":             {RecipePolicy{}, "mysql", true},
+		"allowed":               {RecipePolicy{Allow: []string{"mysql", "nginx"}}, "mysql", true},
+		"allowed by pattern":    {RecipePolicy{Allow: []string{"*-open-source-*"}}, "mysql-open-source-integration", true},
+		"not in allowlist":      {RecipePolicy{Allow: []string{"nginx"}}, "mysql", false},
+		"denied":                {RecipePolicy{Deny: []string{"mysql"}}, "mysql", false},
+		"denied by pattern":     {RecipePolicy{Deny: []string{"my*"}}, "mysql", false},
+		"not denied":            {RecipePolicy{Deny: []string{"nginx"}}, "mysql", true},
+		"denial wins":           {RecipePolicy{Allow: []string{"*"}, Deny: []string{"mysql"}}, "mysql", false},
+		"denial wins by name":   {RecipePolicy{Allow: []string{"mysql"}, Deny: []string{"*"}}, "mysql", false},
+		"allowed with denylist": {RecipePolicy{Allow: []string{"*"}, Deny: []string{"mysql"}}, "nginx", true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.allowed, tc.policy.disallowedReason(tc.name) == "")
+		})
+	}
+}
+
+func TestLoadRecipePolicy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "recipe-policy")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, DefaultRecipePolicyFile)
+	require.NoError(t, ioutil.WriteFile(path, []byte("allow: [\"*\"]\ndeny: [mysql]\n"), 0600))
+
+	p, err := LoadRecipePolicy(path)
+	require.NoError(t, err)
+	require.Equal(t, RecipePolicy{Allow: []string{"*"}, Deny: []string{"mysql"}}, p)
+
+	p = loadRecipePolicy(InstallerContext{RecipePolicy: RecipePolicy{Deny: []string{"nginx"}}, RecipePolicyFile: path})
+	require.Equal(t, []string{"nginx", "mysql"}, p.Deny)
+
+	for _, content := range []string{"deny: [\"[\"]", "permit: [mysql]", "deny: mysql: nginx"} {
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+		_, err = LoadRecipePolicy(path)
+		require.Error(t, err, content)
+		require.Error(t, assertRecipePolicyIsValid(InstallerContext{RecipePolicyFile: path}), content)
+
+		// A policy that cannot be loaded denies every recipe.
+		require.NotEmpty(t, loadRecipePolicy(InstallerContext{RecipePolicyFile: path}).disallowedReason("mysql"), content)
+	}
+
+	_, err = LoadRecipePolicy(filepath.Join(dir, "missing.yml"))
+	require.Error(t, err)
+}
+
+func TestInstall_RecipeSkipped_Policy(t *testing.T) {
+	ic := InstallerContext{
+		AssumeYes: true,
+		RecipePolicy: RecipePolicy{
+			Allow: []string{"*"},
+			Deny:  []string{"nginx", types.LoggingRecipeName},
+		},
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{
+		{Name: "mysql", ValidationNRQL: "testNrql"},
+		{Name: "nginx", ValidationNRQL: "testNrql"},
+	}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{Name: types.InfraAgentRecipeName, ValidationNRQL: "testNrql"},
+		{Name: types.LoggingRecipeName, ValidationNRQL: "testNrql"},
+	}
+	v = validation.NewMockRecipeValidator()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+
+	reporter := statusReporters[0].(*execution.MockStatusReporter)
+	require.Equal(t, 1, reporter.ReportSkipped["nginx"])
+	require.Equal(t, 1, reporter.ReportSkipped[types.LoggingRecipeName])
+	require.Equal(t, 0, reporter.ReportInstalled["nginx"])
+	require.Equal(t, 1, reporter.ReportInstalled["mysql"])
+	require.Equal(t, 1, reporter.ReportInstalled[types.InfraAgentRecipeName])
+
+	for _, s := range status.RecipesSkipped {
+		require.Contains(t, s.SkipReason, recipePolicySkipPrefix)
+	}
+}
+
+func TestInstall_GuidedInfraDeniedByPolicy(t *testing.T) {
+	ic := InstallerContext{
+		AssumeYes:    true,
+		RecipePolicy: RecipePolicy{Allow: []string{"mysql"}},
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{Name: types.InfraAgentRecipeName, ValidationNRQL: "testNrql"},
+		{Name: types.LoggingRecipeName, ValidationNRQL: "testNrql"},
+	}
+	e := execution.NewMockRecipeExecutor()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "recipe policy")
+	require.Equal(t, 0, e.ExecuteCallCount)
+}
+
+func TestInstall_TargetedRecipeDeniedByPolicy(t *testing.T) {
+	ic := InstallerContext{
+		RecipeNames:  []string{types.InfraAgentRecipeName, testRecipeName},
+		RecipePolicy: RecipePolicy{Allow: []string{"*"}, Deny: []string{testRecipeName}},
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{Name: types.InfraAgentRecipeName, ValidationNRQL: "testNrql"},
+		{Name: testRecipeName, ValidationNRQL: "testNrql"},
+	}
+	e := execution.NewMockRecipeExecutor()
+	v = validation.NewMockRecipeValidator()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+
+	reporter := statusReporters[0].(*execution.MockStatusReporter)
+	require.Equal(t, 1, reporter.ReportSkipped[testRecipeName])
+	require.Equal(t, 0, reporter.ReportInstalled[testRecipeName])
+	require.Equal(t, 1, reporter.ReportInstalled[types.InfraAgentRecipeName])
+	require.Equal(t, 1, e.ExecuteCallCount)
+}
+
+func TestInstall_PrerequisiteDeniedByPolicy(t *testing.T) {
+	ic := InstallerContext{
+		AssumeYes:    true,
+		RecipeNames:  []string{testRecipeName},
+		RecipePolicy: RecipePolicy{Deny: []string{types.InfraAgentRecipeName}},
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{Name: testRecipeName, ValidationNRQL: "testNrql", Dependencies: []string{types.InfraAgentRecipeName}},
+		{Name: types.InfraAgentRecipeName, ValidationNRQL: "testNrql"},
+	}
+	e := execution.NewMockRecipeExecutor()
+	v = validation.NewMockRecipeValidator()
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+
+	reporter := statusReporters[0].(*execution.MockStatusReporter)
+	require.Equal(t, 1, reporter.ReportSkipped[types.InfraAgentRecipeName])
+	require.Equal(t, 1, reporter.ReportSkipped[testRecipeName])
+	require.Equal(t, 0, e.ExecuteCallCount)
+}
@@ -0,0 +1,38 @@
+package install
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// checkRequiredPorts ensures none of the ports the recipe requires were found
+// in use during discovery.  A conflict is reported as a failure of the recipe
+// before it is executed, or only warned about with --ignore-port-conflicts.
+// Ports are not checked on a reinstall, as the integration being reinstalled
+// may itself be listening on them.
+func (i *RecipeInstaller) checkRequiredPorts(m *types.DiscoveryManifest, r *types.OpenInstallationRecipe) error {
+	if i.Reinstall {
+		return nil
+	}
+
+	inUse := m.PortsInUse(r.RequiredPorts)
+	if len(inUse) == 0 {
+		return nil
+	}
+
+	err := NewErrPortsInUse(r.Name, inUse)
+
+	if i.IgnorePortConflicts {
+		log.Warnf("Installing despite port conflicts: %s", err)
+		return nil
+	}
+
+	i.status.RecipeFailed(execution.RecipeStatusEvent{
+		Recipe: *r,
+		Msg:    err.Error(),
+	})
+
+	return err
+}
@@ -0,0 +1,169 @@
+package install
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// prerequisiteResolver orders recipes after the recipes they depend on,
+// offering to add dependencies that were not selected.
+type prerequisiteResolver struct {
+	installer *RecipeInstaller
+	ctx       context.Context
+	manifest  *types.DiscoveryManifest
+	// selected are the recipes selected for installation, by name.
+	selected map[string]types.OpenInstallationRecipe
+	// provided are the names of recipes installed separately, which satisfy
+	// dependencies without being ordered.
+	provided map[string]bool
+	// unavailable are the names of recipes that will not be installed, and
+	// cannot be added.
+	unavailable map[string]bool
+	// excluded are the names of recipes that were declined or skipped.
+	excluded map[string]bool
+	visiting map[string]bool
+	ordered  []types.OpenInstallationRecipe
+	added    map[string]bool
+}
+
+// withPrerequisites returns the recipes, each preceded by the recipes it
+// depends on and without duplicates.  A dependency that was not selected is
+// added once the user agrees, or without prompting when --assumeYes is set,
+// and is reported as a prerequisite.  Recipes whose dependencies cannot be
+// installed are reported as skipped.  Dependencies on provided recipes are
+// already satisfied, while those on unavailable recipes cannot be.
+func (i *RecipeInstaller) withPrerequisites(ctx context.Context, m *types.DiscoveryManifest, recipes []types.OpenInstallationRecipe, provided []string, unavailable []string) ([]types.OpenInstallationRecipe, error) {
+	p := prerequisiteResolver{
+		installer:   i,
+		ctx:         ctx,
+		manifest:    m,
+		selected:    map[string]types.OpenInstallationRecipe{},
+		provided:    map[string]bool{},
+		unavailable: map[string]bool{},
+		excluded:    map[string]bool{},
+		visiting:    map[string]bool{},
+		added:       map[string]bool{},
+	}
+
+	for _, r := range recipes {
+		p.selected[r.Name] = r
+	}
+
+	for _, n := range provided {
+		p.provided[n] = true
+	}
+
+	for _, n := range unavailable {
+		p.unavailable[n] = true
+	}
+
+	for _, r := range recipes {
+		if _, err := p.add(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return p.ordered, nil
+}
+
+// add orders the recipe after its dependencies, returning whether it will be
+// installed.
+func (p *prerequisiteResolver) add(r types.OpenInstallationRecipe) (bool, error) {
+	if p.added[r.Name] {
+		return true, nil
+	}
+
+	if p.excluded[r.Name] {
+		return false, nil
+	}
+
+	if p.visiting[r.Name] {
+		log.Warnf("Recipe %s depends on itself through its dependencies.", r.Name)
+		return false, nil
+	}
+
+	p.visiting[r.Name] = true
+	defer delete(p.visiting, r.Name)
+
+	for _, name := range r.Dependencies {
+		ok, err := p.addDependency(name, r)
+		if err != nil {
+			return false, err
+		}
+
+		if !ok {
+			p.installer.status.RecipeSkipped(execution.RecipeStatusEvent{
+				Recipe: r,
+				Msg:    fmt.Sprintf("prerequisite %s will not be installed", name),
+			})
+			p.excluded[r.Name] = true
+			return false, nil
+		}
+	}
+
+	p.ordered = append(p.ordered, r)
+	p.added[r.Name] = true
+
+	return true, nil
+}
+
+// addDependency orders the named dependency of the recipe, returning whether
+// it will be installed.
+func (p *prerequisiteResolver) addDependency(name string, r types.OpenInstallationRecipe) (bool, error) {
+	if p.provided[name] || p.added[name] {
+		return true, nil
+	}
+
+	if p.unavailable[name] || p.excluded[name] {
+		return false, nil
+	}
+
+	if d, ok := p.selected[name]; ok {
+		return p.add(d)
+	}
+
+	d, err := p.installer.fetch(p.ctx, p.manifest, name)
+	if err != nil {
+		log.Warnf("Could not add %s, which %s depends on: %s", name, recipeDisplayName(r), err)
+		p.excluded[name] = true
+		return false, nil
+	}
+
+	if p.installer.skipIfDisallowed(*d) {
+		p.excluded[name] = true
+		return false, nil
+	}
+
+	ok, err := p.confirm(*d, r)
+	if err != nil {
+		return false, err
+	}
+
+	if !ok {
+		p.excluded[name] = true
+		return false, nil
+	}
+
+	p.installer.status.RecipeAvailable(*d)
+	p.installer.status.RecipePrerequisiteAdded(*d, r)
+
+	return p.add(*d)
+}
+
+// confirm asks whether to add the dependency of the recipe, unless
+// --assumeYes is set.
+func (p *prerequisiteResolver) confirm(d types.OpenInstallationRecipe, r types.OpenInstallationRecipe) (bool, error) {
+	if p.installer.AssumeYes {
+		log.Infof("Adding %s, which %s depends on.", recipeDisplayName(d), recipeDisplayName(r))
+		return true, nil
+	}
+
+	msg := showMessage(p.installer.messages().ConfirmPrerequisite, messageData{Recipe: recipeDisplayName(r), Dependency: recipeDisplayName(d)})
+
+	return p.installer.prompter.PromptYesNo(msg)
+}
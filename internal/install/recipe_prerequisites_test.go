@@ -0,0 +1,122 @@
+// +build unit
+
+package install
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/ux"
+)
+
+var (
+	prerequisiteRecipe = types.OpenInstallationRecipe{
+		Name:        "prerequisite",
+		DisplayName: "Prerequisite",
+	}
+	dependentRecipe = types.OpenInstallationRecipe{
+		Name:         "dependent",
+		DisplayName:  "Dependent",
+		Dependencies: []string{"prerequisite"},
+	}
+)
+
+func newPrerequisiteTestInstaller(mp *ux.MockPrompter, f *recipes.MockRecipeFetcher, statusReporter *execution.MockStatusReporter) *RecipeInstaller {
+	return &RecipeInstaller{
+		prompter:      mp,
+		recipeFetcher: f,
+		status:        execution.NewInstallStatus([]execution.StatusSubscriber{statusReporter}, nil),
+	}
+}
+
+func TestWithPrerequisites_OrdersSelectedDependencyFirst(t *testing.T) {
+	statusReporter := execution.NewMockStatusReporter()
+	mp := ux.NewMockPrompter()
+	f := recipes.NewMockRecipeFetcher()
+	i := newPrerequisiteTestInstaller(mp, f, statusReporter)
+
+	ordered, err := i.withPrerequisites(context.Background(), &types.DiscoveryManifest{}, []types.OpenInstallationRecipe{dependentRecipe, prerequisiteRecipe, otherRecipe}, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, []types.OpenInstallationRecipe{prerequisiteRecipe, dependentRecipe, otherRecipe}, ordered)
+	require.Equal(t, 0, mp.PromptYesNoCallCount)
+	require.Equal(t, 0, f.FetchRecipeCallCount)
+}
+
+func TestWithPrerequisites_AddsAcceptedDependency(t *testing.T) {
+	statusReporter := execution.NewMockStatusReporter()
+	mp := ux.NewMockPrompter()
+	mp.PromptYesNoVal = true
+	f := recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVal = &prerequisiteRecipe
+	i := newPrerequisiteTestInstaller(mp, f, statusReporter)
+
+	second := types.OpenInstallationRecipe{Name: "second", Dependencies: []string{"prerequisite"}}
+	ordered, err := i.withPrerequisites(context.Background(), &types.DiscoveryManifest{}, []types.OpenInstallationRecipe{dependentRecipe, second}, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, []types.OpenInstallationRecipe{prerequisiteRecipe, dependentRecipe, second}, ordered)
+	require.Equal(t, 1, mp.PromptYesNoCallCount)
+	require.Equal(t, 1, f.FetchRecipeCallCount)
+	require.Equal(t, []string{"dependent"}, i.status.Statuses[0].PrerequisiteOf)
+}
+
+func TestWithPrerequisites_AssumeYesAddsWithoutPrompting(t *testing.T) {
+	statusReporter := execution.NewMockStatusReporter()
+	mp := ux.NewMockPrompter()
+	f := recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVal = &prerequisiteRecipe
+	i := newPrerequisiteTestInstaller(mp, f, statusReporter)
+	i.AssumeYes = true
+
+	ordered, err := i.withPrerequisites(context.Background(), &types.DiscoveryManifest{}, []types.OpenInstallationRecipe{dependentRecipe}, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, []types.OpenInstallationRecipe{prerequisiteRecipe, dependentRecipe}, ordered)
+	require.Equal(t, 0, mp.PromptYesNoCallCount)
+}
+
+func TestWithPrerequisites_SkipsWhenDeclined(t *testing.T) {
+	statusReporter := execution.NewMockStatusReporter()
+	mp := ux.NewMockPrompter()
+	mp.PromptYesNoVal = false
+	f := recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVal = &prerequisiteRecipe
+	i := newPrerequisiteTestInstaller(mp, f, statusReporter)
+
+	ordered, err := i.withPrerequisites(context.Background(), &types.DiscoveryManifest{}, []types.OpenInstallationRecipe{dependentRecipe, otherRecipe}, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, []types.OpenInstallationRecipe{otherRecipe}, ordered)
+	require.Equal(t, 1, statusReporter.ReportSkipped["dependent"])
+}
+
+func TestWithPrerequisites_ProvidedAndUnavailable(t *testing.T) {
+	statusReporter := execution.NewMockStatusReporter()
+	mp := ux.NewMockPrompter()
+	f := recipes.NewMockRecipeFetcher()
+	i := newPrerequisiteTestInstaller(mp, f, statusReporter)
+
+	ordered, err := i.withPrerequisites(context.Background(), &types.DiscoveryManifest{}, []types.OpenInstallationRecipe{dependentRecipe}, []string{"prerequisite"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []types.OpenInstallationRecipe{dependentRecipe}, ordered)
+
+	ordered, err = i.withPrerequisites(context.Background(), &types.DiscoveryManifest{}, []types.OpenInstallationRecipe{dependentRecipe}, nil, []string{"prerequisite"})
+	require.NoError(t, err)
+	require.Empty(t, ordered)
+	require.Equal(t, 0, f.FetchRecipeCallCount)
+	require.Equal(t, 0, mp.PromptYesNoCallCount)
+}
+
+func TestWithPrerequisites_SkipsCircularDependencies(t *testing.T) {
+	statusReporter := execution.NewMockStatusReporter()
+	i := newPrerequisiteTestInstaller(ux.NewMockPrompter(), recipes.NewMockRecipeFetcher(), statusReporter)
+
+	a := types.OpenInstallationRecipe{Name: "a", Dependencies: []string{"b"}}
+	b := types.OpenInstallationRecipe{Name: "b", Dependencies: []string{"a"}}
+
+	ordered, err := i.withPrerequisites(context.Background(), &types.DiscoveryManifest{}, []types.OpenInstallationRecipe{a, b, otherRecipe}, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, []types.OpenInstallationRecipe{otherRecipe}, ordered)
+}
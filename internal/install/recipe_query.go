@@ -0,0 +1,99 @@
+package install
+
+import (
+	"context"
+	"errors"
+
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// InstallableRecipes are the recipes that apply to a host, categorized as a
+// guided install treats them.
+type InstallableRecipes struct {
+	// InfraAgent is the infrastructure agent recipe, installed first.
+	InfraAgent types.OpenInstallationRecipe
+	// Logging is the logging recipe, or nil when there is none for the host.
+	Logging *types.OpenInstallationRecipe
+	// Recommended are the integrations recommended for the host that can be
+	// installed on it, required ones first, then the most relevant.
+	Recommended []types.OpenInstallationRecipe
+	// Unsupported are the integrations recommended for the host that cannot
+	// be installed on it, with why.
+	Unsupported []UnsupportedRecipe
+}
+
+// UnsupportedRecipe is a recipe that cannot be installed on the host.
+type UnsupportedRecipe struct {
+	Recipe types.OpenInstallationRecipe
+	Reason string
+}
+
+// RecipeQuery configures QueryInstallableRecipes.
+type RecipeQuery struct {
+	// InfraAgentRecipeName and LoggingRecipeName name the infrastructure agent
+	// and logging recipes, types.InfraAgentRecipeName and
+	// types.LoggingRecipeName when empty.
+	InfraAgentRecipeName string
+	LoggingRecipeName    string
+	// SkipRecommendations leaves out the integrations recommended for the host.
+	SkipRecommendations bool
+}
+
+// QueryInstallableRecipes returns the recipes the fetcher offers for the host
+// of the discovery manifest, without prompting for, reporting or installing
+// anything.  An ErrUnsupportedPlatform is returned when there is no
+// infrastructure agent for the host's platform.
+func QueryInstallableRecipes(ctx context.Context, m *types.DiscoveryManifest, f recipes.RecipeFetcher, rq RecipeQuery) (*InstallableRecipes, error) {
+	infraAgentRecipeName := rq.InfraAgentRecipeName
+	if infraAgentRecipeName == "" {
+		infraAgentRecipeName = types.InfraAgentRecipeName
+	}
+
+	loggingRecipeName := rq.LoggingRecipeName
+	if loggingRecipeName == "" {
+		loggingRecipeName = types.LoggingRecipeName
+	}
+
+	infraAgentRecipe, err := fetchPlatformInfraAgentRecipe(ctx, f, m, infraAgentRecipeName)
+	if err != nil {
+		return nil, err
+	}
+
+	loggingRecipe, err := fetchRecipe(ctx, f, m, loggingRecipeName)
+	if err != nil {
+		if isCanceled(err) || !errors.Is(err, recipes.ErrRecipeNotFound) {
+			return nil, err
+		}
+
+		loggingRecipe = nil
+	}
+
+	q := InstallableRecipes{
+		InfraAgent:  *infraAgentRecipe,
+		Logging:     loggingRecipe,
+		Recommended: []types.OpenInstallationRecipe{},
+		Unsupported: []UnsupportedRecipe{},
+	}
+
+	if rq.SkipRecommendations {
+		return &q, nil
+	}
+
+	recommended, err := fetchRecommendedRecipes(ctx, f, m, infraAgentRecipeName, loggingRecipeName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range recommended {
+		if reason := hostUnsupportedReason(m, r); reason != "" {
+			q.Unsupported = append(q.Unsupported, UnsupportedRecipe{Recipe: r, Reason: reason})
+			continue
+		}
+
+		q.Recommended = append(q.Recommended, r)
+	}
+	q.Recommended = requiredFirst(byRelevance(m, q.Recommended))
+
+	return &q, nil
+}
@@ -0,0 +1,142 @@
+// +build unit
+
+package install
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestQueryInstallableRecipes(t *testing.T) {
+	m := &types.DiscoveryManifest{OS: "linux", Platform: "ubuntu"}
+	f := recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{Name: types.InfraAgentRecipeName},
+		{Name: types.LoggingRecipeName},
+	}
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{
+		{Name: types.InfraAgentRecipeName},
+		{Name: "mysql"},
+		{Name: "required", Requirement: types.OpenInstallationRequirementTypes.REQUIRED},
+		{
+			Name: "java-agent",
+			InstallTargets: []types.OpenInstallationRecipeInstallTarget{
+				{Type: types.OpenInstallationTargetTypeTypes.APPLICATION, Os: "LINUX"},
+			},
+		},
+		{
+			Name: "iis",
+			InstallTargets: []types.OpenInstallationRecipeInstallTarget{
+				{Type: types.OpenInstallationTargetTypeTypes.HOST, Os: "WINDOWS"},
+			},
+		},
+	}
+
+	q, err := QueryInstallableRecipes(context.Background(), m, f, RecipeQuery{})
+	require.NoError(t, err)
+	require.Equal(t, types.InfraAgentRecipeName, q.InfraAgent.Name)
+	require.NotNil(t, q.Logging)
+	require.Equal(t, types.LoggingRecipeName, q.Logging.Name)
+	require.Equal(t, []string{"required", "mysql"}, queriedRecipeNames(q.Recommended))
+
+	unsupported := map[string]string{}
+	for _, u := range q.Unsupported {
+		unsupported[u.Recipe.Name] = u.Reason
+	}
+	require.Len(t, unsupported, 2)
+	require.Contains(t, unsupported["java-agent"], "application")
+	require.Contains(t, unsupported["iis"], "install target")
+}
+
+func TestQueryInstallableRecipes_ConfiguredNames(t *testing.T) {
+	f := &namedRecipeFetcher{
+		MockRecipeFetcher: recipes.NewMockRecipeFetcher(),
+		recipes: map[string]types.OpenInstallationRecipe{
+			"custom-infra":   {Name: "custom-infra"},
+			"custom-logging": {Name: "custom-logging"},
+		},
+	}
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{
+		{Name: "custom-infra"},
+		{Name: "custom-logging"},
+		{Name: types.LoggingRecipeName},
+	}
+
+	q, err := QueryInstallableRecipes(context.Background(), &types.DiscoveryManifest{}, f, RecipeQuery{
+		InfraAgentRecipeName: "custom-infra",
+		LoggingRecipeName:    "custom-logging",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "custom-infra", q.InfraAgent.Name)
+	require.Equal(t, "custom-logging", q.Logging.Name)
+	require.Equal(t, []string{types.LoggingRecipeName}, queriedRecipeNames(q.Recommended))
+}
+
+func TestQueryInstallableRecipes_NoLogging(t *testing.T) {
+	f := &namedRecipeFetcher{
+		MockRecipeFetcher: recipes.NewMockRecipeFetcher(),
+		recipes: map[string]types.OpenInstallationRecipe{
+			types.InfraAgentRecipeName: {Name: types.InfraAgentRecipeName},
+		},
+	}
+
+	q, err := QueryInstallableRecipes(context.Background(), &types.DiscoveryManifest{}, f, RecipeQuery{})
+	require.NoError(t, err)
+	require.Equal(t, types.InfraAgentRecipeName, q.InfraAgent.Name)
+	require.Nil(t, q.Logging)
+	require.Empty(t, q.Recommended)
+}
+
+func TestQueryInstallableRecipes_SkipRecommendations(t *testing.T) {
+	f := recipes.NewMockRecipeFetcher()
+	f.FetchRecipeVal = &types.OpenInstallationRecipe{Name: types.InfraAgentRecipeName}
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{{Name: "mysql"}}
+
+	q, err := QueryInstallableRecipes(context.Background(), &types.DiscoveryManifest{}, f, RecipeQuery{SkipRecommendations: true})
+	require.NoError(t, err)
+	require.Empty(t, q.Recommended)
+	require.Equal(t, 0, f.FetchRecommendationsCallCount)
+}
+
+func TestQueryInstallableRecipes_UnsupportedPlatform(t *testing.T) {
+	f := recipes.NewMockRecipeFetcher()
+	f.FetchRecipeErr = recipes.ErrRecipeNotFound
+
+	_, err := QueryInstallableRecipes(context.Background(), &types.DiscoveryManifest{OS: "linux", KernelArch: "s390x"}, f, RecipeQuery{})
+
+	var perr ErrUnsupportedPlatform
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, 0, f.FetchRecommendationsCallCount)
+}
+
+// namedRecipeFetcher fetches the recipes it has by name, and no others.
+type namedRecipeFetcher struct {
+	*recipes.MockRecipeFetcher
+	recipes map[string]types.OpenInstallationRecipe
+}
+
+func (f *namedRecipeFetcher) FetchRecipe(ctx context.Context, m *types.DiscoveryManifest, name string) (*types.OpenInstallationRecipe, error) {
+	f.FetchRecipeNameCount[name]++
+
+	r, ok := f.recipes[name]
+	if !ok {
+		return nil, recipes.ErrRecipeNotFound
+	}
+
+	return &r, nil
+}
+
+func queriedRecipeNames(recipes []types.OpenInstallationRecipe) []string {
+	names := []string{}
+	for _, r := range recipes {
+		names = append(names, r.Name)
+	}
+
+	return names
+}
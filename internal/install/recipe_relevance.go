@@ -0,0 +1,113 @@
+package install
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"sort"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+const (
+	// relevanceOptionFormat shows the relevance of a recipe offered for
+	// selection, as a percentage.
+	relevanceOptionFormat = " [relevance %.0f%%]"
+
+	// halfRelevanceLiterals is the number of literal characters in a matching
+	// process pattern at which a recipe is considered half relevant.
+	halfRelevanceLiterals = 8
+)
+
+// byRelevance returns the recipes ordered from the most to the least relevant
+// to the host, with their relevance set.  Recipes of equal relevance keep their
+// order.
+func byRelevance(m *types.DiscoveryManifest, recipes []types.OpenInstallationRecipe) []types.OpenInstallationRecipe {
+	sorted := make([]types.OpenInstallationRecipe, len(recipes))
+	for n, r := range recipes {
+		r.RelevanceScore = recipeRelevance(m, r)
+		sorted[n] = r
+	}
+
+	sort.SliceStable(sorted, func(a, b int) bool {
+		return sorted[a].RelevanceScore > sorted[b].RelevanceScore
+	})
+
+	return sorted
+}
+
+// recipeRelevance returns the relevance of the recipe to the host, from 0 to
+// 1.  The score given by the recipe source is used when there is one, and is
+// otherwise estimated from the processes the recipe matched.
+func recipeRelevance(m *types.DiscoveryManifest, r types.OpenInstallationRecipe) float64 {
+	if r.RelevanceScore > 0 {
+		return r.RelevanceScore
+	}
+
+	return processMatchRelevance(m, r)
+}
+
+// processMatchRelevance estimates the relevance of the recipe from the most
+// specific of its process patterns matching a process running on the host.  A
+// pattern matching a specific command, such as redis-server, is a stronger
+// signal than one matching a common word, such as java.  Recipes matching no
+// process have no relevance.
+func processMatchRelevance(m *types.DiscoveryManifest, r types.OpenInstallationRecipe) float64 {
+	if m == nil {
+		return 0
+	}
+
+	best := 0
+	for _, pattern := range r.ProcessMatch {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			continue
+		}
+
+		for _, p := range m.Processes {
+			if re.MatchString(p.Command) {
+				if l := patternLiterals(pattern); l > best {
+					best = l
+				}
+				break
+			}
+		}
+	}
+
+	return float64(best) / float64(best+halfRelevanceLiterals)
+}
+
+// patternLiterals returns the number of literal characters any match of the
+// regular expression contains.
+func patternLiterals(pattern string) int {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return 0
+	}
+
+	return countLiterals(re)
+}
+
+func countLiterals(re *syntax.Regexp) int {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return len(re.Rune)
+	case syntax.OpStar, syntax.OpQuest:
+		// Optional parts need not be matched.
+		return 0
+	case syntax.OpAlternate:
+		fewest := -1
+		for _, sub := range re.Sub {
+			if c := countLiterals(sub); fewest < 0 || c < fewest {
+				fewest = c
+			}
+		}
+		return fewest
+	}
+
+	count := 0
+	for _, sub := range re.Sub {
+		count += countLiterals(sub)
+	}
+
+	return count
+}
@@ -0,0 +1,101 @@
+//go:build unit
+// +build unit
+
+package install
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/ux"
+)
+
+func TestByRelevance_ServiceScores(t *testing.T) {
+	sorted := byRelevance(nil, []types.OpenInstallationRecipe{
+		{Name: "a", RelevanceScore: 0.2},
+		{Name: "b"},
+		{Name: "c", RelevanceScore: 0.9},
+		{Name: "d"},
+	})
+
+	names := []string{}
+	for _, r := range sorted {
+		names = append(names, r.Name)
+	}
+	require.Equal(t, []string{"c", "a", "b", "d"}, names)
+}
+
+func TestByRelevance_ProcessMatches(t *testing.T) {
+	m := &types.DiscoveryManifest{
+		Processes: []types.MatchedProcess{
+			{Command: "java -jar app.jar"},
+			{Command: "/usr/bin/redis-server 127.0.0.1:6379"},
+		},
+	}
+
+	sorted := byRelevance(m, []types.OpenInstallationRecipe{
+		{Name: "java", ProcessMatch: []string{"java"}},
+		{Name: "nginx", ProcessMatch: []string{"nginx"}},
+		{Name: "redis", ProcessMatch: []string{"redis-server"}},
+	})
+
+	require.Equal(t, "redis", sorted[0].Name)
+	require.Equal(t, "java", sorted[1].Name)
+	require.Equal(t, "nginx", sorted[2].Name)
+	require.InDelta(t, 0.6, sorted[0].RelevanceScore, 0.001)
+	require.InDelta(t, 1.0/3, sorted[1].RelevanceScore, 0.001)
+	require.Zero(t, sorted[2].RelevanceScore)
+}
+
+func TestPatternLiterals(t *testing.T) {
+	require.Equal(t, 12, patternLiterals("redis-server"))
+	require.Equal(t, 4, patternLiterals("java"))
+	require.Equal(t, 5, patternLiterals("mysqld?"))
+	require.Equal(t, 6, patternLiterals("(mysql|mariadb)d"))
+	require.Equal(t, 0, patternLiterals("(unclosed"))
+}
+
+func TestIntegrationOptionName_Relevance(t *testing.T) {
+	r := types.OpenInstallationRecipe{DisplayName: "Redis", RelevanceScore: 0.6}
+	require.Equal(t, "Redis [relevance 60%]", integrationOptionName(r))
+
+	r.Requirement = types.OpenInstallationRequirementTypes.REQUIRED
+	require.Equal(t, "Redis (required) [relevance 60%]", integrationOptionName(r))
+}
+
+func TestInstall_RecommendationsOfferedByRelevance(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{
+		{Name: "nginx", DisplayName: "NGINX", ValidationNRQL: "testNrql", RelevanceScore: 0.25},
+		{Name: "redis", DisplayName: "Redis", ValidationNRQL: "testNrql", RelevanceScore: 0.6},
+	}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{{
+		Name:           types.InfraAgentRecipeName,
+		ValidationNRQL: "testNrql",
+	}}
+	mp := &ux.MockPrompter{
+		PromptMultiSelectVal: []string{"Redis [relevance 60%]"},
+	}
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, mp, pi, lkf, ss, rd, cr}
+	require.NoError(t, i.Install())
+	require.Equal(t, []string{"Redis [relevance 60%]", "NGINX [relevance 25%]"}, mp.PromptMultiSelectOptions)
+
+	installed := []string{}
+	for _, rs := range status.Statuses {
+		if rs.Status == execution.RecipeStatusTypes.INSTALLED {
+			installed = append(installed, rs.Name)
+		}
+	}
+	require.Contains(t, installed, "redis")
+	require.NotContains(t, installed, "nginx")
+}
@@ -0,0 +1,69 @@
+package install
+
+import (
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+// requiredOptionSuffix marks required recipes among the integrations offered
+// for selection.
+const requiredOptionSuffix = " (required)"
+
+// integrationOptionName returns the name under which the recipe is offered for
+// selection, along with its relevance when known.
+func integrationOptionName(r types.OpenInstallationRecipe) string {
+	name := r.DisplayName
+	if r.IsRequired() {
+		name += requiredOptionSuffix
+	}
+
+	if r.RelevanceScore > 0 {
+		name += fmt.Sprintf(relevanceOptionFormat, r.RelevanceScore*100)
+	}
+
+	return name
+}
+
+// requiredFirst returns the recipes with the required ones first, otherwise
+// keeping their order.
+func requiredFirst(recipes []types.OpenInstallationRecipe) []types.OpenInstallationRecipe {
+	sorted := append([]types.OpenInstallationRecipe{}, recipes...)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		return sorted[a].IsRequired() && !sorted[b].IsRequired()
+	})
+
+	return sorted
+}
+
+// integrationOptionDefaults returns the options to pre-select given the
+// display names of the candidates to pre-select.  Required candidates are
+// always pre-selected.  A nil result pre-selects every candidate.
+func integrationOptionDefaults(candidates []types.OpenInstallationRecipe, defaults []string) []string {
+	if defaults == nil {
+		return nil
+	}
+
+	options := []string{}
+	for _, r := range candidates {
+		if r.IsRequired() || utils.StringInSlice(r.DisplayName, defaults) {
+			options = append(options, integrationOptionName(r))
+		}
+	}
+
+	return options
+}
+
+// warnDeselectedRequired warns about each required candidate that was not
+// selected.
+func warnDeselectedRequired(candidates []types.OpenInstallationRecipe, selected []string) {
+	for _, r := range candidates {
+		if r.IsRequired() && !utils.StringInSlice(integrationOptionName(r), selected) {
+			log.Warnf("%s is required for complete instrumentation of this host, but was not selected.", r.DisplayName)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// +build unit
+
+package install
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/ux"
+)
+
+var (
+	testOptionalRecipe = types.OpenInstallationRecipe{Name: "redis", DisplayName: "Redis", ValidationNRQL: "testNrql"}
+	testRequiredRecipe = types.OpenInstallationRecipe{
+		Name:           "mysql",
+		DisplayName:    "MySQL",
+		ValidationNRQL: "testNrql",
+		Requirement:    types.OpenInstallationRequirementTypes.REQUIRED,
+	}
+)
+
+func TestInstall_RequiredRecipesOfferedFirstAndMarked(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{testOptionalRecipe, testRequiredRecipe}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{{
+		Name:           types.InfraAgentRecipeName,
+		ValidationNRQL: "testNrql",
+	}}
+	mp := &ux.MockPrompter{
+		PromptMultiSelectVal: []string{"MySQL (required)"},
+	}
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, mp, pi, lkf, ss, rd, cr}
+	require.NoError(t, i.Install())
+	require.Equal(t, []string{"MySQL (required)", "Redis"}, mp.PromptMultiSelectOptions)
+
+	reporter := statusReporters[0].(*execution.MockStatusReporter)
+	require.Equal(t, 1, reporter.ReportInstalled["mysql"])
+	require.Equal(t, 1, reporter.ReportSkipped["redis"])
+}
+
+func TestInstall_DeselectedRequiredRecipeIsSkipped(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{testOptionalRecipe, testRequiredRecipe}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{{
+		Name:           types.InfraAgentRecipeName,
+		ValidationNRQL: "testNrql",
+	}}
+	mp := &ux.MockPrompter{
+		PromptMultiSelectVal: []string{"Redis"},
+	}
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, mp, pi, lkf, ss, rd, cr}
+	require.NoError(t, i.Install())
+
+	reporter := statusReporters[0].(*execution.MockStatusReporter)
+	require.Equal(t, 1, reporter.ReportInstalled["redis"])
+	require.Equal(t, 1, reporter.ReportSkipped["mysql"])
+}
+
+func TestIntegrationOptionDefaults(t *testing.T) {
+	candidates := []types.OpenInstallationRecipe{testRequiredRecipe, testOptionalRecipe}
+
+	require.Nil(t, integrationOptionDefaults(candidates, nil))
+	require.Equal(t, []string{"MySQL (required)"}, integrationOptionDefaults(candidates, []string{}))
+	require.Equal(t, []string{"MySQL (required)", "Redis"}, integrationOptionDefaults(candidates, []string{"Redis"}))
+}
+
+func TestRequiredFirst(t *testing.T) {
+	other := types.OpenInstallationRecipe{Name: "other"}
+	sorted := requiredFirst([]types.OpenInstallationRecipe{testOptionalRecipe, testRequiredRecipe, other})
+
+	require.Equal(t, []string{"mysql", "redis", "other"}, []string{sorted[0].Name, sorted[1].Name, sorted[2].Name})
+}
@@ -0,0 +1,34 @@
+package install
+
+import (
+	"fmt"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+)
+
+// newRetryingRecipeExecutor wraps the executor so recipes failing with one of
+// the configured exit codes are retried, reporting retries to the status.
+func newRetryingRecipeExecutor(ic InstallerContext, re execution.RecipeExecutor, status *execution.InstallStatus) *execution.RetryingRecipeExecutor {
+	rre := execution.NewRetryingRecipeExecutor(re)
+	rre.ExitCodes = ic.RetryExitCodes
+	rre.Retries = ic.RecipeRetries
+	rre.Retrying = status.RecipeRetrying
+
+	return rre
+}
+
+// assertRetryConfigIsValid ensures the retry count is not negative and that
+// the retryable exit codes are ones a process can exit with.
+func assertRetryConfigIsValid(ic InstallerContext) error {
+	if ic.RecipeRetries < 0 {
+		return fmt.Errorf("--recipe-retries must not be negative, got %d", ic.RecipeRetries)
+	}
+
+	for _, c := range ic.RetryExitCodes {
+		if c < 1 || c > 255 {
+			return fmt.Errorf("invalid --retry-exit-codes %d: exit codes range from 1 to 255", c)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package install
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/client"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-client-go/newrelic"
+)
+
+// assertRecipeSourceURLIsValid ensures a custom recipe service URL, when
+// given, is an absolute HTTP(S) URL.
+func assertRecipeSourceURLIsValid(ic InstallerContext) error {
+	if ic.RecipeSourceURL == "" {
+		return nil
+	}
+
+	u, err := url.Parse(ic.RecipeSourceURL)
+	if err != nil {
+		return fmt.Errorf("invalid --recipe-source-url %s: %s", ic.RecipeSourceURL, err)
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid --recipe-source-url %s: an absolute http or https URL is required", ic.RecipeSourceURL)
+	}
+
+	return nil
+}
+
+// newRecipeServiceClient returns the NerdGraph client recipes are fetched
+// with, which sends its requests to the custom recipe service URL when given.
+// The client is authenticated as the default profile either way.
+func newRecipeServiceClient(ic InstallerContext, nrClient *newrelic.NewRelic) (recipes.NerdGraphClient, error) {
+	if ic.RecipeSourceURL == "" {
+		return &nrClient.NerdGraph, nil
+	}
+
+	opts, err := caBundleClientOptions(ic.CABundle)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.NewNerdGraphClient(ic.RecipeSourceURL, opts...)
+}
+
+// checkRecipeSource confirms a custom recipe service can be reached, so an
+// unreachable mirror is reported before anything is installed.
+func checkRecipeSource(ctx context.Context, ic InstallerContext, nrClient *newrelic.NewRelic) error {
+	if ic.RecipeSourceURL == "" || ic.LocalRecipes != "" {
+		return nil
+	}
+
+	c, err := newRecipeServiceClient(ic, nrClient)
+	if err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"url": ic.RecipeSourceURL,
+	}).Debug("checking recipe service")
+
+	if err := recipes.PingRecipeService(ctx, c); err != nil {
+		return fmt.Errorf("the recipe service at %s could not be reached: %s", ic.RecipeSourceURL, err)
+	}
+
+	return nil
+}
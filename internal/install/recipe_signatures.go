@@ -0,0 +1,36 @@
+package install
+
+import (
+	"errors"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+)
+
+// newRecipeVerifier returns the verifier of recipe signatures for the given
+// context, or nil when signatures are not verified.  Should the keys fail to
+// load, no recipe verifies, so that signed recipes remain required.
+func newRecipeVerifier(ic InstallerContext) *recipes.RecipeVerifier {
+	if len(ic.RecipePublicKeys) == 0 && !ic.RequireSignedRecipes {
+		return nil
+	}
+
+	keys, err := recipes.LoadPublicKeys(ic.RecipePublicKeys)
+	if err != nil {
+		log.Warnf("Recipe signatures cannot be verified: %s", err)
+	}
+
+	return recipes.NewRecipeVerifier(keys, ic.RequireSignedRecipes)
+}
+
+// assertSignatureConfigIsValid ensures the keys recipes are verified against
+// can be loaded, and that there are some when signed recipes are required.
+func assertSignatureConfigIsValid(ic InstallerContext) error {
+	if ic.RequireSignedRecipes && len(ic.RecipePublicKeys) == 0 {
+		return errors.New("--require-signed requires at least one --recipe-public-key to verify recipes against")
+	}
+
+	_, err := recipes.LoadPublicKeys(ic.RecipePublicKeys)
+	return err
+}
@@ -0,0 +1,62 @@
+package install
+
+import (
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/config"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-client-go/newrelic"
+)
+
+const (
+	recipeSourceService = "service"
+	recipeSourceCache   = "cache"
+	recipeCacheDirName  = "recipe-cache"
+)
+
+// newRecipeFetcher returns the recipe fetcher for the given context.  Local
+// recipes take precedence over any configured sources, and the recipe service
+// is used when neither is given.
+func newRecipeFetcher(ic InstallerContext, nrClient *newrelic.NewRelic) recipes.RecipeFetcher {
+	if ic.LocalRecipes != "" {
+		return &recipes.LocalRecipeFetcher{
+			Path: ic.LocalRecipes,
+		}
+	}
+
+	c, err := newRecipeServiceClient(ic, nrClient)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if len(ic.RecipeSources) == 0 {
+		return recipes.NewServiceRecipeFetcher(c)
+	}
+
+	return newFallbackRecipeFetcher(ic.RecipeSources, recipes.NewServiceRecipeFetcher(c), filepath.Join(config.DefaultConfigDirectory, recipeCacheDirName))
+}
+
+// newFallbackRecipeFetcher builds a fetcher trying the named sources in order.
+// A source is "service", "cache", or the path to a local recipe directory.
+func newFallbackRecipeFetcher(names []string, service recipes.RecipeFetcher, cacheDir string) *recipes.FallbackRecipeFetcher {
+	cache := recipes.NewCacheRecipeFetcher(cacheDir)
+
+	sources := []recipes.RecipeSource{}
+	for _, name := range names {
+		switch name {
+		case recipeSourceService:
+			sources = append(sources, recipes.RecipeSource{Name: name, Fetcher: service})
+		case recipeSourceCache:
+			sources = append(sources, recipes.RecipeSource{Name: name, Fetcher: cache})
+		default:
+			sources = append(sources, recipes.RecipeSource{Name: name, Fetcher: &recipes.LocalRecipeFetcher{Path: name}})
+		}
+	}
+
+	f := recipes.NewFallbackRecipeFetcher(sources...)
+	f.Cache = cache
+
+	return f
+}
@@ -0,0 +1,116 @@
+package recipes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+const cachedRecipeExt = ".json"
+
+// CacheRecipeFetcher is an implementation of the RecipeFetcher interface that
+// serves recipes previously stored from another source, one JSON file per
+// recipe.
+type CacheRecipeFetcher struct {
+	Path string
+}
+
+// NewCacheRecipeFetcher returns a new instance of CacheRecipeFetcher.
+func NewCacheRecipeFetcher(path string) *CacheRecipeFetcher {
+	f := CacheRecipeFetcher{
+		Path: path,
+	}
+
+	return &f
+}
+
+func (f *CacheRecipeFetcher) FetchRecipe(ctx context.Context, manifest *types.DiscoveryManifest, friendlyName string) (*types.OpenInstallationRecipe, error) {
+	recipes, err := f.FetchRecommendations(ctx, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, recipe := range recipes {
+		if recipe.Name == friendlyName {
+			return &recipe, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s: %w", friendlyName, ErrRecipeNotFound)
+}
+
+func (f *CacheRecipeFetcher) FetchRecommendations(ctx context.Context, manifest *types.DiscoveryManifest) ([]types.OpenInstallationRecipe, error) {
+	recipes, err := f.FetchRecipes(ctx, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest.ConstrainRecipes(recipes), nil
+}
+
+func (f *CacheRecipeFetcher) FetchRecipes(ctx context.Context, manifest *types.DiscoveryManifest) ([]types.OpenInstallationRecipe, error) {
+	files, err := ioutil.ReadDir(f.Path)
+	if err != nil {
+		return nil, NewErrSourceUnavailable(fmt.Errorf("unable to read recipe cache: %s", err))
+	}
+
+	recipes := []types.OpenInstallationRecipe{}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != cachedRecipeExt {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(f.Path, file.Name()))
+		if err != nil {
+			log.Debugf("skipping cached recipe %s: %s", file.Name(), err)
+			continue
+		}
+
+		var r types.OpenInstallationRecipe
+		if err := json.Unmarshal(data, &r); err != nil {
+			log.Debugf("skipping cached recipe %s: %s", file.Name(), err)
+			continue
+		}
+
+		recipes = append(recipes, r)
+	}
+
+	return recipes, nil
+}
+
+// Store caches the given recipes, replacing any cached versions.
+func (f *CacheRecipeFetcher) Store(recipes ...types.OpenInstallationRecipe) error {
+	if err := os.MkdirAll(f.Path, 0700); err != nil {
+		return err
+	}
+
+	for _, r := range recipes {
+		if r.Name == "" {
+			continue
+		}
+
+		data, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+
+		if err := utils.WriteFileAtomic(filepath.Join(f.Path, cachedRecipeFileName(r.Name)), data, 0600); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func cachedRecipeFileName(name string) string {
+	return strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(name) + cachedRecipeExt
+}
@@ -0,0 +1,67 @@
+// +build unit
+
+package recipes
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestCacheRecipeFetcher_StoreAndFetch(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "newrelic")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	f := NewCacheRecipeFetcher(filepath.Join(tmp, "cache"))
+	err = f.Store(
+		cachedTestRecipe("infrastructure-agent-installer", "linux"),
+		cachedTestRecipe("windows-recipe", "windows"),
+	)
+	require.NoError(t, err)
+
+	m := &types.DiscoveryManifest{OS: "linux"}
+
+	recipes, err := f.FetchRecipes(context.Background(), m)
+	require.NoError(t, err)
+	require.Len(t, recipes, 2)
+
+	recipes, err = f.FetchRecommendations(context.Background(), m)
+	require.NoError(t, err)
+	require.Len(t, recipes, 1)
+	require.Equal(t, "infrastructure-agent-installer", recipes[0].Name)
+	require.Equal(t, "echo installing", recipes[0].Install)
+
+	r, err := f.FetchRecipe(context.Background(), m, "infrastructure-agent-installer")
+	require.NoError(t, err)
+	require.Equal(t, "infrastructure-agent-installer", r.Name)
+
+	_, err = f.FetchRecipe(context.Background(), m, "windows-recipe")
+	require.ErrorIs(t, err, ErrRecipeNotFound)
+}
+
+func TestCacheRecipeFetcher_MissingCacheIsUnavailable(t *testing.T) {
+	f := NewCacheRecipeFetcher(filepath.Join(os.TempDir(), "newrelic-missing-recipe-cache"))
+
+	_, err := f.FetchRecipes(context.Background(), &types.DiscoveryManifest{})
+	require.Error(t, err)
+
+	var uerr ErrSourceUnavailable
+	require.ErrorAs(t, err, &uerr)
+}
+
+func cachedTestRecipe(name string, os string) types.OpenInstallationRecipe {
+	return types.OpenInstallationRecipe{
+		Name:    name,
+		Install: "echo installing",
+		InstallTargets: []types.OpenInstallationRecipeInstallTarget{
+			{Os: types.OpenInstallationOperatingSystem(os)},
+		},
+	}
+}
@@ -1,6 +1,134 @@
 package recipes
 
-import "github.com/pkg/errors"
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
+)
 
 // ErrRecipeNotFound is used when a recipe is requested by name, but does not exist for the given constraint.
 var ErrRecipeNotFound = errors.New("recipe not found")
+
+// ErrRecipeNotFoundMessage is a not found error with a message tailored to the
+// requested recipe.  It matches ErrRecipeNotFound.
+type ErrRecipeNotFoundMessage struct {
+	msg string
+}
+
+func NewErrRecipeNotFoundMessage(msg string) ErrRecipeNotFoundMessage {
+	return ErrRecipeNotFoundMessage{
+		msg: msg,
+	}
+}
+
+func (e ErrRecipeNotFoundMessage) Error() string {
+	return e.msg
+}
+
+func (e ErrRecipeNotFoundMessage) Unwrap() error {
+	return ErrRecipeNotFound
+}
+
+// ErrPaginationUnsupported is used when the recipe service rejects the paged
+// recommendations query, as services whose schema has no cursors do.  It is
+// not retried.
+var ErrPaginationUnsupported = errors.New("the recipe service does not paginate recommendations")
+
+// ErrSourceUnavailable is used when a recipe source cannot be reached or read,
+// as opposed to the source not having the requested recipe.
+type ErrSourceUnavailable struct {
+	innerErr error
+}
+
+func NewErrSourceUnavailable(err error) ErrSourceUnavailable {
+	return ErrSourceUnavailable{
+		innerErr: err,
+	}
+}
+
+func (e ErrSourceUnavailable) Error() string {
+	return e.innerErr.Error()
+}
+
+func (e ErrSourceUnavailable) Unwrap() error {
+	return e.innerErr
+}
+
+// ErrRecipeSignature is used when a recipe is rejected because it is not
+// signed by a trusted key.
+type ErrRecipeSignature struct {
+	Name   string
+	Status SignatureStatus
+}
+
+func NewErrRecipeSignature(name string, status SignatureStatus) ErrRecipeSignature {
+	return ErrRecipeSignature{
+		Name:   name,
+		Status: status,
+	}
+}
+
+func (e ErrRecipeSignature) Error() string {
+	if e.Status == SignatureStatuses.UNSIGNED {
+		return fmt.Sprintf("recipe %s is not signed and signed recipes are required", e.Name)
+	}
+
+	return fmt.Sprintf("the signature of recipe %s could not be verified against the trusted keys", e.Name)
+}
+
+// ErrPartialRecommendations is used when fetching recommendations failed part
+// way through, after retries.  The recommendations gathered until then are
+// returned along with it, and the fetch can be resumed from Cursor.
+type ErrPartialRecommendations struct {
+	Cursor    string
+	Retrieved int
+	// Expected is the total number of recommendations, or zero when unknown.
+	Expected int
+	innerErr error
+}
+
+func NewErrPartialRecommendations(cursor string, retrieved int, expected int, err error) ErrPartialRecommendations {
+	return ErrPartialRecommendations{
+		Cursor:    cursor,
+		Retrieved: retrieved,
+		Expected:  expected,
+		innerErr:  err,
+	}
+}
+
+func (e ErrPartialRecommendations) Error() string {
+	if e.Expected > 0 {
+		return fmt.Sprintf("retrieved %d of %d recommendations: %s", e.Retrieved, e.Expected, e.innerErr)
+	}
+
+	return fmt.Sprintf("retrieved %d recommendations: %s", e.Retrieved, e.innerErr)
+}
+
+func (e ErrPartialRecommendations) Unwrap() error {
+	return e.innerErr
+}
+
+// HTTPStatusCode returns the HTTP status code of a response the New Relic
+// client rejected, or zero when err is not such a response.  The client only
+// exposes the code of an unexpected status code as the start of its error.
+func HTTPStatusCode(err error) int {
+	var uerr *nrErrors.UnauthorizedError
+	if errors.As(err, &uerr) {
+		return http.StatusUnauthorized
+	}
+
+	var serr *nrErrors.UnexpectedStatusCode
+	if !errors.As(err, &serr) {
+		return 0
+	}
+
+	var code int
+	if _, scanErr := fmt.Sscanf(serr.Error(), "%d response returned", &code); scanErr != nil {
+		return 0
+	}
+
+	return code
+}
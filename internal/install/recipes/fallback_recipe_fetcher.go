@@ -0,0 +1,123 @@
+package recipes
+
+import (
+	"context"
+	"errors"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// RecipeSource is a named source of recipes.
+type RecipeSource struct {
+	Name    string
+	Fetcher RecipeFetcher
+}
+
+// FallbackRecipeFetcher is an implementation of the RecipeFetcher interface
+// that tries an ordered list of sources, returning the first result for each
+// operation.  It only falls back to the next source when a source is
+// unavailable; a recipe that is not found is not looked for elsewhere.
+type FallbackRecipeFetcher struct {
+	// Cache, when set, stores the recipes served by the other sources so they
+	// remain available when those sources are not.
+	Cache   *CacheRecipeFetcher
+	sources []RecipeSource
+}
+
+// NewFallbackRecipeFetcher returns a new instance of FallbackRecipeFetcher
+// trying the given sources in order.
+func NewFallbackRecipeFetcher(sources ...RecipeSource) *FallbackRecipeFetcher {
+	f := FallbackRecipeFetcher{
+		sources: sources,
+	}
+
+	return &f
+}
+
+func (f *FallbackRecipeFetcher) FetchRecipe(ctx context.Context, manifest *types.DiscoveryManifest, friendlyName string) (*types.OpenInstallationRecipe, error) {
+	var recipe *types.OpenInstallationRecipe
+
+	err := f.try(friendlyName, func(s RecipeSource) error {
+		r, err := s.Fetcher.FetchRecipe(ctx, manifest, friendlyName)
+		if err == nil && r != nil {
+			f.store(s, *r)
+		}
+		recipe = r
+		return err
+	})
+
+	return recipe, err
+}
+
+func (f *FallbackRecipeFetcher) FetchRecommendations(ctx context.Context, manifest *types.DiscoveryManifest) ([]types.OpenInstallationRecipe, error) {
+	var recipes []types.OpenInstallationRecipe
+
+	err := f.try("recommendations", func(s RecipeSource) error {
+		r, err := s.Fetcher.FetchRecommendations(ctx, manifest)
+		if err == nil {
+			f.store(s, r...)
+		}
+		recipes = r
+		return err
+	})
+
+	return recipes, err
+}
+
+func (f *FallbackRecipeFetcher) FetchRecipes(ctx context.Context, manifest *types.DiscoveryManifest) ([]types.OpenInstallationRecipe, error) {
+	var recipes []types.OpenInstallationRecipe
+
+	err := f.try("recipes", func(s RecipeSource) error {
+		r, err := s.Fetcher.FetchRecipes(ctx, manifest)
+		if err == nil {
+			f.store(s, r...)
+		}
+		recipes = r
+		return err
+	})
+
+	return recipes, err
+}
+
+// try runs fetch against each source in turn until one is available.  When
+// none are, the error of the first source is returned.
+func (f *FallbackRecipeFetcher) try(what string, fetch func(RecipeSource) error) error {
+	var firstErr error
+
+	for n, s := range f.sources {
+		err := fetch(s)
+
+		var uerr ErrSourceUnavailable
+		if !errors.As(err, &uerr) {
+			if err == nil {
+				log.WithFields(log.Fields{
+					"source": s.Name,
+				}).Debugf("fetched %s", what)
+			}
+
+			return err
+		}
+
+		if firstErr == nil {
+			firstErr = err
+		}
+
+		if n < len(f.sources)-1 {
+			log.Warnf("Recipe source %s is unavailable, trying %s: %s", s.Name, f.sources[n+1].Name, err)
+		}
+	}
+
+	return firstErr
+}
+
+func (f *FallbackRecipeFetcher) store(s RecipeSource, recipes ...types.OpenInstallationRecipe) {
+	if f.Cache == nil || s.Fetcher == RecipeFetcher(f.Cache) {
+		return
+	}
+
+	if err := f.Cache.Store(recipes...); err != nil {
+		log.Debugf("could not cache recipes: %s", err)
+	}
+}
@@ -0,0 +1,92 @@
+// +build unit
+
+package recipes
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestFallbackRecipeFetcher_UsesFirstAvailableSource(t *testing.T) {
+	primary := NewMockRecipeFetcher()
+	primary.FetchRecommendationsVal = []types.OpenInstallationRecipe{{Name: "primary"}}
+	secondary := NewMockRecipeFetcher()
+
+	f := NewFallbackRecipeFetcher(RecipeSource{"primary", primary}, RecipeSource{"secondary", secondary})
+
+	recipes, err := f.FetchRecommendations(context.Background(), &types.DiscoveryManifest{})
+	require.NoError(t, err)
+	require.Equal(t, "primary", recipes[0].Name)
+	require.Equal(t, 0, secondary.FetchRecommendationsCallCount)
+}
+
+func TestFallbackRecipeFetcher_FallsBackWhenUnavailable(t *testing.T) {
+	primary := NewMockRecipeFetcher()
+	primary.FetchRecipeErr = NewErrSourceUnavailable(errors.New("connection refused"))
+	secondary := NewMockRecipeFetcher()
+	secondary.FetchRecipeVal = &types.OpenInstallationRecipe{Name: "secondary"}
+
+	f := NewFallbackRecipeFetcher(RecipeSource{"primary", primary}, RecipeSource{"secondary", secondary})
+
+	r, err := f.FetchRecipe(context.Background(), &types.DiscoveryManifest{}, "secondary")
+	require.NoError(t, err)
+	require.Equal(t, "secondary", r.Name)
+	require.Equal(t, 1, primary.FetchRecipeCallCount)
+	require.Equal(t, 1, secondary.FetchRecipeCallCount)
+}
+
+func TestFallbackRecipeFetcher_DoesNotFallBackWhenNotFound(t *testing.T) {
+	primary := NewMockRecipeFetcher()
+	primary.FetchRecipeErr = NewErrRecipeNotFoundMessage("no recipe found")
+	secondary := NewMockRecipeFetcher()
+	secondary.FetchRecipeVal = &types.OpenInstallationRecipe{Name: "secondary"}
+
+	f := NewFallbackRecipeFetcher(RecipeSource{"primary", primary}, RecipeSource{"secondary", secondary})
+
+	_, err := f.FetchRecipe(context.Background(), &types.DiscoveryManifest{}, "secondary")
+	require.ErrorIs(t, err, ErrRecipeNotFound)
+	require.Equal(t, 0, secondary.FetchRecipeCallCount)
+}
+
+func TestFallbackRecipeFetcher_AllUnavailableReturnsPrimaryError(t *testing.T) {
+	primaryErr := NewErrSourceUnavailable(errors.New("primary down"))
+	primary := NewMockRecipeFetcher()
+	primary.FetchRecipesErr = primaryErr
+	secondary := NewMockRecipeFetcher()
+	secondary.FetchRecipesErr = NewErrSourceUnavailable(errors.New("secondary down"))
+
+	f := NewFallbackRecipeFetcher(RecipeSource{"primary", primary}, RecipeSource{"secondary", secondary})
+
+	_, err := f.FetchRecipes(context.Background(), &types.DiscoveryManifest{})
+	require.Equal(t, primaryErr, err)
+}
+
+func TestFallbackRecipeFetcher_CachesAndServesFromCache(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "newrelic")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	cache := NewCacheRecipeFetcher(tmp)
+	service := NewMockRecipeFetcher()
+	service.FetchRecipesVal = []types.OpenInstallationRecipe{cachedTestRecipe("cached-recipe", "linux")}
+
+	f := NewFallbackRecipeFetcher(RecipeSource{"service", service}, RecipeSource{"cache", cache})
+	f.Cache = cache
+
+	_, err = f.FetchRecipes(context.Background(), &types.DiscoveryManifest{})
+	require.NoError(t, err)
+
+	service.FetchRecipesErr = NewErrSourceUnavailable(errors.New("service down"))
+
+	recipes, err := f.FetchRecipes(context.Background(), &types.DiscoveryManifest{})
+	require.NoError(t, err)
+	require.Len(t, recipes, 1)
+	require.Equal(t, "cached-recipe", recipes[0].Name)
+}
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v2"
@@ -49,6 +50,10 @@ func (f *LocalRecipeFetcher) FetchRecipes(ctx context.Context, manifest *types.D
 		return nil, fmt.Errorf("unable to load recipes from empty path spec")
 	}
 
+	if _, err = os.Stat(f.Path); err != nil {
+		return nil, NewErrSourceUnavailable(fmt.Errorf("unable to load recipes: %s", err))
+	}
+
 	recipes, err = loadRecipesFromDir(ctx, f.Path)
 	if err != nil {
 		return nil, err
@@ -97,6 +102,12 @@ func loadRecipesFromDir(ctx context.Context, path string) ([]types.OpenInstallat
 			continue
 		}
 
+		// Keep the file as written, which its signature covers.
+		r.File = string(content)
+		if sig, err := ioutil.ReadFile(path + SignatureExt); err == nil {
+			r.Signature = strings.TrimSpace(string(sig))
+		}
+
 		recipes = append(recipes, r)
 	}
 
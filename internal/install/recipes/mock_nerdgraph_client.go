@@ -7,6 +7,7 @@ import (
 
 type mockNerdGraphClient struct {
 	respBody interface{}
+	err      error
 }
 
 func newMockNerdGraphClient() *mockNerdGraphClient {
@@ -16,6 +17,10 @@ func newMockNerdGraphClient() *mockNerdGraphClient {
 }
 
 func (c *mockNerdGraphClient) QueryWithResponseAndContext(ctx context.Context, query string, variables map[string]interface{}, respBody interface{}) error {
+	if c.err != nil {
+		return c.err
+	}
+
 	respBodyPtrValue := reflect.ValueOf(respBody)
 	respBodyValue := reflect.Indirect(respBodyPtrValue)
 	respBodyValue.Set(reflect.ValueOf(c.respBody))
@@ -18,6 +18,7 @@ type MockRecipeFetcher struct {
 	FetchRecipeVal                *types.OpenInstallationRecipe
 	FetchRecipesVal               []types.OpenInstallationRecipe
 	FetchRecommendationsVal       []types.OpenInstallationRecipe
+	FetchRecommendationsVals      [][]types.OpenInstallationRecipe
 	FetchRecipeNameCount          map[string]int
 }
 
@@ -48,5 +49,11 @@ func (f *MockRecipeFetcher) FetchRecipes(ctx context.Context, manifest *types.Di
 
 func (f *MockRecipeFetcher) FetchRecommendations(ctx context.Context, manifest *types.DiscoveryManifest) ([]types.OpenInstallationRecipe, error) {
 	f.FetchRecommendationsCallCount++
+
+	if len(f.FetchRecommendationsVals) > 0 {
+		i := utils.MinOf(f.FetchRecommendationsCallCount, len(f.FetchRecommendationsVals)) - 1
+		return f.FetchRecommendationsVals[i], f.FetchRecommendationsErr
+	}
+
 	return f.FetchRecommendationsVal, f.FetchRecommendationsErr
 }
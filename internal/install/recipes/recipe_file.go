@@ -23,6 +23,15 @@ func NewRecipeFileFetcher() RecipeFileFetcher {
 	return &f
 }
 
+// NewRecipeFileFetcherWithClient returns a RecipeFileFetcher that fetches
+// recipe files with the given HTTP client.
+func NewRecipeFileFetcherWithClient(c *http.Client) RecipeFileFetcher {
+	f := RecipeFileFetcherImpl{}
+	f.HTTPGetFunc = c.Get
+	f.readFileFunc = defaultReadFileFunc
+	return &f
+}
+
 func defaultHTTPGetFunc(recipeURL string) (*http.Response, error) {
 	return http.Get(recipeURL)
 }
@@ -48,7 +57,7 @@ func (f *RecipeFileFetcherImpl) FetchRecipeFile(recipeURL *url.URL) (*types.Open
 		return nil, err
 	}
 
-	return NewRecipeFile(string(body))
+	return newRecipeFileWithContent(string(body))
 }
 
 func (f *RecipeFileFetcherImpl) LoadRecipeFile(filename string) (*types.OpenInstallationRecipe, error) {
@@ -57,7 +66,7 @@ func (f *RecipeFileFetcherImpl) LoadRecipeFile(filename string) (*types.OpenInst
 		return nil, err
 	}
 
-	return NewRecipeFile(string(out))
+	return newRecipeFileWithContent(string(out))
 }
 
 func NewRecipeFile(recipeFileString string) (*types.OpenInstallationRecipe, error) {
@@ -69,3 +78,16 @@ func NewRecipeFile(recipeFileString string) (*types.OpenInstallationRecipe, erro
 
 	return &f, nil
 }
+
+// newRecipeFileWithContent parses the recipe file, keeping the file as
+// written, which its signature covers.
+func newRecipeFileWithContent(recipeFileString string) (*types.OpenInstallationRecipe, error) {
+	f, err := NewRecipeFile(recipeFileString)
+	if err != nil {
+		return nil, err
+	}
+
+	f.File = recipeFileString
+
+	return f, nil
+}
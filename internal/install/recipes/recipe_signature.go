@@ -0,0 +1,174 @@
+package recipes
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// SignatureExt is appended to the path or URL of a recipe file to locate its
+// detached signature.
+const SignatureExt = ".sig"
+
+// SignatureStatus is the outcome of verifying the signature of a recipe.
+type SignatureStatus string
+
+var SignatureStatuses = struct {
+	VALID    SignatureStatus
+	UNSIGNED SignatureStatus
+	INVALID  SignatureStatus
+}{
+	VALID:    "VALID",
+	UNSIGNED: "UNSIGNED",
+	INVALID:  "INVALID",
+}
+
+// RecipeVerifier verifies recipes are signed by one of a set of trusted
+// public keys.  The signature covers the recipe file exactly as it was
+// written, so a recipe that was altered no longer verifies.
+type RecipeVerifier struct {
+	keys []ed25519.PublicKey
+	// RequireSigned rejects recipes whose signature is missing or invalid.
+	// Otherwise verification results are only reported.
+	RequireSigned bool
+}
+
+// NewRecipeVerifier returns a new instance of RecipeVerifier trusting the
+// given keys.
+func NewRecipeVerifier(keys []ed25519.PublicKey, requireSigned bool) *RecipeVerifier {
+	v := RecipeVerifier{
+		keys:          keys,
+		RequireSigned: requireSigned,
+	}
+
+	return &v
+}
+
+// Verify returns whether the recipe carries a signature of its file by one of
+// the trusted keys.
+func (v *RecipeVerifier) Verify(r types.OpenInstallationRecipe) SignatureStatus {
+	if r.Signature == "" || r.File == "" {
+		return SignatureStatuses.UNSIGNED
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(r.Signature))
+	if err != nil {
+		return SignatureStatuses.INVALID
+	}
+
+	for _, k := range v.keys {
+		if ed25519.Verify(k, []byte(r.File), sig) {
+			return SignatureStatuses.VALID
+		}
+	}
+
+	return SignatureStatuses.INVALID
+}
+
+// Check verifies the recipe and reports the result, returning an
+// ErrRecipeSignature when the recipe is rejected.  A recipe whose signature
+// verifies is re-derived from its signed file, so that the install which runs
+// is the one the signature covers rather than one stored alongside it.
+func (v *RecipeVerifier) Check(r *types.OpenInstallationRecipe) error {
+	status := v.Verify(*r)
+
+	log.WithFields(log.Fields{
+		"name":      r.Name,
+		"signature": status,
+	}).Debug("verified recipe signature")
+
+	if status == SignatureStatuses.VALID {
+		return fromSignedFile(r)
+	}
+
+	if v.RequireSigned {
+		return NewErrRecipeSignature(r.Name, status)
+	}
+
+	if status == SignatureStatuses.INVALID {
+		log.Warnf("The signature of recipe %s could not be verified.", r.Name)
+	}
+
+	return nil
+}
+
+// fromSignedFile replaces the recipe with the one parsed from its file, keeping
+// its signature and, when the file does not name one, its ID.
+func fromSignedFile(r *types.OpenInstallationRecipe) error {
+	signed, err := newRecipeFileWithContent(r.File)
+	if err != nil {
+		return fmt.Errorf("could not parse signed recipe %s: %s", r.Name, err)
+	}
+
+	signed.Signature = r.Signature
+	if signed.ID == "" {
+		signed.ID = r.ID
+	}
+
+	*r = *signed
+
+	return nil
+}
+
+// SignRecipe signs the file of the recipe with the given key, setting its
+// signature.
+func SignRecipe(r *types.OpenInstallationRecipe, key ed25519.PrivateKey) {
+	r.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, []byte(r.File)))
+}
+
+// LoadPublicKeys reads ed25519 public keys from the given files.  A file holds
+// either a PEM encoded public key, as written by openssl, or a base64 encoded
+// raw key.
+func LoadPublicKeys(paths []string) ([]ed25519.PublicKey, error) {
+	keys := []ed25519.PublicKey{}
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read public key %s: %s", path, err)
+		}
+
+		k, err := parsePublicKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse public key %s: %s", path, err)
+		}
+
+		keys = append(keys, k)
+	}
+
+	return keys, nil
+}
+
+func parsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		k, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		edKey, ok := k.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("not an ed25519 key")
+		}
+
+		return edKey, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("expected a %d byte ed25519 key", ed25519.PublicKeySize)
+	}
+
+	return ed25519.PublicKey(raw), nil
+}
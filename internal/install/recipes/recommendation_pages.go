@@ -0,0 +1,101 @@
+package recipes
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+var (
+	// recommendationPageAttempts is the number of times a page of
+	// recommendations is requested before the fetch is given up.
+	recommendationPageAttempts = 3
+	// recommendationPageRetryDelay is waited before retrying a page, growing
+	// with each attempt.
+	recommendationPageRetryDelay = time.Second
+)
+
+// RecommendationsPage is a page of recipe recommendations.
+type RecommendationsPage struct {
+	Recipes []types.OpenInstallationRecipe
+	// NextCursor identifies the following page, and is empty on the last one.
+	NextCursor string
+	// TotalCount is the number of recommendations across all pages, or zero
+	// when unknown.
+	TotalCount int
+}
+
+// PagedRecommendationFetcher is implemented by recipe fetchers able to fetch
+// recommendations a page at a time, so that a failed fetch can be resumed.
+type PagedRecommendationFetcher interface {
+	// FetchRecommendationsPage fetches the page of recommendations at the
+	// given cursor, the first page when the cursor is empty.
+	FetchRecommendationsPage(ctx context.Context, manifest *types.DiscoveryManifest, cursor string) (*RecommendationsPage, error)
+}
+
+// FetchRecommendationsFrom fetches the recommendations of every page from the
+// given cursor onward, retrying each failed page unless paging is unsupported.  When a page cannot be
+// fetched, the recommendations gathered so far are returned along with an
+// ErrPartialRecommendations holding the cursor to resume from.
+func FetchRecommendationsFrom(ctx context.Context, f PagedRecommendationFetcher, manifest *types.DiscoveryManifest, cursor string) ([]types.OpenInstallationRecipe, error) {
+	recipes := []types.OpenInstallationRecipe{}
+	expected := 0
+
+	for {
+		page, err := fetchRecommendationsPageWithRetry(ctx, f, manifest, cursor)
+		if err != nil {
+			return recipes, NewErrPartialRecommendations(cursor, len(recipes), expected, err)
+		}
+
+		recipes = append(recipes, page.Recipes...)
+		if page.TotalCount > expected {
+			expected = page.TotalCount
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+
+		cursor = page.NextCursor
+	}
+
+	log.WithFields(log.Fields{
+		"retrieved": len(recipes),
+		"expected":  expected,
+	}).Debug("fetched recommendations")
+
+	return recipes, nil
+}
+
+func fetchRecommendationsPageWithRetry(ctx context.Context, f PagedRecommendationFetcher, manifest *types.DiscoveryManifest, cursor string) (*RecommendationsPage, error) {
+	var err error
+
+	for attempt := 1; attempt <= recommendationPageAttempts; attempt++ {
+		var page *RecommendationsPage
+		page, err = f.FetchRecommendationsPage(ctx, manifest, cursor)
+		if err == nil {
+			return page, nil
+		}
+
+		if ctx.Err() != nil || errors.Is(err, ErrPaginationUnsupported) || attempt == recommendationPageAttempts {
+			break
+		}
+
+		log.WithFields(log.Fields{
+			"cursor":  cursor,
+			"attempt": attempt,
+		}).Debugf("retrying page of recommendations: %s", err)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Duration(attempt) * recommendationPageRetryDelay):
+		}
+	}
+
+	return nil, err
+}
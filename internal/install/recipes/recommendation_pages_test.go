@@ -0,0 +1,96 @@
+// +build unit
+
+package recipes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// testPager serves the given pages, keyed by cursor, failing each cursor the
+// number of times given in failures.
+type testPager struct {
+	pages    map[string]*RecommendationsPage
+	failures map[string]int
+	calls    []string
+}
+
+func (p *testPager) FetchRecommendationsPage(ctx context.Context, manifest *types.DiscoveryManifest, cursor string) (*RecommendationsPage, error) {
+	p.calls = append(p.calls, cursor)
+
+	if p.failures[cursor] > 0 {
+		p.failures[cursor]--
+		return nil, errors.New("service unavailable")
+	}
+
+	return p.pages[cursor], nil
+}
+
+func newTestPager() *testPager {
+	return &testPager{
+		pages: map[string]*RecommendationsPage{
+			"":   {Recipes: []types.OpenInstallationRecipe{{Name: "a"}, {Name: "b"}}, NextCursor: "p2", TotalCount: 5},
+			"p2": {Recipes: []types.OpenInstallationRecipe{{Name: "c"}, {Name: "d"}}, NextCursor: "p3", TotalCount: 5},
+			"p3": {Recipes: []types.OpenInstallationRecipe{{Name: "e"}}},
+		},
+		failures: map[string]int{},
+	}
+}
+
+func withoutRetryDelay() func() {
+	delay := recommendationPageRetryDelay
+	recommendationPageRetryDelay = 0
+
+	return func() {
+		recommendationPageRetryDelay = delay
+	}
+}
+
+func TestFetchRecommendationsFrom(t *testing.T) {
+	p := newTestPager()
+
+	recipes, err := FetchRecommendationsFrom(context.Background(), p, &types.DiscoveryManifest{}, "")
+	require.NoError(t, err)
+	require.Len(t, recipes, 5)
+	require.Equal(t, []string{"", "p2", "p3"}, p.calls)
+}
+
+func TestFetchRecommendationsFrom_RetriesFailedPage(t *testing.T) {
+	defer withoutRetryDelay()()
+
+	p := newTestPager()
+	p.failures["p2"] = 2
+
+	recipes, err := FetchRecommendationsFrom(context.Background(), p, &types.DiscoveryManifest{}, "")
+	require.NoError(t, err)
+	require.Len(t, recipes, 5)
+	require.Equal(t, []string{"", "p2", "p2", "p2", "p3"}, p.calls)
+}
+
+func TestFetchRecommendationsFrom_Partial(t *testing.T) {
+	defer withoutRetryDelay()()
+
+	p := newTestPager()
+	p.failures["p3"] = recommendationPageAttempts
+
+	recipes, err := FetchRecommendationsFrom(context.Background(), p, &types.DiscoveryManifest{}, "")
+	require.Error(t, err)
+	require.Len(t, recipes, 4)
+
+	var perr ErrPartialRecommendations
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, "p3", perr.Cursor)
+	require.Equal(t, 4, perr.Retrieved)
+	require.Equal(t, 5, perr.Expected)
+	require.Contains(t, perr.Error(), "retrieved 4 of 5 recommendations")
+
+	// Resume from the cursor of the failed page.
+	recipes, err = FetchRecommendationsFrom(context.Background(), p, &types.DiscoveryManifest{}, perr.Cursor)
+	require.NoError(t, err)
+	require.Equal(t, []types.OpenInstallationRecipe{{Name: "e"}}, recipes)
+}
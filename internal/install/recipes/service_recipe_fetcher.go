@@ -4,17 +4,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/newrelic/newrelic-cli/internal/install/types"
+	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
 )
 
 // ServiceRecipeFetcher is an implementation of the recipeFetcher interface that
 // relies on the Neerdgraph-stitched recipe service to source its results.
 type ServiceRecipeFetcher struct {
 	client NerdGraphClient
+	// pagingUnsupported records that the service rejected the paged
+	// recommendations query, so that it is not sent again.
+	pagingUnsupported bool
 }
 
 // NewServiceRecipeFetcher returns a new instance of ServiceRecipeFetcher.
@@ -43,7 +49,7 @@ func (f *ServiceRecipeFetcher) FetchRecipe(ctx context.Context, manifest *types.
 
 	var resp recipeSearchQueryResult
 	if err := f.client.QueryWithResponseAndContext(ctx, recipeSearchQuery, vars, &resp); err != nil {
-		return nil, err
+		return nil, sourceError(err)
 	}
 
 	results := resp.Docs.OpenInstallation.RecipeSearch.Results
@@ -51,11 +57,11 @@ func (f *ServiceRecipeFetcher) FetchRecipe(ctx context.Context, manifest *types.
 	if len(results) == 0 {
 		switch friendlyName {
 		case types.InfraAgentRecipeName:
-			return nil, errors.New("infrastructure agent was unable to be installed for your operating system. For additional installation options please see: https://docs.newrelic.com/docs/infrastructure/install-infrastructure-agent/linux-installation/tarball-assisted-install-infrastructure-agent-linux/")
+			return nil, NewErrRecipeNotFoundMessage("infrastructure agent was unable to be installed for your operating system. For additional installation options please see: https://docs.newrelic.com/docs/infrastructure/install-infrastructure-agent/linux-installation/tarball-assisted-install-infrastructure-agent-linux/")
 		case types.LoggingRecipeName:
-			return nil, errors.New("logs was unable to be installed for your operating system. For additional installation options please see: https://docs.newrelic.com/docs/logs/enable-log-management-new-relic/enable-log-monitoring-new-relic/enable-log-management-new-relic/")
+			return nil, NewErrRecipeNotFoundMessage("logs was unable to be installed for your operating system. For additional installation options please see: https://docs.newrelic.com/docs/logs/enable-log-management-new-relic/enable-log-monitoring-new-relic/enable-log-management-new-relic/")
 		default:
-			return nil, fmt.Errorf("%s was unable to be installed for your operating system", friendlyName)
+			return nil, NewErrRecipeNotFoundMessage(fmt.Sprintf("%s was unable to be installed for your operating system", friendlyName))
 		}
 	}
 
@@ -67,8 +73,78 @@ func (f *ServiceRecipeFetcher) FetchRecipe(ctx context.Context, manifest *types.
 }
 
 // FetchRecommendations fetches recipe recommendations from the recipe service
-// based on the information passed in the provided DiscoveryManifest.
+// based on the information passed in the provided DiscoveryManifest.  They are
+// fetched a page at a time; should a page fail, the recommendations gathered
+// until then are returned with an ErrPartialRecommendations.  Services which
+// do not paginate are asked for all recommendations at once instead.
 func (f *ServiceRecipeFetcher) FetchRecommendations(ctx context.Context, manifest *types.DiscoveryManifest) ([]types.OpenInstallationRecipe, error) {
+	if f.pagingUnsupported {
+		return f.fetchUnpagedRecommendations(ctx, manifest)
+	}
+
+	allRecipes, err := FetchRecommendationsFrom(ctx, f, manifest, "")
+
+	var perr ErrPartialRecommendations
+	if errors.Is(err, ErrPaginationUnsupported) || (errors.As(err, &perr) && perr.Retrieved == 0) {
+		log.Debugf("fetching recommendations without pagination: %s", err)
+		return f.fetchUnpagedRecommendations(ctx, manifest)
+	}
+
+	return uniqueRecipes(allRecipes), err
+}
+
+func (f *ServiceRecipeFetcher) fetchUnpagedRecommendations(ctx context.Context, manifest *types.DiscoveryManifest) ([]types.OpenInstallationRecipe, error) {
+	allRecipes, err := f.fetchAllRecommendations(ctx, manifest)
+	if err != nil {
+		return nil, sourceError(err)
+	}
+
+	return uniqueRecipes(allRecipes), nil
+}
+
+// FetchRecommendationsPage fetches the page of recommendations at the given
+// cursor from the recipe service.  When the service's schema has no cursors,
+// an error matching ErrPaginationUnsupported is returned.
+func (f *ServiceRecipeFetcher) FetchRecommendationsPage(ctx context.Context, manifest *types.DiscoveryManifest, cursor string) (*RecommendationsPage, error) {
+	c, err := createRecommendationsInput(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := map[string]interface{}{
+		"criteria": c,
+	}
+
+	if cursor != "" {
+		vars["cursor"] = cursor
+	}
+
+	if f.pagingUnsupported {
+		return nil, ErrPaginationUnsupported
+	}
+
+	var resp recommendationsQueryResult
+	if err := f.client.QueryWithResponseAndContext(ctx, recommendationsPageQuery, vars, &resp); err != nil {
+		if isGraphQLValidationError(err) {
+			f.pagingUnsupported = true
+			return nil, fmt.Errorf("%w: %s", ErrPaginationUnsupported, err)
+		}
+
+		return nil, err
+	}
+
+	r := resp.Docs.OpenInstallation.Recommendations
+
+	p := RecommendationsPage{
+		Recipes:    r.Results,
+		NextCursor: r.NextCursor,
+		TotalCount: r.TotalCount,
+	}
+
+	return &p, nil
+}
+
+func (f *ServiceRecipeFetcher) fetchAllRecommendations(ctx context.Context, manifest *types.DiscoveryManifest) ([]types.OpenInstallationRecipe, error) {
 	c, err := createRecommendationsInput(manifest)
 	if err != nil {
 		return nil, err
@@ -83,8 +159,68 @@ func (f *ServiceRecipeFetcher) FetchRecommendations(ctx context.Context, manifes
 		return nil, err
 	}
 
-	allRecipes := resp.Docs.OpenInstallation.Recommendations.Results
+	return resp.Docs.OpenInstallation.Recommendations.Results, nil
+}
 
+// sourceError wraps err in an ErrSourceUnavailable when the recipe service
+// could not be reached or failed on its side, so that other recipe sources are
+// tried instead.  Cancellation, rejected credentials and rejected queries are
+// returned unchanged.
+func sourceError(err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	var serr *nrErrors.UnexpectedStatusCode
+	if errors.As(err, &serr) {
+		if HTTPStatusCode(serr) >= http.StatusInternalServerError {
+			return NewErrSourceUnavailable(err)
+		}
+
+		return err
+	}
+
+	var merr *nrErrors.MaxRetriesReached
+	var nerr net.Error
+	if errors.As(err, &merr) || errors.As(err, &nerr) {
+		return NewErrSourceUnavailable(err)
+	}
+
+	// The New Relic client gives up on a service that keeps failing with
+	// server errors with an untyped error.
+	if strings.Contains(err.Error(), "giving up after") {
+		return NewErrSourceUnavailable(err)
+	}
+
+	return err
+}
+
+// graphQLValidationErrorPatterns are the messages with which GraphQL servers
+// reject a query that does not match their schema.  Such a query fails the
+// same way however often it is sent.
+var graphQLValidationErrorPatterns = []string{
+	"cannot query field",
+	"unknown argument",
+	"unknown type",
+}
+
+// isGraphQLValidationError reports whether err is the rejection of a query
+// that does not match the schema of the service.  The New Relic client returns
+// GraphQL errors untyped, so they are told apart by their message.
+func isGraphQLValidationError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, p := range graphQLValidationErrorPatterns {
+		if strings.Contains(msg, p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// uniqueRecipes returns the given recipes without those whose name was
+// already seen.
+func uniqueRecipes(recipes []types.OpenInstallationRecipe) []types.OpenInstallationRecipe {
 	r := []types.OpenInstallationRecipe{}
 
 	recipeIncluded := func(recipe types.OpenInstallationRecipe, recipes []types.OpenInstallationRecipe) bool {
@@ -97,7 +233,7 @@ func (f *ServiceRecipeFetcher) FetchRecommendations(ctx context.Context, manifes
 		return false
 	}
 
-	for _, recipe := range allRecipes {
+	for _, recipe := range recipes {
 		if recipeIncluded(recipe, r) {
 			continue
 		}
@@ -105,7 +241,7 @@ func (f *ServiceRecipeFetcher) FetchRecommendations(ctx context.Context, manifes
 		r = append(r, recipe)
 	}
 
-	return r, nil
+	return r
 }
 
 // FetchRecipes fetches all available recipes from the recipe service.
@@ -121,12 +257,31 @@ func (f *ServiceRecipeFetcher) FetchRecipes(ctx context.Context, manifest *types
 	}
 
 	if err := f.client.QueryWithResponseAndContext(ctx, recipeSearchQuery, vars, &resp); err != nil {
-		return nil, err
+		return nil, sourceError(err)
 	}
 
 	return resp.Docs.OpenInstallation.RecipeSearch.Results, nil
 }
 
+// PingRecipeService confirms the recipe service behind the given client can be
+// reached and queried.
+func PingRecipeService(ctx context.Context, client NerdGraphClient) error {
+	var resp pingQueryResult
+	if err := client.QueryWithResponseAndContext(ctx, pingQuery, map[string]interface{}{}, &resp); err != nil {
+		return sourceError(err)
+	}
+
+	return nil
+}
+
+type pingQueryResult struct {
+	Docs struct {
+		OpenInstallation struct {
+			Typename string `json:"__typename"`
+		} `json:"openInstallation"`
+	} `json:"docs"`
+}
+
 type recommendationsQueryResult struct {
 	Docs recommendationsQueryDocs `json:"docs"`
 }
@@ -140,7 +295,9 @@ type recommendationsQueryOpenInstallation struct {
 }
 
 type recommendationsResult struct {
-	Results []types.OpenInstallationRecipe `json:"results"`
+	Results    []types.OpenInstallationRecipe `json:"results"`
+	NextCursor string                         `json:"nextCursor,omitempty"`
+	TotalCount int                            `json:"totalCount,omitempty"`
 }
 
 type recommendationsInput struct {
@@ -230,6 +387,8 @@ const (
 		dependencies
 		stability
 		repository
+		file
+		signature
 		install
 		installTargets {
 			type
@@ -282,6 +441,30 @@ const (
 		}
 	}`
 
+	recommendationsPageQuery = `
+	query Recommendations($criteria: OpenInstallationRecommendationsInput, $cursor: String){
+		docs {
+			openInstallation {
+				recommendations(criteria: $criteria, cursor: $cursor) {
+					nextCursor
+					totalCount
+					results {
+						` + recipeResultFragment + `
+					}
+				}
+			}
+		}
+	}`
+
+	pingQuery = `
+	query {
+		docs {
+			openInstallation {
+				__typename
+			}
+		}
+	}`
+
 	recommendationsQuery = `
 	query Recommendations($criteria: OpenInstallationRecommendationsInput){
 		docs {
@@ -4,12 +4,16 @@ package recipes
 
 import (
 	"context"
+	"errors"
+	"net"
+	"net/url"
 	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/newrelic/newrelic-cli/internal/install/types"
+	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
 )
 
 func TestFetchFilters(t *testing.T) {
@@ -106,3 +110,110 @@ func wrapRecommendations(r []types.OpenInstallationRecipe) recommendationsQueryR
 		},
 	}
 }
+
+func TestPingRecipeService(t *testing.T) {
+	c := newMockNerdGraphClient()
+	c.respBody = pingQueryResult{}
+
+	require.NoError(t, PingRecipeService(context.Background(), c))
+}
+
+func TestPingRecipeService_Unavailable(t *testing.T) {
+	c := newMockNerdGraphClient()
+	c.err = errConnectionRefused
+
+	err := PingRecipeService(context.Background(), c)
+	require.Error(t, err)
+	require.True(t, errors.As(err, &ErrSourceUnavailable{}))
+}
+
+func TestFetchRecommendations_FallsBackWithoutPagination(t *testing.T) {
+	c := &pagedNerdGraphClient{
+		pageErr: errors.New("Cannot query field \"nextCursor\" on type \"OpenInstallationRecommendations\""),
+		all:     wrapRecommendations([]types.OpenInstallationRecipe{{Name: "a"}, {Name: "b"}}),
+	}
+
+	s := NewServiceRecipeFetcher(c)
+
+	recipes, err := s.FetchRecommendations(context.Background(), &types.DiscoveryManifest{})
+	require.NoError(t, err)
+	require.Len(t, recipes, 2)
+	require.Equal(t, 1, c.pageCalls)
+
+	// Once rejected, the paged query is not sent again.
+	recipes, err = s.FetchRecommendations(context.Background(), &types.DiscoveryManifest{})
+	require.NoError(t, err)
+	require.Len(t, recipes, 2)
+	require.Equal(t, 1, c.pageCalls)
+}
+
+func TestFetchRecommendations_Unavailable(t *testing.T) {
+	defer withoutRetryDelay()()
+
+	c := newMockNerdGraphClient()
+	c.err = errConnectionRefused
+
+	s := NewServiceRecipeFetcher(c)
+
+	_, err := s.FetchRecommendations(context.Background(), &types.DiscoveryManifest{})
+	require.True(t, errors.As(err, &ErrSourceUnavailable{}))
+}
+
+func TestFetchRecipe_ErrorsNotUnavailable(t *testing.T) {
+	errs := []error{
+		context.Canceled,
+		nrErrors.NewUnauthorizedError(),
+		nrErrors.NewUnexpectedStatusCode(403, ""),
+		errors.New("Cannot query field \"name\""),
+	}
+
+	for _, err := range errs {
+		c := newMockNerdGraphClient()
+		c.err = err
+
+		_, fetchErr := NewServiceRecipeFetcher(c).FetchRecipe(context.Background(), &types.DiscoveryManifest{}, "test")
+		require.Equal(t, err, fetchErr)
+	}
+}
+
+func TestFetchRecipe_ErrorsUnavailable(t *testing.T) {
+	errs := []error{
+		errConnectionRefused,
+		nrErrors.NewUnexpectedStatusCode(503, ""),
+		nrErrors.NewMaxRetriesReached("internal server error"),
+		errors.New("POST https://api.newrelic.com/graphql giving up after 4 attempt(s)"),
+	}
+
+	for _, err := range errs {
+		c := newMockNerdGraphClient()
+		c.err = err
+
+		_, fetchErr := NewServiceRecipeFetcher(c).FetchRecipe(context.Background(), &types.DiscoveryManifest{}, "test")
+		require.True(t, errors.As(fetchErr, &ErrSourceUnavailable{}), err.Error())
+	}
+}
+
+var errConnectionRefused = &url.Error{
+	Op:  "Post",
+	URL: "https://api.newrelic.com/graphql",
+	Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")},
+}
+
+// pagedNerdGraphClient fails the paged recommendations query, and answers any
+// other query with all.
+type pagedNerdGraphClient struct {
+	pageErr   error
+	pageCalls int
+	all       recommendationsQueryResult
+}
+
+func (c *pagedNerdGraphClient) QueryWithResponseAndContext(ctx context.Context, query string, variables map[string]interface{}, respBody interface{}) error {
+	if query == recommendationsPageQuery {
+		c.pageCalls++
+		return c.pageErr
+	}
+
+	*respBody.(*recommendationsQueryResult) = c.all
+
+	return nil
+}
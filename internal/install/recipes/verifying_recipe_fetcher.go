@@ -0,0 +1,175 @@
+package recipes
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// VerifyingRecipeFetcher is an implementation of the RecipeFetcher interface
+// that verifies the signatures of the recipes served by another fetcher,
+// leaving out those the verifier rejects.
+type VerifyingRecipeFetcher struct {
+	fetcher  RecipeFetcher
+	verifier *RecipeVerifier
+}
+
+// NewVerifyingRecipeFetcher returns a new instance of VerifyingRecipeFetcher.
+func NewVerifyingRecipeFetcher(fetcher RecipeFetcher, verifier *RecipeVerifier) *VerifyingRecipeFetcher {
+	f := VerifyingRecipeFetcher{
+		fetcher:  fetcher,
+		verifier: verifier,
+	}
+
+	return &f
+}
+
+func (f *VerifyingRecipeFetcher) FetchRecipe(ctx context.Context, manifest *types.DiscoveryManifest, friendlyName string) (*types.OpenInstallationRecipe, error) {
+	r, err := f.fetcher.FetchRecipe(ctx, manifest, friendlyName)
+	if err != nil || r == nil {
+		return r, err
+	}
+
+	if err := f.verifier.Check(r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (f *VerifyingRecipeFetcher) FetchRecommendations(ctx context.Context, manifest *types.DiscoveryManifest) ([]types.OpenInstallationRecipe, error) {
+	recipes, err := f.fetcher.FetchRecommendations(ctx, manifest)
+
+	// Partial recommendations are verified and passed on with the error.
+	var perr ErrPartialRecommendations
+	if err != nil && !errors.As(err, &perr) {
+		return nil, err
+	}
+
+	return f.verified(recipes), err
+}
+
+func (f *VerifyingRecipeFetcher) FetchRecipes(ctx context.Context, manifest *types.DiscoveryManifest) ([]types.OpenInstallationRecipe, error) {
+	recipes, err := f.fetcher.FetchRecipes(ctx, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.verified(recipes), nil
+}
+
+func (f *VerifyingRecipeFetcher) verified(recipes []types.OpenInstallationRecipe) []types.OpenInstallationRecipe {
+	verified := []types.OpenInstallationRecipe{}
+	for _, r := range recipes {
+		r := r
+		if err := f.verifier.Check(&r); err != nil {
+			log.Warnf("Skipping recipe %s: %s", r.Name, err)
+			continue
+		}
+
+		verified = append(verified, r)
+	}
+
+	return verified
+}
+
+// VerifyingRecipeFileFetcher is an implementation of the RecipeFileFetcher
+// interface that verifies the signatures of the recipe files fetched by
+// another fetcher, returning an error for those the verifier rejects.  The
+// detached signature of a recipe file is looked for next to it, at its path or
+// URL with SignatureExt appended.
+type VerifyingRecipeFileFetcher struct {
+	HTTPGetFunc  func(string) (*http.Response, error)
+	readFileFunc func(string) ([]byte, error)
+	fetcher      RecipeFileFetcher
+	verifier     *RecipeVerifier
+}
+
+// NewVerifyingRecipeFileFetcher returns a new instance of
+// VerifyingRecipeFileFetcher.
+func NewVerifyingRecipeFileFetcher(fetcher RecipeFileFetcher, verifier *RecipeVerifier) *VerifyingRecipeFileFetcher {
+	f := VerifyingRecipeFileFetcher{
+		HTTPGetFunc:  defaultHTTPGetFunc,
+		readFileFunc: defaultReadFileFunc,
+		fetcher:      fetcher,
+		verifier:     verifier,
+	}
+
+	return &f
+}
+
+// NewVerifyingRecipeFileFetcherWithClient returns a VerifyingRecipeFileFetcher
+// that fetches signatures with the given HTTP client.
+func NewVerifyingRecipeFileFetcherWithClient(fetcher RecipeFileFetcher, verifier *RecipeVerifier, c *http.Client) *VerifyingRecipeFileFetcher {
+	f := NewVerifyingRecipeFileFetcher(fetcher, verifier)
+	f.HTTPGetFunc = c.Get
+
+	return f
+}
+
+func (f *VerifyingRecipeFileFetcher) FetchRecipeFile(recipeURL *url.URL) (*types.OpenInstallationRecipe, error) {
+	r, err := f.fetcher.FetchRecipeFile(recipeURL)
+	if err != nil || r == nil {
+		return r, err
+	}
+
+	r.Signature = f.fetchSignature(recipeURL)
+
+	return f.check(r)
+}
+
+func (f *VerifyingRecipeFileFetcher) LoadRecipeFile(filename string) (*types.OpenInstallationRecipe, error) {
+	r, err := f.fetcher.LoadRecipeFile(filename)
+	if err != nil || r == nil {
+		return r, err
+	}
+
+	if sig, err := f.readFileFunc(filename + SignatureExt); err == nil {
+		r.Signature = strings.TrimSpace(string(sig))
+	}
+
+	return f.check(r)
+}
+
+func (f *VerifyingRecipeFileFetcher) check(r *types.OpenInstallationRecipe) (*types.OpenInstallationRecipe, error) {
+	if err := f.verifier.Check(r); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// fetchSignature downloads the detached signature published next to the
+// recipe file, returning an empty signature when there is none.
+func (f *VerifyingRecipeFileFetcher) fetchSignature(recipeURL *url.URL) string {
+	sigURL := *recipeURL
+	sigURL.Path += SignatureExt
+
+	response, err := f.HTTPGetFunc(sigURL.String())
+	if err != nil {
+		log.Debugf("could not fetch recipe signature: %s", err)
+		return ""
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		log.Debugf("no recipe signature found at %s: status code %d", sigURL.String(), response.StatusCode)
+		return ""
+	}
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		log.Debugf("could not fetch recipe signature: %s", err)
+		return ""
+	}
+
+	return strings.TrimSpace(string(body))
+}
@@ -0,0 +1,82 @@
+package remote
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// MockResponse is the canned result of a command run by MockRunner.
+type MockResponse struct {
+	Output string
+	Err    error
+}
+
+// MockRunner is a mock implementation of the Runner interface that responds to
+// commands by the longest matching command prefix.
+type MockRunner struct {
+	HostVal   string
+	Responses map[string]MockResponse
+	Commands  []string
+	Stdins    []string
+	Closed    bool
+}
+
+// NewMockRunner creates a new instance of MockRunner.
+func NewMockRunner(host string) *MockRunner {
+	return &MockRunner{
+		HostVal:   host,
+		Responses: map[string]MockResponse{},
+	}
+}
+
+func (r *MockRunner) Host() string {
+	return r.HostVal
+}
+
+func (r *MockRunner) Run(ctx context.Context, cmd string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	r.Commands = append(r.Commands, cmd)
+
+	if stdin != nil {
+		in, err := ioutil.ReadAll(stdin)
+		if err != nil {
+			return err
+		}
+		r.Stdins = append(r.Stdins, string(in))
+	}
+
+	resp := r.response(cmd)
+	if stdout != nil {
+		if _, err := io.WriteString(stdout, resp.Output); err != nil {
+			return err
+		}
+	}
+
+	return resp.Err
+}
+
+func (r *MockRunner) Output(ctx context.Context, cmd string) (string, error) {
+	var out strings.Builder
+	err := r.Run(ctx, cmd, nil, &out, nil)
+	return out.String(), err
+}
+
+func (r *MockRunner) Close() error {
+	r.Closed = true
+	return nil
+}
+
+func (r *MockRunner) response(cmd string) MockResponse {
+	longest := -1
+	resp := MockResponse{}
+
+	for prefix, v := range r.Responses {
+		if strings.HasPrefix(cmd, prefix) && len(prefix) > longest {
+			longest = len(prefix)
+			resp = v
+		}
+	}
+
+	return resp
+}
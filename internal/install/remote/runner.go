@@ -0,0 +1,19 @@
+package remote
+
+import (
+	"context"
+	"io"
+)
+
+// Runner runs shell commands on a remote host.
+type Runner interface {
+	// Host returns the address of the remote host.
+	Host() string
+	// Run runs the command, connecting its standard streams to those given.
+	// Any of the streams may be nil.
+	Run(ctx context.Context, cmd string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error
+	// Output runs the command and returns its standard output.
+	Output(ctx context.Context, cmd string) (string, error)
+	// Close releases the connection to the remote host.
+	Close() error
+}
@@ -0,0 +1,223 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const dialTimeout = 30 * time.Second
+
+// defaultKeyFiles are the private keys tried, relative to ~/.ssh, when no key
+// file is configured.
+var defaultKeyFiles = []string{"id_ed25519", "id_ecdsa", "id_rsa"}
+
+// SSHConfig holds the options used to connect to remote hosts.
+type SSHConfig struct {
+	// KeyFile is the private key used to authenticate.  When empty, the SSH
+	// agent and the default keys in ~/.ssh are used.
+	KeyFile string
+	// KnownHostsFile is used to verify host keys.  Defaults to
+	// ~/.ssh/known_hosts.
+	KnownHostsFile string
+}
+
+// SSHRunner is an implementation of the Runner interface that runs commands
+// over an SSH connection.
+type SSHRunner struct {
+	target Target
+	client *ssh.Client
+	// agent is the connection to the SSH agent, nil when none is used.
+	agent net.Conn
+}
+
+// NewSSHRunner connects to the given target, authenticating with the SSH agent
+// and private keys, and verifying the host key against known hosts.
+func NewSSHRunner(t Target, c SSHConfig) (*SSHRunner, error) {
+	auth, agentConn, err := authMethods(c)
+	if err != nil {
+		return nil, err
+	}
+
+	hostKeyCallback, err := hostKeyCallback(c)
+	if err != nil {
+		closeAgent(agentConn)
+		return nil, err
+	}
+
+	cfg := &ssh.ClientConfig{
+		User:            t.User,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         dialTimeout,
+	}
+
+	client, err := ssh.Dial("tcp", t.Address(), cfg)
+	if err != nil {
+		closeAgent(agentConn)
+		return nil, fmt.Errorf("could not connect to %s: %s", t, err)
+	}
+
+	r := SSHRunner{
+		target: t,
+		client: client,
+		agent:  agentConn,
+	}
+
+	return &r, nil
+}
+
+func (r *SSHRunner) Host() string {
+	return r.target.Host
+}
+
+func (r *SSHRunner) Run(ctx context.Context, cmd string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	session, err := r.client.NewSession()
+	if err != nil {
+		return fmt.Errorf("could not open session on %s: %s", r.target.Host, err)
+	}
+	defer session.Close()
+
+	session.Stdin = stdin
+	session.Stdout = stdout
+	session.Stderr = stderr
+
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Run(cmd)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		// Interrupt the remote command; closing the session detaches from it.
+		if err := session.Signal(ssh.SIGINT); err != nil {
+			log.Debugf("could not signal remote command on %s: %s", r.target.Host, err)
+		}
+		return ctx.Err()
+	}
+}
+
+func (r *SSHRunner) Output(ctx context.Context, cmd string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	if err := r.Run(ctx, cmd, nil, &stdout, &stderr); err != nil {
+		if stderr.Len() > 0 {
+			return "", fmt.Errorf("%s: %s", err, bytes.TrimSpace(stderr.Bytes()))
+		}
+		return "", err
+	}
+
+	return stdout.String(), nil
+}
+
+func (r *SSHRunner) Close() error {
+	err := r.client.Close()
+	closeAgent(r.agent)
+
+	return err
+}
+
+// authMethods returns the ways to authenticate with the target, along with
+// the connection to the SSH agent they use, if any, for the caller to close.
+func authMethods(c SSHConfig) ([]ssh.AuthMethod, net.Conn, error) {
+	methods := []ssh.AuthMethod{}
+
+	var agentConn net.Conn
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		conn, err := net.Dial("unix", sock)
+		if err != nil {
+			log.Debugf("could not connect to ssh agent: %s", err)
+		} else {
+			agentConn = conn
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		}
+	}
+
+	keyFiles := []string{c.KeyFile}
+	if c.KeyFile == "" {
+		keyFiles = []string{}
+		for _, f := range defaultKeyFiles {
+			keyFiles = append(keyFiles, filepath.Join(sshDir(), f))
+		}
+	}
+
+	signers := []ssh.Signer{}
+	for _, f := range keyFiles {
+		key, err := ioutil.ReadFile(f)
+		if err != nil {
+			if c.KeyFile != "" {
+				closeAgent(agentConn)
+				return nil, nil, fmt.Errorf("could not read ssh key %s: %s", f, err)
+			}
+			continue
+		}
+
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			if c.KeyFile != "" {
+				closeAgent(agentConn)
+				return nil, nil, fmt.Errorf("could not parse ssh key %s: %s", f, err)
+			}
+			log.Debugf("skipping ssh key %s: %s", f, err)
+			continue
+		}
+
+		signers = append(signers, signer)
+	}
+
+	if len(signers) > 0 {
+		methods = append(methods, ssh.PublicKeys(signers...))
+	}
+
+	if len(methods) == 0 {
+		return nil, nil, fmt.Errorf("no ssh agent or usable private key found, use --ssh-key to provide one")
+	}
+
+	return methods, agentConn, nil
+}
+
+func closeAgent(conn net.Conn) {
+	if conn == nil {
+		return
+	}
+
+	if err := conn.Close(); err != nil {
+		log.Debugf("could not close ssh agent connection: %s", err)
+	}
+}
+
+func hostKeyCallback(c SSHConfig) (ssh.HostKeyCallback, error) {
+	file := c.KnownHostsFile
+	if file == "" {
+		file = filepath.Join(sshDir(), "known_hosts")
+	}
+
+	callback, err := knownhosts.New(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not load known hosts from %s: %s", file, err)
+	}
+
+	return callback, nil
+}
+
+func sshDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".ssh"
+	}
+
+	return filepath.Join(home, ".ssh")
+}
@@ -0,0 +1,64 @@
+package remote
+
+import (
+	"fmt"
+	"net"
+	"os/user"
+	"strconv"
+	"strings"
+)
+
+const defaultSSHPort = 22
+
+// Target identifies a remote host to connect to.
+type Target struct {
+	User string
+	Host string
+	Port int
+}
+
+// ParseTarget parses a target of the form [user@]host[:port].  The user
+// defaults to the current user and the port to 22.
+func ParseTarget(s string) (Target, error) {
+	t := Target{
+		Port: defaultSSHPort,
+	}
+
+	if i := strings.LastIndex(s, "@"); i >= 0 {
+		t.User = s[:i]
+		s = s[i+1:]
+	}
+
+	if host, port, err := net.SplitHostPort(s); err == nil {
+		p, err := strconv.Atoi(port)
+		if err != nil || p <= 0 {
+			return Target{}, fmt.Errorf("invalid port in ssh target %s", s)
+		}
+
+		t.Host = host
+		t.Port = p
+	} else {
+		t.Host = strings.Trim(s, "[]")
+	}
+
+	if t.Host == "" {
+		return Target{}, fmt.Errorf("no host in ssh target %s", s)
+	}
+
+	if t.User == "" {
+		if u, err := user.Current(); err == nil {
+			t.User = u.Username
+		}
+	}
+
+	return t, nil
+}
+
+// Address returns the host:port to dial.
+func (t Target) Address() string {
+	return net.JoinHostPort(t.Host, strconv.Itoa(t.Port))
+}
+
+func (t Target) String() string {
+	return t.User + "@" + t.Address()
+}
@@ -0,0 +1,42 @@
+// +build unit
+
+package remote
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTarget(t *testing.T) {
+	target, err := ParseTarget("admin@db-1.example.com:2222")
+	require.NoError(t, err)
+	require.Equal(t, "admin", target.User)
+	require.Equal(t, "db-1.example.com", target.Host)
+	require.Equal(t, 2222, target.Port)
+	require.Equal(t, "db-1.example.com:2222", target.Address())
+}
+
+func TestParseTarget_Defaults(t *testing.T) {
+	target, err := ParseTarget("db-1")
+	require.NoError(t, err)
+	require.Equal(t, "db-1", target.Host)
+	require.Equal(t, 22, target.Port)
+	require.NotEmpty(t, target.User)
+}
+
+func TestParseTarget_IPv6(t *testing.T) {
+	target, err := ParseTarget("root@[::1]:2200")
+	require.NoError(t, err)
+	require.Equal(t, "::1", target.Host)
+	require.Equal(t, 2200, target.Port)
+	require.Equal(t, "[::1]:2200", target.Address())
+}
+
+func TestParseTarget_Invalid(t *testing.T) {
+	_, err := ParseTarget("admin@")
+	require.Error(t, err)
+
+	_, err = ParseTarget("host:notaport")
+	require.Error(t, err)
+}
@@ -0,0 +1,86 @@
+package install
+
+import (
+	"fmt"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/config"
+	"github.com/newrelic/newrelic-cli/internal/install/discovery"
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/remote"
+	"github.com/newrelic/newrelic-client-go/newrelic"
+)
+
+// NewRemoteRecipeInstaller returns a RecipeInstaller that discovers and runs
+// recipes on the remote host behind the given runner.  Status is reported
+// locally as for a local install.
+func NewRemoteRecipeInstaller(ic InstallerContext, nrClient *newrelic.NewRelic, r remote.Runner) *RecipeInstaller {
+	outputDir := ic.OutputDir
+	if outputDir == "" {
+		outputDir = config.DefaultConfigDirectory
+	}
+	ic.OutputDir = filepath.Join(outputDir, "hosts", r.Host())
+
+	i := NewRecipeInstaller(ic, nrClient)
+	i.discoverer = discovery.NewSSHDiscoverer(r, discovery.NewRegexProcessFilterer(i.recipeFetcher))
+	i.fileFilterer = discovery.NewSSHFileFilterer(r)
+	i.recipeExecutor = newRetryingRecipeExecutor(ic, execution.NewSSHRecipeExecutor(r, i.recipeExecutor), i.status)
+
+	return i
+}
+
+// InstallOnRemoteHosts runs the install on each of the context's SSH hosts in
+// turn.  A failure on one host does not stop the install on the others; the
+// failures are returned together once all hosts have been attempted.
+func InstallOnRemoteHosts(ic InstallerContext, nrClient *newrelic.NewRelic) error {
+	cfg := remote.SSHConfig{
+		KeyFile: ic.SSHKeyFile,
+	}
+
+	return installOnHosts(ic.SSHHosts, func(t remote.Target) error {
+		r, err := remote.NewSSHRunner(t, cfg)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+
+		return NewRemoteRecipeInstaller(ic, nrClient, r).Install()
+	})
+}
+
+func installOnHosts(hosts []string, install func(remote.Target) error) error {
+	errs := map[string]error{}
+
+	for _, h := range hosts {
+		fmt.Printf("\nInstalling on %s\n", h)
+
+		t, err := remote.ParseTarget(h)
+		if err == nil {
+			err = install(t)
+		}
+
+		if isCanceled(err) {
+			return err
+		}
+
+		if err != nil {
+			log.Errorf("Installation on %s failed: %s", h, err)
+			errs[h] = err
+		}
+	}
+
+	fmt.Printf("\nInstalled on %d of %d hosts.\n", len(hosts)-len(errs), len(hosts))
+	for _, h := range hosts {
+		if err, ok := errs[h]; ok {
+			fmt.Printf("  %s: %s\n", h, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return NewErrRemoteHostsFailed(errs, len(hosts))
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+// +build unit
+
+package install
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/remote"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestInstallOnHosts(t *testing.T) {
+	installed := []string{}
+	err := installOnHosts([]string{"a", "b"}, func(t remote.Target) error {
+		installed = append(installed, t.Host)
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, installed)
+}
+
+func TestInstallOnHosts_FailureIsolated(t *testing.T) {
+	installed := []string{}
+	err := installOnHosts([]string{"a", "bad", "c", "admin@"}, func(t remote.Target) error {
+		if t.Host == "bad" {
+			return errors.New("connection refused")
+		}
+		installed = append(installed, t.Host)
+		return nil
+	})
+
+	var herr ErrRemoteHostsFailed
+	require.True(t, errors.As(err, &herr))
+	require.Equal(t, 4, herr.Total)
+	require.Equal(t, 2, len(herr.Errors))
+	require.Contains(t, herr.Errors, "bad")
+	require.Contains(t, herr.Errors, "admin@")
+	require.Equal(t, []string{"a", "c"}, installed)
+}
+
+func TestInstallOnHosts_Interrupted(t *testing.T) {
+	installed := []string{}
+	err := installOnHosts([]string{"a", "b"}, func(t remote.Target) error {
+		installed = append(installed, t.Host)
+		return types.ErrInterrupt
+	})
+
+	require.Equal(t, types.ErrInterrupt, err)
+	require.Equal(t, []string{"a"}, installed)
+}
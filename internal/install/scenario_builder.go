@@ -1,6 +1,9 @@
 package install
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/sirupsen/logrus"
 	log "github.com/sirupsen/logrus"
 
@@ -33,6 +36,14 @@ var (
 		Canceled,
 		DisplayExplorerLink,
 	}
+	testScenarioDescriptions = map[TestScenario]string{
+		Basic:               "a guided install of the infrastructure agent, logging and a recommended recipe, each validated after a few attempts",
+		LogMatches:          "the basic install with a log file always matched, to exercise the log file prompts",
+		Fail:                "a guided install in which every recipe fails to execute",
+		StitchedPath:        "a targeted install of the recipes given with --recipe",
+		Canceled:            "an install including a recipe to cancel, to exercise the summary of a canceled install",
+		DisplayExplorerLink: "an install of a recipe with an explorer success link, with debug logging, to display the link to its data",
+	}
 	emptyResults = []nrdb.NRDBResult{
 		map[string]interface{}{
 			"count": 0.0,
@@ -54,15 +65,47 @@ func TestScenarioValues() []string {
 	return v
 }
 
+// Description returns what the scenario exercises.
+func (s TestScenario) Description() string {
+	return testScenarioDescriptions[s]
+}
+
+// ParseTestScenario returns the scenario of the given name, in any case.
+func ParseTestScenario(name string) (TestScenario, error) {
+	for _, s := range TestScenarios {
+		if strings.EqualFold(name, string(s)) {
+			return s, nil
+		}
+	}
+
+	return "", fmt.Errorf("scenario %s is not valid.  Valid values are %s", name, strings.Join(TestScenarioValues(), ","))
+}
+
 type ScenarioBuilder struct {
 	installerContext InstallerContext
+	manifest         *types.DiscoveryManifest
+}
+
+// ScenarioOption configures the scenarios built by a ScenarioBuilder.
+type ScenarioOption func(*ScenarioBuilder)
+
+// WithDiscoveryManifest makes scenarios discover the given manifest instead of
+// the host they run on.
+func WithDiscoveryManifest(m types.DiscoveryManifest) ScenarioOption {
+	return func(b *ScenarioBuilder) {
+		b.manifest = &m
+	}
 }
 
-func NewScenarioBuilder(ic InstallerContext) *ScenarioBuilder {
+func NewScenarioBuilder(ic InstallerContext, opts ...ScenarioOption) *ScenarioBuilder {
 	b := ScenarioBuilder{
 		installerContext: ic,
 	}
 
+	for _, opt := range opts {
+		opt(&b)
+	}
+
 	return &b
 }
 
@@ -85,6 +128,16 @@ func (b *ScenarioBuilder) BuildScenario(s TestScenario) *RecipeInstaller {
 	return nil
 }
 
+// discoverer returns a discoverer of the scenario's manifest, if one was
+// given, or else of the host.
+func (b *ScenarioBuilder) discoverer(pf discovery.ProcessFilterer) discovery.Discoverer {
+	if b.manifest != nil {
+		return discovery.NewMockDiscovererWithManifest(*b.manifest)
+	}
+
+	return discovery.NewPSUtilDiscoverer(pf)
+}
+
 func (b *ScenarioBuilder) Basic() *RecipeInstaller {
 
 	// mock implementations
@@ -101,7 +154,7 @@ func (b *ScenarioBuilder) Basic() *RecipeInstaller {
 
 	pf := discovery.NewRegexProcessFilterer(rf)
 	ff := recipes.NewRecipeFileFetcher()
-	d := discovery.NewPSUtilDiscoverer(pf)
+	d := b.discoverer(pf)
 	gff := discovery.NewGlobFileFilterer()
 	re := execution.NewGoTaskRecipeExecutor()
 	p := ux.NewPromptUIPrompter()
@@ -140,7 +193,7 @@ func (b *ScenarioBuilder) Fail() *RecipeInstaller {
 
 	pf := discovery.NewRegexProcessFilterer(rf)
 	ff := recipes.NewRecipeFileFetcher()
-	d := discovery.NewPSUtilDiscoverer(pf)
+	d := b.discoverer(pf)
 	gff := discovery.NewGlobFileFilterer()
 	re := execution.NewMockFailingRecipeExecutor()
 	p := ux.NewPromptUIPrompter()
@@ -180,7 +233,7 @@ func (b *ScenarioBuilder) LogMatches() *RecipeInstaller {
 
 	pf := discovery.NewRegexProcessFilterer(rf)
 	ff := recipes.NewRecipeFileFetcher()
-	d := discovery.NewPSUtilDiscoverer(pf)
+	d := b.discoverer(pf)
 	re := execution.NewGoTaskRecipeExecutor()
 	p := ux.NewPromptUIPrompter()
 	pi := ux.NewPlainProgress()
@@ -224,7 +277,7 @@ func (b *ScenarioBuilder) StitchedPath() *RecipeInstaller {
 
 	pf := discovery.NewRegexProcessFilterer(rf)
 	ff := recipes.NewRecipeFileFetcher()
-	d := discovery.NewPSUtilDiscoverer(pf)
+	d := b.discoverer(pf)
 	gff := discovery.NewGlobFileFilterer()
 	re := execution.NewGoTaskRecipeExecutor()
 	p := ux.NewPromptUIPrompter()
@@ -262,7 +315,7 @@ func (b *ScenarioBuilder) CanceledInstall() *RecipeInstaller {
 
 	pf := discovery.NewRegexProcessFilterer(rf)
 	ff := recipes.NewRecipeFileFetcher()
-	d := discovery.NewPSUtilDiscoverer(pf)
+	d := b.discoverer(pf)
 	gff := discovery.NewGlobFileFilterer()
 	re := execution.NewGoTaskRecipeExecutor()
 	p := ux.NewPromptUIPrompter()
@@ -302,7 +355,7 @@ func (b *ScenarioBuilder) DisplayExplorerLink() *RecipeInstaller {
 
 	pf := discovery.NewRegexProcessFilterer(rf)
 	ff := recipes.NewRecipeFileFetcher()
-	d := discovery.NewPSUtilDiscoverer(pf)
+	d := b.discoverer(pf)
 	gff := discovery.NewGlobFileFilterer()
 	re := execution.NewGoTaskRecipeExecutor()
 	p := ux.NewPromptUIPrompter()
@@ -0,0 +1,87 @@
+// +build unit
+
+package install
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/discovery"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestScenarioBuilder_WithDiscoveryManifest(t *testing.T) {
+	m := types.DiscoveryManifest{
+		Hostname:        "scenario-host",
+		OS:              "linux",
+		Platform:        "ubuntu",
+		PlatformFamily:  "debian",
+		PlatformVersion: "20.04",
+	}
+	b := NewScenarioBuilder(InstallerContext{}, WithDiscoveryManifest(m))
+
+	for _, s := range TestScenarios {
+		i := b.BuildScenario(s)
+		require.NotNil(t, i)
+
+		discovered, err := i.discoverer.Discover(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, m, *discovered, "scenario %s", s)
+	}
+}
+
+func TestScenarioBuilder_DiscoversHostByDefault(t *testing.T) {
+	i := NewScenarioBuilder(InstallerContext{}).Basic()
+
+	_, ok := i.discoverer.(*discovery.PSUtilDiscoverer)
+	require.True(t, ok)
+}
+
+func TestLoadDiscoveryManifest(t *testing.T) {
+	f, err := ioutil.TempFile("", "manifest-*.json")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString(`{
+  "hostname": "scenario-host",
+  "os": "linux",
+  "platform": "ubuntu",
+  "processes": [{"command": "mysqld", "Process": {"pid": 42}, "MatchingPattern": "mysql"}]
+}`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	m, err := loadDiscoveryManifest(f.Name())
+	require.NoError(t, err)
+	require.Equal(t, "scenario-host", m.Hostname)
+	require.Equal(t, "ubuntu", m.Platform)
+	require.Len(t, m.Processes, 1)
+	require.Equal(t, "mysqld", m.Processes[0].Command)
+	require.Equal(t, "mysql", m.Processes[0].MatchingPattern)
+}
+
+func TestScenarioBuilder_ScenariosListed(t *testing.T) {
+	require.Len(t, testScenarioDescriptions, len(TestScenarios))
+
+	listed := listScenarios()
+	require.Len(t, listed, len(TestScenarios))
+
+	b := NewScenarioBuilder(InstallerContext{})
+	for n, s := range TestScenarios {
+		require.NotEmpty(t, s.Description(), "scenario %s", s)
+		require.NotNil(t, b.BuildScenario(s), "scenario %s", s)
+		require.Equal(t, listedScenario{Name: string(s), Description: s.Description()}, listed[n])
+
+		parsed, err := ParseTestScenario(strings.ToLower(string(s)))
+		require.NoError(t, err)
+		require.Equal(t, s, parsed)
+	}
+
+	_, err := ParseTestScenario("unknown")
+	require.Error(t, err)
+}
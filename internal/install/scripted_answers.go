@@ -0,0 +1,38 @@
+package install
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/ux"
+)
+
+// newPrompter returns the prompter for the given context: one answering from
+// the answers file when given, and otherwise one asking the user.  Should the
+// answers fail to load, every prompt fails rather than waiting on the user.
+func newPrompter(ic InstallerContext) ux.Prompter {
+	if ic.AnswersFile != "" {
+		answers, err := ux.LoadScriptedAnswers(ic.AnswersFile)
+		if err != nil {
+			log.Warnf("The answers file could not be loaded: %s", err)
+		}
+
+		return ux.NewScriptedPrompter(answers)
+	}
+
+	p := ux.NewPromptUIPrompter()
+	p.Timeout = ic.PromptTimeout
+	p.ErrorOnTimeout = ic.PromptTimeoutFails
+
+	return p
+}
+
+// assertAnswersFileIsValid ensures the answers file, when given, can be
+// loaded.
+func assertAnswersFileIsValid(ic InstallerContext) error {
+	if ic.AnswersFile == "" {
+		return nil
+	}
+
+	_, err := ux.LoadScriptedAnswers(ic.AnswersFile)
+	return err
+}
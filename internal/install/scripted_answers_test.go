@@ -0,0 +1,99 @@
+// +build unit
+
+package install
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/ux"
+	"github.com/newrelic/newrelic-cli/internal/install/validation"
+)
+
+func TestInstall_ScriptedAnswers(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{
+		{Name: "mysql-open-source-integration", DisplayName: "MySQL", ValidationNRQL: "testNrql"},
+		{Name: "redis-open-source-integration", DisplayName: "Redis", ValidationNRQL: "testNrql"},
+	}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{Name: types.InfraAgentRecipeName, DisplayName: types.InfraAgentRecipeName, ValidationNRQL: "testNrql"},
+	}
+	v = validation.NewMockRecipeValidator()
+
+	sp := ux.NewScriptedPrompter([]ux.ScriptedAnswer{
+		{Selected: []string{"Redis"}},
+	})
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, sp, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.NoError(t, err)
+	require.Equal(t, 2, statusReporters[0].(*execution.MockStatusReporter).RecipeInstalledCallCount)
+
+	installed := []string{}
+	for _, s := range status.Statuses {
+		if s.Status == execution.RecipeStatusTypes.INSTALLED {
+			installed = append(installed, s.Name)
+		}
+	}
+	require.ElementsMatch(t, []string{types.InfraAgentRecipeName, "redis-open-source-integration"}, installed)
+}
+
+func TestInstall_ScriptedAnswersRunOut(t *testing.T) {
+	ic := InstallerContext{
+		SkipLoggingInstall: true,
+	}
+	statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+	status = execution.NewInstallStatus(statusReporters, execution.NewConcreteSuccessLinkGenerator())
+	f = recipes.NewMockRecipeFetcher()
+	f.FetchRecommendationsVal = []types.OpenInstallationRecipe{
+		{Name: "mysql-open-source-integration", DisplayName: "MySQL", ValidationNRQL: "testNrql"},
+	}
+	f.FetchRecipeVals = []types.OpenInstallationRecipe{
+		{Name: types.InfraAgentRecipeName, DisplayName: types.InfraAgentRecipeName, ValidationNRQL: "testNrql"},
+	}
+	v = validation.NewMockRecipeValidator()
+
+	sp := ux.NewScriptedPrompter(nil)
+
+	i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, sp, pi, lkf, ss, rd, cr}
+	err := i.Install()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no scripted answer is left")
+}
+
+func TestNewPrompter(t *testing.T) {
+	_, ok := newPrompter(InstallerContext{}).(*ux.PromptUIPrompter)
+	require.True(t, ok)
+
+	tmp, err := ioutil.TempDir("", "newrelic")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	path := filepath.Join(tmp, "answers.yml")
+	require.NoError(t, ioutil.WriteFile(path, []byte("- yes: true\n"), 0600))
+
+	ic := InstallerContext{AnswersFile: path}
+	require.NoError(t, assertAnswersFileIsValid(ic))
+
+	sp, ok := newPrompter(ic).(*ux.ScriptedPrompter)
+	require.True(t, ok)
+
+	yes, err := sp.PromptYesNo("Continue?")
+	require.NoError(t, err)
+	require.True(t, yes)
+
+	require.Error(t, assertAnswersFileIsValid(InstallerContext{AnswersFile: filepath.Join(tmp, "missing.yml")}))
+}
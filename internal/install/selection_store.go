@@ -0,0 +1,107 @@
+package install
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/newrelic/newrelic-cli/internal/utils"
+)
+
+const recipeSelectionsFileName = "recipe-selections.json"
+
+// RecipeSelection records the recipes a user chose, and declined, during a
+// guided install.
+type RecipeSelection struct {
+	Selected []string `json:"selected"`
+	Declined []string `json:"declined"`
+}
+
+// SelectionStore persists recipe selections across runs, keyed by a host
+// fingerprint.
+type SelectionStore interface {
+	Load(fingerprint string) (*RecipeSelection, error)
+	Save(fingerprint string, selection RecipeSelection) error
+	Reset() error
+}
+
+// FileSelectionStore is an implementation of the SelectionStore interface that
+// persists selections to a JSON file in the CLI config directory.
+type FileSelectionStore struct {
+	path string
+}
+
+// NewFileSelectionStore returns a new instance of FileSelectionStore.
+func NewFileSelectionStore(configDir string) *FileSelectionStore {
+	s := FileSelectionStore{
+		path: filepath.Join(configDir, recipeSelectionsFileName),
+	}
+
+	return &s
+}
+
+// Load returns the selection saved for the given fingerprint, or nil if none
+// has been saved.
+func (s *FileSelectionStore) Load(fingerprint string) (*RecipeSelection, error) {
+	selections, err := s.readAll()
+	if err != nil {
+		return nil, err
+	}
+
+	selection, ok := selections[fingerprint]
+	if !ok {
+		return nil, nil
+	}
+
+	return &selection, nil
+}
+
+// Save stores the selection for the given fingerprint, replacing any previous one.
+func (s *FileSelectionStore) Save(fingerprint string, selection RecipeSelection) error {
+	selections, err := s.readAll()
+	if err != nil {
+		return err
+	}
+
+	selections[fingerprint] = selection
+
+	data, err := json.MarshalIndent(selections, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0750); err != nil {
+		return err
+	}
+
+	return utils.WriteFileAtomic(s.path, data, 0600)
+}
+
+// Reset removes all saved selections.
+func (s *FileSelectionStore) Reset() error {
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *FileSelectionStore) readAll() (map[string]RecipeSelection, error) {
+	selections := map[string]RecipeSelection{}
+
+	data, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return selections, nil
+		}
+
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &selections); err != nil {
+		return nil, err
+	}
+
+	return selections, nil
+}
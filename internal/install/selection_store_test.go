@@ -0,0 +1,57 @@
+// +build unit
+
+package install
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSelectionStore_SaveAndLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", t.Name())
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s := NewFileSelectionStore(dir)
+
+	selection, err := s.Load("testFingerprint")
+	require.NoError(t, err)
+	require.Nil(t, selection)
+
+	err = s.Save("testFingerprint", RecipeSelection{
+		Selected: []string{"selected-recipe"},
+		Declined: []string{"declined-recipe"},
+	})
+	require.NoError(t, err)
+
+	selection, err = s.Load("testFingerprint")
+	require.NoError(t, err)
+	require.NotNil(t, selection)
+	require.Equal(t, []string{"selected-recipe"}, selection.Selected)
+	require.Equal(t, []string{"declined-recipe"}, selection.Declined)
+
+	selection, err = s.Load("otherFingerprint")
+	require.NoError(t, err)
+	require.Nil(t, selection)
+}
+
+func TestFileSelectionStore_Reset(t *testing.T) {
+	dir, err := ioutil.TempDir("", t.Name())
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s := NewFileSelectionStore(dir)
+
+	require.NoError(t, s.Reset())
+
+	err = s.Save("testFingerprint", RecipeSelection{Selected: []string{"selected-recipe"}})
+	require.NoError(t, err)
+	require.NoError(t, s.Reset())
+
+	selection, err := s.Load("testFingerprint")
+	require.NoError(t, err)
+	require.Nil(t, selection)
+}
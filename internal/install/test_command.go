@@ -1,25 +1,60 @@
 package install
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/output"
 )
 
 var (
-	testScenario string
+	testScenario      string
+	testManifestFile  string
+	testListScenarios bool
 )
 
+// listedScenario is a test scenario as listed by --list-scenarios.
+type listedScenario struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
 // TestCommand represents the test command for the install command.
 var TestCommand = &cobra.Command{
-	Use:    "installTest",
-	Short:  "Run a UX test of the install command.",
-	Hidden: true,
+	Use:   "installTest [scenario]",
+	Short: "Run a UX test of the install command.",
+	Long: `Run a UX test of the install command
+
+The install is run against mock recipes, executors and validators arranged by
+the chosen scenario, given as an argument or with --testScenario, to verify its
+user experience without installing anything.  List the scenarios and what each
+exercises with --list-scenarios.
+`,
+	Example: "newrelic installTest --list-scenarios\nnewrelic installTest fail",
+	Args:    cobra.MaximumNArgs(1),
+	Hidden:  true,
 	Run: func(cmd *cobra.Command, args []string) {
+		if testListScenarios {
+			output.Text(listScenarios())
+			return
+		}
+
+		name := testScenario
+		if len(args) > 0 {
+			name = args[0]
+		}
+
+		scenario, err := ParseTestScenario(name)
+		if err != nil {
+			log.Fatal(err)
+		}
+
 		ic := InstallerContext{
 			RecipePaths:        recipePaths,
 			RecipeNames:        recipeNames,
@@ -30,21 +65,28 @@ var TestCommand = &cobra.Command{
 			AssumeYes:          assumeYes,
 		}
 
-		b := NewScenarioBuilder(ic)
-		i := b.BuildScenario(TestScenario(testScenario))
-
-		if i == nil {
-			log.Fatalf("Scenario %s is not valid.  Valid values are %s", testScenario, strings.Join(TestScenarioValues(), ","))
+		opts := []ScenarioOption{}
+		if testManifestFile != "" {
+			m, err := loadDiscoveryManifest(testManifestFile)
+			if err != nil {
+				log.Fatal(err)
+			}
+			opts = append(opts, WithDiscoveryManifest(*m))
 		}
 
+		b := NewScenarioBuilder(ic, opts...)
+		i := b.BuildScenario(scenario)
+
 		if trace {
 			log.SetLevel(log.TraceLevel)
 		} else if debug {
 			log.SetLevel(log.DebugLevel)
 		}
 
+		fmt.Printf("Running scenario %s: %s\n", scenario, scenario.Description())
+
 		if err := i.Install(); err != nil {
-			if err == types.ErrInterrupt {
+			if isCanceled(err) {
 				return
 			}
 
@@ -53,6 +95,51 @@ var TestCommand = &cobra.Command{
 	},
 }
 
+// listScenarios returns the test scenarios with their descriptions.
+func listScenarios() []listedScenario {
+	listed := make([]listedScenario, len(TestScenarios))
+	for n, s := range TestScenarios {
+		listed[n] = listedScenario{
+			Name:        string(s),
+			Description: s.Description(),
+		}
+	}
+
+	return listed
+}
+
+// loadDiscoveryManifest reads a discovery manifest from a JSON file, as written
+// to manifest.json in the output directory of an install.
+func loadDiscoveryManifest(path string) (*types.DiscoveryManifest, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read discovery manifest: %s", err)
+	}
+
+	// Processes are read without their underlying process, which cannot be
+	// decoded.
+	var manifest struct {
+		types.DiscoveryManifest
+		Processes []struct {
+			Command         string `json:"command"`
+			MatchingPattern string
+		} `json:"processes"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("could not parse discovery manifest %s: %s", path, err)
+	}
+
+	m := manifest.DiscoveryManifest
+	for _, p := range manifest.Processes {
+		m.AddMatchedProcess(types.MatchedProcess{
+			Command:         p.Command,
+			MatchingPattern: p.MatchingPattern,
+		})
+	}
+
+	return &m, nil
+}
+
 func init() {
 	TestCommand.Flags().StringSliceVarP(&recipePaths, "recipePath", "c", []string{}, "the path to a recipe file to install")
 	TestCommand.Flags().StringSliceVarP(&recipeNames, "recipe", "n", []string{}, "the name of a recipe to install")
@@ -60,7 +147,9 @@ func init() {
 	TestCommand.Flags().BoolVarP(&skipIntegrations, "skipIntegrations", "r", false, "skips installation of recommended New Relic integrations")
 	TestCommand.Flags().BoolVarP(&skipLoggingInstall, "skipLoggingInstall", "l", false, "skips installation of New Relic Logging")
 	TestCommand.Flags().BoolVarP(&skipApm, "skipApm", "a", false, "skips installation for APM")
-	TestCommand.Flags().StringVarP(&testScenario, "testScenario", "s", string(Basic), fmt.Sprintf("test scenario to run, defaults to BASIC.  Valid values are %s", strings.Join(TestScenarioValues(), ",")))
+	TestCommand.Flags().StringVarP(&testScenario, "testScenario", "s", string(Basic), fmt.Sprintf("test scenario to run, in any case, defaults to BASIC.  Valid values are %s", strings.Join(TestScenarioValues(), ",")))
+	TestCommand.Flags().BoolVar(&testListScenarios, "list-scenarios", false, "list the test scenarios and what each exercises")
+	TestCommand.Flags().StringVar(&testManifestFile, "manifest", "", "a JSON discovery manifest to use instead of discovering the host")
 	TestCommand.Flags().BoolVar(&debug, "debug", false, "debug level logging")
 	TestCommand.Flags().BoolVar(&trace, "trace", false, "trace level logging")
 	TestCommand.Flags().BoolVarP(&assumeYes, "assumeYes", "y", false, "use \"yes\" for all questions during install")
@@ -1,6 +1,9 @@
 package types
 
 import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
@@ -16,8 +19,93 @@ type DiscoveryManifest struct {
 	PlatformFamily  string           `json:"platformFamily"`
 	PlatformVersion string           `json:"platformVersion"`
 	Processes       []MatchedProcess `json:"processes"`
+	ListeningPorts  []int            `json:"listeningPorts,omitempty"`
+	// Container runtimes running on the host
+	ContainerRuntimes []ContainerRuntime `json:"containerRuntimes,omitempty"`
+	// Filesystems holding the directories recipes install to
+	Filesystems []Filesystem `json:"filesystems,omitempty"`
+	// Monitoring agents of other vendors running on the host
+	MonitoringAgents []MonitoringAgent `json:"monitoringAgents,omitempty"`
+	// The package manager recipes should install packages with
+	PackageManager string `json:"packageManager,omitempty"`
+	// Every package manager available on the host
+	PackageManagers []string `json:"packageManagers,omitempty"`
+	// The kind of container the installer runs in, empty on a bare host
+	Container string `json:"container,omitempty"`
 }
 
+// MonitoringAgent is a monitoring or APM agent of another vendor discovered
+// running on the host.
+type MonitoringAgent struct {
+	Name    string `json:"name"`
+	Vendor  string `json:"vendor"`
+	Command string `json:"command"`
+	PID     int32  `json:"pid"`
+}
+
+// Filesystem is the space available on the filesystem holding a path.
+type Filesystem struct {
+	Path   string `json:"path"`
+	FreeMB uint64 `json:"freeMB"`
+}
+
+// ContainerRuntime is a container runtime discovered on the host.
+type ContainerRuntime struct {
+	Name   string `json:"name"`
+	Socket string `json:"socket"`
+	// Directory the runtime writes container logs to, if found
+	LogDir string `json:"logDir,omitempty"`
+}
+
+// ContainerRuntimeTypes are the names of the container runtimes discovered.
+var ContainerRuntimeTypes = struct {
+	DOCKER     string
+	CONTAINERD string
+	CRIO       string
+}{
+	DOCKER:     "docker",
+	CONTAINERD: "containerd",
+	CRIO:       "cri-o",
+}
+
+// ContainerTypes are the kinds of container the installer is detected running
+// in.  GENERIC is a container of unknown kind.
+var ContainerTypes = struct {
+	DOCKER     string
+	PODMAN     string
+	KUBERNETES string
+	CONTAINERD string
+	LXC        string
+	GENERIC    string
+}{
+	DOCKER:     "docker",
+	PODMAN:     "podman",
+	KUBERNETES: "kubernetes",
+	CONTAINERD: "containerd",
+	LXC:        "lxc",
+	GENERIC:    "container",
+}
+
+// PackageManagerTypes are the names of the package managers discovered.
+var PackageManagerTypes = struct {
+	APT    string
+	DNF    string
+	YUM    string
+	ZYPPER string
+	APK    string
+}{
+	APT:    "apt",
+	DNF:    "dnf",
+	YUM:    "yum",
+	ZYPPER: "zypper",
+	APK:    "apk",
+}
+
+// containerLogRootVar is replaced in log match patterns by the log directory of
+// every discovered container runtime.  The log directory of a single runtime
+// is referenced as ${DOCKER_LOG_ROOT}, ${CONTAINERD_LOG_ROOT} or ${CRIO_LOG_ROOT}.
+const containerLogRootVar = "${CONTAINER_LOG_ROOT}"
+
 // GenericProcess is an abstracted representation of a process.
 type GenericProcess interface {
 	Name() (string, error)
@@ -31,11 +119,188 @@ type MatchedProcess struct {
 	MatchingPattern string
 }
 
+// matchedProcessJSON is the serialized form of a MatchedProcess, in which the
+// process is recorded by value.
+type matchedProcessJSON struct {
+	Command         string `json:"command"`
+	MatchingPattern string `json:"matchingPattern,omitempty"`
+	PID             int32  `json:"pid,omitempty"`
+	Name            string `json:"name,omitempty"`
+	Cmdline         string `json:"cmdline,omitempty"`
+}
+
+// MarshalJSON records the process of the match by value, so a serialized
+// manifest can be read back.
+func (p MatchedProcess) MarshalJSON() ([]byte, error) {
+	v := matchedProcessJSON{
+		Command:         p.Command,
+		MatchingPattern: p.MatchingPattern,
+	}
+
+	if p.Process != nil {
+		v.PID = p.Process.PID()
+		v.Name, _ = p.Process.Name()
+		v.Cmdline, _ = p.Process.Cmdline()
+	}
+
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON restores a match serialized by MarshalJSON.
+func (p *MatchedProcess) UnmarshalJSON(data []byte) error {
+	var v matchedProcessJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	p.Command = v.Command
+	p.MatchingPattern = v.MatchingPattern
+	p.Process = savedProcess{
+		pid:     v.PID,
+		name:    v.Name,
+		cmdline: v.Cmdline,
+	}
+
+	return nil
+}
+
+// savedProcess is a process read back from a serialized manifest.
+type savedProcess struct {
+	cmdline string
+	name    string
+	pid     int32
+}
+
+func (p savedProcess) Name() (string, error) {
+	return p.name, nil
+}
+
+func (p savedProcess) Cmdline() (string, error) {
+	return p.cmdline, nil
+}
+
+func (p savedProcess) PID() int32 {
+	return p.pid
+}
+
+// HasAnyPackageManager returns whether any of the named package managers was
+// found on the host.  It returns true when package managers were not
+// discovered, such as for a manifest saved before they were.
+func (d *DiscoveryManifest) HasAnyPackageManager(names []string) bool {
+	if len(names) == 0 || d.PackageManagers == nil {
+		return true
+	}
+
+	for _, name := range names {
+		for _, pm := range d.PackageManagers {
+			if strings.EqualFold(name, pm) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// InContainer returns whether the installer runs inside a container rather
+// than on a bare host.
+func (d *DiscoveryManifest) InContainer() bool {
+	return d.Container != ""
+}
+
 // AddMatchedProcess adds a discovered process to the underlying manifest.
 func (d *DiscoveryManifest) AddMatchedProcess(p MatchedProcess) {
 	d.Processes = append(d.Processes, p)
 }
 
+// PortsInUse returns those of the given ports on which the host is listening.
+func (d *DiscoveryManifest) PortsInUse(ports []int) []int {
+	inUse := []int{}
+
+	for _, p := range ports {
+		for _, l := range d.ListeningPorts {
+			if p == l {
+				inUse = append(inUse, p)
+				break
+			}
+		}
+	}
+
+	return inUse
+}
+
+// LowestFreeFilesystem returns the discovered filesystem with the least free
+// space, or false when no filesystems were discovered.
+func (d *DiscoveryManifest) LowestFreeFilesystem() (Filesystem, bool) {
+	if len(d.Filesystems) == 0 {
+		return Filesystem{}, false
+	}
+
+	lowest := d.Filesystems[0]
+	for _, f := range d.Filesystems[1:] {
+		if f.FreeMB < lowest.FreeMB {
+			lowest = f
+		}
+	}
+
+	return lowest, true
+}
+
+// ExpandLogMatches replaces references to container log roots in the file
+// patterns of the given log matches with the log directories of the discovered
+// container runtimes.  A match referencing the roots of all runtimes is repeated
+// for each of them, and a match referencing a root that was not discovered is
+// dropped.
+func (d *DiscoveryManifest) ExpandLogMatches(matches []OpenInstallationLogMatch) []OpenInstallationLogMatch {
+	expanded := []OpenInstallationLogMatch{}
+
+	for _, m := range matches {
+		if !strings.Contains(m.File, "_LOG_ROOT}") {
+			expanded = append(expanded, m)
+			continue
+		}
+
+		seen := map[string]bool{}
+		for _, r := range d.ContainerRuntimes {
+			if r.LogDir == "" {
+				continue
+			}
+
+			file := strings.ReplaceAll(m.File, containerLogRootVar, r.LogDir)
+			file = strings.ReplaceAll(file, runtimeLogRootVar(r.Name), r.LogDir)
+			if file == m.File || strings.Contains(file, "_LOG_ROOT}") || seen[file] {
+				continue
+			}
+
+			seen[file] = true
+			match := m
+			match.File = file
+			expanded = append(expanded, match)
+		}
+	}
+
+	return expanded
+}
+
+func runtimeLogRootVar(name string) string {
+	return "${" + strings.ToUpper(strings.ReplaceAll(name, "-", "")) + "_LOG_ROOT}"
+}
+
+// Fingerprint returns a stable identifier for the host described by the
+// manifest.  Discovered processes are not included since they change between runs.
+func (d *DiscoveryManifest) Fingerprint() string {
+	parts := []string{
+		d.Hostname,
+		d.OS,
+		d.Platform,
+		d.PlatformFamily,
+		d.PlatformVersion,
+		d.KernelArch,
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(parts, "|"))))
+}
+
 func (d *DiscoveryManifest) ConstrainRecipes(allRecipes []OpenInstallationRecipe) []OpenInstallationRecipe {
 	var recipes []OpenInstallationRecipe
 
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -150,3 +151,140 @@ func TestDiscoveryManifest_ConstrainRecipes(t *testing.T) {
 	}
 
 }
+
+func TestDiscoveryManifest_Fingerprint(t *testing.T) {
+	m := DiscoveryManifest{
+		Hostname: "testHost",
+		OS:       "linux",
+		Platform: "ubuntu",
+	}
+
+	require.Equal(t, m.Fingerprint(), m.Fingerprint())
+
+	m.Processes = append(m.Processes, MatchedProcess{Command: "java"})
+	withProcesses := m.Fingerprint()
+	m.Processes = nil
+	require.Equal(t, m.Fingerprint(), withProcesses)
+
+	other := m
+	other.Hostname = "otherHost"
+	require.NotEqual(t, m.Fingerprint(), other.Fingerprint())
+}
+
+func TestDiscoveryManifest_PortsInUse(t *testing.T) {
+	m := DiscoveryManifest{ListeningPorts: []int{22, 80, 8080}}
+
+	require.Equal(t, []int{80, 8080}, m.PortsInUse([]int{80, 443, 8080}))
+	require.Empty(t, m.PortsInUse([]int{443}))
+	require.Empty(t, m.PortsInUse(nil))
+}
+
+func TestDiscoveryManifest_LowestFreeFilesystem(t *testing.T) {
+	m := DiscoveryManifest{}
+	_, ok := m.LowestFreeFilesystem()
+	require.False(t, ok)
+
+	m.Filesystems = []Filesystem{
+		{Path: "/", FreeMB: 2048},
+		{Path: "/opt", FreeMB: 64},
+		{Path: "/var", FreeMB: 512},
+	}
+	lowest, ok := m.LowestFreeFilesystem()
+	require.True(t, ok)
+	require.Equal(t, "/opt", lowest.Path)
+}
+
+func TestDiscoveryManifest_ExpandLogMatches(t *testing.T) {
+	m := DiscoveryManifest{
+		ContainerRuntimes: []ContainerRuntime{
+			{Name: ContainerRuntimeTypes.DOCKER, Socket: "/var/run/docker.sock", LogDir: "/var/lib/docker/containers"},
+			{Name: ContainerRuntimeTypes.CONTAINERD, Socket: "/run/containerd/containerd.sock", LogDir: "/var/log/pods"},
+			{Name: ContainerRuntimeTypes.CRIO, Socket: "/var/run/crio/crio.sock", LogDir: "/var/log/pods"},
+		},
+	}
+
+	matches := []OpenInstallationLogMatch{
+		{Name: "syslog", File: "/var/log/syslog"},
+		{Name: "docker", File: "${DOCKER_LOG_ROOT}/*/*-json.log"},
+		{Name: "containers", File: "${CONTAINER_LOG_ROOT}/*/*.log"},
+		{Name: "cri-o", File: "${CRIO_LOG_ROOT}/*/*.log"},
+	}
+
+	expanded := m.ExpandLogMatches(matches)
+	files := []string{}
+	for _, e := range expanded {
+		files = append(files, e.File)
+	}
+
+	require.Equal(t, []string{
+		"/var/log/syslog",
+		"/var/lib/docker/containers/*/*-json.log",
+		"/var/lib/docker/containers/*/*.log",
+		"/var/log/pods/*/*.log",
+		"/var/log/pods/*/*.log",
+	}, files)
+	require.Equal(t, "containers", expanded[3].Name)
+	require.Equal(t, "cri-o", expanded[4].Name)
+}
+
+func TestDiscoveryManifest_ExpandLogMatches_DropsUndiscoveredRoots(t *testing.T) {
+	m := DiscoveryManifest{
+		ContainerRuntimes: []ContainerRuntime{
+			{Name: ContainerRuntimeTypes.CONTAINERD, Socket: "/run/containerd/containerd.sock"},
+		},
+	}
+
+	expanded := m.ExpandLogMatches([]OpenInstallationLogMatch{
+		{Name: "docker", File: "${DOCKER_LOG_ROOT}/*/*-json.log"},
+		{Name: "containers", File: "${CONTAINER_LOG_ROOT}/*/*.log"},
+	})
+	require.Empty(t, expanded)
+}
+
+func TestDiscoveryManifest_JSONRoundTrip(t *testing.T) {
+	m := DiscoveryManifest{
+		Hostname:        "web-1",
+		OS:              "linux",
+		Platform:        "ubuntu",
+		PlatformVersion: "20.04",
+		KernelArch:      "x86_64",
+	}
+	m.AddMatchedProcess(MatchedProcess{
+		Command:         "mysqld",
+		MatchingPattern: "mysqld",
+		Process: savedProcess{
+			pid:     42,
+			name:    "mysqld",
+			cmdline: "/usr/sbin/mysqld --daemonize",
+		},
+	})
+
+	data, err := json.Marshal(m)
+	require.NoError(t, err)
+
+	var loaded DiscoveryManifest
+	require.NoError(t, json.Unmarshal(data, &loaded))
+
+	require.Equal(t, m.Hostname, loaded.Hostname)
+	require.Equal(t, m.PlatformVersion, loaded.PlatformVersion)
+	require.Len(t, loaded.Processes, 1)
+	require.Equal(t, "mysqld", loaded.Processes[0].Command)
+	require.Equal(t, "mysqld", loaded.Processes[0].MatchingPattern)
+	require.Equal(t, int32(42), loaded.Processes[0].Process.PID())
+
+	cmdline, err := loaded.Processes[0].Process.Cmdline()
+	require.NoError(t, err)
+	require.Equal(t, "/usr/sbin/mysqld --daemonize", cmdline)
+}
+
+func TestHasAnyPackageManager(t *testing.T) {
+	m := DiscoveryManifest{PackageManagers: []string{"dnf", "yum"}}
+
+	require.True(t, m.HasAnyPackageManager(nil))
+	require.True(t, m.HasAnyPackageManager([]string{"apt", "yum"}))
+	require.False(t, m.HasAnyPackageManager([]string{"apt", "zypper"}))
+
+	// Hosts whose package managers were not detected are not restricted.
+	undetected := DiscoveryManifest{}
+	require.True(t, undetected.HasAnyPackageManager([]string{"apt"}))
+}
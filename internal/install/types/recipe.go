@@ -1,8 +1,13 @@
 package types
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"fmt"
+	"strconv"
 	"strings"
+	"text/template"
+	"time"
 
 	"gopkg.in/yaml.v2"
 )
@@ -29,6 +34,10 @@ func (r *OpenInstallationRecipe) UnmarshalYAML(unmarshal func(interface{}) error
 		return err
 	}
 
+	if v, ok := recipe["conflictsWith"]; ok {
+		r.ConflictsWith = interfaceSliceToStringSlice(v.([]interface{}))
+	}
+
 	if v, ok := recipe["dependencies"]; ok {
 		r.Dependencies = interfaceSliceToStringSlice(v.([]interface{}))
 	}
@@ -51,8 +60,13 @@ func (r *OpenInstallationRecipe) UnmarshalYAML(unmarshal func(interface{}) error
 		r.Keywords = interfaceSliceToStringSlice(v.([]interface{}))
 	}
 
-	r.LogMatch = expandLogMatch(recipe)
+	r.LogMatch, err = expandLogMatch(recipe)
+	if err != nil {
+		return err
+	}
+
 	r.Name = toStringByFieldName("name", recipe)
+	r.PlatformDefaults = expandPlatformDefaults(recipe)
 	r.PostInstall = expandPostInstall(recipe)
 	r.PreInstall = expandPreInstall(recipe)
 
@@ -67,17 +81,125 @@ func (r *OpenInstallationRecipe) UnmarshalYAML(unmarshal func(interface{}) error
 
 	r.Repository = toStringByFieldName("repository", recipe)
 
+	if v, ok := recipe["requirement"]; ok {
+		r.Requirement = OpenInstallationRequirement(strings.ToUpper(v.(string)))
+	}
+
+	r.RequiredDiskMB, err = toIntByFieldName("requiredDiskMB", recipe)
+	if err != nil {
+		return err
+	}
+
+	r.RequiresEntitlement = toStringByFieldName("requiresEntitlement", recipe)
+
+	if v, ok := recipe["requiredEnvVars"]; ok {
+		r.RequiredEnvVars = interfaceSliceToStringSlice(v.([]interface{}))
+	}
+
+	if v, ok := recipe["packageManagers"]; ok {
+		r.PackageManagers = interfaceSliceToStringSlice(v.([]interface{}))
+	}
+
+	if v, ok := recipe["requiredPorts"]; ok {
+		r.RequiredPorts = interfaceSliceToIntSlice(v.([]interface{}))
+	}
+
+	if v, ok := recipe["retryExitCodes"]; ok {
+		r.RetryExitCodes = interfaceSliceToIntSlice(v.([]interface{}))
+	}
+
+	r.Retries, err = toIntByFieldName("retries", recipe)
+	if err != nil {
+		return err
+	}
+
+	r.Shell = toStringByFieldName("shell", recipe)
+
 	if v, ok := recipe["stability"]; ok {
 		r.Stability = OpenInstallationStability(v.(string))
 	}
 
+	r.StepChecks, err = expandStepChecks(recipe)
+	if err != nil {
+		return err
+	}
+
+	r.StepConditions = expandStepConditions(recipe)
 	r.SuccessLinkConfig = expandSuccessLinkConfig(recipe)
 
+	r.ValidationInterval, err = toDurationByFieldName("validationInterval", recipe)
+	if err != nil {
+		return err
+	}
+
 	if v, ok := recipe["validationNrql"]; ok {
 		r.ValidationNRQL = NRQL(v.(string))
 	}
 
-	return err
+	r.ValidationTimeout, err = toDurationByFieldName("validationTimeout", recipe)
+	if err != nil {
+		return err
+	}
+
+	r.WorkingDir = toStringByFieldName("workingDir", recipe)
+
+	return nil
+}
+
+func expandStepChecks(recipe map[string]interface{}) ([]OpenInstallationStepCheck, error) {
+	v, ok := recipe["stepChecks"]
+	if !ok {
+		return nil, nil
+	}
+
+	dataIn, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid stepChecks %v: expected a list of step checks", v)
+	}
+
+	dataOut := make([]OpenInstallationStepCheck, len(dataIn))
+	for i, vv := range dataIn {
+		vvv, ok := vv.(map[interface{}]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid stepChecks: expected a step check, got %v", vv)
+		}
+
+		varr := map[string]interface{}{}
+		for k, v := range vvv {
+			varr[fmt.Sprint(k)] = v
+		}
+
+		dataOut[i] = OpenInstallationStepCheck{
+			Check:          toStringByFieldName("check", varr),
+			IdempotencyKey: toStringByFieldName("idempotencyKey", varr),
+			Step:           toStringByFieldName("step", varr),
+		}
+	}
+
+	return dataOut, nil
+}
+
+func expandStepConditions(recipe map[string]interface{}) []OpenInstallationStepCondition {
+	v, ok := recipe["stepConditions"]
+	if !ok {
+		return nil
+	}
+
+	dataIn := v.([]interface{})
+	dataOut := make([]OpenInstallationStepCondition, len(dataIn))
+	for i, vv := range dataIn {
+		varr := map[string]interface{}{}
+		for k, v := range vv.(map[interface{}]interface{}) {
+			varr[k.(string)] = v
+		}
+
+		dataOut[i] = OpenInstallationStepCondition{
+			Step: toStringByFieldName("step", varr),
+			When: toStringByFieldName("when", varr),
+		}
+	}
+
+	return dataOut
 }
 
 func expandSuccessLinkConfig(recipe map[string]interface{}) OpenInstallationSuccessLinkConfig {
@@ -169,6 +291,35 @@ func expandPreInstall(recipe map[string]interface{}) OpenInstallationPreInstallC
 	}
 }
 
+func expandPlatformDefaults(recipe map[string]interface{}) map[string]map[string]string {
+	v, ok := recipe["platformDefaults"]
+	if !ok {
+		return nil
+	}
+
+	platforms, ok := v.(map[interface{}]interface{})
+	if !ok {
+		return nil
+	}
+
+	defaults := map[string]map[string]string{}
+	for platform, vars := range platforms {
+		varsMap, ok := vars.(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+
+		platformVars := map[string]string{}
+		for k, val := range varsMap {
+			platformVars[fmt.Sprintf("%v", k)] = fmt.Sprintf("%v", val)
+		}
+
+		defaults[fmt.Sprintf("%v", platform)] = platformVars
+	}
+
+	return defaults
+}
+
 func expandPostInstall(recipe map[string]interface{}) OpenInstallationPostInstallConfiguration {
 	v, ok := recipe["postInstall"]
 	if !ok {
@@ -220,10 +371,10 @@ func expandInputVars(recipe map[string]interface{}) []OpenInstallationRecipeInpu
 	return varsOut
 }
 
-func expandLogMatch(recipe map[string]interface{}) []OpenInstallationLogMatch {
+func expandLogMatch(recipe map[string]interface{}) ([]OpenInstallationLogMatch, error) {
 	v, ok := recipe["logMatch"]
 	if !ok {
-		return []OpenInstallationLogMatch{}
+		return []OpenInstallationLogMatch{}, nil
 	}
 
 	dataIn := v.([]interface{})
@@ -240,18 +391,34 @@ func expandLogMatch(recipe map[string]interface{}) []OpenInstallationLogMatch {
 	}
 
 	for i, v := range dataz {
+		maxAgeDays, err := toIntByFieldName("maxAgeDays", v)
+		if err != nil {
+			return nil, err
+		}
+
+		maxMatches, err := toIntByFieldName("maxMatches", v)
+		if err != nil {
+			return nil, err
+		}
+
 		vOut := OpenInstallationLogMatch{
 			Attributes: expandLogAttributes(v),
 			File:       toStringByFieldName("file", v),
+			MaxAgeDays: maxAgeDays,
+			MaxMatches: maxMatches,
 			Name:       toStringByFieldName("name", v),
 			Pattern:    toStringByFieldName("pattern", v),
 			Systemd:    toStringByFieldName("systemd", v),
 		}
 
+		if e, ok := v["exclude"]; ok {
+			vOut.Exclude = interfaceSliceToStringSlice(e.([]interface{}))
+		}
+
 		dataOut[i] = vOut
 	}
 
-	return dataOut
+	return dataOut, nil
 }
 
 func expandLogAttributes(data map[string]interface{}) OpenInstallationAttributes {
@@ -283,6 +450,35 @@ func toBoolByFieldName(fieldName string, data map[string]interface{}) bool {
 	return false
 }
 
+func toIntByFieldName(fieldName string, data map[string]interface{}) (int, error) {
+	v, ok := data[fieldName]
+	if !ok {
+		return 0, nil
+	}
+
+	i, ok := v.(int)
+	if !ok {
+		return 0, fmt.Errorf("invalid %s %v: expected an integer", fieldName, v)
+	}
+
+	return i, nil
+}
+
+// toDurationByFieldName parses a duration such as 30s or 10m.
+func toDurationByFieldName(fieldName string, data map[string]interface{}) (time.Duration, error) {
+	v := toStringByFieldName(fieldName, data)
+	if v == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %s: %s", fieldName, v, err)
+	}
+
+	return d, nil
+}
+
 func toStringByFieldName(fieldName string, data map[string]interface{}) string {
 	if v, ok := data[fieldName]; ok {
 		return v.(string)
@@ -321,6 +517,64 @@ func interfaceSliceToStringSlice(slice []interface{}) []string {
 	return out
 }
 
+func interfaceSliceToIntSlice(slice []interface{}) []int {
+	out := []int{}
+
+	for _, v := range slice {
+		switch n := v.(type) {
+		case int:
+			out = append(out, n)
+		case string:
+			if i, err := strconv.Atoi(n); err == nil {
+				out = append(out, i)
+			}
+		}
+	}
+
+	return out
+}
+
+// InstallMessageData is the data available to templated pre- and post-install
+// messages, e.g. {{.Manifest.Hostname}} or {{.Vars.NR_DISCOVERED_LOG_FILES}}.
+// Pre-install messages are shown before variables are prompted for, so only
+// the recipe's platform defaults are available to them.
+type InstallMessageData struct {
+	Manifest DiscoveryManifest
+	Vars     RecipeVars
+}
+
+// RenderPreInstallMessage renders the pre-install message as a Go template.
+// When the template cannot be rendered, the raw message is returned along with
+// the error.
+func (r *OpenInstallationRecipe) RenderPreInstallMessage(data InstallMessageData) (string, error) {
+	return renderInstallMessage(r.PreInstallMessage(), data)
+}
+
+// RenderPostInstallMessage renders the post-install message as a Go template.
+// When the template cannot be rendered, the raw message is returned along with
+// the error.
+func (r *OpenInstallationRecipe) RenderPostInstallMessage(data InstallMessageData) (string, error) {
+	return renderInstallMessage(r.PostInstallMessage(), data)
+}
+
+func renderInstallMessage(msg string, data InstallMessageData) (string, error) {
+	if !strings.Contains(msg, "{{") {
+		return msg, nil
+	}
+
+	t, err := template.New("message").Option("missingkey=zero").Parse(msg)
+	if err != nil {
+		return msg, err
+	}
+
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return msg, err
+	}
+
+	return b.String(), nil
+}
+
 func (r *OpenInstallationRecipe) PostInstallMessage() string {
 	if r.PostInstall.Info != "" {
 		return r.PostInstall.Info
@@ -343,6 +597,69 @@ func (r *OpenInstallationRecipe) SetRecipeVar(key string, value string) {
 	RecipeVariables[key] = value
 }
 
+// PlatformDefaultVars returns the recipe's default variables for the given
+// host, along with the platform keys they were taken from.  Defaults keyed by
+// operating system are overridden by those keyed by platform family, which are
+// in turn overridden by those keyed by platform.
+func (r *OpenInstallationRecipe) PlatformDefaultVars(m DiscoveryManifest) (RecipeVars, []string) {
+	vars := RecipeVars{}
+	applied := []string{}
+
+	for _, key := range []string{m.OS, m.PlatformFamily, m.Platform} {
+		if key == "" {
+			continue
+		}
+
+		for platform, defaults := range r.PlatformDefaults {
+			if !strings.EqualFold(platform, key) {
+				continue
+			}
+
+			for k, v := range defaults {
+				vars[k] = v
+			}
+			applied = append(applied, platform)
+		}
+	}
+
+	return vars, applied
+}
+
+// ConflictsWithRecipe returns true if either recipe declares a conflict with
+// the other.
+func (r *OpenInstallationRecipe) ConflictsWithRecipe(other OpenInstallationRecipe) bool {
+	for _, name := range r.ConflictsWith {
+		if name == other.Name {
+			return true
+		}
+	}
+
+	for _, name := range other.ConflictsWith {
+		if name == r.Name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Checksum returns a digest of the recipe's definition, which changes whenever
+// the recipe is updated.
+func (r *OpenInstallationRecipe) Checksum() string {
+	definition := r.File
+	if definition == "" {
+		definition = r.Install
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(definition)))
+}
+
+// IsRequired returns true if the recipe is essential rather than optional when
+// recommended.
+func (r *OpenInstallationRecipe) IsRequired() bool {
+	return strings.EqualFold(string(r.Requirement), string(OpenInstallationRequirementTypes.REQUIRED))
+}
+
 func (r *OpenInstallationRecipe) IsApm() bool {
 	return r.HasKeyword("apm")
 }
@@ -0,0 +1,330 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestRenderPostInstallMessage(t *testing.T) {
+	r := OpenInstallationRecipe{
+		PostInstall: OpenInstallationPostInstallConfiguration{
+			Info: "Logs from {{.Vars.NR_DISCOVERED_LOG_FILES}} on {{.Manifest.Hostname}} are now forwarded.",
+		},
+	}
+	data := InstallMessageData{
+		Manifest: DiscoveryManifest{Hostname: "testHost"},
+		Vars:     RecipeVars{"NR_DISCOVERED_LOG_FILES": "/var/log/syslog"},
+	}
+
+	msg, err := r.RenderPostInstallMessage(data)
+	require.NoError(t, err)
+	require.Equal(t, "Logs from /var/log/syslog on testHost are now forwarded.", msg)
+}
+
+func TestRenderPreInstallMessage(t *testing.T) {
+	r := OpenInstallationRecipe{
+		PreInstall: OpenInstallationPreInstallConfiguration{
+			Info: "Installing on {{.Manifest.Platform}}{{if .Vars.MISSING}} with {{.Vars.MISSING}}{{end}}.",
+		},
+	}
+
+	msg, err := r.RenderPreInstallMessage(InstallMessageData{Manifest: DiscoveryManifest{Platform: "ubuntu"}})
+	require.NoError(t, err)
+	require.Equal(t, "Installing on ubuntu.", msg)
+}
+
+func TestRenderInstallMessage_Static(t *testing.T) {
+	r := OpenInstallationRecipe{
+		PostInstall: OpenInstallationPostInstallConfiguration{Info: "All done."},
+	}
+
+	msg, err := r.RenderPostInstallMessage(InstallMessageData{})
+	require.NoError(t, err)
+	require.Equal(t, "All done.", msg)
+}
+
+func TestRenderInstallMessage_FallsBackOnError(t *testing.T) {
+	info := "Broken {{.Manifest.Hostname"
+	r := OpenInstallationRecipe{
+		PostInstall: OpenInstallationPostInstallConfiguration{Info: info},
+	}
+
+	msg, err := r.RenderPostInstallMessage(InstallMessageData{})
+	require.Error(t, err)
+	require.Equal(t, info, msg)
+
+	info = "Unknown {{.Manifest.NoSuchField}}"
+	r.PostInstall.Info = info
+
+	msg, err = r.RenderPostInstallMessage(InstallMessageData{})
+	require.Error(t, err)
+	require.Equal(t, info, msg)
+}
+
+func TestConflictsWithRecipe(t *testing.T) {
+	a := OpenInstallationRecipe{Name: "a", ConflictsWith: []string{"b"}}
+	b := OpenInstallationRecipe{Name: "b"}
+	c := OpenInstallationRecipe{Name: "c"}
+
+	require.True(t, a.ConflictsWithRecipe(b))
+	require.True(t, b.ConflictsWithRecipe(a))
+	require.False(t, a.ConflictsWithRecipe(c))
+}
+
+func TestUnmarshalYAML_LogMatchLimits(t *testing.T) {
+	data := `
+name: test
+logMatch:
+  - name: app
+    file: /var/log/app/*.log*
+    exclude:
+      - "*.gz"
+    maxMatches: 5
+    maxAgeDays: 7
+`
+	var r OpenInstallationRecipe
+	require.NoError(t, yaml.Unmarshal([]byte(data), &r))
+	require.Equal(t, 1, len(r.LogMatch))
+	require.Equal(t, []string{"*.gz"}, r.LogMatch[0].Exclude)
+	require.Equal(t, 5, r.LogMatch[0].MaxMatches)
+	require.Equal(t, 7, r.LogMatch[0].MaxAgeDays)
+}
+
+func TestUnmarshalYAML_InvalidLogMatchLimit(t *testing.T) {
+	data := `
+name: test
+logMatch:
+  - name: app
+    file: /var/log/app/*.log*
+    maxMatches: many
+`
+	var r OpenInstallationRecipe
+	err := yaml.Unmarshal([]byte(data), &r)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "maxMatches")
+}
+
+func TestUnmarshalYAML_RequiredPorts(t *testing.T) {
+	data := `
+name: test
+requiredPorts:
+  - 8080
+  - "9090"
+`
+	var r OpenInstallationRecipe
+	require.NoError(t, yaml.Unmarshal([]byte(data), &r))
+	require.Equal(t, []int{8080, 9090}, r.RequiredPorts)
+}
+
+func TestUnmarshalYAML_PackageManagers(t *testing.T) {
+	data := `
+name: test
+packageManagers:
+  - dnf
+  - yum
+`
+	var r OpenInstallationRecipe
+	require.NoError(t, yaml.Unmarshal([]byte(data), &r))
+	require.Equal(t, []string{"dnf", "yum"}, r.PackageManagers)
+}
+
+func TestUnmarshalYAML_RequiredDiskMB(t *testing.T) {
+	data := `
+name: test
+requiredDiskMB: 250
+`
+	var r OpenInstallationRecipe
+	require.NoError(t, yaml.Unmarshal([]byte(data), &r))
+	require.Equal(t, 250, r.RequiredDiskMB)
+}
+
+func TestUnmarshalYAML_RequiredEnvVars(t *testing.T) {
+	data := `
+name: test
+requiredEnvVars:
+  - MYSQL_USER
+  - MYSQL_PASSWORD
+`
+	var r OpenInstallationRecipe
+	require.NoError(t, yaml.Unmarshal([]byte(data), &r))
+	require.Equal(t, []string{"MYSQL_USER", "MYSQL_PASSWORD"}, r.RequiredEnvVars)
+}
+
+func TestUnmarshalYAML_RequiresEntitlement(t *testing.T) {
+	data := `
+name: test
+requiresEntitlement: synthetics.private_locations
+`
+	var r OpenInstallationRecipe
+	require.NoError(t, yaml.Unmarshal([]byte(data), &r))
+	require.Equal(t, "synthetics.private_locations", r.RequiresEntitlement)
+}
+
+func TestUnmarshalYAML_ValidationTiming(t *testing.T) {
+	data := `
+name: test
+validationInterval: 30s
+validationTimeout: 10m
+`
+	var r OpenInstallationRecipe
+	require.NoError(t, yaml.Unmarshal([]byte(data), &r))
+	require.Equal(t, 30*time.Second, r.ValidationInterval)
+	require.Equal(t, 10*time.Minute, r.ValidationTimeout)
+}
+
+func TestUnmarshalYAML_ValidationTimingDefaults(t *testing.T) {
+	var r OpenInstallationRecipe
+	require.NoError(t, yaml.Unmarshal([]byte("name: test"), &r))
+	require.Zero(t, r.ValidationInterval)
+	require.Zero(t, r.ValidationTimeout)
+}
+
+func TestUnmarshalYAML_InvalidValidationTimeout(t *testing.T) {
+	data := `
+name: test
+validationTimeout: soon
+`
+	var r OpenInstallationRecipe
+	err := yaml.Unmarshal([]byte(data), &r)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "validationTimeout")
+}
+
+func TestUnmarshalYAML_Retries(t *testing.T) {
+	data := `
+name: test
+retryExitCodes: [2, 100]
+retries: 3
+`
+	var r OpenInstallationRecipe
+	require.NoError(t, yaml.Unmarshal([]byte(data), &r))
+	require.Equal(t, []int{2, 100}, r.RetryExitCodes)
+	require.Equal(t, 3, r.Retries)
+}
+
+func TestUnmarshalYAML_Requirement(t *testing.T) {
+	data := `
+name: test
+requirement: required
+`
+	var r OpenInstallationRecipe
+	require.NoError(t, yaml.Unmarshal([]byte(data), &r))
+	require.Equal(t, OpenInstallationRequirementTypes.REQUIRED, r.Requirement)
+	require.True(t, r.IsRequired())
+
+	require.False(t, (&OpenInstallationRecipe{}).IsRequired())
+	require.False(t, (&OpenInstallationRecipe{Requirement: OpenInstallationRequirementTypes.OPTIONAL}).IsRequired())
+}
+
+func TestUnmarshalYAML_Shell(t *testing.T) {
+	data := `
+name: test
+shell: bash
+`
+	var r OpenInstallationRecipe
+	require.NoError(t, yaml.Unmarshal([]byte(data), &r))
+	require.Equal(t, "bash", r.Shell)
+}
+
+func TestUnmarshalYAML_PlatformDefaults(t *testing.T) {
+	var r OpenInstallationRecipe
+	err := yaml.Unmarshal([]byte(`
+name: test
+platformDefaults:
+  linux:
+    CONFIG_DIR: /etc/newrelic
+    PORT: 8080
+  windows:
+    CONFIG_DIR: C:\Program Files\New Relic
+`), &r)
+	require.NoError(t, err)
+	require.Equal(t, "/etc/newrelic", r.PlatformDefaults["linux"]["CONFIG_DIR"])
+	require.Equal(t, "8080", r.PlatformDefaults["linux"]["PORT"])
+	require.Equal(t, `C:\Program Files\New Relic`, r.PlatformDefaults["windows"]["CONFIG_DIR"])
+}
+
+func TestPlatformDefaultVars(t *testing.T) {
+	r := OpenInstallationRecipe{
+		PlatformDefaults: map[string]map[string]string{
+			"linux":   {"CONFIG_DIR": "/etc/newrelic", "SERVICE": "systemd"},
+			"debian":  {"SERVICE": "upstart"},
+			"Ubuntu":  {"PACKAGE": "deb"},
+			"windows": {"CONFIG_DIR": `C:\newrelic`},
+		},
+	}
+
+	vars, applied := r.PlatformDefaultVars(DiscoveryManifest{OS: "linux", PlatformFamily: "debian", Platform: "ubuntu"})
+	require.Equal(t, RecipeVars{"CONFIG_DIR": "/etc/newrelic", "SERVICE": "upstart", "PACKAGE": "deb"}, vars)
+	require.Equal(t, []string{"linux", "debian", "Ubuntu"}, applied)
+
+	vars, applied = r.PlatformDefaultVars(DiscoveryManifest{OS: "windows"})
+	require.Equal(t, RecipeVars{"CONFIG_DIR": `C:\newrelic`}, vars)
+	require.Equal(t, []string{"windows"}, applied)
+
+	vars, applied = r.PlatformDefaultVars(DiscoveryManifest{OS: "darwin"})
+	require.Empty(t, vars)
+	require.Empty(t, applied)
+}
+
+func TestUnmarshalYAML_StepChecks(t *testing.T) {
+	var r OpenInstallationRecipe
+	err := yaml.Unmarshal([]byte(`
+name: test
+stepChecks:
+  - step: configure
+    check: test -f /etc/newrelic-infra.yml
+  - step: install
+    idempotencyKey: install-v1
+`), &r)
+	require.NoError(t, err)
+	require.Equal(t, []OpenInstallationStepCheck{
+		{Step: "configure", Check: "test -f /etc/newrelic-infra.yml"},
+		{Step: "install", IdempotencyKey: "install-v1"},
+	}, r.StepChecks)
+}
+
+func TestUnmarshalYAML_InvalidStepChecks(t *testing.T) {
+	for _, data := range []string{
+		"name: test\nstepChecks: download\n",
+		"name: test\nstepChecks:\n  - download\n",
+	} {
+		var r OpenInstallationRecipe
+		err := yaml.Unmarshal([]byte(data), &r)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "stepChecks")
+	}
+}
+
+func TestUnmarshalYAML_StepConditions(t *testing.T) {
+	var r OpenInstallationRecipe
+	err := yaml.Unmarshal([]byte(`
+name: test
+stepConditions:
+  - step: install_apt
+    when: packageManager == "apt"
+  - step: install_yum
+    when: "'yum' in packageManagers && !isContainer"
+`), &r)
+	require.NoError(t, err)
+	require.Equal(t, []OpenInstallationStepCondition{
+		{Step: "install_apt", When: `packageManager == "apt"`},
+		{Step: "install_yum", When: "'yum' in packageManagers && !isContainer"},
+	}, r.StepConditions)
+}
+
+func TestChecksum(t *testing.T) {
+	r := OpenInstallationRecipe{Name: "test", File: "name: test\n"}
+	same := OpenInstallationRecipe{Name: "other", File: "name: test\n"}
+	updated := OpenInstallationRecipe{Name: "test", File: "name: test\nretries: 2\n"}
+
+	require.Len(t, r.Checksum(), 64)
+	require.Equal(t, r.Checksum(), same.Checksum())
+	require.NotEqual(t, r.Checksum(), updated.Checksum())
+
+	empty := OpenInstallationRecipe{}
+	install := OpenInstallationRecipe{Name: "test", Install: "version: '3'"}
+	require.NotEqual(t, empty.Checksum(), install.Checksum())
+}
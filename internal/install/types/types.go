@@ -1,6 +1,8 @@
 // Code generated by tutone: DO NOT EDIT
 package types
 
+import "time"
+
 // OpenInstallationCategory - Categorization of a Quickstart
 type OpenInstallationCategory string
 
@@ -104,6 +106,21 @@ var OpenInstallationStabilityTypes = struct {
 	STABLE: "STABLE",
 }
 
+// OpenInstallationRequirement - How important installing a recommended recipe is
+type OpenInstallationRequirement string
+
+var OpenInstallationRequirementTypes = struct {
+	// Recipe is essential to the instrumentation of the host
+	REQUIRED OpenInstallationRequirement
+	// Recipe is recommended, but optional
+	OPTIONAL OpenInstallationRequirement
+}{
+	// Recipe is essential to the instrumentation of the host
+	REQUIRED: "REQUIRED",
+	// Recipe is recommended, but optional
+	OPTIONAL: "OPTIONAL",
+}
+
 // OpenInstallationSuccessLinkType - Success link type
 type OpenInstallationSuccessLinkType string
 
@@ -160,8 +177,14 @@ type OpenInstallationAttributes struct {
 type OpenInstallationLogMatch struct {
 	// List of custom attributes, as key-value pairs, that can be used to send additional data with the logs which you can then query.
 	Attributes OpenInstallationAttributes `json:"attributes,omitempty" yaml:"attributes,omitempty"`
+	// Glob patterns of files to exclude from the matched files.
+	Exclude []string `json:"exclude,omitempty" yaml:"exclude,omitempty"`
 	// Path to the log file or files.
 	File string `json:"file,omitempty" yaml:"file,omitempty"`
+	// Files last modified more than this many days ago are not matched.
+	MaxAgeDays int `json:"maxAgeDays,omitempty" yaml:"maxAgeDays,omitempty"`
+	// Maximum number of files matched by the pattern.
+	MaxMatches int `json:"maxMatches,omitempty" yaml:"maxMatches,omitempty"`
 	// Name of the log or logs.
 	Name string `json:"name" yaml:"name"`
 	// Regular expression for filtering records.
@@ -170,6 +193,24 @@ type OpenInstallationLogMatch struct {
 	Systemd string `json:"systemd,omitempty" yaml:"systemd,omitempty"`
 }
 
+// OpenInstallationStepCheck - Marks a step of the install as already complete, so it is skipped
+type OpenInstallationStepCheck struct {
+	// Command that succeeds when the step is already complete.
+	Check string `json:"check,omitempty" yaml:"check,omitempty"`
+	// Key recorded on the host once the step succeeds. The step is skipped while the key is recorded.
+	IdempotencyKey string `json:"idempotencyKey,omitempty" yaml:"idempotencyKey,omitempty"`
+	// Name of the task in the install task file.
+	Step string `json:"step" yaml:"step"`
+}
+
+// OpenInstallationStepCondition - Runs a step of the install only when a condition on the discovered host holds
+type OpenInstallationStepCondition struct {
+	// Name of the task in the install task file.
+	Step string `json:"step" yaml:"step"`
+	// Expression over the discovery facts and recipe variables. The step is skipped when it is false.
+	When string `json:"when" yaml:"when"`
+}
+
 // OpenInstallationPostInstallConfiguration - Optional post-install configuration items
 type OpenInstallationPostInstallConfiguration struct {
 	// Message/Docs notice displayed to user after running the recipe
@@ -204,6 +245,8 @@ type OpenInstallationQuickstartsFilter struct {
 
 // OpenInstallationRecipe - Installation instructions and definition of an instrumentation integration
 type OpenInstallationRecipe struct {
+	// Named list of recipes that cannot be installed alongside this recipe
+	ConflictsWith []string `json:"conflictsWith,omitempty" yaml:"conflictsWith,omitempty"`
 	// Named list of dependencies for this recipe
 	Dependencies []string `json:"dependencies" yaml:"dependencies"`
 	// Description of the recipe
@@ -226,6 +269,10 @@ type OpenInstallationRecipe struct {
 	LogMatch []OpenInstallationLogMatch `json:"logMatch" yaml:"logMatch"`
 	// Short unique handle for the name of the integration
 	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Default variable values keyed by operating system, platform family or platform
+	PlatformDefaults map[string]map[string]string `json:"platformDefaults,omitempty" yaml:"platformDefaults,omitempty"`
+	// Package managers the install can use, one of which must be available on the host
+	PackageManagers []string `json:"packageManagers,omitempty" yaml:"packageManagers,omitempty"`
 	// Object representing optional post-install configuration items
 	PostInstall OpenInstallationPostInstallConfiguration `json:"postInstall,omitempty" yaml:"postInstall,omitempty"`
 	// Object representing optional pre-install configuration items
@@ -236,13 +283,43 @@ type OpenInstallationRecipe struct {
 	Quickstarts OpenInstallationQuickstartsFilter `json:"quickstarts,omitempty" yaml:"quickstarts,omitempty"`
 	// Github repository url
 	Repository string `json:"repository" yaml:"repository"`
+	// Exit codes of the install on which it is retried, overriding those configured for the install
+	RetryExitCodes []int `json:"retryExitCodes,omitempty" yaml:"retryExitCodes,omitempty"`
+	// Times the install is retried on a retryable exit code, overriding the count configured for the install
+	Retries int `json:"retries,omitempty" yaml:"retries,omitempty"`
+	// Ports the integration listens on, which must not already be in use
+	RequiredPorts []int `json:"requiredPorts,omitempty" yaml:"requiredPorts,omitempty"`
+	// How relevant the recipe is to the host, from 0 to 1, when ranked by the recipe source
+	RelevanceScore float64 `json:"relevanceScore,omitempty" yaml:"-"`
+	// How important installing the recipe is when recommended, optional by default
+	Requirement OpenInstallationRequirement `json:"requirement,omitempty" yaml:"requirement,omitempty"`
+	// Entitlement the account must have for the recipe to be offered
+	RequiresEntitlement string `json:"requiresEntitlement,omitempty" yaml:"requiresEntitlement,omitempty"`
+	// Environment variables the install needs set, unless provided as recipe variables
+	RequiredEnvVars []string `json:"requiredEnvVars,omitempty" yaml:"requiredEnvVars,omitempty"`
+	// Free disk space, in megabytes, the install needs
+	RequiredDiskMB int `json:"requiredDiskMB,omitempty" yaml:"requiredDiskMB,omitempty"`
+	// Shell or interpreter the install steps are run with, such as bash or powershell
+	Shell string `json:"shell,omitempty" yaml:"shell,omitempty"`
+	// Detached base64 ed25519 signature of the recipe file, distributed alongside it
+	Signature string `json:"signature,omitempty" yaml:"-"`
 	// Indicates stability level of recipe
 	Stability OpenInstallationStability `json:"stability,omitempty" yaml:"stability,omitempty"`
+	// Checks that mark steps of the install as already complete, so they are skipped
+	StepChecks []OpenInstallationStepCheck `json:"stepChecks,omitempty" yaml:"stepChecks,omitempty"`
+	// Conditions on the discovered host that the steps of the install run under.
+	StepConditions []OpenInstallationStepCondition `json:"stepConditions,omitempty" yaml:"stepConditions,omitempty"`
 	// Metadata to support generating a URL after installation success
 	SuccessLinkConfig OpenInstallationSuccessLinkConfig `json:"successLinkConfig,omitempty" yaml:"successLinkConfig,omitempty"`
+	// How often the validation NRQL is run, defaulting to the validator's interval
+	ValidationInterval time.Duration `json:"validationInterval,omitempty" yaml:"validationInterval,omitempty"`
 	// NRQL the newrelic-cli uses to validate this recipe
 	// is successfully sending data to New Relic
 	ValidationNRQL NRQL `json:"validationNrql,omitempty" yaml:"validationNrql,omitempty"`
+	// How long to wait for data before validation fails, defaulting to the validator's timeout
+	ValidationTimeout time.Duration `json:"validationTimeout,omitempty" yaml:"validationTimeout,omitempty"`
+	// Directory the install steps run in, with variables expanded, defaulting to the current directory
+	WorkingDir string `json:"workingDir,omitempty" yaml:"workingDir,omitempty"`
 }
 
 // OpenInstallationRecipeInputVariable - Recipe input variable prompts displayed to the user prior to execution
@@ -0,0 +1,130 @@
+package ux
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+const (
+	lineStatePending    = "pending"
+	lineStateInstalling = "installing"
+	lineStateValidating = "validating"
+	lineStateDone       = "done"
+	lineStateFailed     = "failed"
+
+	// clearLine returns the cursor to the start of the line and erases it.
+	clearLine = "\r\x1b[2K"
+)
+
+// LinesProgress is an implementation of the StagedProgressIndicator interface
+// that reports each recipe on a line of its own, with its state: pending,
+// installing, validating, then done or failed.  On a terminal the line of the
+// recipe being installed is rewritten as its state changes.  Otherwise, as when
+// the output is captured in a log, each change of state is written as a new
+// line and no terminal control sequences are written.
+type LinesProgress struct {
+	out io.Writer
+	tty bool
+
+	mu      sync.Mutex
+	current string
+}
+
+// NewLinesProgress returns a new instance of LinesProgress writing to standard
+// output.
+func NewLinesProgress() *LinesProgress {
+	return NewLinesProgressWithWriter(os.Stdout, term.IsTerminal(int(os.Stdout.Fd())))
+}
+
+// NewLinesProgressWithWriter returns a new instance of LinesProgress writing
+// to the given writer, rewriting lines in place only when tty is set.
+func NewLinesProgressWithWriter(out io.Writer, tty bool) *LinesProgress {
+	p := LinesProgress{
+		out: out,
+		tty: tty,
+	}
+
+	return &p
+}
+
+// Pending reports a recipe waiting to be installed.  Pending recipes are only
+// listed when lines are not rewritten, since their lines could not be updated
+// afterwards.
+func (p *LinesProgress) Pending(msg string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.tty {
+		return
+	}
+
+	p.writeLine(msg, lineStatePending)
+}
+
+func (p *LinesProgress) Start(msg string) {
+	p.update(msg, lineStateInstalling)
+}
+
+func (p *LinesProgress) Validating(msg string) {
+	p.update(msg, lineStateValidating)
+}
+
+func (p *LinesProgress) Success(msg string) {
+	p.finish(msg, lineStateDone)
+}
+
+func (p *LinesProgress) Fail(msg string) {
+	p.finish(msg, lineStateFailed)
+}
+
+// Stop ends the line of a recipe that neither succeeded nor failed, such as
+// one that was interrupted.
+func (p *LinesProgress) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.tty && p.current != "" {
+		fmt.Fprintln(p.out)
+	}
+	p.current = ""
+}
+
+func (p *LinesProgress) update(msg string, state string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.current = msg
+
+	if p.tty {
+		fmt.Fprintf(p.out, "%s%s", clearLine, formatProgressLine(msg, state))
+		return
+	}
+
+	p.writeLine(msg, state)
+}
+
+func (p *LinesProgress) finish(msg string, state string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.current = ""
+
+	if p.tty {
+		fmt.Fprintf(p.out, "%s%s\n", clearLine, formatProgressLine(msg, state))
+		return
+	}
+
+	p.writeLine(msg, state)
+}
+
+func (p *LinesProgress) writeLine(msg string, state string) {
+	fmt.Fprintln(p.out, formatProgressLine(msg, state))
+}
+
+func formatProgressLine(msg string, state string) string {
+	return fmt.Sprintf("%-10s %s", state, msg)
+}
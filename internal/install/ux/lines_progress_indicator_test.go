@@ -0,0 +1,83 @@
+package ux
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinesProgressIndicator_interface(t *testing.T) {
+	var r StagedProgressIndicator = NewLinesProgress()
+	require.NotNil(t, r)
+}
+
+func TestLinesProgressIndicator_NotTTY(t *testing.T) {
+	var out bytes.Buffer
+	p := NewLinesProgressWithWriter(&out, false)
+
+	p.Pending("Installing infrastructure-agent-installer")
+	p.Pending("Installing mysql")
+	p.Start("Installing infrastructure-agent-installer")
+	p.Validating("Installing infrastructure-agent-installer")
+	p.Success("Installing infrastructure-agent-installer")
+	p.Stop()
+	p.Start("Installing mysql")
+	p.Fail("Installing mysql")
+	p.Stop()
+
+	require.NotContains(t, out.String(), "\x1b")
+	require.NotContains(t, out.String(), "\r")
+	require.Equal(t, []string{
+		"pending    Installing infrastructure-agent-installer",
+		"pending    Installing mysql",
+		"installing Installing infrastructure-agent-installer",
+		"validating Installing infrastructure-agent-installer",
+		"done       Installing infrastructure-agent-installer",
+		"installing Installing mysql",
+		"failed     Installing mysql",
+	}, strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n"))
+}
+
+func TestLinesProgressIndicator_NotTTYInterrupted(t *testing.T) {
+	var out bytes.Buffer
+	p := NewLinesProgressWithWriter(&out, false)
+
+	p.Start("Installing mysql")
+	p.Stop()
+
+	require.Equal(t, "installing Installing mysql\n", out.String())
+}
+
+func TestLinesProgressIndicator_TTY(t *testing.T) {
+	var out bytes.Buffer
+	p := NewLinesProgressWithWriter(&out, true)
+
+	p.Pending("Installing mysql")
+	p.Start("Installing mysql")
+	p.Validating("Installing mysql")
+	p.Success("Installing mysql")
+	p.Stop()
+
+	require.Equal(t, clearLine+"installing Installing mysql"+
+		clearLine+"validating Installing mysql"+
+		clearLine+"done       Installing mysql\n", out.String())
+}
+
+func TestLinesProgressIndicator_TTYInterrupted(t *testing.T) {
+	var out bytes.Buffer
+	p := NewLinesProgressWithWriter(&out, true)
+
+	p.Start("Installing mysql")
+	p.Stop()
+
+	require.Equal(t, clearLine+"installing Installing mysql\n", out.String())
+}
+
+func TestValidateProgressStyle(t *testing.T) {
+	require.NoError(t, ValidateProgressStyle(""))
+	require.NoError(t, ValidateProgressStyle(ProgressPlain))
+	require.NoError(t, ValidateProgressStyle(ProgressLines))
+	require.Error(t, ValidateProgressStyle("spinner"))
+}
@@ -1,13 +1,30 @@
 package ux
 
+import "github.com/newrelic/newrelic-cli/internal/utils"
+
 type MockPrompter struct {
 	PromptYesNoVal             bool
 	PromptMultiSelectAll       bool
 	PromptYesNoErr             error
 	PromptYesNoCallCount       int
+	PromptYesNoMsg             string
 	PromptMultiSelectVal       []string
+	PromptMultiSelectVals      [][]string
 	PromptMultiSelectErr       error
 	PromptMultiSelectCallCount int
+	PromptMultiSelectMsg       string
+	PromptMultiSelectDefaults  []string
+	PromptMultiSelectOptions   []string
+	PromptSelectVal            string
+	PromptSelectVals           []string
+	PromptSelectErr            error
+	PromptSelectCallCount      int
+	PromptInputVal             string
+	PromptInputVals            []string
+	PromptInputErr             error
+	PromptInputCallCount       int
+	PromptInputMsg             string
+	PromptInputSecret          bool
 }
 
 func NewMockPrompter() *MockPrompter {
@@ -16,15 +33,56 @@ func NewMockPrompter() *MockPrompter {
 
 func (p *MockPrompter) PromptYesNo(msg string) (bool, error) {
 	p.PromptYesNoCallCount++
+	p.PromptYesNoMsg = msg
 	return p.PromptYesNoVal, p.PromptYesNoErr
 }
 
-func (p *MockPrompter) MultiSelect(msg string, options []string) ([]string, error) {
+func (p *MockPrompter) MultiSelect(msg string, options []string, defaults []string) ([]string, error) {
 	p.PromptMultiSelectCallCount++
+	p.PromptMultiSelectMsg = msg
+	p.PromptMultiSelectDefaults = defaults
+	p.PromptMultiSelectOptions = options
 
 	if p.PromptMultiSelectAll {
 		return options, nil
 	}
 
+	if len(p.PromptMultiSelectVals) > 0 {
+		i := utils.MinOf(p.PromptMultiSelectCallCount, len(p.PromptMultiSelectVals)) - 1
+		return p.PromptMultiSelectVals[i], p.PromptMultiSelectErr
+	}
+
 	return p.PromptMultiSelectVal, p.PromptMultiSelectErr
 }
+
+func (p *MockPrompter) Select(msg string, options []string, defaultOption string) (string, error) {
+	p.PromptSelectCallCount++
+
+	if len(p.PromptSelectVals) > 0 {
+		i := utils.MinOf(p.PromptSelectCallCount, len(p.PromptSelectVals)) - 1
+		return p.PromptSelectVals[i], p.PromptSelectErr
+	}
+
+	if p.PromptSelectVal == "" {
+		return defaultOption, p.PromptSelectErr
+	}
+
+	return p.PromptSelectVal, p.PromptSelectErr
+}
+
+func (p *MockPrompter) Input(msg string, defaultValue string, secret bool) (string, error) {
+	p.PromptInputCallCount++
+	p.PromptInputMsg = msg
+	p.PromptInputSecret = secret
+
+	if len(p.PromptInputVals) > 0 {
+		i := utils.MinOf(p.PromptInputCallCount, len(p.PromptInputVals)) - 1
+		return p.PromptInputVals[i], p.PromptInputErr
+	}
+
+	if p.PromptInputVal == "" {
+		return defaultValue, p.PromptInputErr
+	}
+
+	return p.PromptInputVal, p.PromptInputErr
+}
@@ -0,0 +1,17 @@
+package ux
+
+// NoOpProgress is an implementation of the ProgressIndicator interface that
+// reports nothing, for quiet installs.
+type NoOpProgress struct{}
+
+func NewNoOpProgress() *NoOpProgress {
+	return &NoOpProgress{}
+}
+
+func (p *NoOpProgress) Start(string) {}
+
+func (p *NoOpProgress) Success(string) {}
+
+func (p *NoOpProgress) Fail(string) {}
+
+func (p *NoOpProgress) Stop() {}
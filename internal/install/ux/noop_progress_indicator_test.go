@@ -0,0 +1,35 @@
+package ux
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoOpProgressIndicator_interface(t *testing.T) {
+	var r ProgressIndicator = NewNoOpProgress()
+	require.NotNil(t, r)
+}
+
+func TestNoOpProgressIndicator_ProducesNoOutput(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	p := NewNoOpProgress()
+	p.Start("Installing")
+	p.Success("Installing")
+	p.Start("Installing")
+	p.Fail("Installing")
+	p.Stop()
+
+	w.Close()
+	out, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	require.Empty(t, out)
+}
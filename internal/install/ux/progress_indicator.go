@@ -1,8 +1,44 @@
 package ux
 
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// ProgressPlain reports each recipe when it starts and ends.
+	ProgressPlain = "plain"
+	// ProgressLines reports each recipe on a line of its own, with its state.
+	ProgressLines = "lines"
+)
+
 type ProgressIndicator interface {
 	Fail(string)
 	Success(string)
 	Start(string)
 	Stop()
 }
+
+// StagedProgressIndicator is a ProgressIndicator that is also told of the
+// recipes waiting to be installed and of the validation of an installed recipe.
+type StagedProgressIndicator interface {
+	ProgressIndicator
+	Pending(string)
+	Validating(string)
+}
+
+// ProgressStyles returns the names of the supported progress styles.
+func ProgressStyles() []string {
+	return []string{ProgressPlain, ProgressLines}
+}
+
+// ValidateProgressStyle returns an error when the named progress style is not
+// supported.  An empty name selects the plain style.
+func ValidateProgressStyle(style string) error {
+	switch style {
+	case "", ProgressPlain, ProgressLines:
+		return nil
+	}
+
+	return fmt.Errorf("unsupported progress style %s, must be one of: %s", style, strings.Join(ProgressStyles(), ", "))
+}
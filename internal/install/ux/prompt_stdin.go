@@ -0,0 +1,86 @@
+package ux
+
+import (
+	"context"
+	"os"
+	"sync"
+)
+
+// stdinReader reads the standard input on behalf of prompts, so that a prompt
+// that times out or is interrupted stops reading when it is abandoned.  A read
+// of the terminal cannot be interrupted, so a read still pending when its
+// prompt is abandoned is handed to the next prompt rather than dropped.
+type stdinReader struct {
+	file *os.File
+
+	mu sync.Mutex
+	// pending delivers the result of the read in flight, and is nil when
+	// there is none.
+	pending chan stdinReadResult
+	// buffered holds what was read but not yet consumed by a prompt.
+	buffered []byte
+}
+
+type stdinReadResult struct {
+	data []byte
+	err  error
+}
+
+// stdinReadSize is how much a read of the standard input asks for.
+const stdinReadSize = 1024
+
+var stdin = &stdinReader{file: os.Stdin}
+
+// read reads from the standard input until ctx is done.
+func (s *stdinReader) read(ctx context.Context, p []byte) (int, error) {
+	s.mu.Lock()
+	if len(s.buffered) > 0 {
+		n := copy(p, s.buffered)
+		s.buffered = s.buffered[n:]
+		s.mu.Unlock()
+		return n, nil
+	}
+
+	if s.pending == nil {
+		pending := make(chan stdinReadResult, 1)
+		s.pending = pending
+
+		go func() {
+			buf := make([]byte, stdinReadSize)
+			n, err := s.file.Read(buf)
+			pending <- stdinReadResult{data: buf[:n], err: err}
+		}()
+	}
+	pending := s.pending
+	s.mu.Unlock()
+
+	select {
+	case r := <-pending:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.pending = nil
+		n := copy(p, r.data)
+		s.buffered = r.data[n:]
+
+		return n, r.err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// promptStdin is the standard input of a single prompt, which stops reading
+// once the prompt's context is done.  It satisfies survey's terminal.FileReader,
+// so that the prompt still puts the terminal in and out of raw mode.
+type promptStdin struct {
+	ctx    context.Context
+	reader *stdinReader
+}
+
+func (i *promptStdin) Read(p []byte) (int, error) {
+	return i.reader.read(i.ctx, p)
+}
+
+func (i *promptStdin) Fd() uintptr {
+	return i.reader.file.Fd()
+}
@@ -0,0 +1,38 @@
+package ux
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStdinReader_AbandonedReadGoesToNextPrompt(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer r.Close()
+	defer w.Close()
+
+	s := &stdinReader{file: r}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	buf := make([]byte, 1)
+	_, err = s.read(ctx, buf)
+	require.Equal(t, context.Canceled, err)
+
+	_, err = w.Write([]byte("yes"))
+	require.NoError(t, err)
+
+	next := &promptStdin{ctx: context.Background(), reader: s}
+	got := []byte{}
+	for len(got) < 3 {
+		n, err := next.Read(buf)
+		require.NoError(t, err)
+		got = append(got, buf[:n]...)
+	}
+	require.Equal(t, "yes", string(got))
+	require.Equal(t, r.Fd(), next.Fd())
+}
@@ -1,6 +1,11 @@
 package ux
 
 import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
 	survey "github.com/AlecAivazis/survey/v2"
 	"github.com/AlecAivazis/survey/v2/terminal"
 
@@ -8,7 +13,28 @@ import (
 	"github.com/newrelic/newrelic-cli/internal/utils"
 )
 
-type PromptUIPrompter struct{}
+// askOne displays a prompt and collects the response, reading the standard
+// input until ctx is done.  It is a variable so that tests can stand in for
+// the terminal.
+var askOne = func(ctx context.Context, p survey.Prompt, response interface{}) error {
+	in := &promptStdin{ctx: ctx, reader: stdin}
+	return survey.AskOne(p, response, survey.WithStdio(in, os.Stdout, os.Stderr))
+}
+
+// signalCtx is canceled when the install is interrupted by a signal.  It is a
+// variable so that tests can interrupt prompts.
+var signalCtx context.Context = utils.SignalCtx
+
+type PromptUIPrompter struct {
+	// Timeout, when non-zero, bounds how long a prompt waits for a response.
+	// A timed-out prompt answers with its default: no for a yes/no question,
+	// nothing for a multi-select, the default option for a select and the
+	// default value for an input.
+	Timeout time.Duration
+	// ErrorOnTimeout returns types.ErrPromptTimeout from a timed-out prompt
+	// instead of its default answer.
+	ErrorOnTimeout bool
+}
 
 func NewPromptUIPrompter() *PromptUIPrompter {
 	return &PromptUIPrompter{}
@@ -22,35 +48,138 @@ func (p *PromptUIPrompter) PromptYesNo(msg string) (bool, error) {
 		Message: msg,
 	}
 
-	err := survey.AskOne(prompt, &yes)
+	err := p.ask(prompt, &yes)
 	if err != nil {
+		if p.declineOnTimeout(err) {
+			return false, nil
+		}
+
 		return false, err
 	}
 
 	return yes, nil
 }
 
-func (p *PromptUIPrompter) MultiSelect(msg string, options []string) ([]string, error) {
-	defaults := utils.MakeRange(0, len(options)-1)
+func (p *PromptUIPrompter) MultiSelect(msg string, options []string, defaults []string) ([]string, error) {
+	var selectedDefaults interface{} = utils.MakeRange(0, len(options)-1)
+	if defaults != nil {
+		selectedDefaults = defaults
+	}
+
 	selected := []string{}
 	prompt := &survey.MultiSelect{
 		Message: msg,
 		Options: options,
-		Default: defaults,
+		Default: selectedDefaults,
 	}
 
-	err := survey.AskOne(prompt, &selected)
+	err := p.ask(prompt, &selected)
 	if err != nil {
-		if err == terminal.InterruptErr {
-			return nil, types.ErrInterrupt
+		if p.declineOnTimeout(err) {
+			return []string{}, nil
 		}
 
-		if err.Error() == terminal.InterruptErr.Error() {
-			return nil, types.ErrInterrupt
+		return nil, err
+	}
+
+	return selected, nil
+}
+
+func (p *PromptUIPrompter) Select(msg string, options []string, defaultOption string) (string, error) {
+	selected := ""
+	prompt := &survey.Select{
+		Message: msg,
+		Options: options,
+		Default: defaultOption,
+	}
+
+	err := p.ask(prompt, &selected)
+	if err != nil {
+		if p.declineOnTimeout(err) {
+			return defaultOption, nil
 		}
 
-		return nil, err
+		return "", err
 	}
 
 	return selected, nil
 }
+
+func (p *PromptUIPrompter) Input(msg string, defaultValue string, secret bool) (string, error) {
+	value := ""
+	var prompt survey.Prompt = &survey.Input{
+		Message: msg,
+		Default: defaultValue,
+	}
+	if secret {
+		prompt = &survey.Password{
+			Message: msg,
+		}
+	}
+
+	err := p.ask(prompt, &value)
+	if err != nil {
+		if p.declineOnTimeout(err) {
+			return defaultValue, nil
+		}
+
+		return "", err
+	}
+
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	return value, nil
+}
+
+// ask displays the prompt, returning types.ErrInterrupt if the user cancels,
+// such as with Ctrl-C, or the install is interrupted by a signal, and
+// types.ErrPromptTimeout if no response arrives within the timeout.  A prompt
+// that times out or is interrupted stops reading the standard input, and has
+// restored the terminal, by the time ask returns.
+func (p *PromptUIPrompter) ask(prompt survey.Prompt, response interface{}) error {
+	// An install interrupted before the prompt is shown is not prompted.
+	if signalCtx.Err() != nil {
+		return types.ErrInterrupt
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errChan := make(chan error, 1)
+
+	go func() {
+		errChan <- askOne(ctx, prompt, response)
+	}()
+
+	abandon := func(err error) error {
+		cancel()
+		<-errChan
+		return err
+	}
+
+	var timeout <-chan time.Time
+	if p.Timeout > 0 {
+		timer := time.NewTimer(p.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case err := <-errChan:
+		if errors.Is(err, terminal.InterruptErr) {
+			return types.ErrInterrupt
+		}
+
+		return err
+	case <-timeout:
+		return abandon(types.ErrPromptTimeout)
+	case <-signalCtx.Done():
+		return abandon(types.ErrInterrupt)
+	}
+}
+
+func (p *PromptUIPrompter) declineOnTimeout(err error) bool {
+	return err == types.ErrPromptTimeout && !p.ErrorOnTimeout
+}
@@ -0,0 +1,215 @@
+package ux
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	survey "github.com/AlecAivazis/survey/v2"
+	"github.com/AlecAivazis/survey/v2/terminal"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func stubAskOne(fn func(survey.Prompt, interface{}) error) func() {
+	return stubAskOneWithContext(func(_ context.Context, p survey.Prompt, response interface{}) error {
+		return fn(p, response)
+	})
+}
+
+func stubAskOneWithContext(fn func(context.Context, survey.Prompt, interface{}) error) func() {
+	original := askOne
+	askOne = fn
+	return func() { askOne = original }
+}
+
+// neverAnswer waits for a response until the prompt is abandoned, as a prompt
+// reading the terminal does.
+func neverAnswer(ctx context.Context, _ survey.Prompt, _ interface{}) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestPromptUIPrompter_Answered(t *testing.T) {
+	defer stubAskOne(func(p survey.Prompt, response interface{}) error {
+		*(response.(*bool)) = true
+		return nil
+	})()
+
+	p := NewPromptUIPrompter()
+	p.Timeout = time.Second
+
+	yes, err := p.PromptYesNo("continue?")
+	require.NoError(t, err)
+	require.True(t, yes)
+}
+
+func TestPromptUIPrompter_TimeoutDefaults(t *testing.T) {
+	defer stubAskOneWithContext(neverAnswer)()
+
+	p := NewPromptUIPrompter()
+	p.Timeout = 10 * time.Millisecond
+
+	yes, err := p.PromptYesNo("continue?")
+	require.NoError(t, err)
+	require.False(t, yes)
+
+	selected, err := p.MultiSelect("choose", []string{"a", "b"}, nil)
+	require.NoError(t, err)
+	require.Empty(t, selected)
+
+	choice, err := p.Select("choose", []string{"a", "b"}, "b")
+	require.NoError(t, err)
+	require.Equal(t, "b", choice)
+
+	value, err := p.Input("port", "3306", false)
+	require.NoError(t, err)
+	require.Equal(t, "3306", value)
+}
+
+func TestPromptUIPrompter_TimeoutStopsPrompt(t *testing.T) {
+	done := false
+	defer stubAskOneWithContext(func(ctx context.Context, p survey.Prompt, response interface{}) error {
+		err := neverAnswer(ctx, p, response)
+		done = true
+		return err
+	})()
+
+	p := NewPromptUIPrompter()
+	p.Timeout = 10 * time.Millisecond
+
+	_, err := p.PromptYesNo("continue?")
+	require.NoError(t, err)
+	require.True(t, done)
+}
+
+func TestPromptUIPrompter_TimeoutErrors(t *testing.T) {
+	defer stubAskOneWithContext(neverAnswer)()
+
+	p := NewPromptUIPrompter()
+	p.Timeout = 10 * time.Millisecond
+	p.ErrorOnTimeout = true
+
+	_, err := p.PromptYesNo("continue?")
+	require.Equal(t, types.ErrPromptTimeout, err)
+
+	_, err = p.MultiSelect("choose", []string{"a", "b"}, nil)
+	require.Equal(t, types.ErrPromptTimeout, err)
+
+	_, err = p.Select("choose", []string{"a", "b"}, "b")
+	require.Equal(t, types.ErrPromptTimeout, err)
+
+	_, err = p.Input("port", "3306", false)
+	require.Equal(t, types.ErrPromptTimeout, err)
+}
+
+func TestPromptUIPrompter_Input(t *testing.T) {
+	var asked survey.Prompt
+	answer := ""
+	defer stubAskOne(func(prompt survey.Prompt, response interface{}) error {
+		asked = prompt
+		*(response.(*string)) = answer
+		return nil
+	})()
+
+	p := NewPromptUIPrompter()
+
+	answer = "3307"
+	value, err := p.Input("port", "3306", false)
+	require.NoError(t, err)
+	require.Equal(t, "3307", value)
+	require.Equal(t, "3306", asked.(*survey.Input).Default)
+
+	answer = "hunter2"
+	value, err = p.Input("password", "", true)
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", value)
+	require.IsType(t, &survey.Password{}, asked)
+
+	answer = ""
+	value, err = p.Input("password", "changeme", true)
+	require.NoError(t, err)
+	require.Equal(t, "changeme", value)
+}
+
+func TestPromptUIPrompter_Interrupt(t *testing.T) {
+	defer stubAskOne(func(survey.Prompt, interface{}) error {
+		return terminal.InterruptErr
+	})()
+
+	p := NewPromptUIPrompter()
+	p.Timeout = time.Second
+
+	_, err := p.Select("choose", []string{"a", "b"}, "b")
+	require.Equal(t, types.ErrInterrupt, err)
+}
+
+func TestPromptUIPrompter_InterruptMultiSelectAndYesNo(t *testing.T) {
+	defer stubAskOne(func(survey.Prompt, interface{}) error {
+		return terminal.InterruptErr
+	})()
+
+	p := NewPromptUIPrompter()
+
+	selected, err := p.MultiSelect("choose", []string{"a", "b"}, nil)
+	require.Equal(t, types.ErrInterrupt, err)
+	require.Nil(t, selected)
+
+	yes, err := p.PromptYesNo("continue?")
+	require.Equal(t, types.ErrInterrupt, err)
+	require.False(t, yes)
+}
+
+func stubSignalCtx(ctx context.Context) func() {
+	original := signalCtx
+	signalCtx = ctx
+	return func() { signalCtx = original }
+}
+
+func TestPromptUIPrompter_SignalWhilePrompting(t *testing.T) {
+	defer stubAskOneWithContext(neverAnswer)()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer stubSignalCtx(ctx)()
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	p := NewPromptUIPrompter()
+
+	_, err := p.MultiSelect("choose", []string{"a", "b"}, nil)
+	require.Equal(t, types.ErrInterrupt, err)
+}
+
+func TestPromptUIPrompter_SignalBeforePrompting(t *testing.T) {
+	asked := false
+	defer stubAskOne(func(survey.Prompt, interface{}) error {
+		asked = true
+		return nil
+	})()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	defer stubSignalCtx(ctx)()
+
+	p := NewPromptUIPrompter()
+
+	_, err := p.PromptYesNo("continue?")
+	require.Equal(t, types.ErrInterrupt, err)
+	require.False(t, asked)
+}
+
+func TestPromptUIPrompter_Error(t *testing.T) {
+	defer stubAskOne(func(survey.Prompt, interface{}) error {
+		return errors.New("no terminal")
+	})()
+
+	p := NewPromptUIPrompter()
+
+	_, err := p.PromptYesNo("continue?")
+	require.EqualError(t, err, "no terminal")
+}
@@ -2,5 +2,14 @@ package ux
 
 type Prompter interface {
 	PromptYesNo(msg string) (bool, error)
-	MultiSelect(msg string, options []string) ([]string, error)
+	// MultiSelect prompts for any number of the given options.  The options in
+	// defaults are pre-selected; when defaults is nil, all options are.
+	MultiSelect(msg string, options []string, defaults []string) ([]string, error)
+	// Select prompts for exactly one of the given options, pre-selecting
+	// defaultOption.
+	Select(msg string, options []string, defaultOption string) (string, error)
+	// Input prompts for a value typed by the user, pre-filling defaultValue.
+	// A secret value is masked as it is typed, and an empty answer to it takes
+	// defaultValue, which is not shown.
+	Input(msg string, defaultValue string, secret bool) (string, error)
 }
@@ -0,0 +1,195 @@
+package ux
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ScriptedAnswer is a predetermined response to a prompt.
+type ScriptedAnswer struct {
+	// Prompt, when set, restricts the answer to prompts whose message contains
+	// it, so that answers for prompts that may not be shown can be skipped.
+	Prompt string `yaml:"prompt,omitempty"`
+	// Yes answers a yes/no prompt.
+	Yes *bool `yaml:"yes,omitempty"`
+	// Selected answers a multi-select prompt, or a select prompt with a single
+	// option.
+	Selected []string `yaml:"selected,omitempty"`
+	// Value answers an input prompt, such as for a recipe's input variable.
+	Value *string `yaml:"value,omitempty"`
+}
+
+// ScriptedPrompter is an implementation of the Prompter interface that answers
+// prompts with predetermined responses rather than asking the user.  Each
+// prompt takes the first remaining answer whose Prompt matches its message;
+// answers are used once.  A prompt is an error when no answer is left for it,
+// or when its answer is of the wrong kind.
+type ScriptedPrompter struct {
+	answers []ScriptedAnswer
+	used    []bool
+}
+
+// NewScriptedPrompter returns a new instance of ScriptedPrompter giving the
+// answers in order.
+func NewScriptedPrompter(answers []ScriptedAnswer) *ScriptedPrompter {
+	return &ScriptedPrompter{
+		answers: answers,
+		used:    make([]bool, len(answers)),
+	}
+}
+
+// LoadScriptedAnswers reads a YAML or JSON list of answers from the given
+// file.
+func LoadScriptedAnswers(path string) ([]ScriptedAnswer, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read answers file %s: %s", path, err)
+	}
+
+	answers := []ScriptedAnswer{}
+	if err := yaml.UnmarshalStrict(data, &answers); err != nil {
+		return nil, fmt.Errorf("could not parse answers file %s: %s", path, err)
+	}
+
+	for n, a := range answers {
+		if a.kinds() != 1 {
+			return nil, fmt.Errorf("answer %d of %s must set exactly one of yes, selected or value", n+1, path)
+		}
+	}
+
+	return answers, nil
+}
+
+func (p *ScriptedPrompter) PromptYesNo(msg string) (bool, error) {
+	a, err := p.next(msg)
+	if err != nil {
+		return false, err
+	}
+
+	if a.Yes == nil {
+		return false, fmt.Errorf("the scripted answer to %q is not a yes/no answer", msg)
+	}
+
+	return *a.Yes, nil
+}
+
+func (p *ScriptedPrompter) MultiSelect(msg string, options []string, defaults []string) ([]string, error) {
+	a, err := p.next(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.Selected == nil {
+		return nil, fmt.Errorf("the scripted answer to %q is not a selection", msg)
+	}
+
+	return resolveOptions(msg, options, a.Selected)
+}
+
+func (p *ScriptedPrompter) Select(msg string, options []string, defaultOption string) (string, error) {
+	a, err := p.next(msg)
+	if err != nil {
+		return "", err
+	}
+
+	if len(a.Selected) != 1 {
+		return "", fmt.Errorf("the scripted answer to %q must select exactly one option", msg)
+	}
+
+	selected, err := resolveOptions(msg, options, a.Selected)
+	if err != nil {
+		return "", err
+	}
+
+	return selected[0], nil
+}
+
+func (p *ScriptedPrompter) Input(msg string, defaultValue string, secret bool) (string, error) {
+	a, err := p.next(msg)
+	if err != nil {
+		return "", err
+	}
+
+	if a.Value == nil {
+		return "", fmt.Errorf("the scripted answer to %q is not a value", msg)
+	}
+
+	if *a.Value == "" {
+		return defaultValue, nil
+	}
+
+	return *a.Value, nil
+}
+
+// kinds returns the number of kinds of answer set.
+func (a ScriptedAnswer) kinds() int {
+	n := 0
+	if a.Yes != nil {
+		n++
+	}
+	if a.Selected != nil {
+		n++
+	}
+	if a.Value != nil {
+		n++
+	}
+
+	return n
+}
+
+// next returns the first unused answer matching the prompt message.
+func (p *ScriptedPrompter) next(msg string) (*ScriptedAnswer, error) {
+	for n, a := range p.answers {
+		if p.used[n] || !strings.Contains(msg, a.Prompt) {
+			continue
+		}
+
+		p.used[n] = true
+		return &p.answers[n], nil
+	}
+
+	return nil, fmt.Errorf("no scripted answer is left for the prompt %q", msg)
+}
+
+// resolveOptions returns the options the answer selects.  An answer selects the
+// option it equals or, failing that, the only option starting with it followed
+// by a space, so that options can be given without annotations that vary
+// between runs, such as their relevance.
+func resolveOptions(msg string, options []string, selected []string) ([]string, error) {
+	resolved := []string{}
+
+	for _, s := range selected {
+		option, ok := resolveOption(options, s)
+		if !ok {
+			return nil, fmt.Errorf("the scripted answer to %q selects %s, which is not one of the options: %s", msg, s, strings.Join(options, ", "))
+		}
+
+		resolved = append(resolved, option)
+	}
+
+	return resolved, nil
+}
+
+func resolveOption(options []string, s string) (string, bool) {
+	for _, o := range options {
+		if s == o {
+			return o, true
+		}
+	}
+
+	matches := []string{}
+	for _, o := range options {
+		if strings.HasPrefix(o, s+" ") {
+			matches = append(matches, o)
+		}
+	}
+
+	if len(matches) != 1 {
+		return "", false
+	}
+
+	return matches[0], true
+}
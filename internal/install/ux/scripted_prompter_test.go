@@ -0,0 +1,157 @@
+package ux
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScriptedPrompter_InOrder(t *testing.T) {
+	yes, no := true, false
+	p := NewScriptedPrompter([]ScriptedAnswer{
+		{Yes: &yes},
+		{Selected: []string{"mysql", "redis"}},
+		{Yes: &no},
+		{Selected: []string{"skip"}},
+	})
+
+	answer, err := p.PromptYesNo("Install the infrastructure agent?")
+	require.NoError(t, err)
+	require.True(t, answer)
+
+	selected, err := p.MultiSelect("Choose integrations:", []string{"mysql", "nginx", "redis"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"mysql", "redis"}, selected)
+
+	answer, err = p.PromptYesNo("Watch the log files?")
+	require.NoError(t, err)
+	require.False(t, answer)
+
+	choice, err := p.Select("The recipe failed.", []string{"retry", "skip", "abort"}, "skip")
+	require.NoError(t, err)
+	require.Equal(t, "skip", choice)
+
+	_, err = p.PromptYesNo("Anything else?")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no scripted answer is left")
+}
+
+func TestScriptedPrompter_Branching(t *testing.T) {
+	yes, no := true, false
+	p := NewScriptedPrompter([]ScriptedAnswer{
+		{Prompt: "installation plan", Yes: &yes},
+		{Prompt: "failed", Selected: []string{"retry"}},
+		{Yes: &no},
+	})
+
+	// The plan is not shown, so the first unrestricted answer is given.
+	answer, err := p.PromptYesNo("Watch the log files?")
+	require.NoError(t, err)
+	require.False(t, answer)
+
+	answer, err = p.PromptYesNo("Proceed with this installation plan?")
+	require.NoError(t, err)
+	require.True(t, answer)
+
+	// The failure prompt never shows, leaving its answer unused.
+	_, err = p.PromptYesNo("Continue?")
+	require.Error(t, err)
+}
+
+func TestScriptedPrompter_WrongKind(t *testing.T) {
+	yes := true
+	p := NewScriptedPrompter([]ScriptedAnswer{{Yes: &yes}, {Selected: []string{"mysql"}}, {Selected: []string{"a", "b"}}})
+
+	_, err := p.MultiSelect("Choose integrations:", []string{"mysql"}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not a selection")
+
+	_, err = p.MultiSelect("Choose integrations:", []string{"nginx"}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not one of the options")
+
+	_, err = p.Select("Pick one:", []string{"a", "b"}, "a")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "exactly one option")
+}
+
+func TestScriptedPrompter_Input(t *testing.T) {
+	yes, port, empty := true, "3307", ""
+	p := NewScriptedPrompter([]ScriptedAnswer{
+		{Prompt: "port", Value: &port},
+		{Prompt: "password", Value: &empty},
+		{Yes: &yes},
+	})
+
+	value, err := p.Input("MySQL port", "3306", false)
+	require.NoError(t, err)
+	require.Equal(t, "3307", value)
+
+	value, err = p.Input("MySQL password", "changeme", true)
+	require.NoError(t, err)
+	require.Equal(t, "changeme", value)
+
+	_, err = p.Input("MySQL user", "", false)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not a value")
+}
+
+func TestLoadScriptedAnswers(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "newrelic")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	path := filepath.Join(tmp, "answers.yml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`
+- yes: true
+- prompt: recommended instrumentation
+  selected: [MySQL, Redis]
+- selected: []
+- prompt: port
+  value: "3307"
+`), 0600))
+
+	answers, err := LoadScriptedAnswers(path)
+	require.NoError(t, err)
+	require.Len(t, answers, 4)
+	require.True(t, *answers[0].Yes)
+	require.Equal(t, "recommended instrumentation", answers[1].Prompt)
+	require.Equal(t, []string{"MySQL", "Redis"}, answers[1].Selected)
+	require.Equal(t, []string{}, answers[2].Selected)
+	require.Equal(t, "3307", *answers[3].Value)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("- prompt: no answer\n"), 0600))
+	_, err = LoadScriptedAnswers(path)
+	require.Error(t, err)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("- yes: true\n  value: \"3307\"\n"), 0600))
+	_, err = LoadScriptedAnswers(path)
+	require.Error(t, err)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("- yess: true\n"), 0600))
+	_, err = LoadScriptedAnswers(path)
+	require.Error(t, err)
+}
+
+func TestScriptedPrompter_AnnotatedOptions(t *testing.T) {
+	p := NewScriptedPrompter([]ScriptedAnswer{
+		{Selected: []string{"Redis"}},
+		{Selected: []string{"MySQL"}},
+		{Selected: []string{"Redi"}},
+	})
+
+	selected, err := p.MultiSelect("Choose integrations:", []string{"Redis [relevance 60%]", "MySQL"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Redis [relevance 60%]"}, selected)
+
+	// The answer is ambiguous between the annotated options.
+	_, err = p.MultiSelect("Choose integrations:", []string{"MySQL (required)", "MySQL [relevance 10%]"}, nil)
+	require.Error(t, err)
+
+	_, err = p.MultiSelect("Choose integrations:", []string{"Redis [relevance 60%]"}, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not one of the options")
+}
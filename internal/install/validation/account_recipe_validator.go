@@ -0,0 +1,115 @@
+package validation
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/newrelic/newrelic-cli/internal/credentials"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// ErrAccountMismatch is returned when a recipe's entity reports data, but not
+// under the configured account.  This usually means the license key in use
+// belongs to a different account than the profile.
+type ErrAccountMismatch struct {
+	EntityGUID string
+	Expected   int
+	Actual     int
+}
+
+func NewErrAccountMismatch(entityGUID string, expected int, actual int) ErrAccountMismatch {
+	return ErrAccountMismatch{
+		EntityGUID: entityGUID,
+		Expected:   expected,
+		Actual:     actual,
+	}
+}
+
+func (e ErrAccountMismatch) Error() string {
+	if e.Actual != 0 {
+		return fmt.Sprintf("entity %s is reporting to account %d rather than the configured account %d, check that your license key and account ID belong to the same account", e.EntityGUID, e.Actual, e.Expected)
+	}
+
+	return fmt.Sprintf("entity %s is not reporting to the configured account %d, check that your license key and account ID belong to the same account", e.EntityGUID, e.Expected)
+}
+
+// AccountRecipeValidator is an implementation of the RecipeValidator interface
+// that wraps another validator and additionally verifies that the validated
+// entity belongs to the configured account.
+type AccountRecipeValidator struct {
+	// AccountID is the account the entity is expected to report to.  Defaults
+	// to the account of the default profile.
+	AccountID int
+	validator RecipeValidator
+}
+
+// NewAccountRecipeValidator returns a new instance of AccountRecipeValidator.
+func NewAccountRecipeValidator(v RecipeValidator) *AccountRecipeValidator {
+	a := AccountRecipeValidator{
+		validator: v,
+	}
+
+	return &a
+}
+
+// ValidateRecipe validates the given recipe with the wrapped validator, then
+// confirms the account encoded in the returned entity's GUID is the configured
+// account.  Recipes whose validation does not identify an entity, or whose
+// entity GUID cannot be decoded, cannot be checked and pass.
+func (a *AccountRecipeValidator) ValidateRecipe(ctx context.Context, dm types.DiscoveryManifest, r types.OpenInstallationRecipe) (string, error) {
+	entityGUID, err := a.validator.ValidateRecipe(ctx, dm, r)
+	if err != nil || entityGUID == "" {
+		return entityGUID, err
+	}
+
+	accountID := a.accountID()
+	if accountID == 0 {
+		return "", errors.New("no account ID found in default profile")
+	}
+
+	actual := accountIDFromEntityGUID(entityGUID)
+	if actual != 0 && actual != accountID {
+		return "", NewErrAccountMismatch(entityGUID, accountID, actual)
+	}
+
+	return entityGUID, nil
+}
+
+func (a *AccountRecipeValidator) accountID() int {
+	if a.AccountID != 0 {
+		return a.AccountID
+	}
+
+	profile := credentials.DefaultProfile()
+	if profile == nil {
+		return 0
+	}
+
+	return profile.AccountID
+}
+
+// accountIDFromEntityGUID returns the account ID encoded in an entity GUID,
+// or zero if it cannot be decoded.  Entity GUIDs are the base64 encoding of
+// "<accountID>|<domain>|<type>|<identifier>".
+func accountIDFromEntityGUID(entityGUID string) int {
+	decoded, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(entityGUID, "="))
+	if err != nil {
+		return 0
+	}
+
+	parts := strings.Split(string(decoded), "|")
+	if len(parts) < 2 {
+		return 0
+	}
+
+	accountID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0
+	}
+
+	return accountID
+}
@@ -0,0 +1,100 @@
+// +build unit
+
+package validation
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/credentials"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestAccountRecipeValidator_ReportsToAccount(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+
+	entityGUID := base64.RawStdEncoding.EncodeToString([]byte("12345|INFRA|NA|123"))
+	mv := NewMockRecipeValidator()
+	mv.ValidateVal = entityGUID
+	v := NewAccountRecipeValidator(mv)
+
+	result, err := v.ValidateRecipe(context.Background(), types.DiscoveryManifest{}, types.OpenInstallationRecipe{})
+
+	require.NoError(t, err)
+	require.Equal(t, entityGUID, result)
+}
+
+func TestAccountRecipeValidator_WrongAccount(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+
+	entityGUID := base64.RawStdEncoding.EncodeToString([]byte("67890|INFRA|NA|123"))
+	mv := NewMockRecipeValidator()
+	mv.ValidateVal = entityGUID
+	v := NewAccountRecipeValidator(mv)
+
+	_, err := v.ValidateRecipe(context.Background(), types.DiscoveryManifest{}, types.OpenInstallationRecipe{})
+
+	var merr ErrAccountMismatch
+	require.True(t, errors.As(err, &merr))
+	require.Equal(t, 12345, merr.Expected)
+	require.Equal(t, 67890, merr.Actual)
+	require.Equal(t, entityGUID, merr.EntityGUID)
+}
+
+func TestAccountRecipeValidator_AccountOverride(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+
+	mv := NewMockRecipeValidator()
+	mv.ValidateVal = base64.RawStdEncoding.EncodeToString([]byte("999|INFRA|NA|123"))
+	v := NewAccountRecipeValidator(mv)
+	v.AccountID = 999
+
+	_, err := v.ValidateRecipe(context.Background(), types.DiscoveryManifest{}, types.OpenInstallationRecipe{})
+
+	require.NoError(t, err)
+}
+
+func TestAccountRecipeValidator_UndecodableEntityGUID(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+
+	mv := NewMockRecipeValidator()
+	mv.ValidateVal = "testGUID"
+	v := NewAccountRecipeValidator(mv)
+
+	entityGUID, err := v.ValidateRecipe(context.Background(), types.DiscoveryManifest{}, types.OpenInstallationRecipe{})
+
+	require.NoError(t, err)
+	require.Equal(t, "testGUID", entityGUID)
+}
+
+func TestAccountRecipeValidator_NoEntityGUID(t *testing.T) {
+	mv := NewMockRecipeValidator()
+	v := NewAccountRecipeValidator(mv)
+
+	entityGUID, err := v.ValidateRecipe(context.Background(), types.DiscoveryManifest{}, types.OpenInstallationRecipe{})
+
+	require.NoError(t, err)
+	require.Equal(t, "", entityGUID)
+}
+
+func TestAccountRecipeValidator_ValidationError(t *testing.T) {
+	mv := NewMockRecipeValidator()
+	mv.ValidateErr = errors.New("reached max validation attempts")
+	v := NewAccountRecipeValidator(mv)
+
+	_, err := v.ValidateRecipe(context.Background(), types.DiscoveryManifest{}, types.OpenInstallationRecipe{})
+
+	require.Error(t, err)
+	var merr ErrAccountMismatch
+	require.False(t, errors.As(err, &merr))
+}
+
+func TestAccountIDFromEntityGUID(t *testing.T) {
+	require.Equal(t, 12345, accountIDFromEntityGUID(base64.StdEncoding.EncodeToString([]byte("12345|APM|APPLICATION|1"))))
+	require.Equal(t, 0, accountIDFromEntityGUID("not a guid"))
+	require.Equal(t, 0, accountIDFromEntityGUID(""))
+}
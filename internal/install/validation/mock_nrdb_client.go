@@ -8,9 +8,11 @@ import (
 )
 
 type MockNRDBClient struct {
-	results  func() []nrdb.NRDBResult
-	attempts int
-	error    string
+	results   func() []nrdb.NRDBResult
+	attempts  int
+	error     string
+	accountID int
+	nrql      nrdb.NRQL
 }
 
 func NewMockNRDBClient() *MockNRDBClient {
@@ -23,6 +25,8 @@ func NewMockNRDBClient() *MockNRDBClient {
 
 func (c *MockNRDBClient) QueryWithContext(ctx context.Context, accountID int, nrql nrdb.NRQL) (*nrdb.NRDBResultContainer, error) {
 	c.attempts++
+	c.accountID = accountID
+	c.nrql = nrql
 
 	if c.error != "" {
 		return nil, errors.New(c.error)
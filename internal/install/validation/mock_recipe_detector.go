@@ -0,0 +1,36 @@
+package validation
+
+import (
+	"context"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+type MockRecipeDetector struct {
+	DetectErr       error
+	DetectCallCount int
+	InstalledNames  map[string]string
+}
+
+func NewMockRecipeDetector() *MockRecipeDetector {
+	return &MockRecipeDetector{
+		InstalledNames: map[string]string{},
+	}
+}
+
+// SetInstalled marks the named recipe as already installed, reporting the given entity GUID.
+func (m *MockRecipeDetector) SetInstalled(name string, entityGUID string) {
+	m.InstalledNames[name] = entityGUID
+}
+
+func (m *MockRecipeDetector) DetectRecipe(ctx context.Context, dm types.DiscoveryManifest, r types.OpenInstallationRecipe) (bool, string, error) {
+	m.DetectCallCount++
+
+	if m.DetectErr != nil {
+		return false, "", m.DetectErr
+	}
+
+	entityGUID, ok := m.InstalledNames[r.Name]
+
+	return ok, entityGUID, nil
+}
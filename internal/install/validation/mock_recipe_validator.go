@@ -14,6 +14,10 @@ type MockRecipeValidator struct {
 	ValidateCallCount int
 	ValidateVal       string
 	ValidateVals      []string
+	ObserveVal        *ValidationTimeline
+	ObserveErr        error
+	ObserveCallCount  int
+	ObserveWindow     time.Duration
 }
 
 func NewMockRecipeValidator() *MockRecipeValidator {
@@ -44,3 +48,14 @@ func (m *MockRecipeValidator) ValidateRecipe(ctx context.Context, dm types.Disco
 
 	return val, err
 }
+
+func (m *MockRecipeValidator) ObserveRecipe(ctx context.Context, dm types.DiscoveryManifest, r types.OpenInstallationRecipe, window time.Duration) (*ValidationTimeline, error) {
+	m.ObserveCallCount++
+	m.ObserveWindow = window
+
+	if m.ObserveVal == nil && m.ObserveErr == nil {
+		return &ValidationTimeline{}, nil
+	}
+
+	return m.ObserveVal, m.ObserveErr
+}
@@ -0,0 +1,40 @@
+package validation
+
+import (
+	"context"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/utils"
+	utilsValidation "github.com/newrelic/newrelic-cli/internal/utils/validation"
+)
+
+// NRQLRecipeDetector is an implementation of the RecipeDetector interface that
+// runs a recipe's validation query a single time to determine whether data is
+// already being reported for it.
+type NRQLRecipeDetector struct {
+	validator *utilsValidation.PollingNRQLValidator
+}
+
+// NewNRQLRecipeDetector returns a new instance of NRQLRecipeDetector.
+func NewNRQLRecipeDetector(c utils.NRDBClient) *NRQLRecipeDetector {
+	d := NRQLRecipeDetector{
+		validator: utilsValidation.NewPollingNRQLValidator(c),
+	}
+
+	return &d
+}
+
+// DetectRecipe reports whether the given recipe is already reporting data.
+// Recipes without a validation query are never considered installed.
+func (d *NRQLRecipeDetector) DetectRecipe(ctx context.Context, dm types.DiscoveryManifest, r types.OpenInstallationRecipe) (bool, string, error) {
+	if r.ValidationNRQL == "" {
+		return false, "", nil
+	}
+
+	query, err := ResolveValidationNRQL(dm, r, nil)
+	if err != nil {
+		return false, "", err
+	}
+
+	return d.validator.Check(ctx, string(query))
+}
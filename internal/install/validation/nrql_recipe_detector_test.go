@@ -0,0 +1,84 @@
+// +build unit
+
+package validation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/credentials"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-client-go/pkg/nrdb"
+)
+
+func TestDetectRecipe_AlreadyReporting(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+	c := NewMockNRDBClient()
+	c.ReturnResultsAfterNAttempts(nonEmptyResults, nonEmptyResults, 0)
+
+	d := NewNRQLRecipeDetector(c)
+	r := types.OpenInstallationRecipe{ValidationNRQL: "testNrql"}
+
+	installed, _, err := d.DetectRecipe(context.Background(), types.DiscoveryManifest{}, r)
+	require.NoError(t, err)
+	require.True(t, installed)
+	require.Equal(t, 1, c.Attempts())
+}
+
+func TestDetectRecipe_EntityGUID(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+	c := NewMockNRDBClient()
+	results := []nrdb.NRDBResult{
+		map[string]interface{}{
+			"count":      1.0,
+			"entityGuid": "testGuid",
+		},
+	}
+	c.ReturnResultsAfterNAttempts(results, results, 0)
+
+	d := NewNRQLRecipeDetector(c)
+	r := types.OpenInstallationRecipe{ValidationNRQL: "testNrql"}
+
+	installed, entityGUID, err := d.DetectRecipe(context.Background(), types.DiscoveryManifest{}, r)
+	require.NoError(t, err)
+	require.True(t, installed)
+	require.Equal(t, "testGuid", entityGUID)
+}
+
+func TestDetectRecipe_NotReporting(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+	c := NewMockNRDBClient()
+	c.ReturnResultsAfterNAttempts(emptyResults, nonEmptyResults, 2)
+
+	d := NewNRQLRecipeDetector(c)
+	r := types.OpenInstallationRecipe{ValidationNRQL: "testNrql"}
+
+	installed, _, err := d.DetectRecipe(context.Background(), types.DiscoveryManifest{}, r)
+	require.NoError(t, err)
+	require.False(t, installed)
+	require.Equal(t, 1, c.Attempts())
+}
+
+func TestDetectRecipe_NoValidationNRQL(t *testing.T) {
+	c := NewMockNRDBClient()
+	d := NewNRQLRecipeDetector(c)
+
+	installed, _, err := d.DetectRecipe(context.Background(), types.DiscoveryManifest{}, types.OpenInstallationRecipe{})
+	require.NoError(t, err)
+	require.False(t, installed)
+	require.Equal(t, 0, c.Attempts())
+}
+
+func TestDetectRecipe_Error(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+	c := NewMockNRDBClient()
+	c.ThrowError("test error")
+
+	d := NewNRQLRecipeDetector(c)
+	r := types.OpenInstallationRecipe{ValidationNRQL: "testNrql"}
+
+	_, _, err := d.DetectRecipe(context.Background(), types.DiscoveryManifest{}, r)
+	require.Error(t, err)
+}
@@ -1,9 +1,10 @@
 package validation
 
 import (
-	"bytes"
 	"context"
-	"html/template"
+	"time"
+
+	log "github.com/sirupsen/logrus"
 
 	"github.com/newrelic/newrelic-cli/internal/install/types"
 	"github.com/newrelic/newrelic-cli/internal/utils"
@@ -29,30 +30,35 @@ func NewPollingRecipeValidator(c utils.NRDBClient) *PollingRecipeValidator {
 
 // ValidateRecipe polls NRDB to assert data is being reported for the given recipe.
 func (m *PollingRecipeValidator) ValidateRecipe(ctx context.Context, dm types.DiscoveryManifest, r types.OpenInstallationRecipe) (string, error) {
-	query, err := substituteHostname(dm, r)
+	query, err := ResolveValidationNRQL(dm, r, nil)
 	if err != nil {
 		return "", err
 	}
 
-	return m.Validate(ctx, query)
-}
+	interval, maxAttempts := m.recipeTiming(r)
 
-func substituteHostname(dm types.DiscoveryManifest, r types.OpenInstallationRecipe) (string, error) {
-	tmpl, err := template.New("validationNRQL").Parse(string(r.ValidationNRQL))
-	if err != nil {
-		panic(err)
-	}
+	log.WithFields(log.Fields{
+		"name":                r.Name,
+		"validation_interval": interval,
+		"validation_timeout":  time.Duration(maxAttempts) * interval,
+		"max_attempts":        maxAttempts,
+	}).Debug("validating recipe")
 
-	v := struct {
-		HOSTNAME string
-	}{
-		HOSTNAME: dm.Hostname,
+	return m.ValidateWithTiming(ctx, string(query), interval, maxAttempts)
+}
+
+// recipeTiming returns the polling interval and number of attempts for the
+// given recipe, falling back to the validator's own when the recipe sets none.
+func (m *PollingRecipeValidator) recipeTiming(r types.OpenInstallationRecipe) (time.Duration, int) {
+	interval := m.Interval
+	if r.ValidationInterval > 0 {
+		interval = r.ValidationInterval
 	}
 
-	var tpl bytes.Buffer
-	if err = tmpl.Execute(&tpl, v); err != nil {
-		return "", err
+	maxAttempts := m.MaxAttempts
+	if r.ValidationTimeout > 0 && interval > 0 {
+		maxAttempts = int(r.ValidationTimeout/interval) + 1
 	}
 
-	return tpl.String(), nil
+	return interval, maxAttempts
 }
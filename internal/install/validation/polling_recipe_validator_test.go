@@ -148,6 +148,67 @@ func TestValidate_QueryError(t *testing.T) {
 	require.EqualError(t, err, "test error")
 }
 
+func TestValidate_QueriesGivenAccount(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+	c := NewMockNRDBClient()
+
+	c.ReturnResultsAfterNAttempts(emptyResults, nonEmptyResults, 1)
+
+	pi := ux.NewMockProgressIndicator()
+	v := NewPollingRecipeValidator(c)
+	v.ProgressIndicator = pi
+	v.AccountID = 67890
+
+	r := types.OpenInstallationRecipe{}
+	m := types.DiscoveryManifest{}
+
+	_, err := v.ValidateRecipe(getTestContext(), m, r)
+
+	require.NoError(t, err)
+	require.Equal(t, 67890, c.accountID)
+}
+
 func getTestContext() context.Context {
 	return context.WithValue(context.Background(), TestIdentifierKey, true)
 }
+
+func TestValidate_RecipeTiming(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+	c := NewMockNRDBClient()
+	pi := ux.NewMockProgressIndicator()
+	v := NewPollingRecipeValidator(c)
+	v.ProgressIndicator = pi
+	v.MaxAttempts = 10
+	v.Interval = time.Hour
+
+	c.ReturnResultsAfterNAttempts(emptyResults, nonEmptyResults, 5)
+
+	r := types.OpenInstallationRecipe{
+		ValidationInterval: 10 * time.Millisecond,
+		ValidationTimeout:  20 * time.Millisecond,
+	}
+	m := types.DiscoveryManifest{}
+
+	_, err := v.ValidateRecipe(getTestContext(), m, r)
+
+	require.Error(t, err)
+	require.Equal(t, 3, c.Attempts())
+}
+
+func TestRecipeTiming(t *testing.T) {
+	v := NewPollingRecipeValidator(NewMockNRDBClient())
+	v.MaxAttempts = 10
+	v.Interval = 5 * time.Second
+
+	interval, maxAttempts := v.recipeTiming(types.OpenInstallationRecipe{})
+	require.Equal(t, 5*time.Second, interval)
+	require.Equal(t, 10, maxAttempts)
+
+	interval, maxAttempts = v.recipeTiming(types.OpenInstallationRecipe{ValidationTimeout: time.Minute})
+	require.Equal(t, 5*time.Second, interval)
+	require.Equal(t, 13, maxAttempts)
+
+	interval, maxAttempts = v.recipeTiming(types.OpenInstallationRecipe{ValidationInterval: 30 * time.Second})
+	require.Equal(t, 30*time.Second, interval)
+	require.Equal(t, 10, maxAttempts)
+}
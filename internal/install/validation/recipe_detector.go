@@ -0,0 +1,13 @@
+package validation
+
+import (
+	"context"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// RecipeDetector determines whether a recipe is already installed and reporting
+// data prior to installation.
+type RecipeDetector interface {
+	DetectRecipe(context.Context, types.DiscoveryManifest, types.OpenInstallationRecipe) (installed bool, entityGUID string, err error)
+}
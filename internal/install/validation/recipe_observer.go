@@ -0,0 +1,148 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	utilsValidation "github.com/newrelic/newrelic-cli/internal/utils/validation"
+)
+
+// RecipeObserver watches the validation of a recipe over time, rather than
+// until it first passes.
+type RecipeObserver interface {
+	ObserveRecipe(ctx context.Context, dm types.DiscoveryManifest, r types.OpenInstallationRecipe, window time.Duration) (*ValidationTimeline, error)
+}
+
+// ValidationSample is the outcome of one run of a recipe's validation query.
+type ValidationSample struct {
+	At       time.Time `json:"at"`
+	DataSeen bool      `json:"dataSeen"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// ValidationTimeline records the runs of a recipe's validation query over an
+// observation window, telling data that arrives late from data that never
+// arrives.  FirstDataAt is zero when no data was seen, and StableAt, from
+// which every later run saw data, is zero when the latest run saw none.
+type ValidationTimeline struct {
+	StartedAt   time.Time          `json:"startedAt"`
+	FirstDataAt time.Time          `json:"firstDataAt,omitempty"`
+	StableAt    time.Time          `json:"stableAt,omitempty"`
+	EntityGUID  string             `json:"entityGuid,omitempty"`
+	Samples     []ValidationSample `json:"samples"`
+}
+
+func (t *ValidationTimeline) record(at time.Time, dataSeen bool, entityGUID string, err error) {
+	s := ValidationSample{At: at, DataSeen: dataSeen}
+	if err != nil {
+		s.Error = err.Error()
+	}
+	t.Samples = append(t.Samples, s)
+
+	if !dataSeen {
+		t.StableAt = time.Time{}
+		return
+	}
+
+	if t.FirstDataAt.IsZero() {
+		t.FirstDataAt = at
+	}
+	if t.StableAt.IsZero() {
+		t.StableAt = at
+	}
+	if t.EntityGUID == "" {
+		t.EntityGUID = entityGUID
+	}
+}
+
+// Summary describes when data was first seen and when it became stable,
+// relative to the start of the observation.
+func (t ValidationTimeline) Summary() string {
+	missing := 0
+	for _, s := range t.Samples {
+		if !s.DataSeen {
+			missing++
+		}
+	}
+
+	if t.FirstDataAt.IsZero() {
+		return fmt.Sprintf("no data seen in %d checks", len(t.Samples))
+	}
+
+	firstData := t.FirstDataAt.Sub(t.StartedAt).Round(time.Second)
+	if t.StableAt.IsZero() {
+		return fmt.Sprintf("first data after %s, not stable, %d of %d checks without data", firstData, missing, len(t.Samples))
+	}
+
+	return fmt.Sprintf("first data after %s, stable after %s, %d of %d checks without data", firstData, t.StableAt.Sub(t.StartedAt).Round(time.Second), missing, len(t.Samples))
+}
+
+// ObserveRecipe runs the recipe's validation query at its validation interval
+// until the window has passed, recording when data was first seen and when it
+// became stable.  Failed queries are recorded as runs without data.  The
+// timeline so far is returned should the context be canceled.
+func (m *PollingRecipeValidator) ObserveRecipe(ctx context.Context, dm types.DiscoveryManifest, r types.OpenInstallationRecipe, window time.Duration) (*ValidationTimeline, error) {
+	query, err := ResolveValidationNRQL(dm, r, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	interval, _ := m.recipeTiming(r)
+
+	log.WithFields(log.Fields{
+		"name":                r.Name,
+		"validation_interval": interval,
+		"window":              window,
+	}).Debug("observing recipe validation")
+
+	m.ProgressIndicator.Start(fmt.Sprintf("Observing data in New Relic for %s...", window))
+	defer m.ProgressIndicator.Stop()
+
+	t := ValidationTimeline{StartedAt: time.Now()}
+	deadline := t.StartedAt.Add(window)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// Results are never taken from the query cache, which would hide when
+	// data arrives.
+	queryCtx := utilsValidation.WithoutQueryCache(ctx)
+
+	for {
+		ok, entityGUID, err := m.Check(queryCtx, string(query))
+		if ctx.Err() != nil {
+			m.ProgressIndicator.Fail("")
+			return &t, fmt.Errorf("validation observation cancelled")
+		}
+		t.record(time.Now(), ok, entityGUID, err)
+
+		if !time.Now().Before(deadline) {
+			m.ProgressIndicator.Success("")
+			return &t, nil
+		}
+
+		select {
+		case <-ticker.C:
+			continue
+
+		case <-ctx.Done():
+			m.ProgressIndicator.Fail("")
+			return &t, fmt.Errorf("validation observation cancelled")
+		}
+	}
+}
+
+// ObserveRecipe observes the validation of the recipe with the wrapped
+// validator, when it supports observation.
+func (a *AccountRecipeValidator) ObserveRecipe(ctx context.Context, dm types.DiscoveryManifest, r types.OpenInstallationRecipe, window time.Duration) (*ValidationTimeline, error) {
+	o, ok := a.validator.(RecipeObserver)
+	if !ok {
+		return nil, fmt.Errorf("the validation of %s cannot be observed", r.Name)
+	}
+
+	return o.ObserveRecipe(ctx, dm, r, window)
+}
@@ -0,0 +1,97 @@
+// +build unit
+
+package validation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/credentials"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/ux"
+)
+
+func TestObserveRecipe_FirstDataSeen(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+	c := NewMockNRDBClient()
+	c.ReturnResultsAfterNAttempts(emptyResults, nonEmptyResults, 3)
+
+	v := NewPollingRecipeValidator(c)
+	v.ProgressIndicator = ux.NewMockProgressIndicator()
+	r := types.OpenInstallationRecipe{ValidationNRQL: "testNrql", ValidationInterval: 10 * time.Millisecond}
+
+	timeline, err := v.ObserveRecipe(context.Background(), types.DiscoveryManifest{}, r, 100*time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, len(timeline.Samples) > 3)
+	require.False(t, timeline.Samples[0].DataSeen)
+	require.False(t, timeline.Samples[1].DataSeen)
+	require.True(t, timeline.Samples[2].DataSeen)
+	require.Equal(t, timeline.Samples[2].At, timeline.FirstDataAt)
+	require.Equal(t, timeline.FirstDataAt, timeline.StableAt)
+	require.Equal(t, len(timeline.Samples), c.Attempts())
+	require.Contains(t, timeline.Summary(), "stable after")
+}
+
+func TestObserveRecipe_NoData(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+	c := NewMockNRDBClient()
+	c.ReturnResultsAfterNAttempts(emptyResults, emptyResults, 0)
+
+	v := NewPollingRecipeValidator(c)
+	v.ProgressIndicator = ux.NewMockProgressIndicator()
+	r := types.OpenInstallationRecipe{ValidationNRQL: "testNrql", ValidationInterval: 10 * time.Millisecond}
+
+	timeline, err := v.ObserveRecipe(context.Background(), types.DiscoveryManifest{}, r, 30*time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, timeline.FirstDataAt.IsZero())
+	require.True(t, timeline.StableAt.IsZero())
+	require.Contains(t, timeline.Summary(), "no data seen")
+}
+
+func TestObserveRecipe_Canceled(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 12345})
+	c := NewMockNRDBClient()
+
+	v := NewPollingRecipeValidator(c)
+	v.ProgressIndicator = ux.NewMockProgressIndicator()
+	r := types.OpenInstallationRecipe{ValidationNRQL: "testNrql", ValidationInterval: 10 * time.Millisecond}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 25*time.Millisecond)
+	defer cancel()
+
+	timeline, err := v.ObserveRecipe(ctx, types.DiscoveryManifest{}, r, time.Hour)
+	require.Error(t, err)
+	require.NotNil(t, timeline)
+	require.NotEmpty(t, timeline.Samples)
+}
+
+func TestValidationTimeline_Stabilization(t *testing.T) {
+	start := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	timeline := ValidationTimeline{StartedAt: start}
+
+	timeline.record(start, false, "", nil)
+	timeline.record(start.Add(10*time.Second), true, "entity-guid", nil)
+	timeline.record(start.Add(20*time.Second), false, "", nil)
+	require.Equal(t, start.Add(10*time.Second), timeline.FirstDataAt)
+	require.True(t, timeline.StableAt.IsZero())
+	require.Equal(t, "first data after 10s, not stable, 2 of 3 checks without data", timeline.Summary())
+
+	timeline.record(start.Add(30*time.Second), true, "other-guid", nil)
+	timeline.record(start.Add(40*time.Second), true, "", nil)
+	require.Equal(t, start.Add(30*time.Second), timeline.StableAt)
+	require.Equal(t, "entity-guid", timeline.EntityGUID)
+	require.Equal(t, "first data after 10s, stable after 30s, 2 of 5 checks without data", timeline.Summary())
+}
+
+func TestAccountRecipeValidator_ObserveRecipe(t *testing.T) {
+	m := NewMockRecipeValidator()
+	a := NewAccountRecipeValidator(m)
+
+	_, err := a.ObserveRecipe(context.Background(), types.DiscoveryManifest{}, types.OpenInstallationRecipe{}, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 1, m.ObserveCallCount)
+	require.Equal(t, time.Minute, m.ObserveWindow)
+}
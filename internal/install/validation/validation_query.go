@@ -0,0 +1,121 @@
+package validation
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+// ErrMissingQueryVars is returned when the validation query of a recipe
+// references variables that have no value.
+type ErrMissingQueryVars struct {
+	RecipeName string
+	Names      []string
+}
+
+func NewErrMissingQueryVars(recipeName string, names []string) ErrMissingQueryVars {
+	return ErrMissingQueryVars{
+		RecipeName: recipeName,
+		Names:      names,
+	}
+}
+
+func (e ErrMissingQueryVars) Error() string {
+	return fmt.Sprintf("the validation query of recipe %s references variables with no value: %s", e.RecipeName, strings.Join(e.Names, ", "))
+}
+
+// ResolveValidationNRQL returns the validation query of the recipe with the
+// variables it references, such as {{.HOSTNAME}}, substituted.  The variables
+// resolved for the recipe's execution are used, along with the host name of
+// the discovery manifest when they do not set it.
+func ResolveValidationNRQL(dm types.DiscoveryManifest, r types.OpenInstallationRecipe, vars types.RecipeVars) (types.NRQL, error) {
+	tmpl, err := template.New("validationNRQL").Option("missingkey=error").Parse(string(r.ValidationNRQL))
+	if err != nil {
+		return "", fmt.Errorf("the validation query of recipe %s is not a valid template: %s", r.Name, err)
+	}
+
+	data := map[string]string{
+		"HOSTNAME": dm.Hostname,
+	}
+	for k, v := range vars {
+		data[k] = v
+	}
+
+	missing := []string{}
+	if tmpl.Tree != nil {
+		for _, name := range queryVarNames(tmpl.Tree.Root) {
+			if _, ok := data[name]; !ok {
+				missing = append(missing, name)
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", NewErrMissingQueryVars(r.Name, missing)
+	}
+
+	var query bytes.Buffer
+	if err := tmpl.Execute(&query, data); err != nil {
+		return "", fmt.Errorf("could not resolve the validation query of recipe %s: %s", r.Name, err)
+	}
+
+	return types.NRQL(query.String()), nil
+}
+
+// queryVarNames returns the distinct names of the variables referenced under
+// the given template node.
+func queryVarNames(node parse.Node) []string {
+	names := []string{}
+	seen := map[string]bool{}
+
+	var walk func(parse.Node)
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, c := range n.Nodes {
+				walk(c)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, c := range n.Cmds {
+				walk(c)
+			}
+		case *parse.CommandNode:
+			for _, a := range n.Args {
+				walk(a)
+			}
+		case *parse.IfNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			// Fields within the body refer to the elements ranged over.
+			walk(n.Pipe)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walk(n.Pipe)
+			walk(n.ElseList)
+		case *parse.FieldNode:
+			if len(n.Ident) > 0 && !seen[n.Ident[0]] {
+				seen[n.Ident[0]] = true
+				names = append(names, n.Ident[0])
+			}
+		}
+	}
+	walk(node)
+
+	return names
+}
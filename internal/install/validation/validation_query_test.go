@@ -0,0 +1,53 @@
+// +build unit
+
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+)
+
+func TestResolveValidationNRQL_Hostname(t *testing.T) {
+	dm := types.DiscoveryManifest{Hostname: "web-01"}
+	r := types.OpenInstallationRecipe{ValidationNRQL: "SELECT count(*) FROM SystemSample WHERE hostname = '{{.HOSTNAME}}'"}
+
+	query, err := ResolveValidationNRQL(dm, r, nil)
+	require.NoError(t, err)
+	require.Equal(t, types.NRQL("SELECT count(*) FROM SystemSample WHERE hostname = 'web-01'"), query)
+}
+
+func TestResolveValidationNRQL_ExecutionVars(t *testing.T) {
+	dm := types.DiscoveryManifest{Hostname: "web-01"}
+	r := types.OpenInstallationRecipe{ValidationNRQL: "SELECT count(*) FROM RedisSample WHERE entityName = '{{.HOSTNAME}}:{{.REDIS_PORT}}' AND displayName = \"it's {{.NR_CLI_CLUSTER}}\""}
+	vars := types.RecipeVars{"HOSTNAME": "web-01.example.com", "REDIS_PORT": "6379", "NR_CLI_CLUSTER": "a&b"}
+
+	query, err := ResolveValidationNRQL(dm, r, vars)
+	require.NoError(t, err)
+	require.Equal(t, types.NRQL("SELECT count(*) FROM RedisSample WHERE entityName = 'web-01.example.com:6379' AND displayName = \"it's a&b\""), query)
+}
+
+func TestResolveValidationNRQL_MissingVars(t *testing.T) {
+	r := types.OpenInstallationRecipe{
+		Name:           "redis",
+		ValidationNRQL: "SELECT count(*) FROM RedisSample WHERE port = '{{.REDIS_PORT}}'{{if .CLUSTER}} AND cluster = '{{.CLUSTER}}'{{end}}",
+	}
+
+	_, err := ResolveValidationNRQL(types.DiscoveryManifest{}, r, types.RecipeVars{})
+
+	var merr ErrMissingQueryVars
+	require.True(t, errors.As(err, &merr))
+	require.Equal(t, []string{"CLUSTER", "REDIS_PORT"}, merr.Names)
+	require.Equal(t, "the validation query of recipe redis references variables with no value: CLUSTER, REDIS_PORT", err.Error())
+}
+
+func TestResolveValidationNRQL_InvalidTemplate(t *testing.T) {
+	r := types.OpenInstallationRecipe{Name: "redis", ValidationNRQL: "SELECT count(*) FROM RedisSample WHERE port = '{{.REDIS_PORT'"}
+
+	_, err := ResolveValidationNRQL(types.DiscoveryManifest{}, r, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not a valid template")
+}
@@ -0,0 +1,46 @@
+package install
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/validation"
+)
+
+// observeValidation watches the validation query of the recipe for the
+// observation window, reporting when its data was first seen and when it
+// became stable, so delayed ingest can be told from a failed install.  The
+// recipe is then validated as usual.
+func (i *RecipeInstaller) observeValidation(ctx context.Context, m *types.DiscoveryManifest, r *types.OpenInstallationRecipe) {
+	o, ok := i.recipeValidator.(validation.RecipeObserver)
+	if !ok {
+		log.Debugf("the validation of %s cannot be observed", r.Name)
+		return
+	}
+
+	timeline, err := o.ObserveRecipe(ctx, *m, *r, i.ObserveValidation)
+	if err != nil {
+		log.Warnf("Could not observe the validation of %s: %s", r.Name, err)
+		if timeline == nil {
+			return
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"name":          r.Name,
+		"first_data_at": timeline.FirstDataAt,
+		"stable_at":     timeline.StableAt,
+		"checks":        len(timeline.Samples),
+	}).Debug("observed recipe validation")
+
+	msg := fmt.Sprintf("Validation of %s observed for %s: %s", r.Name, i.ObserveValidation, timeline.Summary())
+	if i.Quiet {
+		log.Info(msg)
+		return
+	}
+
+	fmt.Println(msg)
+}
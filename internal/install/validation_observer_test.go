@@ -0,0 +1,44 @@
+// +build unit
+
+package install
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/install/execution"
+	"github.com/newrelic/newrelic-cli/internal/install/recipes"
+	"github.com/newrelic/newrelic-cli/internal/install/types"
+	"github.com/newrelic/newrelic-cli/internal/install/validation"
+)
+
+func TestInstall_ObserveValidation(t *testing.T) {
+	for _, window := range []time.Duration{0, time.Minute} {
+		ic := InstallerContext{
+			SkipLoggingInstall: true,
+			SkipIntegrations:   true,
+			ObserveValidation:  window,
+		}
+		statusReporters = []execution.StatusSubscriber{execution.NewMockStatusReporter()}
+		status = execution.NewInstallStatus(statusReporters, execution.NewMockSuccessLinkGenerator())
+		f = recipes.NewMockRecipeFetcher()
+		f.FetchRecipeVals = []types.OpenInstallationRecipe{
+			{Name: types.InfraAgentRecipeName, ValidationNRQL: "testNrql"},
+		}
+		v = validation.NewMockRecipeValidator()
+
+		i := RecipeInstaller{ic, d, l, mv, f, e, v, ff, status, p, pi, lkf, ss, rd, cr}
+		require.NoError(t, i.Install())
+		require.Equal(t, 1, v.ValidateCallCount)
+
+		if window == 0 {
+			require.Equal(t, 0, v.ObserveCallCount)
+			continue
+		}
+
+		require.Equal(t, 1, v.ObserveCallCount)
+		require.Equal(t, window, v.ObserveWindow)
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	b64 "encoding/base64"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"syscall"
@@ -104,6 +106,17 @@ func MinOf(vars ...int) int {
 	return min
 }
 
+// StringInSlice returns true if the given string is contained in the slice.
+func StringInSlice(str string, list []string) bool {
+	for _, s := range list {
+		if s == str {
+			return true
+		}
+	}
+
+	return false
+}
+
 // GetTimestamp returns the current epoch timestamp in seconds.
 func GetTimestamp() int64 {
 	return time.Now().Unix()
@@ -131,3 +144,31 @@ func StdinExists() bool {
 	}
 	return (fi.Mode() & os.ModeCharDevice) == 0
 }
+
+// WriteFileAtomic writes data to the named file by way of a temporary file in
+// the same directory, so that an interrupted write never leaves a partially
+// written file in place.
+func WriteFileAtomic(filename string, data []byte, perm os.FileMode) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Chmod(tmpName, perm); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpName, filename)
+}
@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -39,3 +42,39 @@ func TestStructToMap(t *testing.T) {
 
 	assert.Equal(t, expected, result)
 }
+
+func TestStringInSlice(t *testing.T) {
+	t.Parallel()
+
+	list := []string{"a", "b", "c"}
+
+	assert.True(t, StringInSlice("b", list))
+	assert.False(t, StringInSlice("d", list))
+	assert.False(t, StringInSlice("a", nil))
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "atomic")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "data.json")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("old"), 0644))
+
+	assert.NoError(t, WriteFileAtomic(path, []byte("new"), 0600))
+
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// No temporary files are left behind.
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(files))
+}
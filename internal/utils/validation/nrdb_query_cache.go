@@ -0,0 +1,125 @@
+package validation
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/newrelic-cli/internal/utils"
+	"github.com/newrelic/newrelic-client-go/pkg/nrdb"
+)
+
+// DefaultQueryCacheTTL is how long a query result is reused.  It is kept
+// shorter than the default polling interval, so that a validator polling a
+// query gets fresh data on each attempt.
+const DefaultQueryCacheTTL = 2 * time.Second
+
+type queryCacheContextKey struct{}
+
+// WithoutQueryCache returns a context whose queries bypass the query cache and
+// always run against NRDB, as for a poll that must see fresh data.
+func WithoutQueryCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queryCacheContextKey{}, true)
+}
+
+func bypassesQueryCache(ctx context.Context) bool {
+	bypass, _ := ctx.Value(queryCacheContextKey{}).(bool)
+	return bypass
+}
+
+// CachingNRDBClient is an implementation of the NRDBClient interface that
+// reuses the results of identical queries run against the same account within
+// a short time, so that recipes validating with the same query do not query
+// NRDB again.  Queries are identical when they only differ by whitespace,
+// their time window being part of the query.  Failed queries are not cached.
+type CachingNRDBClient struct {
+	client utils.NRDBClient
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[queryCacheKey]queryCacheEntry
+}
+
+type queryCacheKey struct {
+	accountID int
+	query     string
+}
+
+type queryCacheEntry struct {
+	result  *nrdb.NRDBResultContainer
+	expires time.Time
+}
+
+// NewCachingNRDBClient returns a new instance of CachingNRDBClient reusing the
+// results of the given client for the given duration.
+func NewCachingNRDBClient(c utils.NRDBClient, ttl time.Duration) *CachingNRDBClient {
+	q := CachingNRDBClient{
+		client:  c,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[queryCacheKey]queryCacheEntry{},
+	}
+
+	return &q
+}
+
+func (c *CachingNRDBClient) QueryWithContext(ctx context.Context, accountID int, nrql nrdb.NRQL) (*nrdb.NRDBResultContainer, error) {
+	key := queryCacheKey{
+		accountID: accountID,
+		query:     strings.Join(strings.Fields(string(nrql)), " "),
+	}
+
+	if !bypassesQueryCache(ctx) {
+		if result, ok := c.cached(key); ok {
+			log.WithFields(log.Fields{
+				"account_id": accountID,
+				"query":      key.query,
+			}).Debug("reusing cached query result")
+
+			return result, nil
+		}
+	}
+
+	result, err := c.client.QueryWithContext(ctx, accountID, nrql)
+	if err != nil {
+		return nil, err
+	}
+
+	c.store(key, result)
+
+	return result, nil
+}
+
+func (c *CachingNRDBClient) cached(key queryCacheKey) (*nrdb.NRDBResultContainer, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || !c.now().Before(e.expires) {
+		return nil, false
+	}
+
+	return e.result, true
+}
+
+// store caches the result, dropping the results that have expired.
+func (c *CachingNRDBClient) store(key queryCacheKey, result *nrdb.NRDBResultContainer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+
+	c.entries[key] = queryCacheEntry{
+		result:  result,
+		expires: now.Add(c.ttl),
+	}
+}
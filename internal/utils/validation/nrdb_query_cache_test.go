@@ -0,0 +1,118 @@
+// +build unit
+
+package validation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/newrelic-cli/internal/credentials"
+	"github.com/newrelic/newrelic-client-go/pkg/nrdb"
+)
+
+type countingNRDBClient struct {
+	calls   int
+	results []nrdb.NRDBResult
+	err     error
+}
+
+func (c *countingNRDBClient) QueryWithContext(ctx context.Context, accountID int, nrql nrdb.NRQL) (*nrdb.NRDBResultContainer, error) {
+	c.calls++
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	return &nrdb.NRDBResultContainer{Results: c.results}, nil
+}
+
+func newTestCache(c *countingNRDBClient) (*CachingNRDBClient, *time.Time) {
+	now := time.Now()
+	q := NewCachingNRDBClient(c, time.Second)
+	q.now = func() time.Time { return now }
+
+	return q, &now
+}
+
+func TestCachingNRDBClient_ReusesResultWithinTTL(t *testing.T) {
+	c := &countingNRDBClient{}
+	q, now := newTestCache(c)
+
+	_, err := q.QueryWithContext(context.Background(), 1, "SELECT count(*) FROM Log SINCE 10 minutes ago")
+	require.NoError(t, err)
+	*now = now.Add(500 * time.Millisecond)
+	_, err = q.QueryWithContext(context.Background(), 1, "SELECT  count(*)\nFROM Log SINCE 10 minutes ago")
+	require.NoError(t, err)
+
+	require.Equal(t, 1, c.calls)
+}
+
+func TestCachingNRDBClient_QueriesAgainAfterTTL(t *testing.T) {
+	c := &countingNRDBClient{}
+	q, now := newTestCache(c)
+
+	_, err := q.QueryWithContext(context.Background(), 1, "SELECT count(*) FROM Log")
+	require.NoError(t, err)
+	*now = now.Add(time.Second)
+	_, err = q.QueryWithContext(context.Background(), 1, "SELECT count(*) FROM Log")
+	require.NoError(t, err)
+
+	require.Equal(t, 2, c.calls)
+}
+
+func TestCachingNRDBClient_KeysByAccountAndQuery(t *testing.T) {
+	c := &countingNRDBClient{}
+	q, _ := newTestCache(c)
+
+	_, err := q.QueryWithContext(context.Background(), 1, "SELECT count(*) FROM Log SINCE 10 minutes ago")
+	require.NoError(t, err)
+	_, err = q.QueryWithContext(context.Background(), 2, "SELECT count(*) FROM Log SINCE 10 minutes ago")
+	require.NoError(t, err)
+	_, err = q.QueryWithContext(context.Background(), 1, "SELECT count(*) FROM Log SINCE 1 hour ago")
+	require.NoError(t, err)
+
+	require.Equal(t, 3, c.calls)
+}
+
+func TestCachingNRDBClient_Bypass(t *testing.T) {
+	c := &countingNRDBClient{}
+	q, _ := newTestCache(c)
+
+	_, err := q.QueryWithContext(context.Background(), 1, "SELECT count(*) FROM Log")
+	require.NoError(t, err)
+	_, err = q.QueryWithContext(WithoutQueryCache(context.Background()), 1, "SELECT count(*) FROM Log")
+	require.NoError(t, err)
+
+	require.Equal(t, 2, c.calls)
+}
+
+func TestCachingNRDBClient_DoesNotCacheErrors(t *testing.T) {
+	c := &countingNRDBClient{err: errors.New("timeout")}
+	q, _ := newTestCache(c)
+
+	_, err := q.QueryWithContext(context.Background(), 1, "SELECT count(*) FROM Log")
+	require.Error(t, err)
+	_, err = q.QueryWithContext(context.Background(), 1, "SELECT count(*) FROM Log")
+	require.Error(t, err)
+
+	require.Equal(t, 2, c.calls)
+}
+
+func TestPollingNRQLValidator_FinalAttemptBypassesCache(t *testing.T) {
+	credentials.SetDefaultProfile(credentials.Profile{AccountID: 1})
+
+	c := &countingNRDBClient{results: []nrdb.NRDBResult{{"count": float64(0)}}}
+	q := NewCachingNRDBClient(c, time.Hour)
+	v := NewPollingNRQLValidator(q)
+	v.Interval = time.Millisecond
+	v.MaxAttempts = 3
+
+	_, err := v.Validate(context.Background(), "SELECT count(*) FROM Log")
+	require.True(t, errors.Is(err, ErrMaxAttemptsReached))
+
+	require.Equal(t, 2, c.calls)
+}
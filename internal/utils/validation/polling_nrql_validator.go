@@ -12,6 +12,10 @@ import (
 	"github.com/newrelic/newrelic-client-go/pkg/nrdb"
 )
 
+// ErrMaxAttemptsReached is returned when no data was found within the maximum
+// number of validation attempts.
+var ErrMaxAttemptsReached = errors.New("reached max validation attempts")
+
 const (
 	defaultMaxAttempts = 60
 	defaultInterval    = 5 * time.Second
@@ -19,6 +23,9 @@ const (
 
 // PollingNRQLValidator polls NRDB to assert data is being reported for the given query.
 type PollingNRQLValidator struct {
+	// AccountID is the account the query is run against.  Defaults to the
+	// account of the default profile.
+	AccountID         int
 	MaxAttempts       int
 	Interval          time.Duration
 	ProgressIndicator ux.ProgressIndicator
@@ -39,12 +46,24 @@ func NewPollingNRQLValidator(c utils.NRDBClient) *PollingNRQLValidator {
 
 // Validate polls NRDB to assert data is being reported for the given query.
 func (m *PollingNRQLValidator) Validate(ctx context.Context, query string) (string, error) {
-	return m.waitForData(ctx, query)
+	return m.waitForData(ctx, query, m.Interval, m.MaxAttempts)
+}
+
+// ValidateWithTiming polls NRDB like Validate, but at the given interval and
+// for at most the given number of attempts rather than the validator's own.
+func (m *PollingNRQLValidator) ValidateWithTiming(ctx context.Context, query string, interval time.Duration, maxAttempts int) (string, error) {
+	return m.waitForData(ctx, query, interval, maxAttempts)
+}
+
+// Check runs the given query once, returning whether data is being reported and
+// the entity GUID found in the results, if any.
+func (m *PollingNRQLValidator) Check(ctx context.Context, query string) (bool, string, error) {
+	return m.tryValidate(ctx, query)
 }
 
-func (m *PollingNRQLValidator) waitForData(ctx context.Context, query string) (string, error) {
+func (m *PollingNRQLValidator) waitForData(ctx context.Context, query string, interval time.Duration, maxAttempts int) (string, error) {
 	count := 0
-	ticker := time.NewTicker(m.Interval)
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	progressMsg := "Checking for data in New Relic (this may take a few minutes)..."
@@ -52,12 +71,19 @@ func (m *PollingNRQLValidator) waitForData(ctx context.Context, query string) (s
 	defer m.ProgressIndicator.Stop()
 
 	for {
-		if count == m.MaxAttempts {
+		if count == maxAttempts {
 			m.ProgressIndicator.Fail("")
-			return "", fmt.Errorf("reached max validation attempts")
+			return "", ErrMaxAttemptsReached
+		}
+
+		// The final attempt bypasses the query cache, so that the absence of
+		// data is never concluded from a cached result.
+		attemptCtx := ctx
+		if count == maxAttempts-1 {
+			attemptCtx = WithoutQueryCache(ctx)
 		}
 
-		ok, entityGUID, err := m.tryValidate(ctx, query)
+		ok, entityGUID, err := m.tryValidate(attemptCtx, query)
 		if err != nil {
 			m.ProgressIndicator.Fail("")
 			return "", err
@@ -116,14 +142,18 @@ func (m *PollingNRQLValidator) tryValidate(ctx context.Context, query string) (b
 }
 
 func (m *PollingNRQLValidator) executeQuery(ctx context.Context, query string) ([]nrdb.NRDBResult, error) {
-	profile := credentials.DefaultProfile()
-	if profile == nil || profile.AccountID == 0 {
-		return nil, errors.New("no account ID found in default profile")
+	accountID := m.AccountID
+	if accountID == 0 {
+		profile := credentials.DefaultProfile()
+		if profile == nil || profile.AccountID == 0 {
+			return nil, errors.New("no account ID found in default profile")
+		}
+		accountID = profile.AccountID
 	}
 
 	nrql := nrdb.NRQL(query)
 
-	result, err := m.client.QueryWithContext(ctx, profile.AccountID, nrql)
+	result, err := m.client.QueryWithContext(ctx, accountID, nrql)
 	if err != nil {
 		return nil, err
 	}